@@ -0,0 +1,45 @@
+package grpcvfs
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/vfsgrpc"
+	"github.com/c2fo/vfs/v5/vfstest"
+)
+
+func TestConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	served, err := _os.NewFileSystem().NewLocation("", dir+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	vfsgrpc.RegisterFileServiceServer(server, vfsgrpc.NewServer(served, vfsgrpc.Options{}))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	fs := NewFileSystem().WithOptions(Options{Insecure: true})
+	defer func() { _ = fs.Close() }()
+	location, err := fs.NewLocation(listener.Addr().String(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vfstest.Run(t, location)
+}
@@ -0,0 +1,62 @@
+/*
+Package grpcvfs is a client backend for files served by a vfsgrpc.Server, ie: a storage gateway holding the
+credentials for a cloud backend.
+
+Usage
+
+Rely on github.com/c2fo/vfs/v5/backend
+
+  import(
+	  "github.com/c2fo/vfs/v5/backend"
+	  "github.com/c2fo/vfs/v5/backend/grpcvfs"
+  )
+
+  func UseFs() error {
+	  fs := backend.Backend(grpcvfs.Scheme)
+	  ...
+  }
+
+Or call directly:
+
+  import "github.com/c2fo/vfs/v5/backend/grpcvfs"
+
+  func DoSomething() {
+	  fs := grpcvfs.NewFileSystem()
+
+	  location, err := fs.NewLocation("gateway.example.com:8443", "/some/path/")
+	  if err != nil {
+		 #handle error
+	  }
+	  ...
+  }
+
+The authority of a grpcvfs URI is the host and port of the server, ie:
+
+  grpcvfs://gateway.example.com:8443/some/path/to/file.txt
+
+and paths are relative to the location the server serves.  Connections are made with the DialOptions in Options,
+which must include transport credentials unless Insecure is set:
+
+  creds, err := credentials.NewClientTLSFromFile("ca.crt", "")
+  #handle error
+  fs := grpcvfs.NewFileSystem().WithOptions(grpcvfs.Options{
+	  DialOptions: []grpc.DialOption{grpc.WithTransportCredentials(creds), grpc.WithPerRPCCredentials(token)},
+  })
+
+Use WithClient to pass a vfsgrpc.FileServiceClient to use for every authority instead.
+
+Reading and Writing
+
+Reads are streamed from the server in chunks from the file's cursor position; seeking restarts the stream at the new
+position.  Writes are streamed to the server, which writes them to its backend once the file is closed, so errors
+writing the file may only be returned by Close.
+
+Copies and moves between files on the same server are done by the server, with its backend's native copy and move,
+so the file's contents aren't streamed to the client and back.
+
+Errors
+
+Errors the server returns for files that don't exist or requests it denies are returned as *os.PathErrors, so
+os.IsNotExist and os.IsPermission can be used to check for them.
+*/
+package grpcvfs
@@ -0,0 +1,422 @@
+package grpcvfs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+	"github.com/c2fo/vfs/v5/vfsgrpc"
+)
+
+// File implements vfs.File interface for files served by a vfsgrpc.Server.  Reads are streamed from the server from
+// the file's cursor position, and writes are streamed to it, being written to its backend once the file is closed.
+type File struct {
+	fileSystem *FileSystem
+	Authority  utils.Authority
+	path       string
+	cursorPos  int64
+
+	reader  vfsgrpc.FileService_ReadClient
+	pending []byte
+	writer  vfsgrpc.FileService_WriteClient
+	cancel  context.CancelFunc
+}
+
+// Info Functions
+
+// Stat returns the file's name, size, last modified time, mode, content type and ETag from a single request.
+func (f *File) Stat() (*vfs.FileInfo, error) {
+	client, err := f.fileSystem.Client(f.Authority)
+	if err != nil {
+		return nil, err
+	}
+	info, err := client.Stat(context.Background(), &vfsgrpc.FileRequest{Path: f.Path()})
+	if err != nil {
+		return nil, convertError("stat", f.Path(), err)
+	}
+	fileInfo := vfs.NewFileInfo(info.Name, info.Size, time.Unix(0, info.ModTime), os.FileMode(info.Mode))
+	fileInfo.ContentType = info.ContentType
+	fileInfo.ETag = info.Etag
+	return fileInfo, nil
+}
+
+// LastModified returns the file's last modified time.
+func (f *File) LastModified() (*time.Time, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	t := info.ModTime()
+	return &t, nil
+}
+
+// Size returns the size of the file in bytes.
+func (f *File) Size() (uint64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(info.Size()), nil
+}
+
+// Name returns the base name of the file, ie: "file.txt" of "grpcvfs://host:port/some/path/to/file.txt"
+func (f *File) Name() string {
+	return path.Base(f.path)
+}
+
+// Path returns the absolute path of the file, ie: "/some/path/to/file.txt" of
+// "grpcvfs://host:port/some/path/to/file.txt"
+func (f *File) Path() string {
+	return utils.EnsureLeadingSlash(f.path)
+}
+
+// Exists returns true if the file exists on the server's backend.
+func (f *File) Exists() (bool, error) {
+	client, err := f.fileSystem.Client(f.Authority)
+	if err != nil {
+		return false, err
+	}
+	response, err := client.Exists(context.Background(), &vfsgrpc.FileRequest{Path: f.Path()})
+	if err != nil {
+		return false, convertError("exists", f.Path(), err)
+	}
+	return response.Exists, nil
+}
+
+// Touch creates a zero-length file on the vfs.File if no File exists.  Update File's last modified timestamp.
+// Returns error if unable to touch File.
+func (f *File) Touch() error {
+	client, err := f.fileSystem.Client(f.Authority)
+	if err != nil {
+		return err
+	}
+	_, err = client.Touch(context.Background(), &vfsgrpc.FileRequest{Path: f.Path()})
+	return convertError("touch", f.Path(), err)
+}
+
+// Location returns a vfs.Location at the location of the file. IE: if file is at
+// grpcvfs://host:port/here/is/the/file.txt the location points to grpcvfs://host:port/here/is/the/
+func (f *File) Location() vfs.Location {
+	return &Location{
+		fileSystem: f.fileSystem,
+		path:       path.Dir(f.path),
+		Authority:  f.Authority,
+	}
+}
+
+// Move/Copy Operations
+
+// MoveToFile moves the file to the target file.  If the target is on the same server, the server moves it with its
+// backend's native move.  Otherwise the file is copied with CopyToFile then deleted.
+func (f *File) MoveToFile(t vfs.File, opts ...vfs.CopyOption) error {
+	if target, ok := t.(*File); ok && f.sameServer(target) {
+		if err := f.serverTransfer(target, "move"); err != nil {
+			return err
+		}
+		return target.Close()
+	}
+
+	//otherwise do copy-delete, verifying the copy before deleting unless disabled
+	if err := f.CopyToFile(t, opts...); err != nil {
+		return err
+	}
+	fsOpts, _ := f.fileSystem.options.(Options)
+	if !fsOpts.DisableMoveVerification && !vfs.NewCopyOptions(opts...).SkipMoveVerification {
+		if err := utils.VerifyCopy(f, t); err != nil {
+			return err
+		}
+	}
+	return f.Delete()
+}
+
+// MoveToLocation works by creating a new file on the target location then calling MoveToFile() on it.  If a file of the
+// same name already exists at the location, the ConflictPolicy in Options determines what happens (overwriting it by
+// default).
+func (f *File) MoveToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	newFile, err := location.FileSystem().NewFile(location.Volume(), path.Join(location.Path(), f.Name()))
+	if err != nil {
+		return nil, err
+	}
+	newFile, skip, err := utils.ResolveConflict(newFile, f.conflictPolicy(opts))
+	if err != nil {
+		return nil, err
+	}
+	if skip {
+		return newFile, nil
+	}
+
+	err = f.MoveToFile(newFile, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newFile, nil
+}
+
+// CopyToFile puts the contents of File into the targetFile passed.  If the target is on the same server, the server
+// copies it with its backend's native copy, so its contents aren't streamed to the client and back.
+func (f *File) CopyToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	options := vfs.NewCopyOptions(opts...)
+	if err := options.Context.Err(); err != nil {
+		return err
+	}
+	if target, ok := file.(*File); ok && f.sameServer(target) {
+		if err := f.serverTransfer(target, "copy"); err != nil {
+			return err
+		}
+	} else if err := utils.TouchCopyWithOptions(file, f, options); err != nil {
+		return err
+	}
+	//Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
+	if cerr := file.Close(); cerr != nil {
+		return cerr
+	}
+	//Close file (f) reader
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if options.VerifyChecksum {
+		return utils.VerifyChecksum(f, file)
+	}
+	return nil
+}
+
+// CopyToLocation creates a copy of *File, using the file's current path as the new file's path at the given location.
+// If a file of the same name already exists at the location, the ConflictPolicy in Options determines what happens
+// (overwriting it by default).
+func (f *File) CopyToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	newFile, err := location.FileSystem().NewFile(location.Volume(), path.Join(location.Path(), f.Name()))
+	if err != nil {
+		return nil, err
+	}
+	newFile, skip, err := utils.ResolveConflict(newFile, f.conflictPolicy(opts))
+	if err != nil {
+		return nil, err
+	}
+	if skip {
+		return newFile, nil
+	}
+
+	if err := f.CopyToFile(newFile, opts...); err != nil {
+		return nil, err
+	}
+	return newFile, nil
+}
+
+// conflictPolicy returns the ConflictPolicy set in opts, or else in Options.
+func (f *File) conflictPolicy(opts []vfs.CopyOption) vfs.ConflictPolicy {
+	policy := vfs.ConflictOverwrite
+	if fsOpts, ok := f.fileSystem.options.(Options); ok {
+		policy = fsOpts.ConflictPolicy
+	}
+	return vfs.NewCopyOptions(opts...).ConflictPolicyOr(policy)
+}
+
+// CRUD Operations
+
+// Delete removes the file from the server's backend.  Error is returned, if any.
+func (f *File) Delete() error {
+	client, err := f.fileSystem.Client(f.Authority)
+	if err != nil {
+		return err
+	}
+	_, err = client.Delete(context.Background(), &vfsgrpc.FileRequest{Path: f.Path()})
+	return convertError("delete", f.Path(), err)
+}
+
+// Close ends any read stream and, if the file has been written to, ends the write stream, returning any error from
+// the server writing the file to its backend.  The cursor is reset to the start of the file.
+func (f *File) Close() error {
+	var err error
+	if f.writer != nil {
+		_, err = f.writer.CloseAndRecv()
+		err = convertError("write", f.Path(), err)
+	}
+	f.closeStreams()
+	f.cursorPos = 0
+	return err
+}
+
+// Read reads from the file's contents, streamed from the server starting at the cursor position.
+func (f *File) Read(p []byte) (int, error) {
+	if f.writer != nil {
+		return 0, errors.New("unable to read from a file that's being written to, close it first")
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if len(f.pending) == 0 {
+		if f.reader == nil {
+			if err := f.openReader(); err != nil {
+				return 0, err
+			}
+		}
+		response, err := f.reader.Recv()
+		if err != nil {
+			f.closeStreams()
+			if err == io.EOF {
+				return 0, io.EOF
+			}
+			return 0, convertError("read", f.Path(), err)
+		}
+		f.pending = response.Data
+	}
+
+	n := copy(p, f.pending)
+	f.pending = f.pending[n:]
+	f.cursorPos += int64(n)
+	return n, nil
+}
+
+// Seek sets the cursor position for the next Read or Write.  Any read stream is restarted from the new position.  A
+// file can't be seeked while it's being written to.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if f.writer != nil {
+		return 0, errors.New("unable to seek a file that's being written to, close it first")
+	}
+
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = f.cursorPos + offset
+	case io.SeekEnd:
+		size, err := f.Size()
+		if err != nil {
+			return 0, err
+		}
+		pos = int64(size) + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if pos < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if pos != f.cursorPos {
+		f.closeStreams()
+		f.cursorPos = pos
+	}
+	return f.cursorPos, nil
+}
+
+// Write streams data to the server, which writes it to the file from the cursor position when the file is closed.
+func (f *File) Write(data []byte) (int, error) {
+	if f.writer == nil {
+		f.closeStreams()
+		if err := f.openWriter(); err != nil {
+			return 0, err
+		}
+	}
+
+	written := 0
+	for written < len(data) {
+		chunk := data[written:]
+		if len(chunk) > vfsgrpc.ChunkSize {
+			chunk = chunk[:vfsgrpc.ChunkSize]
+		}
+		if err := f.writer.Send(&vfsgrpc.WriteRequest{Request: &vfsgrpc.WriteRequest_Data{Data: chunk}}); err != nil {
+			return written, f.writeError(err)
+		}
+		written += len(chunk)
+		f.cursorPos += int64(len(chunk))
+	}
+	return written, nil
+}
+
+// URI returns the File's URI as a string.
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
+
+/*
+	Private helper functions
+*/
+
+// sameServer returns true if target is served by the same server as the file.
+func (f *File) sameServer(target *File) bool {
+	return f.Authority.Host == target.Authority.Host
+}
+
+// serverTransfer has the server copy or move the file to target.
+func (f *File) serverTransfer(target *File, op string) error {
+	client, err := f.fileSystem.Client(f.Authority)
+	if err != nil {
+		return err
+	}
+	request := &vfsgrpc.CopyRequest{Source: f.Path(), Target: target.Path()}
+	if op == "move" {
+		_, err = client.Move(context.Background(), request)
+	} else {
+		_, err = client.Copy(context.Background(), request)
+	}
+	return convertError(op, f.Path(), err)
+}
+
+func (f *File) openReader() error {
+	client, err := f.fileSystem.Client(f.Authority)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	reader, err := client.Read(ctx, &vfsgrpc.ReadRequest{Path: f.Path(), Offset: f.cursorPos})
+	if err != nil {
+		cancel()
+		return convertError("read", f.Path(), err)
+	}
+	f.reader = reader
+	f.cancel = cancel
+	return nil
+}
+
+func (f *File) openWriter() error {
+	client, err := f.fileSystem.Client(f.Authority)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	writer, err := client.Write(ctx)
+	if err != nil {
+		cancel()
+		return convertError("write", f.Path(), err)
+	}
+	f.writer = writer
+	f.cancel = cancel
+	header := &vfsgrpc.WriteHeader{Path: f.Path(), Offset: f.cursorPos}
+	if err := writer.Send(&vfsgrpc.WriteRequest{Request: &vfsgrpc.WriteRequest_Header{Header: header}}); err != nil {
+		return f.writeError(err)
+	}
+	return nil
+}
+
+// writeError returns the error from a failed Send to the write stream, which is only io.EOF when the server has ended
+// the stream, in which case its error is returned instead.  The stream is closed either way.
+func (f *File) writeError(err error) error {
+	if err == io.EOF {
+		_, err = f.writer.CloseAndRecv()
+	}
+	f.closeStreams()
+	return convertError("write", f.Path(), err)
+}
+
+// closeStreams cancels any open read or write stream.
+func (f *File) closeStreams() {
+	if f.cancel != nil {
+		f.cancel()
+	}
+	f.reader = nil
+	f.pending = nil
+	f.writer = nil
+	f.cancel = nil
+}
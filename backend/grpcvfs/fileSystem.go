@@ -0,0 +1,161 @@
+package grpcvfs
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+
+	"google.golang.org/grpc"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/backend"
+	"github.com/c2fo/vfs/v5/utils"
+	"github.com/c2fo/vfs/v5/vfsgrpc"
+)
+
+// Scheme defines the filesystem type.
+const Scheme = "grpcvfs"
+const name = "vfs gRPC gateway"
+
+// FileSystem implements vfs.FileSystem for files served by a vfsgrpc.Server.
+type FileSystem struct {
+	options vfs.Options
+	client  vfsgrpc.FileServiceClient
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+// Retry will return the default no-op retrier.
+func (fs *FileSystem) Retry() vfs.Retry {
+	return vfs.DefaultRetryer()
+}
+
+// NewFile function returns the gRPC implementation of vfs.File.  The authority is the server's host:port.
+func (fs *FileSystem) NewFile(authority string, filePath string) (vfs.File, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil grpcvfs.FileSystem pointer is required")
+	}
+	if filePath == "" {
+		return nil, errors.New("non-empty string for path is required")
+	}
+	if err := utils.ValidateAbsoluteFilePath(filePath); err != nil {
+		return nil, err
+	}
+
+	auth, err := utils.NewAuthority(authority)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		fileSystem: fs,
+		Authority:  auth,
+		path:       path.Clean(filePath),
+	}, nil
+}
+
+// NewLocation function returns the gRPC implementation of vfs.Location.  The authority is the server's host:port.
+func (fs *FileSystem) NewLocation(authority string, locPath string) (vfs.Location, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil grpcvfs.FileSystem pointer is required")
+	}
+	if err := utils.ValidateAbsoluteLocationPath(locPath); err != nil {
+		return nil, err
+	}
+
+	auth, err := utils.NewAuthority(authority)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Location{
+		fileSystem: fs,
+		path:       utils.EnsureTrailingSlash(path.Clean(locPath)),
+		Authority:  auth,
+	}, nil
+}
+
+// Name returns "vfs gRPC gateway"
+func (fs *FileSystem) Name() string {
+	return name
+}
+
+// Scheme return "grpcvfs" as the initial part of a file URI ie: grpcvfs://
+func (fs *FileSystem) Scheme() string {
+	return Scheme
+}
+
+// Client returns a client for the server at authority's host, dialing it with the DialOptions in Options, if
+// necessary.  Connections are reused for every file and location on the same host.
+func (fs *FileSystem) Client(authority utils.Authority) (vfsgrpc.FileServiceClient, error) {
+	if fs.client != nil {
+		return fs.client, nil
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if conn, ok := fs.conns[authority.Host]; ok {
+		return vfsgrpc.NewFileServiceClient(conn), nil
+	}
+
+	if fs.options == nil {
+		fs.options = Options{}
+	}
+	opts, ok := fs.options.(Options)
+	if !ok {
+		return nil, fmt.Errorf("unable to create client, vfs.Options must be a grpcvfs.Options")
+	}
+	conn, err := grpc.Dial(authority.Host, opts.dialOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	if fs.conns == nil {
+		fs.conns = map[string]*grpc.ClientConn{}
+	}
+	fs.conns[authority.Host] = conn
+	return vfsgrpc.NewFileServiceClient(conn), nil
+}
+
+// WithOptions sets options for client and returns the filesystem (chainable)
+func (fs *FileSystem) WithOptions(opts vfs.Options) *FileSystem {
+	// only set options if vfs.Options is grpcvfs.Options
+	if opts, ok := opts.(Options); ok {
+		fs.options = opts
+		// close existing connections so new ones are dialed with the new options
+		_ = fs.Close()
+	}
+	return fs
+}
+
+// WithClient passes in a client used for every authority and returns the filesystem (chainable)
+func (fs *FileSystem) WithClient(client vfsgrpc.FileServiceClient) *FileSystem {
+	fs.client = client
+	return fs
+}
+
+// Close closes the connections to every server dialed by the FileSystem.  They're dialed again when next needed.
+func (fs *FileSystem) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	var err error
+	for host, conn := range fs.conns {
+		if closeErr := conn.Close(); err == nil {
+			err = closeErr
+		}
+		delete(fs.conns, host)
+	}
+	return err
+}
+
+// NewFileSystem initializer for fileSystem struct.
+func NewFileSystem() *FileSystem {
+	return &FileSystem{}
+}
+
+func init() {
+	//registers a default Filesystem
+	backend.Register(Scheme, NewFileSystem())
+	vfs.RegisterErrorClassifier(classifyError)
+}
@@ -0,0 +1,89 @@
+package grpcvfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/backend"
+	"github.com/c2fo/vfs/v5/utils"
+	"github.com/c2fo/vfs/v5/vfsgrpc"
+)
+
+type fileSystemTestSuite struct {
+	suite.Suite
+}
+
+func (ts *fileSystemTestSuite) TestRegistered() {
+	fs := backend.Backend(Scheme)
+	ts.Equal("grpcvfs", fs.Scheme())
+	ts.Equal("vfs gRPC gateway", fs.Name())
+}
+
+func (ts *fileSystemTestSuite) TestClassifyError() {
+	ts.Equal(vfs.ErrorNotFound, vfs.ClassifyError(status.Error(codes.NotFound, "not found")))
+	ts.Equal(vfs.ErrorNetwork, vfs.ClassifyError(status.Error(codes.Unavailable, "connection refused")))
+	ts.Equal(vfs.ErrorUnknown, vfs.ClassifyError(status.Error(codes.ResourceExhausted, "quota exceeded")))
+}
+
+func (ts *fileSystemTestSuite) TestNewFile() {
+	fs := NewFileSystem()
+	file, err := fs.NewFile("gateway:8443", "/path/../to/file.txt")
+	ts.NoError(err)
+	ts.Equal("grpcvfs://gateway:8443/to/file.txt", file.URI())
+
+	_, err = fs.NewFile("gateway:8443", "")
+	ts.Error(err)
+	_, err = fs.NewFile("gateway:8443", "relative.txt")
+	ts.Error(err)
+	_, err = fs.NewFile("", "/file.txt")
+	ts.Error(err)
+	_, err = (*FileSystem)(nil).NewFile("gateway:8443", "/file.txt")
+	ts.Error(err)
+}
+
+func (ts *fileSystemTestSuite) TestNewLocation() {
+	fs := NewFileSystem()
+	location, err := fs.NewLocation("gateway:8443", "/path/to/")
+	ts.NoError(err)
+	ts.Equal("grpcvfs://gateway:8443/path/to/", location.URI())
+
+	_, err = fs.NewLocation("gateway:8443", "/path/to")
+	ts.Error(err)
+}
+
+func (ts *fileSystemTestSuite) TestClient() {
+	authority, err := utils.NewAuthority("gateway:8443")
+	ts.NoError(err)
+
+	_, err = NewFileSystem().Client(authority)
+	ts.Error(err, "transport security is required unless Insecure is set")
+
+	fs := NewFileSystem().WithOptions(Options{Insecure: true})
+	client, err := fs.Client(authority)
+	ts.NoError(err)
+	ts.NotNil(client)
+	ts.Len(fs.conns, 1)
+	_, err = fs.Client(authority)
+	ts.NoError(err)
+	ts.Len(fs.conns, 1, "connections are reused")
+	ts.NoError(fs.Close())
+	ts.Empty(fs.conns)
+
+	fs = NewFileSystem()
+	fs.options = "not options"
+	_, err = fs.Client(authority)
+	ts.Error(err)
+
+	withClient := vfsgrpc.NewFileServiceClient(nil)
+	client, err = NewFileSystem().WithClient(withClient).Client(authority)
+	ts.NoError(err)
+	ts.Equal(withClient, client)
+}
+
+func TestFileSystem(t *testing.T) {
+	suite.Run(t, new(fileSystemTestSuite))
+}
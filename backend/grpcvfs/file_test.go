@@ -0,0 +1,256 @@
+package grpcvfs
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/vfsgrpc"
+)
+
+// serverTestSuite serves a temp dir with a vfsgrpc.Server for each test.
+type serverTestSuite struct {
+	suite.Suite
+	dir     string
+	server  *grpc.Server
+	address string
+	fs      *FileSystem
+}
+
+func (ts *serverTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "grpcvfs_test")
+	ts.NoError(err)
+	ts.dir = dir
+	ts.serve(vfsgrpc.Options{})
+}
+
+func (ts *serverTestSuite) TearDownTest() {
+	ts.NoError(ts.fs.Close())
+	ts.server.Stop()
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+// serve starts a server for the temp dir with options, replacing any already started.
+func (ts *serverTestSuite) serve(options vfsgrpc.Options) {
+	if ts.server != nil {
+		ts.NoError(ts.fs.Close())
+		ts.server.Stop()
+	}
+	location, err := _os.NewFileSystem().NewLocation("", ts.dir+"/")
+	ts.NoError(err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	ts.NoError(err)
+	server := grpc.NewServer()
+	vfsgrpc.RegisterFileServiceServer(server, vfsgrpc.NewServer(location, options))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	ts.server = server
+	ts.address = listener.Addr().String()
+	ts.fs = NewFileSystem().WithOptions(Options{Insecure: true})
+}
+
+func (ts *serverTestSuite) newFile(name string) vfs.File {
+	file, err := ts.fs.NewFile(ts.address, name)
+	ts.NoError(err)
+	return file
+}
+
+func (ts *serverTestSuite) newLocation(path string) vfs.Location {
+	location, err := ts.fs.NewLocation(ts.address, path)
+	ts.NoError(err)
+	return location
+}
+
+func (ts *serverTestSuite) writeFile(name, contents string) {
+	path := filepath.Join(ts.dir, name)
+	ts.NoError(os.MkdirAll(filepath.Dir(path), 0755))
+	ts.NoError(ioutil.WriteFile(path, []byte(contents), 0644))
+}
+
+func (ts *serverTestSuite) readFile(name string) string {
+	data, err := ioutil.ReadFile(filepath.Join(ts.dir, name))
+	ts.NoError(err)
+	return string(data)
+}
+
+type fileTestSuite struct {
+	serverTestSuite
+}
+
+func (ts *fileTestSuite) TestReadAndWrite() {
+	// large enough to be streamed in several chunks
+	data := make([]byte, 3*vfsgrpc.ChunkSize+100)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	file := ts.newFile("/dir/file.bin")
+	n, err := file.Write(data)
+	ts.NoError(err)
+	ts.Equal(len(data), n)
+	ts.NoError(file.Close())
+	ts.Equal(string(data), ts.readFile("dir/file.bin"))
+
+	read, err := ioutil.ReadAll(file)
+	ts.NoError(err)
+	ts.NoError(file.Close())
+	ts.Equal(data, read)
+
+	size, err := file.Size()
+	ts.NoError(err)
+	ts.Equal(uint64(len(data)), size)
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+
+	empty := ts.newFile("/empty.txt")
+	_, err = empty.Write([]byte{})
+	ts.NoError(err)
+	ts.NoError(empty.Close())
+	ts.Equal("", ts.readFile("empty.txt"))
+}
+
+func (ts *fileTestSuite) TestSeek() {
+	ts.writeFile("file.txt", "0123456789")
+	file := ts.newFile("/file.txt")
+
+	buf := make([]byte, 3)
+	_, err := io.ReadFull(file, buf)
+	ts.NoError(err)
+	ts.Equal("012", string(buf))
+
+	pos, err := file.Seek(5, io.SeekStart)
+	ts.NoError(err)
+	ts.Equal(int64(5), pos)
+	_, err = io.ReadFull(file, buf)
+	ts.NoError(err)
+	ts.Equal("567", string(buf))
+
+	pos, err = file.Seek(-2, io.SeekEnd)
+	ts.NoError(err)
+	ts.Equal(int64(8), pos)
+	rest, err := ioutil.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("89", string(rest))
+	ts.NoError(file.Close())
+
+	_, err = file.Write([]byte("new"))
+	ts.NoError(err)
+	_, err = file.Seek(0, io.SeekStart)
+	ts.Error(err, "can't seek while writing")
+	_, err = file.Read(buf)
+	ts.Error(err, "can't read while writing")
+	ts.NoError(file.Close())
+	ts.Equal("new", ts.readFile("file.txt"))
+}
+
+func (ts *fileTestSuite) TestNotExist() {
+	file := ts.newFile("/missing.txt")
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+
+	_, err = file.Read(make([]byte, 1))
+	ts.True(os.IsNotExist(err))
+	_, err = file.Size()
+	ts.True(os.IsNotExist(err))
+	ts.True(os.IsNotExist(file.Delete()))
+	ts.True(os.IsNotExist(file.CopyToFile(ts.newFile("/target.txt"))))
+}
+
+func (ts *fileTestSuite) TestStat() {
+	ts.writeFile("file.txt", "hello")
+	info, err := os.Stat(filepath.Join(ts.dir, "file.txt"))
+	ts.NoError(err)
+
+	stat, err := ts.newFile("/file.txt").(*File).Stat()
+	ts.NoError(err)
+	ts.Equal("file.txt", stat.Name())
+	ts.Equal(int64(5), stat.Size())
+	ts.Equal(info.Mode(), stat.Mode())
+	ts.True(info.ModTime().Equal(stat.ModTime()))
+
+	lastModified, err := ts.newFile("/file.txt").LastModified()
+	ts.NoError(err)
+	ts.True(info.ModTime().Equal(*lastModified))
+}
+
+func (ts *fileTestSuite) TestTouchAndDelete() {
+	file := ts.newFile("/touched.txt")
+	ts.NoError(file.Touch())
+	ts.Equal("", ts.readFile("touched.txt"))
+
+	ts.NoError(file.Delete())
+	_, err := os.Stat(filepath.Join(ts.dir, "touched.txt"))
+	ts.True(os.IsNotExist(err))
+}
+
+func (ts *fileTestSuite) TestCopyAndMove() {
+	ts.writeFile("source.txt", "contents")
+	source := ts.newFile("/source.txt")
+
+	target := ts.newFile("/copies/target.txt")
+	ts.NoError(source.CopyToFile(target))
+	ts.Equal("contents", ts.readFile("copies/target.txt"))
+
+	location, err := ts.fs.NewLocation(ts.address, "/moved/")
+	ts.NoError(err)
+	moved, err := source.MoveToLocation(location)
+	ts.NoError(err)
+	ts.Equal("/moved/source.txt", moved.Path())
+	ts.Equal("contents", ts.readFile("moved/source.txt"))
+	exists, err := source.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+
+	// to and from another scheme
+	memFile := ts.newMemFile()
+	ts.NoError(moved.CopyToFile(memFile))
+	copied, err := memFile.CopyToLocation(ts.newLocation("/from-mem/"))
+	ts.NoError(err)
+	ts.Equal("contents", ts.readFile("from-mem/"+copied.Name()))
+}
+
+func (ts *fileTestSuite) TestReadOnly() {
+	ts.writeFile("file.txt", "contents")
+	ts.serve(vfsgrpc.Options{ReadOnly: true})
+
+	file := ts.newFile("/file.txt")
+	data, err := ioutil.ReadAll(file)
+	ts.NoError(err)
+	ts.NoError(file.Close())
+	ts.Equal("contents", string(data))
+
+	_, _ = file.Write([]byte("changed"))
+	ts.True(os.IsPermission(file.Close()))
+	ts.True(os.IsPermission(file.Delete()))
+	ts.True(os.IsPermission(file.Touch()))
+	ts.True(os.IsPermission(file.CopyToFile(ts.newFile("/copy.txt"))))
+	ts.Equal("contents", ts.readFile("file.txt"))
+}
+
+func (ts *fileTestSuite) TestURI() {
+	file := ts.newFile("/some/path/file.txt")
+	ts.Equal("grpcvfs://"+ts.address+"/some/path/file.txt", file.URI())
+	ts.Equal("file.txt", file.Name())
+	ts.Equal("grpcvfs://"+ts.address+"/some/path/", file.Location().URI())
+}
+
+func (ts *fileTestSuite) newMemFile() vfs.File {
+	file, err := mem.NewFileSystem().NewFile("", "/copy.txt")
+	ts.NoError(err)
+	return file
+}
+
+func TestFile(t *testing.T) {
+	suite.Run(t, new(fileTestSuite))
+}
@@ -0,0 +1,164 @@
+package grpcvfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+	"github.com/c2fo/vfs/v5/vfsgrpc"
+)
+
+// Location implements the vfs.Location interface specific to grpcvfs fs.
+type Location struct {
+	fileSystem *FileSystem
+	path       string
+	Authority  utils.Authority
+}
+
+// List returns the names of the files at the location, as listed by the server's backend.
+func (l *Location) List() ([]string, error) {
+	return l.list("")
+}
+
+// ListByPrefix returns the names of the files at the location whose names begin with prefix.  As with other backends,
+// prefix may include a relative path, ie: "to/file".
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	return l.list(prefix)
+}
+
+// ListByRegex retrieves the filenames of all the files at the location's current path, then filters out all those
+// that don't match the given regex.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	filenames, err := l.List()
+	if err != nil {
+		return []string{}, err
+	}
+
+	filteredFilenames := []string{}
+	for _, filename := range filenames {
+		if regex.MatchString(filename) {
+			filteredFilenames = append(filteredFilenames, filename)
+		}
+	}
+	return filteredFilenames, nil
+}
+
+// Volume returns the Authority of the server the location is on.
+func (l *Location) Volume() string {
+	return fmt.Sprint(l.Authority)
+}
+
+// Path returns the absolute location path on the server, ie: /some/path/.
+func (l *Location) Path() string {
+	return utils.EnsureLeadingSlash(utils.EnsureTrailingSlash(l.path))
+}
+
+// Exists returns true if the location exists on the server's backend.
+func (l *Location) Exists() (bool, error) {
+	client, err := l.fileSystem.Client(l.Authority)
+	if err != nil {
+		return false, err
+	}
+	response, err := client.Exists(context.Background(), &vfsgrpc.FileRequest{Path: l.Path()})
+	if err != nil {
+		return false, convertError("exists", l.Path(), err)
+	}
+	return response.Exists, nil
+}
+
+// NewLocation makes a copy of the underlying Location, then modifies its path by calling ChangeDir with the
+// relativePath argument, returning the resulting location.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	if l == nil {
+		return nil, errors.New("non-nil grpcvfs.Location pointer receiver is required")
+	}
+
+	//make a copy of the original location first, then ChangeDir, leaving the original location as-is
+	newLocation := &Location{}
+	*newLocation = *l
+	err := newLocation.ChangeDir(relativePath)
+	if err != nil {
+		return nil, err
+	}
+	return newLocation, nil
+}
+
+// ChangeDir takes a relative path, and modifies the underlying Location's path.
+func (l *Location) ChangeDir(relativePath string) error {
+	if l == nil {
+		return errors.New("non-nil grpcvfs.Location pointer receiver is required")
+	}
+	if relativePath == "" {
+		return errors.New("non-empty string relativePath is required")
+	}
+	err := utils.ValidateRelativeLocationPath(relativePath)
+	if err != nil {
+		return err
+	}
+	l.path = utils.EnsureLeadingSlash(utils.EnsureTrailingSlash(path.Join(l.path, relativePath)))
+	return nil
+}
+
+// NewFile returns a File at filePath, relative to the location's path.
+func (l *Location) NewFile(filePath string) (vfs.File, error) {
+	if l == nil {
+		return nil, errors.New("non-nil grpcvfs.Location pointer receiver is required")
+	}
+	if filePath == "" {
+		return nil, errors.New("non-empty string filePath is required")
+	}
+	err := utils.ValidateRelativeFilePath(filePath)
+	if err != nil {
+		return nil, err
+	}
+	newFile := &File{
+		fileSystem: l.fileSystem,
+		Authority:  l.Authority,
+		path:       utils.EnsureLeadingSlash(path.Join(l.path, filePath)),
+	}
+	return newFile, nil
+}
+
+// DeleteFile removes the file at fileName path.
+func (l *Location) DeleteFile(fileName string) error {
+	file, err := l.NewFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	return file.Delete()
+}
+
+// FileSystem returns a vfs.fileSystem interface of the location's underlying fileSystem.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// URI returns the Location's URI as a string.
+func (l *Location) URI() string {
+	return utils.GetLocationURI(l)
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
+
+func (l *Location) list(prefix string) ([]string, error) {
+	client, err := l.fileSystem.Client(l.Authority)
+	if err != nil {
+		return []string{}, err
+	}
+	response, err := client.List(context.Background(), &vfsgrpc.ListRequest{Path: l.Path(), Prefix: prefix})
+	if err != nil {
+		return []string{}, convertError("list", l.Path(), err)
+	}
+	if response.Names == nil {
+		return []string{}, nil
+	}
+	return response.Names, nil
+}
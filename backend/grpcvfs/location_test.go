@@ -0,0 +1,84 @@
+package grpcvfs
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type locationTestSuite struct {
+	serverTestSuite
+}
+
+func (ts *locationTestSuite) TestList() {
+	ts.writeFile("dir/a.txt", "a")
+	ts.writeFile("dir/b.csv", "b")
+	ts.writeFile("dir/sub/c.txt", "c")
+	location := ts.newLocation("/dir/")
+
+	names, err := location.List()
+	ts.NoError(err)
+	ts.ElementsMatch([]string{"a.txt", "b.csv"}, names)
+
+	names, err = location.ListByPrefix("a")
+	ts.NoError(err)
+	ts.Equal([]string{"a.txt"}, names)
+	names, err = location.ListByPrefix("sub/c")
+	ts.NoError(err)
+	ts.Equal([]string{"c.txt"}, names)
+
+	names, err = location.ListByRegex(regexp.MustCompile(`\.csv$`))
+	ts.NoError(err)
+	ts.Equal([]string{"b.csv"}, names)
+
+	names, err = ts.newLocation("/missing/").List()
+	ts.NoError(err)
+	ts.Equal([]string{}, names)
+}
+
+func (ts *locationTestSuite) TestExists() {
+	ts.writeFile("dir/a.txt", "a")
+
+	exists, err := ts.newLocation("/dir/").Exists()
+	ts.NoError(err)
+	ts.True(exists)
+	exists, err = ts.newLocation("/missing/").Exists()
+	ts.NoError(err)
+	ts.False(exists)
+}
+
+func (ts *locationTestSuite) TestNewLocationAndFile() {
+	location := ts.newLocation("/some/path/")
+	ts.Equal(ts.address, location.Volume())
+	ts.Equal("/some/path/", location.Path())
+
+	other, err := location.NewLocation("../other/")
+	ts.NoError(err)
+	ts.Equal("/some/other/", other.Path())
+	ts.Equal("/some/path/", location.Path(), "original location is unchanged")
+
+	ts.NoError(location.ChangeDir("sub/"))
+	ts.Equal("grpcvfs://"+ts.address+"/some/path/sub/", location.URI())
+
+	file, err := location.NewFile("../file.txt")
+	ts.NoError(err)
+	ts.Equal("/some/path/file.txt", file.Path())
+	_, err = location.NewFile("")
+	ts.Error(err)
+	_, err = location.NewFile("/abs.txt")
+	ts.Error(err)
+}
+
+func (ts *locationTestSuite) TestDeleteFile() {
+	ts.writeFile("dir/a.txt", "a")
+	location := ts.newLocation("/dir/")
+	ts.NoError(location.DeleteFile("a.txt"))
+	names, err := location.List()
+	ts.NoError(err)
+	ts.Empty(names)
+}
+
+func TestLocation(t *testing.T) {
+	suite.Run(t, new(locationTestSuite))
+}
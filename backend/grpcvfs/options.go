@@ -0,0 +1,70 @@
+package grpcvfs
+
+import (
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// Options holds grpcvfs-specific options.
+type Options struct {
+	// DialOptions are passed to grpc.Dial when connecting to a server, ie: grpc.WithTransportCredentials() for TLS and
+	// grpc.WithPerRPCCredentials() to authenticate with the server.
+	DialOptions []grpc.DialOption
+	// Insecure connects without transport security, ie: to a server on localhost.
+	Insecure bool
+	// DisableMoveVerification skips checking that a file moved by copying (to another server or scheme) was copied in
+	// full before the source is deleted.
+	DisableMoveVerification bool
+	// ConflictPolicy determines what CopyToLocation and MoveToLocation do when the target file already exists.
+	ConflictPolicy vfs.ConflictPolicy
+}
+
+// dialOptions returns the grpc.DialOptions to connect to a server with.
+func (o Options) dialOptions() []grpc.DialOption {
+	opts := append([]grpc.DialOption{}, o.DialOptions...)
+	if o.Insecure {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	return opts
+}
+
+// convertError converts a gRPC status error from the server for the operation op on path to an *os.PathError for the
+// corresponding os error where there is one, so os.IsNotExist and os.IsPermission can be used.
+func convertError(op, path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	switch status.Code(err) {
+	case codes.NotFound:
+		return &os.PathError{Op: op, Path: path, Err: os.ErrNotExist}
+	case codes.PermissionDenied:
+		return &os.PathError{Op: op, Path: path, Err: os.ErrPermission}
+	case codes.AlreadyExists:
+		return vfs.ErrFileExists
+	default:
+		return err
+	}
+}
+
+// classifyError maps gRPC status errors that convertError leaves as they are onto a vfs.ErrorKind.  It's registered
+// with vfs.RegisterErrorClassifier, so vfs.ClassifyError classifies them.
+func classifyError(err error) vfs.ErrorKind {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return vfs.ErrorNotFound
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return vfs.ErrorPermission
+	case codes.DeadlineExceeded:
+		return vfs.ErrorTimeout
+	case codes.Unavailable:
+		return vfs.ErrorNetwork
+	case codes.Canceled:
+		return vfs.ErrorCanceled
+	}
+	return vfs.ErrorUnknown
+}
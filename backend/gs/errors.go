@@ -0,0 +1,34 @@
+package gs
+
+import (
+	"net/http"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// classifyError maps err, an error returned by the gs backend, onto a vfs.ErrorKind.  It's registered with
+// vfs.RegisterErrorClassifier, so vfs.ClassifyError classifies gcs errors.  vfs.ErrorUnknown is returned for errors
+// that didn't come from gcs.
+func classifyError(err error) vfs.ErrorKind {
+	if err == storage.ErrObjectNotExist || err == storage.ErrBucketNotExist {
+		return vfs.ErrorNotFound
+	}
+	apiErr, ok := err.(*googleapi.Error)
+	if !ok {
+		return vfs.ErrorUnknown
+	}
+	switch apiErr.Code {
+	case http.StatusNotFound:
+		return vfs.ErrorNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return vfs.ErrorPermission
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return vfs.ErrorThrottled
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return vfs.ErrorTimeout
+	}
+	return vfs.ErrorUnknown
+}
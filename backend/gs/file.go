@@ -3,6 +3,7 @@ package gs
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -60,6 +61,7 @@ func (f *File) Close() error {
 		}
 	}
 
+	utils.PutWriteBuffer(f.writeBuffer)
 	f.writeBuffer = nil
 	return nil
 }
@@ -95,7 +97,7 @@ func (f *File) Write(data []byte) (n int, err error) {
 		//
 		//so now we do:
 
-		f.writeBuffer = bytes.NewBuffer([]byte{})
+		f.writeBuffer = utils.GetWriteBuffer()
 
 	}
 	return f.writeBuffer.Write(data)
@@ -129,32 +131,58 @@ func (f *File) Location() vfs.Location {
 
 // CopyToLocation creates a copy of *File, using the file's current name as the new file's
 // name at the given location. If the given location is also GCS, the GCS API for copying
-// files will be utilized, otherwise, standard io.Copy will be done to the new file.
-func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
-	dest, err := location.NewFile(f.Name())
+// files will be utilized, otherwise, standard io.Copy will be done to the new file.  If a
+// file of the same name already exists at the location, the ConflictPolicy in Options
+// determines what happens (overwriting it by default).
+func (f *File) CopyToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	dest, _, err := f.copyToLocation(location, opts)
 	if err != nil {
 		return nil, err
 	}
-	err = f.CopyToFile(dest)
+	return dest, nil
+}
+
+// copyToLocation copies the file to the location according to the ConflictPolicy in opts, or else in Options, returning
+// the target file and whether the copy was skipped.
+func (f *File) copyToLocation(location vfs.Location, opts []vfs.CopyOption) (vfs.File, bool, error) {
+	var policy vfs.ConflictPolicy
+	if fsOpts, ok := f.fileSystem.options.(Options); ok {
+		policy = fsOpts.ConflictPolicy
+	}
+	policy = vfs.NewCopyOptions(opts...).ConflictPolicyOr(policy)
+
+	dest, err := location.NewFile(f.Name())
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	return dest, nil
+	dest, skip, err := utils.ResolveConflict(dest, policy)
+	if err != nil || skip {
+		return dest, skip, err
+	}
+	return dest, false, f.CopyToFile(dest, opts...)
 }
 
 // CopyToFile puts the contents of File into the target vfs.File passed in. Uses the GCS CopierFrom
 // method if the target file is also on GCS, otherwise uses io.Copy.
-func (f *File) CopyToFile(file vfs.File) error {
+func (f *File) CopyToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	copyOptions := vfs.NewCopyOptions(opts...)
+	if err := copyOptions.Context.Err(); err != nil {
+		return err
+	}
+
 	if tf, ok := file.(*File); ok {
 		options, ok := tf.Location().FileSystem().(*FileSystem).options.(Options)
 		if ok {
 			if f.isSameAuth(&options) {
-				return f.copyWithinGCSToFile(tf)
+				if err := f.copyWithinGCSToFile(tf); err != nil {
+					return err
+				}
+				return f.verifyChecksum(file, copyOptions)
 			}
 		}
 	}
 
-	if err := utils.TouchCopy(file, f); err != nil {
+	if err := utils.TouchCopyWithOptions(file, f, copyOptions); err != nil {
 		return err
 	}
 	//Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
@@ -162,33 +190,67 @@ func (f *File) CopyToFile(file vfs.File) error {
 		return cerr
 	}
 	//Close file (f) reader
-	return f.Close()
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return f.verifyChecksum(file, copyOptions)
+}
+
+// verifyChecksum compares the contents of the file and target, a copy of it, if options ask to.
+func (f *File) verifyChecksum(target vfs.File, options vfs.CopyOptions) error {
+	if !options.VerifyChecksum {
+		return nil
+	}
+	return utils.VerifyChecksum(f, target)
 }
 
 // MoveToLocation works by first calling File.CopyToLocation(vfs.Location) then, if that
 // succeeds, it deletes the original file, returning the new file. If the copy process fails
 // the error is returned, and the Delete isn't called. If the call to Delete fails, the error
-// and the file generated by the copy are both returned.
-func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
-	newFile, err := f.CopyToLocation(location)
+// and the file generated by the copy are both returned.  If the copy is skipped under the
+// vfs.ConflictSkip policy, the original file isn't deleted.
+func (f *File) MoveToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	newFile, skipped, err := f.copyToLocation(location, opts)
 	if err != nil {
 		return nil, err
 	}
+	if skipped {
+		return newFile, nil
+	}
+	if err := f.verifyMove(newFile, opts); err != nil {
+		return nil, err
+	}
 	delErr := f.Delete()
 	return newFile, delErr
 }
 
 // MoveToFile puts the contents of File into the target vfs.File passed in using File.CopyToFile.
 // If the copy succeeds, the source file is deleted. Any errors from the copy or delete are
-// returned.
-func (f *File) MoveToFile(file vfs.File) error {
-	if err := f.CopyToFile(file); err != nil {
+// returned.  When moving to another scheme, the copy is verified against the source (see
+// utils.VerifyCopy) before the source is deleted, unless move verification is disabled.
+func (f *File) MoveToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	if err := f.CopyToFile(file, opts...); err != nil {
+		return err
+	}
+	if err := f.verifyMove(file, opts); err != nil {
 		return err
 	}
 
 	return f.Delete()
 }
 
+// verifyMove checks that a move's copy to another scheme was written in full before the source is deleted, unless
+// DisableMoveVerification is set in Options or the move's options include vfs.WithoutMoveVerification.
+func (f *File) verifyMove(target vfs.File, opts []vfs.CopyOption) error {
+	if target.Location().FileSystem().Scheme() == Scheme || vfs.NewCopyOptions(opts...).SkipMoveVerification {
+		return nil
+	}
+	if fsOpts, ok := f.fileSystem.options.(Options); ok && fsOpts.DisableMoveVerification {
+		return nil
+	}
+	return utils.VerifyCopy(f, target)
+}
+
 // Delete clears any local temp file, or write buffer from read/writes to the file, then makes
 // a DeleteObject call to GCS for the file. Returns any error returned by the API.
 func (f *File) Delete() error {
@@ -351,6 +413,19 @@ func (f *File) Size() (uint64, error) {
 	return uint64(attr.Size), nil
 }
 
+// ContentMD5 returns the hex encoded MD5 of the object's contents stored by gcs, so utils.VerifyCopy can verify a copy
+// to or from gcs without reading the object.  ok is false for composite objects, which have none.
+func (f *File) ContentMD5() (md5 string, ok bool, err error) {
+	attr, err := f.getObjectAttrs()
+	if err != nil {
+		return "", false, err
+	}
+	if len(attr.MD5) == 0 {
+		return "", false, nil
+	}
+	return hex.EncodeToString(attr.MD5), true, nil
+}
+
 // Path returns full path with leading slash of the GCS file key.
 func (f *File) Path() string {
 	return f.key
@@ -124,4 +124,5 @@ func NewFileSystem() *FileSystem {
 func init() {
 	//registers a default Filesystem
 	backend.Register(Scheme, NewFileSystem())
+	vfs.RegisterErrorClassifier(classifyError)
 }
@@ -12,6 +12,11 @@ type Options struct {
 	CredentialFile string   `json:"credentialFilePath,omitempty"`
 	Endpoint       string   `json:"endpoint,omitempty"`
 	Scopes         []string `json:"WithoutAuthentication,omitempty"`
+	// DisableMoveVerification skips checking that a file moved to another scheme was copied in full before the source
+	// is deleted.
+	DisableMoveVerification bool `json:"disableMoveVerification,omitempty"`
+	// ConflictPolicy determines what CopyToLocation and MoveToLocation do when the target file already exists.
+	ConflictPolicy vfs.ConflictPolicy `json:"conflictPolicy,omitempty"`
 	Retry          vfs.Retry
 }
 
@@ -0,0 +1,16 @@
+package mem_test
+
+import (
+	"testing"
+
+	"github.com/c2fo/vfs/v5/backend/mem"
+	"github.com/c2fo/vfs/v5/vfstest"
+)
+
+func TestConformance(t *testing.T) {
+	location, err := mem.NewFileSystem().NewLocation("", "/conformance/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vfstest.Run(t, location)
+}
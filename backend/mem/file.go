@@ -21,7 +21,8 @@ import (
 //	up-to-date file contents while also preserving the state of their own cursor that is
 //	used in Read() and Seek() calls. Calls to Write() all reference
 // 	"filename's" buffer, which is why it is locked. Data written into the writeBuffer
-//	does not appear in "filename's" 'contents' slice until a call to Close() is made
+//	does not appear in "filename's" 'contents' slice until a call to Close() is made,
+//	which replaces the contents with it, as writing to a file does on other backends.
 //
 type memFile struct {
 	sync.Mutex
@@ -33,6 +34,7 @@ type memFile struct {
 	name         string
 	isOpen       bool
 	filepath     string
+	written      bool // Write has been called since the last Close
 }
 
 //File implements vfs.File interface for the in-memory implementation of FileSystem.
@@ -70,14 +72,11 @@ func (f *File) Close() error {
 		return nilReference()
 	}
 	f.memFile.Lock()
-	if f.memFile.writeBuffer.Len() > 0 {
-		bufferContents := f.memFile.writeBuffer.Bytes()
-		for i := range bufferContents {
-			f.memFile.contents = append(f.memFile.contents, bufferContents[i])
-
-		}
+	if f.memFile.written {
+		f.memFile.contents = f.memFile.writeBuffer.Bytes()
 		f.memFile.lastModified = time.Now()
 		f.memFile.writeBuffer = new(bytes.Buffer)
+		f.memFile.written = false
 	}
 
 	f.memFile.isOpen = false
@@ -96,7 +95,6 @@ func (f *File) Read(p []byte) (n int, err error) {
 		return 0, doesNotExist()
 	}
 	//if file exists:
-	offset := f.cursor
 	if f.isOpen == false {
 		f.isOpen = true
 	}
@@ -133,7 +131,7 @@ func (f *File) Read(p []byte) (n int, err error) {
 	if f.cursor > len(f.contents) {
 		f.cursor = len(f.contents)
 	}
-	return readBufferLength - offset, nil
+	return j, nil
 
 }
 
@@ -146,6 +144,8 @@ func (f *File) Seek(offset int64, whence int) (int64, error) {
 		}
 		return 0, doesNotExist()
 	}
+	//in case the file contents have changed
+	f.synchronize()
 
 	length := len(f.contents)
 
@@ -192,6 +192,7 @@ func (f *File) Write(p []byte) (int, error) {
 	}
 	f.memFile.Lock()
 	num, err := f.memFile.writeBuffer.Write(p)
+	f.memFile.written = true
 	f.memFile.lastModified = time.Now()
 	f.memFile.Unlock()
 	return num, err
@@ -217,6 +218,8 @@ func (f *File) Exists() (bool, error) {
 			if object, ok2 := mapRef[vol][fullPath]; ok2 {
 
 				if object != nil && object.i.(*memFile).exists {
+					//in case the file was deleted and written again since this File was created
+					f.memFile = object.i.(*memFile)
 					return true, nil
 				}
 			}
@@ -241,7 +244,7 @@ func (f *File) Location() vfs.Location {
 //at given location contents are simply overwritten using "CopyToFile", otherwise
 //a newFile is made, takes the contents of the current file, and ends up at
 //the given location
-func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+func (f *File) CopyToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
 
 	if ok, err := f.Exists(); !ok {
 		if err != nil {
@@ -249,6 +252,11 @@ func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
 		}
 		return nil, doesNotExist()
 	}
+	if resolved, skip, err := resolveConflict(f, location, opts); err != nil || skip {
+		return resolved, err
+	} else if resolved != nil {
+		return resolved, f.CopyToFile(resolved, opts...)
+	}
 	testPath := path.Join(path.Clean(location.Path()), f.Name())
 	thisLoc := f.Location().(*Location)
 	mapRef := thisLoc.fileSystem.fsMap
@@ -261,7 +269,7 @@ func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
 			memFile := mapRef[vol][testPath].i.(*memFile)
 			file := deepCopy(memFile)
 
-			cerr := f.CopyToFile(file)
+			cerr := f.CopyToFile(file, opts...)
 
 			if cerr != nil {
 				return nil, cerr
@@ -279,23 +287,42 @@ func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
 		return nil, err
 	}
 
-	if err = f.CopyToFile(newFile); err != nil {
+	if err = f.CopyToFile(newFile, opts...); err != nil {
 		return nil, err
 	}
 
 	return newFile, nil
 }
 
+// resolveConflict returns the file to copy or move to in place of the file of the same name as f at location, and
+// whether to skip it, according to the ConflictPolicy in opts.  nil is returned if no ConflictPolicy is set, or it's
+// vfs.ConflictOverwrite, in which case the file at location is simply overwritten.
+func resolveConflict(f *File, location vfs.Location, opts []vfs.CopyOption) (vfs.File, bool, error) {
+	policy := vfs.NewCopyOptions(opts...).ConflictPolicyOr(vfs.ConflictOverwrite)
+	if policy == vfs.ConflictOverwrite {
+		return nil, false, nil
+	}
+	target, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, false, err
+	}
+	return utils.ResolveConflict(target, policy)
+}
+
 //CopyToFile copies the receiver file into the target file.
 //The target file is deleted, so any references to it will
 //be nil.  In order to access the target after calling CopyToFile
 //use its previous path to call it using the fsMap.  Additionally,
 //after this is called, f's cursor will reset as if it had been closed.
-func (f *File) CopyToFile(target vfs.File) error {
+func (f *File) CopyToFile(target vfs.File, opts ...vfs.CopyOption) error {
 
 	if f == nil || target == nil {
 		return nilReference()
 	}
+	options := vfs.NewCopyOptions(opts...)
+	if err := options.Context.Err(); err != nil {
+		return err
+	}
 
 	if ex, _ := f.Exists(); !ex {
 		return doesNotExist()
@@ -322,19 +349,25 @@ func (f *File) CopyToFile(target vfs.File) error {
 		}
 
 	}
-	if _, err := target.Write(f.memFile.contents); err != nil {
+	if _, err := utils.CopyWithOptions(target, bytes.NewReader(f.memFile.contents), options); err != nil {
 		return err
 	}
 	err := target.Close()
 	if err != nil {
 		return err
 	}
-	return f.Close()
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if options.VerifyChecksum {
+		return utils.VerifyChecksum(f, target)
+	}
+	return nil
 }
 
 //MoveToLocation moves the receiver file to the passed in location. It does so by
 //creating a copy of 'f' in "location".  'f' is subsequently  deleted
-func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+func (f *File) MoveToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
 
 	if f == nil || location == nil {
 		return nil, nilReference()
@@ -346,6 +379,11 @@ func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
 		}
 		return nil, doesNotExist()
 	}
+	if resolved, skip, err := resolveConflict(f, location, opts); err != nil || skip {
+		return resolved, err
+	} else if resolved != nil {
+		return resolved, f.MoveToFile(resolved, opts...)
+	}
 
 	// if the underling FileSystem is in-memory, then this is the native way of
 	//replacing a file with the same name as "f" at the location
@@ -364,7 +402,7 @@ func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
 				memFile := mapRef[vol][testPath].i.(*memFile)
 				f.memFile.location.FileSystem().(*FileSystem).Unlock()
 				file := deepCopy(memFile)
-				err := f.CopyToFile(file)
+				err := f.CopyToFile(file, opts...)
 				if err != nil {
 					return nil, err
 				}
@@ -390,10 +428,15 @@ func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
 		return nil, err
 	}
 	//copying over the data
-	err = f.CopyToFile(newFile)
+	err = f.CopyToFile(newFile, opts...)
 	if err != nil {
 		return nil, err
 	}
+	if location.FileSystem().Scheme() != Scheme && !vfs.NewCopyOptions(opts...).SkipMoveVerification {
+		if err := utils.VerifyCopy(f, newFile); err != nil {
+			return nil, err
+		}
+	}
 	//delete the receiver
 	err = f.Delete()
 	if err != nil {
@@ -404,7 +447,7 @@ func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
 
 //MoveToFile creates a newFile, and moves it to "file".
 //The receiver is always deleted (since it's being "moved")
-func (f *File) MoveToFile(file vfs.File) error {
+func (f *File) MoveToFile(file vfs.File, opts ...vfs.CopyOption) error {
 	if f == nil {
 		return nilReference()
 	}
@@ -414,9 +457,14 @@ func (f *File) MoveToFile(file vfs.File) error {
 		}
 		return doesNotExist()
 	}
-	if err := f.CopyToFile(file); err != nil {
+	if err := f.CopyToFile(file, opts...); err != nil {
 		return err
 	}
+	if file.Location().FileSystem().Scheme() != Scheme && !vfs.NewCopyOptions(opts...).SkipMoveVerification {
+		if err := utils.VerifyCopy(f, file); err != nil {
+			return err
+		}
+	}
 
 	return f.Delete()
 }
@@ -469,6 +517,7 @@ func newMemFile(file *File, location vfs.Location) *memFile {
 		file.name,
 		false,
 		path.Join(location.Path(), file.Name()),
+		false,
 	}
 }
 
@@ -483,7 +532,8 @@ func (f *File) LastModified() (*time.Time, error) {
 		}
 		return nil, doesNotExist()
 	}
-	return &f.memFile.lastModified, nil
+	lastModified := f.memFile.lastModified
+	return &lastModified, nil
 }
 
 //Size returns the size of the file contents.  In our case, the length of the file's byte slice
@@ -368,6 +368,7 @@ func (s *memFileTest) TestCopyToLocationOS() {
 	s.NoError(err, "unexpected error creating osFile")
 	_, err = osFile.Write(make([]byte, 0))
 	s.NoError(err, "unexpected error writing zero bytes to osFile")
+	s.NoError(osFile.Close(), "unexpected error closing osFile")
 
 	exists, err := osFile.Exists()
 	s.NoError(err, "unexpected existence error")
@@ -650,6 +651,7 @@ func (s *memFileTest) TestLastModified() {
 	_, err = s.testFile.Write([]byte("hey!"))
 	s.NoError(err, "unexpected write error")
 
+	t, _ = s.testFile.LastModified()
 	secondTime := *t
 
 	s.True(secondTime.UnixNano() > firstTime.UnixNano())
@@ -166,13 +166,17 @@ func (l *Location) NewFile(relFilePath string) (vfs.File, error) {
 		return nil, err
 	}
 
+	pref := l.Path()
+	str := relFilePath
+	nameStr := path.Join(pref, str)
+
 	//after validating the path, we check to see if the
 	//file already exists. if it does, return a reference to it
 	mapRef := l.fileSystem.fsMap
 	if _, ok := mapRef[l.volume]; ok {
-		fileList := mapRef[l.volume].filesHere(l.Path())
+		fileList := mapRef[l.volume].filesHere(utils.EnsureTrailingSlash(path.Dir(nameStr)))
 		for _, file := range fileList {
-			if file.name == path.Base(relFilePath) {
+			if file.name == path.Base(nameStr) {
 				fileCopy := deepCopy(file)
 				return fileCopy, nil
 			}
@@ -181,9 +185,6 @@ func (l *Location) NewFile(relFilePath string) (vfs.File, error) {
 
 	//since the file didn't already exist, we will create a
 	//location and the file (NewLocation takes care of duplicates)
-	pref := l.Path()
-	str := relFilePath
-	nameStr := path.Join(pref, str)
 
 	loc, err := l.fileSystem.NewLocation(l.Volume(), utils.EnsureTrailingSlash(path.Dir(nameStr)))
 	if err != nil {
@@ -208,7 +209,7 @@ func (l *Location) DeleteFile(relFilePath string) error {
 	fullPath := path.Join(l.Path(), relFilePath)
 	mapRef := l.fileSystem.fsMap
 	if _, ok := mapRef[vol]; ok {
-		if thisObj, ok2 := mapRef[vol][fullPath]; ok2 {
+		if thisObj, ok2 := mapRef[vol][fullPath]; ok2 && thisObj != nil {
 			file := thisObj.i.(*memFile)
 			file.exists = false
 			file = nil
@@ -1,6 +1,7 @@
 package mem
 
 import (
+	"io/ioutil"
 	"path"
 	"regexp"
 	"testing"
@@ -221,6 +222,27 @@ func (s *memLocationTest) TestNewFileSameName() {
 
 }
 
+//TestNewFileNestedPath ensures that a file created with a nested relative path is found again, and that a file of the
+//same name directly at the location isn't mistaken for it
+func (s *memLocationTest) TestNewFileNestedPath() {
+	location, err := s.fileSystem.NewLocation("", "/nested/")
+	s.NoError(err, "unexpected error creating a location")
+
+	for name, text := range map[string]string{"file.txt": "top", "sub/file.txt": "nested"} {
+		file, err := location.NewFile(name)
+		s.NoError(err, "unexpected error creating a file")
+		_, err = file.Write([]byte(text))
+		s.NoError(err, "unexpected error writing to file")
+		s.NoError(file.Close(), "unexpected error closing file")
+	}
+
+	file, err := location.NewFile("sub/file.txt")
+	s.NoError(err, "unexpected error creating a file")
+	data, err := ioutil.ReadAll(file)
+	s.NoError(err, "unexpected read error")
+	s.Equal("nested", string(data))
+}
+
 //TestChangeDir tests that we can change the directory on a location but that it doesn't change the file's location
 func (s *memLocationTest) TestChangeDir() {
 
@@ -0,0 +1,24 @@
+package os_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/vfstest"
+)
+
+func TestConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	location, err := _os.NewFileSystem().NewLocation("", dir+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vfstest.Run(t, location)
+}
@@ -24,6 +24,30 @@ Or call directly:
       ...
   }
 
+Options
+
+Copies between os files can keep the source's permission bits and modification time, ie: when staging executables:
+
+  fs := _os.NewFileSystem().WithOptions(_os.Options{PreserveMode: true, PreserveTimes: true})
+
+os.File also provides FileMode, Chmod and Chown.
+
+Copies between os files on the same filesystem can be made instantly, without using additional space, by setting
+CopyMethod to CopyHardLink or CopyClone (a copy-on-write reflink, linux only).  Copies that can't be made that way,
+ie: across devices, fall back to copying the contents.
+
+Symbolic Links
+
+By default, symlinks to files are listed and copied as the files they point to, while symlinks to directories are never
+listed, so copying a tree doesn't duplicate a symlinked directory.  With Options.NoFollowSymlinks, List skips symlinks
+entirely and copying a symlink to another os file recreates the link itself.  os.File also provides IsSymlink, Readlink
+and Symlink.
+
+Locking
+
+os.File implements vfs.Locker with an flock of a lock file beside it, the file's path with a ".lock" suffix, which is
+held until the lease is released or the process exits.  Locking is only supported on linux, darwin and freebsd.
+
 See Also
 
 See: https://golang.org/pkg/os/
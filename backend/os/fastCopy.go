@@ -0,0 +1,52 @@
+package os
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CopyMethod determines how an os file is copied to another os file.
+type CopyMethod int
+
+const (
+	// CopyContents copies the file's contents (the default).
+	CopyContents CopyMethod = iota
+	// CopyHardLink hard links the copy to the original, so it takes no additional space.  Since Write replaces a file
+	// rather than modifying it in place, writing to either file afterwards doesn't affect the other, but the in-place
+	// changes made by File.WriteAt and File.Truncate affect both.
+	CopyHardLink
+	// CopyClone makes a copy-on-write clone (reflink) of the file where the filesystem supports it (ie: btrfs, XFS),
+	// otherwise an in-kernel copy_file_range copy.  Only available on linux.
+	CopyClone
+)
+
+// fastCopy copies the file to targetPath using method, returning false if the copy couldn't be made that way (ie: the
+// target is on a different device) and should fall back to copying the contents.  Like Write, the copy is made to a
+// temp file that is renamed into place.
+func (f *File) fastCopy(targetPath string, method CopyMethod) bool {
+	if err := os.MkdirAll(filepath.Dir(targetPath), os.ModeDir|0777); err != nil {
+		return false
+	}
+	tempPath := filepath.Join(filepath.Dir(targetPath),
+		fmt.Sprintf(".%s.%d.tmp", filepath.Base(targetPath), time.Now().UnixNano()))
+
+	var err error
+	switch method {
+	case CopyHardLink:
+		err = os.Link(f.Path(), tempPath)
+	case CopyClone:
+		err = cloneFile(f.Path(), tempPath)
+	default:
+		return false
+	}
+	if err == nil {
+		err = os.Rename(tempPath, targetPath)
+	}
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return false
+	}
+	return true
+}
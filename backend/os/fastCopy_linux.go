@@ -0,0 +1,53 @@
+package os
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// ficlone is the FICLONE ioctl request, which clones one file's extents into another.
+const ficlone = 0x40049409
+
+// cloneFile makes a copy-on-write clone of src at dst, falling back to copy_file_range if cloning isn't supported.
+func cloneFile(src, dst string) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = srcFile.Close() }()
+
+	stats, err := srcFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	dstFile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		return err
+	}
+
+	err = unix.IoctlSetInt(int(dstFile.Fd()), ficlone, int(srcFile.Fd()))
+	if err != nil {
+		err = copyFileRange(srcFile, dstFile, stats.Size())
+	}
+	if closeErr := dstFile.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// copyFileRange copies size bytes from src to dst in the kernel.
+func copyFileRange(src, dst *os.File, size int64) error {
+	for size > 0 {
+		n, err := unix.CopyFileRange(int(src.Fd()), nil, int(dst.Fd()), nil, int(size), 0)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return unix.EIO
+		}
+		size -= int64(n)
+	}
+	return nil
+}
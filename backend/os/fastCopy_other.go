@@ -0,0 +1,12 @@
+// +build !linux
+
+package os
+
+import (
+	"errors"
+)
+
+// cloneFile isn't supported outside of linux.
+func cloneFile(src, dst string) error {
+	return errors.New("file cloning is only supported on linux")
+}
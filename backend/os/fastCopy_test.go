@@ -0,0 +1,94 @@
+package os
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+type osFastCopyTest struct {
+	suite.Suite
+	dir string
+	fs  *FileSystem
+	src vfs.File
+}
+
+func (s *osFastCopyTest) SetupTest() {
+	dir, err := ioutil.TempDir("", "os_fast_copy_test")
+	s.NoError(err)
+	s.dir = utils.EnsureTrailingSlash(dir)
+	s.fs = NewFileSystem()
+
+	s.src, err = s.fs.NewFile("", s.dir+"src.txt")
+	s.NoError(err)
+	_, err = s.src.Write([]byte("some contents"))
+	s.NoError(err)
+	s.NoError(s.src.Close())
+}
+
+func (s *osFastCopyTest) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *osFastCopyTest) TestHardLink() {
+	s.fs.WithOptions(Options{CopyMethod: CopyHardLink})
+	target, err := s.fs.NewFile("", s.dir+"sub/linked.txt")
+	s.NoError(err)
+	s.NoError(s.src.CopyToFile(target))
+
+	srcStats, err := os.Stat(s.src.Path())
+	s.NoError(err)
+	targetStats, err := os.Stat(target.Path())
+	s.NoError(err)
+	s.True(os.SameFile(srcStats, targetStats), "copy is a hard link")
+
+	// writing to the copy replaces it, leaving the original alone
+	_, err = target.Write([]byte("new contents"))
+	s.NoError(err)
+	s.NoError(target.Close())
+	data, err := ioutil.ReadFile(s.src.Path())
+	s.NoError(err)
+	s.Equal("some contents", string(data))
+
+	// an existing target is replaced
+	s.NoError(s.src.CopyToFile(target))
+	data, err = ioutil.ReadFile(target.Path())
+	s.NoError(err)
+	s.Equal("some contents", string(data))
+}
+
+func (s *osFastCopyTest) TestClone() {
+	s.fs.WithOptions(Options{CopyMethod: CopyClone})
+	loc, err := s.src.Location().NewLocation("clones/")
+	s.NoError(err)
+	copied, err := s.src.CopyToLocation(loc)
+	s.NoError(err)
+
+	data, err := ioutil.ReadFile(copied.Path())
+	s.NoError(err)
+	s.Equal("some contents", string(data), "cloned, or fell back to a regular copy")
+
+	srcStats, err := os.Stat(s.src.Path())
+	s.NoError(err)
+	copiedStats, err := os.Stat(copied.Path())
+	s.NoError(err)
+	s.False(os.SameFile(srcStats, copiedStats))
+}
+
+func (s *osFastCopyTest) TestFastCopy_Unsupported() {
+	s.False(s.src.(*File).fastCopy(s.dir+"other.txt", CopyContents))
+	missing, err := s.fs.NewFile("", s.dir+"missing.txt")
+	s.NoError(err)
+	s.False(missing.(*File).fastCopy(s.dir+"other.txt", CopyHardLink), "link fails")
+	_, err = os.Stat(s.dir + "other.txt")
+	s.True(os.IsNotExist(err))
+}
+
+func TestOSFastCopy(t *testing.T) {
+	suite.Run(t, new(osFastCopyTest))
+}
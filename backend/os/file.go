@@ -1,8 +1,9 @@
 package os
 
 import (
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -45,6 +46,35 @@ func (f *File) LastModified() (*time.Time, error) {
 	return &statsTime, err
 }
 
+// Stat returns the file's name, size, last modified time and mode from a single os.Stat call.
+func (f *File) Stat() (*vfs.FileInfo, error) {
+	stats, err := os.Stat(f.Path())
+	if err != nil {
+		return nil, err
+	}
+	return vfs.NewFileInfo(f.Name(), stats.Size(), stats.ModTime(), stats.Mode()), nil
+}
+
+// FileMode returns the file's mode and permission bits.
+func (f *File) FileMode() (os.FileMode, error) {
+	stats, err := os.Stat(f.Path())
+	if err != nil {
+		return 0, err
+	}
+
+	return stats.Mode(), nil
+}
+
+// Chmod changes the file's mode to mode.
+func (f *File) Chmod(mode os.FileMode) error {
+	return os.Chmod(f.Path(), mode)
+}
+
+// Chown changes the numeric uid and gid of the file.  A uid or gid of -1 leaves that value unchanged.
+func (f *File) Chown(uid, gid int) error {
+	return os.Chown(f.Path(), uid, gid)
+}
+
 // Name returns the full name of the File relative to Location.Name().
 func (f *File) Name() string {
 	return path.Base(f.name)
@@ -65,29 +95,16 @@ func (f *File) Size() (uint64, error) {
 	return uint64(stats.Size()), err
 }
 
-// Close implements the io.Closer interface, closing the underlying *os.File. its an error, if any.
+// Close implements the io.Closer interface, closing the underlying *os.File. its an error, if any.  If the file was
+// written to, the temp file holding the writes is renamed over the file's path, so readers never observe a partially
+// written file.
 func (f *File) Close() error {
-	// check if temp file
-	// close temp file
-	// os.Rename() (replace) temp file to file
 	f.useTempFile = false
 	f.cursorPos = 0
 	if f.tempFile != nil {
-		err := f.tempFile.Close()
-		if err != nil {
-			return err
-		}
-
-		// get original file, open it if it has not been opened
-		finalFile, err := f.getInternalFile()
-		if err != nil {
+		if err := f.commitTempFile(); err != nil {
 			return err
 		}
-		err = os.Rename(f.tempFile.Name(), finalFile.Name())
-		if err != nil && !os.IsNotExist(err) {
-			return err
-		}
-		f.tempFile = nil
 	}
 	if f.file == nil {
 		// Do nothing on files that were never referenced
@@ -132,6 +149,14 @@ func (f *File) Read(p []byte) (int, error) {
 // the file, 1 means relative to the current offset, and 2 means relative to the end.  It returns the new offset and
 // an error, if any.
 func (f *File) Seek(offset int64, whence int) (int64, error) {
+	// as for Read, don't create the file by opening it
+	if !f.useTempFile && f.file == nil {
+		if exists, err := f.Exists(); err != nil {
+			return 0, err
+		} else if !exists {
+			return 0, fmt.Errorf("failed to seek. File does not exist at %s", f)
+		}
+	}
 	useFile, err := f.getInternalFile()
 	if err != nil {
 		return 0, err
@@ -145,6 +170,72 @@ func (f *File) Seek(offset int64, whence int) (int64, error) {
 	return f.cursorPos, err
 }
 
+// ReadRange returns up to length bytes of the file starting at offset, reading them from a separate file handle so the
+// file's read cursor is unaffected.  Fewer than length bytes are returned if the range extends past the end of the
+// file, and io.EOF if offset is at or beyond it.
+func (f *File) ReadRange(offset, length int64) ([]byte, error) {
+	if offset < 0 || length < 1 {
+		return nil, errors.New("non-negative offset and positive length are required")
+	}
+
+	file, err := os.Open(f.Path())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	buf := make([]byte, length)
+	n, err := file.ReadAt(buf, offset)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// Truncate changes the size of the file, discarding anything beyond size or extending it with zeros, as os.Truncate
+// does.  Unlike Write, the change is made to the file in place rather than on Close, so it's also seen through any
+// hard links to the file, ie: copies made with CopyHardLink.  An error is returned if the File has writes pending
+// Close, which would replace the change.
+func (f *File) Truncate(size int64) error {
+	if err := f.checkInPlace("truncate"); err != nil {
+		return err
+	}
+	return os.Truncate(f.Path(), size)
+}
+
+// WriteAt writes p to the file starting at offset off, in place, ie: to fix up a fixed-width record.  As with
+// ReadRange, a separate file handle is used so the file's cursor is unaffected, and unlike Write the data goes straight
+// to the file rather than replacing it on Close, so it's also seen through any hard links to the file.  The file must
+// already exist; it's extended if off is beyond its end.  As with Truncate, an error is returned if the File has writes
+// pending Close.
+func (f *File) WriteAt(p []byte, off int64) (int, error) {
+	if err := f.checkInPlace("write"); err != nil {
+		return 0, err
+	}
+	file, err := os.OpenFile(f.Path(), os.O_WRONLY, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := file.WriteAt(p, off)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return n, err
+}
+
+// checkInPlace returns an error if the File has writes pending Close, since committing them would silently discard an
+// in-place change made in the meantime.
+func (f *File) checkInPlace(op string) error {
+	if f.useTempFile || f.tempFile != nil {
+		return fmt.Errorf("unable to %s %s in place while it has writes pending Close", op, f)
+	}
+	return nil
+}
+
 // Exists true if the file exists on the file system, otherwise false, and an error, if any.
 func (f *File) Exists() (bool, error) {
 	_, err := os.Stat(f.Path())
@@ -178,6 +269,42 @@ func (f *File) Write(p []byte) (n int, err error) {
 	return write, err
 }
 
+// WriteTo implements the io.WriterTo interface so io.Copy reads the underlying file directly, letting the kernel copy
+// the data (ie: with copy_file_range, where supported) when w is also an os.File.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	if !f.useTempFile {
+		if exists, err := f.Exists(); err != nil {
+			return 0, err
+		} else if !exists {
+			return 0, fmt.Errorf("failed to read. File does not exist at %s", f)
+		}
+	}
+	useFile, err := f.getInternalFile()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := io.Copy(w, useFile)
+	f.cursorPos += n
+	return n, err
+}
+
+// ReadFrom implements the io.ReaderFrom interface so io.Copy writes directly to the underlying file, letting the kernel
+// copy the data, where supported, when r is also an os.File.  As with Write, the data is committed to the file on
+// Close.
+func (f *File) ReadFrom(r io.Reader) (int64, error) {
+	f.useTempFile = true
+
+	useFile, err := f.getInternalFile()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := io.Copy(useFile, r)
+	f.cursorPos += n
+	return n, err
+}
+
 // Location returns the underlying os.Location.
 func (f *File) Location() vfs.Location {
 	return &Location{
@@ -187,19 +314,26 @@ func (f *File) Location() vfs.Location {
 }
 
 // MoveToFile move a file. It accepts a target vfs.File and returns an error, if any.
-func (f *File) MoveToFile(file vfs.File) error {
+func (f *File) MoveToFile(file vfs.File, opts ...vfs.CopyOption) error {
 	// handle native os move/rename
 	if file.Location().FileSystem().Scheme() == Scheme {
+		if err := ensureDir(file.Location()); err != nil {
+			return err
+		}
 		err := os.Rename(f.Path(), file.Path())
 		if err != nil {
 			return err
 		}
 	} else {
 		// do copy/delete move for non-native os moves
-		_, err := f.copyWithName(file.Name(), file.Location())
+		options := vfs.NewCopyOptions(opts...)
+		newFile, err := f.copyWithName(file.Name(), file.Location(), options)
 		if err != nil {
 			return err
 		}
+		if err := f.verifyMove(newFile, options); err != nil {
+			return err
+		}
 
 		err = f.Delete()
 		if err != nil {
@@ -210,22 +344,36 @@ func (f *File) MoveToFile(file vfs.File) error {
 }
 
 // MoveToLocation moves a file to a new Location. It accepts a target vfs.Location and returns a vfs.File and an error, if any.
-func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+// If a file of the same name already exists at the location, the ConflictPolicy in Options determines what happens
+// (overwriting it by default).  A move skipped under vfs.ConflictSkip leaves the file in place.
+func (f *File) MoveToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	options := vfs.NewCopyOptions(opts...)
+	name, skip, err := f.resolveConflict(location, options)
+	if err != nil {
+		return nil, err
+	}
+	if skip {
+		return location.NewFile(name)
+	}
+
 	// handle native os move/rename
 	if location.FileSystem().Scheme() == Scheme {
 		if err := ensureDir(location); err != nil {
 			return nil, err
 		}
-		err := os.Rename(f.Path(), path.Join(location.Path(), f.Name()))
+		err := os.Rename(f.Path(), path.Join(location.Path(), name))
 		if err != nil {
 			return nil, err
 		}
 	} else {
 		// do copy/delete move for non-native os moves
-		_, err := f.copyWithName(f.Name(), location)
+		newFile, err := f.copyWithName(name, location, options)
 		if err != nil {
 			return f, err
 		}
+		if err := f.verifyMove(newFile, options); err != nil {
+			return nil, err
+		}
 
 		delErr := f.Delete()
 		if delErr != nil {
@@ -233,18 +381,56 @@ func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
 		}
 	}
 	//return vfs.File for newly moved file
-	return location.NewFile(f.Name())
+	return location.NewFile(name)
+}
+
+// verifyMove checks that a move's copy to another scheme was written in full before the source is deleted, unless
+// DisableMoveVerification is set in Options or the move's options include vfs.WithoutMoveVerification.
+func (f *File) verifyMove(target vfs.File, options vfs.CopyOptions) error {
+	if options.SkipMoveVerification || getOptions(f.filesystem.options).DisableMoveVerification {
+		return nil
+	}
+	return utils.VerifyCopy(f, target)
 }
 
 // CopyToFile copies the file to a new File.  It accepts a vfs.File and returns an error, if any.
-func (f *File) CopyToFile(file vfs.File) error {
-	_, err := f.copyWithName(file.Name(), file.Location())
+func (f *File) CopyToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	_, err := f.copyWithName(file.Name(), file.Location(), vfs.NewCopyOptions(opts...))
 	return err
 }
 
 // CopyToLocation copies existing File to new Location with the same name.  It accepts a vfs.Location and returns a vfs.File and error, if any.
-func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
-	return f.copyWithName(f.Name(), location)
+// If a file of the same name already exists at the location, the ConflictPolicy in Options determines what happens
+// (overwriting it by default).
+func (f *File) CopyToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	options := vfs.NewCopyOptions(opts...)
+	name, skip, err := f.resolveConflict(location, options)
+	if err != nil {
+		return nil, err
+	}
+	if skip {
+		return location.NewFile(name)
+	}
+	return f.copyWithName(name, location, options)
+}
+
+// resolveConflict returns the name the file should be copied or moved to at location, and whether to skip it, according
+// to the ConflictPolicy in options, or else in Options.
+func (f *File) resolveConflict(location vfs.Location, options vfs.CopyOptions) (string, bool, error) {
+	policy := options.ConflictPolicyOr(getOptions(f.filesystem.options).ConflictPolicy)
+	if policy == vfs.ConflictOverwrite {
+		return f.Name(), false, nil
+	}
+
+	target, err := location.FileSystem().NewFile(location.Volume(), path.Join(location.Path(), f.Name()))
+	if err != nil {
+		return "", false, err
+	}
+	resolved, skip, err := utils.ResolveConflict(target, policy)
+	if err != nil {
+		return "", false, err
+	}
+	return resolved.Name(), skip, nil
 }
 
 // URI returns the File's URI as a string.
@@ -277,13 +463,30 @@ func (f *File) Touch() error {
 	return os.Chtimes(f.Path(), now, now)
 }
 
-func (f *File) copyWithName(name string, location vfs.Location) (vfs.File, error) {
+func (f *File) copyWithName(name string, location vfs.Location, options vfs.CopyOptions) (vfs.File, error) {
+	if err := options.Context.Err(); err != nil {
+		return nil, err
+	}
 	newFile, err := location.FileSystem().NewFile(location.Volume(), path.Join(location.Path(), name))
 	if err != nil {
 		return nil, err
 	}
 
-	if err := utils.TouchCopy(newFile, f); err != nil {
+	if getOptions(f.filesystem.options).NoFollowSymlinks && location.FileSystem().Scheme() == Scheme {
+		if isLink, err := f.IsSymlink(); err != nil {
+			return nil, err
+		} else if isLink {
+			return newFile, f.copySymlink(newFile.Path())
+		}
+	}
+
+	if method := getOptions(f.filesystem.options).CopyMethod; method != CopyContents && location.FileSystem().Scheme() == Scheme {
+		if f.fastCopy(newFile.Path(), method) {
+			return newFile, f.finishCopy(newFile, options)
+		}
+	}
+
+	if err := utils.TouchCopyWithOptions(newFile, f, options); err != nil {
 		return nil, err
 	}
 	err = f.Close()
@@ -295,9 +498,53 @@ func (f *File) copyWithName(name string, location vfs.Location) (vfs.File, error
 	if err != nil {
 		return nil, err
 	}
+
+	if err := f.finishCopy(newFile, options); err != nil {
+		return nil, err
+	}
 	return newFile, nil
 }
 
+// finishCopy preserves the file's attributes on target, a copy of it, and verifies its checksum if options ask to.
+func (f *File) finishCopy(target vfs.File, options vfs.CopyOptions) error {
+	if err := f.preserveAttributes(target, options.PreserveMetadata); err != nil {
+		return err
+	}
+	if options.VerifyChecksum {
+		return utils.VerifyChecksum(f, target)
+	}
+	return nil
+}
+
+// preserveAttributes applies the file's mode and/or times to an os copy of it, as set in the file system's Options, or
+// both if all is set.
+func (f *File) preserveAttributes(target vfs.File, all bool) error {
+	opts := getOptions(f.filesystem.options)
+	if all {
+		opts.PreserveMode, opts.PreserveTimes = true, true
+	}
+	if !opts.PreserveMode && !opts.PreserveTimes || target.Location().FileSystem().Scheme() != Scheme {
+		return nil
+	}
+	targetPath := target.Path()
+
+	stats, err := os.Stat(f.Path())
+	if err != nil {
+		return err
+	}
+	if opts.PreserveMode {
+		if err := os.Chmod(targetPath, stats.Mode()); err != nil {
+			return err
+		}
+	}
+	if opts.PreserveTimes {
+		if err := os.Chtimes(targetPath, stats.ModTime(), stats.ModTime()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (f *File) openFile() (*os.File, error) {
 	if f.file != nil {
 		return f.file, nil
@@ -341,10 +588,10 @@ func ensureDir(location vfs.Location) error {
 	return nil
 }
 
-// If cursor is not (0,0) will copy original file to a temp file,
-//opening its file descriptor to the current cursor position.
-//If cursor is (0,0), just begin writing to new temp file.
-//No need to copy original first.
+// getInternalFile returns the file to read and seek in: the original file, or, once the file has been written to, the
+// temp file holding the writes.
+//
+// todo: editing in place logic/appending logic (see issue #42)
 func (f *File) getInternalFile() (*os.File, error) {
 	// this is the use case of vfs.file
 	if f.useTempFile == false {
@@ -366,7 +613,7 @@ func (f *File) getInternalFile() (*os.File, error) {
 	}
 	// this is the use case of vfs.tempFile
 	if f.tempFile == nil {
-		localTempFile, err := f.copyToLocalTempReader()
+		localTempFile, err := f.newTempFile()
 		if err != nil {
 			return nil, err
 		}
@@ -376,29 +623,35 @@ func (f *File) getInternalFile() (*os.File, error) {
 	return f.tempFile, nil
 }
 
-func (f *File) copyToLocalTempReader() (*os.File, error) {
-	tmpFile, err := ioutil.TempFile("", fmt.Sprintf("%s.%d", f.Name(), time.Now().UnixNano()))
-	if err != nil {
-		return nil, err
-	}
-
+// newTempFile creates the temp file that writes go to until Close.  It's created alongside the file, rather than in
+// os.TempDir(), so that it can be atomically renamed into place.
+func (f *File) newTempFile() (*os.File, error) {
 	openFunc := openOSFile
 	if f.fileOpener != nil {
 		openFunc = f.fileOpener
 	}
 
-	if _, err = openFunc(f.Path()); err != nil {
-		return nil, err
+	tempPath := filepath.Join(filepath.Dir(f.Path()), fmt.Sprintf(".%s.%d.tmp", f.Name(), time.Now().UnixNano()))
+	return openFunc(tempPath)
+}
+
+// commitTempFile closes the temp file and renames it to the file's path, keeping the mode of any file it replaces.
+// On failure the temp file is removed, leaving any existing file untouched.
+func (f *File) commitTempFile() error {
+	tempFile := f.tempFile
+	f.tempFile = nil
+
+	err := tempFile.Close()
+	if err == nil {
+		if stats, statErr := os.Stat(f.Path()); statErr == nil {
+			err = os.Chmod(tempFile.Name(), stats.Mode().Perm())
+		}
 	}
-	// todo: editing in place logic/appending logic (see issue #42)
-	//if _, err := io.Copy(tmpFile, f.file); err != nil {
-	//	return nil, err
-	//}
-	//
-	//// Return cursor to the beginning of the new temp file
-	//if _, err := tmpFile.Seek(f.cursorPos, 0); err != nil {
-	//	return nil, err
-	//}
-
-	return tmpFile, nil
+	if err == nil {
+		err = os.Rename(tempFile.Name(), f.Path())
+	}
+	if err != nil {
+		_ = os.Remove(tempFile.Name())
+	}
+	return err
 }
@@ -13,7 +13,9 @@ const Scheme = "file"
 const name = "os"
 
 // FileSystem implements vfs.Filesystem for the OS file system.
-type FileSystem struct{}
+type FileSystem struct {
+	options vfs.Options
+}
 
 // Retry will return a retriever provided via options, or a no-op if none is provided.
 func (fs *FileSystem) Retry() vfs.Retry {
@@ -52,6 +54,20 @@ func (fs *FileSystem) Scheme() string {
 	return Scheme
 }
 
+// WithOptions sets options for the file system and returns the file system (chainable)
+func (fs *FileSystem) WithOptions(opts vfs.Options) *FileSystem {
+	// only set options if vfs.Options is os.Options
+	if opts, ok := opts.(Options); ok {
+		fs.options = opts
+	}
+	return fs
+}
+
+// NewFileSystem initializer for the os FileSystem struct.
+func NewFileSystem() *FileSystem {
+	return &FileSystem{}
+}
+
 func init() {
 	backend.Register(Scheme, &FileSystem{})
 }
@@ -72,6 +72,15 @@ func (o *osFileSystemTest) TestLocation() {
 	o.Equal("file", fs.Scheme())
 }
 
+func (o *osFileSystemTest) TestWithOptions() {
+	fs := NewFileSystem().WithOptions(Options{PreserveMode: true})
+	o.Equal(Options{PreserveMode: true}, fs.options)
+
+	// options of other types are ignored
+	fs.WithOptions("not os options")
+	o.Equal(Options{PreserveMode: true}, fs.options)
+}
+
 func TestOSFileSystemn(t *testing.T) {
 	suite.Run(t, new(osFileSystemTest))
 }
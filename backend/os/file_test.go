@@ -1,12 +1,16 @@
 package os
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -182,6 +186,94 @@ func (s *osFileTest) TestSeek() {
 	s.Error(err)
 }
 
+func (s *osFileTest) TestReadRange() {
+	data, err := s.testFile.(*File).ReadRange(6, 3)
+	s.NoError(err)
+	s.Equal("wor", string(data))
+
+	// short read at the end of the file
+	data, err = s.testFile.(*File).ReadRange(6, 100)
+	s.NoError(err)
+	s.Equal("world", string(data))
+
+	_, err = s.testFile.(*File).ReadRange(100, 1)
+	s.Equal(io.EOF, err)
+
+	_, err = s.testFile.(*File).ReadRange(-1, 1)
+	s.EqualError(err, "non-negative offset and positive length are required")
+}
+
+func (s *osFileTest) TestTruncateAndWriteAt() {
+	file, err := s.tmploc.NewFile("test_files/records.txt")
+	s.NoError(err)
+	_, err = file.Write([]byte("aaaa|bbbb|cccc|"))
+	s.NoError(err)
+	s.NoError(file.Close())
+
+	osFile := file.(*File)
+	n, err := osFile.WriteAt([]byte("BBBB"), 5)
+	s.NoError(err)
+	s.Equal(4, n)
+	s.NoError(osFile.Truncate(10))
+
+	data, err := ioutil.ReadFile(osFile.Path())
+	s.NoError(err)
+	s.Equal("aaaa|BBBB|", string(data))
+
+	// extending pads with zeros
+	s.NoError(osFile.Truncate(12))
+	data, err = ioutil.ReadFile(osFile.Path())
+	s.NoError(err)
+	s.Equal("aaaa|BBBB|\x00\x00", string(data))
+
+	missing, err := s.tmploc.NewFile("test_files/missing.txt")
+	s.NoError(err)
+	_, err = missing.(*File).WriteAt([]byte("x"), 0)
+	s.Error(err)
+	s.Error(missing.(*File).Truncate(0))
+
+	// in-place changes would be lost when pending writes replace the file on Close
+	_, err = osFile.Write([]byte("replaced"))
+	s.NoError(err)
+	_, err = osFile.WriteAt([]byte("x"), 0)
+	s.EqualError(err, "unable to write "+osFile.String()+" in place while it has writes pending Close")
+	s.EqualError(osFile.Truncate(0), "unable to truncate "+osFile.String()+" in place while it has writes pending Close")
+	s.NoError(osFile.Close())
+	s.NoError(osFile.Truncate(4))
+	data, err = ioutil.ReadFile(osFile.Path())
+	s.NoError(err)
+	s.Equal("repl", string(data))
+}
+
+func (s *osFileTest) TestWriteToAndReadFrom() {
+	src, ok := s.testFile.(io.WriterTo)
+	s.True(ok, "os.File should implement io.WriterTo")
+	buf := &bytes.Buffer{}
+	n, err := src.WriteTo(buf)
+	s.NoError(err)
+	s.Equal(int64(11), n)
+	s.Equal("hello world", buf.String())
+	s.NoError(s.testFile.Close())
+
+	// io.Copy between os.Files uses ReadFrom/WriteTo
+	target, err := s.tmploc.NewFile("test_files/readFrom.txt")
+	s.NoError(err)
+	n, err = io.Copy(target, s.testFile)
+	s.NoError(err)
+	s.Equal(int64(11), n)
+	s.NoError(target.Close())
+	s.NoError(s.testFile.Close())
+	contents, err := ioutil.ReadFile(target.Path())
+	s.NoError(err)
+	s.Equal("hello world", string(contents))
+	s.NoError(target.Delete())
+
+	missing, err := s.tmploc.NewFile("test_files/missing.txt")
+	s.NoError(err)
+	_, err = missing.(*File).WriteTo(buf)
+	s.Error(err, "file doesn't exist")
+}
+
 func (s *osFileTest) TestCopyToLocation() {
 	expectedText := "hello world"
 	otherFs := new(mocks.FileSystem)
@@ -321,6 +413,8 @@ func (s *osFileTest) TestMoveToLocation() {
 	fsMockFile := new(mocks.File)
 	fsMockFile.On("Write", mock.Anything).Return(10, nil)
 	fsMockFile.On("Close").Return(nil)
+	fsMockFile.On("Size").Return(uint64(len(expectedText)), nil)
+	mockContents(fsMockFile, expectedText)
 	mockfs.On("NewFile", mock.Anything, mock.Anything).Return(fsMockFile, nil)
 	mockLocation.On("FileSystem").Return(mockfs)
 	mockLocation.On("Volume").Return("")
@@ -393,6 +487,8 @@ func (s *osFileTest) TestMoveToFile() {
 	fsMockFile := new(mocks.File)
 	fsMockFile.On("Write", mock.Anything).Return(13, nil)
 	fsMockFile.On("Close").Return(nil)
+	fsMockFile.On("Size").Return(uint64(len(text)), nil)
+	mockContents(fsMockFile, text)
 	mockfs.On("NewFile", mock.Anything, mock.Anything).Return(fsMockFile, nil)
 	mockLocation.On("FileSystem").Return(mockfs)
 	mockLocation.On("Volume").Return("")
@@ -406,6 +502,78 @@ func (s *osFileTest) TestMoveToFile() {
 	s.NoError(file2.MoveToFile(mockFile))
 }
 
+func (s *osFileTest) TestMoveToFile_NewDirectory() {
+	src, err := s.tmploc.NewFile("test_files/moving.txt")
+	s.NoError(err)
+	_, err = src.Write([]byte("some text"))
+	s.NoError(err)
+	s.NoError(src.Close())
+
+	target, err := s.tmploc.NewFile("test_files/new/dir/moved.txt")
+	s.NoError(err)
+	s.NoError(src.MoveToFile(target))
+	exists, err := target.Exists()
+	s.NoError(err)
+	s.True(exists)
+	s.NoError(os.RemoveAll(path.Join(s.tmploc.Path(), "test_files/new")))
+}
+
+func (s *osFileTest) TestMoveToFile_VerificationFailed() {
+	src, err := s.tmploc.NewFile("test_files/verify.txt")
+	s.NoError(err)
+	_, err = src.Write([]byte("some text"))
+	s.NoError(err)
+	s.NoError(src.Close())
+
+	// the target comes up short
+	mockfs := new(mocks.FileSystem)
+	mockfs.On("Scheme").Return("mock")
+	mockLocation := new(mocks.Location)
+	mockLocation.On("FileSystem").Return(mockfs)
+	mockLocation.On("Volume").Return("")
+	mockLocation.On("Path").Return("/some/path/to/")
+	targetFile := new(mocks.File)
+	targetFile.On("Write", mock.Anything).Return(9, nil)
+	targetFile.On("Close").Return(nil)
+	targetFile.On("Size").Return(uint64(4), nil)
+	targetFile.On("String").Return("mock:///some/path/to/verify.txt")
+	mockfs.On("NewFile", mock.Anything, mock.Anything).Return(targetFile, nil)
+	mockFile := new(mocks.File)
+	mockFile.On("Location").Return(mockLocation)
+	mockFile.On("Name").Return("verify.txt")
+
+	err = src.MoveToFile(mockFile)
+	s.Error(err)
+	s.Contains(err.Error(), "failed verification")
+	s.IsType(&utils.CopyVerificationError{}, err)
+	exists, err := src.Exists()
+	s.NoError(err)
+	s.True(exists, "source isn't deleted")
+
+	moved, err := src.MoveToLocation(mockLocation)
+	s.IsType(&utils.CopyVerificationError{}, err)
+	s.Nil(moved)
+	exists, err = src.Exists()
+	s.NoError(err)
+	s.True(exists, "source isn't deleted")
+
+	// verification skipped for the move
+	s.NoError(src.MoveToFile(mockFile, vfs.WithoutMoveVerification()))
+	exists, err = src.Exists()
+	s.NoError(err)
+	s.False(exists)
+
+	// verification disabled
+	_, err = src.Write([]byte("some text"))
+	s.NoError(err)
+	s.NoError(src.Close())
+	src.(*File).filesystem = NewFileSystem().WithOptions(Options{DisableMoveVerification: true})
+	s.NoError(src.MoveToFile(mockFile))
+	exists, err = src.Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
 func (s *osFileTest) TestWrite() {
 	expectedText := "new file"
 	data := make([]byte, len(expectedText))
@@ -553,14 +721,166 @@ func (s *osFileTest) TestCursorErrs() {
 	_, err = noFile.Seek(-10, 2)
 	s.Error(err)
 	s.NoError(noFile.Close())
+	exists, err := noFile.Exists()
+	s.NoError(err)
+	s.False(exists, "a failed seek doesn't create the file")
 
 	noFile.(*File).fileOpener = nil
 	noFile.(*File).useTempFile = false
 	b, err := noFile.Write([]byte("blah"))
 	s.NoError(err)
 	s.Equal(4, b)
-	noFile.(*File).fileOpener = func(filePath string) (*os.File, error) { return nil, errors.New("Bad Opener") }
+	// rename fails when a non-empty directory is in the way
+	s.NoError(os.MkdirAll(path.Join(noFile.Path(), "dir"), 0777))
 	s.Error(noFile.Close())
+	s.NoError(os.RemoveAll(noFile.Path()))
+}
+
+func (s *osFileTest) TestAtomicWrite() {
+	file, err := s.tmploc.NewFile("test_files/atomic.txt")
+	s.NoError(err)
+	_, err = file.Write([]byte("original"))
+	s.NoError(err)
+	s.NoError(file.Close())
+	s.NoError(os.Chmod(file.Path(), 0600))
+
+	_, err = file.Write([]byte("partial"))
+	s.NoError(err)
+
+	// readers see the original until Close
+	data, err := ioutil.ReadFile(file.Path())
+	s.NoError(err)
+	s.Equal("original", string(data))
+	entries, err := ioutil.ReadDir(path.Dir(file.Path()))
+	s.NoError(err)
+	tempFiles := 0
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".atomic.txt.") {
+			tempFiles++
+		}
+	}
+	s.Equal(1, tempFiles, "writes go to a temp file in the same directory")
+
+	s.NoError(file.Close())
+	data, err = ioutil.ReadFile(file.Path())
+	s.NoError(err)
+	s.Equal("partial", string(data))
+	stats, err := os.Stat(file.Path())
+	s.NoError(err)
+	s.Equal(os.FileMode(0600), stats.Mode().Perm(), "mode of the replaced file is kept")
+
+	entries, err = ioutil.ReadDir(path.Dir(file.Path()))
+	s.NoError(err)
+	for _, entry := range entries {
+		s.False(strings.HasPrefix(entry.Name(), ".atomic.txt."), "temp file was renamed")
+	}
+
+	// a new file doesn't exist until Close
+	newFile, err := s.tmploc.NewFile("test_files/atomicNew.txt")
+	s.NoError(err)
+	_, err = newFile.Write([]byte("new"))
+	s.NoError(err)
+	exists, err := newFile.Exists()
+	s.NoError(err)
+	s.False(exists)
+	s.NoError(newFile.Close())
+	exists, err = newFile.Exists()
+	s.NoError(err)
+	s.True(exists)
+	s.NoError(file.Delete())
+	s.NoError(newFile.Delete())
+}
+
+func (s *osFileTest) TestChmod() {
+	file, err := s.tmploc.NewFile("test_files/chmod.sh")
+	s.NoError(err)
+	s.NoError(file.Touch())
+
+	s.NoError(file.(*File).Chmod(0755))
+	mode, err := file.(*File).FileMode()
+	s.NoError(err)
+	s.Equal(os.FileMode(0755), mode.Perm())
+
+	// chown to the current owner (-1 leaves ids unchanged)
+	s.NoError(file.(*File).Chown(-1, -1))
+	s.NoError(file.(*File).Chown(os.Getuid(), os.Getgid()))
+	s.NoError(file.Delete())
+
+	_, err = file.(*File).FileMode()
+	s.Error(err, "file doesn't exist")
+	s.Error(file.(*File).Chmod(0755), "file doesn't exist")
+}
+
+func (s *osFileTest) TestCopyPreserveAttributes() {
+	src, err := s.tmploc.NewFile("test_files/preserve.sh")
+	s.NoError(err)
+	_, err = src.Write([]byte("#!/bin/sh"))
+	s.NoError(err)
+	s.NoError(src.Close())
+	s.NoError(os.Chmod(src.Path(), 0750))
+	mtime := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	s.NoError(os.Chtimes(src.Path(), mtime, mtime))
+
+	// not preserved by default
+	copied, err := s.tmploc.NewFile("test_files/preserved/preserve.sh")
+	s.NoError(err)
+	s.NoError(src.CopyToFile(copied))
+	stats, err := os.Stat(copied.Path())
+	s.NoError(err)
+	s.NotEqual(mtime, stats.ModTime())
+
+	// preserved
+	src.(*File).filesystem = NewFileSystem().WithOptions(Options{PreserveMode: true, PreserveTimes: true})
+	s.NoError(src.CopyToFile(copied))
+	stats, err = os.Stat(copied.Path())
+	s.NoError(err)
+	s.Equal(os.FileMode(0750), stats.Mode().Perm())
+	s.True(mtime.Equal(stats.ModTime()))
+
+	s.NoError(os.RemoveAll(path.Dir(copied.Path())))
+	s.NoError(src.Delete())
+}
+
+func (s *osFileTest) TestCopyToLocation_ConflictPolicy() {
+	location, err := s.tmploc.NewLocation("test_files/conflict/")
+	s.NoError(err)
+	existing, err := location.NewFile("test.txt")
+	s.NoError(err)
+	_, err = existing.Write([]byte("existing"))
+	s.NoError(err)
+	s.NoError(existing.Close())
+
+	src := s.testFile.(*File)
+	defer func() { src.filesystem = s.fileSystem }()
+
+	src.filesystem = NewFileSystem().WithOptions(Options{ConflictPolicy: vfs.ConflictSkip})
+	copied, err := src.CopyToLocation(location)
+	s.NoError(err)
+	s.Equal(existing.Path(), copied.Path())
+	contents, err := ioutil.ReadFile(existing.Path())
+	s.NoError(err)
+	s.Equal("existing", string(contents), "existing file should be left as-is")
+
+	// a skipped move leaves the source in place
+	_, err = src.MoveToLocation(location)
+	s.NoError(err)
+	exists, err := src.Exists()
+	s.NoError(err)
+	s.True(exists)
+
+	src.filesystem = NewFileSystem().WithOptions(Options{ConflictPolicy: vfs.ConflictError})
+	_, err = src.CopyToLocation(location)
+	s.Equal(vfs.ErrFileExists, err)
+
+	src.filesystem = NewFileSystem().WithOptions(Options{ConflictPolicy: vfs.ConflictRenameWithSuffix})
+	copied, err = src.CopyToLocation(location)
+	s.NoError(err)
+	s.Equal("test-1.txt", copied.Name())
+	contents, err = ioutil.ReadFile(copied.Path())
+	s.NoError(err)
+	s.Equal("hello world", string(contents))
+
+	s.NoError(os.RemoveAll(location.Path()))
 }
 
 func (s *osFileTest) TestLastModified() {
@@ -686,6 +1006,14 @@ func createDir(baseLoc vfs.Location, dirname string) {
 	}
 }
 
+// mockContents sets up file to be read (once) as contents, ie: when a move's copy is verified.
+func mockContents(file *mocks.File, contents string) {
+	file.On("Read", mock.Anything).Run(func(args mock.Arguments) {
+		copy(args.Get(0).([]byte), contents)
+	}).Return(len(contents), nil).Once()
+	file.On("Read", mock.Anything).Return(0, io.EOF)
+}
+
 func writeStringFile(baseLoc vfs.Location, filename, data string) {
 	file := path.Join(baseLoc.Path(), filename)
 	f, err := os.Create(file)
@@ -84,12 +84,73 @@ func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
 	})
 }
 
+// ListInfo returns a FileInfo for each file that List returns, which is useful for passing to code written against
+// os.FileInfo.
+func (l *Location) ListInfo() ([]*vfs.FileInfo, error) {
+	entries, err := l.fileInfoList(func(name string) bool { return true })
+	if err != nil {
+		return []*vfs.FileInfo{}, err
+	}
+
+	infos := make([]*vfs.FileInfo, 0, len(entries))
+	for _, info := range entries {
+		infos = append(infos, vfs.NewFileInfo(info.Name(), info.Size(), info.ModTime(), info.Mode()))
+	}
+	return infos, nil
+}
+
+// ListWithPrefixes returns the files found directly at the location, as List does, along with a Location for each
+// sub-directory, which is useful for walking or displaying the location as a directory tree.
+func (l *Location) ListWithPrefixes() ([]vfs.File, []vfs.Location, error) {
+	files := []vfs.File{}
+	locations := []vfs.Location{}
+	exists, err := l.Exists()
+	if err != nil || !exists {
+		return files, locations, err
+	}
+
+	entries, err := ioutil.ReadDir(l.Path())
+	if err != nil {
+		return files, locations, err
+	}
+	opts := l.options()
+	for _, info := range entries {
+		switch {
+		case info.IsDir():
+			location, err := l.NewLocation(utils.EnsureTrailingSlash(info.Name()))
+			if err != nil {
+				return []vfs.File{}, []vfs.Location{}, err
+			}
+			locations = append(locations, location)
+		case includeInList(l.Path(), info, opts):
+			file, err := l.NewFile(info.Name())
+			if err != nil {
+				return []vfs.File{}, []vfs.Location{}, err
+			}
+			files = append(files, file)
+		}
+	}
+	return files, locations, nil
+}
+
 func (l *Location) fileList(testEval fileTest) ([]string, error) {
 	files := make([]string, 0)
-	exists, err := l.Exists()
+	entries, err := l.fileInfoList(testEval)
 	if err != nil {
 		return files, err
 	}
+	for _, info := range entries {
+		files = append(files, info.Name())
+	}
+	return files, nil
+}
+
+func (l *Location) fileInfoList(testEval fileTest) ([]os.FileInfo, error) {
+	infos := make([]os.FileInfo, 0)
+	exists, err := l.Exists()
+	if err != nil {
+		return infos, err
+	}
 
 	// Function should return an empty slice if the directory doesn't exist. This is to match behavior of remote
 	// systems. If the user cares about the distinction between directories that are empty, vs non-existent then
@@ -97,17 +158,18 @@ func (l *Location) fileList(testEval fileTest) ([]string, error) {
 	if exists {
 		entries, err := ioutil.ReadDir(l.Path())
 		if err != nil {
-			return files, err
+			return infos, err
 		}
 
+		opts := l.options()
 		for _, info := range entries {
-			if !info.IsDir() && testEval(info.Name()) {
-				files = append(files, info.Name())
+			if includeInList(l.Path(), info, opts) && testEval(info.Name()) {
+				infos = append(infos, info)
 			}
 		}
 	}
 
-	return files, nil
+	return infos, nil
 }
 
 // Volume returns the volume, if any, of the location. Given "C:\foo\bar" it returns "C:" on Windows. On other platforms it returns "".
@@ -182,6 +244,14 @@ func (l *Location) ChangeDir(relativePath string) error {
 	return nil
 }
 
+// options returns the os.Options of the location's file system.
+func (l *Location) options() Options {
+	if fs, ok := l.fileSystem.(*FileSystem); ok {
+		return getOptions(fs.options)
+	}
+	return Options{}
+}
+
 // FileSystem returns a vfs.FileSystem interface of the location's underlying file system.
 func (l *Location) FileSystem() vfs.FileSystem {
 	return l.fileSystem
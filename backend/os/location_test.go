@@ -53,6 +53,37 @@ func (s *osLocationTest) TestList() {
 	s.Equal(expected, actual)
 }
 
+func (s *osLocationTest) TestListInfo() {
+	infos, err := s.testFile.Location().(*Location).ListInfo()
+	s.NoError(err)
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name())
+		s.False(info.IsDir())
+	}
+	s.Equal([]string{"empty.txt", "prefix-file.txt", "test.txt"}, names)
+	s.Equal(int64(0), infos[0].Size())
+}
+
+func (s *osLocationTest) TestListWithPrefixes() {
+	files, locations, err := s.testFile.Location().(*Location).ListWithPrefixes()
+	s.NoError(err)
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	s.Equal([]string{"empty.txt", "prefix-file.txt", "test.txt"}, names)
+	s.Len(locations, 1)
+	s.Equal(s.testFile.Location().Path()+"subdir/", locations[0].Path())
+
+	missing, err := s.testFile.Location().NewLocation("not/a/directory/")
+	s.NoError(err)
+	files, locations, err = missing.(*Location).ListWithPrefixes()
+	s.NoError(err)
+	s.Empty(files)
+	s.Empty(locations)
+}
+
 func (s *osLocationTest) TestList_NonExistentDirectory() {
 	location, err := s.testFile.Location().NewLocation("not/a/directory/")
 	s.Nil(err, "error isn't expected")
@@ -158,6 +189,7 @@ func (s *osLocationTest) TestDeleteFile() {
 
 	_, err = file.Write([]byte(expectedText))
 	s.NoError(err, "Shouldn't fail to write text to file.")
+	s.NoError(file.Close(), "Shouldn't fail to close file.")
 
 	exists, err := file.Exists()
 	s.NoError(err, "Exists shouldn't throw error.")
@@ -0,0 +1,56 @@
+package os
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// lockSuffix is appended to a file's path for the path of its lock file.
+const lockSuffix = ".lock"
+
+// lease is a vfs.Lease on a file, held by an flock on its open lock file.
+type lease struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// Lock implements vfs.Locker, taking a lease on the file with an exclusive flock of the lock file <path>.lock beside
+// it, or returning vfs.ErrLocked if another holder, in this process or another, has it locked.  The lock is held until
+// it's released or the process exits, so ttl is ignored and refreshing the lease has no effect.  Locking is only
+// supported on linux, darwin and freebsd.
+func (f *File) Lock(ttl time.Duration) (vfs.Lease, error) {
+	file, err := lockFile(f.Path() + lockSuffix)
+	if err != nil {
+		return nil, err
+	}
+	return &lease{file: file}, nil
+}
+
+// Refresh implements vfs.Lease.  Since flocks don't expire, it only returns vfs.ErrLocked if the lease was released.
+func (l *lease) Refresh(ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return vfs.ErrLocked
+	}
+	return nil
+}
+
+// Release implements vfs.Lease, removing and unlocking the lock file.
+func (l *lease) Release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	// remove the lock file while it's still locked, so it's never removed from under another holder
+	err := os.Remove(l.file.Name())
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	l.file = nil
+	return err
+}
@@ -0,0 +1,13 @@
+// +build !linux,!darwin,!freebsd
+
+package os
+
+import (
+	"errors"
+	"os"
+)
+
+// lockFile isn't supported outside of linux, darwin and freebsd.
+func lockFile(name string) (*os.File, error) {
+	return nil, errors.New("file locking is only supported on linux, darwin and freebsd")
+}
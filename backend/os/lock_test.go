@@ -0,0 +1,57 @@
+// +build linux darwin freebsd
+
+package os
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+type osLockTest struct {
+	suite.Suite
+	dir string
+}
+
+func (s *osLockTest) SetupTest() {
+	dir, err := ioutil.TempDir("", "os_lock_test")
+	s.NoError(err)
+	s.dir = dir
+}
+
+func (s *osLockTest) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *osLockTest) TestLock() {
+	file, err := NewFileSystem().NewFile("", s.dir+"/sub/file.txt")
+	s.NoError(err)
+
+	lease, err := vfs.Lock(file, time.Minute)
+	s.NoError(err)
+	_, err = os.Stat(s.dir + "/sub/file.txt.lock")
+	s.NoError(err, "the lock file is created beside the file")
+
+	_, err = vfs.Lock(file, time.Minute)
+	s.Equal(vfs.ErrLocked, err, "the file can't be locked again until it's released")
+	s.NoError(lease.Refresh(time.Minute))
+
+	s.NoError(lease.Release())
+	_, err = os.Stat(s.dir + "/sub/file.txt.lock")
+	s.True(os.IsNotExist(err), "the lock file is removed on release")
+	s.Equal(vfs.ErrLocked, lease.Refresh(time.Minute), "released leases can't be refreshed")
+	s.NoError(lease.Release(), "releasing again does nothing")
+
+	lease, err = vfs.Lock(file, time.Minute)
+	s.NoError(err, "released files can be locked again")
+	s.NoError(lease.Release())
+}
+
+func TestOSLock(t *testing.T) {
+	suite.Run(t, new(osLockTest))
+}
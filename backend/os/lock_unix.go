@@ -0,0 +1,45 @@
+// +build linux darwin freebsd
+
+package os
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// lockFile opens and exclusively flocks the lock file at name, creating it if needed.  vfs.ErrLocked is returned if
+// it's already locked.
+func lockFile(name string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(name), os.ModeDir|0777); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, err
+	}
+	if err := unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB); err != nil {
+		_ = file.Close()
+		if err == unix.EWOULDBLOCK {
+			return nil, vfs.ErrLocked
+		}
+		return nil, err
+	}
+
+	// the holder may have released the lock, removing the lock file, between it being opened and locked here, which
+	// leaves this lock on a file another Lock won't see
+	opened, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	current, err := os.Stat(name)
+	if err != nil || !os.SameFile(opened, current) {
+		_ = file.Close()
+		return nil, vfs.ErrLocked
+	}
+	return file, nil
+}
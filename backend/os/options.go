@@ -0,0 +1,33 @@
+package os
+
+import (
+	"github.com/c2fo/vfs/v5"
+)
+
+// Options holds os-specific options.
+type Options struct {
+	// PreserveMode keeps a file's permission bits when it's copied to another os file.
+	PreserveMode bool `json:"preserveMode,omitempty"`
+	// PreserveTimes keeps a file's modification (and access) times when it's copied to another os file.
+	PreserveTimes bool `json:"preserveTimes,omitempty"`
+	// NoFollowSymlinks leaves symbolic links unresolved: List skips them, and copying one to another os file recreates
+	// the link instead of copying what it points to.  By default, symlinks to files are listed and copied as regular
+	// files, while symlinks to directories are never listed.
+	NoFollowSymlinks bool `json:"noFollowSymlinks,omitempty"`
+	// CopyMethod, when CopyHardLink or CopyClone, copies to os files on the same filesystem instantly and without using
+	// additional space.  Copies that can't be made that way fall back to copying the contents.
+	CopyMethod CopyMethod `json:"copyMethod,omitempty"`
+	// DisableMoveVerification skips checking that a file moved to another scheme was copied in full before the source
+	// is deleted.
+	DisableMoveVerification bool `json:"disableMoveVerification,omitempty"`
+	// ConflictPolicy determines what CopyToLocation and MoveToLocation do when the target file already exists.
+	ConflictPolicy vfs.ConflictPolicy `json:"conflictPolicy,omitempty"`
+}
+
+// getOptions returns the file system's os.Options, or the zero value if none are set.
+func getOptions(opts vfs.Options) Options {
+	if opts, ok := opts.(Options); ok {
+		return opts
+	}
+	return Options{}
+}
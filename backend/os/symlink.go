@@ -0,0 +1,62 @@
+package os
+
+import (
+	"os"
+	"path"
+)
+
+// IsSymlink returns true if the file is a symbolic link.
+func (f *File) IsSymlink() (bool, error) {
+	stats, err := os.Lstat(f.Path())
+	if err != nil {
+		return false, err
+	}
+
+	return stats.Mode()&os.ModeSymlink != 0, nil
+}
+
+// Readlink returns the target of the symbolic link at the file's path.
+func (f *File) Readlink() (string, error) {
+	return os.Readlink(f.Path())
+}
+
+// Symlink creates the file as a symbolic link to target, creating its directory if necessary.  Target is used as-is,
+// so a relative target is relative to the file's location.
+func (f *File) Symlink(target string) error {
+	if err := os.MkdirAll(path.Dir(f.Path()), os.ModeDir|0777); err != nil {
+		return err
+	}
+
+	return os.Symlink(target, f.Path())
+}
+
+// includeInList returns true if the directory entry should be listed as a file.  Symlinks are resolved, so a symlink to
+// a directory (or a broken symlink) isn't listed, unless NoFollowSymlinks is set, in which case symlinks aren't listed
+// at all.
+func includeInList(dir string, info os.FileInfo, opts Options) bool {
+	if info.Mode()&os.ModeSymlink == 0 {
+		return !info.IsDir()
+	}
+	if opts.NoFollowSymlinks {
+		return false
+	}
+
+	target, err := os.Stat(path.Join(dir, info.Name()))
+	return err == nil && !target.IsDir()
+}
+
+// copySymlink recreates the file's symbolic link at targetPath, replacing any existing file there.
+func (f *File) copySymlink(targetPath string) error {
+	target, err := f.Readlink()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(targetPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(targetPath), os.ModeDir|0777); err != nil {
+		return err
+	}
+	return os.Symlink(target, targetPath)
+}
@@ -0,0 +1,115 @@
+package os
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+type osSymlinkTest struct {
+	suite.Suite
+	dir string
+	loc vfs.Location
+}
+
+func (s *osSymlinkTest) SetupTest() {
+	dir, err := ioutil.TempDir("", "os_symlink_test")
+	s.NoError(err)
+	s.dir = utils.EnsureTrailingSlash(dir)
+
+	// dir/
+	//   file.txt
+	//   file-link.txt -> file.txt
+	//   subdir/
+	//   subdir-link -> subdir
+	//   broken-link -> missing.txt
+	s.NoError(ioutil.WriteFile(path.Join(dir, "file.txt"), []byte("hello"), 0644))
+	s.NoError(os.Mkdir(path.Join(dir, "subdir"), 0755))
+	s.NoError(os.Symlink("file.txt", path.Join(dir, "file-link.txt")))
+	s.NoError(os.Symlink("subdir", path.Join(dir, "subdir-link")))
+	s.NoError(os.Symlink("missing.txt", path.Join(dir, "broken-link")))
+
+	s.loc, err = NewFileSystem().NewLocation("", s.dir)
+	s.NoError(err)
+}
+
+func (s *osSymlinkTest) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *osSymlinkTest) TestSymlinkReadlink() {
+	file, err := s.loc.NewFile("file-link.txt")
+	s.NoError(err)
+	isLink, err := file.(*File).IsSymlink()
+	s.NoError(err)
+	s.True(isLink)
+	target, err := file.(*File).Readlink()
+	s.NoError(err)
+	s.Equal("file.txt", target)
+
+	regular, err := s.loc.NewFile("file.txt")
+	s.NoError(err)
+	isLink, err = regular.(*File).IsSymlink()
+	s.NoError(err)
+	s.False(isLink)
+	_, err = regular.(*File).Readlink()
+	s.Error(err, "not a link")
+
+	newLink, err := s.loc.NewFile("links/new-link.txt")
+	s.NoError(err)
+	s.NoError(newLink.(*File).Symlink("../file.txt"))
+	data, err := ioutil.ReadAll(newLink)
+	s.NoError(err)
+	s.Equal("hello", string(data))
+	s.NoError(newLink.Close())
+	s.Error(newLink.(*File).Symlink("../file.txt"), "link already exists")
+}
+
+func (s *osSymlinkTest) TestList() {
+	files, err := s.loc.List()
+	s.NoError(err)
+	s.ElementsMatch([]string{"file.txt", "file-link.txt"}, files, "links to files are followed, links to dirs are not listed")
+
+	s.loc.FileSystem().(*FileSystem).WithOptions(Options{NoFollowSymlinks: true})
+	files, err = s.loc.List()
+	s.NoError(err)
+	s.Equal([]string{"file.txt"}, files, "links aren't listed")
+}
+
+func (s *osSymlinkTest) TestCopy() {
+	link, err := s.loc.NewFile("file-link.txt")
+	s.NoError(err)
+	target, err := s.loc.NewLocation("copies/")
+	s.NoError(err)
+
+	// link is followed
+	copied, err := link.CopyToLocation(target)
+	s.NoError(err)
+	isLink, err := copied.(*File).IsSymlink()
+	s.NoError(err)
+	s.False(isLink)
+	data, err := ioutil.ReadFile(copied.Path())
+	s.NoError(err)
+	s.Equal("hello", string(data))
+
+	// link is recreated
+	s.loc.FileSystem().(*FileSystem).WithOptions(Options{NoFollowSymlinks: true})
+	copied, err = link.CopyToLocation(target)
+	s.NoError(err)
+	isLink, err = copied.(*File).IsSymlink()
+	s.NoError(err)
+	s.True(isLink, "existing copy is replaced by the link")
+	linkTarget, err := copied.(*File).Readlink()
+	s.NoError(err)
+	s.Equal("file.txt", linkTarget)
+}
+
+func TestOSSymlink(t *testing.T) {
+	suite.Run(t, new(osSymlinkTest))
+}
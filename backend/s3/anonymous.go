@@ -0,0 +1,34 @@
+package s3
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// ErrAnonymousWrite is returned for requests that would write to s3, ie: uploads, deletes and copies, made by a file
+// system with Options.Anonymous set, since they'd be rejected without credentials.
+var ErrAnonymousWrite = errors.New("s3 writes require credentials, but the file system is anonymous (Options.Anonymous)")
+
+// anonymousReads are the operations, other than GET and HEAD requests, an anonymous client may make, since they only
+// read.
+var anonymousReads = map[string]bool{
+	"SelectObjectContent": true,
+}
+
+// addAnonymousHandlers adds a request handler rejecting requests that would write with ErrAnonymousWrite.  It runs
+// when requests are built, so presigning them fails too.
+func addAnonymousHandlers(handlers *request.Handlers) {
+	handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: "vfs.Anonymous",
+		Fn: func(r *request.Request) {
+			switch {
+			case r.Operation.HTTPMethod == http.MethodGet, r.Operation.HTTPMethod == http.MethodHead:
+			case anonymousReads[r.Operation.Name]:
+			default:
+				r.Error = ErrAnonymousWrite
+			}
+		},
+	})
+}
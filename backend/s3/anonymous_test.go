@@ -0,0 +1,66 @@
+package s3
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+type anonymousTestSuite struct {
+	suite.Suite
+	fs *FileSystem
+}
+
+func (ts *anonymousTestSuite) SetupTest() {
+	ts.fs = NewFileSystem().WithOptions(Options{Anonymous: true, Region: "us-east-1"})
+}
+
+func (ts *anonymousTestSuite) client() *s3.S3 {
+	client, err := ts.fs.Client()
+	ts.Require().NoError(err)
+	return client.(*s3.S3)
+}
+
+func (ts *anonymousTestSuite) TestReadsUnsigned() {
+	req, _ := ts.client().GetObjectRequest(new(s3.GetObjectInput).SetBucket("public").SetKey("data.csv"))
+	ts.NoError(req.Sign())
+	ts.Empty(req.HTTPRequest.Header.Get("Authorization"), "anonymous requests aren't signed")
+
+	req, _ = ts.client().ListObjectsV2Request(new(s3.ListObjectsV2Input).SetBucket("public"))
+	ts.NoError(req.Build())
+}
+
+func (ts *anonymousTestSuite) TestWritesRejected() {
+	client := ts.client()
+	req, _ := client.PutObjectRequest(new(s3.PutObjectInput).
+		SetBucket("public").SetKey("data.csv").SetBody(strings.NewReader("hello")))
+	ts.Equal(ErrAnonymousWrite, req.Send())
+	ts.Equal(vfs.ErrorPermission, vfs.ClassifyError(ErrAnonymousWrite))
+
+	_, err := client.DeleteObject(new(s3.DeleteObjectInput).SetBucket("public").SetKey("data.csv"))
+	ts.Equal(ErrAnonymousWrite, err)
+	_, err = client.CopyObject(new(s3.CopyObjectInput).
+		SetBucket("public").SetKey("copy.csv").SetCopySource("public/data.csv"))
+	ts.Equal(ErrAnonymousWrite, err)
+
+	file, err := ts.fs.NewFile("public", "/data.csv")
+	ts.NoError(err)
+	_, err = file.(*File).PresignUploadPart("u1", 1, time.Hour)
+	ts.Equal(ErrAnonymousWrite, err, "writes can't be presigned")
+	_, err = file.(*File).PostPolicy(PostPolicyOptions{})
+	ts.Equal(ErrAnonymousWrite, err)
+}
+
+func (ts *anonymousTestSuite) TestRoleARN() {
+	_, err := getClient(Options{Anonymous: true, RoleARN: "arn:aws:iam::123456789012:role/reader"})
+	ts.EqualError(err, "a role can't be assumed by an anonymous file system")
+}
+
+func TestAnonymous(t *testing.T) {
+	suite.Run(t, new(anonymousTestSuite))
+}
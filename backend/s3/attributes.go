@@ -0,0 +1,71 @@
+package s3
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// Extended attribute names returned by File.ExtendedAttributes.  User-defined object metadata is included with the
+// MetadataAttributePrefix followed by the metadata key.
+const (
+	ETagAttribute           = "ETag"
+	VersionIDAttribute      = "VersionId"
+	StorageClassAttribute   = "StorageClass"
+	ContentTypeAttribute    = "ContentType"
+	MetadataAttributePrefix = "x-amz-meta-"
+)
+
+// ETag returns the object's ETag, without the surrounding quotes s3 returns it with.  For objects uploaded in a single
+// part without SSE-KMS this is the hex MD5 of the contents; otherwise it only identifies the contents.
+func (f *File) ETag() (string, error) {
+	head, err := f.getHeadObject()
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(aws.StringValue(head.ETag), `"`), nil
+}
+
+// ResolveVersionID returns the VersionId of the object: the version the file is pinned to (see WithVersion) or else the
+// latest version's, found with a HEAD request (or the File's cached HEAD result) so it can be recorded and read back
+// with WithVersion later.  VersionID, by contrast, only returns the version the file is pinned to.  "" is returned for
+// objects in buckets which have never had versioning enabled.
+func (f *File) ResolveVersionID() (string, error) {
+	if f.versionID != "" {
+		return f.versionID, nil
+	}
+	head, err := f.getFullHeadObject()
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(head.VersionId), nil
+}
+
+// ExtendedAttributes implements vfs.ExtendedAttributer, returning the object's ETag, VersionId, StorageClass,
+// ContentType and user-defined metadata from a single HEAD request (or the File's cached HEAD result, see Refresh).
+// Files from a listing make the request, since listings only include the ETag and StorageClass.  Attributes the object
+// doesn't have are omitted.
+func (f *File) ExtendedAttributes() (map[string]string, error) {
+	head, err := f.getFullHeadObject()
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := make(map[string]string)
+	set := func(name string, value *string) {
+		if v := aws.StringValue(value); v != "" {
+			attrs[name] = v
+		}
+	}
+	set(ETagAttribute, aws.String(strings.Trim(aws.StringValue(head.ETag), `"`)))
+	set(VersionIDAttribute, head.VersionId)
+	set(StorageClassAttribute, head.StorageClass)
+	set(ContentTypeAttribute, head.ContentType)
+	for key, value := range head.Metadata {
+		set(MetadataAttributePrefix+strings.ToLower(key), value)
+	}
+	if f.versionID != "" {
+		attrs[VersionIDAttribute] = f.versionID
+	}
+	return attrs, nil
+}
@@ -0,0 +1,124 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type attributesTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	file      *File
+}
+
+func (ts *attributesTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	fs := &FileSystem{client: ts.s3apiMock, options: Options{AccessKeyID: "abc"}}
+	file, err := fs.NewFile("bucket", "/audit/report.csv")
+	ts.NoError(err)
+	ts.file = file.(*File)
+}
+
+func (ts *attributesTestSuite) TestAttributes() {
+	ts.s3apiMock.On("HeadObject", mock.MatchedBy(func(input *s3.HeadObjectInput) bool {
+		return input.VersionId == nil
+	})).Return(&s3.HeadObjectOutput{
+		ETag:         aws.String(`"abc123"`),
+		VersionId:    aws.String("v2"),
+		StorageClass: aws.String(s3.StorageClassStandardIa),
+		ContentType:  aws.String("text/csv"),
+		Metadata:     map[string]*string{"Owner": aws.String("finance")},
+	}, nil).Once()
+
+	etag, err := ts.file.ETag()
+	ts.NoError(err)
+	ts.Equal("abc123", etag)
+	versionID, err := ts.file.ResolveVersionID()
+	ts.NoError(err)
+	ts.Equal("v2", versionID)
+
+	var attributer vfs.ExtendedAttributer = ts.file
+	attrs, err := attributer.ExtendedAttributes()
+	ts.NoError(err)
+	ts.Equal(map[string]string{
+		ETagAttribute:                     "abc123",
+		VersionIDAttribute:                "v2",
+		StorageClassAttribute:             s3.StorageClassStandardIa,
+		ContentTypeAttribute:              "text/csv",
+		MetadataAttributePrefix + "owner": "finance",
+	}, attrs)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *attributesTestSuite) TestAttributes_Pinned() {
+	ts.s3apiMock.On("HeadObject", mock.MatchedBy(func(input *s3.HeadObjectInput) bool {
+		return aws.StringValue(input.VersionId) == "v1"
+	})).Return(&s3.HeadObjectOutput{ETag: aws.String(`"old"`)}, nil).Once()
+
+	pinned, err := ts.file.WithVersion("v1")
+	ts.NoError(err)
+	versionID, err := pinned.ResolveVersionID()
+	ts.NoError(err)
+	ts.Equal("v1", versionID)
+
+	attrs, err := pinned.ExtendedAttributes()
+	ts.NoError(err)
+	ts.Equal(map[string]string{ETagAttribute: "old", VersionIDAttribute: "v1"}, attrs)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *attributesTestSuite) TestAttributes_Listed() {
+	ts.s3apiMock.On("ListObjectsV2", mock.AnythingOfType("*s3.ListObjectsV2Input")).Return(&s3.ListObjectsV2Output{
+		Contents: []*s3.Object{
+			{Key: aws.String("audit/report.csv"), Size: aws.Int64(10), ETag: aws.String(`"abc123"`)},
+		},
+		IsTruncated: aws.Bool(false),
+	}, nil).Once()
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{
+		ContentLength: aws.Int64(10),
+		ETag:          aws.String(`"abc123"`),
+		VersionId:     aws.String("v2"),
+		ContentType:   aws.String("text/csv"),
+		Metadata:      map[string]*string{"Owner": aws.String("finance")},
+	}, nil).Once()
+
+	files, err := ts.file.Location().(*Location).ListFiles()
+	ts.NoError(err)
+	ts.Len(files, 1)
+	listed := files[0].(*File)
+
+	// the listing has the ETag, but not the attributes only HEAD returns
+	etag, err := listed.ETag()
+	ts.NoError(err)
+	ts.Equal("abc123", etag)
+	ts.s3apiMock.AssertNotCalled(ts.T(), "HeadObject", mock.Anything)
+
+	attrs, err := listed.ExtendedAttributes()
+	ts.NoError(err)
+	ts.Equal(map[string]string{
+		ETagAttribute:                     "abc123",
+		VersionIDAttribute:                "v2",
+		ContentTypeAttribute:              "text/csv",
+		MetadataAttributePrefix + "owner": "finance",
+	}, attrs)
+
+	// the full HEAD is cached from then on
+	versionID, err := listed.ResolveVersionID()
+	ts.NoError(err)
+	ts.Equal("v2", versionID)
+	info, err := listed.Stat()
+	ts.NoError(err)
+	ts.Equal("text/csv", info.ContentType)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func TestAttributes(t *testing.T) {
+	suite.Run(t, new(attributesTestSuite))
+}
@@ -0,0 +1,319 @@
+package s3
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3control"
+	"github.com/aws/aws-sdk-go/service/s3control/s3controliface"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// FileIterator iterates over files, as a ListIterator does.
+type FileIterator interface {
+	Next() bool
+	File() vfs.File
+	Err() error
+}
+
+// BatchOperation is the operation an S3 Batch Operations job performs on each object in its manifest.
+type BatchOperation struct {
+	operation *s3control.JobOperation
+}
+
+// BatchCopy copies each object to the target location's bucket, with the location's path prepended to its key, ie:
+// s3://bucket/dir/file.txt is copied to s3://target/prefix/dir/file.txt for the target location s3://target/prefix/.
+// The ACL and object lock settings in the target's Options are applied to the copies.
+func BatchCopy(target *Location) BatchOperation {
+	operation := new(s3control.S3CopyObjectOperation).SetTargetResource("arn:aws:s3:::" + target.bucket)
+	if prefix := target.listPrefix(); prefix != "" {
+		operation.SetTargetKeyPrefix(prefix)
+	}
+	if opts, ok := target.fileSystem.options.(Options); ok {
+		if opts.ACL != "" {
+			operation.SetCannedAccessControlList(opts.ACL)
+		}
+		if opts.ObjectLockMode != "" && opts.ObjectLockRetention > 0 {
+			operation.SetObjectLockMode(opts.ObjectLockMode).
+				SetObjectLockRetainUntilDate(time.Now().Add(opts.ObjectLockRetention))
+		}
+		if opts.ObjectLockLegalHold {
+			operation.SetObjectLockLegalHoldStatus(s3control.S3ObjectLockLegalHoldStatusOn)
+		}
+	}
+	return BatchOperation{operation: new(s3control.JobOperation).SetS3PutObjectCopy(operation)}
+}
+
+// BatchTag replaces the tags of each object with tags.
+func BatchTag(tags map[string]string) BatchOperation {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tagSet := make([]*s3control.S3Tag, 0, len(keys))
+	for _, key := range keys {
+		tagSet = append(tagSet, new(s3control.S3Tag).SetKey(key).SetValue(tags[key]))
+	}
+	return BatchOperation{operation: new(s3control.JobOperation).SetS3PutObjectTagging(
+		new(s3control.S3SetObjectTaggingOperation).SetTagSet(tagSet))}
+}
+
+// BatchRestore initiates a temporary restore of each archived object for the given number of days, as File.Restore
+// does.  Tier is one of s3.TierStandard or s3.TierBulk; Batch Operations doesn't support expedited restores.
+func BatchRestore(tier string, days int64) BatchOperation {
+	return BatchOperation{operation: new(s3control.JobOperation).SetS3InitiateRestoreObject(
+		new(s3control.S3InitiateRestoreObjectOperation).SetGlacierJobTier(tier).SetExpirationInDays(days))}
+}
+
+// BatchJobOptions configures an S3 Batch Operations job.
+type BatchJobOptions struct {
+	// AccountID is the id of the AWS account the job is created in.  Required.
+	AccountID string
+	// RoleARN is the IAM role Batch Operations assumes to perform the operation.  Required.
+	RoleARN string
+	// Priority orders the account's jobs; higher priority jobs run first.
+	Priority int64
+	// Description describes the job in the s3 console.
+	Description string
+	// Report, when set, is the location a completion report of every task is written to.
+	Report *Location
+	// ConfirmationRequired holds the job until it's confirmed in the s3 console or with BatchJob.Confirm.
+	ConfirmationRequired bool
+	// InventoryManifest submits the manifest as the manifest.json of an S3 Inventory report (see
+	// ListOptions.InventoryManifest), rather than a manifest written by WriteBatchManifest.
+	InventoryManifest bool
+}
+
+// BatchJob is an S3 Batch Operations job.
+type BatchJob struct {
+	// ID is the job's id.
+	ID         string
+	accountID  string
+	fileSystem *FileSystem
+}
+
+// BatchJobStatus is the progress of an S3 Batch Operations job.
+type BatchJobStatus struct {
+	// Status is the job's status, ie: s3control.JobStatusActive or s3control.JobStatusComplete.
+	Status string
+	// Total is the number of objects in the job's manifest, once it's been read.  Succeeded and Failed are the number
+	// of them the operation has succeeded or failed for so far.
+	Total     int64
+	Succeeded int64
+	Failed    int64
+	// FailureReasons are the reasons the job failed, if it did.
+	FailureReasons []string
+}
+
+// Done returns true if the job has completed, failed or been cancelled.
+func (s *BatchJobStatus) Done() bool {
+	switch s.Status {
+	case s3control.JobStatusComplete, s3control.JobStatusFailed, s3control.JobStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// WriteBatchManifest writes a manifest of the files iterated by files, which must be s3 files, to manifest in the
+// CSV format S3 Batch Operations reads, returning how many were written.  Since the manifest is written to a temp file
+// until it's closed, it can list millions of files from a ListIterator without holding them in memory.  files is
+// closed, if it's an io.Closer like ListIterator, when writing stops.
+func WriteBatchManifest(manifest vfs.File, files FileIterator) (count int64, err error) {
+	if closer, ok := files.(io.Closer); ok {
+		defer func() { _ = closer.Close() }()
+	}
+	defer func() {
+		if closeErr := manifest.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	w := csv.NewWriter(manifest)
+	for files.Next() {
+		file, ok := files.File().(*File)
+		if !ok {
+			return count, fmt.Errorf("%s isn't an s3 file", files.File())
+		}
+		// keys are URL encoded in manifests
+		key := (&url.URL{Path: keyOf(file.key)}).EscapedPath()
+		if err := w.Write([]string{file.bucket, key}); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := files.Err(); err != nil {
+		return count, err
+	}
+	w.Flush()
+	return count, w.Error()
+}
+
+// SubmitBatchJob creates an S3 Batch Operations job performing operation on each object listed in manifest, ie: one
+// written by WriteBatchManifest, so huge copies, tagging or restores are run by s3 rather than with millions of API
+// calls.  The job runs asynchronously; see BatchJob.Wait.  A client created from Options or an *s3.S3 passed to
+// WithClient is required, since the job is created with an s3control client configured the same way.
+func (fs *FileSystem) SubmitBatchJob(manifest *File, operation BatchOperation, opts BatchJobOptions) (*BatchJob, error) {
+	if opts.AccountID == "" || opts.RoleARN == "" {
+		return nil, errors.New("an AccountID and RoleARN are required for a batch job")
+	}
+	if operation.operation == nil {
+		return nil, errors.New("a batch operation is required")
+	}
+	control, err := fs.controlClient()
+	if err != nil {
+		return nil, err
+	}
+	client, err := fs.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	// the manifest is identified by its ETag, so a manifest that's changed since isn't used
+	head, err := client.HeadObject(new(s3.HeadObjectInput).SetBucket(manifest.bucket).SetKey(keyOf(manifest.key)))
+	if err != nil {
+		return nil, err
+	}
+	spec := new(s3control.JobManifestSpec).
+		SetFormat(s3control.JobManifestFormatS3batchOperationsCsv20180820).
+		SetFields(aws.StringSlice([]string{s3control.JobManifestFieldNameBucket, s3control.JobManifestFieldNameKey}))
+	if opts.InventoryManifest {
+		spec = new(s3control.JobManifestSpec).SetFormat(s3control.JobManifestFormatS3inventoryReportCsv20161130)
+	}
+	location := new(s3control.JobManifestLocation).
+		SetObjectArn("arn:aws:s3:::" + manifest.bucket + "/" + keyOf(manifest.key)).
+		SetETag(aws.StringValue(head.ETag))
+
+	report := new(s3control.JobReport).SetEnabled(false)
+	if opts.Report != nil {
+		report.SetEnabled(true).
+			SetBucket("arn:aws:s3:::" + opts.Report.bucket).
+			SetFormat(s3control.JobReportFormatReportCsv20180820).
+			SetReportScope(s3control.JobReportScopeAllTasks)
+		if prefix := opts.Report.listPrefix(); prefix != "" {
+			report.SetPrefix(prefix)
+		}
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	input := new(s3control.CreateJobInput).
+		SetAccountId(opts.AccountID).
+		SetClientRequestToken(hex.EncodeToString(token)).
+		SetConfirmationRequired(opts.ConfirmationRequired).
+		SetManifest(new(s3control.JobManifest).SetSpec(spec).SetLocation(location)).
+		SetOperation(operation.operation).
+		SetPriority(opts.Priority).
+		SetReport(report).
+		SetRoleArn(opts.RoleARN)
+	if opts.Description != "" {
+		input.SetDescription(opts.Description)
+	}
+	output, err := control.CreateJob(input)
+	if err != nil {
+		return nil, err
+	}
+	return &BatchJob{ID: aws.StringValue(output.JobId), accountID: opts.AccountID, fileSystem: fs}, nil
+}
+
+// BatchJob returns the S3 Batch Operations job with the given id, ie: to check on a job submitted by another process.
+func (fs *FileSystem) BatchJob(accountID, id string) *BatchJob {
+	return &BatchJob{ID: id, accountID: accountID, fileSystem: fs}
+}
+
+// Status returns the job's progress.
+func (j *BatchJob) Status() (*BatchJobStatus, error) {
+	control, err := j.fileSystem.controlClient()
+	if err != nil {
+		return nil, err
+	}
+	output, err := control.DescribeJob(new(s3control.DescribeJobInput).SetAccountId(j.accountID).SetJobId(j.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	job := output.Job
+	status := &BatchJobStatus{Status: aws.StringValue(job.Status)}
+	if progress := job.ProgressSummary; progress != nil {
+		status.Total = aws.Int64Value(progress.TotalNumberOfTasks)
+		status.Succeeded = aws.Int64Value(progress.NumberOfTasksSucceeded)
+		status.Failed = aws.Int64Value(progress.NumberOfTasksFailed)
+	}
+	for _, failure := range job.FailureReasons {
+		status.FailureReasons = append(status.FailureReasons,
+			aws.StringValue(failure.FailureCode)+": "+aws.StringValue(failure.FailureReason))
+	}
+	return status, nil
+}
+
+// Wait polls the job's status every interval until it's done, returning its final status.
+func (j *BatchJob) Wait(interval time.Duration) (*BatchJobStatus, error) {
+	for {
+		status, err := j.Status()
+		if err != nil || status.Done() {
+			return status, err
+		}
+		time.Sleep(interval)
+	}
+}
+
+// Confirm runs a job created with ConfirmationRequired.
+func (j *BatchJob) Confirm() error {
+	return j.updateStatus(s3control.RequestedJobStatusReady)
+}
+
+// Cancel cancels the job.  Objects the operation was already performed on aren't affected.
+func (j *BatchJob) Cancel() error {
+	return j.updateStatus(s3control.RequestedJobStatusCancelled)
+}
+
+func (j *BatchJob) updateStatus(status string) error {
+	control, err := j.fileSystem.controlClient()
+	if err != nil {
+		return err
+	}
+	_, err = control.UpdateJobStatus(new(s3control.UpdateJobStatusInput).
+		SetAccountId(j.accountID).
+		SetJobId(j.ID).
+		SetRequestedJobStatus(status))
+	return err
+}
+
+// controlClient returns the s3control client for Batch Operations, creating it, if necessary, with the configuration
+// of the file system's client.
+func (fs *FileSystem) controlClient() (s3controliface.S3ControlAPI, error) {
+	if fs.control != nil {
+		return fs.control, nil
+	}
+	client, err := fs.Client()
+	if err != nil {
+		return nil, err
+	}
+	s3Client, ok := client.(*s3.S3)
+	if !ok {
+		return nil, errors.New("s3 Batch Operations require a client created from Options or an *s3.S3")
+	}
+
+	// s3control has its own endpoints, so only an s3 client's other configuration applies
+	config := s3Client.Config.Copy()
+	config.Endpoint = nil
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+	fs.control = s3control.New(sess)
+	return fs.control, nil
+}
@@ -0,0 +1,192 @@
+package s3
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3control"
+	"github.com/aws/aws-sdk-go/service/s3control/s3controliface"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/backend/mem"
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+// fakeControl records the Batch Operations calls made to it.
+type fakeControl struct {
+	s3controliface.S3ControlAPI
+	created  *s3control.CreateJobInput
+	updated  []string
+	statuses []string
+}
+
+func (c *fakeControl) CreateJob(input *s3control.CreateJobInput) (*s3control.CreateJobOutput, error) {
+	c.created = input
+	return &s3control.CreateJobOutput{JobId: aws.String("job-1")}, nil
+}
+
+func (c *fakeControl) DescribeJob(input *s3control.DescribeJobInput) (*s3control.DescribeJobOutput, error) {
+	if aws.StringValue(input.JobId) != "job-1" || aws.StringValue(input.AccountId) != "123456789012" {
+		return nil, errors.New("no such job")
+	}
+	status := c.statuses[0]
+	if len(c.statuses) > 1 {
+		c.statuses = c.statuses[1:]
+	}
+	job := &s3control.JobDescriptor{
+		Status: aws.String(status),
+		ProgressSummary: &s3control.JobProgressSummary{
+			TotalNumberOfTasks:     aws.Int64(3),
+			NumberOfTasksSucceeded: aws.Int64(2),
+			NumberOfTasksFailed:    aws.Int64(1),
+		},
+	}
+	if status == s3control.JobStatusFailed {
+		job.FailureReasons = []*s3control.JobFailure{{FailureCode: aws.String("400"), FailureReason: aws.String("bad manifest")}}
+	}
+	return &s3control.DescribeJobOutput{Job: job}, nil
+}
+
+func (c *fakeControl) UpdateJobStatus(input *s3control.UpdateJobStatusInput) (*s3control.UpdateJobStatusOutput, error) {
+	c.updated = append(c.updated, aws.StringValue(input.RequestedJobStatus))
+	return &s3control.UpdateJobStatusOutput{}, nil
+}
+
+type batchTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	control   *fakeControl
+	fs        *FileSystem
+}
+
+func (ts *batchTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	ts.control = &fakeControl{}
+	ts.fs = &FileSystem{client: ts.s3apiMock, control: ts.control, options: Options{ACL: "bucket-owner-full-control"}}
+}
+
+func (ts *batchTestSuite) location(bucket, path string) *Location {
+	loc, err := ts.fs.NewLocation(bucket, path)
+	ts.Require().NoError(err)
+	return loc.(*Location)
+}
+
+func (ts *batchTestSuite) TestWriteBatchManifest() {
+	ts.s3apiMock.On("ListObjectsV2", mock.AnythingOfType("*s3.ListObjectsV2Input")).Return(&s3.ListObjectsV2Output{
+		Contents: []*s3.Object{
+			{Key: aws.String("dir/a.txt")},
+			{Key: aws.String("dir/sub/b c.txt")},
+			{Key: aws.String("dir/d,e.txt")},
+		},
+		IsTruncated: aws.Bool(false),
+	}, nil)
+	manifest, err := mem.NewFileSystem().NewFile("", "/manifest.csv")
+	ts.NoError(err)
+
+	count, err := WriteBatchManifest(manifest, ts.location("bucket", "/dir/").ListIterator(ListOptions{Recursive: true}))
+	ts.NoError(err)
+	ts.Equal(int64(3), count)
+	content, err := ioutil.ReadAll(manifest)
+	ts.NoError(err)
+	ts.Equal("bucket,dir/a.txt\nbucket,dir/sub/b%20c.txt\nbucket,\"dir/d,e.txt\"\n", string(content))
+}
+
+func (ts *batchTestSuite) TestSubmitBatchJob() {
+	ts.s3apiMock.On("HeadObject", &s3.HeadObjectInput{Bucket: aws.String("jobs"), Key: aws.String("manifests/copy.csv")}).
+		Return(&s3.HeadObjectOutput{ETag: aws.String(`"etag"`)}, nil)
+	manifest, err := ts.fs.NewFile("jobs", "/manifests/copy.csv")
+	ts.NoError(err)
+
+	job, err := ts.fs.SubmitBatchJob(manifest.(*File), BatchCopy(ts.location("target", "/backup/")), BatchJobOptions{
+		AccountID: "123456789012",
+		RoleARN:   "arn:aws:iam::123456789012:role/batch",
+		Priority:  10,
+		Report:    ts.location("jobs", "/reports/"),
+	})
+	ts.NoError(err)
+	ts.Equal("job-1", job.ID)
+
+	input := ts.control.created
+	ts.Equal("123456789012", aws.StringValue(input.AccountId))
+	ts.Equal("arn:aws:iam::123456789012:role/batch", aws.StringValue(input.RoleArn))
+	ts.Equal(int64(10), aws.Int64Value(input.Priority))
+	ts.NotEmpty(aws.StringValue(input.ClientRequestToken))
+	ts.Equal("arn:aws:s3:::jobs/manifests/copy.csv", aws.StringValue(input.Manifest.Location.ObjectArn))
+	ts.Equal(`"etag"`, aws.StringValue(input.Manifest.Location.ETag))
+	ts.Equal(s3control.JobManifestFormatS3batchOperationsCsv20180820, aws.StringValue(input.Manifest.Spec.Format))
+	ts.Equal([]string{"Bucket", "Key"}, aws.StringValueSlice(input.Manifest.Spec.Fields))
+	copyOperation := input.Operation.S3PutObjectCopy
+	ts.Equal("arn:aws:s3:::target", aws.StringValue(copyOperation.TargetResource))
+	ts.Equal("backup/", aws.StringValue(copyOperation.TargetKeyPrefix))
+	ts.Equal("bucket-owner-full-control", aws.StringValue(copyOperation.CannedAccessControlList))
+	ts.True(aws.BoolValue(input.Report.Enabled))
+	ts.Equal("arn:aws:s3:::jobs", aws.StringValue(input.Report.Bucket))
+	ts.Equal("reports/", aws.StringValue(input.Report.Prefix))
+	ts.NoError(input.Validate())
+
+	// an inventory report as the manifest, without a completion report
+	_, err = ts.fs.SubmitBatchJob(manifest.(*File), BatchTag(map[string]string{"b": "2", "a": "1"}), BatchJobOptions{
+		AccountID:         "123456789012",
+		RoleARN:           "arn:aws:iam::123456789012:role/batch",
+		InventoryManifest: true,
+	})
+	ts.NoError(err)
+	input = ts.control.created
+	ts.Equal(s3control.JobManifestFormatS3inventoryReportCsv20161130, aws.StringValue(input.Manifest.Spec.Format))
+	ts.Equal("a", aws.StringValue(input.Operation.S3PutObjectTagging.TagSet[0].Key))
+	ts.False(aws.BoolValue(input.Report.Enabled))
+	ts.NoError(input.Validate())
+
+	_, err = ts.fs.SubmitBatchJob(manifest.(*File), BatchRestore(s3.TierBulk, 7), BatchJobOptions{AccountID: "123456789012"})
+	ts.EqualError(err, "an AccountID and RoleARN are required for a batch job")
+}
+
+func (ts *batchTestSuite) TestStatus() {
+	ts.control.statuses = []string{s3control.JobStatusPreparing, s3control.JobStatusActive, s3control.JobStatusComplete}
+	job := ts.fs.BatchJob("123456789012", "job-1")
+
+	status, err := job.Status()
+	ts.NoError(err)
+	ts.Equal(s3control.JobStatusPreparing, status.Status)
+	ts.False(status.Done())
+
+	status, err = job.Wait(0)
+	ts.NoError(err)
+	ts.True(status.Done())
+	ts.Equal(&BatchJobStatus{Status: s3control.JobStatusComplete, Total: 3, Succeeded: 2, Failed: 1}, status)
+
+	ts.control.statuses = []string{s3control.JobStatusFailed}
+	status, err = job.Wait(0)
+	ts.NoError(err)
+	ts.Equal([]string{"400: bad manifest"}, status.FailureReasons)
+
+	_, err = ts.fs.BatchJob("123456789012", "missing").Status()
+	ts.EqualError(err, "no such job")
+
+	ts.NoError(job.Confirm())
+	ts.NoError(job.Cancel())
+	ts.Equal([]string{s3control.RequestedJobStatusReady, s3control.RequestedJobStatusCancelled}, ts.control.updated)
+}
+
+func (ts *batchTestSuite) TestControlClient() {
+	_, err := (&FileSystem{client: ts.s3apiMock}).controlClient()
+	ts.EqualError(err, "s3 Batch Operations require a client created from Options or an *s3.S3")
+
+	fs := NewFileSystem().WithOptions(Options{Region: "us-west-2", Endpoint: "http://localhost:9000"})
+	control, err := fs.controlClient()
+	ts.NoError(err)
+	config := control.(*s3control.S3Control).Config
+	ts.Equal("us-west-2", aws.StringValue(config.Region))
+	ts.NotEqual("http://localhost:9000", config.Endpoint, "s3's endpoint isn't used for s3control")
+
+	fs.WithOptions(Options{Region: "us-east-1"})
+	ts.Nil(fs.control, "control client is recreated with new options")
+}
+
+func TestBatch(t *testing.T) {
+	suite.Run(t, new(batchTestSuite))
+}
@@ -0,0 +1,109 @@
+package s3
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// CreateBucket creates a bucket (volume).  If the FileSystem's Options specify a Region other than us-east-1, the bucket
+// is created in that region.
+func (fs *FileSystem) CreateBucket(bucket string) error {
+	if bucket == "" {
+		return errors.New("non-empty string bucket is required")
+	}
+
+	client, err := fs.Client()
+	if err != nil {
+		return err
+	}
+
+	input := new(s3.CreateBucketInput).SetBucket(bucket)
+	if opts, ok := fs.options.(Options); ok && opts.Region != "" && opts.Region != "us-east-1" {
+		input.SetCreateBucketConfiguration(new(s3.CreateBucketConfiguration).SetLocationConstraint(opts.Region))
+	}
+
+	_, err = client.CreateBucket(input)
+	return err
+}
+
+// DeleteBucket deletes a bucket (volume).  The bucket must be empty.
+func (fs *FileSystem) DeleteBucket(bucket string) error {
+	if bucket == "" {
+		return errors.New("non-empty string bucket is required")
+	}
+
+	client, err := fs.Client()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteBucket(new(s3.DeleteBucketInput).SetBucket(bucket))
+	return err
+}
+
+// BucketExists returns true if the bucket exists and is accessible.  False is returned without an error if the bucket
+// simply doesn't exist.
+func (fs *FileSystem) BucketExists(bucket string) (bool, error) {
+	if bucket == "" {
+		return false, errors.New("non-empty string bucket is required")
+	}
+
+	client, err := fs.Client()
+	if err != nil {
+		return false, err
+	}
+
+	_, err = client.HeadBucket(new(s3.HeadBucketInput).SetBucket(bucket))
+	if err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Ping checks that the file system's credentials are valid and that bucket is reachable with them, by sending a HEAD
+// request for the bucket, so services can fail fast at startup and readiness probes can detect credentials that have
+// expired or been revoked.  Unlike BucketExists, a missing bucket is an error (vfs.IsNotFound is true of it).  Set
+// OperationTimeout in Options to bound how long it takes when s3 can't be reached.
+func (fs *FileSystem) Ping(bucket string) error {
+	if bucket == "" {
+		return errors.New("non-empty string bucket is required")
+	}
+
+	client, err := fs.Client()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.HeadBucket(new(s3.HeadBucketInput).SetBucket(bucket))
+	return err
+}
+
+// ListBuckets returns the names of all buckets (volumes) owned by the authenticated user.
+func (fs *FileSystem) ListBuckets() ([]string, error) {
+	client, err := fs.Client()
+	if err != nil {
+		return []string{}, err
+	}
+
+	output, err := client.ListBuckets(new(s3.ListBucketsInput))
+	if err != nil {
+		return []string{}, err
+	}
+
+	buckets := make([]string, 0, len(output.Buckets))
+	for _, bucket := range output.Buckets {
+		buckets = append(buckets, aws.StringValue(bucket.Name))
+	}
+	return buckets, nil
+}
+
+// Volumes returns the names of the buckets owned by the authenticated user, as ListBuckets does.  Switch a Location to
+// one of them with Location.ChangeVolume.
+func (fs *FileSystem) Volumes() ([]string, error) {
+	return fs.ListBuckets()
+}
@@ -0,0 +1,130 @@
+package s3
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type bucketTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	fs        *FileSystem
+}
+
+func (ts *bucketTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	ts.fs = &FileSystem{client: ts.s3apiMock, options: Options{Region: "us-west-2"}}
+}
+
+func (ts *bucketTestSuite) TestCreateBucket() {
+	ts.s3apiMock.On("CreateBucket", mock.MatchedBy(func(input *s3.CreateBucketInput) bool {
+		return *input.Bucket == "bucket" && *input.CreateBucketConfiguration.LocationConstraint == "us-west-2"
+	})).Return(&s3.CreateBucketOutput{}, nil).Once()
+	ts.NoError(ts.fs.CreateBucket("bucket"))
+
+	// no location constraint for us-east-1
+	ts.fs.options = Options{Region: "us-east-1"}
+	ts.s3apiMock.On("CreateBucket", mock.MatchedBy(func(input *s3.CreateBucketInput) bool {
+		return *input.Bucket == "bucket2" && input.CreateBucketConfiguration == nil
+	})).Return(nil, errors.New("create error")).Once()
+	ts.EqualError(ts.fs.CreateBucket("bucket2"), "create error")
+
+	ts.EqualError(ts.fs.CreateBucket(""), "non-empty string bucket is required")
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *bucketTestSuite) TestDeleteBucket() {
+	ts.s3apiMock.On("DeleteBucket", &s3.DeleteBucketInput{Bucket: aws.String("bucket")}).Return(&s3.DeleteBucketOutput{}, nil).Once()
+	ts.NoError(ts.fs.DeleteBucket("bucket"))
+	ts.EqualError(ts.fs.DeleteBucket(""), "non-empty string bucket is required")
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *bucketTestSuite) TestBucketExists() {
+	ts.s3apiMock.On("HeadBucket", &s3.HeadBucketInput{Bucket: aws.String("bucket")}).Return(&s3.HeadBucketOutput{}, nil).Once()
+	exists, err := ts.fs.BucketExists("bucket")
+	ts.NoError(err)
+	ts.True(exists)
+
+	ts.s3apiMock.On("HeadBucket", &s3.HeadBucketInput{Bucket: aws.String("missing")}).
+		Return(nil, awserr.New(errCodeNotFound, "not found", nil)).Once()
+	exists, err = ts.fs.BucketExists("missing")
+	ts.NoError(err, "missing bucket isn't an error")
+	ts.False(exists)
+
+	ts.s3apiMock.On("HeadBucket", &s3.HeadBucketInput{Bucket: aws.String("forbidden")}).
+		Return(nil, awserr.New("Forbidden", "forbidden", nil)).Once()
+	exists, err = ts.fs.BucketExists("forbidden")
+	ts.Error(err)
+	ts.False(exists)
+}
+
+func (ts *bucketTestSuite) TestPing() {
+	ts.s3apiMock.On("HeadBucket", &s3.HeadBucketInput{Bucket: aws.String("bucket")}).Return(&s3.HeadBucketOutput{}, nil).Once()
+	ts.NoError(ts.fs.Ping("bucket"))
+
+	ts.s3apiMock.On("HeadBucket", &s3.HeadBucketInput{Bucket: aws.String("missing")}).
+		Return(nil, awserr.New(errCodeNotFound, "not found", nil)).Once()
+	err := ts.fs.Ping("missing")
+	ts.Error(err, "missing bucket is an error")
+	ts.True(isNotFound(err))
+
+	ts.s3apiMock.On("HeadBucket", &s3.HeadBucketInput{Bucket: aws.String("bucket")}).
+		Return(nil, awserr.New("ExpiredToken", "token expired", nil)).Once()
+	ts.Error(ts.fs.Ping("bucket"))
+
+	ts.EqualError(ts.fs.Ping(""), "non-empty string bucket is required")
+}
+
+func (ts *bucketTestSuite) TestListBuckets() {
+	ts.s3apiMock.On("ListBuckets", mock.AnythingOfType("*s3.ListBucketsInput")).Return(&s3.ListBucketsOutput{
+		Buckets: []*s3.Bucket{{Name: aws.String("bucket1")}, {Name: aws.String("bucket2")}},
+	}, nil).Once()
+	buckets, err := ts.fs.ListBuckets()
+	ts.NoError(err)
+	ts.Equal([]string{"bucket1", "bucket2"}, buckets)
+
+	ts.s3apiMock.On("ListBuckets", mock.AnythingOfType("*s3.ListBucketsInput")).Return(nil, errors.New("list error")).Once()
+	buckets, err = ts.fs.ListBuckets()
+	ts.EqualError(err, "list error")
+	ts.Empty(buckets)
+}
+
+func (ts *bucketTestSuite) TestVolumes() {
+	ts.s3apiMock.On("ListBuckets", mock.AnythingOfType("*s3.ListBucketsInput")).Return(&s3.ListBucketsOutput{
+		Buckets: []*s3.Bucket{{Name: aws.String("bucket1")}, {Name: aws.String("bucket2")}},
+	}, nil).Once()
+	volumes, err := ts.fs.Volumes()
+	ts.NoError(err)
+	ts.Equal([]string{"bucket1", "bucket2"}, volumes)
+}
+
+func (ts *bucketTestSuite) TestChangeVolume() {
+	location, err := ts.fs.NewLocation("bucket", "/path/to/")
+	ts.NoError(err)
+
+	ts.s3apiMock.On("HeadBucket", &s3.HeadBucketInput{Bucket: aws.String("other")}).Return(&s3.HeadBucketOutput{}, nil).Once()
+	ts.NoError(location.(*Location).ChangeVolume("other"))
+	ts.Equal("other", location.Volume())
+	ts.Equal("/path/to/", location.Path(), "path is kept")
+
+	ts.s3apiMock.On("HeadBucket", &s3.HeadBucketInput{Bucket: aws.String("missing")}).
+		Return(nil, awserr.New(errCodeNotFound, "not found", nil)).Once()
+	ts.EqualError(location.(*Location).ChangeVolume("missing"), "bucket missing does not exist")
+	ts.Equal("other", location.Volume(), "location is unchanged")
+
+	ts.EqualError(location.(*Location).ChangeVolume(""), "non-empty string bucket is required")
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func TestBucket(t *testing.T) {
+	suite.Run(t, new(bucketTestSuite))
+}
@@ -0,0 +1,60 @@
+package s3
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// Checksums holds the hex encoded digests of the data written to a File, see Options.ComputeChecksums.
+type Checksums struct {
+	MD5    string
+	SHA256 string
+}
+
+// writeHashes computes digests of data as it's written to a File.
+type writeHashes struct {
+	md5    hash.Hash
+	sha256 hash.Hash
+}
+
+func newWriteHashes() *writeHashes {
+	return &writeHashes{
+		md5:    md5.New(),
+		sha256: sha256.New(),
+	}
+}
+
+// Write implements io.Writer.  hash.Hash writes never return an error.
+func (h *writeHashes) Write(p []byte) (int, error) {
+	_, _ = h.md5.Write(p)
+	_, _ = h.sha256.Write(p)
+	return len(p), nil
+}
+
+// applyToUpload sets the upload's Content-MD5 header so s3 verifies the data it receives.  s3manager only sends it for
+// single part uploads.
+func (h *writeHashes) applyToUpload(input *s3manager.UploadInput) {
+	contentMD5 := base64.StdEncoding.EncodeToString(h.md5.Sum(nil))
+	input.ContentMD5 = &contentMD5
+}
+
+func (h *writeHashes) checksums() *Checksums {
+	return &Checksums{
+		MD5:    hex.EncodeToString(h.md5.Sum(nil)),
+		SHA256: hex.EncodeToString(h.sha256.Sum(nil)),
+	}
+}
+
+// Checksums returns the digests of the data written to the file, available after Close when Options.ComputeChecksums
+// (or VerifyWrites) is set, without re-reading the object.  nil is returned if nothing has been written and closed
+// since the option was set.
+func (f *File) Checksums() *Checksums {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.checksums
+}
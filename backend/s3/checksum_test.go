@@ -0,0 +1,69 @@
+package s3
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type checksumTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+}
+
+func (ts *checksumTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+}
+
+func (ts *checksumTestSuite) TestComputeChecksums() {
+	ts.s3apiMock.On("PutObjectRequest", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		// base64 md5 of "hello world"
+		return aws.StringValue(input.ContentMD5) == "XrY7u+Ae7tCTyyK7j1rNww=="
+	})).Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}}, &s3.PutObjectOutput{}).Once()
+
+	fs := &FileSystem{client: ts.s3apiMock, options: Options{ComputeChecksums: true}}
+	file, err := fs.NewFile("bucket", "/file.txt")
+	ts.NoError(err)
+
+	_, err = file.Write([]byte("hello "))
+	ts.NoError(err)
+	_, err = file.Write([]byte("world"))
+	ts.NoError(err)
+	ts.Nil(file.(*File).Checksums(), "checksums aren't available until Close")
+	ts.NoError(file.Close())
+
+	ts.Equal(&Checksums{
+		MD5:    "5eb63bbbe01eeed093cb22bb8f5acdc3",
+		SHA256: "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+	}, file.(*File).Checksums())
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *checksumTestSuite) TestChecksumsNotComputedByDefault() {
+	ts.s3apiMock.On("PutObjectRequest", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return input.ContentMD5 == nil
+	})).Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}}, &s3.PutObjectOutput{}).Once()
+
+	fs := &FileSystem{client: ts.s3apiMock, options: Options{}}
+	file, err := fs.NewFile("bucket", "/file.txt")
+	ts.NoError(err)
+	_, err = file.Write([]byte("hello world"))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+
+	ts.Nil(file.(*File).Checksums())
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func TestChecksum(t *testing.T) {
+	suite.Run(t, new(checksumTestSuite))
+}
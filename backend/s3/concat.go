@@ -0,0 +1,156 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// minCopyPartSize is the minimum size s3 allows for each part of a multipart upload except the last.
+const minCopyPartSize = 5 * 1024 * 1024
+
+// maxCopyParts is the maximum number of parts s3 allows in a multipart upload.
+const maxCopyParts = 10000
+
+// Concat writes the contents of parts, in order, to the file, ie: to assemble a file uploaded in chunks.  When every
+// part is an s3 file accessible with the file's credentials and all but the last are at least 5MiB, the parts are
+// concatenated server-side with a multipart upload of UploadPartCopy requests, so nothing is downloaded.  Otherwise
+// the parts' contents are streamed through.
+func (f *File) Concat(parts ...vfs.File) error {
+	if len(parts) == 0 {
+		return errors.New("at least one part is required")
+	}
+
+	copySources, err := f.copySources(parts)
+	if err != nil {
+		return err
+	}
+	if copySources == nil {
+		return f.streamConcat(parts)
+	}
+	return f.copyParts(copySources)
+}
+
+// partCopy is a part of a multipart upload copied server-side from source, or from sourceRange of it, if set, ie:
+// "bytes=0-1023".
+type partCopy struct {
+	source      string
+	sourceRange string
+}
+
+// copyParts replaces the object with the concatenation of parts, copied server-side with a multipart upload of
+// UploadPartCopy requests.  The upload is aborted on error.
+func (f *File) copyParts(parts []partCopy) error {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	createInput := new(s3.CreateMultipartUploadInput).
+		SetBucket(f.bucket).
+		SetKey(keyOf(f.key)).
+		SetServerSideEncryption("AES256")
+	opts, _ := f.fileSystem.options.(Options)
+	if opts.ACL != "" {
+		createInput.SetACL(opts.ACL)
+	}
+	if contentType := f.contentType(opts, nil); contentType != "" {
+		createInput.SetContentType(contentType)
+	}
+	upload, err := client.CreateMultipartUpload(createInput)
+	if err != nil {
+		return err
+	}
+	uploadID := aws.StringValue(upload.UploadId)
+
+	completed := make([]*s3.CompletedPart, 0, len(parts))
+	for i, part := range parts {
+		partNumber := int64(i + 1)
+		input := new(s3.UploadPartCopyInput).
+			SetBucket(f.bucket).
+			SetKey(keyOf(f.key)).
+			SetUploadId(uploadID).
+			SetPartNumber(partNumber).
+			SetCopySource(part.source)
+		if part.sourceRange != "" {
+			input.SetCopySourceRange(part.sourceRange)
+		}
+		output, err := client.UploadPartCopy(input)
+		if err != nil {
+			_ = f.AbortMultipartUpload(uploadID)
+			return fmt.Errorf("unable to copy part %d of %s: %s", partNumber, f, err.Error())
+		}
+		etag := ""
+		if output.CopyPartResult != nil {
+			etag = aws.StringValue(output.CopyPartResult.ETag)
+		}
+		completed = append(completed, new(s3.CompletedPart).SetPartNumber(partNumber).SetETag(etag))
+	}
+
+	_, err = client.CompleteMultipartUpload(new(s3.CompleteMultipartUploadInput).
+		SetBucket(f.bucket).
+		SetKey(keyOf(f.key)).
+		SetUploadId(uploadID).
+		SetMultipartUpload(new(s3.CompletedMultipartUpload).SetParts(completed)))
+	f.invalidateHead()
+	if err != nil {
+		_ = f.AbortMultipartUpload(uploadID)
+	}
+	return err
+}
+
+// copySources returns the CopySource of each part if they can all be concatenated server-side, otherwise nil.
+func (f *File) copySources(parts []vfs.File) ([]partCopy, error) {
+	if len(parts) > maxCopyParts {
+		return nil, nil
+	}
+
+	copySources := make([]partCopy, 0, len(parts))
+	for i, part := range parts {
+		s3Part, ok := part.(*File)
+		if !ok {
+			return nil, nil
+		}
+		copyInput, err := s3Part.getCopyObjectInput(f)
+		if err != nil {
+			return nil, err
+		}
+		if copyInput == nil {
+			return nil, nil
+		}
+		if i < len(parts)-1 {
+			size, err := s3Part.Size()
+			if err != nil {
+				return nil, err
+			}
+			if size < minCopyPartSize {
+				return nil, nil
+			}
+		}
+		copySources = append(copySources, partCopy{source: aws.StringValue(copyInput.CopySource)})
+	}
+	return copySources, nil
+}
+
+// streamConcat writes the contents of each part to the file in turn.
+func (f *File) streamConcat(parts []vfs.File) error {
+	// start the write buffer so the first part is appended to it rather than copied over the file server-side
+	if _, err := f.Write([]byte{}); err != nil {
+		return err
+	}
+	for _, part := range parts {
+		if _, err := utils.Copy(f, part); err != nil {
+			_ = part.Close()
+			return err
+		}
+		if err := part.Close(); err != nil {
+			return err
+		}
+	}
+	return f.Close()
+}
@@ -0,0 +1,100 @@
+package s3
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type concatTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	fs        *FileSystem
+	file      *File
+}
+
+func (ts *concatTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	ts.fs = &FileSystem{client: ts.s3apiMock, options: Options{AccessKeyID: "abc"}}
+	file, err := ts.fs.NewFile("bucket", "/assembled.csv")
+	ts.NoError(err)
+	ts.file = file.(*File)
+}
+
+func (ts *concatTestSuite) parts(sizes ...int64) []vfs.File {
+	var parts []vfs.File
+	for i, size := range sizes {
+		parts = append(parts, &File{
+			fileSystem: ts.fs,
+			bucket:     "bucket",
+			key:        "/chunks/part" + string(rune('1'+i)),
+			head:       &s3.HeadObjectOutput{ContentLength: aws.Int64(size)},
+		})
+	}
+	return parts
+}
+
+func (ts *concatTestSuite) TestConcat_ServerSide() {
+	ts.s3apiMock.On("CreateMultipartUpload", mock.MatchedBy(func(input *s3.CreateMultipartUploadInput) bool {
+		return *input.Bucket == "bucket" && *input.Key == "assembled.csv"
+	})).Return(&s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil).Once()
+	for i, copySource := range []string{"bucket%2Fchunks%2Fpart1", "bucket%2Fchunks%2Fpart2"} {
+		partNumber := int64(i + 1)
+		copySource := copySource
+		ts.s3apiMock.On("UploadPartCopy", mock.MatchedBy(func(input *s3.UploadPartCopyInput) bool {
+			return *input.PartNumber == partNumber && *input.CopySource == copySource && *input.UploadId == "upload"
+		})).Return(&s3.UploadPartCopyOutput{CopyPartResult: &s3.CopyPartResult{ETag: aws.String(copySource)}}, nil).Once()
+	}
+	ts.s3apiMock.On("CompleteMultipartUpload", mock.MatchedBy(func(input *s3.CompleteMultipartUploadInput) bool {
+		parts := input.MultipartUpload.Parts
+		return len(parts) == 2 && *parts[0].PartNumber == 1 && *parts[1].ETag == "bucket%2Fchunks%2Fpart2"
+	})).Return(&s3.CompleteMultipartUploadOutput{}, nil).Once()
+
+	// the last part may be smaller than the minimum part size
+	ts.NoError(ts.file.Concat(ts.parts(minCopyPartSize, 10)...))
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *concatTestSuite) TestConcat_ServerSideAbortsOnError() {
+	ts.s3apiMock.On("CreateMultipartUpload", mock.Anything).
+		Return(&s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil).Once()
+	ts.s3apiMock.On("UploadPartCopy", mock.Anything).Return(nil, errors.New("copy failed")).Once()
+	ts.s3apiMock.On("AbortMultipartUpload", mock.MatchedBy(func(input *s3.AbortMultipartUploadInput) bool {
+		return *input.UploadId == "upload"
+	})).Return(&s3.AbortMultipartUploadOutput{}, nil).Once()
+
+	err := ts.file.Concat(ts.parts(minCopyPartSize, 10)...)
+	ts.EqualError(err, "unable to copy part 1 of s3://bucket/assembled.csv: copy failed")
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *concatTestSuite) TestConcat_Streamed() {
+	// parts smaller than the minimum part size can't be copied server-side
+	ts.s3apiMock.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).Return(func(input *s3.GetObjectInput) *s3.GetObjectOutput {
+		return &s3.GetObjectOutput{Body: nopCloser{bytes.NewBufferString(*input.Key + ",")}}
+	}, nil)
+	ts.s3apiMock.On("PutObjectRequest", mock.AnythingOfType("*s3.PutObjectInput")).
+		Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}}, &s3.PutObjectOutput{}).Once()
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+
+	ts.NoError(ts.file.Concat(ts.parts(10, 10)...))
+	ts.s3apiMock.AssertNotCalled(ts.T(), "CreateMultipartUpload", mock.Anything)
+	ts.s3apiMock.AssertExpectations(ts.T())
+
+	ts.EqualError(ts.file.Concat(), "at least one part is required")
+}
+
+func TestConcat(t *testing.T) {
+	suite.Run(t, new(concatTestSuite))
+}
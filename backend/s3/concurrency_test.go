@@ -0,0 +1,102 @@
+package s3
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type concurrencyTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	fs        *FileSystem
+}
+
+func (ts *concurrencyTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	ts.fs = &FileSystem{client: ts.s3apiMock, options: Options{AccessKeyID: "abc"}}
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+}
+
+func (ts *concurrencyTestSuite) TestConcurrentWrites() {
+	const workers, lines = 8, 100
+	var uploaded string
+	ts.s3apiMock.On("PutObjectRequest", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		data, _ := ioutil.ReadAll(input.Body)
+		uploaded = string(data)
+		return true
+	})).Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}},
+		&s3.PutObjectOutput{}).Once()
+
+	file, err := ts.fs.NewFile("bucket", "/shared.txt")
+	ts.NoError(err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < lines; j++ {
+				_, err := file.Write([]byte("line\n"))
+				ts.NoError(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	ts.NoError(file.Close())
+	ts.Equal(strings.Repeat("line\n", workers*lines), uploaded)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *concurrencyTestSuite) TestConcurrentReads() {
+	contents := strings.Repeat("0123456789", 100)
+	ts.s3apiMock.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).
+		Return(&s3.GetObjectOutput{Body: nopCloser{bytes.NewBufferString(contents)}}, nil).Once()
+
+	file, err := ts.fs.NewFile("bucket", "/shared.txt")
+	ts.NoError(err)
+
+	// each chunk is read by exactly one worker, so between them they read the whole file, downloading it only once
+	var mu sync.Mutex
+	total := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, 7)
+			for {
+				n, err := file.Read(buf)
+				mu.Lock()
+				total += n
+				mu.Unlock()
+				if err == io.EOF {
+					return
+				}
+				ts.NoError(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	ts.Equal(len(contents), total)
+	ts.NoError(file.Close())
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func TestConcurrency(t *testing.T) {
+	suite.Run(t, new(concurrencyTestSuite))
+}
@@ -0,0 +1,41 @@
+package s3
+
+import (
+	"mime"
+	"net/http"
+	"path"
+)
+
+// sniffLen is the number of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// contentType returns the content type to upload the file with: Options.ContentType if set, otherwise one detected from
+// the file's extension or the start of data, if possible.  An empty string leaves the content type to s3.
+func (f *File) contentType(opts Options, data []byte) string {
+	if opts.ContentType != "" {
+		return opts.ContentType
+	}
+	if opts.DisableContentTypeDetection {
+		return ""
+	}
+	return detectContentType(f.Name(), data)
+}
+
+// detectContentType returns the MIME type for the file extension of name or, if it isn't known, sniffed from data.
+// An empty string is returned if neither identifies the content.
+func detectContentType(name string, data []byte) string {
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		return contentType
+	}
+	if len(data) == 0 {
+		return ""
+	}
+	if len(data) > sniffLen {
+		data = data[:sniffLen]
+	}
+	// DetectContentType falls back to application/octet-stream when it can't identify the content
+	if contentType := http.DetectContentType(data); contentType != "application/octet-stream" {
+		return contentType
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package s3
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type contentTypeTestSuite struct {
+	suite.Suite
+}
+
+func (ts *contentTypeTestSuite) TestDetectContentType() {
+	ts.Equal("text/html; charset=utf-8", detectContentType("index.html", nil), "detected by extension")
+	ts.Equal("image/png", detectContentType("image.png", []byte("not really a png")), "extension takes precedence")
+	ts.Equal("application/pdf", detectContentType("report", []byte("%PDF-1.4")), "sniffed when extension is unknown")
+	ts.Equal("text/plain; charset=utf-8", detectContentType("data", []byte(strings.Repeat("a", 1000))))
+	ts.Equal("", detectContentType("data", []byte{0x00, 0x01, 0x02}), "unidentified content is left to s3")
+	ts.Equal("", detectContentType("data", nil))
+}
+
+func (ts *contentTypeTestSuite) TestContentTypeOptions() {
+	file := &File{key: "/index.html"}
+	ts.Equal("text/html; charset=utf-8", file.contentType(Options{}, nil))
+	ts.Equal("application/x-custom", file.contentType(Options{ContentType: "application/x-custom"}, nil), "override")
+	ts.Equal("", file.contentType(Options{DisableContentTypeDetection: true}, nil), "detection disabled")
+}
+
+func (ts *contentTypeTestSuite) TestUploadContentType() {
+	s3apiMock := &mocks.S3API{}
+	s3apiMock.On("PutObjectRequest", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return aws.StringValue(input.ContentType) == "application/json"
+	})).Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}}, &s3.PutObjectOutput{}).Once()
+	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+
+	fs := &FileSystem{client: s3apiMock, options: Options{}}
+	file, err := fs.NewFile("bucket", "/data/payload.json")
+	ts.NoError(err)
+	_, err = file.Write([]byte(`{"hello": "world"}`))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+	s3apiMock.AssertExpectations(ts.T())
+}
+
+func TestContentType(t *testing.T) {
+	suite.Run(t, new(contentTypeTestSuite))
+}
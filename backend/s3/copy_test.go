@@ -0,0 +1,196 @@
+package s3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type copyTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	fs        *FileSystem
+}
+
+func (ts *copyTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	ts.fs = &FileSystem{client: ts.s3apiMock, options: Options{AccessKeyID: "abc"}}
+}
+
+func (ts *copyTestSuite) newFile(key string) *File {
+	file, err := ts.fs.NewFile("bucket", key)
+	ts.NoError(err)
+	return file.(*File)
+}
+
+func (ts *copyTestSuite) TestWriteTo_Streams() {
+	ts.s3apiMock.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).
+		Return(&s3.GetObjectOutput{Body: nopCloser{bytes.NewBufferString("hello world")}}, nil).Once()
+
+	src := ts.newFile("/src.txt")
+	buf := &bytes.Buffer{}
+	n, err := io.Copy(buf, src)
+	ts.NoError(err)
+	ts.Equal(int64(11), n)
+	ts.Equal("hello world", buf.String())
+	ts.Nil(src.tempFile, "no temp file should be used")
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *copyTestSuite) TestWriteTo_ServerSide() {
+	ts.s3apiMock.On("CopyObject", mock.MatchedBy(func(input *s3.CopyObjectInput) bool {
+		return *input.Key == "dst.txt" && *input.CopySource == "bucket%2Fsrc.txt"
+	})).Return(&s3.CopyObjectOutput{}, nil).Once()
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(11)}, nil)
+
+	dst := ts.newFile("/dst.txt")
+	n, err := io.Copy(dst, ts.newFile("/src.txt"))
+	ts.NoError(err)
+	ts.Equal(int64(11), n)
+	ts.s3apiMock.AssertNotCalled(ts.T(), "GetObject", mock.Anything)
+
+	// the copy replaced dst's contents, so it can't be written to until it's closed
+	_, err = dst.Write([]byte("more"))
+	ts.Equal(errCopiedInto, err)
+	ts.NoError(dst.Close())
+	ts.s3apiMock.On("PutObjectRequest", mock.AnythingOfType("*s3.PutObjectInput")).
+		Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}}, &s3.PutObjectOutput{}).Once()
+	_, err = dst.Write([]byte("more"))
+	ts.NoError(err)
+	ts.NoError(dst.Close())
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *copyTestSuite) TestReadFrom() {
+	ts.s3apiMock.On("PutObjectRequest", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		body := new(strings.Builder)
+		_, _ = io.Copy(body, input.Body)
+		return body.String() == "hello world"
+	})).Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}}, &s3.PutObjectOutput{}).Once()
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+
+	dst := ts.newFile("/dst.txt")
+	n, err := dst.ReadFrom(strings.NewReader("hello world"))
+	ts.NoError(err)
+	ts.Equal(int64(11), n)
+	ts.NoError(dst.Close())
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *copyTestSuite) TestReadFrom_ServerSide() {
+	ts.s3apiMock.On("CopyObject", mock.AnythingOfType("*s3.CopyObjectInput")).Return(&s3.CopyObjectOutput{}, nil).Once()
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(11)}, nil)
+
+	dst := ts.newFile("/dst.txt")
+	n, err := dst.ReadFrom(ts.newFile("/src.txt"))
+	ts.NoError(err)
+	ts.Equal(int64(11), n)
+	_, err = dst.ReadFrom(strings.NewReader("more"))
+	ts.Equal(errCopiedInto, err)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *copyTestSuite) TestWrite_SpillsToDisk() {
+	ts.s3apiMock.On("PutObjectRequest", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		body := new(strings.Builder)
+		_, _ = io.Copy(body, input.Body)
+		return body.String() == "hello world"
+	})).Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}}, &s3.PutObjectOutput{}).Once()
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+
+	ts.fs.options = Options{AccessKeyID: "abc", MaxWriteBufferMemory: 5}
+	dst := ts.newFile("/dst.txt")
+	_, err := dst.Write([]byte("hello"))
+	ts.NoError(err)
+	ts.False(dst.writeBuffer.Spilled())
+	_, err = dst.Write([]byte(" world"))
+	ts.NoError(err)
+	ts.True(dst.writeBuffer.Spilled())
+
+	ts.NoError(dst.Close())
+	ts.Nil(dst.writeBuffer)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *copyTestSuite) TestCopyToFile_Stream() {
+	ts.s3apiMock.On("GetObjectWithContext", mock.Anything, &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("src/data.csv"),
+	}).Return(&s3.GetObjectOutput{
+		Body:        nopCloser{bytes.NewBufferString("hello world")},
+		ContentType: aws.String("text/plain"),
+	}, nil).Once()
+
+	targetMock := &mocks.S3API{}
+	var uploaded string
+	targetMock.On("PutObjectRequest", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		body, err := ioutil.ReadAll(input.Body)
+		uploaded = string(body)
+		return err == nil &&
+			aws.StringValue(input.Bucket) == "other-account" &&
+			aws.StringValue(input.Key) == "dst/data.csv" &&
+			aws.StringValue(input.ContentType) == "text/plain"
+	})).Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}},
+		&s3.PutObjectOutput{}).Once()
+	targetFS := &FileSystem{client: targetMock, options: Options{AccessKeyID: "abc", CopyMode: CopyModeStream}}
+	target, err := targetFS.NewFile("other-account", "/dst/data.csv")
+	ts.NoError(err)
+
+	var progress int64
+	ts.NoError(ts.newFile("/src/data.csv").CopyToFile(target, vfs.WithProgress(func(copied int64) { progress = copied })))
+	ts.Equal("hello world", uploaded, "read with the source's client and written with the target's")
+	ts.Equal(int64(11), progress)
+	ts.s3apiMock.AssertNotCalled(ts.T(), "CopyObject", mock.Anything)
+	targetMock.AssertNotCalled(ts.T(), "CopyObject", mock.Anything)
+	ts.s3apiMock.AssertExpectations(ts.T())
+	targetMock.AssertExpectations(ts.T())
+
+	// ReadFrom doesn't copy server-side either
+	copied, err := ts.newFile("/src/data.csv").copyInto(target.(*File))
+	ts.NoError(err)
+	ts.False(copied)
+}
+
+func (ts *copyTestSuite) TestCopyToFile_StreamError() {
+	ts.s3apiMock.On("GetObjectWithContext", mock.Anything, mock.AnythingOfType("*s3.GetObjectInput")).
+		Return(nil, errors.New("access denied")).Once()
+	ts.fs.options = Options{AccessKeyID: "abc", CopyMode: CopyModeStream}
+	targetFS := &FileSystem{client: &mocks.S3API{}, options: Options{AccessKeyID: "abc"}}
+	target, err := targetFS.NewFile("other-account", "/dst/data.csv")
+	ts.NoError(err)
+
+	err = ts.newFile("/src/data.csv").CopyToFile(target)
+	ts.EqualError(err, "access denied")
+}
+
+func (ts *copyTestSuite) TestCopyToFile_StreamSameFileSystem() {
+	// a file system's own credentials can read and write both objects, so the copy is still made server-side
+	ts.fs.options = Options{AccessKeyID: "abc", CopyMode: CopyModeStream}
+	ts.s3apiMock.On("CopyObject", mock.MatchedBy(func(input *s3.CopyObjectInput) bool {
+		return *input.CopySource == "bucket%2Fsrc%2Fdata.csv" && *input.Key == "dst/data.csv"
+	})).Return(&s3.CopyObjectOutput{}, nil).Once()
+
+	ts.NoError(ts.newFile("/src/data.csv").CopyToFile(ts.newFile("/dst/data.csv")))
+	ts.s3apiMock.AssertNotCalled(ts.T(), "GetObjectWithContext", mock.Anything, mock.Anything)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func TestCopy(t *testing.T) {
+	suite.Run(t, new(copyTestSuite))
+}
@@ -0,0 +1,81 @@
+package s3
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// CredentialProvider resolves the credentials for each request as it's made, from the bucket and the key (or, for
+// listings, the key prefix) it's for, ie: to use each tenant's own keys from a secrets store such as Vault.  Returning
+// nil credentials uses those found as described in Authentication.  Credentials are resolved for every request, so
+// providers should return a *credentials.Credentials they keep for each tenant, which caches the value until it
+// expires, rather than fetching secrets each time; expiring or invalidating it picks up rotated secrets.
+type CredentialProvider interface {
+	Credentials(bucket, key string) (*credentials.Credentials, error)
+}
+
+// CredentialProviderFunc is a func implementing CredentialProvider.
+type CredentialProviderFunc func(bucket, key string) (*credentials.Credentials, error)
+
+// Credentials implements CredentialProvider.
+func (f CredentialProviderFunc) Credentials(bucket, key string) (*credentials.Credentials, error) {
+	return f(bucket, key)
+}
+
+// addCredentialProviderHandlers adds a request handler signing each request with the credentials provider resolves
+// for it.  It runs before the request is signed, both when it's sent and when it's presigned.
+func addCredentialProviderHandlers(handlers *request.Handlers, provider CredentialProvider) {
+	handlers.Sign.PushFrontNamed(request.NamedHandler{
+		Name: "vfs.CredentialProvider",
+		Fn: func(r *request.Request) {
+			bucket, key := requestTarget(r)
+			creds, err := resolveCredentials(provider, bucket, key)
+			if err != nil {
+				r.Error = err
+				return
+			}
+			if creds != nil {
+				// the request's Config is its own copy, so this only affects this request
+				r.Config.Credentials = creds
+			}
+		},
+	})
+}
+
+// resolveCredentials returns the credentials provider resolves for bucket and key, wrapping any error.
+func resolveCredentials(provider CredentialProvider, bucket, key string) (*credentials.Credentials, error) {
+	creds, err := provider.Credentials(bucket, key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get credentials for s3://%s/%s: %s", bucket, key, err.Error())
+	}
+	return creds, nil
+}
+
+// requestTarget returns the bucket and key, or key prefix, of a request's input, either of which may be empty.
+func requestTarget(r *request.Request) (string, string) {
+	bucket := paramValue(r.Params, "Bucket")
+	key := paramValue(r.Params, "Key")
+	if key == "" {
+		key = paramValue(r.Params, "Prefix")
+	}
+	return bucket, key
+}
+
+// paramValue returns the string field name of an operation's input, or "" if it has none.
+func paramValue(params interface{}, name string) string {
+	values, err := awsutil.ValuesAtPath(params, name)
+	if err != nil || len(values) == 0 {
+		return ""
+	}
+	switch value := values[0].(type) {
+	case *string:
+		return aws.StringValue(value)
+	case string:
+		return value
+	}
+	return ""
+}
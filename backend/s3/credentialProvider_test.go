@@ -0,0 +1,94 @@
+package s3
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/suite"
+)
+
+type credentialProviderTestSuite struct {
+	suite.Suite
+	fs       *FileSystem
+	requests []string
+}
+
+func (ts *credentialProviderTestSuite) SetupTest() {
+	ts.requests = nil
+	provider := CredentialProviderFunc(func(bucket, key string) (*credentials.Credentials, error) {
+		ts.requests = append(ts.requests, bucket+"/"+key)
+		switch bucket {
+		case "tenant-a":
+			return credentials.NewStaticCredentials("KEY-A", "secret-a", ""), nil
+		case "tenant-b":
+			return credentials.NewStaticCredentials("KEY-B", "secret-b", ""), nil
+		case "broken":
+			return nil, errors.New("vault is sealed")
+		}
+		return nil, nil
+	})
+	ts.fs = NewFileSystem().WithOptions(Options{
+		Region:             "us-east-1",
+		AccessKeyID:        "DEFAULT",
+		SecretAccessKey:    "secret",
+		CredentialProvider: provider,
+	})
+}
+
+func (ts *credentialProviderTestSuite) client() *s3.S3 {
+	client, err := ts.fs.Client()
+	ts.Require().NoError(err)
+	return client.(*s3.S3)
+}
+
+func (ts *credentialProviderTestSuite) TestSignsPerBucket() {
+	for bucket, keyID := range map[string]string{"tenant-a": "KEY-A", "tenant-b": "KEY-B", "shared": "DEFAULT"} {
+		req, _ := ts.client().GetObjectRequest(new(s3.GetObjectInput).SetBucket(bucket).SetKey("some/file.txt"))
+		ts.NoError(req.Sign())
+		ts.Contains(req.HTTPRequest.Header.Get("Authorization"), "Credential="+keyID+"/", bucket)
+	}
+	ts.ElementsMatch([]string{"tenant-a/some/file.txt", "tenant-b/some/file.txt", "shared/some/file.txt"}, ts.requests)
+}
+
+func (ts *credentialProviderTestSuite) TestListingUsesPrefix() {
+	req, _ := ts.client().ListObjectsV2Request(new(s3.ListObjectsV2Input).SetBucket("tenant-a").SetPrefix("reports/"))
+	ts.NoError(req.Sign())
+	ts.Contains(req.HTTPRequest.Header.Get("Authorization"), "Credential=KEY-A/")
+	ts.Equal([]string{"tenant-a/reports/"}, ts.requests)
+}
+
+func (ts *credentialProviderTestSuite) TestPresign() {
+	req, _ := ts.client().GetObjectRequest(new(s3.GetObjectInput).SetBucket("tenant-b").SetKey("file.txt"))
+	u, err := req.Presign(time.Hour)
+	ts.NoError(err)
+	parsed, err := url.Parse(u)
+	ts.NoError(err)
+	ts.True(strings.HasPrefix(parsed.Query().Get("X-Amz-Credential"), "KEY-B/"))
+}
+
+func (ts *credentialProviderTestSuite) TestPostPolicy() {
+	file, err := ts.fs.NewFile("tenant-a", "/uploads/file.txt")
+	ts.NoError(err)
+	policy, err := file.(*File).PostPolicy(PostPolicyOptions{})
+	ts.NoError(err)
+	ts.True(strings.HasPrefix(policy.Fields["x-amz-credential"], "KEY-A/"))
+}
+
+func (ts *credentialProviderTestSuite) TestProviderError() {
+	_, err := ts.client().GetObject(new(s3.GetObjectInput).SetBucket("broken").SetKey("file.txt"))
+	ts.EqualError(err, "unable to get credentials for s3://broken/file.txt: vault is sealed")
+}
+
+func (ts *credentialProviderTestSuite) TestAnonymous() {
+	_, err := getClient(Options{Anonymous: true, CredentialProvider: CredentialProviderFunc(nil)})
+	ts.EqualError(err, "an anonymous file system can't have a CredentialProvider")
+}
+
+func TestCredentialProvider(t *testing.T) {
+	suite.Run(t, new(credentialProviderTestSuite))
+}
@@ -0,0 +1,95 @@
+package s3
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// webIdentityProviderName is reported as the ProviderName of credentials retrieved with a web identity token.
+const webIdentityProviderName = "WebIdentityRoleProvider"
+
+// webIdentityRoleAssumer is the subset of the sts API used by webIdentityRoleProvider.
+type webIdentityRoleAssumer interface {
+	AssumeRoleWithWebIdentity(*sts.AssumeRoleWithWebIdentityInput) (*sts.AssumeRoleWithWebIdentityOutput, error)
+}
+
+// webIdentityRoleProvider retrieves credentials by assuming a role with the OIDC token found in a file, such as the one
+// projected into an EKS pod.  The token file is re-read on every retrieval since it is rotated out from under us.
+type webIdentityRoleProvider struct {
+	credentials.Expiry
+	client          webIdentityRoleAssumer
+	roleARN         string
+	roleSessionName string
+	tokenFile       string
+	duration        time.Duration
+	expiryWindow    time.Duration
+}
+
+// Retrieve implements credentials.Provider.
+func (p *webIdentityRoleProvider) Retrieve() (credentials.Value, error) {
+	token, err := ioutil.ReadFile(p.tokenFile)
+	if err != nil {
+		return credentials.Value{ProviderName: webIdentityProviderName},
+			fmt.Errorf("unable to read web identity token file %s: %s", p.tokenFile, err.Error())
+	}
+
+	input := new(sts.AssumeRoleWithWebIdentityInput).
+		SetRoleArn(p.roleARN).
+		SetRoleSessionName(p.roleSessionName).
+		SetWebIdentityToken(string(token))
+	if p.duration > 0 {
+		input.SetDurationSeconds(int64(p.duration / time.Second))
+	}
+
+	output, err := p.client.AssumeRoleWithWebIdentity(input)
+	if err != nil {
+		return credentials.Value{ProviderName: webIdentityProviderName}, err
+	}
+
+	p.SetExpiration(aws.TimeValue(output.Credentials.Expiration), p.expiryWindow)
+	return credentials.Value{
+		AccessKeyID:     aws.StringValue(output.Credentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(output.Credentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(output.Credentials.SessionToken),
+		ProviderName:    webIdentityProviderName,
+	}, nil
+}
+
+// getRoleCredentials returns credentials for the role in opt, assumed either with a web identity token (when
+// WebIdentityTokenFile is set) or with the credentials of cfgProvider.  The returned credentials are refreshed
+// automatically as they near expiration.
+func getRoleCredentials(cfgProvider client.ConfigProvider, opt Options) *credentials.Credentials {
+	sessionName := opt.RoleSessionName
+	if sessionName == "" {
+		sessionName = fmt.Sprintf("vfs-%d", time.Now().UTC().UnixNano())
+	}
+
+	if opt.WebIdentityTokenFile != "" {
+		return credentials.NewCredentials(&webIdentityRoleProvider{
+			client:          sts.New(cfgProvider),
+			roleARN:         opt.RoleARN,
+			roleSessionName: sessionName,
+			tokenFile:       opt.WebIdentityTokenFile,
+			duration:        opt.RoleDuration,
+			expiryWindow:    opt.CredentialExpiryWindow,
+		})
+	}
+
+	return stscreds.NewCredentials(cfgProvider, opt.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+		p.RoleSessionName = sessionName
+		p.ExpiryWindow = opt.CredentialExpiryWindow
+		if opt.RoleDuration > 0 {
+			p.Duration = opt.RoleDuration
+		}
+		if opt.ExternalID != "" {
+			p.ExternalID = aws.String(opt.ExternalID)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package s3
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/stretchr/testify/suite"
+)
+
+type fakeWebIdentityRoleAssumer struct {
+	input  *sts.AssumeRoleWithWebIdentityInput
+	output *sts.AssumeRoleWithWebIdentityOutput
+	err    error
+}
+
+func (f *fakeWebIdentityRoleAssumer) AssumeRoleWithWebIdentity(input *sts.AssumeRoleWithWebIdentityInput) (*sts.AssumeRoleWithWebIdentityOutput, error) {
+	f.input = input
+	return f.output, f.err
+}
+
+type credentialsTestSuite struct {
+	suite.Suite
+	tokenFile string
+}
+
+func (ts *credentialsTestSuite) SetupTest() {
+	f, err := ioutil.TempFile("", "token")
+	ts.NoError(err)
+	_, err = f.WriteString("my-oidc-token")
+	ts.NoError(err)
+	ts.NoError(f.Close())
+	ts.tokenFile = f.Name()
+}
+
+func (ts *credentialsTestSuite) TearDownTest() {
+	_ = os.Remove(ts.tokenFile)
+}
+
+func (ts *credentialsTestSuite) TestWebIdentityRoleProvider() {
+	expiration := time.Now().Add(time.Hour)
+	assumer := &fakeWebIdentityRoleAssumer{
+		output: &sts.AssumeRoleWithWebIdentityOutput{
+			Credentials: &sts.Credentials{
+				AccessKeyId:     aws.String("key"),
+				SecretAccessKey: aws.String("secret"),
+				SessionToken:    aws.String("token"),
+				Expiration:      &expiration,
+			},
+		},
+	}
+	provider := &webIdentityRoleProvider{
+		client:          assumer,
+		roleARN:         "arn:aws:iam::123456789012:role/writer",
+		roleSessionName: "session",
+		tokenFile:       ts.tokenFile,
+		duration:        30 * time.Minute,
+	}
+
+	creds := credentials.NewCredentials(provider)
+	value, err := creds.Get()
+	ts.NoError(err)
+	ts.Equal(credentials.Value{
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		ProviderName:    webIdentityProviderName,
+	}, value)
+	ts.Equal("my-oidc-token", *assumer.input.WebIdentityToken)
+	ts.Equal("arn:aws:iam::123456789012:role/writer", *assumer.input.RoleArn)
+	ts.Equal("session", *assumer.input.RoleSessionName)
+	ts.Equal(int64(1800), *assumer.input.DurationSeconds)
+	ts.False(creds.IsExpired())
+
+	// credentials are refreshed once expired
+	provider.SetExpiration(time.Now().Add(-time.Minute), 0)
+	ts.True(creds.IsExpired())
+	assumer.input = nil
+	_, err = creds.Get()
+	ts.NoError(err)
+	ts.NotNil(assumer.input, "role was assumed again")
+}
+
+func (ts *credentialsTestSuite) TestWebIdentityRoleProvider_Errors() {
+	assumer := &fakeWebIdentityRoleAssumer{err: errors.New("access denied")}
+	provider := &webIdentityRoleProvider{client: assumer, tokenFile: ts.tokenFile}
+	_, err := provider.Retrieve()
+	ts.EqualError(err, "access denied")
+
+	provider.tokenFile = "/nonexistent/token"
+	_, err = provider.Retrieve()
+	ts.Error(err, "missing token file")
+	ts.Contains(err.Error(), "unable to read web identity token file /nonexistent/token")
+}
+
+func (ts *credentialsTestSuite) TestGetRoleCredentials() {
+	sess := session.Must(session.NewSession())
+	ts.NotNil(getRoleCredentials(sess, Options{RoleARN: "arn", ExternalID: "external"}))
+	ts.NotNil(getRoleCredentials(sess, Options{RoleARN: "arn", WebIdentityTokenFile: ts.tokenFile}))
+
+	client, err := getClient(Options{RoleARN: "arn", RoleSessionName: "session"})
+	ts.NoError(err)
+	ts.NotNil(client.(*s3.S3).Config.Credentials)
+}
+
+func TestCredentials(t *testing.T) {
+	suite.Run(t, new(credentialsTestSuite))
+}
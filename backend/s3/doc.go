@@ -53,11 +53,253 @@ would have to be cast as s3.FileSystem to use the following:
       fs = fs.WithClient(s3apiMock)
   }
 
+To reuse an aws session the application already configured, ie: with a custom HTTP client or proxy, pass it to
+WithSession (or set Session in Options).  The client is created from it on first use, with any other Options applied:
+
+  fs := s3.NewFileSystem().WithSession(sess)
+
 Object ACL
 
 Canned ACL's can be passed in as an Option.  This string will be applied to all writes, moves, and copies.
 See https://docs.aws.amazon.com/AmazonS3/latest/dev/acl-overview.html#canned-acl for values.
 
+Content Type
+
+Uploads are given a content type detected from the file's extension or, failing that, by sniffing its first 512 bytes,
+so that objects served via presigned URLs open correctly in a browser.  Set ContentType in Options to use a fixed content
+type instead, or DisableContentTypeDetection to leave it to s3 (binary/octet-stream).
+
+Temp Files
+
+Reading or seeking an s3 file downloads it to a temp file, which is removed on Close.  Set TempDir in Options to create
+temp files somewhere other than os.TempDir(), ie: a volume with more space than a container's /tmp, and MaxTempUsage to
+cap their total size.  Temp files left behind by a crash can be removed at startup with:
+
+    fs.CleanupTempFiles(24 * time.Hour)
+
+Transfer Acceleration
+
+Setting UseAccelerate to true in Options will cause the client to use the s3-accelerate endpoint for all requests.  The
+bucket must have Transfer Acceleration enabled.
+See https://docs.aws.amazon.com/AmazonS3/latest/dev/transfer-acceleration.html
+
+Object Lock
+
+For buckets with Object Lock enabled, ObjectLockMode and ObjectLockRetention Options will apply a retention period to
+all writes and copies, and ObjectLockLegalHold will place a legal hold on them.  Existing objects can be locked with
+File.SetRetention and File.SetLegalHold.
+
+Archived Objects
+
+Reading an object in the GLACIER or DEEP_ARCHIVE storage class that hasn't been restored returns an
+*s3.ArchivedObjectError.  A restore can be initiated and polled:
+
+  err := s3File.Restore(s3.TierBulk, 7)
+  ...
+  status, err := s3File.RestoreStatus()
+  if status.Restored {
+      // read the file
+  }
+
+File.StorageClass reports an object's storage class, and File.SetStorageClass moves it to another by copying it onto
+itself, ie: to demote cold data to STANDARD_IA or GLACIER:
+
+  err := s3File.SetStorageClass(s3.StorageClassStandardIa)
+
+S3 Select
+
+File.Select runs a SQL expression against a CSV, JSON or Parquet object server-side, returning only the matching
+records:
+
+  rows, err := s3File.Select(
+      "SELECT s.name FROM S3Object s WHERE s.state = 'KS'",
+      &s3.InputSerialization{CSV: &s3.CSVInput{FileHeaderInfo: aws.String(s3.FileHeaderInfoUse)}},
+      &s3.OutputSerialization{CSV: &s3.CSVOutput{}},
+  )
+  defer rows.Close()
+
+Resumable Uploads
+
+With LeavePartsOnError set in Options, a failed multipart upload is left in progress rather than aborted.  It can then
+be found with File.MultipartUploads and finished with File.ResumeMultipartUpload, given the part size it was uploaded
+in, which only uploads the missing parts, or discarded with File.AbortMultipartUpload.
+
+Client-Side Chunked Uploads
+
+A multipart upload can also be driven by another client, such as a browser uploading a large file in chunks.
+File.CreateMultipartUpload starts the upload and File.PresignUploadPart returns a URL the client PUTs each part to:
+
+  s3File := file.(*s3.File)
+  uploadID, err := s3File.CreateMultipartUpload()
+  partURL, err := s3File.PresignUploadPart(uploadID, 1, 15*time.Minute)
+
+Once every part is uploaded, File.CompleteMultipartUpload assembles them into the object, either from the part numbers
+and ETags the client reports or, given nil, from the parts listed by File.Parts.  Parts can also be uploaded directly
+with File.UploadPart.
+
+Browser Uploads
+
+File.PostPolicy and Location.PostPolicy return a signed S3 POST policy, letting a browser upload straight to s3 with an
+HTML form, to the file or to any key beneath the location, within the size and content type constraints given:
+
+  policy, err := location.(*s3.Location).PostPolicy(s3.PostPolicyOptions{MaxSize: 10 << 20, ContentTypePrefix: "image/"})
+
+The form is POSTed to policy.URL with each of policy.Fields, followed by the file.  Policies are signed with the
+client's credentials, so the client must be one created from Options.
+
+Locking
+
+s3.File implements vfs.Locker, so workers can take an exclusive, expiring lease on a file with vfs.Lock:
+
+  lease, err := vfs.Lock(file, 5*time.Minute)
+  if err == vfs.ErrLocked {
+      // another worker is processing the file
+  }
+  defer lease.Release()
+
+The lease is a lock object, the file's key with a ".lock" suffix, created and refreshed with conditional writes, so
+only one worker holds it at a time.  A lease not refreshed before its ttl passes may be taken by another worker.
+
+Object Versioning
+
+On versioned buckets, s3.File can list and act on prior versions of an object:
+
+  func DoSomething() {
+      ...
+      s3File := file.(*s3.File)
+
+      versions, err := s3File.Versions()
+
+      // read a prior version
+      old, err := s3File.WithVersion(versions[1].VersionID)
+      b, err := ioutil.ReadAll(old)
+
+      // make a prior version the latest
+      err = s3File.RestoreVersion(versions[1].VersionID)
+
+      // permanently remove a version
+      err = s3File.DeleteVersion(versions[1].VersionID)
+  }
+
+Delete on a versioned bucket only places a delete marker, after which Exists reports false even though prior versions
+remain.  IsDeleteMarked and DeleteMarkers surface this state, RemoveDeleteMarkers undoes the delete, and
+DeleteAllVersions purges every version and delete marker of the key.
+
+Timeouts and Rate Limiting
+
+OperationTimeout in Options bounds every s3 API call, retries included, so a request can't hang indefinitely.  It
+covers waiting for the response, not reading a file's contents once s3 starts sending them, so large reads aren't cut
+short.
+RequestsPerSecond (with RequestBurst) caps the rate of API calls made by the client, which keeps large listing or
+copy jobs within s3 request quotas.
+
+s3 throttles requests to a prefix that arrive faster than it can scale, with 503 SlowDown errors, which fail bulk jobs
+once the SDK's retries run out.  Set AdaptiveThrottling in Options to slow the client down when that happens: each
+storm of throttled requests halves the rate requests are sent at, which recovers by 10% a second while none are, and
+throttled requests are retried no sooner than a Retry-After header asks.  OnThrottle is called for each throttled
+request, ie: to log or count them:
+
+  fs := s3.NewFileSystem().WithOptions(s3.Options{
+      AdaptiveThrottling: true,
+      OnThrottle: func(e s3.ThrottleEvent) {
+          log.Printf("%s s3://%s/%s throttled, now %.0f requests/s", e.Operation, e.Bucket, e.Key, e.RequestsPerSecond)
+      },
+  })
+
+Connections
+
+Go's http client keeps only 2 idle connections per host, so highly concurrent jobs spend much of their time opening
+connections.  Set MaxIdleConnsPerHost in Options to about the number of concurrent requests, and MaxConnsPerHost to cap
+them.  ProxyURL and TLSConfig route requests through a proxy and configure TLS, ie: to trust a private CA:
+
+  fs := s3.NewFileSystem().WithOptions(s3.Options{
+      Region:              "us-east-1",
+      MaxIdleConnsPerHost: 64,
+      ProxyURL:            "http://proxy.internal:3128",
+      TLSConfig:           &tls.Config{RootCAs: pool},
+  })
+
+Waiting for Writes
+
+Close checks that a file it wrote exists before returning, up to 5 times a second apart, which guarded against s3's
+former eventual consistency.  s3 is now strongly consistent, so set DisableWaitForWrite (or WaitForWrite to s3.NoWait)
+in Options to skip the check, or set WaitForWrite to s3.WaitUntilExists with more retries for an S3-compatible store
+that's still eventually consistent.
+
+Inventory Listings
+
+Listing a bucket of 100M+ objects takes 100K+ LIST calls.  Where an S3 Inventory report of the bucket is configured,
+ListIterator can read the files from its latest report instead, in far fewer requests, by setting InventoryManifest in
+ListOptions to the URI of the report's manifest.json.  Recursive lists every file beneath the location, not just those
+directly at it.  The report is only as current as when it was made, and must be in CSV format.  Close the iterator if
+iteration stops early, so the report file being read isn't left open:
+
+  it := loc.(*s3.Location).ListIterator(s3.ListOptions{
+      InventoryManifest: "s3://inventory-bucket/reports/my-bucket/daily/2020-01-02T01-00Z/manifest.json",
+      Recursive:         true,
+  })
+  defer it.Close()
+
+Batch Operations
+
+Copying, tagging or restoring millions of objects one API call at a time takes days.  S3 Batch Operations runs such a
+job inside s3 instead: write a manifest of the files, ie: from a ListIterator, then submit a job performing the
+operation (BatchCopy, BatchTag or BatchRestore) on each, and wait for it to finish:
+
+  count, err := s3.WriteBatchManifest(manifestFile, loc.ListIterator(s3.ListOptions{Recursive: true}))
+  ...
+  job, err := fs.SubmitBatchJob(manifestFile, s3.BatchCopy(backupLoc), s3.BatchJobOptions{
+      AccountID: "123456789012",
+      RoleARN:   "arn:aws:iam::123456789012:role/batch-operations",
+      Report:    reportLoc,
+  })
+  ...
+  status, err := job.Wait(time.Minute)
+
+An S3 Inventory report's manifest.json can be submitted as the manifest too, with InventoryManifest in BatchJobOptions.
+
+Keys
+
+A file's path is its key with a leading slash, ie: the key "path/to/file.txt" is the path "/path/to/file.txt".  Paths
+are cleaned with path.Clean, so "/path//to/./file.txt" is the key "path/to/file.txt" too.  To address keys that
+cleaning would rewrite, such as those with an embedded "//", a leading slash or a trailing slash (zero-byte folder
+markers), set DisableKeyNormalization in Options; paths are then used as given:
+
+  fs := s3.NewFileSystem().WithOptions(s3.Options{DisableKeyNormalization: true})
+  marker, err := fs.NewFile("bucket", "/path/to/")      // the folder marker "path/to/"
+  file, err := fs.NewFile("bucket", "//file.txt")       // the key "/file.txt"
+
+Folders created in the S3 console, or by tools such as Hadoop, are zero-byte folder marker objects, ie: "path/to/" or
+"path/to_$folder$", which are listed as empty files.  Set ExcludeFolderMarkers in Options to leave them out of
+listings, or FolderMarkersAsLocations to list the folders they mark as Locations from ListWithPrefixes instead.
+
+Errors
+
+Errors are returned as the SDK reports them.  Importing the backend registers a classifier with vfs.ClassifyError,
+which sorts them, and the network errors the SDK wraps, into kinds such as vfs.ErrorNotFound, vfs.ErrorThrottled and
+vfs.ErrorTimeout, for deciding whether to retry:
+
+  if vfs.ClassifyError(err) == vfs.ErrorThrottled {
+      // back off and retry
+  }
+
+Bucket Management
+
+s3.FileSystem can manage buckets (volumes) directly, which is handy for provisioning and for tests run against MinIO or
+LocalStack: CreateBucket, DeleteBucket, BucketExists and ListBuckets.  CreateBucket uses the Region in Options, if any,
+as the bucket's location constraint.  Such servers generally need ForcePathStyle set in Options along with their
+Endpoint.  See also the testutil package, which provisions buckets on them for integration tests.
+
+Volumes lists the buckets too, and Location.ChangeVolume switches a location to the same path in another bucket once
+it's checked that the bucket exists.
+
+Ping checks that the credentials are valid and that a bucket is reachable with them, so a service can fail fast at
+startup, or a readiness probe can detect credentials that have expired:
+
+  if err := fs.Ping("my-bucket"); err != nil {
+      log.Fatalf("s3 isn't reachable: %v", err)
+  }
+
 Authentication
 
 Authentication, by default, occurs automatically when Client() is called. It looks for credentials in the following places,
@@ -83,6 +325,54 @@ preferring the first location found:
 See the following for more auth info: https://docs.aws.amazon.com/cli/latest/userguide/cli-configure-envvars.html
 and https://docs.aws.amazon.com/sdk-for-go/v1/developer-guide/configuring-sdk.html
 
+Public buckets can be read without credentials by setting Anonymous in Options, so no credentials are looked for and
+requests are sent unsigned:
+
+  fs := s3.NewFileSystem().WithOptions(s3.Options{Anonymous: true, Region: "us-east-1"})
+
+Anything that would write, such as closing a written file, deleting or copying, then fails with ErrAnonymousWrite.
+
+Assuming a Role
+
+Setting RoleARN in Options assumes that role (optionally with an ExternalID) using the credentials found above, or, if
+WebIdentityTokenFile is also set, using that OIDC token.  The temporary credentials are refreshed automatically before
+they expire.  To act under a different role for just some locations, use Location.WithOptions:
+
+  customerLoc := loc.(*s3.Location).WithOptions(s3.Options{
+      RoleARN:    "arn:aws:iam::123456789012:role/vfs-writer",
+      ExternalID: "customer-external-id",
+  })
+
+Credential Providers
+
+When the credentials depend on which bucket or prefix is being accessed, ie: each tenant's keys kept in Vault and
+rotated there, set a CredentialProvider in Options.  It's asked for the credentials each request is signed with, from
+the bucket and key (or, for listings, prefix) the request is for, so one file system can serve every tenant:
+
+  fs := s3.NewFileSystem().WithOptions(s3.Options{
+      Region: "us-east-1",
+      CredentialProvider: s3.CredentialProviderFunc(func(bucket, key string) (*credentials.Credentials, error) {
+          return tenants.Credentials(bucket, key) // cached per tenant, expired when their secret rotates
+      }),
+  })
+
+Copies are signed with the credentials for the target, which must also be able to read the source.  Returning nil
+credentials uses those found as described above.
+
+Cross-Account Copies
+
+Copies between s3 files are made server-side with CopyObject, using the source's credentials, when both file systems
+have the same access key.  Across accounts, that needs bucket policies letting one account's credentials read the
+source and write the target.  Where they don't, give each file system its own credentials and set CopyMode to
+CopyModeStream, so copies between them are read with the source's credentials and written with the target's, streamed
+through the client without touching disk.  Copies within either file system are still made server-side:
+
+  source := s3.NewFileSystem().WithOptions(s3.Options{RoleARN: "arn:aws:iam::111111111111:role/reader"})
+  target := s3.NewFileSystem().WithOptions(s3.Options{
+      RoleARN:  "arn:aws:iam::222222222222:role/writer",
+      CopyMode: s3.CopyModeStream,
+  })
+
 See Also
 
 See: https://github.com/aws/aws-sdk-go/tree/master/service/s3
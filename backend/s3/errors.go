@@ -0,0 +1,109 @@
+package s3
+
+import (
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+const (
+	// errCodeNotFound is returned by HEAD requests (which have no response body) for a bucket or key that doesn't exist.
+	errCodeNotFound = "NotFound"
+	// errCodeSlowDown is returned by s3 when the request rate for a prefix is too high.
+	errCodeSlowDown = "SlowDown"
+	// errCodeRequestError is returned by the SDK when a request can't be sent, wrapping the cause.
+	errCodeRequestError = "RequestError"
+)
+
+// errorCodeKinds maps s3 error codes to the kind of error they indicate.  Throttling codes are recognized by
+// request.IsErrorThrottle.
+var errorCodeKinds = map[string]vfs.ErrorKind{
+	errCodeNotFound:                vfs.ErrorNotFound,
+	s3.ErrCodeNoSuchKey:            vfs.ErrorNotFound,
+	s3.ErrCodeNoSuchBucket:         vfs.ErrorNotFound,
+	s3.ErrCodeNoSuchUpload:         vfs.ErrorNotFound,
+	"NoSuchVersion":                vfs.ErrorNotFound,
+	"AccessDenied":                 vfs.ErrorPermission,
+	"Forbidden":                    vfs.ErrorPermission,
+	"InvalidAccessKeyId":           vfs.ErrorPermission,
+	"SignatureDoesNotMatch":        vfs.ErrorPermission,
+	"ExpiredToken":                 vfs.ErrorPermission,
+	"NoCredentialProviders":        vfs.ErrorPermission,
+	errCodeSlowDown:                vfs.ErrorThrottled,
+	"ServiceUnavailable":           vfs.ErrorThrottled,
+	"RequestTimeout":               vfs.ErrorTimeout,
+	request.ErrCodeResponseTimeout: vfs.ErrorTimeout,
+	request.CanceledErrorCode:      vfs.ErrorCanceled,
+	errCodeRequestError:            vfs.ErrorNetwork,
+	request.ErrCodeSerialization:   vfs.ErrorNetwork,
+	request.ErrCodeRead:            vfs.ErrorNetwork,
+}
+
+// classifyError maps err, an error returned by the s3 backend, onto a vfs.ErrorKind, whether it's an error response
+// from s3 or a failure to reach it wrapped by the SDK, ie: a RequestError caused by a timeout is vfs.ErrorTimeout.  It's
+// registered with vfs.RegisterErrorClassifier, so vfs.ClassifyError classifies s3 errors.  vfs.ErrorUnknown is returned
+// for errors that didn't come from s3 or the SDK.
+func classifyError(err error) vfs.ErrorKind {
+	if archived, ok := err.(*ArchivedObjectError); ok {
+		return vfs.ClassifyError(archived.Err)
+	}
+	if err == ErrAnonymousWrite {
+		return vfs.ErrorPermission
+	}
+
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		return vfs.ErrorUnknown
+	}
+	if request.IsErrorThrottle(aerr) {
+		return vfs.ErrorThrottled
+	}
+	kind, ok := errorCodeKinds[aerr.Code()]
+	if !ok || kind == vfs.ErrorNetwork {
+		// classify the cause of errors the SDK wraps, ie: a timed out connection
+		if cause := aerr.OrigErr(); cause != nil {
+			if causeKind := vfs.ClassifyError(cause); causeKind != vfs.ErrorUnknown {
+				return causeKind
+			}
+		}
+	}
+	if ok {
+		return kind
+	}
+	if failure, ok := err.(awserr.RequestFailure); ok {
+		return statusCodeKind(failure.StatusCode())
+	}
+	return vfs.ErrorUnknown
+}
+
+// statusCodeKind returns the kind of error indicated by the HTTP status code of an error response without a known code.
+func statusCodeKind(statusCode int) vfs.ErrorKind {
+	switch statusCode {
+	case http.StatusNotFound:
+		return vfs.ErrorNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return vfs.ErrorPermission
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return vfs.ErrorThrottled
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return vfs.ErrorTimeout
+	}
+	return vfs.ErrorUnknown
+}
+
+// isNotFound returns true if err indicates that a bucket or object doesn't exist.
+func isNotFound(err error) bool {
+	return classifyError(err) == vfs.ErrorNotFound
+}
+
+// errorCode returns the s3 error code of err, or "" if it isn't an awserr.Error, ie: a network error.
+func errorCode(err error) string {
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.Code()
+	}
+	return ""
+}
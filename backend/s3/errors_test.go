@@ -0,0 +1,101 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+// timeoutError is a net.Error for a timed out connection.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+type errorsTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	fs        *FileSystem
+}
+
+func (ts *errorsTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	ts.fs = &FileSystem{client: ts.s3apiMock}
+}
+
+func (ts *errorsTestSuite) TestClassifyError() {
+	urlTimeout := &url.Error{Op: "Head", URL: "https://bucket.s3.amazonaws.com/key", Err: timeoutError{}}
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	tests := []struct {
+		err  error
+		kind vfs.ErrorKind
+	}{
+		{nil, vfs.ErrorUnknown},
+		{errors.New("some error"), vfs.ErrorUnknown},
+		{awserr.New(s3.ErrCodeNoSuchKey, "no such key", nil), vfs.ErrorNotFound},
+		{awserr.New(errCodeNotFound, "not found", nil), vfs.ErrorNotFound},
+		{awserr.New(s3.ErrCodeNoSuchBucket, "no such bucket", nil), vfs.ErrorNotFound},
+		{awserr.New("AccessDenied", "access denied", nil), vfs.ErrorPermission},
+		{awserr.New(errCodeSlowDown, "slow down", nil), vfs.ErrorThrottled},
+		{awserr.New("Throttling", "throttling", nil), vfs.ErrorThrottled},
+		{awserr.New(request.CanceledErrorCode, "canceled", context.Canceled), vfs.ErrorCanceled},
+		{awserr.New(errCodeRequestError, "send request failed", urlTimeout), vfs.ErrorTimeout},
+		{awserr.New(errCodeRequestError, "send request failed", refused), vfs.ErrorNetwork},
+		{awserr.New(errCodeRequestError, "send request failed", nil), vfs.ErrorNetwork},
+		{awserr.NewRequestFailure(awserr.New("Unrecognized", "", nil), 404, "id"), vfs.ErrorNotFound},
+		{awserr.NewRequestFailure(awserr.New("Unrecognized", "", nil), 503, "id"), vfs.ErrorThrottled},
+		{awserr.NewRequestFailure(awserr.New("Unrecognized", "", nil), 500, "id"), vfs.ErrorUnknown},
+		{&ArchivedObjectError{URI: "s3://bucket/key", Err: awserr.New("AccessDenied", "", nil)}, vfs.ErrorPermission},
+		{urlTimeout, vfs.ErrorTimeout},
+		{refused, vfs.ErrorNetwork},
+		{context.DeadlineExceeded, vfs.ErrorTimeout},
+		{context.Canceled, vfs.ErrorCanceled},
+	}
+	for _, test := range tests {
+		ts.Equal(test.kind, vfs.ClassifyError(test.err), "%v", test.err)
+	}
+	ts.True(vfs.IsNotFound(awserr.New(s3.ErrCodeNoSuchKey, "", nil)))
+	ts.False(vfs.IsNotFound(timeoutError{}))
+}
+
+func (ts *errorsTestSuite) TestExistsNonAWSError() {
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(nil, timeoutError{}).Once()
+	file, err := ts.fs.NewFile("bucket", "/path/to/file.txt")
+	ts.Require().NoError(err)
+	exists, err := file.Exists()
+	ts.Equal(timeoutError{}, err, "a non-s3 error is returned, not a panic")
+	ts.False(exists)
+
+	ts.s3apiMock.On("HeadBucket", &s3.HeadBucketInput{Bucket: aws.String("bucket")}).Return(nil, timeoutError{}).Once()
+	location, err := ts.fs.NewLocation("bucket", "/path/")
+	ts.Require().NoError(err)
+	exists, err = location.Exists()
+	ts.Equal(timeoutError{}, err, "a non-s3 error is returned, not a panic")
+	ts.False(exists)
+
+	ts.s3apiMock.On("HeadBucket", &s3.HeadBucketInput{Bucket: aws.String("missing")}).
+		Return(nil, awserr.New(errCodeNotFound, "not found", nil)).Once()
+	location, err = ts.fs.NewLocation("missing", "/")
+	ts.Require().NoError(err)
+	exists, err = location.Exists()
+	ts.NoError(err, "HeadBucket's NotFound is a missing bucket")
+	ts.False(exists)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func TestErrors(t *testing.T) {
+	suite.Run(t, new(errorsTestSuite))
+}
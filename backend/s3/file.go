@@ -1,36 +1,64 @@
 package s3
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/url"
 	"os"
 	"path"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 
 	"github.com/c2fo/vfs/v5"
-	"github.com/c2fo/vfs/v5/mocks"
 	"github.com/c2fo/vfs/v5/utils"
 )
 
+// errCopiedInto is returned when writing to a File that has had an object copied into it server-side, see WriteTo.
+var errCopiedInto = errors.New("unable to write to an s3 file after copying into it server-side until it is closed")
+
 //File implements vfs.File interface for S3 fs.
+//
+// A File is safe for concurrent use, ie: by a pool of workers sharing a handle.  Reads (and seeks) share a single
+// cursor into the file's temp file, and concurrent writes are appended to its write buffer in the order they're made,
+// so callers still need to coordinate which goroutine reads or writes what.  Close uploads whatever has been written
+// by every goroutine.  For independent readers and writers, use OpenReader and OpenWriter.
 type File struct {
-	fileSystem  *FileSystem
-	bucket      string
-	key         string
+	fileSystem *FileSystem
+	bucket     string
+	key        string
+	versionID  string
+
+	// mu guards the read and write state below
+	mu          sync.Mutex
 	tempFile    *os.File
-	writeBuffer *bytes.Buffer
+	tempSize    int64
+	writeBuffer *utils.SpillBuffer
+	writeHashes *writeHashes
+	checksums   *Checksums
+	copiedInto  bool
+	// flushed is set when everything in writeBuffer has been uploaded by Flush
+	flushed bool
+	// closed is set once the file has been closed, so closing it again does nothing until it is next read or written
+	closed bool
+
+	// headMu guards the cached HEAD result
+	headMu sync.Mutex
+	head   *s3.HeadObjectOutput
+	// headPartial is set when head was filled in from a listing, so lacks the fields only HEAD returns
+	headPartial bool
 }
 
 // Info Functions
 
-// LastModified returns the LastModified property of a HEAD request to the s3 object.
+// LastModified returns the LastModified property of a HEAD request to the s3 object.  HEAD results are cached on the
+// File, see Refresh.
 func (f *File) LastModified() (*time.Time, error) {
 	head, err := f.getHeadObject()
 	if err != nil {
@@ -50,14 +78,10 @@ func (f *File) Path() string {
 }
 
 // Exists returns a boolean of whether or not the object exists on s3, based on a call for
-// the object's HEAD through the s3 API.
+// the object's HEAD through the s3 API.  HEAD results are cached on the File, see Refresh.
 func (f *File) Exists() (bool, error) {
 	_, err := f.getHeadObject()
-	code := ""
-	if err != nil {
-		code = err.(awserr.Error).Code()
-	}
-	if err != nil && (code == s3.ErrCodeNoSuchKey || code == "NotFound") {
+	if isNotFound(err) {
 		return false, nil
 	} else if err != nil {
 		return false, err
@@ -66,13 +90,38 @@ func (f *File) Exists() (bool, error) {
 	return true, nil
 }
 
-// Size returns the ContentLength value from an s3 HEAD request on the file's object.
+// Size returns the ContentLength value from an s3 HEAD request on the file's object.  HEAD results are cached on the
+// File, see Refresh.
 func (f *File) Size() (uint64, error) {
 	head, err := f.getHeadObject()
 	if err != nil {
 		return 0, err
 	}
-	return uint64(*head.ContentLength), nil
+	return uint64(aws.Int64Value(head.ContentLength)), nil
+}
+
+// Stat returns the file's size, last modified time, content type and encoding, ETag and storage class from a single
+// HEAD request (or the File's cached HEAD result, see Refresh).  Files from a listing make the request, since listings
+// don't include content types.
+func (f *File) Stat() (*vfs.FileInfo, error) {
+	head, err := f.getFullHeadObject()
+	if err != nil {
+		return nil, err
+	}
+	return f.fileInfo(head), nil
+}
+
+// fileInfo returns the FileInfo for the file's HEAD result.
+func (f *File) fileInfo(head *s3.HeadObjectOutput) *vfs.FileInfo {
+	info := vfs.NewFileInfo(f.Name(), aws.Int64Value(head.ContentLength), aws.TimeValue(head.LastModified), 0644)
+	info.ContentType = aws.StringValue(head.ContentType)
+	info.ContentEncoding = aws.StringValue(head.ContentEncoding)
+	info.ETag = strings.Trim(aws.StringValue(head.ETag), `"`)
+	info.StorageClass = s3.StorageClassStandard
+	if head.StorageClass != nil {
+		info.StorageClass = *head.StorageClass
+	}
+	return info
 }
 
 // Location returns a vfs.Location at the location of the object. IE: if file is at
@@ -80,7 +129,7 @@ func (f *File) Size() (uint64, error) {
 func (f *File) Location() vfs.Location {
 	return vfs.Location(&Location{
 		fileSystem: f.fileSystem,
-		prefix:     path.Dir(f.key),
+		prefix:     f.fileSystem.dirPath(f.key),
 		bucket:     f.bucket,
 	})
 }
@@ -89,26 +138,31 @@ func (f *File) Location() vfs.Location {
 
 // CopyToFile puts the contents of File into the targetFile passed. Uses the S3 CopyObject
 // method if the target file is also on S3, otherwise uses io.Copy.
-func (f *File) CopyToFile(file vfs.File) error {
+func (f *File) CopyToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	options := vfs.NewCopyOptions(opts...)
+	if err := options.Context.Err(); err != nil {
+		return err
+	}
+
 	//if target is S3
 	if tf, ok := file.(*File); ok {
-		input, err := f.getCopyObjectInput(tf)
-		if err != nil {
-			return err
-		}
-		//if input is not nil, use it to natively copy object
-		if input != nil {
-			client, err := f.fileSystem.Client()
-			if err != nil {
+		if f.streamCopies(tf) {
+			if err := f.streamCopy(tf, options); err != nil {
 				return err
 			}
-			_, err = client.CopyObject(input)
+			return f.verifyChecksum(file, options)
+		}
+		copied, err := f.nativeCopy(tf)
+		if err != nil {
 			return err
 		}
+		if copied {
+			return f.verifyChecksum(file, options)
+		}
 	}
 
 	//otherwise use TouchCopy (io.Copy)
-	if err := utils.TouchCopy(file, f); err != nil {
+	if err := utils.TouchCopyWithOptions(file, f, options); err != nil {
 		return err
 	}
 	//Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
@@ -116,14 +170,29 @@ func (f *File) CopyToFile(file vfs.File) error {
 		return cerr
 	}
 	//Close file (f) reader
-	return f.Close()
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return f.verifyChecksum(file, options)
+}
+
+// verifyChecksum compares the contents of the file and target, a copy of it, if options ask to.
+func (f *File) verifyChecksum(target vfs.File, options vfs.CopyOptions) error {
+	if !options.VerifyChecksum {
+		return nil
+	}
+	return utils.VerifyChecksum(f, target)
 }
 
 // MoveToFile puts the contents of File into the targetFile passed using File.CopyToFile.
 // If the copy succeeds, the source file is deleted. Any errors from the copy or delete are
-// returned.
-func (f *File) MoveToFile(file vfs.File) error {
-	if err := f.CopyToFile(file); err != nil {
+// returned.  When moving to another scheme, the copy is verified against the source (see
+// utils.VerifyCopy) before the source is deleted, unless move verification is disabled.
+func (f *File) MoveToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	if err := f.CopyToFile(file, opts...); err != nil {
+		return err
+	}
+	if err := f.verifyMove(file, opts); err != nil {
 		return err
 	}
 
@@ -133,35 +202,102 @@ func (f *File) MoveToFile(file vfs.File) error {
 // MoveToLocation works by first calling File.CopyToLocation(vfs.Location) then, if that
 // succeeds, it deletes the original file, returning the new file. If the copy process fails
 // the error is returned, and the Delete isn't called. If the call to Delete fails, the error
-// and the file generated by the copy are both returned.
-func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
-	newFile, err := f.CopyToLocation(location)
+// and the file generated by the copy are both returned.  If the copy is skipped under the
+// vfs.ConflictSkip policy, the original file isn't deleted.
+func (f *File) MoveToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	newFile, skipped, err := f.copyToLocation(location, opts)
 	if err != nil {
 		return nil, err
 	}
+	if skipped {
+		return newFile, nil
+	}
+	if err := f.verifyMove(newFile, opts); err != nil {
+		return nil, err
+	}
 	delErr := f.Delete()
 	return newFile, delErr
 }
 
+// Rename moves the file to newName within its current location (same bucket and prefix) using a server-side CopyObject
+// followed by a delete, returning the renamed file.  IE: renaming s3://bucket/inbox/data.csv to "data.csv.done" results
+// in s3://bucket/inbox/data.csv.done.  newName must be a file name, not a path.
+func (f *File) Rename(newName string) (vfs.File, error) {
+	if newName == "" {
+		return nil, errors.New("non-empty string newName is required")
+	}
+	if strings.Contains(newName, "/") {
+		return nil, fmt.Errorf("newName %q must be a file name, not a path", newName)
+	}
+
+	newFile, err := f.Location().NewFile(newName)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.MoveToFile(newFile); err != nil {
+		return nil, err
+	}
+	return newFile, nil
+}
+
+// verifyMove checks that a move's copy to another scheme was written in full before the source is deleted, unless
+// DisableMoveVerification is set in Options or the move's options include vfs.WithoutMoveVerification.
+func (f *File) verifyMove(target vfs.File, opts []vfs.CopyOption) error {
+	if target.Location().FileSystem().Scheme() == Scheme || vfs.NewCopyOptions(opts...).SkipMoveVerification {
+		return nil
+	}
+	if fsOpts, ok := f.fileSystem.options.(Options); ok && fsOpts.DisableMoveVerification {
+		return nil
+	}
+	return utils.VerifyCopy(f, target)
+}
+
 // CopyToLocation creates a copy of *File, using the file's current name as the new file's
 // name at the given location. If the given location is also s3, the AWS API for copying
-// files will be utilized, otherwise, standard io.Copy will be done to the new file.
-func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
-	newFile, err := location.NewFile(f.Name())
+// files will be utilized, otherwise, standard io.Copy will be done to the new file.  If a
+// file of the same name already exists at the location, the ConflictPolicy in Options
+// determines what happens (overwriting it by default).
+func (f *File) CopyToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	newFile, _, err := f.copyToLocation(location, opts)
 	if err != nil {
 		return nil, err
 	}
+	return newFile, nil
+}
+
+// copyToLocation copies the file to the location according to the ConflictPolicy in opts, or else in Options, returning
+// the target file and whether the copy was skipped.
+func (f *File) copyToLocation(location vfs.Location, opts []vfs.CopyOption) (vfs.File, bool, error) {
+	var policy vfs.ConflictPolicy
+	if fsOpts, ok := f.fileSystem.options.(Options); ok {
+		policy = fsOpts.ConflictPolicy
+	}
+	policy = vfs.NewCopyOptions(opts...).ConflictPolicyOr(policy)
 
-	return newFile, f.CopyToFile(newFile)
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, false, err
+	}
+	newFile, skip, err := utils.ResolveConflict(newFile, policy)
+	if err != nil || skip {
+		return newFile, skip, err
+	}
+	return newFile, false, f.CopyToFile(newFile, opts...)
 }
 
 // CRUD Operations
 
 // Delete clears any local temp file, or write buffer from read/writes to the file, then makes
-// a DeleteObject call to s3 for the file. Returns any error returned by the API.
+// a DeleteObject call to s3 for the file. Returns any error returned by the API.  If the file
+// is pinned to a version (see WithVersion), only that version is removed.
 func (f *File) Delete() error {
-	f.writeBuffer = nil
-	if err := f.Close(); err != nil {
+	f.mu.Lock()
+	err := f.discardWriteBuffer()
+	if err == nil {
+		err = f.close()
+	}
+	f.mu.Unlock()
+	if err != nil {
 		return err
 	}
 
@@ -170,52 +306,152 @@ func (f *File) Delete() error {
 		return err
 	}
 
-	_, err = client.DeleteObject(&s3.DeleteObjectInput{
-		Key:    &f.key,
+	input := &s3.DeleteObjectInput{
+		Key:    aws.String(keyOf(f.key)),
 		Bucket: &f.bucket,
-	})
+	}
+	if f.versionID != "" {
+		input.SetVersionId(f.versionID)
+	}
+	_, err = client.DeleteObject(input)
+	f.invalidateHead()
 	return err
 }
 
 // Close cleans up underlying mechanisms for reading from and writing to the file. Closes and removes the
-// local temp file, and triggers a write to s3 of anything in the f.writeBuffer if it has been created.
+// local temp file, and triggers a write to s3 of anything in the f.writeBuffer if it has been created.  Closing a file
+// again, without reading or writing it in between, does nothing.
 func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.close()
+}
 
-	if f.tempFile != nil {
-		defer func() { _ = f.tempFile.Close() }()
+// close is Close for callers already holding f.mu.
+func (f *File) close() error {
+	if f.closed && f.tempFile == nil && f.writeBuffer == nil && !f.copiedInto {
+		return nil
+	}
 
-		err := os.Remove(f.tempFile.Name())
-		if err != nil && !os.IsNotExist(err) {
+	if err := f.removeTempFile(); err != nil {
+		return err
+	}
+
+	if f.writeBuffer != nil && !f.flushed {
+		if err := f.upload(f.writeBuffer, f.writeHashes); err != nil {
 			return err
 		}
+	}
+	if f.writeHashes != nil {
+		f.checksums = f.writeHashes.checksums()
+	}
 
-		f.tempFile = nil
+	if err := f.discardWriteBuffer(); err != nil {
+		return err
 	}
+	f.copiedInto = false
 
-	if f.writeBuffer != nil {
-		client, err := f.fileSystem.Client()
-		if err != nil {
-			return err
-		}
+	if err := f.waitForWrite(); err != nil {
+		return err
+	}
+	f.closed = true
+	return nil
+}
 
-		uploader := s3manager.NewUploaderWithClient(client)
-		uploadInput := uploadInput(f)
-		uploadInput.Body = f.writeBuffer
+// Flush uploads everything written to the file so far, replacing the object, while keeping the file open for more
+// writes, ie: so a long-running writer can checkpoint its progress.  s3 objects can't be appended to, so each Flush,
+// and the Close that follows it, uploads the file's contents from the start.  Flush does nothing if nothing has been
+// written since the last Flush or Close.
+func (f *File) Flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.writeBuffer == nil || f.flushed {
+		return nil
+	}
+	if err := f.upload(f.writeBuffer, f.writeHashes); err != nil {
+		return err
+	}
+	if err := f.waitForWrite(); err != nil {
+		return err
+	}
+	f.flushed = true
+	return nil
+}
 
-		_, err = uploader.Upload(uploadInput)
-		if err != nil {
-			return err
-		}
+// Abort discards anything written to the file since it was last closed without uploading it, and closes and removes
+// the local temp file, if any.  Unlike Delete, the object itself is left as it was and no request is made to s3.
+func (f *File) Abort() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.removeTempFile(); err != nil {
+		return err
+	}
+	if err := f.discardWriteBuffer(); err != nil {
+		return err
 	}
+	f.copiedInto = false
+	f.closed = true
+	return nil
+}
 
-	f.writeBuffer = nil
+// removeTempFile closes and removes the temp file reads are made from, if any.
+func (f *File) removeTempFile() error {
+	if f.tempFile == nil {
+		return nil
+	}
+	tempFile := f.tempFile
+	defer func() { _ = tempFile.Close() }()
 
-	return waitUntilFileExists(f, 5)
+	err := os.Remove(tempFile.Name())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	f.tempFile = nil
+	f.fileSystem.releaseTemp(f.tempSize)
+	f.tempSize = 0
+	return nil
+}
+
+// upload replaces the object with the contents of buffer, sending the MD5 in hashes, if any, as its Content-MD5.
+func (f *File) upload(buffer *utils.SpillBuffer, hashes *writeHashes) error {
+	f.invalidateHead()
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	opts, _ := f.fileSystem.options.(Options)
+	uploader := s3manager.NewUploaderWithClient(client, uploaderOptions(opts))
+	uploadInput := uploadInput(f)
+	head, err := buffer.Head(sniffLen)
+	if err != nil {
+		return err
+	}
+	if contentType := f.contentType(opts, head); contentType != "" {
+		uploadInput.ContentType = &contentType
+	}
+	if hashes != nil {
+		hashes.applyToUpload(uploadInput)
+	}
+	if uploadInput.Body, err = buffer.Reader(); err != nil {
+		return err
+	}
+
+	if _, err = uploader.Upload(uploadInput); err != nil {
+		return err
+	}
+	if opts.VerifyWrites {
+		return f.verifyUpload(buffer.Len(), hashes)
+	}
+	return nil
 }
 
 // Read implements the standard for io.Reader. For this to work with an s3 file, a temporary local copy of
 // the file is created, and reads work on that. This file is closed and removed upon calling f.Close()
 func (f *File) Read(p []byte) (n int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	if err := f.checkTempFile(); err != nil {
 		return 0, err
 	}
@@ -225,6 +461,8 @@ func (f *File) Read(p []byte) (n int, err error) {
 // Seek implements the standard for io.Seeker. A temporary local copy of the s3 file is created (the same
 // one used for Reads) which Seek() acts on. This file is closed and removed upon calling f.Close()
 func (f *File) Seek(offset int64, whence int) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	if err := f.checkTempFile(); err != nil {
 		return 0, err
 	}
@@ -236,6 +474,15 @@ func (f *File) Seek(offset int64, whence int) (int64, error) {
 // PutObject to s3. The underlying implementation uses s3manager which will determine whether
 // it is appropriate to call PutObject, or initiate a multi-part upload.
 func (f *File) Write(data []byte) (res int, err error) {
+	if f.versionID != "" {
+		return 0, errors.New("unable to write to a specific version of an s3 object")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.copiedInto {
+		return 0, errCopiedInto
+	}
 	if f.writeBuffer == nil {
 		//note, initializing with 'data' and returning len(data), nil
 		//causes issues with some Write usages, notably csv.Writer
@@ -246,9 +493,83 @@ func (f *File) Write(data []byte) (res int, err error) {
 		//
 		//so now we do:
 
-		f.writeBuffer = bytes.NewBuffer([]byte{})
+		opts, _ := f.fileSystem.options.(Options)
+		f.writeBuffer = utils.NewSpillBuffer(opts.MaxWriteBufferMemory, f.fileSystem.tempDir())
+		f.checksums = nil
+		if opts.ComputeChecksums || opts.VerifyWrites {
+			f.writeHashes = newWriteHashes()
+		}
+	}
+	f.flushed = false
+	n, err := f.writeBuffer.Write(data)
+	if f.writeHashes != nil {
+		_, _ = f.writeHashes.Write(data[:n])
+	}
+	return n, err
+}
+
+// WriteTo implements the io.WriterTo interface so io.Copy streams the object straight to w rather than through a local
+// temp file.  If w is an s3.File accessible with the same credentials (and not already written to), the object is
+// copied server-side with CopyObject instead, so nothing is downloaded; w then can't be written to again until it's
+// closed, since the copy replaced its contents.  If the file has already been read from, the remainder of its contents
+// is copied.  With Options.DownloadConcurrency set, the object is downloaded in parallel to a temp file first, as it is
+// for Read.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	f.mu.Lock()
+	useTempFile := f.tempFile != nil
+	if opts, ok := f.fileSystem.options.(Options); useTempFile || (ok && opts.DownloadConcurrency > 1) {
+		err := f.checkTempFile()
+		f.mu.Unlock()
+		if err != nil {
+			return 0, err
+		}
+		// copy through Read, rather than from the temp file directly, so the lock isn't held while writing to w
+		return io.Copy(w, struct{ io.Reader }{f})
+	}
+	f.mu.Unlock()
+
+	if target, ok := w.(*File); ok && target != f {
+		if copied, err := f.copyInto(target); err != nil {
+			return 0, err
+		} else if copied {
+			size, err := f.Size()
+			return int64(size), err
+		}
+	}
+
+	reader, err := f.getObject()
+	if err != nil {
+		return 0, err
 	}
-	return f.writeBuffer.Write(data)
+	defer func() { _ = reader.Close() }()
+	return io.Copy(w, reader)
+}
+
+// ReadFrom implements the io.ReaderFrom interface so io.Copy writes r straight into the file's write buffer.  If r is
+// an unread s3.File accessible with the same credentials and nothing has been written to this file yet, the object is
+// copied server-side with CopyObject instead, after which the file can't be written to again until it's closed.
+// Otherwise, as with Write, the data is uploaded on Close.
+func (f *File) ReadFrom(r io.Reader) (int64, error) {
+	if f.versionID != "" {
+		return 0, errors.New("unable to write to a specific version of an s3 object")
+	}
+
+	if source, ok := r.(*File); ok && source != f {
+		if copied, err := source.copyInto(f); err != nil {
+			return 0, err
+		} else if copied {
+			size, err := source.Size()
+			return int64(size), err
+		}
+	}
+
+	// initialize the write buffer so an empty r still writes an empty object
+	if _, err := f.Write([]byte{}); err != nil {
+		return 0, err
+	}
+	// copy through Write, which appends to the write buffer (and checksums, if enabled), so the lock isn't held while
+	// reading from r
+	return utils.Copy(struct{ io.Writer }{f}, r)
 }
 
 // Touch creates a zero-length file on the vfs.File if no File exists.  Update File's last modified timestamp.
@@ -273,12 +594,22 @@ func (f *File) Touch() error {
 		}
 	} else {
 		// file already exists so update its last modified date
+		f.invalidateHead()
 		return utils.UpdateLastModifiedByMoving(f)
 	}
 
 	return nil
 }
 
+// Refresh discards any cached HEAD result for the file and re-fetches it from s3.  Size, LastModified and Exists cache
+// the HEAD response on the File to avoid repeated requests; call Refresh when the object may have been changed by
+// something other than this File.  The cache is cleared automatically by writes, deletes, and copies made through it.
+func (f *File) Refresh() error {
+	f.invalidateHead()
+	_, err := f.getHeadObject()
+	return err
+}
+
 // URI returns the File's URI as a string.
 func (f *File) URI() string {
 	return utils.GetFileURI(f)
@@ -292,17 +623,57 @@ func (f *File) String() string {
 /*
 	Private helper functions
 */
+// getHeadObject returns the cached HEAD result for the file, making a HeadObject request if there is none.  Failed
+// requests (including not found) are not cached.  The result may have been filled in from a listing, so only its
+// ContentLength, LastModified, ETag and StorageClass are sure to be set; see getFullHeadObject.
 func (f *File) getHeadObject() (*s3.HeadObjectOutput, error) {
-	headObjectInput := new(s3.HeadObjectInput).SetKey(f.key).SetBucket(f.bucket)
+	return f.headObject(false)
+}
+
+// getFullHeadObject returns the cached HEAD result for the file as getHeadObject does, but makes a HeadObject request
+// in place of one filled in from a listing, for the fields only HEAD returns, ie: VersionId, ContentType and Metadata.
+func (f *File) getFullHeadObject() (*s3.HeadObjectOutput, error) {
+	return f.headObject(true)
+}
+
+func (f *File) headObject(full bool) (*s3.HeadObjectOutput, error) {
+	f.headMu.Lock()
+	defer f.headMu.Unlock()
+	if f.head != nil && !(full && f.headPartial) {
+		return f.head, nil
+	}
+
+	headObjectInput := new(s3.HeadObjectInput).SetKey(keyOf(f.key)).SetBucket(f.bucket)
+	if f.versionID != "" {
+		headObjectInput.SetVersionId(f.versionID)
+	}
 	client, err := f.fileSystem.Client()
 	if err != nil {
 		return nil, err
 	}
-	return client.HeadObject(headObjectInput)
+	head, err := client.HeadObject(headObjectInput)
+	if err != nil {
+		return nil, err
+	}
+	f.head = head
+	f.headPartial = false
+	return head, nil
+}
+
+// invalidateHead clears the cached HEAD result so that the next call re-fetches it.
+func (f *File) invalidateHead() {
+	f.headMu.Lock()
+	f.head = nil
+	f.headPartial = false
+	f.headMu.Unlock()
 }
 
 // For copy from S3-to-S3 when credentials are the same between source and target, return *s3.CopyObjectInput or error
 func (f *File) getCopyObjectInput(targetFile *File) (*s3.CopyObjectInput, error) {
+	if f.streamCopies(targetFile) {
+		return nil, nil
+	}
+
 	//first we must determine if we're using the same s3 credentials for source and target before doing a native copy
 	isSameAccount := false
 	var ACL string
@@ -334,15 +705,22 @@ func (f *File) getCopyObjectInput(targetFile *File) (*s3.CopyObjectInput, error)
 	// first before pushing out to the target file's location.
 	if isSameAccount {
 		//PathEscape ensures we url-encode as required by the API, including double-encoding literals
-		copySourceKey := url.PathEscape(path.Join(f.bucket, f.key))
+		copySourceKey := url.PathEscape(f.bucket + "/" + keyOf(f.key))
+		if f.versionID != "" {
+			copySourceKey += "?versionId=" + url.QueryEscape(f.versionID)
+		}
 
 		copyInput := new(s3.CopyObjectInput).
 			SetServerSideEncryption("AES256").
 			SetACL(ACL).
-			SetKey(targetFile.key).
+			SetKey(keyOf(targetFile.key)).
 			SetBucket(targetFile.bucket).
 			SetCopySource(copySourceKey)
 
+		if targetOpts, ok := targetOptions.(Options); ok {
+			applyObjectLockToCopy(targetOpts, copyInput)
+		}
+
 		//validate copyInput
 		if err := copyInput.Validate(); err != nil {
 			return nil, err
@@ -355,30 +733,103 @@ func (f *File) getCopyObjectInput(targetFile *File) (*s3.CopyObjectInput, error)
 	return nil, nil
 }
 
+// nativeCopy copies the object to target server-side with CopyObject if both use the same credentials, returning false
+// if they don't.
+func (f *File) nativeCopy(target *File) (bool, error) {
+	input, err := f.getCopyObjectInput(target)
+	if err != nil || input == nil {
+		return false, err
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return false, err
+	}
+	_, err = client.CopyObject(input)
+	target.invalidateHead()
+	return true, err
+}
+
+// copyInto copies the object to target server-side with nativeCopy, as for WriteTo and ReadFrom, returning false if
+// the file has been read from, target has been written to, or they don't use the same credentials.  Only one file's
+// lock is held at a time.
+func (f *File) copyInto(target *File) (bool, error) {
+	f.mu.Lock()
+	read := f.tempFile != nil
+	f.mu.Unlock()
+	if read {
+		return false, nil
+	}
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	if target.writeBuffer != nil || target.copiedInto {
+		return false, nil
+	}
+	copied, err := f.nativeCopy(target)
+	if copied && err == nil {
+		target.copiedInto = true
+	}
+	return copied, err
+}
+
+// discardWriteBuffer releases the write buffer, if any, without uploading it.  f.mu must be held.
+func (f *File) discardWriteBuffer() error {
+	f.writeHashes = nil
+	f.flushed = false
+	if f.writeBuffer == nil {
+		return nil
+	}
+	err := f.writeBuffer.Close()
+	f.writeBuffer = nil
+	return err
+}
+
 func (f *File) checkTempFile() error {
 	if f.tempFile == nil {
+		size, err := f.reserveTempSize()
+		if err != nil {
+			return err
+		}
 		localTempFile, err := f.copyToLocalTempReader()
 		if err != nil {
+			f.fileSystem.releaseTemp(size)
 			return err
 		}
 		f.tempFile = localTempFile
+		f.tempSize = size
 	}
 
 	return nil
 }
 
-func (f *File) copyToLocalTempReader() (*os.File, error) {
-	tmpFile, err := ioutil.TempFile("", fmt.Sprintf("%s.%d", f.Name(), time.Now().UnixNano()))
+// reserveTempSize accounts for the temp file the object is about to be downloaded to, returning its size.  The size is
+// only looked up when Options.MaxTempUsage is set.
+func (f *File) reserveTempSize() (int64, error) {
+	opts, ok := f.fileSystem.options.(Options)
+	if !ok || opts.MaxTempUsage <= 0 {
+		return 0, nil
+	}
+	size, err := f.Size()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
+	if err := f.fileSystem.reserveTemp(int64(size), opts.MaxTempUsage); err != nil {
+		return 0, fmt.Errorf("unable to download %s: %s", f, err.Error())
+	}
+	return int64(size), nil
+}
 
-	outputReader, err := f.getObject()
+func (f *File) copyToLocalTempReader() (*os.File, error) {
+	pattern := fmt.Sprintf("%s%s.%d", tempFilePrefix, f.Name(), time.Now().UnixNano())
+	tmpFile, err := ioutil.TempFile(f.fileSystem.tempDir(), pattern)
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err := io.Copy(tmpFile, outputReader); err != nil {
+	if err := f.downloadTo(tmpFile); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
 		return nil, err
 	}
 
@@ -391,8 +842,41 @@ func (f *File) copyToLocalTempReader() (*os.File, error) {
 	return tmpFile, nil
 }
 
+// downloadTo writes the object's contents to the local file.  When Options.DownloadConcurrency is greater than 1, the
+// object is fetched with parallel ranged GETs using s3manager.Downloader, otherwise with a single streamed GET.
+func (f *File) downloadTo(file *os.File) error {
+	if opts, ok := f.fileSystem.options.(Options); ok && opts.DownloadConcurrency > 1 {
+		client, err := f.fileSystem.Client()
+		if err != nil {
+			return err
+		}
+
+		downloader := s3manager.NewDownloaderWithClient(client, func(d *s3manager.Downloader) {
+			d.Concurrency = opts.DownloadConcurrency
+			if opts.DownloadPartSize > 0 {
+				d.PartSize = opts.DownloadPartSize
+			}
+		})
+		_, err = downloader.Download(file, f.getObjectInput())
+		return f.wrapArchivedError(err)
+	}
+
+	outputReader, err := f.getObject()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = outputReader.Close() }()
+
+	_, err = io.Copy(file, outputReader)
+	return err
+}
+
 func (f *File) getObjectInput() *s3.GetObjectInput {
-	return new(s3.GetObjectInput).SetBucket(f.bucket).SetKey(f.key)
+	input := new(s3.GetObjectInput).SetBucket(f.bucket).SetKey(keyOf(f.key))
+	if f.versionID != "" {
+		input.SetVersionId(f.versionID)
+	}
+	return input
 }
 
 func (f *File) getObject() (io.ReadCloser, error) {
@@ -402,7 +886,7 @@ func (f *File) getObject() (io.ReadCloser, error) {
 	}
 	getOutput, err := client.GetObject(f.getObjectInput())
 	if err != nil {
-		return nil, err
+		return nil, f.wrapArchivedError(err)
 	}
 
 	return getOutput.Body, nil
@@ -413,7 +897,7 @@ func uploadInput(f *File) *s3manager.UploadInput {
 	sseType := "AES256"
 	input := &s3manager.UploadInput{
 		Bucket:               &f.bucket,
-		Key:                  &f.key,
+		Key:                  aws.String(keyOf(f.key)),
 		ServerSideEncryption: &sseType,
 	}
 
@@ -425,45 +909,8 @@ func uploadInput(f *File) *s3manager.UploadInput {
 		if opts.ACL != "" {
 			input.ACL = &opts.ACL
 		}
+		applyObjectLockToUpload(opts, input)
 	}
 
 	return input
 }
-
-//WaitUntilFileExists attempts to ensure that a recently written file is available before moving on.  This is helpful for
-// attempting to overcome race conditions withe S3's "eventual consistency".
-// WaitUntilFileExists accepts vfs.File and an int representing the number of times to retry(once a second).
-// error is returned if the file is still not available after the specified retries.
-// nil is returned once the file is available.
-func waitUntilFileExists(file vfs.File, retries int) error {
-	// Ignore in-memory VFS files
-	if _, ok := file.(*mocks.ReadWriteFile); ok {
-		return nil
-	}
-
-	// Return as if file was found when retries is set to -1. Useful mainly for testing.
-	if retries == -1 {
-		return nil
-	}
-	var retryCount = 0
-	for {
-		if retryCount == retries {
-			return fmt.Errorf("failed to find file %s after %d retries", file, retries)
-		}
-
-		//check for existing file
-		found, err := file.Exists()
-		if err != nil {
-			return fmt.Errorf("unable to perform S3 exists on file %s: %s", file, err.Error())
-		}
-
-		if found {
-			break
-		}
-
-		retryCount++
-		time.Sleep(time.Second * 1)
-	}
-
-	return nil
-}
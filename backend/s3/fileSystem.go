@@ -4,9 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"strings"
 
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3control/s3controliface"
 	"github.com/c2fo/vfs/v5"
 	"github.com/c2fo/vfs/v5/backend"
 	"github.com/c2fo/vfs/v5/utils"
@@ -18,8 +21,12 @@ const name = "AWS S3"
 
 // FileSystem implements vfs.FileSystem for the S3 file system.
 type FileSystem struct {
-	client  s3iface.S3API
-	options vfs.Options
+	// tempUsage is the total size of temp files currently held by the file system's files, see Options.MaxTempUsage.
+	// It's accessed atomically so is kept first for 64-bit alignment.
+	tempUsage int64
+	client    s3iface.S3API
+	control   s3controliface.S3ControlAPI
+	options   vfs.Options
 }
 
 // Retry will return the default no-op retrier. The S3 client provides its own retryer interface, and is available
@@ -36,6 +43,13 @@ func (fs *FileSystem) NewFile(volume string, name string) (vfs.File, error) {
 	if volume == "" || name == "" {
 		return nil, errors.New("non-empty strings for bucket and key are required")
 	}
+	if !fs.normalizeKeys() {
+		// a trailing slash is allowed for folder markers
+		if !strings.HasPrefix(name, "/") {
+			return nil, errors.New(utils.ErrBadAbsFilePath)
+		}
+		return &File{fileSystem: fs, bucket: utils.RemoveTrailingSlash(volume), key: name}, nil
+	}
 	if err := utils.ValidateAbsoluteFilePath(name); err != nil {
 		return nil, err
 	}
@@ -59,9 +73,13 @@ func (fs *FileSystem) NewLocation(volume string, name string) (vfs.Location, err
 		return nil, err
 	}
 
+	prefix := name
+	if fs.normalizeKeys() {
+		prefix = utils.EnsureTrailingSlash(path.Clean(name))
+	}
 	return &Location{
 		fileSystem: fs,
-		prefix:     utils.EnsureTrailingSlash(path.Clean(name)),
+		prefix:     prefix,
 		bucket:     utils.RemoveTrailingSlash(volume),
 	}, nil
 }
@@ -105,20 +123,41 @@ func (fs *FileSystem) WithOptions(opts vfs.Options) *FileSystem {
 		fs.options = opts
 		//we set client to nil to ensure that a new client is created using the new context when Client() is called
 		fs.client = nil
+		fs.control = nil
 	}
 	return fs
 }
 
-// WithClient passes in an s3 client and returns the file system (chainable)
+// WithClient passes in an s3 client and returns the file system (chainable).  A nil client is ignored.
 func (fs *FileSystem) WithClient(client interface{}) *FileSystem {
-	switch client.(type) {
-	case s3iface.S3API, *s3.S3:
-		fs.client = client.(s3iface.S3API)
+	switch c := client.(type) {
+	case *s3.S3:
+		if c == nil {
+			return fs
+		}
+		fs.client = c
+		fs.control = nil
+		fs.options = nil
+	case s3iface.S3API:
+		fs.client = c
+		fs.control = nil
 		fs.options = nil
 	}
 	return fs
 }
 
+// WithSession sets the aws session the client is created from (see Options.Session), keeping any other options, and
+// returns the file system (chainable).  The client isn't created until it's first used, so the file system can be
+// configured before the session is known to be valid.  A nil session is ignored.
+func (fs *FileSystem) WithSession(sess *session.Session) *FileSystem {
+	if sess == nil {
+		return fs
+	}
+	opts, _ := fs.options.(Options)
+	opts.Session = sess
+	return fs.WithOptions(opts)
+}
+
 // NewFileSystem initializer for FileSystem struct accepts aws-sdk s3iface.S3API client and returns Filesystem or error.
 func NewFileSystem() *FileSystem {
 	return &FileSystem{}
@@ -127,4 +166,5 @@ func NewFileSystem() *FileSystem {
 func init() {
 	//registers a default FileSystem
 	backend.Register(Scheme, NewFileSystem())
+	vfs.RegisterErrorClassifier(classifyError)
 }
@@ -1,8 +1,11 @@
 package s3
 
 import (
+	"net/http"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/stretchr/testify/suite"
@@ -119,6 +122,59 @@ func (ts *fileSystemTestSuite) TestClient() {
 
 }
 
+func (ts *fileSystemTestSuite) TestWithClient() {
+	var nilClient *s3.S3
+	fs := NewFileSystem().WithOptions(Options{Region: "us-east-1"}).WithClient(nilClient)
+	ts.Nil(fs.client, "nil client is ignored")
+	ts.NotNil(fs.options, "options are kept")
+
+	fs = fs.WithClient(s3apiMock)
+	ts.Equal(s3apiMock, fs.client)
+	ts.Nil(fs.options, "options don't apply to a client passed in")
+}
+
+func (ts *fileSystemTestSuite) TestWithSession() {
+	httpClient := &http.Client{}
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("eu-west-1"),
+		HTTPClient:  httpClient,
+		Credentials: credentials.NewStaticCredentials("SESSION", "secret", ""),
+	}))
+
+	fs := NewFileSystem().WithOptions(Options{ACL: "private"}).WithSession(sess)
+	ts.Nil(fs.client, "client isn't created until it's used")
+	ts.Equal("private", fs.options.(Options).ACL, "other options are kept")
+
+	client, err := fs.Client()
+	ts.NoError(err)
+	config := client.(*s3.S3).Config
+	ts.Equal(httpClient, config.HTTPClient, "session's http client is used")
+	ts.Equal("eu-west-1", aws.StringValue(config.Region))
+	value, err := config.Credentials.Get()
+	ts.NoError(err)
+	ts.Equal("SESSION", value.AccessKeyID, "session's credentials are used")
+
+	// options set on top of the session
+	fs = NewFileSystem().WithOptions(Options{
+		Region:          "us-east-2",
+		AccessKeyID:     "OPTIONS",
+		SecretAccessKey: "secret",
+		ForcePathStyle:  true,
+		Session:         sess,
+	})
+	client, err = fs.Client()
+	ts.NoError(err)
+	config = client.(*s3.S3).Config
+	ts.Equal(httpClient, config.HTTPClient)
+	ts.Equal("us-east-2", aws.StringValue(config.Region))
+	ts.True(aws.BoolValue(config.S3ForcePathStyle))
+	value, err = config.Credentials.Get()
+	ts.NoError(err)
+	ts.Equal("OPTIONS", value.AccessKeyID)
+
+	ts.Nil(NewFileSystem().WithSession(nil).options, "nil session is ignored")
+}
+
 func TestFileSystem(t *testing.T) {
 	suite.Run(t, new(fileSystemTestSuite))
 }
@@ -3,12 +3,16 @@ package s3
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -71,6 +75,35 @@ func (ts *fileTestSuite) TestRead() {
 	ts.Equal(localFile.String(), contents, "Copying an s3 file to a buffer should fill buffer with file's contents")
 }
 
+func (ts *fileTestSuite) TestRead_ParallelDownload() {
+	contents := "hello world!"
+	s3apiMock.On("GetObjectWithContext", mock.Anything, mock.AnythingOfType("*s3.GetObjectInput"), mock.Anything).Return(
+		func(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) *s3.GetObjectOutput {
+			var start, end int
+			_, _ = fmt.Sscanf(*input.Range, "bytes=%d-%d", &start, &end)
+			if end >= len(contents) {
+				end = len(contents) - 1
+			}
+			return &s3.GetObjectOutput{
+				Body:         nopCloser{bytes.NewBufferString(contents[start : end+1])},
+				ContentRange: aws.String(fmt.Sprintf("bytes %d-%d/%d", start, end, len(contents))),
+			}
+		}, nil)
+	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+
+	parallelFs := FileSystem{client: s3apiMock, options: Options{DownloadConcurrency: 3, DownloadPartSize: 4}}
+	file, err := parallelFs.NewFile("bucket", "/some/path/file.txt")
+	ts.NoError(err)
+
+	var localFile = bytes.NewBuffer([]byte{})
+	_, copyErr := io.Copy(localFile, file)
+	ts.NoError(copyErr, "no error expected")
+	ts.NoError(file.Close(), "no error expected")
+
+	ts.Equal(contents, localFile.String(), "parallel ranged download should assemble the file's contents")
+	s3apiMock.AssertNumberOfCalls(ts.T(), "GetObjectWithContext", 3)
+}
+
 // TODO: Write on Close() (actual s3 calls wait until file is closed to be made.)
 func (ts *fileTestSuite) TestWrite() {
 	file, err := fs.NewFile("bucket", "/tmp/hello.txt")
@@ -204,6 +237,60 @@ func (ts *fileTestSuite) TestMoveToFile() {
 	s3apiMock.AssertExpectations(ts.T())
 }
 
+func (ts *fileTestSuite) TestRename() {
+	s3apiMock.On("CopyObject", mock.AnythingOfType("*s3.CopyObjectInput")).Return(&s3.CopyObjectOutput{}, nil)
+	s3apiMock.On("DeleteObject", mock.AnythingOfType("*s3.DeleteObjectInput")).Return(&s3.DeleteObjectOutput{}, nil)
+	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+
+	renamed, err := testFile.(*File).Rename("file.txt.done")
+	ts.NoError(err, "no error expected")
+	ts.Equal("s3://bucket/some/path/to/file.txt.done", renamed.URI())
+	s3apiMock.AssertCalled(ts.T(), "CopyObject", mock.MatchedBy(func(input *s3.CopyObjectInput) bool {
+		return aws.StringValue(input.Key) == "some/path/to/file.txt.done" &&
+			aws.StringValue(input.CopySource) == "bucket%2Fsome%2Fpath%2Fto%2Ffile.txt"
+	}))
+	s3apiMock.AssertCalled(ts.T(), "DeleteObject", mock.MatchedBy(func(input *s3.DeleteObjectInput) bool {
+		return aws.StringValue(input.Key) == "some/path/to/file.txt"
+	}))
+
+	_, err = testFile.(*File).Rename("")
+	ts.EqualError(err, "non-empty string newName is required")
+	_, err = testFile.(*File).Rename("done/file.txt")
+	ts.EqualError(err, `newName "done/file.txt" must be a file name, not a path`)
+}
+
+func (ts *fileTestSuite) TestMoveToFile_VerificationFailed() {
+	contents := "hello world"
+	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(int64(len(contents)))}, nil)
+	s3apiMock.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).Return(&s3.GetObjectOutput{
+		Body: nopCloser{bytes.NewBufferString(contents)},
+	}, nil)
+
+	// target on another scheme that comes up short
+	mockFs := &mocks.FileSystem{}
+	mockFs.On("Scheme").Return("mock")
+	mockLocation := &mocks.Location{}
+	mockLocation.On("FileSystem").Return(mockFs)
+	targetFile := &mocks.File{}
+	targetFile.On("Location").Return(mockLocation)
+	targetFile.On("Write", mock.Anything).Return(len(contents), nil)
+	targetFile.On("Close").Return(nil)
+	targetFile.On("Size").Return(uint64(5), nil)
+	targetFile.On("String").Return("mock:///file.txt")
+
+	err := testFile.MoveToFile(targetFile)
+	ts.Error(err)
+	ts.Contains(err.Error(), "failed verification")
+	s3apiMock.AssertNotCalled(ts.T(), "DeleteObject", mock.Anything)
+
+	// verification disabled
+	testFile.(*File).fileSystem.options = Options{DisableMoveVerification: true}
+	s3apiMock.On("DeleteObject", mock.AnythingOfType("*s3.DeleteObjectInput")).Return(&s3.DeleteObjectOutput{}, nil)
+	ts.NoError(testFile.MoveToFile(targetFile))
+	s3apiMock.AssertCalled(ts.T(), "DeleteObject", mock.Anything)
+}
+
 func (ts *fileTestSuite) TestGetCopyObject() {
 	type getCopyObjectTest struct {
 		key, expectedCopySource string
@@ -459,6 +546,43 @@ func (ts *fileTestSuite) TestMoveToLocationFail() {
 	location.AssertExpectations(ts.T())
 }
 
+func (ts *fileTestSuite) TestMoveToLocation_ConflictSkip() {
+	// the target already exists, so nothing should be copied and the source shouldn't be deleted
+	targetClient := &mocks.S3API{}
+	targetClient.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+	target := &File{fileSystem: &FileSystem{client: targetClient}, bucket: "bucket", key: "/new/hello.txt"}
+	location := new(mocks.Location)
+	location.On("NewFile", "hello.txt").Return(target, nil)
+
+	skipFs := FileSystem{client: s3apiMock, options: Options{ConflictPolicy: vfs.ConflictSkip}}
+	file, err := skipFs.NewFile("bucket", "/hello.txt")
+	ts.NoError(err)
+
+	moved, err := file.MoveToLocation(location)
+	ts.NoError(err, "no error expected")
+	ts.Equal(target, moved, "existing file should be returned")
+
+	s3apiMock.AssertNotCalled(ts.T(), "CopyObject", mock.Anything)
+	s3apiMock.AssertNotCalled(ts.T(), "DeleteObject", mock.Anything)
+	location.AssertExpectations(ts.T())
+}
+
+func (ts *fileTestSuite) TestCopyToLocation_ConflictError() {
+	targetClient := &mocks.S3API{}
+	targetClient.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+	location := new(mocks.Location)
+	location.On("NewFile", "hello.txt").
+		Return(&File{fileSystem: &FileSystem{client: targetClient}, bucket: "bucket", key: "/new/hello.txt"}, nil)
+
+	errorFs := FileSystem{client: s3apiMock, options: Options{ConflictPolicy: vfs.ConflictError}}
+	file, err := errorFs.NewFile("bucket", "/hello.txt")
+	ts.NoError(err)
+
+	_, err = file.CopyToLocation(location)
+	ts.Equal(vfs.ErrFileExists, err)
+	s3apiMock.AssertNotCalled(ts.T(), "CopyObject", mock.Anything)
+}
+
 func (ts *fileTestSuite) TestDelete() {
 	s3apiMock.On("DeleteObject", mock.AnythingOfType("*s3.DeleteObjectInput")).Return(&s3.DeleteObjectOutput{}, nil)
 	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
@@ -467,6 +591,50 @@ func (ts *fileTestSuite) TestDelete() {
 	s3apiMock.AssertExpectations(ts.T())
 }
 
+func (ts *fileTestSuite) TestCloseTwice() {
+	s3apiMock.On("PutObjectRequest", mock.AnythingOfType("*s3.PutObjectInput")).
+		Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}}, &s3.PutObjectOutput{}).Once()
+	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil).Once()
+	_, err := testFile.Write([]byte("hello world"))
+	ts.NoError(err)
+	ts.NoError(testFile.Close())
+	ts.NoError(testFile.Close(), "closing again is a no-op")
+	s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *fileTestSuite) TestAbort() {
+	_, err := testFile.Write([]byte("hello world"))
+	ts.NoError(err)
+	ts.NoError(testFile.(*File).Abort())
+	ts.NoError(testFile.Close(), "nothing is left to upload")
+	s3apiMock.AssertNotCalled(ts.T(), "PutObjectRequest", mock.Anything)
+	s3apiMock.AssertNotCalled(ts.T(), "DeleteObject", mock.Anything)
+	s3apiMock.AssertNotCalled(ts.T(), "HeadObject", mock.Anything)
+}
+
+func (ts *fileTestSuite) TestFlush() {
+	var uploads []string
+	s3apiMock.On("PutObjectRequest", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		body, _ := ioutil.ReadAll(input.Body)
+		_, _ = input.Body.Seek(0, io.SeekStart)
+		uploads = append(uploads, string(body))
+		return true
+	})).Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}}, &s3.PutObjectOutput{}).Twice()
+	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+
+	ts.NoError(testFile.(*File).Flush(), "nothing to flush")
+	_, err := testFile.Write([]byte("hello "))
+	ts.NoError(err)
+	ts.NoError(testFile.(*File).Flush())
+	ts.NoError(testFile.(*File).Flush(), "nothing written since the last flush")
+	_, err = testFile.Write([]byte("world"))
+	ts.NoError(err)
+	ts.NoError(testFile.Close())
+
+	ts.Equal([]string{"hello ", "hello world"}, uploads)
+	s3apiMock.AssertExpectations(ts.T())
+}
+
 func (ts *fileTestSuite) TestLastModified() {
 	now := time.Now()
 	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{
@@ -502,6 +670,84 @@ func (ts *fileTestSuite) TestSize() {
 	s3apiMock.AssertExpectations(ts.T())
 }
 
+func (ts *fileTestSuite) TestStat() {
+	lastModified := time.Now()
+	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{
+		ContentLength:   aws.Int64(100),
+		LastModified:    &lastModified,
+		ContentType:     aws.String("text/plain"),
+		ContentEncoding: aws.String("gzip"),
+		ETag:            aws.String(`"d41d8cd98f00b204e9800998ecf8427e"`),
+	}, nil).Once()
+
+	info, err := testFile.(*File).Stat()
+	ts.NoError(err)
+	ts.Equal("file.txt", info.Name())
+	ts.Equal(int64(100), info.Size())
+	ts.Equal(lastModified, info.ModTime())
+	ts.Equal(os.FileMode(0644), info.Mode())
+	ts.False(info.IsDir())
+	ts.Equal("text/plain", info.ContentType)
+	ts.Equal("gzip", info.ContentEncoding)
+	ts.Equal("d41d8cd98f00b204e9800998ecf8427e", info.ETag)
+	ts.Equal(s3.StorageClassStandard, info.StorageClass)
+
+	// FileInfo can be used wherever an os.FileInfo is expected
+	var osInfo os.FileInfo = info
+	ts.Equal("file.txt", osInfo.Name())
+
+	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(nil, awserr.New("NotFound", "file does not exist", nil))
+	ts.Error(testFile.(*File).Refresh())
+	_, err = testFile.(*File).Stat()
+	ts.Error(err, "stat of a file that doesn't exist is an error")
+}
+
+func (ts *fileTestSuite) TestHeadCaching() {
+	contentLength := int64(100)
+	lastModified := time.Now()
+	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{
+		ContentLength: &contentLength,
+		LastModified:  &lastModified,
+	}, nil)
+
+	exists, err := testFile.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+	size, err := testFile.Size()
+	ts.NoError(err)
+	ts.Equal(uint64(100), size)
+	modTime, err := testFile.LastModified()
+	ts.NoError(err)
+	ts.Equal(lastModified, *modTime)
+	s3apiMock.AssertNumberOfCalls(ts.T(), "HeadObject", 1)
+
+	// Refresh re-fetches
+	ts.NoError(testFile.(*File).Refresh())
+	_, err = testFile.Size()
+	ts.NoError(err)
+	s3apiMock.AssertNumberOfCalls(ts.T(), "HeadObject", 2)
+
+	// writes invalidate the cache
+	s3apiMock.On("PutObjectRequest", mock.AnythingOfType("*s3.PutObjectInput")).Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}}, &s3.PutObjectOutput{})
+	_, err = testFile.Write([]byte("new contents"))
+	ts.NoError(err)
+	ts.NoError(testFile.Close())
+	s3apiMock.AssertNumberOfCalls(ts.T(), "HeadObject", 3)
+}
+
+func (ts *fileTestSuite) TestHeadCaching_NotFoundNotCached() {
+	s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(nil, awserr.New("NotFound", "file does not exist", errors.New("file not found")))
+
+	for i := 0; i < 2; i++ {
+		exists, err := testFile.Exists()
+		ts.NoError(err)
+		ts.False(exists)
+	}
+	s3apiMock.AssertNumberOfCalls(ts.T(), "HeadObject", 2)
+}
+
 func (ts *fileTestSuite) TestPath() {
 	ts.Equal("/some/path/to/file.txt", testFile.Path(), "Should return file.key (with leading slash)")
 }
@@ -524,7 +770,7 @@ func (ts *fileTestSuite) TestUploadInput() {
 	fs = FileSystem{client: &mocks.S3API{}}
 	file, _ := fs.NewFile("mybucket", "/some/file/test.txt")
 	ts.Equal("AES256", *uploadInput(file.(*File)).ServerSideEncryption, "sse was set")
-	ts.Equal("/some/file/test.txt", *uploadInput(file.(*File)).Key, "key was set")
+	ts.Equal("some/file/test.txt", *uploadInput(file.(*File)).Key, "key was set without the leading slash of its path")
 	ts.Equal("mybucket", *uploadInput(file.(*File)).Bucket, "bucket was set")
 }
 
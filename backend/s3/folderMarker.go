@@ -0,0 +1,43 @@
+package s3
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// folderMarkerSuffix ends the keys of the zero-byte objects Hadoop's s3n and EMR write to mark a folder, ie:
+// "path/to_$folder$" for the folder "path/to/".
+const folderMarkerSuffix = "_$folder$"
+
+// markedFolder returns the key prefix of the folder marked by a listed object and true if it's a folder marker: a
+// zero-byte object whose key ends in "/" or "_$folder$" or, with Options.FolderMarkersAsLocations, any key ending in
+// "/".
+func (fs *FileSystem) markedFolder(object *s3.Object) (string, bool) {
+	opts, _ := fs.options.(Options)
+	key := aws.StringValue(object.Key)
+	zeroByte := aws.Int64Value(object.Size) == 0
+	switch {
+	case strings.HasSuffix(key, "/") && (zeroByte || opts.FolderMarkersAsLocations):
+		return key, true
+	case strings.HasSuffix(key, folderMarkerSuffix) && zeroByte:
+		return strings.TrimSuffix(key, folderMarkerSuffix) + "/", true
+	}
+	return "", false
+}
+
+// listedAsFile returns true if a listed object is listed as a file of the location with the key prefix prefix: it
+// isn't the location's own folder marker, and isn't a folder marker left out by Options.ExcludeFolderMarkers or
+// Options.FolderMarkersAsLocations.
+func (fs *FileSystem) listedAsFile(object *s3.Object, prefix string) bool {
+	if aws.StringValue(object.Key) == prefix {
+		return false
+	}
+	opts, _ := fs.options.(Options)
+	if !opts.ExcludeFolderMarkers && !opts.FolderMarkersAsLocations {
+		return true
+	}
+	_, marker := fs.markedFolder(object)
+	return !marker
+}
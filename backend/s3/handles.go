@@ -0,0 +1,128 @@
+package s3
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// errHandleClosed is returned when using a reader or writer handle after it's been closed.
+var errHandleClosed = errors.New("s3 file handle is already closed")
+
+// OpenReader downloads the object to a temp file of its own and returns a reader of it, independent of the File's own
+// Read and Seek cursor and of any other readers.  Closing the reader removes its temp file.  As for Read, the temp file
+// is created in Options.TempDir and counts towards Options.MaxTempUsage while open.  A reader isn't safe for concurrent
+// use, though separate readers may be used concurrently.
+func (f *File) OpenReader() (vfs.ReadSeekCloser, error) {
+	size, err := f.reserveTempSize()
+	if err != nil {
+		return nil, err
+	}
+	tempFile, err := f.copyToLocalTempReader()
+	if err != nil {
+		f.fileSystem.releaseTemp(size)
+		return nil, err
+	}
+	return &reader{fileSystem: f.fileSystem, tempFile: tempFile, tempSize: size}, nil
+}
+
+// OpenWriter returns a writer which buffers what's written to it, independent of the File's own Write buffer and of any
+// other writers, and replaces the object with it on Close.  As for Write, writes beyond Options.MaxWriteBufferMemory
+// spill to a temp file, and checksums are available from File.Checksums after Close if Options.ComputeChecksums is
+// set.  A writer isn't safe for concurrent use.
+func (f *File) OpenWriter() (io.WriteCloser, error) {
+	if f.versionID != "" {
+		return nil, errors.New("unable to write to a specific version of an s3 object")
+	}
+
+	opts, _ := f.fileSystem.options.(Options)
+	w := &writer{
+		file:   f,
+		buffer: utils.NewSpillBuffer(opts.MaxWriteBufferMemory, f.fileSystem.tempDir()),
+	}
+	if opts.ComputeChecksums || opts.VerifyWrites {
+		w.hashes = newWriteHashes()
+	}
+	return w, nil
+}
+
+// reader is the handle returned by File.OpenReader.
+type reader struct {
+	fileSystem *FileSystem
+	tempFile   *os.File
+	tempSize   int64
+}
+
+// Read implements io.Reader.
+func (r *reader) Read(p []byte) (int, error) {
+	if r.tempFile == nil {
+		return 0, errHandleClosed
+	}
+	return r.tempFile.Read(p)
+}
+
+// Seek implements io.Seeker.
+func (r *reader) Seek(offset int64, whence int) (int64, error) {
+	if r.tempFile == nil {
+		return 0, errHandleClosed
+	}
+	return r.tempFile.Seek(offset, whence)
+}
+
+// Close closes and removes the reader's temp file.
+func (r *reader) Close() error {
+	if r.tempFile == nil {
+		return errHandleClosed
+	}
+	tempFile := r.tempFile
+	r.tempFile = nil
+	r.fileSystem.releaseTemp(r.tempSize)
+
+	closeErr := tempFile.Close()
+	if err := os.Remove(tempFile.Name()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return closeErr
+}
+
+// writer is the handle returned by File.OpenWriter.
+type writer struct {
+	file   *File
+	buffer *utils.SpillBuffer
+	hashes *writeHashes
+}
+
+// Write implements io.Writer.
+func (w *writer) Write(p []byte) (int, error) {
+	if w.buffer == nil {
+		return 0, errHandleClosed
+	}
+	n, err := w.buffer.Write(p)
+	if w.hashes != nil {
+		_, _ = w.hashes.Write(p[:n])
+	}
+	return n, err
+}
+
+// Close uploads everything written to the writer, replacing the object.
+func (w *writer) Close() error {
+	if w.buffer == nil {
+		return errHandleClosed
+	}
+	buffer := w.buffer
+	w.buffer = nil
+	defer func() { _ = buffer.Close() }()
+
+	if err := w.file.upload(buffer, w.hashes); err != nil {
+		return err
+	}
+	if w.hashes != nil {
+		w.file.mu.Lock()
+		w.file.checksums = w.hashes.checksums()
+		w.file.mu.Unlock()
+	}
+	return w.file.waitForWrite()
+}
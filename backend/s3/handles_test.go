@@ -0,0 +1,97 @@
+package s3
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type handlesTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	file      *File
+}
+
+func (ts *handlesTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	fs := &FileSystem{client: ts.s3apiMock, options: Options{AccessKeyID: "abc"}}
+	file, err := fs.NewFile("bucket", "/file.txt")
+	ts.NoError(err)
+	ts.file = file.(*File)
+}
+
+func (ts *handlesTestSuite) TestOpenReader() {
+	ts.s3apiMock.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).Return(
+		func(*s3.GetObjectInput) *s3.GetObjectOutput {
+			return &s3.GetObjectOutput{Body: nopCloser{bytes.NewBufferString("hello world")}}
+		}, nil).Twice()
+
+	first, err := ts.file.OpenReader()
+	ts.NoError(err)
+	second, err := ts.file.OpenReader()
+	ts.NoError(err)
+
+	// each reader has its own cursor
+	_, err = first.Seek(6, io.SeekStart)
+	ts.NoError(err)
+	data, err := ioutil.ReadAll(first)
+	ts.NoError(err)
+	ts.Equal("world", string(data))
+	data, err = ioutil.ReadAll(second)
+	ts.NoError(err)
+	ts.Equal("hello world", string(data))
+
+	ts.NoError(first.Close())
+	ts.NoError(second.Close())
+	ts.Equal(errHandleClosed, first.Close())
+	_, err = first.Read(make([]byte, 1))
+	ts.Equal(errHandleClosed, err)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *handlesTestSuite) TestOpenWriter() {
+	ts.s3apiMock.On("PutObjectRequest", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		data, _ := ioutil.ReadAll(input.Body)
+		return string(data) == "from writer"
+	})).Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}},
+		&s3.PutObjectOutput{}).Once()
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+
+	w, err := ts.file.OpenWriter()
+	ts.NoError(err)
+	_, err = w.Write([]byte("from "))
+	ts.NoError(err)
+
+	// the file's own write buffer is unaffected by the writer
+	_, err = ts.file.Write([]byte("from file"))
+	ts.NoError(err)
+
+	_, err = w.Write([]byte("writer"))
+	ts.NoError(err)
+	ts.NoError(w.Close())
+	ts.Equal(errHandleClosed, w.Close())
+	_, err = w.Write([]byte("more"))
+	ts.Equal(errHandleClosed, err)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *handlesTestSuite) TestOpenWriter_Version() {
+	versioned, err := ts.file.WithVersion("v1")
+	ts.NoError(err)
+	_, err = versioned.OpenWriter()
+	ts.Error(err)
+}
+
+func TestHandles(t *testing.T) {
+	suite.Run(t, new(handlesTestSuite))
+}
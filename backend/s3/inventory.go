@@ -0,0 +1,195 @@
+package s3
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// inventoryFormatCSV is the only S3 Inventory report format that can be listed from.  ORC and Parquet reports would
+// need decoders this module doesn't depend on.
+const inventoryFormatCSV = "CSV"
+
+// inventoryManifest is the manifest.json written with each S3 Inventory report, listing its data files.
+type inventoryManifest struct {
+	SourceBucket      string `json:"sourceBucket"`
+	DestinationBucket string `json:"destinationBucket"`
+	FileFormat        string `json:"fileFormat"`
+	FileSchema        string `json:"fileSchema"`
+	Files             []struct {
+		Key string `json:"key"`
+	} `json:"files"`
+}
+
+// inventoryReader reads the objects listed in an S3 Inventory report, one data file at a time.  Delete markers and
+// noncurrent versions, which are listed by reports that include all versions, are skipped.
+type inventoryReader struct {
+	fs      *FileSystem
+	bucket  string
+	files   []string
+	columns map[string]int
+	body    io.ReadCloser
+	rows    *csv.Reader
+}
+
+// openInventory reads the manifest of an S3 Inventory report at manifestURI, ie:
+// "s3://inventory-bucket/prefix/source-bucket/config-id/2006-01-02T15-04Z/manifest.json", returning a reader of the
+// objects it lists.  An error is returned if the report isn't of sourceBucket or isn't in CSV format.
+func openInventory(fs *FileSystem, manifestURI, sourceBucket string) (*inventoryReader, error) {
+	u, err := url.Parse(manifestURI)
+	if err != nil || u.Scheme != Scheme || u.Host == "" || keyOf(u.Path) == "" {
+		return nil, fmt.Errorf("invalid inventory manifest URI %q", manifestURI)
+	}
+	client, err := fs.Client()
+	if err != nil {
+		return nil, err
+	}
+	output, err := client.GetObject(new(s3.GetObjectInput).SetBucket(u.Host).SetKey(keyOf(u.Path)))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = output.Body.Close() }()
+
+	var manifest inventoryManifest
+	if err := json.NewDecoder(output.Body).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("unable to read inventory manifest %s: %s", manifestURI, err.Error())
+	}
+	if manifest.SourceBucket != sourceBucket {
+		return nil, fmt.Errorf("inventory manifest %s is of bucket %s, not %s", manifestURI, manifest.SourceBucket,
+			sourceBucket)
+	}
+	if !strings.EqualFold(manifest.FileFormat, inventoryFormatCSV) {
+		return nil, fmt.Errorf("inventory manifest %s is in %s format, but only CSV can be listed", manifestURI,
+			manifest.FileFormat)
+	}
+
+	r := &inventoryReader{
+		fs:      fs,
+		bucket:  strings.TrimPrefix(manifest.DestinationBucket, "arn:aws:s3:::"),
+		columns: map[string]int{},
+	}
+	if r.bucket == "" {
+		r.bucket = u.Host
+	}
+	for i, column := range strings.Split(manifest.FileSchema, ",") {
+		r.columns[strings.TrimSpace(column)] = i
+	}
+	if _, ok := r.columns["Key"]; !ok {
+		return nil, fmt.Errorf("inventory manifest %s has no Key column", manifestURI)
+	}
+	for _, file := range manifest.Files {
+		r.files = append(r.files, file.Key)
+	}
+	return r, nil
+}
+
+// next returns the next object listed, or io.EOF once all have been.
+func (r *inventoryReader) next() (*s3.Object, error) {
+	for {
+		if r.rows == nil {
+			if len(r.files) == 0 {
+				return nil, io.EOF
+			}
+			if err := r.openFile(r.files[0]); err != nil {
+				return nil, err
+			}
+			r.files = r.files[1:]
+		}
+
+		row, err := r.rows.Read()
+		if err == io.EOF {
+			r.close()
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if r.value(row, "IsDeleteMarker") == "true" || r.value(row, "IsLatest") == "false" {
+			continue
+		}
+		return r.object(row)
+	}
+}
+
+// close closes the data file being read, if any.
+func (r *inventoryReader) close() {
+	if r.body != nil {
+		_ = r.body.Close()
+	}
+	r.body = nil
+	r.rows = nil
+}
+
+// openFile opens the gzipped CSV data file at key in the report's destination bucket.
+func (r *inventoryReader) openFile(key string) error {
+	client, err := r.fs.Client()
+	if err != nil {
+		return err
+	}
+	output, err := client.GetObject(new(s3.GetObjectInput).SetBucket(r.bucket).SetKey(key))
+	if err != nil {
+		return err
+	}
+	gz, err := gzip.NewReader(output.Body)
+	if err != nil {
+		_ = output.Body.Close()
+		return fmt.Errorf("unable to read inventory file s3://%s/%s: %s", r.bucket, key, err.Error())
+	}
+	r.body = output.Body
+	r.rows = csv.NewReader(gz)
+	r.rows.FieldsPerRecord = -1
+	r.rows.ReuseRecord = true
+	return nil
+}
+
+// value returns the value of a row's column, or "" if the report doesn't include it.
+func (r *inventoryReader) value(row []string, column string) string {
+	if i, ok := r.columns[column]; ok && i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+// object returns the object listed by a row, with the size, last modified time, ETag and storage class the report
+// includes.
+func (r *inventoryReader) object(row []string) (*s3.Object, error) {
+	// keys are URL encoded in CSV reports
+	key, err := url.QueryUnescape(r.value(row, "Key"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key %q in inventory: %s", r.value(row, "Key"), err.Error())
+	}
+	if key == "" {
+		return nil, errors.New("inventory lists an object without a key")
+	}
+	object := new(s3.Object).SetKey(key)
+	if size := r.value(row, "Size"); size != "" {
+		n, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size %q of %s in inventory", size, key)
+		}
+		object.SetSize(n)
+	}
+	if modified := r.value(row, "LastModifiedDate"); modified != "" {
+		t, err := time.Parse(time.RFC3339, modified)
+		if err != nil {
+			return nil, fmt.Errorf("invalid last modified date %q of %s in inventory", modified, key)
+		}
+		object.SetLastModified(t)
+	}
+	if etag := r.value(row, "ETag"); etag != "" {
+		object.SetETag(`"` + strings.Trim(etag, `"`) + `"`)
+	}
+	if storageClass := r.value(row, "StorageClass"); storageClass != "" {
+		object.SetStorageClass(storageClass)
+	}
+	return object, nil
+}
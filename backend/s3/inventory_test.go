@@ -0,0 +1,151 @@
+package s3
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+const manifestURI = "s3://inventory/reports/bucket/daily/2020-01-02T01-00Z/manifest.json"
+
+type inventoryTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	loc       *Location
+}
+
+func (ts *inventoryTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	fs := &FileSystem{client: ts.s3apiMock}
+	loc, err := fs.NewLocation("bucket", "/dir1/")
+	ts.NoError(err)
+	ts.loc = loc.(*Location)
+}
+
+func (ts *inventoryTestSuite) object(bucket, key string, body []byte) {
+	ts.s3apiMock.On("GetObject", &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)}).
+		Return(func(*s3.GetObjectInput) *s3.GetObjectOutput {
+			return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(body))}
+		}, nil)
+}
+
+func (ts *inventoryTestSuite) manifest(format string) {
+	ts.object("inventory", "reports/bucket/daily/2020-01-02T01-00Z/manifest.json", []byte(`{
+		"sourceBucket": "bucket",
+		"destinationBucket": "arn:aws:s3:::inventory",
+		"fileFormat": "`+format+`",
+		"fileSchema": "Bucket, Key, VersionId, IsLatest, IsDeleteMarker, Size, LastModifiedDate, ETag, StorageClass",
+		"files": [{"key": "reports/bucket/daily/data/1.csv.gz"}, {"key": "reports/bucket/daily/data/2.csv.gz"}]
+	}`))
+	ts.object("inventory", "reports/bucket/daily/data/1.csv.gz", gzipped(
+		`"bucket","dir1/a.txt","v1","true","false","10","2020-01-01T10:00:00.000Z","etag-a","STANDARD"`+"\n"+
+			`"bucket","dir1/old.txt","v1","false","false","5","2019-01-01T10:00:00.000Z","etag-old","STANDARD"`+"\n"+
+			`"bucket","dir1/deleted.txt","v2","true","true","","2020-01-01T10:00:00.000Z","",""`+"\n"+
+			`"bucket","dir2/other.txt","v1","true","false","7","2020-01-01T10:00:00.000Z","etag-other","STANDARD"`+"\n"))
+	ts.object("inventory", "reports/bucket/daily/data/2.csv.gz", gzipped(
+		`"bucket","dir1/with+space.txt","v1","true","false","20","2020-01-01T11:00:00.000Z","etag-b","GLACIER"`+"\n"+
+			`"bucket","dir1/sub/c.txt","v1","true","false","30","2020-01-01T12:00:00.000Z","etag-c","STANDARD"`+"\n"))
+}
+
+func (ts *inventoryTestSuite) names(opts ListOptions) []string {
+	opts.InventoryManifest = manifestURI
+	it := ts.loc.ListIterator(opts)
+	names := []string{}
+	for it.Next() {
+		names = append(names, keyOf(it.File().Path()))
+	}
+	ts.NoError(it.Err())
+	return names
+}
+
+func (ts *inventoryTestSuite) TestList() {
+	ts.manifest("CSV")
+
+	ts.Equal([]string{"dir1/a.txt", "dir1/with space.txt"}, ts.names(ListOptions{}))
+	ts.Equal([]string{"dir1/a.txt", "dir1/with space.txt", "dir1/sub/c.txt"}, ts.names(ListOptions{Recursive: true}))
+	ts.Equal([]string{"dir1/with space.txt", "dir1/sub/c.txt"}, ts.names(ListOptions{Recursive: true, MaxKeys: 1,
+		StartAfter: "a.txt"}))
+	ts.Equal([]string{"dir1/sub/c.txt"}, ts.names(ListOptions{Recursive: true, MinSize: 25}))
+	ts.s3apiMock.AssertNotCalled(ts.T(), "ListObjectsV2", mock.Anything)
+
+	it := ts.loc.ListIterator(ListOptions{InventoryManifest: manifestURI})
+	ts.Require().True(it.Next())
+	file := it.File().(*File)
+	ts.Equal(int64(10), aws.Int64Value(file.head.ContentLength), "metadata is populated from the report")
+	ts.Equal(time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC), aws.TimeValue(file.head.LastModified))
+	ts.Equal(`"etag-a"`, aws.StringValue(file.head.ETag))
+	ts.Equal("STANDARD", aws.StringValue(file.head.StorageClass))
+}
+
+func (ts *inventoryTestSuite) TestClose() {
+	ts.object("inventory", "reports/bucket/daily/2020-01-02T01-00Z/manifest.json", []byte(`{
+		"sourceBucket": "bucket",
+		"fileFormat": "CSV",
+		"fileSchema": "Bucket, Key, Size",
+		"files": [{"key": "reports/bucket/daily/data/1.csv.gz"}]
+	}`))
+	body := &closeRecorder{Reader: bytes.NewReader(gzipped(
+		`"bucket","dir1/a.txt","10"` + "\n" + `"bucket","dir1/b.txt","20"` + "\n"))}
+	ts.s3apiMock.On("GetObject", &s3.GetObjectInput{Bucket: aws.String("inventory"),
+		Key: aws.String("reports/bucket/daily/data/1.csv.gz")}).Return(&s3.GetObjectOutput{Body: body}, nil)
+
+	it := ts.loc.ListIterator(ListOptions{InventoryManifest: manifestURI, MaxKeys: 1})
+	ts.Require().True(it.Next())
+	ts.False(body.closed)
+
+	// stopping early closes the report file being read
+	ts.NoError(it.Close())
+	ts.True(body.closed)
+	ts.False(it.Next())
+	ts.NoError(it.Err())
+}
+
+// closeRecorder is a response body that records whether it's been closed.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func (ts *inventoryTestSuite) TestUnsupported() {
+	ts.manifest("Parquet")
+	it := ts.loc.ListIterator(ListOptions{InventoryManifest: manifestURI})
+	ts.False(it.Next())
+	ts.EqualError(it.Err(), "inventory manifest "+manifestURI+" is in Parquet format, but only CSV can be listed")
+
+	other, err := ts.loc.fileSystem.NewLocation("other", "/")
+	ts.NoError(err)
+	it = other.(*Location).ListIterator(ListOptions{InventoryManifest: manifestURI})
+	ts.False(it.Next())
+	ts.EqualError(it.Err(), "inventory manifest "+manifestURI+" is of bucket bucket, not other")
+
+	it = ts.loc.ListIterator(ListOptions{InventoryManifest: "inventory/manifest.json"})
+	ts.False(it.Next())
+	ts.EqualError(it.Err(), `invalid inventory manifest URI "inventory/manifest.json"`)
+}
+
+func gzipped(content string) []byte {
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	_, _ = w.Write([]byte(content))
+	_ = w.Close()
+	return buf.Bytes()
+}
+
+func TestInventory(t *testing.T) {
+	suite.Run(t, new(inventoryTestSuite))
+}
@@ -0,0 +1,44 @@
+package s3
+
+import (
+	"path"
+	"strings"
+
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// keyOf returns the s3 key of a file or location path.  Paths have a leading slash that isn't part of the key, ie: the
+// path "/dir/file.txt" is the key "dir/file.txt", and, with Options.DisableKeyNormalization, "//file.txt" is the key
+// "/file.txt".
+func keyOf(p string) string {
+	return strings.TrimPrefix(p, "/")
+}
+
+// normalizeKeys returns false if the file system's Options.DisableKeyNormalization is set.
+func (fs *FileSystem) normalizeKeys() bool {
+	if fs == nil {
+		return true
+	}
+	opts, ok := fs.options.(Options)
+	return !ok || !opts.DisableKeyNormalization
+}
+
+// joinPath joins the location path base and the relative path rel, cleaning the result with path.Join unless key
+// normalization is disabled.
+func (fs *FileSystem) joinPath(base, rel string) string {
+	if fs.normalizeKeys() {
+		return path.Join(base, rel)
+	}
+	return utils.EnsureTrailingSlash(base) + rel
+}
+
+// dirPath returns the path of the location containing the file at p, ie: "/dir" for "/dir/file.txt".  Without key
+// normalization, the result keeps its trailing slash and the location of a folder marker, ie: "/dir/sub/", is its
+// parent, "/dir/".
+func (fs *FileSystem) dirPath(p string) string {
+	if fs.normalizeKeys() {
+		return path.Dir(p)
+	}
+	trimmed := strings.TrimSuffix(p, "/")
+	return trimmed[:strings.LastIndex(trimmed, "/")+1]
+}
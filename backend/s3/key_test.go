@@ -0,0 +1,102 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type keyTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	fs        *FileSystem
+}
+
+func (ts *keyTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	ts.fs = &FileSystem{client: ts.s3apiMock, options: Options{DisableKeyNormalization: true}}
+}
+
+// headKey expects a HEAD request for key, as made by Exists.
+func (ts *keyTestSuite) headKey(key string) {
+	ts.s3apiMock.On("HeadObject", mock.MatchedBy(func(input *s3.HeadObjectInput) bool {
+		return aws.StringValue(input.Key) == key
+	})).Return(&s3.HeadObjectOutput{}, nil).Once()
+}
+
+func (ts *keyTestSuite) TestNormalized() {
+	file, err := (&FileSystem{client: ts.s3apiMock}).NewFile("bucket", "/some//path/./file.txt")
+	ts.NoError(err)
+	ts.Equal("/some/path/file.txt", file.Path(), "paths are cleaned by default")
+
+	ts.headKey("some/path/file.txt")
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *keyTestSuite) TestDisableKeyNormalization() {
+	file, err := ts.fs.NewFile("bucket", "/some//path/file.txt")
+	ts.NoError(err)
+	ts.Equal("/some//path/file.txt", file.Path())
+	ts.Equal("/some//path/", file.Location().Path())
+	ts.headKey("some//path/file.txt")
+	_, err = file.Exists()
+	ts.NoError(err)
+
+	file, err = ts.fs.NewFile("bucket", "//leading.txt")
+	ts.NoError(err)
+	ts.headKey("/leading.txt")
+	_, err = file.Exists()
+	ts.NoError(err)
+
+	marker, err := ts.fs.NewFile("bucket", "/dir/sub/")
+	ts.NoError(err, "folder markers are files")
+	ts.Equal("sub", marker.Name())
+	ts.Equal("/dir/", marker.Location().Path(), "a folder marker is in its parent location")
+	ts.headKey("dir/sub/")
+	_, err = marker.Exists()
+	ts.NoError(err)
+
+	_, err = ts.fs.NewFile("bucket", "relative.txt")
+	ts.Error(err, "paths must still be absolute")
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *keyTestSuite) TestDisableKeyNormalization_Location() {
+	location, err := ts.fs.NewLocation("bucket", "/some//path/")
+	ts.NoError(err)
+	ts.Equal("/some//path/", location.Path())
+
+	file, err := location.NewFile("résumé 1.txt")
+	ts.NoError(err)
+	ts.Equal("/some//path/résumé 1.txt", file.Path())
+
+	marker, err := location.NewFile("sub/")
+	ts.NoError(err)
+	ts.Equal("/some//path/sub/", marker.Path())
+
+	sub, err := location.NewLocation("../")
+	ts.NoError(err)
+	ts.Equal("/some//path/../", sub.Path(), `".." is part of the key`)
+}
+
+func (ts *keyTestSuite) TestClient() {
+	client, err := getClient(Options{DisableKeyNormalization: true})
+	ts.NoError(err)
+	ts.True(aws.BoolValue(client.(*s3.S3).Config.DisableRestProtocolURICleaning))
+
+	client, err = getClient(Options{})
+	ts.NoError(err)
+	ts.False(aws.BoolValue(client.(*s3.S3).Config.DisableRestProtocolURICleaning))
+}
+
+func TestKey(t *testing.T) {
+	suite.Run(t, new(keyTestSuite))
+}
@@ -0,0 +1,173 @@
+package s3
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// tokenBucket is a simple token bucket rate limiter.  Tokens accrue at rate per second up to burst, and each call to
+// wait consumes one, blocking until one is available.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token, returning how long the caller must wait before using it.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// currentRate returns the rate tokens accrue at.
+func (b *tokenBucket) currentRate() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rate
+}
+
+// setRate changes the rate tokens accrue at from now on.
+func (b *tokenBucket) setRate(rate float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.rate = rate
+}
+
+// wait blocks until a token is available or ctx is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	delay := b.reserve()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// addLimitHandlers adds request handlers to enforce the OperationTimeout and RequestsPerSecond options, if set.
+func addLimitHandlers(handlers *request.Handlers, opt Options) {
+	if opt.OperationTimeout > 0 {
+		handlers.Validate.PushFrontNamed(request.NamedHandler{
+			Name: "vfs.OperationTimeout",
+			Fn: func(r *request.Request) {
+				ctx, cancel := context.WithCancel(r.Context())
+				timer := time.AfterFunc(opt.OperationTimeout, cancel)
+				r.SetContext(ctx)
+
+				var body *cancelOnClose
+				r.Handlers.Send.PushBack(func(r *request.Request) {
+					if r.HTTPResponse != nil && r.HTTPResponse.Body != nil {
+						body = &cancelOnClose{ReadCloser: r.HTTPResponse.Body, cancel: cancel}
+						r.HTTPResponse.Body = body
+					}
+				})
+				r.Handlers.Complete.PushBack(func(r *request.Request) {
+					timer.Stop()
+					if r.Error == nil && body != nil {
+						// a streamed body, ie: GetObject's, is read after the call returns, so canceling the context
+						// waits until it's closed
+						body.complete()
+						return
+					}
+					cancel()
+				})
+			},
+		})
+	}
+
+	if opt.RequestsPerSecond > 0 {
+		limiter := newTokenBucket(opt.RequestsPerSecond, opt.RequestBurst)
+		// Sign runs for every attempt, so retries are rate limited too
+		handlers.Sign.PushFrontNamed(request.NamedHandler{
+			Name: "vfs.RateLimit",
+			Fn: func(r *request.Request) {
+				if err := limiter.wait(r.Context()); err != nil {
+					r.Error = rateLimitCanceled(err)
+				}
+			},
+		})
+	}
+}
+
+// cancelOnClose is a response body that cancels its request's context once the request has completed and the body is
+// closed, whichever is last, so the body can still be read after the call returns.
+type cancelOnClose struct {
+	io.ReadCloser
+	mu        sync.Mutex
+	cancel    context.CancelFunc
+	closed    bool
+	completed bool
+}
+
+// Close closes the body, canceling the context if the request has completed.  Bodies of failed attempts are closed
+// before they're retried, which mustn't cancel the retry.
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	if b.completed {
+		b.cancel()
+	}
+	return err
+}
+
+// complete marks the request completed, canceling the context if the body was already closed, ie: once unmarshaled.
+func (b *cancelOnClose) complete() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.completed = true
+	if b.closed {
+		b.cancel()
+	}
+}
+
+// rateLimitCanceled returns the error for a request whose context was done while it waited to be sent.
+func rateLimitCanceled(err error) error {
+	return awserr.New(request.CanceledErrorCode, "request context canceled while rate limited", err)
+}
@@ -0,0 +1,127 @@
+package s3
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/suite"
+)
+
+type limitsTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+	// mu guards delay and calls, which the server's handler uses on its own goroutines
+	mu    sync.Mutex
+	delay time.Duration
+	calls int
+}
+
+func (ts *limitsTestSuite) SetupTest() {
+	ts.calls = 0
+	ts.delay = 0
+	ts.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts.mu.Lock()
+		ts.calls++
+		delay := ts.delay
+		ts.mu.Unlock()
+		time.Sleep(delay)
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte("contents"))
+		}
+	}))
+}
+
+func (ts *limitsTestSuite) TearDownTest() {
+	ts.server.Close()
+}
+
+func (ts *limitsTestSuite) client(opts Options) *s3.S3 {
+	opts.Endpoint = ts.server.URL
+	opts.Region = "us-east-1"
+	opts.AccessKeyID = "key"
+	opts.SecretAccessKey = "secret"
+	client, err := getClient(opts)
+	ts.NoError(err)
+	c := client.(*s3.S3)
+	c.Config.S3ForcePathStyle = aws.Bool(true)
+	c.Config.MaxRetries = aws.Int(0)
+	return c
+}
+
+func (ts *limitsTestSuite) TestOperationTimeout() {
+	delay := 200 * time.Millisecond
+	ts.mu.Lock()
+	ts.delay = delay
+	ts.mu.Unlock()
+	client := ts.client(Options{OperationTimeout: 20 * time.Millisecond})
+
+	start := time.Now()
+	_, err := client.HeadObject(new(s3.HeadObjectInput).SetBucket("bucket").SetKey("key"))
+	ts.Error(err, "request timed out")
+	ts.Equal(request.CanceledErrorCode, err.(awserr.Error).Code())
+	ts.True(time.Since(start) < delay, "didn't wait for the response")
+
+	// no timeout
+	client = ts.client(Options{})
+	_, err = client.HeadObject(new(s3.HeadObjectInput).SetBucket("bucket").SetKey("key"))
+	ts.NoError(err)
+}
+
+func (ts *limitsTestSuite) TestOperationTimeout_GetObjectBody() {
+	client := ts.client(Options{OperationTimeout: 20 * time.Millisecond})
+
+	req, output := client.GetObjectRequest(new(s3.GetObjectInput).SetBucket("bucket").SetKey("key"))
+	ts.NoError(req.Send())
+	// the body is read after the call returns, and after the timeout would have expired
+	time.Sleep(40 * time.Millisecond)
+	ts.NoError(req.Context().Err(), "context isn't canceled until the body is closed")
+	body, err := ioutil.ReadAll(output.Body)
+	ts.NoError(err)
+	ts.Equal("contents", string(body))
+
+	ts.NoError(output.Body.Close())
+	ts.Equal(context.Canceled, req.Context().Err(), "context is canceled once the body is closed")
+
+	// contexts of calls whose bodies are unmarshaled are canceled when they complete
+	req, _ = client.HeadObjectRequest(new(s3.HeadObjectInput).SetBucket("bucket").SetKey("key"))
+	ts.NoError(req.Send())
+	ts.Equal(context.Canceled, req.Context().Err())
+}
+
+func (ts *limitsTestSuite) TestRateLimit() {
+	client := ts.client(Options{RequestsPerSecond: 20, RequestBurst: 2})
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := client.HeadObject(new(s3.HeadObjectInput).SetBucket("bucket").SetKey("key"))
+		ts.NoError(err)
+	}
+	ts.mu.Lock()
+	ts.Equal(4, ts.calls)
+	ts.mu.Unlock()
+	// 2 requests are allowed immediately, the next 2 at 50ms intervals
+	ts.True(time.Since(start) >= 90*time.Millisecond, "requests were rate limited")
+}
+
+func (ts *limitsTestSuite) TestTokenBucket_Wait() {
+	bucket := newTokenBucket(1, 1)
+	ts.NoError(bucket.wait(context.Background()), "burst token is available")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	ts.Equal(context.DeadlineExceeded, bucket.wait(ctx), "waiting stops when the context is done")
+}
+
+func TestLimits(t *testing.T) {
+	suite.Run(t, new(limitsTestSuite))
+}
@@ -0,0 +1,233 @@
+package s3
+
+import (
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// ListOptions controls how a ListIterator pages through a Location.
+type ListOptions struct {
+	// MaxKeys is the number of keys requested per call to the s3 API.  Defaults to (and may not exceed) 1000.
+	MaxKeys int64
+	// StartAfter is a file name, relative to the location, after which listing begins.  Useful for resuming a
+	// previously interrupted listing.
+	StartAfter string
+
+	// The following filters are applied to each page of results as it is iterated.  Zero values are ignored.
+
+	// ModifiedAfter only includes files last modified after this time.
+	ModifiedAfter time.Time
+	// ModifiedBefore only includes files last modified before this time.
+	ModifiedBefore time.Time
+	// MinSize only includes files of at least this many bytes.
+	MinSize int64
+	// MaxSize only includes files of at most this many bytes.
+	MaxSize int64
+	// NameFilter only includes files whose name (relative to the location) matches.
+	NameFilter *regexp.Regexp
+
+	// Recursive includes the files beneath the location's "sub-directories" too, ie: to scan a whole bucket.
+	Recursive bool
+	// InventoryManifest, when set, lists the files from the S3 Inventory report whose manifest.json is at this URI, ie:
+	// "s3://inventory-bucket/prefix/bucket/config-id/2006-01-02T15-04Z/manifest.json", rather than calling the s3 API
+	// to list them.  Reading a report of 100M+ objects is far quicker and cheaper than listing them, but it's only as
+	// current as the report (up to a day or a week old).  The report must be of the location's bucket, in CSV format;
+	// ORC and Parquet reports aren't supported.  Files are listed in the report's order, not by key, so MaxKeys only
+	// sets how many are read at a time and StartAfter is applied as a filter.
+	InventoryManifest string
+}
+
+// matches returns true if the listed object satisfies the options' filters.
+func (o ListOptions) matches(object *s3.Object) bool {
+	lastModified := aws.TimeValue(object.LastModified)
+	if !o.ModifiedAfter.IsZero() && !lastModified.After(o.ModifiedAfter) {
+		return false
+	}
+	if !o.ModifiedBefore.IsZero() && !lastModified.Before(o.ModifiedBefore) {
+		return false
+	}
+	size := aws.Int64Value(object.Size)
+	if o.MinSize > 0 && size < o.MinSize {
+		return false
+	}
+	if o.MaxSize > 0 && size > o.MaxSize {
+		return false
+	}
+	if o.NameFilter != nil && !o.NameFilter.MatchString(path.Base(aws.StringValue(object.Key))) {
+		return false
+	}
+	return true
+}
+
+// ListIterator lazily pages through the files at a Location, only holding one page of results in memory at a time.
+// Files not matching the ListOptions filters are skipped, so selecting, for example, files older than 30 days doesn't
+// require materializing the full listing first.  Call Close if iteration stops before Next returns false, so an
+// inventory report being read (see ListOptions.InventoryManifest) isn't left open:
+//
+//   it := loc.ListIterator(s3.ListOptions{ModifiedBefore: time.Now().AddDate(0, 0, -30)})
+//   defer it.Close()
+//   for it.Next() {
+//       file := it.File()
+//       ...
+//   }
+//   if err := it.Err(); err != nil {
+//       ...
+//   }
+type ListIterator struct {
+	location  *Location
+	prefix    string
+	input     *s3.ListObjectsV2Input
+	opts      ListOptions
+	inventory *inventoryReader
+	page      []*s3.Object
+	file      *File
+	lastPage  bool
+	err       error
+}
+
+// ListIterator returns a ListIterator over the files at the location.  Files returned by the iterator have their
+// metadata populated from the listing as with ListFiles.
+func (l *Location) ListIterator(opts ListOptions) *ListIterator {
+	prefix := l.listPrefix()
+	input := new(s3.ListObjectsV2Input).SetBucket(l.bucket).SetPrefix(prefix)
+	if !opts.Recursive {
+		input.SetDelimiter("/")
+	}
+	if opts.MaxKeys > 0 {
+		input.SetMaxKeys(opts.MaxKeys)
+	}
+	if opts.StartAfter != "" {
+		input.SetStartAfter(prefix + opts.StartAfter)
+	}
+
+	return &ListIterator{
+		location: l,
+		prefix:   prefix,
+		input:    input,
+		opts:     opts,
+	}
+}
+
+// Next advances the iterator to the next file, fetching the next page of results from s3 if needed.  It returns false
+// when there are no more files or an error occurred, see Err.
+func (it *ListIterator) Next() bool {
+	it.file = nil
+	for it.err == nil {
+		for len(it.page) > 0 {
+			object := it.page[0]
+			it.page = it.page[1:]
+			if it.location.fileSystem.listedAsFile(object, it.prefix) && it.opts.matches(object) {
+				it.file = it.location.newFileFromObject(object)
+				return true
+			}
+		}
+
+		if it.lastPage {
+			return false
+		}
+		it.fetchPage()
+	}
+	return false
+}
+
+// File returns the current file.  It is only valid after a call to Next returns true.
+func (it *ListIterator) File() vfs.File {
+	if it.file == nil {
+		return nil
+	}
+	return it.file
+}
+
+// Err returns the error, if any, that stopped iteration.
+func (it *ListIterator) Err() error {
+	return it.err
+}
+
+// Close stops iteration, closing the inventory report file being read, if any.  Next returns false once it's called.
+// It needn't be called once Next has returned false, though it's safe to.
+func (it *ListIterator) Close() error {
+	it.page = nil
+	it.file = nil
+	it.lastPage = true
+	if it.inventory != nil {
+		it.inventory.close()
+	}
+	return nil
+}
+
+func (it *ListIterator) fetchPage() {
+	if it.opts.InventoryManifest != "" {
+		it.fetchInventoryPage()
+		return
+	}
+
+	client, err := it.location.fileSystem.Client()
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	output, err := client.ListObjectsV2(it.input)
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.page = output.Contents
+
+	// if s3 response "IsTruncated" the next page is fetched using the continuation token
+	if aws.BoolValue(output.IsTruncated) {
+		it.input.SetContinuationToken(aws.StringValue(output.NextContinuationToken))
+	} else {
+		it.lastPage = true
+	}
+}
+
+// fetchInventoryPage reads the next page of the location's files from the inventory report, opening it first if
+// necessary.
+func (it *ListIterator) fetchInventoryPage() {
+	if it.inventory == nil {
+		inventory, err := openInventory(it.location.fileSystem, it.opts.InventoryManifest, it.location.bucket)
+		if err != nil {
+			it.err = err
+			return
+		}
+		it.inventory = inventory
+	}
+
+	pageSize := int(aws.Int64Value(it.input.MaxKeys))
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+	startAfter := aws.StringValue(it.input.StartAfter)
+	it.page = make([]*s3.Object, 0, pageSize)
+	for len(it.page) < pageSize {
+		object, err := it.inventory.next()
+		if err == io.EOF {
+			it.lastPage = true
+			return
+		}
+		if err != nil {
+			it.inventory.close()
+			it.err = err
+			return
+		}
+
+		key := aws.StringValue(object.Key)
+		if !strings.HasPrefix(key, it.prefix) || key <= startAfter {
+			continue
+		}
+		if !it.opts.Recursive && strings.Contains(key[len(it.prefix):], "/") {
+			// beneath a "sub-directory", which a delimited listing would return as a common prefix
+			continue
+		}
+		it.page = append(it.page, object)
+	}
+}
@@ -0,0 +1,140 @@
+package s3
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type listIteratorTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	loc       *Location
+}
+
+func (ts *listIteratorTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	fs := &FileSystem{client: ts.s3apiMock}
+	loc, err := fs.NewLocation("bucket", "/dir1/")
+	ts.NoError(err)
+	ts.loc = loc.(*Location)
+}
+
+func (ts *listIteratorTestSuite) TestIterate() {
+	ts.s3apiMock.On("ListObjectsV2", mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+		return input.ContinuationToken == nil &&
+			*input.MaxKeys == 2 &&
+			*input.StartAfter == "dir1/a.txt"
+	})).Return(&s3.ListObjectsV2Output{
+		Contents:              []*s3.Object{{Key: aws.String("dir1/b.txt")}, {Key: aws.String("dir1/c.txt")}},
+		IsTruncated:           aws.Bool(true),
+		NextContinuationToken: aws.String("token"),
+	}, nil).Once()
+	ts.s3apiMock.On("ListObjectsV2", mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+		return aws.StringValue(input.ContinuationToken) == "token"
+	})).Return(&s3.ListObjectsV2Output{
+		Contents:    []*s3.Object{{Key: aws.String("dir1/d.txt")}},
+		IsTruncated: aws.Bool(false),
+	}, nil).Once()
+
+	it := ts.loc.ListIterator(ListOptions{MaxKeys: 2, StartAfter: "a.txt"})
+	ts.Nil(it.File(), "no file before Next")
+
+	var names []string
+	for it.Next() {
+		names = append(names, it.File().Name())
+		if len(names) == 1 {
+			ts.s3apiMock.AssertNumberOfCalls(ts.T(), "ListObjectsV2", 1)
+		}
+	}
+	ts.NoError(it.Err())
+	ts.Equal([]string{"b.txt", "c.txt", "d.txt"}, names)
+	ts.False(it.Next(), "iterator stays exhausted")
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *listIteratorTestSuite) TestIterate_EmptyPage() {
+	ts.s3apiMock.On("ListObjectsV2", mock.AnythingOfType("*s3.ListObjectsV2Input")).
+		Return(&s3.ListObjectsV2Output{Contents: []*s3.Object{{Key: aws.String("dir1/")}}, IsTruncated: aws.Bool(false)}, nil)
+
+	it := ts.loc.ListIterator(ListOptions{})
+	ts.False(it.Next(), "directory marker is skipped")
+	ts.NoError(it.Err())
+}
+
+func (ts *listIteratorTestSuite) TestIterate_Recursive() {
+	ts.s3apiMock.On("ListObjectsV2", mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+		return input.Delimiter == nil && aws.StringValue(input.Prefix) == "dir1/"
+	})).Return(&s3.ListObjectsV2Output{
+		Contents:    []*s3.Object{{Key: aws.String("dir1/a.txt")}, {Key: aws.String("dir1/sub/b.txt")}},
+		IsTruncated: aws.Bool(false),
+	}, nil).Once()
+
+	it := ts.loc.ListIterator(ListOptions{Recursive: true})
+	var paths []string
+	for it.Next() {
+		paths = append(paths, it.File().Path())
+	}
+	ts.NoError(it.Err())
+	ts.Equal([]string{"/dir1/a.txt", "/dir1/sub/b.txt"}, paths)
+}
+
+func (ts *listIteratorTestSuite) TestIterate_Error() {
+	ts.s3apiMock.On("ListObjectsV2", mock.AnythingOfType("*s3.ListObjectsV2Input")).Return(nil, errors.New("list error"))
+
+	it := ts.loc.ListIterator(ListOptions{})
+	ts.False(it.Next())
+	ts.EqualError(it.Err(), "list error")
+
+	files, err := ts.loc.ListFiles()
+	ts.EqualError(err, "list error")
+	ts.Empty(files)
+}
+
+func (ts *listIteratorTestSuite) TestIterate_Filters() {
+	now := time.Now()
+	old := now.AddDate(0, 0, -40)
+	ts.s3apiMock.On("ListObjectsV2", mock.AnythingOfType("*s3.ListObjectsV2Input")).Return(&s3.ListObjectsV2Output{
+		Contents: []*s3.Object{
+			{Key: aws.String("dir1/old.csv"), Size: aws.Int64(100), LastModified: &old},
+			{Key: aws.String("dir1/new.csv"), Size: aws.Int64(100), LastModified: &now},
+			{Key: aws.String("dir1/old.txt"), Size: aws.Int64(100), LastModified: &old},
+			{Key: aws.String("dir1/small.csv"), Size: aws.Int64(1), LastModified: &old},
+			{Key: aws.String("dir1/big.csv"), Size: aws.Int64(1000), LastModified: &old},
+		},
+		IsTruncated: aws.Bool(false),
+	}, nil)
+
+	tests := []struct {
+		opts     ListOptions
+		expected []string
+		message  string
+	}{
+		{ListOptions{}, []string{"old.csv", "new.csv", "old.txt", "small.csv", "big.csv"}, "no filters"},
+		{ListOptions{ModifiedBefore: now.AddDate(0, 0, -30)}, []string{"old.csv", "old.txt", "small.csv", "big.csv"}, "modified before"},
+		{ListOptions{ModifiedAfter: old}, []string{"new.csv"}, "modified after"},
+		{ListOptions{MinSize: 10, MaxSize: 100}, []string{"old.csv", "new.csv", "old.txt"}, "size range"},
+		{ListOptions{NameFilter: regexp.MustCompile(`\.csv$`), MaxSize: 100}, []string{"old.csv", "new.csv", "small.csv"}, "name and size"},
+	}
+	for _, test := range tests {
+		it := ts.loc.ListIterator(test.opts)
+		names := []string{}
+		for it.Next() {
+			names = append(names, it.File().Name())
+		}
+		ts.NoError(it.Err(), test.message)
+		ts.Equal(test.expected, names, test.message)
+	}
+}
+
+func TestListIterator(t *testing.T) {
+	suite.Run(t, new(listIteratorTestSuite))
+}
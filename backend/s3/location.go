@@ -2,11 +2,12 @@ package s3
 
 import (
 	"errors"
-	"path"
+	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
-	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 
 	"github.com/c2fo/vfs/v5"
@@ -24,16 +25,109 @@ type Location struct {
 // set to the location's path. This will make a call to the s3 API for every 1000 keys to return.
 // If you have many thousands of keys at the given location, this could become quite expensive.
 func (l *Location) List() ([]string, error) {
-	prefix := utils.RemoveLeadingSlash(l.prefix)
+	prefix := keyOf(l.prefix)
 	listObjectsInput := l.getListObjectsInput().SetPrefix(utils.EnsureTrailingSlash(prefix))
 	return l.fullLocationList(listObjectsInput, prefix)
 }
 
+// ListFiles returns the files found at the location as vfs.Files.  Each File's size, last modified time, ETag and
+// storage class are populated from the listing, so calling Size, LastModified, Exists, ETag or StorageClass on it
+// doesn't make an additional HEAD request (see File.Refresh).  Stat, ResolveVersionID and ExtendedAttributes still make
+// one, for the fields listings don't include.  The resource considerations of List() apply here as well.
+func (l *Location) ListFiles() ([]vfs.File, error) {
+	files := []vfs.File{}
+	it := l.ListIterator(ListOptions{})
+	for it.Next() {
+		files = append(files, it.File())
+	}
+	if err := it.Err(); err != nil {
+		return []vfs.File{}, err
+	}
+	return files, nil
+}
+
+// ListInfo returns a FileInfo for each file found at the location, populated from the listing without HEAD requests,
+// which is useful for passing to code written against os.FileInfo.  Since listings don't include them, the FileInfos
+// have no ContentType or ContentEncoding; File.Stat has them.  The resource considerations of List() apply here as
+// well.
+func (l *Location) ListInfo() ([]*vfs.FileInfo, error) {
+	infos := []*vfs.FileInfo{}
+	it := l.ListIterator(ListOptions{})
+	for it.Next() {
+		head, err := it.file.getHeadObject()
+		if err != nil {
+			return []*vfs.FileInfo{}, err
+		}
+		infos = append(infos, it.file.fileInfo(head))
+	}
+	if err := it.Err(); err != nil {
+		return []*vfs.FileInfo{}, err
+	}
+	return infos, nil
+}
+
+// ListWithPrefixes returns the files found directly at the location along with a Location for each "sub-directory"
+// (s3 common prefix) beneath it, which is useful for walking or displaying an s3 bucket as a directory tree.  Files
+// have their metadata populated from the listing as with ListFiles.  The resource considerations of List() apply here
+// as well.
+func (l *Location) ListWithPrefixes() ([]vfs.File, []vfs.Location, error) {
+	files := []vfs.File{}
+	locations := []vfs.Location{}
+	client, err := l.fileSystem.Client()
+	if err != nil {
+		return files, locations, err
+	}
+
+	opts, _ := l.fileSystem.options.(Options)
+	prefix := l.listPrefix()
+	input := new(s3.ListObjectsV2Input).SetBucket(l.bucket).SetDelimiter("/").SetPrefix(prefix)
+	var folders []string
+	for {
+		output, err := client.ListObjectsV2(input)
+		if err != nil {
+			return []vfs.File{}, []vfs.Location{}, err
+		}
+		for _, object := range output.Contents {
+			if l.fileSystem.listedAsFile(object, prefix) {
+				files = append(files, l.newFileFromObject(object))
+				continue
+			}
+			if folder, ok := l.fileSystem.markedFolder(object); ok && opts.FolderMarkersAsLocations && folder != prefix {
+				folders = append(folders, folder)
+			}
+		}
+		for _, commonPrefix := range output.CommonPrefixes {
+			folders = append(folders, aws.StringValue(commonPrefix.Prefix))
+		}
+
+		// if s3 response "IsTruncated" we need to call List again with the continuation token
+		if aws.BoolValue(output.IsTruncated) {
+			input.SetContinuationToken(aws.StringValue(output.NextContinuationToken))
+		} else {
+			break
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, folder := range folders {
+		if seen[folder] {
+			continue
+		}
+		seen[folder] = true
+		locations = append(locations, &Location{
+			fileSystem: l.fileSystem,
+			bucket:     l.bucket,
+			prefix:     utils.EnsureLeadingSlash(folder),
+		})
+	}
+	return files, locations, nil
+}
+
 // ListByPrefix calls the s3 API with the location's prefix modified relatively by the prefix arg passed to the
 // function. The resource considerations of List() apply to this function as well.
 func (l *Location) ListByPrefix(prefix string) ([]string, error) {
-	searchPrefix := utils.RemoveLeadingSlash(path.Join(l.prefix, prefix))
-	d := path.Dir(searchPrefix)
+	searchPrefix := keyOf(l.fileSystem.joinPath(l.prefix, prefix))
+	d := l.fileSystem.dirPath(searchPrefix)
 	listObjectsInput := l.getListObjectsInput().SetPrefix(searchPrefix)
 	return l.fullLocationList(listObjectsInput, d)
 }
@@ -76,7 +170,7 @@ func (l *Location) Exists() (bool, error) {
 	}
 	_, err = client.HeadBucket(headBucketInput)
 	if err != nil {
-		if err.(awserr.Error).Code() == s3.ErrCodeNoSuchBucket {
+		if isNotFound(err) {
 			return false, nil
 		}
 		return false, err
@@ -85,6 +179,51 @@ func (l *Location) Exists() (bool, error) {
 	return true, err
 }
 
+// PrefixExists returns true if there is at least one object under the location's prefix (at any depth).  Unlike Exists,
+// which only checks the bucket, this is useful for checking whether files have been delivered to a location.
+func (l *Location) PrefixExists() (bool, error) {
+	client, err := l.fileSystem.Client()
+	if err != nil {
+		return false, err
+	}
+	input := new(s3.ListObjectsV2Input).SetBucket(l.bucket).SetPrefix(l.listPrefix()).SetMaxKeys(1)
+	output, err := client.ListObjectsV2(input)
+	if err != nil {
+		return false, err
+	}
+	return len(output.Contents) > 0, nil
+}
+
+// LocationStats summarizes the files found at a Location.
+type LocationStats struct {
+	// FileCount is the number of files at the location.
+	FileCount int
+	// TotalSize is the combined size of the files in bytes.
+	TotalSize uint64
+	// NewestModified is the most recent last modified time of the files, or the zero time if there are none.
+	NewestModified time.Time
+}
+
+// Stats returns the number, total size and newest last modified time of the files at the location, computed by paging
+// through the listing (see ListIterator), which is useful for validating that an expected delivery is complete.  The
+// resource considerations of List() apply here as well.
+func (l *Location) Stats() (LocationStats, error) {
+	stats := LocationStats{}
+	it := l.ListIterator(ListOptions{})
+	for it.Next() {
+		head := it.file.head
+		stats.FileCount++
+		stats.TotalSize += uint64(aws.Int64Value(head.ContentLength))
+		if lastModified := aws.TimeValue(head.LastModified); lastModified.After(stats.NewestModified) {
+			stats.NewestModified = lastModified
+		}
+	}
+	if err := it.Err(); err != nil {
+		return LocationStats{}, err
+	}
+	return stats, nil
+}
+
 // NewLocation makes a copy of the underlying Location, then modifies its path by calling ChangeDir with the
 // relativePath argument, returning the resulting location. The only possible errors come from the call to
 // ChangeDir, which, for the s3 implementation doesn't ever result in an error.
@@ -116,7 +255,25 @@ func (l *Location) ChangeDir(relativePath string) error {
 	if err != nil {
 		return err
 	}
-	l.prefix = utils.EnsureLeadingSlash(utils.EnsureTrailingSlash(path.Join(l.prefix, relativePath)))
+	l.prefix = utils.EnsureLeadingSlash(utils.EnsureTrailingSlash(l.fileSystem.joinPath(l.prefix, relativePath)))
+	return nil
+}
+
+// ChangeVolume moves the location to the same path in another bucket, once it's checked that the bucket exists and is
+// accessible.  The location is left unchanged if it isn't.
+func (l *Location) ChangeVolume(bucket string) error {
+	if l == nil {
+		return errors.New("non-nil s3.Location pointer is required")
+	}
+	bucket = utils.RemoveTrailingSlash(bucket)
+	exists, err := l.fileSystem.BucketExists(bucket)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s does not exist", bucket)
+	}
+	l.bucket = bucket
 	return nil
 }
 
@@ -129,14 +286,15 @@ func (l *Location) NewFile(filePath string) (vfs.File, error) {
 	if filePath == "" {
 		return nil, errors.New("non-empty string filePath is required")
 	}
+	// without key normalization, a trailing slash is allowed for folder markers
 	err := utils.ValidateRelativeFilePath(filePath)
-	if err != nil {
+	if err != nil && (l.fileSystem.normalizeKeys() || utils.ValidateRelativeLocationPath(filePath) != nil) {
 		return nil, err
 	}
 	newFile := &File{
 		fileSystem: l.fileSystem,
 		bucket:     l.bucket,
-		key:        utils.EnsureLeadingSlash(path.Join(l.prefix, filePath)),
+		key:        utils.EnsureLeadingSlash(l.fileSystem.joinPath(l.prefix, filePath)),
 	}
 	return newFile, nil
 }
@@ -156,6 +314,17 @@ func (l *Location) FileSystem() vfs.FileSystem {
 	return l.fileSystem
 }
 
+// WithOptions returns a copy of the location backed by its own FileSystem (and so its own client) configured with opts,
+// leaving the original location as-is.  This allows per-location credentials, ie: writing to a customer's bucket under
+// a role assumed via Options.RoleARN while the rest of the process uses its default credentials.  Files and locations
+// created from the returned location share its FileSystem.
+func (l *Location) WithOptions(opts vfs.Options) *Location {
+	newLocation := &Location{}
+	*newLocation = *l
+	newLocation.fileSystem = NewFileSystem().WithOptions(opts)
+	return newLocation
+}
+
 // URI returns the Location's URI as a string.
 func (l *Location) URI() string {
 	return utils.GetLocationURI(l)
@@ -181,7 +350,7 @@ func (l *Location) fullLocationList(input *s3.ListObjectsInput, prefix string) (
 		if err != nil {
 			return []string{}, err
 		}
-		newKeys := getNamesFromObjectSlice(listObjectsOutput.Contents, utils.EnsureTrailingSlash(utils.RemoveLeadingSlash(prefix)))
+		newKeys := l.getNamesFromObjectSlice(listObjectsOutput.Contents, utils.EnsureTrailingSlash(prefix))
 		keys = append(keys, newKeys...)
 
 		// if s3 response "IsTruncated" we need to call List again with
@@ -200,10 +369,37 @@ func (l *Location) getListObjectsInput() *s3.ListObjectsInput {
 	return new(s3.ListObjectsInput).SetBucket(l.bucket).SetDelimiter("/")
 }
 
-func getNamesFromObjectSlice(objects []*s3.Object, locationPrefix string) []string {
+// listPrefix returns the location's path as an s3 key prefix: no leading slash, and a trailing slash unless the location
+// is the root of the bucket.
+func (l *Location) listPrefix() string {
+	prefix := keyOf(l.prefix)
+	if prefix == "" {
+		return prefix
+	}
+	return utils.EnsureTrailingSlash(prefix)
+}
+
+// newFileFromObject returns a *File for a listed s3 object, with its HEAD cache populated from the listing.  The cache is
+// marked partial, so the fields a listing doesn't include are fetched with a HEAD request when they're needed.
+func (l *Location) newFileFromObject(object *s3.Object) *File {
+	return &File{
+		fileSystem: l.fileSystem,
+		bucket:     l.bucket,
+		key:        utils.EnsureLeadingSlash(aws.StringValue(object.Key)),
+		head: &s3.HeadObjectOutput{
+			ContentLength: object.Size,
+			LastModified:  object.LastModified,
+			ETag:          object.ETag,
+			StorageClass:  object.StorageClass,
+		},
+		headPartial: true,
+	}
+}
+
+func (l *Location) getNamesFromObjectSlice(objects []*s3.Object, locationPrefix string) []string {
 	var keys []string
 	for _, object := range objects {
-		if *object.Key != locationPrefix {
+		if l.fileSystem.listedAsFile(object, locationPrefix) {
 			keys = append(keys, strings.TrimPrefix(*object.Key, locationPrefix))
 		}
 	}
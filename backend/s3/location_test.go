@@ -1,10 +1,14 @@
 package s3
 
 import (
+	"errors"
 	"path"
 	"regexp"
+	"sort"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/stretchr/testify/mock"
@@ -98,6 +102,189 @@ func (lt *locationTestSuite) TestList_pagedCall() {
 	lt.s3apiMock.AssertNumberOfCalls(lt.T(), "ListObjects", 2)
 }
 
+func (lt *locationTestSuite) TestListFiles() {
+	lastModified := time.Now()
+	lt.s3apiMock.On("ListObjectsV2", mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+		return *input.Prefix == "dir1/" && *input.Delimiter == "/" && input.ContinuationToken == nil
+	})).Return(&s3.ListObjectsV2Output{
+		Contents: []*s3.Object{
+			{Key: aws.String("dir1/")},
+			{Key: aws.String("dir1/file.txt"), Size: aws.Int64(10), LastModified: &lastModified, ETag: aws.String(`"abc"`)},
+		},
+		IsTruncated:           aws.Bool(true),
+		NextContinuationToken: aws.String("token"),
+	}, nil).Once()
+	lt.s3apiMock.On("ListObjectsV2", mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+		return aws.StringValue(input.ContinuationToken) == "token"
+	})).Return(&s3.ListObjectsV2Output{
+		Contents:    []*s3.Object{{Key: aws.String("dir1/file2.txt"), Size: aws.Int64(20), LastModified: &lastModified}},
+		IsTruncated: aws.Bool(false),
+	}, nil).Once()
+
+	loc, err := lt.fs.NewLocation("bucket", "/dir1/")
+	lt.NoError(err)
+	files, err := loc.(*Location).ListFiles()
+	lt.NoError(err)
+	lt.Len(files, 2, "directory marker is skipped")
+	lt.Equal("s3://bucket/dir1/file.txt", files[0].URI())
+	lt.Equal("s3://bucket/dir1/file2.txt", files[1].URI())
+
+	// metadata comes from the listing, not HEAD
+	size, err := files[1].Size()
+	lt.NoError(err)
+	lt.Equal(uint64(20), size)
+	etag, err := files[0].(*File).ETag()
+	lt.NoError(err)
+	lt.Equal("abc", etag)
+	modified, err := files[0].LastModified()
+	lt.NoError(err)
+	lt.Equal(lastModified, *modified)
+	lt.s3apiMock.AssertExpectations(lt.T())
+	lt.s3apiMock.AssertNotCalled(lt.T(), "HeadObject", mock.Anything)
+}
+
+func (lt *locationTestSuite) TestListInfo() {
+	lastModified := time.Now()
+	lt.s3apiMock.On("ListObjectsV2", mock.AnythingOfType("*s3.ListObjectsV2Input")).Return(&s3.ListObjectsV2Output{
+		Contents: []*s3.Object{
+			{Key: aws.String("dir1/file.txt"), Size: aws.Int64(10), LastModified: &lastModified, ETag: aws.String(`"abc"`)},
+		},
+		IsTruncated: aws.Bool(false),
+	}, nil).Once()
+
+	loc, err := lt.fs.NewLocation("bucket", "/dir1/")
+	lt.NoError(err)
+	infos, err := loc.(*Location).ListInfo()
+	lt.NoError(err)
+	lt.Len(infos, 1)
+	lt.Equal("file.txt", infos[0].Name())
+	lt.Equal(int64(10), infos[0].Size())
+	lt.Equal(lastModified, infos[0].ModTime())
+	lt.Equal("abc", infos[0].ETag)
+	lt.s3apiMock.AssertNotCalled(lt.T(), "HeadObject", mock.Anything)
+}
+
+func (lt *locationTestSuite) TestListWithPrefixes() {
+	lt.s3apiMock.On("ListObjectsV2", mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+		return *input.Prefix == "dir1/" && *input.Delimiter == "/" && input.ContinuationToken == nil
+	})).Return(&s3.ListObjectsV2Output{
+		Contents:              []*s3.Object{{Key: aws.String("dir1/")}, {Key: aws.String("dir1/file.txt"), Size: aws.Int64(10)}},
+		CommonPrefixes:        []*s3.CommonPrefix{{Prefix: aws.String("dir1/sub1/")}},
+		IsTruncated:           aws.Bool(true),
+		NextContinuationToken: aws.String("token"),
+	}, nil).Once()
+	lt.s3apiMock.On("ListObjectsV2", mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+		return aws.StringValue(input.ContinuationToken) == "token"
+	})).Return(&s3.ListObjectsV2Output{
+		CommonPrefixes: []*s3.CommonPrefix{{Prefix: aws.String("dir1/sub2/")}},
+		IsTruncated:    aws.Bool(false),
+	}, nil).Once()
+
+	loc, err := lt.fs.NewLocation("bucket", "/dir1/")
+	lt.NoError(err)
+	files, locations, err := loc.(*Location).ListWithPrefixes()
+	lt.NoError(err)
+	lt.Len(files, 1, "directory marker is skipped")
+	lt.Equal("s3://bucket/dir1/file.txt", files[0].URI())
+	lt.Len(locations, 2)
+	lt.Equal("s3://bucket/dir1/sub1/", locations[0].URI())
+	lt.Equal("/dir1/sub2/", locations[1].Path())
+	lt.s3apiMock.AssertExpectations(lt.T())
+}
+
+func (lt *locationTestSuite) TestListWithPrefixes_Error() {
+	lt.s3apiMock.On("ListObjectsV2", mock.AnythingOfType("*s3.ListObjectsV2Input")).Return(nil, errors.New("list error"))
+
+	loc, err := lt.fs.NewLocation("bucket", "/")
+	lt.NoError(err)
+	files, locations, err := loc.(*Location).ListWithPrefixes()
+	lt.EqualError(err, "list error")
+	lt.Empty(files)
+	lt.Empty(locations)
+}
+
+// folderMarkerObjects are the objects listed at "dir1/" by TestListFolderMarkers.
+func folderMarkerObjects() []*s3.Object {
+	return []*s3.Object{
+		{Key: aws.String("dir1/")},
+		{Key: aws.String("dir1/empty"), Size: aws.Int64(0)},
+		{Key: aws.String("dir1/file.txt"), Size: aws.Int64(10)},
+		{Key: aws.String("dir1/hadoop_$folder$"), Size: aws.Int64(0)},
+		{Key: aws.String("dir1/sub1/"), Size: aws.Int64(0)},
+		{Key: aws.String("dir1/sub2/"), Size: aws.Int64(5)},
+	}
+}
+
+func (lt *locationTestSuite) TestListFolderMarkers() {
+	lt.s3apiMock.On("ListObjects", mock.AnythingOfType("*s3.ListObjectsInput")).Return(&s3.ListObjectsOutput{
+		Contents:    folderMarkerObjects(),
+		IsTruncated: aws.Bool(false),
+	}, nil)
+	lt.s3apiMock.On("ListObjectsV2", mock.AnythingOfType("*s3.ListObjectsV2Input")).Return(&s3.ListObjectsV2Output{
+		Contents:       folderMarkerObjects(),
+		CommonPrefixes: []*s3.CommonPrefix{{Prefix: aws.String("dir1/sub1/")}},
+		IsTruncated:    aws.Bool(false),
+	}, nil)
+
+	for _, test := range []struct {
+		opts      Options
+		files     []string
+		locations []string
+	}{
+		{
+			opts:      Options{},
+			files:     []string{"empty", "file.txt", "hadoop_$folder$", "sub1/", "sub2/"},
+			locations: []string{"/dir1/sub1/"},
+		},
+		{
+			opts:      Options{ExcludeFolderMarkers: true},
+			files:     []string{"empty", "file.txt", "sub2/"},
+			locations: []string{"/dir1/sub1/"},
+		},
+		{
+			opts:      Options{FolderMarkersAsLocations: true},
+			files:     []string{"empty", "file.txt"},
+			locations: []string{"/dir1/hadoop/", "/dir1/sub1/", "/dir1/sub2/"},
+		},
+	} {
+		fs := &FileSystem{client: lt.s3apiMock, options: test.opts}
+		loc, err := fs.NewLocation("bucket", "/dir1/")
+		lt.NoError(err)
+
+		names, err := loc.List()
+		lt.NoError(err)
+		lt.Equal(test.files, names, "%+v", test.opts)
+
+		files, err := loc.(*Location).ListFiles()
+		lt.NoError(err)
+		lt.Len(files, len(test.files), "%+v", test.opts)
+
+		files, locations, err := loc.(*Location).ListWithPrefixes()
+		lt.NoError(err)
+		lt.Len(files, len(test.files), "%+v", test.opts)
+		var paths []string
+		for _, location := range locations {
+			paths = append(paths, location.Path())
+		}
+		sort.Strings(paths)
+		lt.Equal(test.locations, paths, "%+v", test.opts)
+	}
+}
+
+func (lt *locationTestSuite) TestWithOptions() {
+	loc, err := lt.fs.NewLocation("bucket", "/dir1/")
+	lt.NoError(err)
+	opts := Options{RoleARN: "arn:aws:iam::123456789012:role/writer", ExternalID: "external"}
+	roleLoc := loc.(*Location).WithOptions(opts)
+	lt.Equal(loc.URI(), roleLoc.URI())
+	lt.Equal(opts, roleLoc.FileSystem().(*FileSystem).options, "new location has its own options")
+	lt.Equal(lt.fs, loc.FileSystem(), "original location is unchanged")
+
+	file, err := roleLoc.NewFile("file.txt")
+	lt.NoError(err)
+	lt.Equal(roleLoc.FileSystem(), file.Location().FileSystem(), "files share the location's file system")
+}
+
 func (lt *locationTestSuite) TestListByPrefix() {
 	expectedFileList := []string{"file1.txt", "file2.txt"}
 	keyListFromAPI := []string{"dir1/file1.txt", "dir1/file2.txt"}
@@ -231,6 +418,60 @@ func (lt *locationTestSuite) TestExists_false() {
 	lt.s3apiMock.AssertExpectations(lt.T())
 }
 
+func (lt *locationTestSuite) TestPrefixExists() {
+	lt.s3apiMock.On("ListObjectsV2", mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+		return *input.Prefix == "delivery/" && input.Delimiter == nil && aws.Int64Value(input.MaxKeys) == 1
+	})).Return(&s3.ListObjectsV2Output{Contents: []*s3.Object{{Key: aws.String("delivery/sub/file.txt")}}}, nil).Once()
+	lt.s3apiMock.On("ListObjectsV2", mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+		return *input.Prefix == "missing/"
+	})).Return(&s3.ListObjectsV2Output{}, nil).Once()
+
+	loc, err := lt.fs.NewLocation("bucket", "/delivery/")
+	lt.NoError(err)
+	exists, err := loc.(*Location).PrefixExists()
+	lt.NoError(err)
+	lt.True(exists)
+
+	loc, err = lt.fs.NewLocation("bucket", "/missing/")
+	lt.NoError(err)
+	exists, err = loc.(*Location).PrefixExists()
+	lt.NoError(err)
+	lt.False(exists)
+	lt.s3apiMock.AssertExpectations(lt.T())
+}
+
+func (lt *locationTestSuite) TestStats() {
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now()
+	lt.s3apiMock.On("ListObjectsV2", mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+		return input.ContinuationToken == nil
+	})).Return(&s3.ListObjectsV2Output{
+		Contents: []*s3.Object{
+			{Key: aws.String("dir1/")},
+			{Key: aws.String("dir1/a.txt"), Size: aws.Int64(10), LastModified: &newer},
+		},
+		IsTruncated:           aws.Bool(true),
+		NextContinuationToken: aws.String("token"),
+	}, nil).Once()
+	lt.s3apiMock.On("ListObjectsV2", mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+		return aws.StringValue(input.ContinuationToken) == "token"
+	})).Return(&s3.ListObjectsV2Output{
+		Contents:    []*s3.Object{{Key: aws.String("dir1/b.txt"), Size: aws.Int64(5), LastModified: &older}},
+		IsTruncated: aws.Bool(false),
+	}, nil).Once()
+
+	loc, err := lt.fs.NewLocation("bucket", "/dir1/")
+	lt.NoError(err)
+	stats, err := loc.(*Location).Stats()
+	lt.NoError(err)
+	lt.Equal(LocationStats{FileCount: 2, TotalSize: 15, NewestModified: newer}, stats)
+
+	lt.s3apiMock.On("ListObjectsV2", mock.Anything).Return(nil, errors.New("list failed")).Once()
+	_, err = loc.(*Location).Stats()
+	lt.EqualError(err, "list failed")
+	lt.s3apiMock.AssertExpectations(lt.T())
+}
+
 func (lt *locationTestSuite) TestChangeDir() {
 	//test nil Location
 	var nilLoc *Location
@@ -0,0 +1,177 @@
+package s3
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// lockSuffix is appended to a file's key for the key of its lock object.
+const lockSuffix = ".lock"
+
+// lockRecord is the content of a lock object.
+type lockRecord struct {
+	Token   string    `json:"token"`
+	Expires time.Time `json:"expires"`
+}
+
+// lease is a vfs.Lease on a file, held by writing its lock object.  Each write of the lock object is conditional on
+// the ETag of the last, so a lease that expired and was taken by another holder can't be refreshed or released.
+type lease struct {
+	mu    sync.Mutex
+	file  *File
+	key   string
+	token string
+	etag  string
+}
+
+// Lock implements vfs.Locker, taking a lease on the file by creating the lock object <key>.lock beside it, which
+// expires after ttl.  The lock object is only created if it doesn't already exist, or replaced if the lease it records
+// has expired, with S3's conditional writes, so only one of the workers racing for it succeeds; the others get
+// vfs.ErrLocked.  Since expiry is decided by the clocks of the workers, they should be kept in sync.
+func (f *File) Lock(ttl time.Duration) (vfs.Lease, error) {
+	if ttl <= 0 {
+		return nil, errors.New("positive ttl is required")
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	l := &lease{file: f, key: keyOf(f.key) + lockSuffix, token: hex.EncodeToString(token)}
+
+	err := l.put(ttl, "If-None-Match", "*")
+	if err == nil {
+		return l, nil
+	}
+	if !isConditionFailed(err) {
+		return nil, err
+	}
+
+	// the lock object exists, so take it over only if the lease it records has expired
+	record, etag, err := l.read()
+	if err != nil {
+		if isNotFound(err) {
+			// released in the meantime, most likely to another worker's Lock
+			return nil, vfs.ErrLocked
+		}
+		return nil, err
+	}
+	if time.Now().Before(record.Expires) {
+		return nil, vfs.ErrLocked
+	}
+	if err := l.put(ttl, "If-Match", etag); err != nil {
+		if isConditionFailed(err) {
+			return nil, vfs.ErrLocked
+		}
+		return nil, err
+	}
+	return l, nil
+}
+
+// Refresh implements vfs.Lease.
+func (l *lease) Refresh(ttl time.Duration) error {
+	if ttl <= 0 {
+		return errors.New("positive ttl is required")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.put(ttl, "If-Match", l.etag); err != nil {
+		if isConditionFailed(err) || isNotFound(err) {
+			return vfs.ErrLocked
+		}
+		return err
+	}
+	return nil
+}
+
+// Release implements vfs.Lease, deleting the lock object.
+func (l *lease) Release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	client, err := l.file.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+	req, _ := client.DeleteObjectRequest(new(s3.DeleteObjectInput).SetBucket(l.file.bucket).SetKey(l.key))
+	req.HTTPRequest.Header.Set("If-Match", l.etag)
+	err = req.Send()
+	switch {
+	case err == nil, isNotFound(err):
+		return nil
+	case isConditionFailed(err):
+		return vfs.ErrLocked
+	}
+	return err
+}
+
+// put writes the lock object, recording the lease until ttl from now, with the conditional header header set to value.
+func (l *lease) put(ttl time.Duration, header, value string) error {
+	client, err := l.file.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(lockRecord{Token: l.token, Expires: time.Now().Add(ttl).UTC()})
+	if err != nil {
+		return err
+	}
+
+	req, output := client.PutObjectRequest(new(s3.PutObjectInput).
+		SetBucket(l.file.bucket).
+		SetKey(l.key).
+		SetBody(bytes.NewReader(body)).
+		SetContentType("application/json").
+		SetServerSideEncryption("AES256"))
+	req.HTTPRequest.Header.Set(header, value)
+	if err := req.Send(); err != nil {
+		return err
+	}
+	l.etag = aws.StringValue(output.ETag)
+	return nil
+}
+
+// read returns the lock object's record and ETag.
+func (l *lease) read() (lockRecord, string, error) {
+	var record lockRecord
+	client, err := l.file.fileSystem.Client()
+	if err != nil {
+		return record, "", err
+	}
+	output, err := client.GetObject(new(s3.GetObjectInput).SetBucket(l.file.bucket).SetKey(l.key))
+	if err != nil {
+		return record, "", err
+	}
+	defer func() { _ = output.Body.Close() }()
+
+	body, err := ioutil.ReadAll(output.Body)
+	if err != nil {
+		return record, "", err
+	}
+	if err := json.Unmarshal(body, &record); err != nil {
+		return record, "", err
+	}
+	return record, aws.StringValue(output.ETag), nil
+}
+
+// isConditionFailed returns true if err is s3's response to a conditional write whose condition wasn't met, or which
+// conflicted with another conditional write of the same key.
+func isConditionFailed(err error) bool {
+	if failure, ok := err.(awserr.RequestFailure); ok {
+		return failure.StatusCode() == http.StatusPreconditionFailed || failure.StatusCode() == http.StatusConflict
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type lockTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	file      *File
+	// headers are the headers of the requests sent
+	headers []http.Header
+}
+
+func (ts *lockTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	ts.headers = nil
+	fs := &FileSystem{client: ts.s3apiMock, options: Options{}}
+	file, err := fs.NewFile("bucket", "/some/file.txt")
+	ts.NoError(err)
+	ts.file = file.(*File)
+}
+
+// request returns a request which fails with statusCode, if it isn't 0, recording its headers.
+func (ts *lockTestSuite) request(statusCode int) *request.Request {
+	req := &request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}}
+	ts.headers = append(ts.headers, req.HTTPRequest.Header)
+	if statusCode != 0 {
+		req.Error = awserr.NewRequestFailure(awserr.New("PreconditionFailed", "failed", nil), statusCode, "")
+	}
+	return req
+}
+
+// expectPut expects a put of the lock object, failing with statusCode if it isn't 0.
+func (ts *lockTestSuite) expectPut(statusCode int, etag string) {
+	ts.s3apiMock.On("PutObjectRequest", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return *input.Key == "some/file.txt.lock"
+	})).Return(ts.request(statusCode), &s3.PutObjectOutput{ETag: aws.String(etag)}).Once()
+}
+
+// expectRead expects a read of the lock object, recording a lease expiring at expires.
+func (ts *lockTestSuite) expectRead(expires time.Time) {
+	body, err := json.Marshal(lockRecord{Token: "other", Expires: expires})
+	ts.NoError(err)
+	ts.s3apiMock.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).Return(&s3.GetObjectOutput{
+		Body: ioutil.NopCloser(bytes.NewReader(body)),
+		ETag: aws.String("held"),
+	}, nil).Once()
+}
+
+func (ts *lockTestSuite) TestLock() {
+	ts.expectPut(0, "etag1")
+	lease, err := vfs.Lock(ts.file, time.Minute)
+	ts.NoError(err)
+	ts.Equal("*", ts.headers[0].Get("If-None-Match"), "the lock object is only created if it doesn't exist")
+
+	ts.expectPut(0, "etag2")
+	ts.NoError(lease.Refresh(time.Minute))
+	ts.Equal("etag1", ts.headers[1].Get("If-Match"), "refreshes are conditional on the last write")
+
+	ts.s3apiMock.On("DeleteObjectRequest", mock.MatchedBy(func(input *s3.DeleteObjectInput) bool {
+		return *input.Key == "some/file.txt.lock"
+	})).Return(ts.request(0), &s3.DeleteObjectOutput{}).Once()
+	ts.NoError(lease.Release())
+	ts.Equal("etag2", ts.headers[2].Get("If-Match"))
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *lockTestSuite) TestLocked() {
+	ts.expectPut(http.StatusPreconditionFailed, "")
+	ts.expectRead(time.Now().Add(time.Minute))
+	_, err := ts.file.Lock(time.Minute)
+	ts.Equal(vfs.ErrLocked, err, "unexpired leases aren't taken over")
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *lockTestSuite) TestLockExpired() {
+	ts.expectPut(http.StatusPreconditionFailed, "")
+	ts.expectRead(time.Now().Add(-time.Minute))
+	ts.expectPut(0, "etag1")
+	_, err := ts.file.Lock(time.Minute)
+	ts.NoError(err)
+	ts.Equal("held", ts.headers[1].Get("If-Match"), "expired leases are replaced only if they're unchanged")
+
+	// another worker replaced the expired lease first
+	ts.expectPut(http.StatusPreconditionFailed, "")
+	ts.expectRead(time.Now().Add(-time.Minute))
+	ts.expectPut(http.StatusPreconditionFailed, "")
+	_, err = ts.file.Lock(time.Minute)
+	ts.Equal(vfs.ErrLocked, err)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *lockTestSuite) TestLostLease() {
+	ts.expectPut(0, "etag1")
+	lease, err := ts.file.Lock(time.Minute)
+	ts.NoError(err)
+
+	ts.expectPut(http.StatusPreconditionFailed, "")
+	ts.Equal(vfs.ErrLocked, lease.Refresh(time.Minute))
+	ts.s3apiMock.On("DeleteObjectRequest", mock.AnythingOfType("*s3.DeleteObjectInput")).
+		Return(ts.request(http.StatusPreconditionFailed), &s3.DeleteObjectOutput{}).Once()
+	ts.Equal(vfs.ErrLocked, lease.Release(), "another holder's lock object isn't deleted")
+}
+
+func (ts *lockTestSuite) TestLockErrors() {
+	_, err := ts.file.Lock(0)
+	ts.EqualError(err, "positive ttl is required")
+
+	ts.expectPut(http.StatusForbidden, "")
+	_, err = ts.file.Lock(time.Minute)
+	ts.Error(err)
+	ts.Equal(vfs.ErrorPermission, vfs.ClassifyError(err))
+
+	ts.expectPut(http.StatusPreconditionFailed, "")
+	ts.s3apiMock.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).
+		Return(nil, errors.New("network error")).Once()
+	_, err = ts.file.Lock(time.Minute)
+	ts.EqualError(err, "network error")
+}
+
+func TestLock(t *testing.T) {
+	suite.Run(t, new(lockTestSuite))
+}
@@ -0,0 +1,397 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// MultipartUpload describes an in-progress (not yet completed or aborted) multipart upload.
+type MultipartUpload struct {
+	Key       string
+	UploadID  string
+	Initiated time.Time
+}
+
+// UploadedPart is a part uploaded to a multipart upload, identified by its part number and the ETag s3 returned for it.
+type UploadedPart struct {
+	PartNumber int64
+	ETag       string
+	Size       int64
+}
+
+// MultipartUploads returns the in-progress multipart uploads for the file's key, oldest first.  Uploads are only left
+// in progress after a failure when Options.LeavePartsOnError is true.
+func (f *File) MultipartUploads() ([]MultipartUpload, error) {
+	key := keyOf(f.key)
+	var uploads []MultipartUpload
+	err := listMultipartUploads(f.fileSystem, f.bucket, key, func(upload MultipartUpload) {
+		if upload.Key == key {
+			uploads = append(uploads, upload)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
+
+// AbortMultipartUpload aborts the given in-progress multipart upload of the file, removing any parts already uploaded.
+func (f *File) AbortMultipartUpload(uploadID string) error {
+	if uploadID == "" {
+		return errors.New("non-empty string uploadID is required")
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.AbortMultipartUpload(new(s3.AbortMultipartUploadInput).
+		SetBucket(f.bucket).
+		SetKey(keyOf(f.key)).
+		SetUploadId(uploadID))
+	return err
+}
+
+// ResumeMultipartUpload completes an interrupted multipart upload of the file.  source must provide the same size
+// bytes that were originally being uploaded, and partSize must be the part size they were uploaded in:
+// Options.UploadPartSize, or s3manager.DefaultUploadPartSize if it wasn't set.  Only the parts not already on s3 are
+// read and uploaded before the upload is completed.  An error is returned, rather than an object assembled from
+// mismatched parts, if any part already uploaded isn't the size partSize implies for it.
+func (f *File) ResumeMultipartUpload(uploadID string, source io.ReaderAt, size, partSize int64) error {
+	if uploadID == "" {
+		return errors.New("non-empty string uploadID is required")
+	}
+	if source == nil || size < 1 {
+		return errors.New("non-nil source and positive size are required")
+	}
+	if partSize < 1 {
+		return errors.New("positive partSize is required")
+	}
+	partCount := (size + partSize - 1) / partSize
+	if partCount > s3manager.MaxUploadParts {
+		return fmt.Errorf("a part size of %d splits %d bytes into %d parts, more than the %d s3 allows",
+			partSize, size, partCount, s3manager.MaxUploadParts)
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	uploaded, err := f.listParts(uploadID)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range uploaded {
+		partNumber := aws.Int64Value(part.PartNumber)
+		if partNumber < 1 || partNumber > partCount {
+			return fmt.Errorf("part %d of upload %s is beyond the %d parts of %d bytes in a part size of %d",
+				partNumber, uploadID, partCount, size, partSize)
+		}
+		expected := partSize
+		if partNumber == partCount {
+			expected = size - (partCount-1)*partSize
+		}
+		if aws.Int64Value(part.Size) != expected {
+			return fmt.Errorf("part %d of upload %s is %d bytes, not the %d expected for a part size of %d",
+				partNumber, uploadID, aws.Int64Value(part.Size), expected, partSize)
+		}
+	}
+
+	completed := make(map[int64]*s3.CompletedPart, len(uploaded))
+	for _, part := range uploaded {
+		completed[aws.Int64Value(part.PartNumber)] = new(s3.CompletedPart).
+			SetPartNumber(aws.Int64Value(part.PartNumber)).
+			SetETag(aws.StringValue(part.ETag))
+	}
+
+	for partNumber, offset := int64(1), int64(0); offset < size; partNumber, offset = partNumber+1, offset+partSize {
+		if _, ok := completed[partNumber]; ok {
+			continue
+		}
+
+		length := partSize
+		if offset+length > size {
+			length = size - offset
+		}
+
+		output, err := client.UploadPart(new(s3.UploadPartInput).
+			SetBucket(f.bucket).
+			SetKey(keyOf(f.key)).
+			SetUploadId(uploadID).
+			SetPartNumber(partNumber).
+			SetContentLength(length).
+			SetBody(io.NewSectionReader(source, offset, length)))
+		if err != nil {
+			return fmt.Errorf("unable to upload part %d of %s: %s", partNumber, f, err.Error())
+		}
+		completed[partNumber] = new(s3.CompletedPart).SetPartNumber(partNumber).SetETag(aws.StringValue(output.ETag))
+	}
+
+	parts := make([]*s3.CompletedPart, 0, len(completed))
+	for _, part := range completed {
+		parts = append(parts, part)
+	}
+	sort.Slice(parts, func(i, j int) bool { return *parts[i].PartNumber < *parts[j].PartNumber })
+
+	_, err = client.CompleteMultipartUpload(new(s3.CompleteMultipartUploadInput).
+		SetBucket(f.bucket).
+		SetKey(keyOf(f.key)).
+		SetUploadId(uploadID).
+		SetMultipartUpload(new(s3.CompletedMultipartUpload).SetParts(parts)))
+	f.invalidateHead()
+	return err
+}
+
+// CreateMultipartUpload starts a multipart upload to the file, returning its upload ID.  Parts are then uploaded with
+// File.UploadPart, or by a client given URLs from File.PresignUploadPart, and the upload finished with
+// File.CompleteMultipartUpload or discarded with File.AbortMultipartUpload.  The upload is created with the same
+// encryption, ACL, Object Lock and content type options as any other upload of the file.
+func (f *File) CreateMultipartUpload() (string, error) {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return "", err
+	}
+
+	upload := uploadInput(f)
+	input := new(s3.CreateMultipartUploadInput).
+		SetBucket(f.bucket).
+		SetKey(keyOf(f.key)).
+		SetServerSideEncryption(aws.StringValue(upload.ServerSideEncryption))
+	input.ACL = upload.ACL
+	input.ObjectLockMode = upload.ObjectLockMode
+	input.ObjectLockRetainUntilDate = upload.ObjectLockRetainUntilDate
+	input.ObjectLockLegalHoldStatus = upload.ObjectLockLegalHoldStatus
+	opts, _ := f.fileSystem.options.(Options)
+	if contentType := f.contentType(opts, nil); contentType != "" {
+		input.SetContentType(contentType)
+	}
+
+	output, err := client.CreateMultipartUpload(input)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(output.UploadId), nil
+}
+
+// UploadPart uploads body as the part numbered partNumber, from 1 to 10,000, of the given multipart upload of the
+// file.  Every part but the last must be at least 5MB.  Uploading a part number again replaces the earlier part.
+func (f *File) UploadPart(uploadID string, partNumber int64, body io.ReadSeeker) (UploadedPart, error) {
+	if uploadID == "" {
+		return UploadedPart{}, errors.New("non-empty string uploadID is required")
+	}
+	if body == nil {
+		return UploadedPart{}, errors.New("non-nil body is required")
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return UploadedPart{}, err
+	}
+
+	size, err := body.Seek(0, io.SeekEnd)
+	if err != nil {
+		return UploadedPart{}, err
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return UploadedPart{}, err
+	}
+
+	output, err := client.UploadPart(new(s3.UploadPartInput).
+		SetBucket(f.bucket).
+		SetKey(keyOf(f.key)).
+		SetUploadId(uploadID).
+		SetPartNumber(partNumber).
+		SetContentLength(size).
+		SetBody(body))
+	if err != nil {
+		return UploadedPart{}, fmt.Errorf("unable to upload part %d of %s: %s", partNumber, f, err.Error())
+	}
+	return UploadedPart{PartNumber: partNumber, ETag: aws.StringValue(output.ETag), Size: size}, nil
+}
+
+// PresignUploadPart returns a URL, valid for expires, which a client such as a browser can PUT the part numbered
+// partNumber of the given multipart upload to, without credentials of its own.  The ETag response header of the PUT
+// is the part's ETag, for File.CompleteMultipartUpload.  Since the URL is signed with the client's credentials, the
+// client must be one created from Options.
+func (f *File) PresignUploadPart(uploadID string, partNumber int64, expires time.Duration) (string, error) {
+	if uploadID == "" {
+		return "", errors.New("non-empty string uploadID is required")
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return "", err
+	}
+
+	req, _ := client.UploadPartRequest(new(s3.UploadPartInput).
+		SetBucket(f.bucket).
+		SetKey(keyOf(f.key)).
+		SetUploadId(uploadID).
+		SetPartNumber(partNumber))
+	return req.Presign(expires)
+}
+
+// Parts returns the parts already uploaded to the given multipart upload of the file, in part number order.
+func (f *File) Parts(uploadID string) ([]UploadedPart, error) {
+	if uploadID == "" {
+		return nil, errors.New("non-empty string uploadID is required")
+	}
+
+	listed, err := f.listParts(uploadID)
+	if err != nil {
+		return nil, err
+	}
+	parts := make([]UploadedPart, 0, len(listed))
+	for _, part := range listed {
+		parts = append(parts, UploadedPart{
+			PartNumber: aws.Int64Value(part.PartNumber),
+			ETag:       aws.StringValue(part.ETag),
+			Size:       aws.Int64Value(part.Size),
+		})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	return parts, nil
+}
+
+// CompleteMultipartUpload finishes the given multipart upload of the file, assembling parts into the object.  If parts
+// is nil, every part already uploaded is used, as listed by File.Parts.
+func (f *File) CompleteMultipartUpload(uploadID string, parts []UploadedPart) error {
+	if uploadID == "" {
+		return errors.New("non-empty string uploadID is required")
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	if parts == nil {
+		if parts, err = f.Parts(uploadID); err != nil {
+			return err
+		}
+	}
+	if len(parts) == 0 {
+		return errors.New("at least one uploaded part is required")
+	}
+
+	completed := make([]*s3.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, new(s3.CompletedPart).SetPartNumber(part.PartNumber).SetETag(part.ETag))
+	}
+	sort.Slice(completed, func(i, j int) bool { return *completed[i].PartNumber < *completed[j].PartNumber })
+
+	_, err = client.CompleteMultipartUpload(new(s3.CompleteMultipartUploadInput).
+		SetBucket(f.bucket).
+		SetKey(keyOf(f.key)).
+		SetUploadId(uploadID).
+		SetMultipartUpload(new(s3.CompletedMultipartUpload).SetParts(completed)))
+	f.invalidateHead()
+	return err
+}
+
+// AbortIncompleteUploads aborts every in-progress multipart upload in the bucket that was initiated more than olderThan
+// ago, removing their orphaned parts.  The number of uploads aborted is returned.
+func (fs *FileSystem) AbortIncompleteUploads(bucket string, olderThan time.Duration) (int, error) {
+	if bucket == "" {
+		return 0, errors.New("non-empty string bucket is required")
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	var stale []MultipartUpload
+	err := listMultipartUploads(fs, bucket, "", func(upload MultipartUpload) {
+		if upload.Initiated.Before(cutoff) {
+			stale = append(stale, upload)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	client, err := fs.Client()
+	if err != nil {
+		return 0, err
+	}
+
+	for i, upload := range stale {
+		_, err := client.AbortMultipartUpload(new(s3.AbortMultipartUploadInput).
+			SetBucket(bucket).
+			SetKey(upload.Key).
+			SetUploadId(upload.UploadID))
+		if err != nil {
+			return i, fmt.Errorf("unable to abort upload %s of s3://%s/%s: %s", upload.UploadID, bucket, upload.Key, err.Error())
+		}
+	}
+
+	return len(stale), nil
+}
+
+// listParts returns all parts already uploaded for the given multipart upload of the file.
+func (f *File) listParts(uploadID string) ([]*s3.Part, error) {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	input := new(s3.ListPartsInput).SetBucket(f.bucket).SetKey(keyOf(f.key)).SetUploadId(uploadID)
+	var parts []*s3.Part
+	for {
+		output, err := client.ListParts(input)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, output.Parts...)
+
+		if aws.BoolValue(output.IsTruncated) {
+			input.SetPartNumberMarker(aws.Int64Value(output.NextPartNumberMarker))
+		} else {
+			break
+		}
+	}
+	return parts, nil
+}
+
+// listMultipartUploads pages through ListMultipartUploads for keys beginning with prefix, passing each upload to fn.
+func listMultipartUploads(fs *FileSystem, bucket, prefix string, fn func(MultipartUpload)) error {
+	client, err := fs.Client()
+	if err != nil {
+		return err
+	}
+
+	input := new(s3.ListMultipartUploadsInput).SetBucket(bucket)
+	if prefix != "" {
+		input.SetPrefix(prefix)
+	}
+
+	for {
+		output, err := client.ListMultipartUploads(input)
+		if err != nil {
+			return err
+		}
+		for _, upload := range output.Uploads {
+			fn(MultipartUpload{
+				Key:       aws.StringValue(upload.Key),
+				UploadID:  aws.StringValue(upload.UploadId),
+				Initiated: aws.TimeValue(upload.Initiated),
+			})
+		}
+
+		// if s3 response "IsTruncated" we need to call again with updated key and upload id markers
+		if aws.BoolValue(output.IsTruncated) {
+			input.SetKeyMarker(aws.StringValue(output.NextKeyMarker))
+			input.SetUploadIdMarker(aws.StringValue(output.NextUploadIdMarker))
+		} else {
+			break
+		}
+	}
+	return nil
+}
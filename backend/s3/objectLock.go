@@ -0,0 +1,135 @@
+package s3
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// Retention holds the Object Lock retention settings of an s3 object.  Mode is one of s3.ObjectLockRetentionModeGovernance
+// or s3.ObjectLockRetentionModeCompliance.
+type Retention struct {
+	Mode        string
+	RetainUntil time.Time
+}
+
+// SetRetention places (or extends) an Object Lock retention period on the file.  The bucket must have Object Lock
+// enabled.
+func (f *File) SetRetention(retention Retention) error {
+	if retention.Mode == "" || retention.RetainUntil.IsZero() {
+		return errors.New("non-empty retention mode and retain until date are required")
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	input := new(s3.PutObjectRetentionInput).
+		SetBucket(f.bucket).
+		SetKey(keyOf(f.key)).
+		SetRetention(new(s3.ObjectLockRetention).
+			SetMode(retention.Mode).
+			SetRetainUntilDate(retention.RetainUntil))
+	if f.versionID != "" {
+		input.SetVersionId(f.versionID)
+	}
+
+	_, err = client.PutObjectRetention(input)
+	f.invalidateHead()
+	return err
+}
+
+// Retention returns the file's current Object Lock retention settings.  A zero Retention is returned if none is set.
+func (f *File) Retention() (Retention, error) {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return Retention{}, err
+	}
+
+	input := new(s3.GetObjectRetentionInput).SetBucket(f.bucket).SetKey(keyOf(f.key))
+	if f.versionID != "" {
+		input.SetVersionId(f.versionID)
+	}
+
+	output, err := client.GetObjectRetention(input)
+	if err != nil {
+		return Retention{}, err
+	}
+	if output.Retention == nil {
+		return Retention{}, nil
+	}
+	return Retention{
+		Mode:        aws.StringValue(output.Retention.Mode),
+		RetainUntil: aws.TimeValue(output.Retention.RetainUntilDate),
+	}, nil
+}
+
+// SetLegalHold turns the Object Lock legal hold on the file on or off.
+func (f *File) SetLegalHold(on bool) error {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	status := s3.ObjectLockLegalHoldStatusOff
+	if on {
+		status = s3.ObjectLockLegalHoldStatusOn
+	}
+
+	input := new(s3.PutObjectLegalHoldInput).
+		SetBucket(f.bucket).
+		SetKey(keyOf(f.key)).
+		SetLegalHold(new(s3.ObjectLockLegalHold).SetStatus(status))
+	if f.versionID != "" {
+		input.SetVersionId(f.versionID)
+	}
+
+	_, err = client.PutObjectLegalHold(input)
+	f.invalidateHead()
+	return err
+}
+
+// LegalHold returns true if an Object Lock legal hold is in place on the file.
+func (f *File) LegalHold() (bool, error) {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return false, err
+	}
+
+	input := new(s3.GetObjectLegalHoldInput).SetBucket(f.bucket).SetKey(keyOf(f.key))
+	if f.versionID != "" {
+		input.SetVersionId(f.versionID)
+	}
+
+	output, err := client.GetObjectLegalHold(input)
+	if err != nil {
+		return false, err
+	}
+	return output.LegalHold != nil && aws.StringValue(output.LegalHold.Status) == s3.ObjectLockLegalHoldStatusOn, nil
+}
+
+// applyObjectLockToUpload sets the Object Lock options, if any, on an upload.
+func applyObjectLockToUpload(opts Options, input *s3manager.UploadInput) {
+	if opts.ObjectLockMode != "" && opts.ObjectLockRetention > 0 {
+		input.ObjectLockMode = aws.String(opts.ObjectLockMode)
+		input.ObjectLockRetainUntilDate = aws.Time(time.Now().Add(opts.ObjectLockRetention))
+	}
+	if opts.ObjectLockLegalHold {
+		input.ObjectLockLegalHoldStatus = aws.String(s3.ObjectLockLegalHoldStatusOn)
+	}
+}
+
+// applyObjectLockToCopy sets the Object Lock options, if any, on a native copy.
+func applyObjectLockToCopy(opts Options, input *s3.CopyObjectInput) {
+	if opts.ObjectLockMode != "" && opts.ObjectLockRetention > 0 {
+		input.SetObjectLockMode(opts.ObjectLockMode)
+		input.SetObjectLockRetainUntilDate(time.Now().Add(opts.ObjectLockRetention))
+	}
+	if opts.ObjectLockLegalHold {
+		input.SetObjectLockLegalHoldStatus(s3.ObjectLockLegalHoldStatusOn)
+	}
+}
@@ -0,0 +1,93 @@
+package s3
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type objectLockTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	file      *File
+}
+
+func (ts *objectLockTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	fs := &FileSystem{client: ts.s3apiMock, options: Options{AccessKeyID: "abc"}}
+	file, err := fs.NewFile("bucket", "/some/path/file.txt")
+	ts.NoError(err)
+	ts.file = file.(*File)
+}
+
+func (ts *objectLockTestSuite) TestSetRetention() {
+	err := ts.file.SetRetention(Retention{Mode: s3.ObjectLockRetentionModeCompliance})
+	ts.EqualError(err, "non-empty retention mode and retain until date are required")
+
+	until := time.Now().Add(time.Hour)
+	ts.s3apiMock.On("PutObjectRetention", mock.MatchedBy(func(input *s3.PutObjectRetentionInput) bool {
+		return *input.Retention.Mode == s3.ObjectLockRetentionModeCompliance && input.Retention.RetainUntilDate.Equal(until)
+	})).Return(&s3.PutObjectRetentionOutput{}, nil)
+
+	ts.NoError(ts.file.SetRetention(Retention{Mode: s3.ObjectLockRetentionModeCompliance, RetainUntil: until}))
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *objectLockTestSuite) TestRetention() {
+	until := time.Now()
+	ts.s3apiMock.On("GetObjectRetention", mock.AnythingOfType("*s3.GetObjectRetentionInput")).Return(&s3.GetObjectRetentionOutput{
+		Retention: &s3.ObjectLockRetention{Mode: aws.String(s3.ObjectLockRetentionModeGovernance), RetainUntilDate: &until},
+	}, nil).Once()
+
+	retention, err := ts.file.Retention()
+	ts.NoError(err)
+	ts.Equal(Retention{Mode: s3.ObjectLockRetentionModeGovernance, RetainUntil: until}, retention)
+
+	ts.s3apiMock.On("GetObjectRetention", mock.AnythingOfType("*s3.GetObjectRetentionInput")).
+		Return(nil, errors.New("no retention")).Once()
+	_, err = ts.file.Retention()
+	ts.EqualError(err, "no retention")
+}
+
+func (ts *objectLockTestSuite) TestLegalHold() {
+	ts.s3apiMock.On("PutObjectLegalHold", mock.MatchedBy(func(input *s3.PutObjectLegalHoldInput) bool {
+		return *input.LegalHold.Status == s3.ObjectLockLegalHoldStatusOn
+	})).Return(&s3.PutObjectLegalHoldOutput{}, nil)
+	ts.NoError(ts.file.SetLegalHold(true))
+
+	ts.s3apiMock.On("GetObjectLegalHold", mock.AnythingOfType("*s3.GetObjectLegalHoldInput")).Return(&s3.GetObjectLegalHoldOutput{
+		LegalHold: &s3.ObjectLockLegalHold{Status: aws.String(s3.ObjectLockLegalHoldStatusOn)},
+	}, nil)
+	on, err := ts.file.LegalHold()
+	ts.NoError(err)
+	ts.True(on)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *objectLockTestSuite) TestUploadInput() {
+	ts.file.fileSystem.options = Options{
+		ObjectLockMode:      s3.ObjectLockModeGovernance,
+		ObjectLockRetention: time.Hour,
+		ObjectLockLegalHold: true,
+	}
+	input := uploadInput(ts.file)
+	ts.Equal(s3.ObjectLockModeGovernance, *input.ObjectLockMode)
+	ts.WithinDuration(time.Now().Add(time.Hour), *input.ObjectLockRetainUntilDate, time.Minute)
+	ts.Equal(s3.ObjectLockLegalHoldStatusOn, *input.ObjectLockLegalHoldStatus)
+
+	ts.file.fileSystem.options = Options{ObjectLockMode: s3.ObjectLockModeGovernance}
+	input = uploadInput(ts.file)
+	ts.Nil(input.ObjectLockMode, "retention requires both mode and period")
+	ts.Nil(input.ObjectLockLegalHoldStatus)
+}
+
+func TestObjectLock(t *testing.T) {
+	suite.Run(t, new(objectLockTestSuite))
+}
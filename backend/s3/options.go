@@ -1,6 +1,8 @@
 package s3
 
 import (
+	"crypto/tls"
+	"errors"
 	"net/http"
 	"os"
 	"time"
@@ -14,6 +16,9 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"github.com/c2fo/vfs/v5"
 )
 
 // Options holds s3-specific options.  Currently only client options are used.
@@ -24,51 +29,247 @@ type Options struct {
 	Region          string `json:"region,omitempty"`
 	Endpoint        string `json:"endpoint,omitempty"`
 	ACL             string `json:"acl,omitempty"`
-	Retry           request.Retryer
-	MaxRetries      int
+	UseAccelerate   bool   `json:"useAccelerate,omitempty"`
+	// ForcePathStyle addresses buckets in the request path (https://endpoint/bucket/key) rather than the host name
+	// (https://bucket.endpoint/key), as S3-compatible servers such as MinIO and LocalStack generally require.
+	ForcePathStyle bool `json:"forcePathStyle,omitempty"`
+	// ObjectLockMode and ObjectLockRetention set an Object Lock retention period on all writes and copies, ie:
+	// s3.ObjectLockModeCompliance for 7 * 24 * time.Hour. Both must be set for retention to be applied.
+	ObjectLockMode      string        `json:"objectLockMode,omitempty"`
+	ObjectLockRetention time.Duration `json:"objectLockRetention,omitempty"`
+	// ObjectLockLegalHold places a legal hold on all writes and copies.
+	ObjectLockLegalHold bool `json:"objectLockLegalHold,omitempty"`
+	// DownloadConcurrency, when greater than 1, downloads objects for Read and Seek with that many parallel ranged
+	// GETs of DownloadPartSize bytes each (defaults to s3manager.DefaultDownloadPartSize).
+	DownloadConcurrency int   `json:"downloadConcurrency,omitempty"`
+	DownloadPartSize    int64 `json:"downloadPartSize,omitempty"`
+	// UploadPartSize, UploadConcurrency and LeavePartsOnError tune multipart uploads made by s3manager.Uploader.
+	// Zero values use the s3manager defaults.
+	UploadPartSize    int64 `json:"uploadPartSize,omitempty"`
+	UploadConcurrency int   `json:"uploadConcurrency,omitempty"`
+	LeavePartsOnError bool  `json:"leavePartsOnError,omitempty"`
+	// ContentType, when set, is applied to all uploads.  Otherwise the content type of each upload is detected from
+	// its file extension or, failing that, its first 512 bytes, unless DisableContentTypeDetection is set.  Objects
+	// without a detected content type are stored by s3 as binary/octet-stream.
+	ContentType                 string `json:"contentType,omitempty"`
+	DisableContentTypeDetection bool   `json:"disableContentTypeDetection,omitempty"`
+	// ComputeChecksums computes MD5 and SHA256 digests of data as it's written.  The MD5 is sent as the Content-MD5 of
+	// single part uploads so s3 verifies what it received, and both are available from File.Checksums after Close.
+	ComputeChecksums bool `json:"computeChecksums,omitempty"`
+	// VerifyWrites checks each object after it's uploaded by Close, Flush or a writer from OpenWriter, failing them
+	// with a *WriteVerificationError if its size doesn't match what was written or, where its ETag is the MD5 of its
+	// contents (single part uploads not encrypted with SSE-KMS or SSE-C), its MD5 doesn't either.  Checksums are
+	// computed as data is written, as with ComputeChecksums.  The data written is kept after a failed Close or Flush, so
+	// it can be retried.
+	VerifyWrites bool `json:"verifyWrites,omitempty"`
+	// MaxWriteBufferMemory caps the memory used to buffer a file's writes until Close, ie: 16 * 1024 * 1024.  Once a
+	// file's writes exceed it, they're moved to a temp file instead.  Zero buffers all writes in memory.
+	MaxWriteBufferMemory int64 `json:"maxWriteBufferMemory,omitempty"`
+	// TempDir is the directory temp files are created in, both those objects are downloaded to for Read and Seek and
+	// those writes spill to (see MaxWriteBufferMemory).  It defaults to os.TempDir().  MaxTempUsage, when set, caps the
+	// total size of the objects downloaded to temp files by a FileSystem's files at any one time; reads that would
+	// exceed it fail rather than fill the disk.  See also FileSystem.CleanupTempFiles.
+	TempDir      string `json:"tempDir,omitempty"`
+	MaxTempUsage int64  `json:"maxTempUsage,omitempty"`
+	// RoleARN, when set, is assumed using the credentials otherwise resolved from these Options (see Authentication),
+	// or using the OIDC token in WebIdentityTokenFile if that is set.  ExternalID is passed along when assuming the
+	// role, as required by many cross-account trust policies.  RoleSessionName defaults to a generated name and
+	// RoleDuration to the sts default of 15 minutes.  Assumed role credentials are refreshed automatically,
+	// CredentialExpiryWindow before they expire.
+	RoleARN                string        `json:"roleArn,omitempty"`
+	ExternalID             string        `json:"externalId,omitempty"`
+	RoleSessionName        string        `json:"roleSessionName,omitempty"`
+	RoleDuration           time.Duration `json:"roleDuration,omitempty"`
+	WebIdentityTokenFile   string        `json:"webIdentityTokenFile,omitempty"`
+	CredentialExpiryWindow time.Duration `json:"credentialExpiryWindow,omitempty"`
+	// OperationTimeout bounds each s3 API call, including all of its retries, up to when its response arrives; reading
+	// a GetObject body afterwards isn't bounded.  RequestsPerSecond, when set, limits the rate of s3 API calls (retries
+	// included) made by the client, allowing bursts of up to RequestBurst calls.  These only apply to clients created
+	// from Options, not those passed in with WithClient.
+	OperationTimeout  time.Duration `json:"operationTimeout,omitempty"`
+	RequestsPerSecond float64       `json:"requestsPerSecond,omitempty"`
+	RequestBurst      int           `json:"requestBurst,omitempty"`
+	// DisableMoveVerification skips checking that a file moved to another scheme was copied in full (by comparing
+	// sizes and checksums, see utils.VerifyCopy) before the source is deleted.
+	DisableMoveVerification bool `json:"disableMoveVerification,omitempty"`
+	// ConflictPolicy determines what CopyToLocation and MoveToLocation do when the target file already exists.
+	ConflictPolicy vfs.ConflictPolicy `json:"conflictPolicy,omitempty"`
+	Retry          request.Retryer
+	MaxRetries     int
+	// WaitForWrite is how Close waits for a file it wrote to become visible, ie: NoWait, or WaitUntilExists with
+	// more retries for an S3-compatible store that's only eventually consistent.  It defaults to checking that the
+	// file exists up to 5 times, a second apart.  DisableWaitForWrite skips the wait, like NoWait.
+	WaitForWrite        WaitStrategy `json:"-"`
+	DisableWaitForWrite bool         `json:"disableWaitForWrite,omitempty"`
+	// DisableKeyNormalization keeps the paths given to NewFile and NewLocation, and to a Location's NewFile,
+	// NewLocation and ChangeDir, as they are rather than cleaning them with path.Clean, so keys with an embedded "//",
+	// a leading slash (the path "//file.txt") or a trailing slash (a zero-byte folder marker, ie: the file "/dir/")
+	// can be addressed.  ".." is then part of a key rather than navigating up.  Clients created from Options don't
+	// clean request paths when it's set (see aws.Config.DisableRestProtocolURICleaning); a client passed to
+	// WithClient must be configured the same way.
+	DisableKeyNormalization bool `json:"disableKeyNormalization,omitempty"`
+	// Anonymous sends requests unsigned, without looking for credentials, ie: to read public datasets.  Requests that
+	// would write, such as uploads, deletes and copies, fail with ErrAnonymousWrite instead of being sent.  It only
+	// applies to clients created from Options, and can't be combined with RoleARN.
+	Anonymous bool `json:"anonymous,omitempty"`
+	// CredentialProvider, when set, resolves the credentials each request is signed with from the bucket and key it's
+	// for, as it's made, ie: for per-tenant credentials that are rotated.  It only applies to clients created from
+	// Options, and can't be combined with Anonymous.
+	CredentialProvider CredentialProvider `json:"-"`
+	// ExcludeFolderMarkers leaves folder markers out of listings: zero-byte objects whose keys end in "/", as the S3
+	// console creates for folders, or in "_$folder$", as Hadoop does, which are otherwise listed as empty files.
+	// FolderMarkersAsLocations leaves them out of file listings too, as well as any other object whose key ends in "/",
+	// and lists the folders they mark as Locations from ListWithPrefixes, so folders with nothing in them are listed.
+	ExcludeFolderMarkers     bool `json:"excludeFolderMarkers,omitempty"`
+	FolderMarkersAsLocations bool `json:"folderMarkersAsLocations,omitempty"`
+	// Session, when set, is the aws session the client is created from, so its configuration (ie: an HTTP client with
+	// a proxy, credentials or region) is used instead of the defaults.  Region, Endpoint, UseAccelerate,
+	// ForcePathStyle, Retry and credentials set in these Options still apply on top of it; the session's credentials
+	// are used unless AccessKeyID, RoleARN or Anonymous is set.  See also FileSystem.WithSession.
+	Session *session.Session `json:"-"`
+	// MaxIdleConnsPerHost, MaxConnsPerHost and IdleConnTimeout size the client's connection pool, ie: raising
+	// MaxIdleConnsPerHost above net/http's default of 2 for highly concurrent copies, which otherwise open (and
+	// close) a connection for most requests.  ProxyURL sends requests through that proxy rather than any set by the
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables, and TLSConfig, when set, configures the client's TLS,
+	// ie: RootCAs for an S3-compatible server with a private CA.  Setting any of them gives the client its own
+	// transport, replacing a Session's http client.  They only apply to clients created from Options.
+	MaxIdleConnsPerHost int           `json:"maxIdleConnsPerHost,omitempty"`
+	MaxConnsPerHost     int           `json:"maxConnsPerHost,omitempty"`
+	IdleConnTimeout     time.Duration `json:"idleConnTimeout,omitempty"`
+	ProxyURL            string        `json:"proxyUrl,omitempty"`
+	TLSConfig           *tls.Config   `json:"-"`
+	// AdaptiveThrottling slows the client when s3 throttles its requests (ie: a 503 SlowDown), halving the rate they're
+	// sent at, no more than once a second, and raising it again by 10% for each second none are throttled, up to
+	// RequestsPerSecond if set.  Throttled requests are retried no sooner than a Retry-After header asks.  OnThrottle,
+	// when set, is called for each throttled request, with the rate requests are then limited to, ie: to log or
+	// alert on throttling storms.  They only apply to clients created from Options.
+	AdaptiveThrottling bool                `json:"adaptiveThrottling,omitempty"`
+	OnThrottle         func(ThrottleEvent) `json:"-"`
+	// CopyMode determines how files are copied to and from other s3 files, see CopyModeStream for copies between
+	// accounts that can't be made server-side.  It applies if set on either the source's or the target's Options.
+	CopyMode CopyMode `json:"copyMode,omitempty"`
 }
 
 // getClient setup S3 client
 func getClient(opt Options) (s3iface.S3API, error) {
 
-	//setup default config
+	//setup default config, or only what's set in opt when configuring on top of a session
 	awsConfig := defaults.Config()
+	if opt.Session != nil {
+		awsConfig = &aws.Config{}
+	}
 
-	//setup region using opt or env
+	//setup region using opt or env, leaving a session's own region in place
 	if opt.Region != "" {
 		awsConfig.WithRegion(opt.Region)
-	} else if val, ok := os.LookupEnv("AWS_DEFAULT_REGION"); ok {
+	} else if val, ok := os.LookupEnv("AWS_DEFAULT_REGION"); ok && opt.Session == nil {
 		awsConfig.WithRegion(val)
 	}
 
 	//use specific endpoint, otherwise, will use aws "default endpoint resolver" based on region
-	awsConfig.WithEndpoint(opt.Endpoint)
+	if opt.Endpoint != "" {
+		awsConfig.WithEndpoint(opt.Endpoint)
+	}
+
+	//use the s3 transfer acceleration endpoint (bucket must have acceleration enabled)
+	if opt.UseAccelerate {
+		awsConfig.WithS3UseAccelerate(true)
+	}
+
+	if opt.ForcePathStyle {
+		awsConfig.WithS3ForcePathStyle(true)
+	}
+
+	if opt.DisableKeyNormalization {
+		awsConfig.DisableRestProtocolURICleaning = aws.Bool(true)
+	}
 
 	if opt.Retry != nil {
 		awsConfig.Retryer = opt.Retry
 	}
 
-	//set up credential provider chain
-	credentialProviders, err := initCredentialProviderChain(opt)
+	//tune the http transport, if requested
+	transportClient, err := httpClient(opt)
 	if err != nil {
 		return nil, err
 	}
-	awsConfig.WithCredentials(
-		credentials.NewChainCredentials(credentialProviders),
-	)
+	if transportClient != nil {
+		awsConfig.WithHTTPClient(transportClient)
+	}
+
+	//set up credential provider chain, unless requests are to be made anonymously
+	if opt.Anonymous {
+		if opt.RoleARN != "" {
+			return nil, errors.New("a role can't be assumed by an anonymous file system")
+		}
+		if opt.CredentialProvider != nil {
+			return nil, errors.New("an anonymous file system can't have a CredentialProvider")
+		}
+		awsConfig.WithCredentials(credentials.AnonymousCredentials)
+	} else if opt.Session == nil || opt.AccessKeyID != "" {
+		credentialProviders, err := initCredentialProviderChain(opt)
+		if err != nil {
+			return nil, err
+		}
+		awsConfig.WithCredentials(
+			credentials.NewChainCredentials(credentialProviders),
+		)
+	}
+
+	//assume a role, if requested, using the credentials resolved above (or a web identity token)
+	if opt.RoleARN != "" {
+		baseSession, err := newSession(opt, awsConfig)
+		if err != nil {
+			return nil, err
+		}
+		awsConfig.WithCredentials(getRoleCredentials(baseSession, opt))
+	}
 
 	// create new session with config
-	s, err := session.NewSessionWithOptions(
+	s, err := newSession(opt, awsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	//return client instance, with any timeout and rate limit applied to its requests
+	client := s3.New(s)
+	addLimitHandlers(&client.Handlers, opt)
+	addThrottleHandlers(&client.Handlers, opt)
+	if opt.AdaptiveThrottling {
+		client.Retryer = throttleRetryer{Retryer: client.Retryer}
+	}
+	if opt.Anonymous {
+		addAnonymousHandlers(&client.Handlers)
+	}
+	if opt.CredentialProvider != nil {
+		addCredentialProviderHandlers(&client.Handlers, opt.CredentialProvider)
+	}
+	return client, nil
+}
+
+// newSession returns a session with awsConfig, copied from opt.Session if it's set.
+func newSession(opt Options, awsConfig *aws.Config) (*session.Session, error) {
+	if opt.Session != nil {
+		return opt.Session.Copy(awsConfig), nil
+	}
+	return session.NewSessionWithOptions(
 		session.Options{
 			Config: *awsConfig,
 		},
 	)
-	if err != nil {
-		return nil, err
-	}
+}
 
-	//return client instance
-	return s3.New(s), nil
+// uploaderOptions returns an s3manager.Uploader option func which applies any upload tuning set in opt.
+func uploaderOptions(opt Options) func(*s3manager.Uploader) {
+	return func(u *s3manager.Uploader) {
+		if opt.UploadPartSize > 0 {
+			u.PartSize = opt.UploadPartSize
+		}
+		if opt.UploadConcurrency > 0 {
+			u.Concurrency = opt.UploadConcurrency
+		}
+		u.LeavePartsOnError = opt.LeavePartsOnError
+	}
 }
 
 //initCredentialProviderChain returns an array of credential providers that will be used, in order, to attempt authentication
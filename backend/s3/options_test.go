@@ -4,7 +4,10 @@ import (
 	"os"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -42,6 +45,43 @@ func (o *optionsTestSuite) TestGetClient() {
 	o.NoError(err)
 	o.NotNil(client, "client is set")
 	o.Equal("set-by-envvar", *client.(*s3.S3).Config.Region, "region is set by env var")
+
+	// a session's region isn't overridden by the env var
+	sess, err := session.NewSession(&aws.Config{Region: aws.String("session-region")})
+	o.NoError(err)
+	client, err = getClient(Options{Session: sess})
+	o.NoError(err)
+	o.Equal("session-region", *client.(*s3.S3).Config.Region, "region is set by the session")
+
+	// transfer acceleration
+	opts = Options{UseAccelerate: true}
+	client, err = getClient(opts)
+	o.NoError(err)
+	o.NotNil(client, "client is set")
+	o.True(*client.(*s3.S3).Config.S3UseAccelerate, "accelerate is set")
+
+	// path style addressing
+	opts = Options{ForcePathStyle: true}
+	client, err = getClient(opts)
+	o.NoError(err)
+	o.True(*client.(*s3.S3).Config.S3ForcePathStyle, "path style is set")
+}
+
+func (o *optionsTestSuite) TestUploaderOptions() {
+	//defaults are left alone
+	uploader := s3manager.NewUploaderWithClient(&s3.S3{}, uploaderOptions(Options{}))
+	o.Equal(s3manager.DefaultUploadPartSize, uploader.PartSize)
+	o.Equal(s3manager.DefaultUploadConcurrency, uploader.Concurrency)
+	o.False(uploader.LeavePartsOnError)
+
+	uploader = s3manager.NewUploaderWithClient(&s3.S3{}, uploaderOptions(Options{
+		UploadPartSize:    10 * 1024 * 1024,
+		UploadConcurrency: 10,
+		LeavePartsOnError: true,
+	}))
+	o.Equal(int64(10*1024*1024), uploader.PartSize)
+	o.Equal(10, uploader.Concurrency)
+	o.True(uploader.LeavePartsOnError)
 }
 
 func TestOptions(t *testing.T) {
@@ -0,0 +1,192 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	// defaultPostPolicyExpiry is how long a post policy is valid for when PostPolicyOptions doesn't set Expires.
+	defaultPostPolicyExpiry = time.Hour
+	// postPolicyAlgorithm is the signing algorithm of post policies, AWS Signature Version 4.
+	postPolicyAlgorithm = "AWS4-HMAC-SHA256"
+	// filenameVariable is replaced by s3 with the name of the file uploaded by a post policy's form.
+	filenameVariable = "${filename}"
+)
+
+// PostPolicyOptions constrains the uploads allowed by a PostPolicy.
+type PostPolicyOptions struct {
+	// Expires is how long the policy can be used for, an hour if 0.
+	Expires time.Duration
+	// MinSize and MaxSize limit the size of the upload in bytes, if MaxSize is greater than 0.
+	MinSize int64
+	MaxSize int64
+	// ContentType requires the upload's Content-Type to be exactly it, ie: "image/png", and is included in the
+	// policy's Fields.  ContentTypePrefix instead requires it to start with the prefix, ie: "image/", leaving the
+	// form to set the Content-Type field.
+	ContentType       string
+	ContentTypePrefix string
+}
+
+// PostPolicy is a signed S3 POST policy, allowing a browser to upload directly to s3 with an HTML form.  The form is
+// POSTed to URL as multipart/form-data with each of Fields, followed by the file itself in a field named "file".
+type PostPolicy struct {
+	URL    string
+	Fields map[string]string
+	// Expires is when the policy stops being accepted.
+	Expires time.Time
+}
+
+// PostPolicy returns a PostPolicy allowing a browser to upload to the file, within the constraints of opts.  The
+// file system's client must be an *s3.S3, ie: one created from Options, since the policy is signed with its
+// credentials.
+func (f *File) PostPolicy(opts PostPolicyOptions) (*PostPolicy, error) {
+	key := keyOf(f.key)
+	return newPostPolicy(f.fileSystem, f.bucket, key, map[string]string{"key": key}, opts, time.Now())
+}
+
+// PostPolicy returns a PostPolicy allowing a browser to upload to any key beneath the location, within the
+// constraints of opts.  The key is the location's path followed by the name of the uploaded file, unless the form
+// replaces the "key" field with another beginning with the location's path.  As for File.PostPolicy, the file
+// system's client must be an *s3.S3.
+func (l *Location) PostPolicy(opts PostPolicyOptions) (*PostPolicy, error) {
+	prefix := keyOf(l.Path())
+	condition := []interface{}{"starts-with", "$key", prefix}
+	return newPostPolicy(l.fileSystem, l.bucket, prefix+filenameVariable, condition, opts, time.Now())
+}
+
+// newPostPolicy returns a PostPolicy for uploads to bucket with the key field key, constrained by keyCondition and
+// opts, signed at now.
+func newPostPolicy(fs *FileSystem, bucket, key string, keyCondition interface{}, opts PostPolicyOptions,
+	now time.Time) (*PostPolicy, error) {
+	client, err := fs.Client()
+	if err != nil {
+		return nil, err
+	}
+	svc, ok := client.(*s3.S3)
+	if !ok {
+		return nil, errors.New("post policies can only be signed by an *s3.S3 client")
+	}
+	if svc.Config.Credentials == credentials.AnonymousCredentials {
+		return nil, ErrAnonymousWrite
+	}
+	provided := svc.Config.Credentials
+	if o, ok := fs.options.(Options); ok && o.CredentialProvider != nil {
+		resolved, err := resolveCredentials(o.CredentialProvider, bucket, key)
+		if err != nil {
+			return nil, err
+		}
+		if resolved != nil {
+			provided = resolved
+		}
+	}
+	creds, err := provided.Get()
+	if err != nil {
+		return nil, err
+	}
+	region := svc.SigningRegion
+	if region == "" {
+		region = aws.StringValue(svc.Config.Region)
+	}
+	postURL, err := bucketURL(svc.Endpoint, bucket, aws.BoolValue(svc.Config.S3ForcePathStyle))
+	if err != nil {
+		return nil, err
+	}
+
+	now = now.UTC()
+	expiry := opts.Expires
+	if expiry <= 0 {
+		expiry = defaultPostPolicyExpiry
+	}
+	date := now.Format("20060102")
+	fields := map[string]string{
+		"key":              key,
+		"x-amz-algorithm":  postPolicyAlgorithm,
+		"x-amz-credential": creds.AccessKeyID + "/" + date + "/" + region + "/s3/aws4_request",
+		"x-amz-date":       now.Format("20060102T150405Z"),
+	}
+	if creds.SessionToken != "" {
+		fields["x-amz-security-token"] = creds.SessionToken
+	}
+	if o, ok := fs.options.(Options); ok && o.ACL != "" {
+		fields["acl"] = o.ACL
+	}
+	if opts.ContentType != "" {
+		fields["Content-Type"] = opts.ContentType
+	}
+
+	conditions := []interface{}{map[string]string{"bucket": bucket}, keyCondition}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		if name != "key" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		conditions = append(conditions, map[string]string{name: fields[name]})
+	}
+	if opts.ContentTypePrefix != "" {
+		conditions = append(conditions, []interface{}{"starts-with", "$Content-Type", opts.ContentTypePrefix})
+	}
+	if opts.MaxSize > 0 {
+		conditions = append(conditions, []interface{}{"content-length-range", opts.MinSize, opts.MaxSize})
+	}
+
+	expires := now.Add(expiry)
+	policy, err := json.Marshal(map[string]interface{}{
+		"expiration": expires.Format("2006-01-02T15:04:05.000Z"),
+		"conditions": conditions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(policy)
+	fields["policy"] = encoded
+	signature := hmacSHA256(signingKey(creds.SecretAccessKey, date, region, "s3"), encoded)
+	fields["x-amz-signature"] = hex.EncodeToString(signature)
+
+	return &PostPolicy{URL: postURL, Fields: fields, Expires: expires}, nil
+}
+
+// bucketURL returns the URL of bucket at the s3 endpoint, addressing the bucket in the host name unless forcePathStyle
+// is set or its name contains a dot, which wouldn't match the endpoint's TLS certificate.
+func bucketURL(endpoint, bucket string, forcePathStyle bool) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	if forcePathStyle || strings.Contains(bucket, ".") {
+		u.Path = "/" + bucket + "/"
+	} else {
+		u.Host = bucket + "." + u.Host
+		u.Path = "/"
+	}
+	return u.String(), nil
+}
+
+// signingKey returns the Signature Version 4 signing key for requests to service in region on date, ie: "20200102".
+func signingKey(secret, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,111 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// errCodeInvalidRange is returned by s3 for a range starting beyond the end of the object.
+const errCodeInvalidRange = "InvalidRange"
+
+// ReadRange returns up to length bytes of the file starting at offset, using a ranged GET so only those bytes are
+// downloaded, ie: to peek at the start of a log or sniff a file's format.  Fewer than length bytes are returned if the
+// range extends past the end of the file, and io.EOF if offset is at or beyond it.  The file's read cursor is unaffected.
+func (f *File) ReadRange(offset, length int64) ([]byte, error) {
+	if offset < 0 || length < 1 {
+		return nil, errors.New("non-negative offset and positive length are required")
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	input := f.getObjectInput().SetRange(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	output, err := client.GetObject(input)
+	if err != nil {
+		if errorCode(err) == errCodeInvalidRange {
+			return nil, io.EOF
+		}
+		return nil, f.wrapArchivedError(err)
+	}
+	defer func() { _ = output.Body.Close() }()
+
+	return ioutil.ReadAll(output.Body)
+}
+
+// OpenRandomAccess returns a reader of the file at any offset, with its size, for readers of formats which read a
+// footer or directory at the end of the file first, ie: zip.NewReader(r, r.Size()) and parquet readers.  The size comes
+// from the file's cached HEAD, and each ReadAt is a ranged GET of only the bytes asked for, so nothing else is
+// downloaded.  Reads are pinned to the ETag of the HEAD, failing if the object is replaced while it's being read.  The
+// reader is safe for concurrent use, and its Close does nothing.
+func (f *File) OpenRandomAccess() (utils.RandomAccessReader, error) {
+	head, err := f.getHeadObject()
+	if err != nil {
+		return nil, err
+	}
+	return &randomAccessReader{file: f, size: aws.Int64Value(head.ContentLength), etag: aws.StringValue(head.ETag)}, nil
+}
+
+// randomAccessReader is the reader returned by File.OpenRandomAccess.
+type randomAccessReader struct {
+	file *File
+	size int64
+	etag string
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *randomAccessReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("non-negative offset is required")
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	end := off + int64(len(p))
+	if end > r.size {
+		end = r.size
+	}
+
+	client, err := r.file.fileSystem.Client()
+	if err != nil {
+		return 0, err
+	}
+	input := r.file.getObjectInput().SetRange(fmt.Sprintf("bytes=%d-%d", off, end-1))
+	if r.etag != "" {
+		input.SetIfMatch(r.etag)
+	}
+	output, err := client.GetObject(input)
+	if err != nil {
+		if isConditionFailed(err) {
+			return 0, fmt.Errorf("%s was replaced while being read", r.file)
+		}
+		return 0, r.file.wrapArchivedError(err)
+	}
+	defer func() { _ = output.Body.Close() }()
+
+	n, err := io.ReadFull(output.Body, p[:end-off])
+	if err == nil && end-off < int64(len(p)) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Size implements utils.RandomAccessReader.
+func (r *randomAccessReader) Size() int64 {
+	return r.size
+}
+
+// Close implements io.Closer.  Each read is a separate request, so there's nothing to close.
+func (r *randomAccessReader) Close() error {
+	return nil
+}
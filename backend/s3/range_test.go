@@ -0,0 +1,125 @@
+package s3
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type rangeTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	file      *File
+}
+
+func (ts *rangeTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	fs := &FileSystem{client: ts.s3apiMock, options: Options{AccessKeyID: "abc"}}
+	file, err := fs.NewFile("bucket", "/logs/app.log")
+	ts.NoError(err)
+	ts.file = file.(*File)
+}
+
+func (ts *rangeTestSuite) TestReadRange() {
+	ts.s3apiMock.On("GetObject", mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+		return aws.StringValue(input.Range) == "bytes=10-14" && *input.Key == "logs/app.log"
+	})).Return(&s3.GetObjectOutput{Body: nopCloser{bytes.NewBufferString("hello")}}, nil).Once()
+
+	data, err := ts.file.ReadRange(10, 5)
+	ts.NoError(err)
+	ts.Equal("hello", string(data))
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *rangeTestSuite) TestReadRange_PastEnd() {
+	ts.s3apiMock.On("GetObject", mock.Anything).
+		Return(nil, awserr.New(errCodeInvalidRange, "The requested range is not satisfiable", nil)).Once()
+
+	_, err := ts.file.ReadRange(1000, 5)
+	ts.Equal(io.EOF, err)
+}
+
+func (ts *rangeTestSuite) TestReadRange_InvalidArgs() {
+	_, err := ts.file.ReadRange(0, 0)
+	ts.EqualError(err, "non-negative offset and positive length are required")
+	ts.s3apiMock.AssertNotCalled(ts.T(), "GetObject", mock.Anything)
+}
+
+func (ts *rangeTestSuite) TestOpenRandomAccess() {
+	// a zip whose directory, at the end, is all zip.NewReader needs to read
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "data.bin", Method: zip.Store})
+	ts.NoError(err)
+	data := make([]byte, 1<<20)
+	_, err = rand.Read(data)
+	ts.NoError(err)
+	_, err = w.Write(data)
+	ts.NoError(err)
+	ts.NoError(zw.Close())
+	contents := archive.Bytes()
+
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{
+		ContentLength: aws.Int64(int64(len(contents))),
+		ETag:          aws.String(`"etag"`),
+	}, nil).Once()
+	downloaded := 0
+	ts.s3apiMock.On("GetObject", mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+		return aws.StringValue(input.IfMatch) == `"etag"`
+	})).Return(func(input *s3.GetObjectInput) *s3.GetObjectOutput {
+		var start, end int
+		_, err := fmt.Sscanf(*input.Range, "bytes=%d-%d", &start, &end)
+		ts.NoError(err)
+		ts.True(end < len(contents), "ranges are within the object")
+		downloaded += end - start + 1
+		return &s3.GetObjectOutput{Body: nopCloser{bytes.NewReader(contents[start : end+1])}}
+	}, nil)
+
+	r, err := ts.file.OpenRandomAccess()
+	ts.NoError(err)
+	ts.Equal(int64(len(contents)), r.Size())
+	zr, err := zip.NewReader(r, r.Size())
+	ts.NoError(err)
+	ts.Equal("data.bin", zr.File[0].Name)
+	ts.True(downloaded < len(contents)/100, "only the directory is downloaded, not %d bytes", downloaded)
+
+	// reads past the end are short, without a request for the missing bytes
+	p := make([]byte, 10)
+	n, err := r.ReadAt(p, int64(len(contents)-4))
+	ts.Equal(4, n)
+	ts.Equal(io.EOF, err)
+	n, err = r.ReadAt(p, int64(len(contents)))
+	ts.Zero(n)
+	ts.Equal(io.EOF, err)
+	ts.NoError(r.Close())
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *rangeTestSuite) TestOpenRandomAccess_Replaced() {
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(100), ETag: aws.String(`"etag"`)}, nil).Once()
+	failed := awserr.New("PreconditionFailed", "At least one of the pre-conditions you specified did not hold", nil)
+	ts.s3apiMock.On("GetObject", mock.Anything).
+		Return(nil, awserr.NewRequestFailure(failed, http.StatusPreconditionFailed, "")).Once()
+
+	r, err := ts.file.OpenRandomAccess()
+	ts.NoError(err)
+	_, err = r.ReadAt(make([]byte, 10), 0)
+	ts.EqualError(err, "s3://bucket/logs/app.log was replaced while being read")
+}
+
+func TestReadRange(t *testing.T) {
+	suite.Run(t, new(rangeTestSuite))
+}
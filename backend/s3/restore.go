@@ -0,0 +1,127 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	// errCodeInvalidObjectState is returned by GetObject for archived objects that have not been restored.
+	errCodeInvalidObjectState = "InvalidObjectState"
+	// errCodeRestoreAlreadyInProgress is returned by RestoreObject when a restore has already been initiated.
+	errCodeRestoreAlreadyInProgress = "RestoreAlreadyInProgress"
+)
+
+var (
+	ongoingRequestRegex = regexp.MustCompile(`ongoing-request="(true|false)"`)
+	expiryDateRegex     = regexp.MustCompile(`expiry-date="([^"]+)"`)
+)
+
+// ArchivedObjectError is returned when reading a file whose object is in the GLACIER or DEEP_ARCHIVE storage class and
+// has not been restored.  Use File.Restore to initiate a restore and File.RestoreStatus to poll for its completion.
+type ArchivedObjectError struct {
+	URI string
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ArchivedObjectError) Error() string {
+	return fmt.Sprintf("s3 object %s is archived and must be restored before it can be read: %s", e.URI, e.Err)
+}
+
+// RestoreStatus describes the archive and restore state of an s3 object.
+type RestoreStatus struct {
+	// StorageClass is the object's storage class, ie: GLACIER.  Objects in the STANDARD class report "STANDARD".
+	StorageClass string
+	// InProgress is true if a restore has been initiated and has not yet completed.
+	InProgress bool
+	// Restored is true if a temporary restored copy of the archived object is available to read.
+	Restored bool
+	// Expiry is when the restored copy will be removed.  Only set when Restored is true.
+	Expiry time.Time
+}
+
+// IsArchiveStorageClass returns true if class is an archival storage class, GLACIER or DEEP_ARCHIVE, whose objects
+// must be restored (see File.Restore) before they can be read or copied.
+func IsArchiveStorageClass(class string) bool {
+	return class == s3.StorageClassGlacier || class == s3.StorageClassDeepArchive
+}
+
+// Archived returns true if the object is in an archival storage class, see IsArchiveStorageClass.
+func (r RestoreStatus) Archived() bool {
+	return IsArchiveStorageClass(r.StorageClass)
+}
+
+// Restore initiates a temporary restore of an archived object for the given number of days.  Tier is one of
+// s3.TierStandard, s3.TierBulk or s3.TierExpedited.  Restoring an object whose restore is already in progress is
+// not an error.
+func (f *File) Restore(tier string, days int64) error {
+	if tier == "" || days < 1 {
+		return errors.New("non-empty tier and positive number of days are required")
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	input := new(s3.RestoreObjectInput).
+		SetBucket(f.bucket).
+		SetKey(keyOf(f.key)).
+		SetRestoreRequest(new(s3.RestoreRequest).
+			SetDays(days).
+			SetGlacierJobParameters(new(s3.GlacierJobParameters).SetTier(tier)))
+	if f.versionID != "" {
+		input.SetVersionId(f.versionID)
+	}
+
+	_, err = client.RestoreObject(input)
+	f.invalidateHead()
+	if errorCode(err) == errCodeRestoreAlreadyInProgress {
+		return nil
+	}
+	return err
+}
+
+// RestoreStatus returns the archive and restore state of the file, based on a HEAD request to the s3 object.
+func (f *File) RestoreStatus() (RestoreStatus, error) {
+	// always re-fetch since restore status is typically polled
+	f.invalidateHead()
+	head, err := f.getHeadObject()
+	if err != nil {
+		return RestoreStatus{}, err
+	}
+
+	status := RestoreStatus{StorageClass: s3.StorageClassStandard}
+	if head.StorageClass != nil {
+		status.StorageClass = *head.StorageClass
+	}
+
+	// the x-amz-restore header looks like:
+	//   ongoing-request="false", expiry-date="Fri, 23 Dec 2012 00:00:00 GMT"
+	restore := aws.StringValue(head.Restore)
+	if m := ongoingRequestRegex.FindStringSubmatch(restore); m != nil {
+		status.InProgress = m[1] == "true"
+		status.Restored = m[1] == "false"
+	}
+	if m := expiryDateRegex.FindStringSubmatch(restore); m != nil {
+		if expiry, err := time.Parse(time.RFC1123, m[1]); err == nil {
+			status.Expiry = expiry
+		}
+	}
+
+	return status, nil
+}
+
+// wrapArchivedError returns an *ArchivedObjectError if err indicates that the object is archived, otherwise err.
+func (f *File) wrapArchivedError(err error) error {
+	if errorCode(err) == errCodeInvalidObjectState {
+		return &ArchivedObjectError{URI: f.URI(), Err: err}
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package s3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type restoreTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	file      *File
+}
+
+func (ts *restoreTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	fs := &FileSystem{client: ts.s3apiMock, options: Options{AccessKeyID: "abc"}}
+	file, err := fs.NewFile("bucket", "/some/path/file.txt")
+	ts.NoError(err)
+	ts.file = file.(*File)
+}
+
+func (ts *restoreTestSuite) TestRead_Archived() {
+	ts.s3apiMock.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).
+		Return(nil, awserr.New(errCodeInvalidObjectState, "The operation is not valid for the object's storage class", nil))
+
+	_, err := ts.file.Read(make([]byte, 10))
+	ts.Error(err)
+	archivedErr, ok := err.(*ArchivedObjectError)
+	ts.True(ok, "error is an *ArchivedObjectError")
+	ts.Equal("s3://bucket/some/path/file.txt", archivedErr.URI)
+}
+
+func (ts *restoreTestSuite) TestRestore() {
+	ts.EqualError(ts.file.Restore("", 1), "non-empty tier and positive number of days are required")
+	ts.EqualError(ts.file.Restore(s3.TierBulk, 0), "non-empty tier and positive number of days are required")
+
+	ts.s3apiMock.On("RestoreObject", mock.MatchedBy(func(input *s3.RestoreObjectInput) bool {
+		return *input.RestoreRequest.Days == 3 && *input.RestoreRequest.GlacierJobParameters.Tier == s3.TierBulk
+	})).Return(&s3.RestoreObjectOutput{}, nil).Once()
+	ts.NoError(ts.file.Restore(s3.TierBulk, 3))
+
+	ts.s3apiMock.On("RestoreObject", mock.AnythingOfType("*s3.RestoreObjectInput")).
+		Return(nil, awserr.New(errCodeRestoreAlreadyInProgress, "already in progress", nil)).Once()
+	ts.NoError(ts.file.Restore(s3.TierBulk, 3), "restore already in progress is not an error")
+
+	ts.s3apiMock.On("RestoreObject", mock.AnythingOfType("*s3.RestoreObjectInput")).
+		Return(nil, awserr.New("AccessDenied", "denied", nil)).Once()
+	ts.Error(ts.file.Restore(s3.TierBulk, 3))
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *restoreTestSuite) TestRestoreStatus() {
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{
+		StorageClass: aws.String(s3.StorageClassGlacier),
+		Restore:      aws.String(`ongoing-request="true"`),
+	}, nil).Once()
+	status, err := ts.file.RestoreStatus()
+	ts.NoError(err)
+	ts.True(status.Archived())
+	ts.True(status.InProgress)
+	ts.False(status.Restored)
+
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{
+		StorageClass: aws.String(s3.StorageClassDeepArchive),
+		Restore:      aws.String(`ongoing-request="false", expiry-date="Fri, 23 Dec 2012 00:00:00 GMT"`),
+	}, nil).Once()
+	status, err = ts.file.RestoreStatus()
+	ts.NoError(err)
+	ts.True(status.Archived())
+	ts.False(status.InProgress)
+	ts.True(status.Restored)
+	ts.Equal(time.Date(2012, 12, 23, 0, 0, 0, 0, time.UTC), status.Expiry.UTC())
+
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil).Once()
+	status, err = ts.file.RestoreStatus()
+	ts.NoError(err)
+	ts.False(status.Archived())
+	ts.Equal(s3.StorageClassStandard, status.StorageClass)
+}
+
+func (ts *restoreTestSuite) TestIsArchiveStorageClass() {
+	ts.True(IsArchiveStorageClass(s3.StorageClassGlacier))
+	ts.True(IsArchiveStorageClass(s3.StorageClassDeepArchive))
+	ts.False(IsArchiveStorageClass(s3.StorageClassStandardIa))
+	ts.False(IsArchiveStorageClass(""))
+}
+
+func TestRestore(t *testing.T) {
+	suite.Run(t, new(restoreTestSuite))
+}
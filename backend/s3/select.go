@@ -0,0 +1,71 @@
+package s3
+
+import (
+	"errors"
+	"io"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// Select runs an S3 Select SQL expression against the file's object server-side and returns a reader of the matching
+// records, formatted per outputSerialization.  Only the results are transferred, so a few rows can be pulled from a
+// very large CSV, JSON or Parquet object without downloading it.  The returned io.ReadCloser must be closed.
+//
+//   rows, err := s3File.Select(
+//       "SELECT s.name FROM S3Object s WHERE s.state = 'KS'",
+//       &s3.InputSerialization{CSV: &s3.CSVInput{FileHeaderInfo: aws.String(s3.FileHeaderInfoUse)}},
+//       &s3.OutputSerialization{CSV: &s3.CSVOutput{}},
+//   )
+func (f *File) Select(expression string, inputSerialization *s3.InputSerialization,
+	outputSerialization *s3.OutputSerialization) (io.ReadCloser, error) {
+	if expression == "" {
+		return nil, errors.New("non-empty string expression is required")
+	}
+	if inputSerialization == nil || outputSerialization == nil {
+		return nil, errors.New("non-nil input and output serialization are required")
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.SelectObjectContent(new(s3.SelectObjectContentInput).
+		SetBucket(f.bucket).
+		SetKey(keyOf(f.key)).
+		SetExpression(expression).
+		SetExpressionType(s3.ExpressionTypeSql).
+		SetInputSerialization(inputSerialization).
+		SetOutputSerialization(outputSerialization))
+	if err != nil {
+		return nil, f.wrapArchivedError(err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		for event := range output.EventStream.Events() {
+			if records, ok := event.(*s3.RecordsEvent); ok {
+				if _, err := pw.Write(records.Payload); err != nil {
+					// reader was closed
+					return
+				}
+			}
+		}
+		// a nil error results in io.EOF for the reader
+		_ = pw.CloseWithError(output.EventStream.Err())
+	}()
+
+	return &selectReader{PipeReader: pr, stream: output.EventStream}, nil
+}
+
+// selectReader streams the records of an S3 Select event stream.
+type selectReader struct {
+	*io.PipeReader
+	stream *s3.SelectObjectContentEventStream
+}
+
+// Close closes both the reader and the underlying event stream.
+func (r *selectReader) Close() error {
+	_ = r.PipeReader.Close()
+	return r.stream.Close()
+}
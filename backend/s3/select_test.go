@@ -0,0 +1,102 @@
+package s3
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type selectTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	file      *File
+}
+
+// eventStreamReader is a canned s3.SelectObjectContentEventStreamReader
+type eventStreamReader struct {
+	events chan s3.SelectObjectContentEventStreamEvent
+	err    error
+}
+
+func newEventStreamReader(err error, events ...s3.SelectObjectContentEventStreamEvent) *eventStreamReader {
+	r := &eventStreamReader{events: make(chan s3.SelectObjectContentEventStreamEvent, len(events)), err: err}
+	for _, e := range events {
+		r.events <- e
+	}
+	close(r.events)
+	return r
+}
+
+func (r *eventStreamReader) Events() <-chan s3.SelectObjectContentEventStreamEvent { return r.events }
+func (r *eventStreamReader) Close() error                                          { return nil }
+func (r *eventStreamReader) Err() error                                            { return r.err }
+
+func (ts *selectTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	fs := &FileSystem{client: ts.s3apiMock, options: Options{AccessKeyID: "abc"}}
+	file, err := fs.NewFile("bucket", "/some/path/file.csv")
+	ts.NoError(err)
+	ts.file = file.(*File)
+}
+
+func (ts *selectTestSuite) TestSelect() {
+	query := "SELECT s.name FROM S3Object s"
+	ts.s3apiMock.On("SelectObjectContent", mock.MatchedBy(func(input *s3.SelectObjectContentInput) bool {
+		return *input.Expression == query && *input.ExpressionType == s3.ExpressionTypeSql
+	})).Return(&s3.SelectObjectContentOutput{
+		EventStream: &s3.SelectObjectContentEventStream{
+			StreamCloser: ioutil.NopCloser(nil),
+			Reader: newEventStreamReader(nil,
+				&s3.RecordsEvent{Payload: []byte("alice\n")},
+				&s3.StatsEvent{},
+				&s3.RecordsEvent{Payload: []byte("bob\n")},
+				&s3.EndEvent{},
+			),
+		},
+	}, nil)
+
+	rows, err := ts.file.Select(query, &s3.InputSerialization{CSV: &s3.CSVInput{}}, &s3.OutputSerialization{CSV: &s3.CSVOutput{}})
+	ts.NoError(err)
+	contents, err := ioutil.ReadAll(rows)
+	ts.NoError(err)
+	ts.Equal("alice\nbob\n", string(contents))
+	ts.NoError(rows.Close())
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *selectTestSuite) TestSelect_StreamError() {
+	ts.s3apiMock.On("SelectObjectContent", mock.AnythingOfType("*s3.SelectObjectContentInput")).Return(&s3.SelectObjectContentOutput{
+		EventStream: &s3.SelectObjectContentEventStream{
+			StreamCloser: ioutil.NopCloser(nil),
+			Reader:       newEventStreamReader(errors.New("stream error"), &s3.RecordsEvent{Payload: []byte("alice\n")}),
+		},
+	}, nil)
+
+	rows, err := ts.file.Select("SELECT * FROM S3Object", &s3.InputSerialization{}, &s3.OutputSerialization{})
+	ts.NoError(err)
+	_, err = ioutil.ReadAll(rows)
+	ts.EqualError(err, "stream error")
+}
+
+func (ts *selectTestSuite) TestSelect_Errors() {
+	_, err := ts.file.Select("", &s3.InputSerialization{}, &s3.OutputSerialization{})
+	ts.EqualError(err, "non-empty string expression is required")
+
+	_, err = ts.file.Select("SELECT * FROM S3Object", nil, &s3.OutputSerialization{})
+	ts.EqualError(err, "non-nil input and output serialization are required")
+
+	ts.s3apiMock.On("SelectObjectContent", mock.AnythingOfType("*s3.SelectObjectContentInput")).
+		Return(nil, errors.New("select error"))
+	_, err = ts.file.Select("SELECT * FROM S3Object", &s3.InputSerialization{}, &s3.OutputSerialization{})
+	ts.EqualError(err, "select error")
+}
+
+func TestSelect(t *testing.T) {
+	suite.Run(t, new(selectTestSuite))
+}
@@ -0,0 +1,58 @@
+package s3
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// StorageClass returns the object's storage class, ie: s3.StorageClassDeepArchive, from its HEAD (see Refresh).
+// Objects in the STANDARD class report s3.StorageClassStandard.
+func (f *File) StorageClass() (string, error) {
+	head, err := f.getHeadObject()
+	if err != nil {
+		return "", err
+	}
+	if class := aws.StringValue(head.StorageClass); class != "" {
+		return class, nil
+	}
+	return s3.StorageClassStandard, nil
+}
+
+// SetStorageClass moves the object to class, ie: s3.StorageClassStandardIa or s3.StorageClassGlacier, by copying it
+// onto itself server-side, keeping its metadata.  In a versioned bucket the copy is a new version.  An object in an
+// archival class must be restored before it can be moved out of it, otherwise an *ArchivedObjectError is returned.
+// As with any CopyObject request, objects larger than 5GB can't be moved.
+func (f *File) SetStorageClass(class string) error {
+	if class == "" {
+		return errors.New("non-empty string class is required")
+	}
+	if f.versionID != "" {
+		return errors.New("the storage class of a specific version can't be set")
+	}
+
+	//PathEscape ensures we url-encode as required by the API, including double-encoding literals
+	copyInput := new(s3.CopyObjectInput).
+		SetServerSideEncryption("AES256").
+		SetKey(keyOf(f.key)).
+		SetBucket(f.bucket).
+		SetCopySource(url.PathEscape(f.bucket + "/" + keyOf(f.key))).
+		SetMetadataDirective(s3.MetadataDirectiveCopy).
+		SetStorageClass(class)
+	if opts, ok := f.fileSystem.options.(Options); ok {
+		if opts.ACL != "" {
+			copyInput.SetACL(opts.ACL)
+		}
+		applyObjectLockToCopy(opts, copyInput)
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+	_, err = client.CopyObject(copyInput)
+	f.invalidateHead()
+	return f.wrapArchivedError(err)
+}
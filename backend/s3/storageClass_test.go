@@ -0,0 +1,68 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type storageClassTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	file      *File
+}
+
+func (ts *storageClassTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	fs := &FileSystem{client: ts.s3apiMock, options: Options{ACL: "private"}}
+	file, err := fs.NewFile("bucket", "/some/path/file name.txt")
+	ts.NoError(err)
+	ts.file = file.(*File)
+}
+
+func (ts *storageClassTestSuite) TestStorageClass() {
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil).Once()
+	class, err := ts.file.StorageClass()
+	ts.NoError(err)
+	ts.Equal(s3.StorageClassStandard, class, "STANDARD objects have no storage class header")
+
+	ts.file.invalidateHead()
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{StorageClass: aws.String(s3.StorageClassDeepArchive)}, nil).Once()
+	class, err = ts.file.StorageClass()
+	ts.NoError(err)
+	ts.Equal(s3.StorageClassDeepArchive, class)
+}
+
+func (ts *storageClassTestSuite) TestSetStorageClass() {
+	ts.s3apiMock.On("CopyObject", mock.MatchedBy(func(input *s3.CopyObjectInput) bool {
+		return aws.StringValue(input.CopySource) == "bucket%2Fsome%2Fpath%2Ffile%20name.txt" &&
+			aws.StringValue(input.Key) == "some/path/file name.txt" &&
+			aws.StringValue(input.StorageClass) == s3.StorageClassGlacier &&
+			aws.StringValue(input.MetadataDirective) == s3.MetadataDirectiveCopy &&
+			aws.StringValue(input.ACL) == "private"
+	})).Return(&s3.CopyObjectOutput{}, nil).Once()
+	ts.NoError(ts.file.SetStorageClass(s3.StorageClassGlacier))
+
+	ts.s3apiMock.On("CopyObject", mock.AnythingOfType("*s3.CopyObjectInput")).
+		Return(nil, awserr.New(errCodeInvalidObjectState, "object is archived", nil)).Once()
+	err := ts.file.SetStorageClass(s3.StorageClassStandard)
+	_, ok := err.(*ArchivedObjectError)
+	ts.True(ok, "archived objects must be restored first")
+
+	ts.EqualError(ts.file.SetStorageClass(""), "non-empty string class is required")
+	version, err := ts.file.WithVersion("v1")
+	ts.NoError(err)
+	ts.Error(version.SetStorageClass(s3.StorageClassGlacier))
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func TestStorageClass(t *testing.T) {
+	suite.Run(t, new(storageClassTestSuite))
+}
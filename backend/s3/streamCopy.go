@@ -0,0 +1,83 @@
+package s3
+
+import (
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// CopyMode determines how an s3 file is copied to another s3 file.
+type CopyMode int
+
+const (
+	// CopyModeAuto copies server-side with CopyObject, using the source's client, when both file systems have the same
+	// access key, and otherwise downloads the source and uploads it with the target's client.  It's the default.
+	CopyModeAuto CopyMode = iota
+	// CopyModeStream streams copies between file systems through the client: the source is read with its file
+	// system's credentials and written with the target's, so neither needs access to the other's bucket, ie: across
+	// accounts whose bucket policies don't allow a server-side CopyObject.  Nothing is written to disk; the upload holds
+	// UploadConcurrency parts of UploadPartSize in memory.  Copies within a single file system, whose credentials can
+	// read and write both objects, are still made server-side, as are server-side edits such as File.Truncate.
+	CopyModeStream
+)
+
+// streamCopies returns true if a copy from the file to target must be streamed through the client, because they're on
+// different file systems and either's Options set CopyModeStream.
+func (f *File) streamCopies(target *File) bool {
+	if f.fileSystem == target.fileSystem {
+		return false
+	}
+	for _, fs := range []*FileSystem{f.fileSystem, target.fileSystem} {
+		if opts, ok := fs.options.(Options); ok && opts.CopyMode == CopyModeStream {
+			return true
+		}
+	}
+	return false
+}
+
+// streamCopy copies the object to target by piping a GET made with the file's client into an upload made with the
+// target's.  The object's content type is kept unless the target's Options set one.
+func (f *File) streamCopy(target *File, options vfs.CopyOptions) error {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+	output, err := client.GetObjectWithContext(options.Context, f.getObjectInput())
+	if err != nil {
+		return f.wrapArchivedError(err)
+	}
+	defer func() { _ = output.Body.Close() }()
+
+	targetClient, err := target.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+	opts, _ := target.fileSystem.options.(Options)
+	input := uploadInput(target)
+	contentType := aws.StringValue(output.ContentType)
+	if opts.ContentType != "" || contentType == "" {
+		contentType = target.contentType(opts, nil)
+	}
+	if contentType != "" {
+		input.ContentType = &contentType
+	}
+
+	// copy through a pipe so the copy options' progress, bandwidth limit and context apply
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := utils.CopyWithOptions(pw, output.Body, options)
+		_ = pw.CloseWithError(err)
+	}()
+	input.Body = pr
+
+	target.invalidateHead()
+	uploader := s3manager.NewUploaderWithClient(targetClient, uploaderOptions(opts))
+	_, err = uploader.UploadWithContext(options.Context, input)
+	// unblock the copy if the upload stopped reading
+	_ = pr.CloseWithError(io.ErrClosedPipe)
+	return err
+}
@@ -0,0 +1,65 @@
+package s3
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// tempFilePrefix begins the name of every temp file an s3 File downloads to, so orphaned ones can be found by
+// CleanupTempFiles.
+const tempFilePrefix = "vfs-s3-"
+
+// tempDir returns the directory temp files are created in, Options.TempDir or, if that's empty, os.TempDir().
+func (fs *FileSystem) tempDir() string {
+	if opts, ok := fs.options.(Options); ok && opts.TempDir != "" {
+		return opts.TempDir
+	}
+	return os.TempDir()
+}
+
+// reserveTemp accounts for a temp file of size bytes, returning an error instead if it would take the file system's
+// temp file usage beyond max.
+func (fs *FileSystem) reserveTemp(size, max int64) error {
+	if usage := atomic.AddInt64(&fs.tempUsage, size); usage > max {
+		atomic.AddInt64(&fs.tempUsage, -size)
+		return fmt.Errorf("temp file of %d bytes would exceed MaxTempUsage of %d bytes (%d in use)", size, max, usage-size)
+	}
+	return nil
+}
+
+// releaseTemp releases a temp file's usage previously accounted for by reserveTemp.
+func (fs *FileSystem) releaseTemp(size int64) {
+	atomic.AddInt64(&fs.tempUsage, -size)
+}
+
+// CleanupTempFiles removes temp files left in the temp directory (see Options.TempDir) by s3 files that weren't
+// closed, ie: because the process crashed.  Only files last modified more than olderThan ago are removed so that temp
+// files in use by other processes sharing the directory are left alone.  Call it once at startup.
+func (fs *FileSystem) CleanupTempFiles(olderThan time.Duration) error {
+	dir := fs.tempDir()
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	for _, info := range infos {
+		if info.IsDir() || info.ModTime().After(cutoff) {
+			continue
+		}
+		if !strings.HasPrefix(info.Name(), tempFilePrefix) && !strings.HasPrefix(info.Name(), utils.SpillFilePrefix) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, info.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
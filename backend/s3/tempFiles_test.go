@@ -0,0 +1,114 @@
+package s3
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+type tempFilesTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	fs        *FileSystem
+	dir       string
+}
+
+func (ts *tempFilesTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "s3_temp_test")
+	ts.NoError(err)
+	ts.dir = dir
+	ts.s3apiMock = &mocks.S3API{}
+	ts.fs = &FileSystem{client: ts.s3apiMock, options: Options{AccessKeyID: "abc", TempDir: dir}}
+}
+
+func (ts *tempFilesTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *tempFilesTestSuite) tempFiles() []string {
+	infos, err := ioutil.ReadDir(ts.dir)
+	ts.NoError(err)
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	return names
+}
+
+func (ts *tempFilesTestSuite) TestTempDir() {
+	ts.s3apiMock.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).
+		Return(&s3.GetObjectOutput{Body: nopCloser{bytes.NewBufferString("hello world")}}, nil)
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+	file, err := ts.fs.NewFile("bucket", "/file.txt")
+	ts.NoError(err)
+
+	data := make([]byte, 5)
+	_, err = file.Read(data)
+	ts.NoError(err)
+	ts.Equal("hello", string(data))
+	names := ts.tempFiles()
+	ts.Len(names, 1)
+	ts.Contains(names[0], tempFilePrefix+"file.txt")
+
+	ts.NoError(file.Close())
+	ts.Empty(ts.tempFiles(), "temp file should be removed on Close")
+}
+
+func (ts *tempFilesTestSuite) TestMaxTempUsage() {
+	ts.fs.options = Options{AccessKeyID: "abc", TempDir: ts.dir, MaxTempUsage: 15}
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(10)}, nil)
+	ts.s3apiMock.On("GetObject", mock.AnythingOfType("*s3.GetObjectInput")).Return(
+		func(*s3.GetObjectInput) *s3.GetObjectOutput {
+			return &s3.GetObjectOutput{Body: nopCloser{bytes.NewBufferString("0123456789")}}
+		}, nil)
+
+	first, err := ts.fs.NewFile("bucket", "/first.txt")
+	ts.NoError(err)
+	_, err = first.Read(make([]byte, 1))
+	ts.NoError(err)
+
+	second, err := ts.fs.NewFile("bucket", "/second.txt")
+	ts.NoError(err)
+	_, err = second.Read(make([]byte, 1))
+	ts.Error(err, "second download should exceed MaxTempUsage")
+	ts.Contains(err.Error(), "MaxTempUsage")
+
+	// closing the first file frees its usage for the second
+	ts.NoError(first.Close())
+	_, err = second.Read(make([]byte, 1))
+	ts.NoError(err)
+	ts.NoError(second.Close())
+	ts.Equal(int64(0), ts.fs.tempUsage)
+}
+
+func (ts *tempFilesTestSuite) TestCleanupTempFiles() {
+	old := time.Now().Add(-2 * time.Hour)
+	create := func(name string, modTime time.Time) {
+		path := filepath.Join(ts.dir, name)
+		ts.NoError(ioutil.WriteFile(path, []byte("data"), 0600))
+		ts.NoError(os.Chtimes(path, modTime, modTime))
+	}
+	create(tempFilePrefix+"orphaned.txt.123", old)
+	create(utils.SpillFilePrefix+"456", old)
+	create(tempFilePrefix+"inuse.txt.789", time.Now())
+	create("unrelated.txt", old)
+
+	ts.NoError(ts.fs.CleanupTempFiles(time.Hour))
+	ts.ElementsMatch([]string{tempFilePrefix + "inuse.txt.789", "unrelated.txt"}, ts.tempFiles())
+}
+
+func TestTempFiles(t *testing.T) {
+	suite.Run(t, new(tempFilesTestSuite))
+}
@@ -0,0 +1,230 @@
+package s3
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+const (
+	// minAdaptiveRate is the lowest request rate, per second, adaptive throttling slows a client to.
+	minAdaptiveRate = 1
+	// adaptiveInterval is how often adaptive throttling changes a client's request rate: it's cut at most once per
+	// interval however many requests of a burst are throttled, and raised by adaptiveIncrease for each interval without
+	// a throttled request.
+	adaptiveInterval = time.Second
+	adaptiveIncrease = 1.1
+)
+
+// ThrottleEvent describes a request that s3 throttled, ie: with a 503 SlowDown, see Options.OnThrottle.
+type ThrottleEvent struct {
+	// Operation is the name of the s3 API call, ie: "DeleteObject".
+	Operation string
+	// Bucket and Key are those of the request, where it has them.
+	Bucket string
+	Key    string
+	// Code and StatusCode are those of s3's error response.
+	Code       string
+	StatusCode int
+	// Attempt is the number of the attempt that was throttled, starting at 1.
+	Attempt int
+	// RetryAfter is the delay s3 asked for in a Retry-After header, or 0 if it didn't send one.
+	RetryAfter time.Duration
+	// RequestsPerSecond is the rate the client's requests are now limited to, or 0 if they aren't limited.
+	RequestsPerSecond float64
+}
+
+// adaptiveLimiter limits a client's request rate, cutting it in half when s3 throttles a request and raising it again
+// while s3 doesn't.  Requests aren't limited until one is first throttled, unless maxRate is set, in which case it's
+// the rate they start at and never exceed.
+type adaptiveLimiter struct {
+	mu      sync.Mutex
+	bucket  *tokenBucket
+	maxRate float64
+	burst   int
+	changed time.Time
+	// sent counts the requests sent since windowStart, to measure the rate they're sent at before any limit.
+	sent        int
+	windowStart time.Time
+	measured    float64
+}
+
+func newAdaptiveLimiter(maxRate float64, burst int) *adaptiveLimiter {
+	l := &adaptiveLimiter{maxRate: maxRate, burst: burst, windowStart: time.Now()}
+	if maxRate > 0 {
+		l.bucket = newTokenBucket(maxRate, burst)
+	}
+	return l
+}
+
+// wait blocks until a request may be sent, or ctx is done.
+func (l *adaptiveLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if elapsed := now.Sub(l.windowStart); elapsed >= adaptiveInterval {
+		l.measured = float64(l.sent) / elapsed.Seconds()
+		l.sent = 0
+		l.windowStart = now
+	}
+	l.sent++
+	bucket := l.bucket
+	l.mu.Unlock()
+
+	if bucket == nil {
+		return nil
+	}
+	return bucket.wait(ctx)
+}
+
+// throttled halves the request rate, or, the first time requests are throttled, limits them to half the rate they
+// were being sent at.  It returns the new rate.
+func (l *adaptiveLimiter) throttled() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if l.bucket != nil && now.Sub(l.changed) < adaptiveInterval {
+		// already cut for this burst of throttled requests
+		return l.bucket.currentRate()
+	}
+
+	rate := l.measured
+	if l.bucket != nil {
+		rate = l.bucket.currentRate()
+	} else if rate == 0 {
+		// no full interval has been measured yet, so assume the requests so far were sent over one
+		rate = float64(l.sent) / adaptiveInterval.Seconds()
+		if elapsed := now.Sub(l.windowStart); elapsed > adaptiveInterval {
+			rate = float64(l.sent) / elapsed.Seconds()
+		}
+	}
+	rate /= 2
+	if rate < minAdaptiveRate {
+		rate = minAdaptiveRate
+	}
+	l.setRate(rate)
+	l.changed = now
+	return rate
+}
+
+// succeeded raises the request rate, at most once per adaptiveInterval, up to maxRate if set.
+func (l *adaptiveLimiter) succeeded() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.bucket == nil || time.Since(l.changed) < adaptiveInterval {
+		return
+	}
+	rate := l.bucket.currentRate() * adaptiveIncrease
+	if l.maxRate > 0 && rate > l.maxRate {
+		rate = l.maxRate
+	}
+	l.setRate(rate)
+	l.changed = time.Now()
+}
+
+func (l *adaptiveLimiter) setRate(rate float64) {
+	if l.bucket == nil {
+		l.bucket = newTokenBucket(rate, l.burst)
+		return
+	}
+	l.bucket.setRate(rate)
+}
+
+// throttleRetryer waits at least as long as a throttled response's Retry-After header asks before retrying, whether
+// it's given in seconds or as an HTTP date, which the SDK's retryers don't all honor.
+type throttleRetryer struct {
+	request.Retryer
+}
+
+// RetryRules implements request.Retryer.
+func (r throttleRetryer) RetryRules(req *request.Request) time.Duration {
+	delay := r.Retryer.RetryRules(req)
+	if retryAfter := retryAfter(req); retryAfter > delay {
+		return retryAfter
+	}
+	return delay
+}
+
+// retryAfter returns the delay asked for by the Retry-After header of a throttled response, or 0.
+func retryAfter(r *request.Request) time.Duration {
+	if r.HTTPResponse == nil || classifyError(r.Error) != vfs.ErrorThrottled {
+		return 0
+	}
+	value := r.HTTPResponse.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+// addThrottleHandlers adds request handlers for the AdaptiveThrottling and OnThrottle options, if set, replacing the
+// RequestsPerSecond limit added by addLimitHandlers with an adaptive one.
+func addThrottleHandlers(handlers *request.Handlers, opt Options) {
+	if !opt.AdaptiveThrottling && opt.OnThrottle == nil {
+		return
+	}
+
+	var limiter *adaptiveLimiter
+	if opt.AdaptiveThrottling {
+		limiter = newAdaptiveLimiter(opt.RequestsPerSecond, opt.RequestBurst)
+		handlers.Sign.Remove(request.NamedHandler{Name: "vfs.RateLimit"})
+		// Sign runs for every attempt, so retries are rate limited too
+		handlers.Sign.PushFrontNamed(request.NamedHandler{
+			Name: "vfs.AdaptiveRateLimit",
+			Fn: func(r *request.Request) {
+				if err := limiter.wait(r.Context()); err != nil {
+					r.Error = rateLimitCanceled(err)
+				}
+			},
+		})
+		handlers.CompleteAttempt.PushBackNamed(request.NamedHandler{
+			Name: "vfs.AdaptiveRateLimitRecovery",
+			Fn: func(r *request.Request) {
+				if r.Error == nil {
+					limiter.succeeded()
+				}
+			},
+		})
+	}
+
+	// Retry runs for each failed attempt, once its error response has been unmarshaled
+	handlers.Retry.PushFrontNamed(request.NamedHandler{
+		Name: "vfs.Throttle",
+		Fn: func(r *request.Request) {
+			if classifyError(r.Error) != vfs.ErrorThrottled {
+				return
+			}
+			event := ThrottleEvent{
+				Operation:  r.Operation.Name,
+				Code:       errorCode(r.Error),
+				Attempt:    r.RetryCount + 1,
+				RetryAfter: retryAfter(r),
+			}
+			event.Bucket, event.Key = requestTarget(r)
+			if r.HTTPResponse != nil {
+				event.StatusCode = r.HTTPResponse.StatusCode
+			}
+			if limiter != nil {
+				event.RequestsPerSecond = limiter.throttled()
+			}
+			if opt.OnThrottle != nil {
+				opt.OnThrottle(event)
+			}
+		},
+	})
+}
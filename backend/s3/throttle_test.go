@@ -0,0 +1,161 @@
+package s3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/suite"
+)
+
+// quickRetryer retries without waiting, to keep tests fast.
+type quickRetryer struct {
+	client.DefaultRetryer
+}
+
+func (quickRetryer) RetryRules(*request.Request) time.Duration {
+	return time.Millisecond
+}
+
+type throttleTestSuite struct {
+	suite.Suite
+	server    *httptest.Server
+	throttles int
+	calls     int
+}
+
+func (ts *throttleTestSuite) SetupTest() {
+	ts.calls = 0
+	ts.throttles = 0
+	ts.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts.calls++
+		if ts.calls <= ts.throttles {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`<Error><Code>SlowDown</Code><Message>Please reduce your request rate.</Message></Error>`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+func (ts *throttleTestSuite) TearDownTest() {
+	ts.server.Close()
+}
+
+func (ts *throttleTestSuite) client(opts Options) *s3.S3 {
+	opts.Endpoint = ts.server.URL
+	opts.Region = "us-east-1"
+	opts.AccessKeyID = "key"
+	opts.SecretAccessKey = "secret"
+	opts.ForcePathStyle = true
+	opts.Retry = quickRetryer{client.DefaultRetryer{NumMaxRetries: 3}}
+	c, err := getClient(opts)
+	ts.Require().NoError(err)
+	return c.(*s3.S3)
+}
+
+func (ts *throttleTestSuite) TestOnThrottle() {
+	var events []ThrottleEvent
+	ts.throttles = 2
+	c := ts.client(Options{OnThrottle: func(event ThrottleEvent) { events = append(events, event) }})
+
+	_, err := c.DeleteObject(new(s3.DeleteObjectInput).SetBucket("bucket").SetKey("some/key"))
+	ts.NoError(err, "throttled requests were retried")
+	ts.Equal(3, ts.calls)
+	ts.Require().Len(events, 2)
+	ts.Equal(ThrottleEvent{
+		Operation:  "DeleteObject",
+		Bucket:     "bucket",
+		Key:        "some/key",
+		Code:       "SlowDown",
+		StatusCode: http.StatusServiceUnavailable,
+		Attempt:    1,
+	}, events[0])
+	ts.Equal(2, events[1].Attempt)
+	ts.Zero(events[1].RequestsPerSecond, "requests aren't limited without AdaptiveThrottling")
+}
+
+func (ts *throttleTestSuite) TestAdaptiveThrottling() {
+	var events []ThrottleEvent
+	ts.throttles = 1
+	c := ts.client(Options{
+		AdaptiveThrottling: true,
+		RequestsPerSecond:  100,
+		RequestBurst:       1,
+		OnThrottle:         func(event ThrottleEvent) { events = append(events, event) },
+	})
+
+	_, err := c.DeleteObject(new(s3.DeleteObjectInput).SetBucket("bucket").SetKey("key"))
+	ts.NoError(err)
+	ts.Require().Len(events, 1)
+	ts.Equal(float64(50), events[0].RequestsPerSecond, "rate was halved")
+
+	// requests are now limited to 50 per second
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := c.HeadObject(new(s3.HeadObjectInput).SetBucket("bucket").SetKey("key"))
+		ts.NoError(err)
+	}
+	ts.True(time.Since(start) >= 50*time.Millisecond, "requests were rate limited")
+}
+
+func (ts *throttleTestSuite) TestAdaptiveLimiter() {
+	limiter := newAdaptiveLimiter(0, 1)
+	ts.Nil(limiter.bucket, "requests aren't limited until they're throttled")
+	limiter.sent = 40
+
+	ts.Equal(float64(20), limiter.throttled(), "limited to half the rate requests were sent at")
+	ts.Equal(float64(20), limiter.throttled(), "not cut again for the same burst")
+	limiter.succeeded()
+	ts.Equal(float64(20), limiter.bucket.currentRate(), "not raised until an interval passes")
+
+	limiter.changed = time.Now().Add(-adaptiveInterval)
+	ts.Equal(float64(10), limiter.throttled())
+	limiter.changed = time.Now().Add(-adaptiveInterval)
+	limiter.succeeded()
+	ts.InDelta(11, limiter.bucket.currentRate(), 0.001, "raised by 10%")
+
+	for i := 0; i < 10; i++ {
+		limiter.changed = time.Now().Add(-adaptiveInterval)
+		ts.True(limiter.throttled() >= minAdaptiveRate)
+	}
+	ts.Equal(float64(minAdaptiveRate), limiter.bucket.currentRate())
+
+	limiter = newAdaptiveLimiter(5, 1)
+	limiter.changed = time.Now().Add(-adaptiveInterval)
+	limiter.succeeded()
+	ts.Equal(float64(5), limiter.bucket.currentRate(), "never raised above RequestsPerSecond")
+}
+
+func (ts *throttleTestSuite) TestRetryAfter() {
+	retryer := throttleRetryer{Retryer: client.DefaultRetryer{NumMaxRetries: 3}}
+	throttled := func(retryAfter string) *request.Request {
+		r := &request.Request{
+			HTTPResponse: &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}},
+			Error:        awserr.NewRequestFailure(awserr.New("SlowDown", "slow down", nil), http.StatusServiceUnavailable, ""),
+		}
+		r.HTTPResponse.Header.Set("Retry-After", retryAfter)
+		return r
+	}
+
+	ts.Equal(7*time.Second, retryer.RetryRules(throttled("7")))
+	delay := retryer.RetryRules(throttled(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)))
+	ts.True(delay > 58*time.Second && delay <= time.Minute, "HTTP date is honored")
+	ts.True(retryer.RetryRules(throttled("soon")) < 10*time.Second, "invalid header is ignored")
+
+	r := throttled("30")
+	r.Error = awserr.NewRequestFailure(awserr.New("InternalError", "oops", nil), http.StatusInternalServerError, "")
+	r.HTTPResponse.StatusCode = http.StatusInternalServerError
+	ts.True(retryer.RetryRules(r) < 10*time.Second, "only throttled responses' Retry-After is honored")
+	ts.Zero(retryAfter(&request.Request{Error: aws.ErrMissingEndpoint}))
+}
+
+func TestThrottle(t *testing.T) {
+	suite.Run(t, new(throttleTestSuite))
+}
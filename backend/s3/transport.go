@@ -0,0 +1,56 @@
+package s3
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// httpClient returns an http client whose transport is tuned by the connection pool, proxy and TLS options set in opt,
+// or nil if none of them are, so the aws default (or the session's client) is used.
+func httpClient(opt Options) (*http.Client, error) {
+	if opt.MaxIdleConnsPerHost == 0 && opt.MaxConnsPerHost == 0 && opt.IdleConnTimeout == 0 &&
+		opt.ProxyURL == "" && opt.TLSConfig == nil {
+		return nil, nil
+	}
+
+	// the same as http.DefaultTransport, but not shared with the rest of the process
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	if opt.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = opt.MaxIdleConnsPerHost
+		if transport.MaxIdleConns < opt.MaxIdleConnsPerHost {
+			transport.MaxIdleConns = opt.MaxIdleConnsPerHost
+		}
+	}
+	if opt.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = opt.MaxConnsPerHost
+	}
+	if opt.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = opt.IdleConnTimeout
+	}
+	if opt.ProxyURL != "" {
+		proxy, err := url.Parse(opt.ProxyURL)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid ProxyURL %q", opt.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if opt.TLSConfig != nil {
+		transport.TLSClientConfig = opt.TLSConfig.Clone()
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
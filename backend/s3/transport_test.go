@@ -0,0 +1,75 @@
+package s3
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/suite"
+)
+
+type transportTestSuite struct {
+	suite.Suite
+}
+
+func (ts *transportTestSuite) transport(opts Options) *http.Transport {
+	client, err := getClient(opts)
+	ts.Require().NoError(err)
+	transport, ok := client.(*s3.S3).Config.HTTPClient.Transport.(*http.Transport)
+	ts.Require().True(ok, "client has its own transport")
+	return transport
+}
+
+func (ts *transportTestSuite) TestDefault() {
+	client, err := httpClient(Options{Region: "us-east-1"})
+	ts.NoError(err)
+	ts.Nil(client, "aws default is used")
+}
+
+func (ts *transportTestSuite) TestConnectionPool() {
+	transport := ts.transport(Options{
+		Region:              "us-east-1",
+		MaxIdleConnsPerHost: 256,
+		MaxConnsPerHost:     512,
+		IdleConnTimeout:     time.Minute,
+	})
+	ts.Equal(256, transport.MaxIdleConnsPerHost)
+	ts.Equal(256, transport.MaxIdleConns, "total idle connections hold at least those of one host")
+	ts.Equal(512, transport.MaxConnsPerHost)
+	ts.Equal(time.Minute, transport.IdleConnTimeout)
+	ts.NotNil(transport.Proxy, "proxy environment variables are still honored")
+	ts.NotEqual(http.DefaultTransport, transport, "default transport isn't modified")
+}
+
+func (ts *transportTestSuite) TestProxy() {
+	transport := ts.transport(Options{Region: "us-east-1", ProxyURL: "http://proxy.internal:3128"})
+	req, err := http.NewRequest(http.MethodGet, "https://bucket.s3.amazonaws.com/key", nil)
+	ts.NoError(err)
+	proxy, err := transport.Proxy(req)
+	ts.NoError(err)
+	ts.Equal("http://proxy.internal:3128", proxy.String())
+
+	_, err = getClient(Options{ProxyURL: "not a url"})
+	ts.EqualError(err, `invalid ProxyURL "not a url"`)
+}
+
+func (ts *transportTestSuite) TestTLSConfig() {
+	config := &tls.Config{ServerName: "minio.internal", MinVersion: tls.VersionTLS12}
+	transport := ts.transport(Options{Region: "us-east-1", TLSConfig: config})
+	ts.Equal("minio.internal", transport.TLSClientConfig.ServerName)
+	ts.True(config != transport.TLSClientConfig, "config is copied")
+}
+
+func (ts *transportTestSuite) TestReplacesSessionClient() {
+	sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1"), HTTPClient: &http.Client{}}))
+	transport := ts.transport(Options{Session: sess, MaxIdleConnsPerHost: 32})
+	ts.Equal(32, transport.MaxIdleConnsPerHost)
+}
+
+func TestTransport(t *testing.T) {
+	suite.Run(t, new(transportTestSuite))
+}
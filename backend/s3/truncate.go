@@ -0,0 +1,60 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// maxCopyPartSize is the maximum size s3 allows for a part copied with UploadPartCopy.
+const maxCopyPartSize = 5 * 1024 * 1024 * 1024
+
+// Truncate shortens the object to its first size bytes.  s3 objects can't be modified in place, so the object is
+// replaced server-side with a multipart upload copying that range of it, without downloading anything.  s3 objects
+// can't be extended this way either, so size must not be greater than the object's current size.  Anything written to
+// the file but not yet closed is unaffected, and still replaces the object on Close.
+func (f *File) Truncate(size int64) error {
+	if size < 0 {
+		return errors.New("non-negative size is required")
+	}
+	current, err := f.Size()
+	if err != nil {
+		return err
+	}
+	if size > int64(current) {
+		return fmt.Errorf("unable to extend %s from %d to %d bytes, s3 objects can only be shortened", f, current, size)
+	}
+	if size == int64(current) {
+		return nil
+	}
+	if size == 0 {
+		// there's no range to copy, so just upload an empty object
+		w, err := f.OpenWriter()
+		if err != nil {
+			return err
+		}
+		return w.Close()
+	}
+
+	copyInput, err := f.getCopyObjectInput(f)
+	if err != nil {
+		return err
+	}
+	if copyInput == nil {
+		return fmt.Errorf("unable to truncate %s server-side", f)
+	}
+
+	var parts []partCopy
+	for offset := int64(0); offset < size; offset += maxCopyPartSize {
+		end := offset + maxCopyPartSize
+		if end > size {
+			end = size
+		}
+		parts = append(parts, partCopy{
+			source:      aws.StringValue(copyInput.CopySource),
+			sourceRange: fmt.Sprintf("bytes=%d-%d", offset, end-1),
+		})
+	}
+	return f.copyParts(parts)
+}
@@ -0,0 +1,85 @@
+package s3
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type truncateTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	file      *File
+}
+
+func (ts *truncateTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	fs := &FileSystem{client: ts.s3apiMock, options: Options{AccessKeyID: "abc"}}
+	file, err := fs.NewFile("bucket", "/records.dat")
+	ts.NoError(err)
+	ts.file = file.(*File)
+	ts.file.head = &s3.HeadObjectOutput{ContentLength: aws.Int64(100)}
+}
+
+func (ts *truncateTestSuite) TestTruncate() {
+	ts.s3apiMock.On("CreateMultipartUpload", mock.MatchedBy(func(input *s3.CreateMultipartUploadInput) bool {
+		return *input.Key == "records.dat"
+	})).Return(&s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil).Once()
+	ts.s3apiMock.On("UploadPartCopy", mock.MatchedBy(func(input *s3.UploadPartCopyInput) bool {
+		return *input.CopySource == "bucket%2Frecords.dat" && *input.CopySourceRange == "bytes=0-39" &&
+			*input.PartNumber == 1
+	})).Return(&s3.UploadPartCopyOutput{CopyPartResult: &s3.CopyPartResult{ETag: aws.String("etag")}}, nil).Once()
+	ts.s3apiMock.On("CompleteMultipartUpload", mock.MatchedBy(func(input *s3.CompleteMultipartUploadInput) bool {
+		return len(input.MultipartUpload.Parts) == 1
+	})).Return(&s3.CompleteMultipartUploadOutput{}, nil).Once()
+
+	ts.NoError(ts.file.Truncate(40))
+	ts.Nil(ts.file.head, "HEAD cache should be cleared")
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *truncateTestSuite) TestTruncate_CopyModeStream() {
+	// copies within a file system are made server-side even when copies between file systems are streamed
+	ts.file.fileSystem.options = Options{AccessKeyID: "abc", CopyMode: CopyModeStream}
+	ts.s3apiMock.On("CreateMultipartUpload", mock.AnythingOfType("*s3.CreateMultipartUploadInput")).
+		Return(&s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil).Once()
+	ts.s3apiMock.On("UploadPartCopy", mock.MatchedBy(func(input *s3.UploadPartCopyInput) bool {
+		return *input.CopySource == "bucket%2Frecords.dat" && *input.CopySourceRange == "bytes=0-39"
+	})).Return(&s3.UploadPartCopyOutput{CopyPartResult: &s3.CopyPartResult{ETag: aws.String("etag")}}, nil).Once()
+	ts.s3apiMock.On("CompleteMultipartUpload", mock.AnythingOfType("*s3.CompleteMultipartUploadInput")).
+		Return(&s3.CompleteMultipartUploadOutput{}, nil).Once()
+
+	ts.NoError(ts.file.Truncate(40))
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *truncateTestSuite) TestTruncate_Empty() {
+	ts.s3apiMock.On("PutObjectRequest", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return *input.Key == "records.dat"
+	})).Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}},
+		&s3.PutObjectOutput{}).Once()
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil)
+
+	ts.NoError(ts.file.Truncate(0))
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *truncateTestSuite) TestTruncate_NoOpAndErrors() {
+	ts.NoError(ts.file.Truncate(100))
+	ts.EqualError(ts.file.Truncate(101),
+		"unable to extend s3://bucket/records.dat from 100 to 101 bytes, s3 objects can only be shortened")
+	ts.EqualError(ts.file.Truncate(-1), "non-negative size is required")
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func TestTruncate(t *testing.T) {
+	suite.Run(t, new(truncateTestSuite))
+}
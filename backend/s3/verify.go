@@ -0,0 +1,85 @@
+package s3
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// WriteVerificationError is returned by Close, and by Flush and the Close of a writer from OpenWriter, when
+// Options.VerifyWrites is set and the object found in s3 after an upload doesn't match what was written, ie: because
+// a proxy truncated the upload.
+type WriteVerificationError struct {
+	// URI is the URI of the object.
+	URI string
+	// Field is what didn't match: "size" or "checksum".
+	Field string
+	// Written is the value of Field for the data written, and Found its value for the object in s3.
+	Written string
+	Found   string
+}
+
+// Error implements error.
+func (e *WriteVerificationError) Error() string {
+	return fmt.Sprintf("object %s doesn't match what was written: its %s is %s, expected %s", e.URI, e.Field, e.Found,
+		e.Written)
+}
+
+// verifyUpload checks that the object, just uploaded, has the size written and, where its ETag is the MD5 of its
+// contents, the MD5 in hashes, returning a *WriteVerificationError if it doesn't.  The object is first waited for as
+// Close would, since it can't be checked until it's visible.
+func (f *File) verifyUpload(size int64, hashes *writeHashes) error {
+	if err := f.waitForWrite(); err != nil {
+		return err
+	}
+	head, err := f.getHeadObject()
+	if err != nil {
+		return err
+	}
+
+	if found := aws.Int64Value(head.ContentLength); found != size {
+		f.invalidateHead()
+		return &WriteVerificationError{
+			URI:     f.URI(),
+			Field:   "size",
+			Written: strconv.FormatInt(size, 10),
+			Found:   strconv.FormatInt(found, 10),
+		}
+	}
+	if hashes == nil || !etagIsMD5(head) {
+		return nil
+	}
+	written := hex.EncodeToString(hashes.md5.Sum(nil))
+	if found := strings.Trim(aws.StringValue(head.ETag), `"`); found != written {
+		f.invalidateHead()
+		return &WriteVerificationError{URI: f.URI(), Field: "checksum", Written: written, Found: found}
+	}
+	return nil
+}
+
+// ContentMD5 returns the hex encoded MD5 of the object's contents from its ETag, so utils.VerifyCopy can verify a copy
+// to or from s3 without reading the object.  ok is false when the ETag isn't an MD5, ie: for multipart uploads.
+func (f *File) ContentMD5() (md5 string, ok bool, err error) {
+	// a HEAD filled in from a listing doesn't say how the object is encrypted, which etagIsMD5 depends on
+	head, err := f.getFullHeadObject()
+	if err != nil {
+		return "", false, err
+	}
+	if !etagIsMD5(head) {
+		return "", false, nil
+	}
+	return strings.Trim(aws.StringValue(head.ETag), `"`), true, nil
+}
+
+// etagIsMD5 returns true if the ETag of the object is the MD5 of its contents, which it isn't for multipart uploads
+// (whose ETags end in "-" and the number of parts) or objects encrypted with SSE-KMS or SSE-C.
+func etagIsMD5(head *s3.HeadObjectOutput) bool {
+	return head.ETag != nil &&
+		!strings.Contains(aws.StringValue(head.ETag), "-") &&
+		aws.StringValue(head.ServerSideEncryption) != s3.ServerSideEncryptionAwsKms &&
+		head.SSECustomerAlgorithm == nil
+}
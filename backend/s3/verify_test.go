@@ -0,0 +1,143 @@
+package s3
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+// md5 of "hello world"
+const helloWorldMD5 = "5eb63bbbe01eeed093cb22bb8f5acdc3"
+
+type verifyTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	fs        *FileSystem
+}
+
+func (ts *verifyTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	ts.fs = &FileSystem{client: ts.s3apiMock, options: Options{VerifyWrites: true, WaitForWrite: NoWait}}
+}
+
+func (ts *verifyTestSuite) expectUpload() {
+	ts.s3apiMock.On("PutObjectRequest", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return aws.StringValue(input.ContentMD5) == "XrY7u+Ae7tCTyyK7j1rNww=="
+	})).Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}},
+		&s3.PutObjectOutput{}).Once()
+}
+
+func (ts *verifyTestSuite) expectHead(size int64, etag string) {
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(size), ETag: aws.String(etag)}, nil).Once()
+}
+
+func (ts *verifyTestSuite) write() *File {
+	file, err := ts.fs.NewFile("bucket", "/file.txt")
+	ts.NoError(err)
+	_, err = file.Write([]byte("hello world"))
+	ts.NoError(err)
+	return file.(*File)
+}
+
+func (ts *verifyTestSuite) TestVerified() {
+	ts.expectUpload()
+	ts.expectHead(11, `"`+helloWorldMD5+`"`)
+
+	file := ts.write()
+	ts.NoError(file.Close())
+	ts.Equal(helloWorldMD5, file.Checksums().MD5, "checksums are computed to verify them")
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *verifyTestSuite) TestSizeMismatch() {
+	ts.expectUpload()
+	ts.expectHead(5, `"`+helloWorldMD5+`"`)
+
+	file := ts.write()
+	err := file.Close()
+	ts.Equal(&WriteVerificationError{URI: "s3://bucket/file.txt", Field: "size", Written: "11", Found: "5"}, err)
+	ts.EqualError(err, "object s3://bucket/file.txt doesn't match what was written: its size is 5, expected 11")
+
+	// the data written is kept, so Close can be retried
+	ts.expectUpload()
+	ts.expectHead(11, `"`+helloWorldMD5+`"`)
+	ts.NoError(file.Close())
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *verifyTestSuite) TestChecksumMismatch() {
+	ts.expectUpload()
+	ts.expectHead(11, `"00000000000000000000000000000000"`)
+
+	err := ts.write().Close()
+	ts.Equal(&WriteVerificationError{
+		URI:     "s3://bucket/file.txt",
+		Field:   "checksum",
+		Written: helloWorldMD5,
+		Found:   "00000000000000000000000000000000",
+	}, err)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *verifyTestSuite) TestMultipartETagNotCompared() {
+	ts.expectUpload()
+	ts.expectHead(11, `"00000000000000000000000000000000-2"`)
+
+	ts.NoError(ts.write().Close())
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *verifyTestSuite) TestFlush() {
+	ts.expectUpload()
+	ts.expectHead(5, `"`+helloWorldMD5+`"`)
+
+	file := ts.write()
+	ts.IsType(&WriteVerificationError{}, file.Flush())
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *verifyTestSuite) TestEtagIsMD5() {
+	ts.True(etagIsMD5(&s3.HeadObjectOutput{ETag: aws.String(`"` + helloWorldMD5 + `"`)}))
+	ts.False(etagIsMD5(&s3.HeadObjectOutput{}))
+	ts.False(etagIsMD5(&s3.HeadObjectOutput{ETag: aws.String(`"` + helloWorldMD5 + `-3"`)}))
+	ts.False(etagIsMD5(&s3.HeadObjectOutput{
+		ETag:                 aws.String(`"` + helloWorldMD5 + `"`),
+		ServerSideEncryption: aws.String(s3.ServerSideEncryptionAwsKms),
+	}))
+	ts.False(etagIsMD5(&s3.HeadObjectOutput{
+		ETag:                 aws.String(`"` + helloWorldMD5 + `"`),
+		SSECustomerAlgorithm: aws.String("AES256"),
+	}))
+}
+
+func (ts *verifyTestSuite) TestContentMD5() {
+	file := &File{fileSystem: ts.fs, bucket: "bucket", key: "/hello.txt"}
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(11), ETag: aws.String(`"` + helloWorldMD5 + `"`)}, nil).Once()
+	md5, ok, err := file.ContentMD5()
+	ts.NoError(err)
+	ts.True(ok)
+	ts.Equal(helloWorldMD5, md5)
+
+	// the ETag of a multipart upload isn't an MD5
+	file = &File{fileSystem: ts.fs, bucket: "bucket", key: "/multipart.txt"}
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).
+		Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(11), ETag: aws.String(`"` + helloWorldMD5 + `-3"`)}, nil).Once()
+	_, ok, err = file.ContentMD5()
+	ts.NoError(err)
+	ts.False(ok)
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func TestVerify(t *testing.T) {
+	suite.Run(t, new(verifyTestSuite))
+}
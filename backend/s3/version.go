@@ -0,0 +1,267 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// Version describes a single version of an object in a versioned s3 bucket.
+type Version struct {
+	VersionID    string
+	IsLatest     bool
+	LastModified time.Time
+	Size         int64
+	ETag         string
+}
+
+// DeleteMarker describes a delete marker placed on a key in a versioned s3 bucket by a non-versioned delete.
+type DeleteMarker struct {
+	VersionID    string
+	IsLatest     bool
+	LastModified time.Time
+}
+
+// maxDeleteObjects is the maximum number of keys the s3 DeleteObjects API accepts per request.
+const maxDeleteObjects = 1000
+
+// Versions returns all versions of the file's key, newest first, as reported by the s3 ListObjectVersions API. This
+// will make a call to the s3 API for every 1000 versions returned. Buckets without versioning enabled report a single
+// version with a VersionID of "null".
+func (f *File) Versions() ([]Version, error) {
+	var versions []Version
+	err := f.listObjectVersions(func(output *s3.ListObjectVersionsOutput) {
+		key := keyOf(f.key)
+		for _, v := range output.Versions {
+			if aws.StringValue(v.Key) != key {
+				continue
+			}
+			versions = append(versions, Version{
+				VersionID:    aws.StringValue(v.VersionId),
+				IsLatest:     aws.BoolValue(v.IsLatest),
+				LastModified: aws.TimeValue(v.LastModified),
+				Size:         aws.Int64Value(v.Size),
+				ETag:         aws.StringValue(v.ETag),
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// WithVersion returns a new *File pinned to the given VersionId.  Reads, Size, LastModified and Exists on the
+// returned file act on that version rather than the latest one.  Writes to a version-pinned file are not allowed.
+func (f *File) WithVersion(versionID string) (*File, error) {
+	if versionID == "" {
+		return nil, errors.New("non-empty string versionID is required")
+	}
+	return &File{
+		fileSystem: f.fileSystem,
+		bucket:     f.bucket,
+		key:        f.key,
+		versionID:  versionID,
+	}, nil
+}
+
+// VersionID returns the VersionId the file is pinned to, or "" if the file refers to the latest version.  It makes no
+// request; see ResolveVersionID for the VersionId of the latest version.
+func (f *File) VersionID() string {
+	return f.versionID
+}
+
+// RestoreVersion makes the given version the latest version of the file by natively copying it in place.
+func (f *File) RestoreVersion(versionID string) error {
+	if versionID == "" {
+		return errors.New("non-empty string versionID is required")
+	}
+
+	//PathEscape ensures we url-encode as required by the API, including double-encoding literals
+	copySource := url.PathEscape(f.bucket+"/"+keyOf(f.key)) + "?versionId=" + url.QueryEscape(versionID)
+
+	copyInput := new(s3.CopyObjectInput).
+		SetServerSideEncryption("AES256").
+		SetKey(keyOf(f.key)).
+		SetBucket(f.bucket).
+		SetCopySource(copySource)
+
+	if opts, ok := f.fileSystem.options.(Options); ok && opts.ACL != "" {
+		copyInput.SetACL(opts.ACL)
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+	_, err = client.CopyObject(copyInput)
+	f.invalidateHead()
+	return err
+}
+
+// DeleteVersion permanently removes the given version of the file.  Unlike Delete, no delete marker is created.
+func (f *File) DeleteVersion(versionID string) error {
+	if versionID == "" {
+		return errors.New("non-empty string versionID is required")
+	}
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteObject(&s3.DeleteObjectInput{
+		Key:       aws.String(keyOf(f.key)),
+		Bucket:    &f.bucket,
+		VersionId: &versionID,
+	})
+	f.invalidateHead()
+	return err
+}
+
+// DeleteMarkers returns all delete markers on the file's key, newest first.
+func (f *File) DeleteMarkers() ([]DeleteMarker, error) {
+	var markers []DeleteMarker
+	err := f.listObjectVersions(func(output *s3.ListObjectVersionsOutput) {
+		key := keyOf(f.key)
+		for _, m := range output.DeleteMarkers {
+			if aws.StringValue(m.Key) != key {
+				continue
+			}
+			markers = append(markers, DeleteMarker{
+				VersionID:    aws.StringValue(m.VersionId),
+				IsLatest:     aws.BoolValue(m.IsLatest),
+				LastModified: aws.TimeValue(m.LastModified),
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return markers, nil
+}
+
+// IsDeleteMarked returns true if the latest version of the file's key is a delete marker.  In that case Exists will
+// report false even though prior versions remain in the bucket.
+func (f *File) IsDeleteMarked() (bool, error) {
+	markers, err := f.DeleteMarkers()
+	if err != nil {
+		return false, err
+	}
+	for _, m := range markers {
+		if m.IsLatest {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RemoveDeleteMarkers permanently removes all delete markers on the file's key.  If any versions remain, the most
+// recent of them becomes the latest version again, effectively undoing a Delete.
+func (f *File) RemoveDeleteMarkers() error {
+	markers, err := f.DeleteMarkers()
+	if err != nil {
+		return err
+	}
+	versionIDs := make([]string, 0, len(markers))
+	for _, m := range markers {
+		versionIDs = append(versionIDs, m.VersionID)
+	}
+	return f.deleteVersionIDs(versionIDs)
+}
+
+// DeleteAllVersions permanently removes every version and delete marker of the file's key, fully purging it from a
+// versioned bucket.
+func (f *File) DeleteAllVersions() error {
+	var versionIDs []string
+	err := f.listObjectVersions(func(output *s3.ListObjectVersionsOutput) {
+		key := keyOf(f.key)
+		for _, v := range output.Versions {
+			if aws.StringValue(v.Key) == key {
+				versionIDs = append(versionIDs, aws.StringValue(v.VersionId))
+			}
+		}
+		for _, m := range output.DeleteMarkers {
+			if aws.StringValue(m.Key) == key {
+				versionIDs = append(versionIDs, aws.StringValue(m.VersionId))
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return f.deleteVersionIDs(versionIDs)
+}
+
+// deleteVersionIDs removes the given versions of the file's key using DeleteObjects, in batches of maxDeleteObjects.
+func (f *File) deleteVersionIDs(versionIDs []string) error {
+	if len(versionIDs) == 0 {
+		return nil
+	}
+	defer f.invalidateHead()
+
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	key := keyOf(f.key)
+	for start := 0; start < len(versionIDs); start += maxDeleteObjects {
+		end := start + maxDeleteObjects
+		if end > len(versionIDs) {
+			end = len(versionIDs)
+		}
+
+		objects := make([]*s3.ObjectIdentifier, 0, end-start)
+		for _, id := range versionIDs[start:end] {
+			objects = append(objects, new(s3.ObjectIdentifier).SetKey(key).SetVersionId(id))
+		}
+
+		output, err := client.DeleteObjects(new(s3.DeleteObjectsInput).
+			SetBucket(f.bucket).
+			SetDelete(new(s3.Delete).SetObjects(objects).SetQuiet(true)))
+		if err != nil {
+			return err
+		}
+		if len(output.Errors) > 0 {
+			e := output.Errors[0]
+			return fmt.Errorf("unable to delete version %s of %s: %s", aws.StringValue(e.VersionId), f, aws.StringValue(e.Message))
+		}
+	}
+
+	return nil
+}
+
+// listObjectVersions pages through ListObjectVersions for the file's key, passing each page to fn.
+func (f *File) listObjectVersions(fn func(output *s3.ListObjectVersionsOutput)) error {
+	client, err := f.fileSystem.Client()
+	if err != nil {
+		return err
+	}
+
+	input := new(s3.ListObjectVersionsInput).
+		SetBucket(f.bucket).
+		SetPrefix(keyOf(f.key))
+
+	for {
+		output, err := client.ListObjectVersions(input)
+		if err != nil {
+			return err
+		}
+		fn(output)
+
+		// if s3 response "IsTruncated" we need to call again with updated key and version markers
+		if aws.BoolValue(output.IsTruncated) {
+			input.SetKeyMarker(aws.StringValue(output.NextKeyMarker))
+			input.SetVersionIdMarker(aws.StringValue(output.NextVersionIdMarker))
+		} else {
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package s3
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type versionTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+	fs        *FileSystem
+}
+
+func (ts *versionTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	ts.fs = &FileSystem{client: ts.s3apiMock, options: Options{AccessKeyID: "abc"}}
+}
+
+func (ts *versionTestSuite) newFile(name string) *File {
+	file, err := ts.fs.NewFile("bucket", name)
+	ts.NoError(err)
+	return file.(*File)
+}
+
+func (ts *versionTestSuite) TestVersions() {
+	now := time.Now()
+	ts.s3apiMock.On("ListObjectVersions", mock.MatchedBy(func(input *s3.ListObjectVersionsInput) bool {
+		return *input.Prefix == "some/path/file.txt" && input.KeyMarker == nil
+	})).Return(&s3.ListObjectVersionsOutput{
+		IsTruncated:         aws.Bool(true),
+		NextKeyMarker:       aws.String("some/path/file.txt"),
+		NextVersionIdMarker: aws.String("v2"),
+		Versions: []*s3.ObjectVersion{
+			{Key: aws.String("some/path/file.txt"), VersionId: aws.String("v3"), IsLatest: aws.Bool(true), LastModified: &now, Size: aws.Int64(3)},
+			{Key: aws.String("some/path/file.txt.bak"), VersionId: aws.String("x1")},
+			{Key: aws.String("some/path/file.txt"), VersionId: aws.String("v2"), Size: aws.Int64(2)},
+		},
+	}, nil).Once()
+	ts.s3apiMock.On("ListObjectVersions", mock.MatchedBy(func(input *s3.ListObjectVersionsInput) bool {
+		return aws.StringValue(input.KeyMarker) == "some/path/file.txt" && aws.StringValue(input.VersionIdMarker) == "v2"
+	})).Return(&s3.ListObjectVersionsOutput{
+		IsTruncated: aws.Bool(false),
+		Versions: []*s3.ObjectVersion{
+			{Key: aws.String("some/path/file.txt"), VersionId: aws.String("v1"), Size: aws.Int64(1)},
+		},
+	}, nil).Once()
+
+	versions, err := ts.newFile("/some/path/file.txt").Versions()
+	ts.NoError(err)
+	ts.Len(versions, 3, "only exact key matches are returned")
+	ts.Equal("v3", versions[0].VersionID)
+	ts.True(versions[0].IsLatest)
+	ts.Equal(now, versions[0].LastModified)
+	ts.Equal(int64(3), versions[0].Size)
+	ts.Equal("v1", versions[2].VersionID)
+	ts.s3apiMock.AssertExpectations(ts.T())
+
+	// list error
+	ts.s3apiMock.On("ListObjectVersions", mock.Anything).Return(nil, errors.New("list error")).Once()
+	_, err = ts.newFile("/some/path/file.txt").Versions()
+	ts.EqualError(err, "list error")
+}
+
+func (ts *versionTestSuite) TestWithVersion() {
+	file := ts.newFile("/some/path/file.txt")
+	_, err := file.WithVersion("")
+	ts.EqualError(err, "non-empty string versionID is required")
+
+	versioned, err := file.WithVersion("v1")
+	ts.NoError(err)
+	ts.Equal("v1", versioned.VersionID())
+	ts.Equal("", file.VersionID(), "original file is unchanged")
+	ts.Equal(file.URI(), versioned.URI())
+
+	ts.s3apiMock.On("GetObject", mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+		return aws.StringValue(input.VersionId) == "v1"
+	})).Return(&s3.GetObjectOutput{Body: nopCloser{bytes.NewBufferString("old contents")}}, nil)
+	ts.s3apiMock.On("HeadObject", mock.MatchedBy(func(input *s3.HeadObjectInput) bool {
+		return aws.StringValue(input.VersionId) == "v1"
+	})).Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(12)}, nil)
+
+	contents, err := ioutil.ReadAll(versioned)
+	ts.NoError(err)
+	ts.Equal("old contents", string(contents))
+
+	size, err := versioned.Size()
+	ts.NoError(err)
+	ts.Equal(uint64(12), size)
+
+	_, err = versioned.Write([]byte("new"))
+	ts.EqualError(err, "unable to write to a specific version of an s3 object")
+
+	ts.NoError(versioned.Close())
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *versionTestSuite) TestRestoreVersion() {
+	file := ts.newFile("/some/path/file name.txt")
+	ts.EqualError(file.RestoreVersion(""), "non-empty string versionID is required")
+
+	ts.s3apiMock.On("CopyObject", mock.MatchedBy(func(input *s3.CopyObjectInput) bool {
+		return *input.CopySource == "bucket%2Fsome%2Fpath%2Ffile%20name.txt?versionId=v%2B1" &&
+			*input.Key == "some/path/file name.txt" && *input.Bucket == "bucket"
+	})).Return(&s3.CopyObjectOutput{}, nil)
+
+	ts.NoError(file.RestoreVersion("v+1"))
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *versionTestSuite) TestDeleteVersion() {
+	file := ts.newFile("/some/path/file.txt")
+	ts.EqualError(file.DeleteVersion(""), "non-empty string versionID is required")
+
+	ts.s3apiMock.On("DeleteObject", mock.MatchedBy(func(input *s3.DeleteObjectInput) bool {
+		return aws.StringValue(input.VersionId) == "v1"
+	})).Return(&s3.DeleteObjectOutput{}, nil)
+
+	ts.NoError(file.DeleteVersion("v1"))
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *versionTestSuite) listWithDeleteMarker() {
+	ts.s3apiMock.On("ListObjectVersions", mock.AnythingOfType("*s3.ListObjectVersionsInput")).Return(&s3.ListObjectVersionsOutput{
+		IsTruncated: aws.Bool(false),
+		Versions: []*s3.ObjectVersion{
+			{Key: aws.String("some/path/file.txt"), VersionId: aws.String("v1")},
+			{Key: aws.String("some/path/file.txt.bak"), VersionId: aws.String("x1")},
+		},
+		DeleteMarkers: []*s3.DeleteMarkerEntry{
+			{Key: aws.String("some/path/file.txt"), VersionId: aws.String("dm1"), IsLatest: aws.Bool(true)},
+			{Key: aws.String("some/path/file.txt.bak"), VersionId: aws.String("x2"), IsLatest: aws.Bool(true)},
+		},
+	}, nil)
+}
+
+func (ts *versionTestSuite) TestDeleteMarkers() {
+	ts.listWithDeleteMarker()
+	file := ts.newFile("/some/path/file.txt")
+
+	markers, err := file.DeleteMarkers()
+	ts.NoError(err)
+	ts.Len(markers, 1)
+	ts.Equal("dm1", markers[0].VersionID)
+
+	marked, err := file.IsDeleteMarked()
+	ts.NoError(err)
+	ts.True(marked, "latest version is a delete marker")
+}
+
+func (ts *versionTestSuite) TestRemoveDeleteMarkers() {
+	ts.listWithDeleteMarker()
+	ts.s3apiMock.On("DeleteObjects", mock.MatchedBy(func(input *s3.DeleteObjectsInput) bool {
+		return len(input.Delete.Objects) == 1 &&
+			*input.Delete.Objects[0].Key == "some/path/file.txt" &&
+			*input.Delete.Objects[0].VersionId == "dm1"
+	})).Return(&s3.DeleteObjectsOutput{}, nil)
+
+	ts.NoError(ts.newFile("/some/path/file.txt").RemoveDeleteMarkers())
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *versionTestSuite) TestDeleteAllVersions() {
+	ts.listWithDeleteMarker()
+	ts.s3apiMock.On("DeleteObjects", mock.MatchedBy(func(input *s3.DeleteObjectsInput) bool {
+		return len(input.Delete.Objects) == 2 &&
+			*input.Delete.Objects[0].VersionId == "v1" &&
+			*input.Delete.Objects[1].VersionId == "dm1"
+	})).Return(&s3.DeleteObjectsOutput{
+		Errors: []*s3.Error{{VersionId: aws.String("v1"), Message: aws.String("Access Denied")}},
+	}, nil)
+
+	err := ts.newFile("/some/path/file.txt").DeleteAllVersions()
+	ts.EqualError(err, "unable to delete version v1 of s3://bucket/some/path/file.txt: Access Denied")
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *versionTestSuite) TestDeleteAllVersions_NoVersions() {
+	ts.s3apiMock.On("ListObjectVersions", mock.AnythingOfType("*s3.ListObjectVersionsInput")).
+		Return(&s3.ListObjectVersionsOutput{IsTruncated: aws.Bool(false)}, nil)
+
+	ts.NoError(ts.newFile("/some/path/file.txt").DeleteAllVersions(), "nothing to delete")
+	ts.s3apiMock.AssertNotCalled(ts.T(), "DeleteObjects", mock.Anything)
+}
+
+func TestVersion(t *testing.T) {
+	suite.Run(t, new(versionTestSuite))
+}
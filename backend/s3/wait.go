@@ -0,0 +1,57 @@
+package s3
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+const (
+	// defaultWaitRetries and defaultWaitInterval are the wait used when Options doesn't set WaitForWrite.
+	defaultWaitRetries  = 5
+	defaultWaitInterval = time.Second
+)
+
+// WaitStrategy is called by Close after writing a file to s3, to wait until the file is visible, ie: on S3-compatible
+// stores that are only eventually consistent.  It returns an error if the file doesn't become visible.
+type WaitStrategy func(file vfs.File) error
+
+// NoWait is a WaitStrategy that returns immediately.  s3 itself is strongly consistent, so needs no wait.
+func NoWait(file vfs.File) error {
+	return nil
+}
+
+// WaitUntilExists returns a WaitStrategy that checks whether the file exists up to retries times, interval apart,
+// returning an error if it's still not found.
+func WaitUntilExists(retries int, interval time.Duration) WaitStrategy {
+	return func(file vfs.File) error {
+		for i := 0; i < retries; i++ {
+			if i > 0 {
+				time.Sleep(interval)
+			}
+			found, err := file.Exists()
+			if err != nil {
+				return fmt.Errorf("unable to perform S3 exists on file %s: %s", file, err.Error())
+			}
+			if found {
+				return nil
+			}
+		}
+		return fmt.Errorf("failed to find file %s after %d retries", file, retries)
+	}
+}
+
+// waitForWrite waits for the file, just written, with the WaitStrategy in Options, or by default
+// WaitUntilExists(5, time.Second).
+func (f *File) waitForWrite() error {
+	opts, _ := f.fileSystem.options.(Options)
+	switch {
+	case opts.DisableWaitForWrite:
+		return nil
+	case opts.WaitForWrite != nil:
+		return opts.WaitForWrite(f)
+	default:
+		return WaitUntilExists(defaultWaitRetries, defaultWaitInterval)(f)
+	}
+}
@@ -0,0 +1,86 @@
+package s3
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/mocks"
+)
+
+type waitTestSuite struct {
+	suite.Suite
+	s3apiMock *mocks.S3API
+}
+
+func (ts *waitTestSuite) SetupTest() {
+	ts.s3apiMock = &mocks.S3API{}
+	ts.s3apiMock.On("PutObjectRequest", mock.AnythingOfType("*s3.PutObjectInput")).
+		Return(&request.Request{HTTPRequest: &http.Request{Header: make(map[string][]string), URL: &url.URL{}}}, &s3.PutObjectOutput{})
+}
+
+// write writes to a new file on a FileSystem with options and closes it.
+func (ts *waitTestSuite) write(options Options) error {
+	fs := &FileSystem{client: ts.s3apiMock, options: options}
+	file, err := fs.NewFile("bucket", "/file.txt")
+	ts.Require().NoError(err)
+	_, err = file.Write([]byte("hello world"))
+	ts.Require().NoError(err)
+	return file.Close()
+}
+
+func (ts *waitTestSuite) TestDefaultWait() {
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil).Once()
+	ts.NoError(ts.write(Options{}))
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func (ts *waitTestSuite) TestDisableWaitForWrite() {
+	ts.NoError(ts.write(Options{DisableWaitForWrite: true}))
+	ts.NoError(ts.write(Options{WaitForWrite: NoWait}))
+	ts.s3apiMock.AssertNotCalled(ts.T(), "HeadObject", mock.Anything)
+}
+
+func (ts *waitTestSuite) TestCustomWait() {
+	var waited vfs.File
+	ts.NoError(ts.write(Options{WaitForWrite: func(file vfs.File) error {
+		waited = file
+		return nil
+	}}))
+	ts.Require().NotNil(waited)
+	ts.Equal("s3://bucket/file.txt", waited.URI())
+
+	ts.EqualError(ts.write(Options{WaitForWrite: func(file vfs.File) error {
+		return errors.New("not visible")
+	}}), "not visible")
+}
+
+func (ts *waitTestSuite) TestWaitUntilExists() {
+	notFound := awserr.New(s3.ErrCodeNoSuchKey, "no such key", nil)
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(nil, notFound).Twice()
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(&s3.HeadObjectOutput{}, nil).Once()
+	ts.NoError(ts.write(Options{WaitForWrite: WaitUntilExists(3, time.Millisecond)}))
+	ts.s3apiMock.AssertExpectations(ts.T())
+
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(nil, notFound).Twice()
+	ts.EqualError(ts.write(Options{WaitForWrite: WaitUntilExists(2, time.Millisecond)}),
+		"failed to find file s3://bucket/file.txt after 2 retries")
+
+	ts.s3apiMock.On("HeadObject", mock.AnythingOfType("*s3.HeadObjectInput")).Return(nil, errors.New("timeout")).Once()
+	ts.EqualError(ts.write(Options{WaitForWrite: WaitUntilExists(2, time.Millisecond)}),
+		"unable to perform S3 exists on file s3://bucket/file.txt: timeout")
+	ts.s3apiMock.AssertExpectations(ts.T())
+}
+
+func TestWait(t *testing.T) {
+	suite.Run(t, new(waitTestSuite))
+}
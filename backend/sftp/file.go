@@ -123,7 +123,7 @@ func (f *File) Location() vfs.Location {
 // returned.
 // If the given location is also sftp AND for the same user and host, the sftp Rename method is used, otherwise
 // we'll do a an io.Copy to the destination file then delete source file.
-func (f *File) MoveToFile(t vfs.File) error {
+func (f *File) MoveToFile(t vfs.File, opts ...vfs.CopyOption) error {
 	// sftp rename if vfs is sftp and for the same user/host
 	if f.fileSystem.Scheme() == t.Location().FileSystem().Scheme() &&
 		f.Authority.User == t.(*File).Authority.User &&
@@ -147,22 +147,37 @@ func (f *File) MoveToFile(t vfs.File) error {
 		return f.sftpRename(t.(*File))
 	}
 
-	//otherwise do copy-delete
-	if err := f.CopyToFile(t); err != nil {
+	//otherwise do copy-delete, verifying the copy before deleting unless disabled
+	if err := f.CopyToFile(t, opts...); err != nil {
 		return err
 	}
+	fsOpts, _ := f.fileSystem.options.(Options)
+	if !fsOpts.DisableMoveVerification && !vfs.NewCopyOptions(opts...).SkipMoveVerification {
+		if err := utils.VerifyCopy(f, t); err != nil {
+			return err
+		}
+	}
 	return f.Delete()
 }
 
-// MoveToLocation works by creating a new file on the target location then calling MoveToFile() on it.
-func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
+// MoveToLocation works by creating a new file on the target location then calling MoveToFile() on it.  If a file of the
+// same name already exists at the location, the ConflictPolicy in Options determines what happens (overwriting it by
+// default).
+func (f *File) MoveToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
 
 	newFile, err := location.FileSystem().NewFile(location.Volume(), path.Join(location.Path(), f.Name()))
 	if err != nil {
 		return nil, err
 	}
+	newFile, skip, err := utils.ResolveConflict(newFile, f.conflictPolicy(opts))
+	if err != nil {
+		return nil, err
+	}
+	if skip {
+		return newFile, nil
+	}
 
-	err = f.MoveToFile(newFile)
+	err = f.MoveToFile(newFile, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -170,9 +185,9 @@ func (f *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
 }
 
 // CopyToFile puts the contents of File into the targetFile passed.
-func (f *File) CopyToFile(file vfs.File) error {
-
-	if err := utils.TouchCopy(file, f); err != nil {
+func (f *File) CopyToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	options := vfs.NewCopyOptions(opts...)
+	if err := utils.TouchCopyWithOptions(file, f, options); err != nil {
 		return err
 	}
 	//Close target to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
@@ -180,32 +195,47 @@ func (f *File) CopyToFile(file vfs.File) error {
 		return cerr
 	}
 	//Close file (f) reader
-	return f.Close()
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if options.VerifyChecksum {
+		return utils.VerifyChecksum(f, file)
+	}
+	return nil
 }
 
 // CopyToLocation creates a copy of *File, using the file's current path as the new file's
-// path at the given location.
-func (f *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
+// path at the given location.  If a file of the same name already exists at the location, the
+// ConflictPolicy in Options determines what happens (overwriting it by default).
+func (f *File) CopyToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
 
 	newFile, err := location.FileSystem().NewFile(location.Volume(), path.Join(location.Path(), f.Name()))
 	if err != nil {
 		return nil, err
 	}
-
-	if err := utils.TouchCopy(newFile, f); err != nil {
+	newFile, skip, err := utils.ResolveConflict(newFile, f.conflictPolicy(opts))
+	if err != nil {
 		return nil, err
 	}
-	//Close target file to flush and ensure that cursor isn't at the end of the file when the caller reopens for read
-	if cerr := newFile.Close(); cerr != nil {
-		return nil, cerr
+	if skip {
+		return newFile, nil
 	}
-	//Close file (f) reader
-	if cerr := f.Close(); cerr != nil {
-		return nil, cerr
+
+	if err := f.CopyToFile(newFile, opts...); err != nil {
+		return nil, err
 	}
 	return newFile, nil
 }
 
+// conflictPolicy returns the ConflictPolicy set in opts, or else in Options.
+func (f *File) conflictPolicy(opts []vfs.CopyOption) vfs.ConflictPolicy {
+	policy := vfs.ConflictOverwrite
+	if fsOpts, ok := f.fileSystem.options.(Options); ok {
+		policy = fsOpts.ConflictPolicy
+	}
+	return vfs.NewCopyOptions(opts...).ConflictPolicyOr(policy)
+}
+
 // CRUD Operations
 
 // Delete removes the remote file.  Error is returned, if any.
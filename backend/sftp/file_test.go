@@ -346,7 +346,8 @@ func (ts *fileTestSuite) TestMoveToFile_differentAuthority() {
 	sourceFileInfo.On("Size").Return(int64(contentLength))
 
 	sourceClient := &mocks.Client{}
-	sourceClient.On("Stat", mock.Anything).Return(sourceFileInfo, nil).Once()
+	// once for the copy, once to verify it
+	sourceClient.On("Stat", mock.Anything).Return(sourceFileInfo, nil).Twice()
 	sourceClient.On("Remove", mock.Anything).Return(nil).Once()
 
 	sourceSftpFile := &mocks.SFTPFile{}
@@ -366,7 +367,11 @@ func (ts *fileTestSuite) TestMoveToFile_differentAuthority() {
 	}
 
 	// set up target
+	targetFileInfo := &mocks.FileInfo{}
+	targetFileInfo.On("Size").Return(int64(contentLength))
+
 	targetClient := &mocks.Client{}
+	targetClient.On("Stat", mock.Anything).Return(targetFileInfo, nil).Once()
 
 	targetSftpFile := &mocks.SFTPFile{}
 	targetSftpFile.On("Write", mock.Anything).Return(contentLength, nil).Once()
@@ -396,6 +401,56 @@ func (ts *fileTestSuite) TestMoveToFile_differentAuthority() {
 	targetSftpFile.AssertExpectations(ts.T())
 }
 
+func (ts *fileTestSuite) TestMoveToFile_verificationFailed() {
+	// set up source
+	sourceFileInfo := &mocks.FileInfo{}
+	sourceFileInfo.On("Size").Return(int64(10))
+
+	sourceClient := &mocks.Client{}
+	sourceClient.On("Stat", mock.Anything).Return(sourceFileInfo, nil)
+
+	sourceSftpFile := &mocks.SFTPFile{}
+	sourceSftpFile.On("Read", mock.Anything).Return(0, io.EOF)
+	sourceSftpFile.On("Close").Return(nil)
+
+	sourceFile := &File{
+		fileSystem: &FileSystem{sftpclient: sourceClient, options: Options{}},
+		Authority:  utils.Authority{Host: "host1.com:22", User: "user"},
+		path:       "/some/path.txt",
+		sftpfile:   sourceSftpFile,
+	}
+
+	// set up target, which comes up short
+	targetFileInfo := &mocks.FileInfo{}
+	targetFileInfo.On("Size").Return(int64(0))
+
+	targetClient := &mocks.Client{}
+	targetClient.On("Stat", mock.Anything).Return(targetFileInfo, nil)
+
+	targetSftpFile := &mocks.SFTPFile{}
+	targetSftpFile.On("Close").Return(nil)
+
+	targetFile := &File{
+		fileSystem: &FileSystem{sftpclient: targetClient, options: Options{}},
+		Authority:  utils.Authority{Host: "host2.com:22", User: "user"},
+		path:       "/some/path.txt",
+		sftpfile:   targetSftpFile,
+	}
+
+	err := sourceFile.MoveToFile(targetFile)
+	ts.Error(err, "copy was incomplete")
+	ts.Contains(err.Error(), "failed verification")
+	sourceClient.AssertNotCalled(ts.T(), "Remove", mock.Anything)
+
+	// verification disabled
+	sourceFile.fileSystem.options = Options{DisableMoveVerification: true}
+	sourceFile.sftpfile = sourceSftpFile
+	targetFile.sftpfile = targetSftpFile
+	sourceClient.On("Remove", mock.Anything).Return(nil).Once()
+	ts.NoError(sourceFile.MoveToFile(targetFile))
+	sourceClient.AssertExpectations(ts.T())
+}
+
 func (ts *fileTestSuite) TestMoveToFile_sameAuthority() {
 	// set up source
 	sourceClient := &mocks.Client{}
@@ -449,7 +504,8 @@ func (ts *fileTestSuite) TestMoveToLocation() {
 	sourceFileInfo.On("Size").Return(int64(contentLength))
 
 	sourceClient := &mocks.Client{}
-	sourceClient.On("Stat", mock.Anything).Return(sourceFileInfo, nil).Once()
+	// once for the copy, once to verify it
+	sourceClient.On("Stat", mock.Anything).Return(sourceFileInfo, nil).Twice()
 	sourceClient.On("Remove", mock.Anything).Return(nil).Once()
 
 	sourceSftpFile := &mocks.SFTPFile{}
@@ -469,7 +525,11 @@ func (ts *fileTestSuite) TestMoveToLocation() {
 	}
 
 	// set up target
+	targetFileInfo := &mocks.FileInfo{}
+	targetFileInfo.On("Size").Return(int64(contentLength))
+
 	targetClient := &mocks.Client{}
+	targetClient.On("Stat", mock.Anything).Return(targetFileInfo, nil).Once()
 
 	targetSftpFile := &mocks.SFTPFile{}
 	targetSftpFile.On("Write", mock.Anything).Return(contentLength, nil).Once()
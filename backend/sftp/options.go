@@ -26,8 +26,13 @@ type Options struct {
 	KnownHostsFile     string              `json:"knownHostsFile,omitempty"` // env var VFS_SFTP_KNOWN_HOSTS_FILE
 	KnownHostsString   string              `json:"knownHostsString,omitempty"`
 	KnownHostsCallback ssh.HostKeyCallback //env var VFS_SFTP_INSECURE_KNOWN_HOSTS
-	Retry              vfs.Retry
-	MaxRetries         int
+	// DisableMoveVerification skips checking that a file moved by copying (to another host or scheme) was copied in
+	// full before the source is deleted.
+	DisableMoveVerification bool `json:"disableMoveVerification,omitempty"`
+	// ConflictPolicy determines what CopyToLocation and MoveToLocation do when the target file already exists.
+	ConflictPolicy vfs.ConflictPolicy `json:"conflictPolicy,omitempty"`
+	Retry          vfs.Retry
+	MaxRetries     int
 }
 
 // Note that as of 1.12, OPENSSH private key format is not supported when encrypt (with passphrase).
@@ -0,0 +1,114 @@
+package vfs
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// BandwidthLimiter caps the rate at which bytes pass through the readers and writers it wraps and the copies it's
+// passed to with WithBandwidthLimiter, ie: so a bulk sync doesn't saturate a shared link.  A limiter shared by several
+// streams holds them to its rate together.  Up to a second's worth of bytes may pass at once after a pause.
+//
+// A nil *BandwidthLimiter doesn't limit anything.
+type BandwidthLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// NewBandwidthLimiter returns a BandwidthLimiter allowing bytesPerSecond bytes per second, or nil (no limit) if
+// bytesPerSecond isn't positive.
+func NewBandwidthLimiter(bytesPerSecond int64) *BandwidthLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	return &BandwidthLimiter{rate: float64(bytesPerSecond), tokens: float64(bytesPerSecond), last: time.Now()}
+}
+
+// WaitN blocks until n more bytes may pass, or ctx is done, in which case its error is returned.
+func (l *BandwidthLimiter) WaitN(ctx context.Context, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+	delay := l.reserve(n)
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reserve takes n bytes' worth of tokens, returning how long the caller must wait before using them.  Tokens accrue
+// at the limiter's rate up to a second's worth.
+func (l *BandwidthLimiter) reserve(n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// Reader returns a reader that reads from r no faster than the limiter allows, ie: to throttle reading a File.
+func (l *BandwidthLimiter) Reader(r io.Reader) io.Reader {
+	if l == nil {
+		return r
+	}
+	return &limitedReader{r: r, limiter: l}
+}
+
+// Writer returns a writer that writes to w no faster than the limiter allows, ie: to throttle writing a File.
+func (l *BandwidthLimiter) Writer(w io.Writer) io.Writer {
+	if l == nil {
+		return w
+	}
+	return &limitedWriter{w: w, limiter: l}
+}
+
+// limitedReader is the reader returned by BandwidthLimiter.Reader.
+type limitedReader struct {
+	r       io.Reader
+	limiter *BandwidthLimiter
+}
+
+// Read implements io.Reader, waiting for the bytes read to be allowed before returning.
+func (r *limitedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if waitErr := r.limiter.WaitN(context.Background(), n); waitErr != nil && err == nil {
+		err = waitErr
+	}
+	return n, err
+}
+
+// limitedWriter is the writer returned by BandwidthLimiter.Writer.
+type limitedWriter struct {
+	w       io.Writer
+	limiter *BandwidthLimiter
+}
+
+// Write implements io.Writer, waiting for the bytes to be allowed before writing them.
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if err := w.limiter.WaitN(context.Background(), len(p)); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
@@ -0,0 +1,68 @@
+package vfs_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+type bandwidthTestSuite struct {
+	suite.Suite
+}
+
+func (ts *bandwidthTestSuite) TestWaitN() {
+	limiter := vfs.NewBandwidthLimiter(10000)
+	start := time.Now()
+	ts.NoError(limiter.WaitN(context.Background(), 10000), "a second's worth of bytes pass at once")
+	ts.True(time.Since(start) < 50*time.Millisecond)
+
+	ts.NoError(limiter.WaitN(context.Background(), 2000))
+	ts.True(time.Since(start) >= 150*time.Millisecond, "2000 more bytes wait about 200ms")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ts.Equal(context.Canceled, limiter.WaitN(ctx, 10000))
+}
+
+func (ts *bandwidthTestSuite) TestNoLimit() {
+	var limiter *vfs.BandwidthLimiter
+	ts.Nil(vfs.NewBandwidthLimiter(0))
+	ts.NoError(limiter.WaitN(context.Background(), 1<<30))
+
+	r := strings.NewReader("contents")
+	ts.Equal(r, limiter.Reader(r), "nil limiter doesn't wrap")
+}
+
+func (ts *bandwidthTestSuite) TestReaderWriter() {
+	limiter := vfs.NewBandwidthLimiter(10000)
+	start := time.Now()
+
+	contents, err := ioutil.ReadAll(limiter.Reader(strings.NewReader(strings.Repeat("x", 10000))))
+	ts.NoError(err)
+	ts.Len(contents, 10000)
+
+	var buf bytes.Buffer
+	n, err := limiter.Writer(&buf).Write(make([]byte, 2000))
+	ts.NoError(err)
+	ts.Equal(2000, n)
+	ts.Equal(2000, buf.Len())
+	ts.True(time.Since(start) >= 150*time.Millisecond, "reader and writer share the limit")
+}
+
+func (ts *bandwidthTestSuite) TestCopyOption() {
+	limiter := vfs.NewBandwidthLimiter(100)
+	ts.Equal(limiter, vfs.NewCopyOptions(vfs.WithBandwidthLimiter(limiter)).BandwidthLimiter)
+	ts.NotNil(vfs.NewCopyOptions(vfs.WithBandwidthLimit(100)).BandwidthLimiter)
+	ts.Nil(vfs.NewCopyOptions().BandwidthLimiter)
+}
+
+func TestBandwidth(t *testing.T) {
+	suite.Run(t, new(bandwidthTestSuite))
+}
@@ -0,0 +1,27 @@
+/*
+Package casfs is a content-addressable store of blobs beneath a vfs.Location on any backend.  Each blob is stored once,
+named by the SHA-256 of its contents, so identical payloads are deduplicated and a blob's digest is a stable name for it
+wherever it's stored, ie: for build artifacts.
+
+Usage
+
+  location, err := s3.NewFileSystem().NewLocation("mybucket", "/artifacts/")
+  store := casfs.NewStore(location)
+
+  blob, err := store.Put(reader)
+  fmt.Println(blob.Digest)   // sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824
+
+  file, err := store.Get(blob.Digest)
+
+Layout
+
+Blobs are stored beneath the location under "sha256/", named by the hex digest of their contents, within a
+sub-location named by its first two characters:
+
+  sha256/2c/2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824
+
+Put writes each payload to "tmp/" while hashing it, then moves it into place, or deletes it if the blob is already
+stored.  Blobs are never modified once stored, so a blob's file can be read by any number of readers while others are
+being put.
+*/
+package casfs
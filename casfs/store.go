@@ -0,0 +1,196 @@
+package casfs
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+const (
+	// algorithm is the hash algorithm blobs are addressed by, which prefixes their digests.
+	algorithm = "sha256"
+	// blobsPath is the sub-location of the store's location that blobs are stored beneath.
+	blobsPath = algorithm + "/"
+	// tmpPath is the sub-location of the store's location that payloads are written to while they're hashed.
+	tmpPath = "tmp/"
+)
+
+// ErrNotFound is returned when a blob isn't in the store.
+var ErrNotFound = errors.New("blob not found")
+
+// Blob is a blob in a Store.
+type Blob struct {
+	// Digest is the blob's content address, "sha256:" followed by the hex SHA-256 of its contents.
+	Digest string
+	// Size is the size of the blob in bytes.
+	Size int64
+	// File is the file the blob is stored in.
+	File vfs.File
+}
+
+// URI returns the URI of the file the blob is stored in, which doesn't change while the blob is stored.
+func (b *Blob) URI() string {
+	return b.File.URI()
+}
+
+// Store is a content-addressable store of blobs beneath a location.
+type Store struct {
+	location vfs.Location
+}
+
+// NewStore returns a Store of the blobs beneath location.
+func NewStore(location vfs.Location) *Store {
+	return &Store{location: location}
+}
+
+// Put stores the contents read from r, unless a blob with the same contents is already stored, returning the blob.
+func (s *Store) Put(r io.Reader) (*Blob, error) {
+	tmp, err := s.tmpFile()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(tmp, io.TeeReader(r, hash))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size == 0 {
+		// nothing was written, which doesn't create the file on every backend, ie: mem and s3
+		err = tmp.Touch()
+	}
+	if err != nil {
+		_ = tmp.Delete()
+		return nil, err
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	file, err := s.blobFile(sum)
+	if err != nil {
+		_ = tmp.Delete()
+		return nil, err
+	}
+	exists, err := file.Exists()
+	if err != nil {
+		_ = tmp.Delete()
+		return nil, err
+	}
+	if exists {
+		err = tmp.Delete()
+	} else {
+		err = tmp.MoveToFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Blob{Digest: algorithm + ":" + sum, Size: size, File: file}, nil
+}
+
+// Get returns the blob with the given digest, or ErrNotFound if it isn't stored.
+func (s *Store) Get(digest string) (*Blob, error) {
+	file, err := s.file(digest)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := file.Exists()
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+	size, err := file.Size()
+	if err != nil {
+		return nil, err
+	}
+	return &Blob{Digest: digest, Size: int64(size), File: file}, nil
+}
+
+// Has returns true if the blob with the given digest is stored.
+func (s *Store) Has(digest string) (bool, error) {
+	file, err := s.file(digest)
+	if err != nil {
+		return false, err
+	}
+	return file.Exists()
+}
+
+// Delete deletes the blob with the given digest, or returns ErrNotFound if it isn't stored.  Since blobs are shared by
+// every Put of the same contents, callers must know that none of them still need it.
+func (s *Store) Delete(digest string) error {
+	file, err := s.file(digest)
+	if err != nil {
+		return err
+	}
+	exists, err := file.Exists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return file.Delete()
+}
+
+// Verify reads the blob with the given digest, returning an error if its contents no longer match the digest, ie:
+// because it was corrupted or modified outside of the store.
+func (s *Store) Verify(digest string) error {
+	blob, err := s.Get(digest)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	_, err = io.Copy(hash, blob.File)
+	if closeErr := blob.File.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if found := algorithm + ":" + hex.EncodeToString(hash.Sum(nil)); found != digest {
+		return fmt.Errorf("blob %s has digest %s", digest, found)
+	}
+	return nil
+}
+
+// file returns the file a blob with the given digest is stored in.
+func (s *Store) file(digest string) (vfs.File, error) {
+	sum, err := parseDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+	return s.blobFile(sum)
+}
+
+// blobFile returns the file a blob whose hex SHA-256 is sum is stored in.
+func (s *Store) blobFile(sum string) (vfs.File, error) {
+	return s.location.NewFile(blobsPath + sum[:2] + "/" + sum)
+}
+
+// tmpFile returns a new, uniquely named, file for a payload to be written to while it's hashed.
+func (s *Store) tmpFile() (vfs.File, error) {
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	return s.location.NewFile(fmt.Sprintf("%s%d-%s", tmpPath, time.Now().UnixNano(), hex.EncodeToString(random)))
+}
+
+// parseDigest returns the hex SHA-256 of a digest, or an error if it isn't a valid SHA-256 digest.
+func parseDigest(digest string) (string, error) {
+	sum := strings.TrimPrefix(digest, algorithm+":")
+	if sum == digest || len(sum) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid digest %q", digest)
+	}
+	if _, err := hex.DecodeString(sum); err != nil || strings.ToLower(sum) != sum {
+		return "", fmt.Errorf("invalid digest %q", digest)
+	}
+	return sum, nil
+}
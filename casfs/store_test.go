@@ -0,0 +1,149 @@
+package casfs_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/casfs"
+)
+
+// emptyDigest is the digest of an empty payload.
+const emptyDigest = "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// helloDigest is the digest of "hello".
+const helloDigest = "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+type storeTestSuite struct {
+	suite.Suite
+	dir      string
+	location vfs.Location
+	store    *casfs.Store
+}
+
+func (ts *storeTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "casfs_test")
+	ts.NoError(err)
+	ts.dir = dir
+	ts.location, err = _os.NewFileSystem().NewLocation("", dir+"/")
+	ts.NoError(err)
+	ts.store = casfs.NewStore(ts.location)
+}
+
+func (ts *storeTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *storeTestSuite) TestPutAndGet() {
+	blob, err := ts.store.Put(strings.NewReader("hello"))
+	ts.NoError(err)
+	ts.Equal(helloDigest, blob.Digest)
+	ts.Equal(int64(5), blob.Size)
+	ts.Equal(ts.location.URI()+"sha256/2c/"+strings.TrimPrefix(helloDigest, "sha256:"), blob.URI())
+
+	got, err := ts.store.Get(helloDigest)
+	ts.NoError(err)
+	ts.Equal(blob.URI(), got.URI())
+	ts.Equal(int64(5), got.Size)
+	contents, err := ioutil.ReadAll(got.File)
+	ts.NoError(err)
+	ts.Equal("hello", string(contents))
+	ts.NoError(got.File.Close())
+
+	tmp, err := ts.location.NewLocation("tmp/")
+	ts.NoError(err)
+	names, err := tmp.List()
+	ts.NoError(err)
+	ts.Empty(names, "payloads are moved out of tmp")
+}
+
+func (ts *storeTestSuite) TestPutEmpty() {
+	for _, location := range []vfs.Location{ts.location, ts.memLocation()} {
+		store := casfs.NewStore(location)
+		blob, err := store.Put(strings.NewReader(""))
+		ts.NoError(err, location.URI())
+		ts.Equal(emptyDigest, blob.Digest)
+		ts.Equal(int64(0), blob.Size)
+
+		has, err := store.Has(emptyDigest)
+		ts.NoError(err)
+		ts.True(has, "empty payloads are stored")
+	}
+}
+
+func (ts *storeTestSuite) memLocation() vfs.Location {
+	location, err := mem.NewFileSystem().NewLocation("store", "/")
+	ts.NoError(err)
+	return location
+}
+
+func (ts *storeTestSuite) TestDeduplicate() {
+	first, err := ts.store.Put(strings.NewReader("hello"))
+	ts.NoError(err)
+	lastModified, err := first.File.LastModified()
+	ts.NoError(err)
+
+	second, err := ts.store.Put(strings.NewReader("hello"))
+	ts.NoError(err)
+	ts.Equal(first.URI(), second.URI())
+	unchanged, err := second.File.LastModified()
+	ts.NoError(err)
+	ts.Equal(lastModified, unchanged, "stored blobs aren't rewritten")
+
+	other, err := ts.store.Put(strings.NewReader("world"))
+	ts.NoError(err)
+	ts.NotEqual(first.Digest, other.Digest)
+}
+
+func (ts *storeTestSuite) TestHasAndDelete() {
+	has, err := ts.store.Has(helloDigest)
+	ts.NoError(err)
+	ts.False(has)
+	_, err = ts.store.Get(helloDigest)
+	ts.Equal(casfs.ErrNotFound, err)
+	ts.Equal(casfs.ErrNotFound, ts.store.Delete(helloDigest))
+
+	_, err = ts.store.Put(strings.NewReader("hello"))
+	ts.NoError(err)
+	has, err = ts.store.Has(helloDigest)
+	ts.NoError(err)
+	ts.True(has)
+
+	ts.NoError(ts.store.Delete(helloDigest))
+	has, err = ts.store.Has(helloDigest)
+	ts.NoError(err)
+	ts.False(has)
+}
+
+func (ts *storeTestSuite) TestVerify() {
+	blob, err := ts.store.Put(strings.NewReader("hello"))
+	ts.NoError(err)
+	ts.NoError(ts.store.Verify(blob.Digest))
+
+	ts.NoError(ioutil.WriteFile(blob.File.Path(), []byte("corrupted"), 0644))
+	ts.Error(ts.store.Verify(blob.Digest))
+}
+
+func (ts *storeTestSuite) TestInvalidDigest() {
+	for _, digest := range []string{
+		"",
+		"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		"md5:5d41402abc4b2a76b9719d911017c592",
+		"sha256:2cf24d",
+		"sha256:2CF24DBA5FB0A30E26E83B2AC5B9E29E1B161E5C1FA7425E73043362938B9824",
+		"sha256:../../../../../../../../../../../../../../../../../../../../../../../etc/passwd",
+	} {
+		_, err := ts.store.Get(digest)
+		ts.EqualError(err, `invalid digest "`+digest+`"`, digest)
+	}
+}
+
+func TestStore(t *testing.T) {
+	suite.Run(t, new(storeTestSuite))
+}
@@ -0,0 +1,129 @@
+package vfs
+
+import (
+	"context"
+)
+
+// CopyOptions configures a copy or move made by CopyToFile, CopyToLocation, MoveToFile or MoveToLocation.  They're set
+// with CopyOption arguments, ie:
+//
+//   file.CopyToLocation(location, vfs.WithConflictPolicy(vfs.ConflictSkip), vfs.WithChecksumVerification())
+//
+// Backends read them with NewCopyOptions.
+type CopyOptions struct {
+	// Context cancels a copy still in progress, returning the context's error.  Server-side copies can only be canceled
+	// before they start.
+	Context context.Context
+	// BufferSize is the size of the buffer a file's contents are copied through when they aren't copied server-side.
+	// Zero uses a pooled 256KB buffer.
+	BufferSize int
+	// PreserveMetadata keeps the permissions and modification time of an os file copied to another os file.  It's only
+	// honored by the os backend; other backends ignore it, though server-side copies within s3 or gs always keep the
+	// object's metadata.
+	PreserveMetadata bool
+	// ConflictPolicy determines what CopyToLocation and MoveToLocation do when the target file already exists, in place
+	// of the ConflictPolicy in the backend's Options.  It's only used if set with WithConflictPolicy.
+	ConflictPolicy ConflictPolicy
+	// VerifyChecksum re-reads the source and target once copied, returning an error if their contents differ.
+	VerifyChecksum bool
+	// SkipMoveVerification skips checking, before deleting the source, that a file moved by copying it (ie: to another
+	// scheme) was copied in full.  It applies to every backend, alongside the DisableMoveVerification in their Options.
+	SkipMoveVerification bool
+	// Progress, if set, is called with the total number of bytes copied so far as a file's contents are copied.  It
+	// isn't called for server-side copies.
+	Progress func(copied int64)
+	// BandwidthLimiter, if set, caps the rate at which a file's contents are copied.  Server-side copies aren't
+	// limited.
+	BandwidthLimiter *BandwidthLimiter
+
+	conflictPolicySet bool
+}
+
+// CopyOption sets one of the CopyOptions.
+type CopyOption func(*CopyOptions)
+
+// NewCopyOptions returns the CopyOptions set by opts, with a Context of context.Background() unless set.
+func NewCopyOptions(opts ...CopyOption) CopyOptions {
+	var options CopyOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.Context == nil {
+		options.Context = context.Background()
+	}
+	return options
+}
+
+// ConflictPolicyOr returns the ConflictPolicy set with WithConflictPolicy, or policy if none was set, ie: the
+// ConflictPolicy in a backend's Options.
+func (o CopyOptions) ConflictPolicyOr(policy ConflictPolicy) ConflictPolicy {
+	if o.conflictPolicySet {
+		return o.ConflictPolicy
+	}
+	return policy
+}
+
+// WithContext sets the Context that cancels a copy.
+func WithContext(ctx context.Context) CopyOption {
+	return func(o *CopyOptions) {
+		o.Context = ctx
+	}
+}
+
+// WithBufferSize sets the size of the buffer a file's contents are copied through.
+func WithBufferSize(size int) CopyOption {
+	return func(o *CopyOptions) {
+		o.BufferSize = size
+	}
+}
+
+// WithPreserveMetadata keeps the source file's permissions and modification time when copying between os files.  See
+// CopyOptions.PreserveMetadata.
+func WithPreserveMetadata() CopyOption {
+	return func(o *CopyOptions) {
+		o.PreserveMetadata = true
+	}
+}
+
+// WithConflictPolicy sets what CopyToLocation and MoveToLocation do when the target file already exists.
+func WithConflictPolicy(policy ConflictPolicy) CopyOption {
+	return func(o *CopyOptions) {
+		o.ConflictPolicy = policy
+		o.conflictPolicySet = true
+	}
+}
+
+// WithChecksumVerification compares the contents of the source and target once copied.
+func WithChecksumVerification() CopyOption {
+	return func(o *CopyOptions) {
+		o.VerifyChecksum = true
+	}
+}
+
+// WithoutMoveVerification skips verifying a move's copy before its source is deleted, ie: when the target's backend
+// verifies writes itself.
+func WithoutMoveVerification() CopyOption {
+	return func(o *CopyOptions) {
+		o.SkipMoveVerification = true
+	}
+}
+
+// WithProgress sets a function called with the number of bytes copied so far.
+func WithProgress(progress func(copied int64)) CopyOption {
+	return func(o *CopyOptions) {
+		o.Progress = progress
+	}
+}
+
+// WithBandwidthLimit caps the rate at which a file's contents are copied at bytesPerSecond.  To hold several copies to
+// a rate together, share a BandwidthLimiter between them with WithBandwidthLimiter instead.
+func WithBandwidthLimit(bytesPerSecond int64) CopyOption {
+	return WithBandwidthLimiter(NewBandwidthLimiter(bytesPerSecond))
+}
+
+// WithBandwidthLimiter sets the BandwidthLimiter a file's contents are copied through.
+func WithBandwidthLimiter(limiter *BandwidthLimiter) CopyOption {
+	return func(o *CopyOptions) {
+		o.BandwidthLimiter = limiter
+	}
+}
@@ -0,0 +1,121 @@
+package vfs_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+)
+
+type copyTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (ts *copyTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "copy_test")
+	ts.NoError(err)
+	ts.dir = dir
+}
+
+func (ts *copyTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *copyTestSuite) newFile(name, contents string) vfs.File {
+	file, err := _os.NewFileSystem().NewFile("", filepath.Join(ts.dir, name))
+	ts.Require().NoError(err)
+	if contents != "" {
+		_, err = file.Write([]byte(contents))
+		ts.NoError(err)
+		ts.NoError(file.Close())
+	}
+	return file
+}
+
+func (ts *copyTestSuite) read(file vfs.File) string {
+	contents, err := ioutil.ReadFile(file.Path())
+	ts.NoError(err)
+	return string(contents)
+}
+
+func (ts *copyTestSuite) TestNewCopyOptions() {
+	options := vfs.NewCopyOptions()
+	ts.Equal(context.Background(), options.Context)
+	ts.Equal(vfs.ConflictSkip, options.ConflictPolicyOr(vfs.ConflictSkip), "no policy set")
+
+	options = vfs.NewCopyOptions(vfs.WithConflictPolicy(vfs.ConflictOverwrite), vfs.WithBufferSize(10),
+		vfs.WithPreserveMetadata(), vfs.WithChecksumVerification())
+	ts.Equal(vfs.ConflictOverwrite, options.ConflictPolicyOr(vfs.ConflictSkip), "policy set overrides the default")
+	ts.Equal(10, options.BufferSize)
+	ts.True(options.PreserveMetadata)
+	ts.True(options.VerifyChecksum)
+}
+
+func (ts *copyTestSuite) TestConflictPolicy() {
+	source := ts.newFile("src/file.txt", "new")
+	existing := ts.newFile("dst/file.txt", "old")
+
+	copied, err := source.CopyToLocation(existing.Location(), vfs.WithConflictPolicy(vfs.ConflictSkip))
+	ts.NoError(err)
+	ts.Equal(existing.Path(), copied.Path())
+	ts.Equal("old", ts.read(existing), "existing file is skipped")
+
+	_, err = source.CopyToLocation(existing.Location(), vfs.WithConflictPolicy(vfs.ConflictError))
+	ts.Equal(vfs.ErrFileExists, err)
+
+	copied, err = source.CopyToLocation(existing.Location())
+	ts.NoError(err)
+	ts.Equal("new", ts.read(copied), "overwritten by default")
+}
+
+func (ts *copyTestSuite) TestProgress() {
+	source := ts.newFile("src.txt", "0123456789")
+	var progress []int64
+	err := source.CopyToFile(ts.newFile("dst.txt", ""), vfs.WithBufferSize(4),
+		vfs.WithProgress(func(copied int64) { progress = append(progress, copied) }))
+	ts.NoError(err)
+	ts.Equal([]int64{4, 8, 10}, progress)
+}
+
+func (ts *copyTestSuite) TestBandwidthLimit() {
+	source := ts.newFile("src.txt", strings.Repeat("x", 1200))
+	start := time.Now()
+	ts.NoError(source.CopyToFile(ts.newFile("dst.txt", ""), vfs.WithBandwidthLimit(1000)))
+	ts.True(time.Since(start) >= 150*time.Millisecond, "1200 bytes at 1000 bytes/sec take about 200ms")
+}
+
+func (ts *copyTestSuite) TestContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	target := ts.newFile("dst.txt", "")
+	err := ts.newFile("src.txt", "contents").CopyToFile(target, vfs.WithContext(ctx))
+	ts.Equal(context.Canceled, err)
+	exists, err := target.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+}
+
+func (ts *copyTestSuite) TestPreserveMetadataAndVerify() {
+	source := ts.newFile("src.txt", "contents")
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	ts.NoError(os.Chtimes(source.Path(), modTime, modTime))
+
+	moved := ts.newFile("moved.txt", "")
+	ts.NoError(source.CopyToFile(moved, vfs.WithPreserveMetadata(), vfs.WithChecksumVerification()))
+	info, err := os.Stat(moved.Path())
+	ts.NoError(err)
+	ts.True(modTime.Equal(info.ModTime()), "modification time is preserved")
+}
+
+func TestCopy(t *testing.T) {
+	suite.Run(t, new(copyTestSuite))
+}
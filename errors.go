@@ -0,0 +1,103 @@
+package vfs
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+)
+
+// ErrorKind classifies the errors returned by backends, so callers can decide whether to retry, ie: a throttled or
+// timed out request, without knowing which backend, or SDK, returned the error.  See ClassifyError.
+type ErrorKind int
+
+// The kinds of error returned by ClassifyError.
+const (
+	// ErrorUnknown is any error not classified as one of the other kinds.
+	ErrorUnknown ErrorKind = iota
+	// ErrorNotFound is returned for a file, location or volume that doesn't exist.
+	ErrorNotFound
+	// ErrorPermission is returned when the credentials are invalid or don't allow the request.
+	ErrorPermission
+	// ErrorThrottled is returned when a backend rejects requests for exceeding its request rate, ie: s3's SlowDown.
+	ErrorThrottled
+	// ErrorTimeout is returned when a request or connection times out.
+	ErrorTimeout
+	// ErrorNetwork is returned when a backend can't be reached, ie: a refused connection or a failed DNS lookup.
+	ErrorNetwork
+	// ErrorCanceled is returned when a request's context is canceled.
+	ErrorCanceled
+)
+
+var errorKindNames = map[ErrorKind]string{
+	ErrorUnknown:    "unknown",
+	ErrorNotFound:   "not found",
+	ErrorPermission: "permission denied",
+	ErrorThrottled:  "throttled",
+	ErrorTimeout:    "timeout",
+	ErrorNetwork:    "network",
+	ErrorCanceled:   "canceled",
+}
+
+// String returns the name of the kind, ie: "not found".
+func (k ErrorKind) String() string {
+	if name, ok := errorKindNames[k]; ok {
+		return name
+	}
+	return errorKindNames[ErrorUnknown]
+}
+
+var (
+	errorClassifiersMu sync.RWMutex
+	errorClassifiers   []func(error) ErrorKind
+)
+
+// RegisterErrorClassifier adds a function that maps errors from a backend, ie: its SDK's error responses, onto an
+// ErrorKind for ClassifyError, returning ErrorUnknown for errors it doesn't recognize.  Backends register theirs when
+// they're imported, as they register themselves with backend.Register.
+func RegisterErrorClassifier(classify func(error) ErrorKind) {
+	errorClassifiersMu.Lock()
+	defer errorClassifiersMu.Unlock()
+	errorClassifiers = append(errorClassifiers, classify)
+}
+
+// ClassifyError returns the kind of err, an error returned by any backend.  Errors are classified by the classifiers
+// of the backends imported (see RegisterErrorClassifier) and otherwise as os, context and net errors, ie:
+// os.IsNotExist is ErrorNotFound and a net.Error that timed out is ErrorTimeout.  ErrorUnknown is returned for nil.
+func ClassifyError(err error) ErrorKind {
+	if err == nil {
+		return ErrorUnknown
+	}
+
+	errorClassifiersMu.RLock()
+	classifiers := errorClassifiers
+	errorClassifiersMu.RUnlock()
+	for _, classify := range classifiers {
+		if kind := classify(err); kind != ErrorUnknown {
+			return kind
+		}
+	}
+
+	switch {
+	case err == context.Canceled:
+		return ErrorCanceled
+	case err == context.DeadlineExceeded:
+		return ErrorTimeout
+	case os.IsNotExist(err):
+		return ErrorNotFound
+	case os.IsPermission(err):
+		return ErrorPermission
+	}
+	if netErr, ok := err.(net.Error); ok {
+		if netErr.Timeout() {
+			return ErrorTimeout
+		}
+		return ErrorNetwork
+	}
+	return ErrorUnknown
+}
+
+// IsNotFound returns true if err indicates that a file, location or volume doesn't exist.
+func IsNotFound(err error) bool {
+	return ClassifyError(err) == ErrorNotFound
+}
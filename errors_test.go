@@ -0,0 +1,69 @@
+package vfs_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+type errorsTestSuite struct {
+	suite.Suite
+}
+
+func (ts *errorsTestSuite) TestErrorKindString() {
+	ts.Equal("not found", vfs.ErrorNotFound.String())
+	ts.Equal("throttled", vfs.ErrorThrottled.String())
+	ts.Equal("unknown", vfs.ErrorKind(-1).String())
+}
+
+func (ts *errorsTestSuite) TestClassifyError() {
+	tests := []struct {
+		err  error
+		kind vfs.ErrorKind
+	}{
+		{nil, vfs.ErrorUnknown},
+		{errors.New("something failed"), vfs.ErrorUnknown},
+		{context.Canceled, vfs.ErrorCanceled},
+		{context.DeadlineExceeded, vfs.ErrorTimeout},
+		{&os.PathError{Op: "open", Path: "/tmp/missing.txt", Err: os.ErrNotExist}, vfs.ErrorNotFound},
+		{os.ErrPermission, vfs.ErrorPermission},
+		{&net.DNSError{Err: "no such host", Name: "example.invalid"}, vfs.ErrorNetwork},
+		{&net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true}, vfs.ErrorTimeout},
+	}
+	for _, test := range tests {
+		ts.Equal(test.kind, vfs.ClassifyError(test.err), "%v", test.err)
+	}
+}
+
+type throttledError struct{}
+
+func (throttledError) Error() string { return "slow down" }
+
+func (ts *errorsTestSuite) TestRegisterErrorClassifier() {
+	ts.Equal(vfs.ErrorUnknown, vfs.ClassifyError(throttledError{}))
+
+	vfs.RegisterErrorClassifier(func(err error) vfs.ErrorKind {
+		if _, ok := err.(throttledError); ok {
+			return vfs.ErrorThrottled
+		}
+		return vfs.ErrorUnknown
+	})
+	ts.Equal(vfs.ErrorThrottled, vfs.ClassifyError(throttledError{}))
+	ts.Equal(vfs.ErrorNotFound, vfs.ClassifyError(os.ErrNotExist), "unrecognized errors fall through")
+}
+
+func (ts *errorsTestSuite) TestIsNotFound() {
+	ts.True(vfs.IsNotFound(os.ErrNotExist))
+	ts.False(vfs.IsNotFound(os.ErrPermission))
+	ts.False(vfs.IsNotFound(nil))
+}
+
+func TestErrors(t *testing.T) {
+	suite.Run(t, new(errorsTestSuite))
+}
@@ -3,10 +3,13 @@ module github.com/c2fo/vfs/v5
 go 1.12
 
 require (
+	bazil.org/fuse v0.0.0-20200117225306-7b5117fecadc
 	cloud.google.com/go v0.34.0
-	github.com/aws/aws-sdk-go v1.19.10
+	github.com/aws/aws-sdk-go v1.19.21
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/fatih/color v1.7.0
+	github.com/go-git/go-billy/v5 v5.0.0
+	github.com/golang/protobuf v1.3.1
 	github.com/google/martian v2.1.0+incompatible // indirect
 	github.com/googleapis/gax-go v2.0.2+incompatible // indirect
 	github.com/kr/fs v0.1.0 // indirect
@@ -15,12 +18,14 @@ require (
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/pkg/errors v0.8.1
 	github.com/pkg/sftp v1.10.0
+	github.com/spf13/afero v1.2.2
 	github.com/stretchr/objx v0.1.1 // indirect
 	github.com/stretchr/testify v1.4.0
 	golang.org/x/crypto v0.0.0-20190308221718-c2843e01d9a2
 	golang.org/x/net v0.0.0-20190404232315-eb5bcb51f2a3
 	golang.org/x/oauth2 v0.0.0-20190517181255-950ef44c6e07 // indirect
+	golang.org/x/sys v0.0.0-20200302150141-5c8b2ff67527
 	google.golang.org/api v0.5.0
 	google.golang.org/genproto v0.0.0-20190516172635-bb713bdc0e52 // indirect
-	google.golang.org/grpc v1.20.1 // indirect
+	google.golang.org/grpc v1.20.1
 )
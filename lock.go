@@ -0,0 +1,37 @@
+package vfs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Lease is an exclusive lock on a file, obtained with Lock.  It's held until it's released or, on backends where
+// leases expire, its ttl passes without a Refresh, after which another holder may take it.
+type Lease interface {
+	// Refresh extends the lease to ttl from now.  ErrLocked is returned if the lease expired and another holder has
+	// since taken it.
+	Refresh(ttl time.Duration) error
+	// Release gives up the lease so another holder can take it.
+	Release() error
+}
+
+// Locker is implemented by files which can be locked by Lock, ie: s3.File and os.File.
+type Locker interface {
+	Lock(ttl time.Duration) (Lease, error)
+}
+
+// Lock takes an exclusive lease on file for ttl, so workers sharing the file can coordinate which of them processes it
+// without an external lock service.  Lock doesn't wait: ErrLocked is returned if another holder's lease hasn't expired,
+// and callers wanting to wait retry.  Locks are advisory; they only exclude other callers of Lock, not reads or writes
+// of the file.  An error is returned if file's backend doesn't support locking.
+func Lock(file File, ttl time.Duration) (Lease, error) {
+	if ttl <= 0 {
+		return nil, errors.New("positive ttl is required")
+	}
+	locker, ok := file.(Locker)
+	if !ok {
+		return nil, fmt.Errorf("locking isn't supported for %s", file)
+	}
+	return locker.Lock(ttl)
+}
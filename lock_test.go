@@ -0,0 +1,29 @@
+package vfs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/backend/mem"
+)
+
+type lockTestSuite struct {
+	suite.Suite
+}
+
+func (ts *lockTestSuite) TestLockUnsupported() {
+	file, err := mem.NewFileSystem().NewFile("bucket", "/file.txt")
+	ts.NoError(err)
+
+	_, err = vfs.Lock(file, 0)
+	ts.EqualError(err, "positive ttl is required")
+	_, err = vfs.Lock(file, time.Minute)
+	ts.EqualError(err, "locking isn't supported for mem://bucket/file.txt")
+}
+
+func TestLock(t *testing.T) {
+	suite.Run(t, new(lockTestSuite))
+}
@@ -25,13 +25,20 @@ func (_m *File) Close() error {
 	return r0
 }
 
-// CopyToFile provides a mock function with given fields: file
-func (_m *File) CopyToFile(file vfs.File) error {
-	ret := _m.Called(file)
+// CopyToFile provides a mock function with given fields: file, opts
+func (_m *File) CopyToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, file)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(vfs.File) error); ok {
-		r0 = rf(file)
+	if rf, ok := ret.Get(0).(func(vfs.File, ...vfs.CopyOption) error); ok {
+		r0 = rf(file, opts...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -39,13 +46,20 @@ func (_m *File) CopyToFile(file vfs.File) error {
 	return r0
 }
 
-// CopyToLocation provides a mock function with given fields: location
-func (_m *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
-	ret := _m.Called(location)
+// CopyToLocation provides a mock function with given fields: location, opts
+func (_m *File) CopyToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, location)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 vfs.File
-	if rf, ok := ret.Get(0).(func(vfs.Location) vfs.File); ok {
-		r0 = rf(location)
+	if rf, ok := ret.Get(0).(func(vfs.Location, ...vfs.CopyOption) vfs.File); ok {
+		r0 = rf(location, opts...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(vfs.File)
@@ -53,8 +67,8 @@ func (_m *File) CopyToLocation(location vfs.Location) (vfs.File, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(vfs.Location) error); ok {
-		r1 = rf(location)
+	if rf, ok := ret.Get(1).(func(vfs.Location, ...vfs.CopyOption) error); ok {
+		r1 = rf(location, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -136,13 +150,20 @@ func (_m *File) Location() vfs.Location {
 	return r0
 }
 
-// MoveToFile provides a mock function with given fields: file
-func (_m *File) MoveToFile(file vfs.File) error {
-	ret := _m.Called(file)
+// MoveToFile provides a mock function with given fields: file, opts
+func (_m *File) MoveToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, file)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(vfs.File) error); ok {
-		r0 = rf(file)
+	if rf, ok := ret.Get(0).(func(vfs.File, ...vfs.CopyOption) error); ok {
+		r0 = rf(file, opts...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -150,13 +171,20 @@ func (_m *File) MoveToFile(file vfs.File) error {
 	return r0
 }
 
-// MoveToLocation provides a mock function with given fields: location
-func (_m *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
-	ret := _m.Called(location)
+// MoveToLocation provides a mock function with given fields: location, opts
+func (_m *File) MoveToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, location)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 vfs.File
-	if rf, ok := ret.Get(0).(func(vfs.Location) vfs.File); ok {
-		r0 = rf(location)
+	if rf, ok := ret.Get(0).(func(vfs.Location, ...vfs.CopyOption) vfs.File); ok {
+		r0 = rf(location, opts...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(vfs.File)
@@ -164,8 +192,8 @@ func (_m *File) MoveToLocation(location vfs.Location) (vfs.File, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(vfs.Location) error); ok {
-		r1 = rf(location)
+	if rf, ok := ret.Get(1).(func(vfs.Location, ...vfs.CopyOption) error); ok {
+		r1 = rf(location, opts...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,39 @@
+package vfs
+
+import (
+	"fmt"
+)
+
+// ProcessError is returned by Process when processing a file failed, after the file was moved to the failed location.
+type ProcessError struct {
+	// File is the file at the failed location.
+	File File
+	// Err is the error processing failed with.
+	Err error
+}
+
+// Error implements error.
+func (e *ProcessError) Error() string {
+	return fmt.Sprintf("processing failed, moved to %s: %s", e.File, e.Err.Error())
+}
+
+// Process calls fn with file, then moves the file to done if fn succeeds or to failed (a quarantine, or dead-letter,
+// location) if it returns an error, ie: for ingesting files dropped at a location.  The file is closed before it's
+// moved, so fn needn't close it.  The moved file is returned; when fn fails it's returned with a *ProcessError wrapping
+// fn's error.  Any other error means the file couldn't be moved and is still where it was.
+func Process(file File, done, failed Location, fn func(File) error) (File, error) {
+	err := fn(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		return file.MoveToLocation(done)
+	}
+	moved, moveErr := file.MoveToLocation(failed)
+	if moveErr != nil {
+		return nil, fmt.Errorf("unable to move %s to %s after processing failed (%s): %s", file, failed, err.Error(),
+			moveErr.Error())
+	}
+	return moved, &ProcessError{File: moved, Err: err}
+}
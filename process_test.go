@@ -0,0 +1,98 @@
+package vfs_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+)
+
+type processTestSuite struct {
+	suite.Suite
+	dir    string
+	done   vfs.Location
+	failed vfs.Location
+}
+
+func (ts *processTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "process_test")
+	ts.NoError(err)
+	ts.dir = dir
+
+	fs := _os.NewFileSystem()
+	ts.done, err = fs.NewLocation("", dir+"/done/")
+	ts.NoError(err)
+	ts.failed, err = fs.NewLocation("", dir+"/failed/")
+	ts.NoError(err)
+}
+
+func (ts *processTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *processTestSuite) newFile(contents string) vfs.File {
+	file, err := _os.NewFileSystem().NewFile("", ts.dir+"/inbox/feed.csv")
+	ts.NoError(err)
+	_, err = file.Write([]byte(contents))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+	return file
+}
+
+func (ts *processTestSuite) exists(file vfs.File) bool {
+	exists, err := file.Exists()
+	ts.NoError(err)
+	return exists
+}
+
+func (ts *processTestSuite) TestDone() {
+	file := ts.newFile("a,b\n")
+	var read string
+	moved, err := vfs.Process(file, ts.done, ts.failed, func(f vfs.File) error {
+		data, err := ioutil.ReadAll(f)
+		read = string(data)
+		return err
+	})
+	ts.NoError(err)
+	ts.Equal("a,b\n", read)
+	ts.Equal(ts.done.Path()+"feed.csv", moved.Path())
+	ts.True(ts.exists(moved))
+	ts.False(ts.exists(file))
+}
+
+func (ts *processTestSuite) TestFailed() {
+	file := ts.newFile("a,b\n")
+	moved, err := vfs.Process(file, ts.done, ts.failed, func(f vfs.File) error {
+		_, _ = f.Read(make([]byte, 1))
+		return errors.New("bad row")
+	})
+	ts.Error(err)
+	processErr, ok := err.(*vfs.ProcessError)
+	ts.True(ok, "processing errors are returned as a *ProcessError")
+	ts.EqualError(processErr.Err, "bad row")
+	ts.Equal(moved, processErr.File)
+	ts.Equal(ts.failed.Path()+"feed.csv", moved.Path())
+	ts.True(ts.exists(moved))
+	ts.False(ts.exists(file))
+}
+
+func (ts *processTestSuite) TestMoveError() {
+	file, err := _os.NewFileSystem().NewFile("", ts.dir+"/inbox/missing.csv")
+	ts.NoError(err)
+	moved, err := vfs.Process(file, ts.done, ts.failed, func(f vfs.File) error {
+		return nil
+	})
+	ts.Error(err)
+	_, ok := err.(*vfs.ProcessError)
+	ts.False(ok, "a failed move isn't a *ProcessError")
+	ts.Nil(moved)
+}
+
+func TestProcess(t *testing.T) {
+	suite.Run(t, new(processTestSuite))
+}
@@ -0,0 +1,43 @@
+/*
+Package testutil helps integration test code built on vfs against an S3-compatible server, such as MinIO or
+LocalStack, provisioning buckets, seeding them with fixtures and tearing them down again.
+
+Usage
+
+Connect to a server named by the VFS_TEST_S3_ENDPOINT environment variable, skipping the test if it isn't set:
+
+  func TestIngest(t *testing.T) {
+      server, err := testutil.S3FromEnv()
+      if err == testutil.ErrNoS3Endpoint {
+          t.Skip(err)
+      }
+      if err != nil {
+          t.Fatal(err)
+      }
+      defer server.Close()
+
+      bucket, err := server.NewBucket()
+      if err != nil {
+          t.Fatal(err)
+      }
+      err = testutil.Seed(bucket, map[string]string{
+          "inbox/feed.csv": "a,b\n",
+      })
+      ...
+  }
+
+Or start a throwaway MinIO server with docker using StartMinIO.  Close deletes the buckets created with NewBucket,
+along with their contents, and stops any server StartMinIO started.
+
+With go 1.16 or later, SeedFS seeds a location from any fs.FS, ie: a testing/fstest.MapFS or embedded fixtures.
+
+Configuration
+
+S3FromEnv reads the following environment variables:
+
+  VFS_TEST_S3_ENDPOINT           the server's endpoint, ie: http://localhost:9000
+  VFS_TEST_S3_REGION             defaults to us-east-1
+  VFS_TEST_S3_ACCESS_KEY_ID      defaults to "test", which LocalStack accepts
+  VFS_TEST_S3_SECRET_ACCESS_KEY  defaults to "test"
+*/
+package testutil
@@ -0,0 +1,211 @@
+package testutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/c2fo/vfs/v5"
+	vfss3 "github.com/c2fo/vfs/v5/backend/s3"
+)
+
+// Environment variables read by S3FromEnv.
+const (
+	S3EndpointEnv        = "VFS_TEST_S3_ENDPOINT"
+	S3RegionEnv          = "VFS_TEST_S3_REGION"
+	S3AccessKeyIDEnv     = "VFS_TEST_S3_ACCESS_KEY_ID"
+	S3SecretAccessKeyEnv = "VFS_TEST_S3_SECRET_ACCESS_KEY"
+)
+
+// MinIOImage is the docker image StartMinIO runs.
+const MinIOImage = "minio/minio"
+
+const (
+	defaultRegion     = "us-east-1"
+	defaultCredential = "test"
+	// startTimeout is how long StartMinIO waits for the server to accept requests.
+	startTimeout = 30 * time.Second
+)
+
+// ErrNoS3Endpoint is returned by S3FromEnv when VFS_TEST_S3_ENDPOINT isn't set, ie: to skip integration tests.
+var ErrNoS3Endpoint = errors.New(S3EndpointEnv + " is not set")
+
+// S3Server is a connection to an S3-compatible server for integration tests.  Close it once the tests are done.
+type S3Server struct {
+	// Endpoint is the server's endpoint, ie: http://127.0.0.1:9000.
+	Endpoint string
+	// Options are the s3.Options used to connect to the server, for creating file systems of your own.
+	Options vfss3.Options
+
+	fs        *vfss3.FileSystem
+	container string
+	mu        sync.Mutex
+	buckets   []string
+}
+
+// ConnectS3 returns an S3Server for the server at endpoint, checking that it can be reached.
+func ConnectS3(endpoint, region, accessKeyID, secretAccessKey string) (*S3Server, error) {
+	options := vfss3.Options{
+		Endpoint:        endpoint,
+		Region:          region,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		ForcePathStyle:  true,
+	}
+	server := &S3Server{
+		Endpoint: endpoint,
+		Options:  options,
+		fs:       vfss3.NewFileSystem().WithOptions(options),
+	}
+	if _, err := server.fs.ListBuckets(); err != nil {
+		return nil, fmt.Errorf("unable to connect to s3 at %s: %s", endpoint, err.Error())
+	}
+	return server, nil
+}
+
+// S3FromEnv returns an S3Server for the server configured by environment variables, see the package documentation.
+// ErrNoS3Endpoint is returned if VFS_TEST_S3_ENDPOINT isn't set.
+func S3FromEnv() (*S3Server, error) {
+	endpoint := os.Getenv(S3EndpointEnv)
+	if endpoint == "" {
+		return nil, ErrNoS3Endpoint
+	}
+	return ConnectS3(endpoint, getenv(S3RegionEnv, defaultRegion), getenv(S3AccessKeyIDEnv, defaultCredential),
+		getenv(S3SecretAccessKeyEnv, defaultCredential))
+}
+
+// StartMinIO starts a MinIO server in a docker container, listening on a free port of 127.0.0.1, and returns an
+// S3Server for it once it's accepting requests.  Close stops and removes the container.  Docker must be installed.
+func StartMinIO() (*S3Server, error) {
+	id, err := docker("run", "--detach", "--rm", "--publish", "127.0.0.1::9000",
+		"--env", "MINIO_ROOT_USER="+defaultCredential+"-user",
+		"--env", "MINIO_ROOT_PASSWORD="+defaultCredential+"-password",
+		MinIOImage, "server", "/data")
+	if err != nil {
+		return nil, err
+	}
+	stop := func() { _, _ = docker("rm", "--force", id) }
+
+	address, err := docker("port", id, "9000/tcp")
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	// docker may list an address per IP version
+	endpoint := "http://" + strings.Split(address, "\n")[0]
+
+	deadline := time.Now().Add(startTimeout)
+	for {
+		server, err := ConnectS3(endpoint, defaultRegion, defaultCredential+"-user", defaultCredential+"-password")
+		if err == nil {
+			server.container = id
+			return server, nil
+		}
+		if time.Now().After(deadline) {
+			stop()
+			return nil, fmt.Errorf("minio didn't start within %s: %s", startTimeout, err.Error())
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// FileSystem returns the s3.FileSystem connected to the server.
+func (s *S3Server) FileSystem() *vfss3.FileSystem {
+	return s.fs
+}
+
+// NewBucket creates a bucket with a unique name and returns its root location.  Close deletes it, with its contents.
+func (s *S3Server) NewBucket() (vfs.Location, error) {
+	bucket := fmt.Sprintf("vfs-test-%d", time.Now().UnixNano())
+	if err := s.fs.CreateBucket(bucket); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.buckets = append(s.buckets, bucket)
+	s.mu.Unlock()
+	return s.fs.NewLocation(bucket, "/")
+}
+
+// Close deletes the buckets created with NewBucket and their contents and, for a server started by StartMinIO, stops
+// it.  The first error is returned, after trying to delete every bucket.
+func (s *S3Server) Close() error {
+	s.mu.Lock()
+	buckets := s.buckets
+	s.buckets = nil
+	s.mu.Unlock()
+
+	var firstErr error
+	for _, bucket := range buckets {
+		err := s.emptyBucket(bucket)
+		if err == nil {
+			err = s.fs.DeleteBucket(bucket)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("unable to delete bucket %s: %s", bucket, err.Error())
+		}
+	}
+
+	if s.container != "" {
+		if _, err := docker("rm", "--force", s.container); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		s.container = ""
+	}
+	return firstErr
+}
+
+// emptyBucket deletes every object in bucket.
+func (s *S3Server) emptyBucket(bucket string) error {
+	client, err := s.fs.Client()
+	if err != nil {
+		return err
+	}
+
+	var deleteErr error
+	err = client.ListObjectsV2Pages(new(s3.ListObjectsV2Input).SetBucket(bucket),
+		func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+			if len(page.Contents) == 0 {
+				return true
+			}
+			objects := make([]*s3.ObjectIdentifier, 0, len(page.Contents))
+			for _, object := range page.Contents {
+				objects = append(objects, new(s3.ObjectIdentifier).SetKey(aws.StringValue(object.Key)))
+			}
+			_, deleteErr = client.DeleteObjects(new(s3.DeleteObjectsInput).
+				SetBucket(bucket).
+				SetDelete(new(s3.Delete).SetObjects(objects).SetQuiet(true)))
+			return deleteErr == nil
+		})
+	if err != nil {
+		return err
+	}
+	return deleteErr
+}
+
+// docker runs the docker command with args, returning its trimmed output.
+func docker(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("docker", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("docker %s failed: %s: %s", args[0], err.Error(), strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// getenv returns the value of the environment variable key, or fallback if it isn't set.
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,58 @@
+package testutil_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/testutil"
+)
+
+type s3TestSuite struct {
+	suite.Suite
+}
+
+func (ts *s3TestSuite) TestS3FromEnvNotSet() {
+	endpoint, set := os.LookupEnv(testutil.S3EndpointEnv)
+	ts.NoError(os.Unsetenv(testutil.S3EndpointEnv))
+	defer func() {
+		if set {
+			_ = os.Setenv(testutil.S3EndpointEnv, endpoint)
+		}
+	}()
+
+	_, err := testutil.S3FromEnv()
+	ts.Equal(testutil.ErrNoS3Endpoint, err)
+}
+
+func (ts *s3TestSuite) TestConnectS3Error() {
+	_, err := testutil.ConnectS3("http://127.0.0.1:1", "us-east-1", "test", "test")
+	ts.Error(err, "the server is checked on connecting")
+}
+
+// TestBucket runs against the server named by VFS_TEST_S3_ENDPOINT, if set.
+func (ts *s3TestSuite) TestBucket() {
+	server, err := testutil.S3FromEnv()
+	if err == testutil.ErrNoS3Endpoint {
+		ts.T().Skip(err)
+	}
+	ts.Require().NoError(err)
+
+	bucket, err := server.NewBucket()
+	ts.Require().NoError(err)
+	ts.NoError(testutil.Seed(bucket, map[string]string{"a.txt": "a", "inbox/b.txt": "b"}))
+
+	names, err := bucket.List()
+	ts.NoError(err)
+	ts.Equal([]string{"a.txt"}, names)
+
+	ts.NoError(server.Close())
+	exists, err := server.FileSystem().BucketExists(bucket.Volume())
+	ts.NoError(err)
+	ts.False(exists, "Close deletes the bucket and its contents")
+}
+
+func TestS3(t *testing.T) {
+	suite.Run(t, new(s3TestSuite))
+}
@@ -0,0 +1,36 @@
+package testutil
+
+import (
+	"sort"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// Seed writes files, keyed by their path relative to location, ie: "inbox/feed.csv", to location.
+func Seed(location vfs.Location, files map[string]string) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := writeFile(location, name, []byte(files[name])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile writes data to the file at relFilePath in location.
+func writeFile(location vfs.Location, relFilePath string, data []byte) error {
+	file, err := location.NewFile(relFilePath)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,24 @@
+// +build go1.16
+
+package testutil
+
+import (
+	"io/fs"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// SeedFS copies every file in fsys to location, keeping their paths, ie: to seed a bucket from a testing/fstest.MapFS
+// or from fixtures embedded with go:embed.
+func SeedFS(location vfs.Location, fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		return writeFile(location, name, data)
+	})
+}
@@ -0,0 +1,20 @@
+// +build go1.16
+
+package testutil_test
+
+import (
+	"io/fs"
+	"testing/fstest"
+
+	"github.com/c2fo/vfs/v5/testutil"
+)
+
+func (ts *seedTestSuite) TestSeedFS() {
+	ts.NoError(testutil.SeedFS(ts.location, fstest.MapFS{
+		"feed.csv":          {Data: []byte("a,b\n")},
+		"inbox/2020/01.csv": {Data: []byte("c,d\n")},
+		"inbox/empty":       {Mode: fs.ModeDir | 0755},
+	}))
+	ts.Equal("a,b\n", ts.contents("feed.csv"))
+	ts.Equal("c,d\n", ts.contents("inbox/2020/01.csv"))
+}
@@ -0,0 +1,53 @@
+package testutil_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/testutil"
+)
+
+type seedTestSuite struct {
+	suite.Suite
+	dir      string
+	location vfs.Location
+}
+
+func (ts *seedTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "seed_test")
+	ts.NoError(err)
+	ts.dir = dir
+	ts.location, err = _os.NewFileSystem().NewLocation("", dir+"/")
+	ts.NoError(err)
+}
+
+func (ts *seedTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *seedTestSuite) contents(name string) string {
+	data, err := ioutil.ReadFile(filepath.Join(ts.dir, name))
+	ts.NoError(err)
+	return string(data)
+}
+
+func (ts *seedTestSuite) TestSeed() {
+	ts.NoError(testutil.Seed(ts.location, map[string]string{
+		"feed.csv":          "a,b\n",
+		"inbox/2020/01.csv": "c,d\n",
+		"empty.txt":         "",
+	}))
+	ts.Equal("a,b\n", ts.contents("feed.csv"))
+	ts.Equal("c,d\n", ts.contents("inbox/2020/01.csv"))
+	ts.Equal("", ts.contents("empty.txt"))
+}
+
+func TestSeed(t *testing.T) {
+	suite.Run(t, new(seedTestSuite))
+}
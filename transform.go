@@ -0,0 +1,64 @@
+package vfs
+
+import (
+	"io"
+)
+
+// CopyWithTransform copies the contents of src to dst through transform, ie: to compress, decompress or re-encode
+// them, without a temp file.  transform is passed a reader of src and returns a reader of the transformed contents; if
+// it's an io.Closer, it's closed once the copy is done.  A nil transform copies the contents unchanged.  Both files
+// are closed, as with CopyToFile, and the number of bytes written to dst is returned.  As vfs has no way to abandon a
+// write, dst is closed even if the copy fails, so it may be left with partial contents.
+//
+// Transforms that write their output, such as gzip.NewWriter, can be used with WriterTransform:
+//
+//	_, err := vfs.CopyWithTransform(dst, src, vfs.WriterTransform(func(w io.Writer) io.WriteCloser {
+//	    return gzip.NewWriter(w)
+//	}))
+func CopyWithTransform(dst, src File, transform func(io.Reader) io.Reader) (int64, error) {
+	reader := io.Reader(src)
+	if transform != nil {
+		reader = transform(src)
+	}
+
+	written, err := copyTransformed(dst, reader)
+	if closer, ok := reader.(io.Closer); ok && reader != io.Reader(src) {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if closeErr := src.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = dst.Close()
+		return written, err
+	}
+	return written, dst.Close()
+}
+
+// copyTransformed writes everything read from reader to dst, creating dst even if there's nothing to write.
+func copyTransformed(dst File, reader io.Reader) (int64, error) {
+	if _, err := dst.Write([]byte{}); err != nil {
+		return 0, err
+	}
+	return io.Copy(dst, reader)
+}
+
+// WriterTransform returns a transform for CopyWithTransform from a writer-based one, such as gzip.NewWriter or
+// base64.NewEncoder, which writes its output to the io.Writer it's given.  The output is piped from a goroutine, which
+// closes the returned io.WriteCloser, flushing it, once its input has been written.
+func WriterTransform(newWriter func(w io.Writer) io.WriteCloser) func(io.Reader) io.Reader {
+	return func(r io.Reader) io.Reader {
+		pipeReader, pipeWriter := io.Pipe()
+		go func() {
+			w := newWriter(pipeWriter)
+			_, err := io.Copy(w, r)
+			if closeErr := w.Close(); err == nil {
+				err = closeErr
+			}
+			_ = pipeWriter.CloseWithError(err)
+		}()
+		return pipeReader
+	}
+}
@@ -0,0 +1,136 @@
+package vfs_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+)
+
+type transformTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (ts *transformTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "transform_test")
+	ts.NoError(err)
+	ts.dir = dir
+}
+
+func (ts *transformTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *transformTestSuite) newFile(name, contents string) vfs.File {
+	file, err := _os.NewFileSystem().NewFile("", ts.dir+"/"+name)
+	ts.NoError(err)
+	if contents != "" {
+		_, err = file.Write([]byte(contents))
+		ts.NoError(err)
+		ts.NoError(file.Close())
+	}
+	return file
+}
+
+func (ts *transformTestSuite) readFile(name string) []byte {
+	data, err := ioutil.ReadFile(ts.dir + "/" + name)
+	ts.NoError(err)
+	return data
+}
+
+func (ts *transformTestSuite) TestCompressAndDecompress() {
+	contents := strings.Repeat("a line of text\n", 1000)
+	src := ts.newFile("data.txt", contents)
+
+	compressed := ts.newFile("data.txt.gz", "")
+	written, err := vfs.CopyWithTransform(compressed, src, vfs.WriterTransform(func(w io.Writer) io.WriteCloser {
+		return gzip.NewWriter(w)
+	}))
+	ts.NoError(err)
+	ts.Equal(int64(len(ts.readFile("data.txt.gz"))), written)
+	ts.True(written < int64(len(contents)))
+
+	decompressed := ts.newFile("copy.txt", "")
+	var transformErr error
+	written, err = vfs.CopyWithTransform(decompressed, compressed, func(r io.Reader) io.Reader {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			transformErr = err
+			return r
+		}
+		return gz
+	})
+	ts.NoError(transformErr)
+	ts.NoError(err)
+	ts.Equal(int64(len(contents)), written)
+	ts.Equal(contents, string(ts.readFile("copy.txt")))
+}
+
+func (ts *transformTestSuite) TestNilTransform() {
+	src := ts.newFile("src.txt", "contents")
+	dst := ts.newFile("dst.txt", "")
+	written, err := vfs.CopyWithTransform(dst, src, nil)
+	ts.NoError(err)
+	ts.Equal(int64(8), written)
+	ts.Equal("contents", string(ts.readFile("dst.txt")))
+}
+
+func (ts *transformTestSuite) TestEmpty() {
+	src := ts.newFile("empty.txt", "")
+	ts.NoError(src.Touch())
+	dst := ts.newFile("encoded.txt", "")
+	_, err := vfs.CopyWithTransform(dst, src, vfs.WriterTransform(func(w io.Writer) io.WriteCloser {
+		return base64.NewEncoder(base64.StdEncoding, w)
+	}))
+	ts.NoError(err)
+	ts.Equal([]byte{}, ts.readFile("encoded.txt"), "dst is created even with no output")
+}
+
+func (ts *transformTestSuite) TestTransformError() {
+	src := ts.newFile("src.txt", "contents")
+	dst := ts.newFile("dst.txt", "")
+	_, err := vfs.CopyWithTransform(dst, src, vfs.WriterTransform(func(w io.Writer) io.WriteCloser {
+		return failingWriter{}
+	}))
+	ts.EqualError(err, "transform failed")
+
+	_, err = vfs.CopyWithTransform(dst, ts.newFile("missing.txt", ""), nil)
+	ts.Error(err)
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("transform failed") }
+func (failingWriter) Close() error                { return nil }
+
+func (ts *transformTestSuite) TestWriterTransformStopsWhenClosed() {
+	transform := vfs.WriterTransform(func(w io.Writer) io.WriteCloser {
+		return nopWriteCloser{w}
+	})
+	reader := transform(bytes.NewReader(make([]byte, 1024*1024)))
+	buf := make([]byte, 10)
+	_, err := io.ReadFull(reader, buf)
+	ts.NoError(err)
+	// closing the reader early ends the goroutine writing to it
+	ts.NoError(reader.(io.Closer).Close())
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestTransform(t *testing.T) {
+	suite.Run(t, new(transformTestSuite))
+}
@@ -0,0 +1,107 @@
+package vfs
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// URI is a parsed vfs URI, ie: s3://bucket/path/to/file.txt, the form returned by File.URI and Location.URI.
+type URI struct {
+	// Scheme identifies the FileSystem, ie: s3.  It's always lower case.
+	Scheme string
+	// Volume is the URI's authority: a bucket name, or [userinfo@]host[:port].  Userinfo is kept percent-encoded, as
+	// it appears in the URI, since sftp parses it from the volume.
+	Volume string
+	// Path is the absolute, decoded path of the file or location, ie: "/path/to/my file.txt".  Location paths end in
+	// a slash.
+	Path string
+}
+
+// ParseURI parses uri, decoding the percent-encoding of its volume and path.  Since vfs URIs have no query or
+// fragment, an unencoded "?" or "#" is taken to be part of the path, so s3://bucket/report#1.csv is the file
+// "/report#1.csv".  A URI without a path is the root location of its volume, "/".
+func ParseURI(uri string) (URI, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return URI{}, err
+	}
+	if u.Scheme == "" {
+		return URI{}, fmt.Errorf("uri %q has no scheme", uri)
+	}
+	if u.Opaque != "" {
+		return URI{}, fmt.Errorf("uri %q must be of the form scheme://volume/path", uri)
+	}
+
+	volume := u.Host
+	if u.User != nil {
+		volume = u.User.String() + "@" + volume
+	}
+
+	p := u.Path
+	if u.ForceQuery || u.RawQuery != "" {
+		query, err := url.PathUnescape(u.RawQuery)
+		if err != nil {
+			return URI{}, err
+		}
+		p += "?" + query
+	}
+	if u.Fragment != "" {
+		p += "#" + u.Fragment
+	}
+	if p == "" {
+		p = "/"
+	}
+	return URI{Scheme: u.Scheme, Volume: volume, Path: p}, nil
+}
+
+// BuildURI returns the URI of the file or location at path on volume of the FileSystem with scheme, percent-encoding
+// what needs it so the URI parses back to the same parts with ParseURI.  Spaces, "+", "%", "?", "#" and non-ASCII
+// characters in path are encoded, ie:
+//
+//   vfs.BuildURI("s3", "bucket", "/my report+1.csv")   // s3://bucket/my%20report%2B1.csv
+func BuildURI(scheme, volume, path string) string {
+	host := volume
+	var userinfo string
+	if i := strings.LastIndex(volume, "@"); i >= 0 {
+		userinfo, host = volume[:i+1], volume[i+1:]
+	}
+	return strings.ToLower(scheme) + "://" + userinfo + escape(host, isHostByte) + escape(path, isPathByte)
+}
+
+// String returns the URI built from its parts, see BuildURI.
+func (u URI) String() string {
+	return BuildURI(u.Scheme, u.Volume, u.Path)
+}
+
+// escape percent-encodes the bytes of s for which allowed returns false.
+func escape(s string, allowed func(c byte) bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if allowed(c) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// isUnreserved returns true for the characters that are never encoded, per RFC 3986.
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// isPathByte returns true for the characters left unencoded in a path.  "+" is encoded, although RFC 3986 allows it,
+// since it's commonly decoded as a space.
+func isPathByte(c byte) bool {
+	return isUnreserved(c) || strings.IndexByte("/!$&'()*,;=:@", c) >= 0
+}
+
+// isHostByte returns true for the characters left unencoded in a host, including the brackets of IPv6 addresses and
+// the colon of a port.
+func isHostByte(c byte) bool {
+	return isUnreserved(c) || strings.IndexByte("!$&'()*+,;=:[]", c) >= 0
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// defaultBatchConcurrency is the number of operations run in parallel when a batch's concurrency isn't set.
+const defaultBatchConcurrency = 4
+
+// BatchOp is a single operation of a batch: Source is copied or moved to Target, or deleted.  Target isn't used by
+// BatchDelete.
+type BatchOp struct {
+	Source vfs.File
+	Target vfs.File
+}
+
+// BatchResult is the outcome of a single BatchOp.
+type BatchResult struct {
+	Op BatchOp
+	// Err is the error the operation failed with, nil if it succeeded.
+	Err error
+	// Bytes is the size of Source, as found before the operation; 0 if it couldn't be found.
+	Bytes uint64
+}
+
+// OK returns true if the operation succeeded.
+func (r BatchResult) OK() bool {
+	return r.Err == nil
+}
+
+// BatchCopy copies the Source of each op to its Target with CopyToFile, running up to concurrency at a time (4 if
+// concurrency is 0 or less).  A failed copy doesn't stop the others; the outcome of each is returned, in the same order
+// as ops.
+func BatchCopy(ops []BatchOp, concurrency int) []BatchResult {
+	return runBatch(ops, concurrency, func(op BatchOp) error {
+		if op.Target == nil {
+			return errors.New("batch copy requires a Target file")
+		}
+		return op.Source.CopyToFile(op.Target)
+	})
+}
+
+// BatchMove moves the Source of each op to its Target with MoveToFile, running up to concurrency at a time (4 if
+// concurrency is 0 or less).  A failed move doesn't stop the others; the outcome of each is returned, in the same order
+// as ops.
+func BatchMove(ops []BatchOp, concurrency int) []BatchResult {
+	return runBatch(ops, concurrency, func(op BatchOp) error {
+		if op.Target == nil {
+			return errors.New("batch move requires a Target file")
+		}
+		return op.Source.MoveToFile(op.Target)
+	})
+}
+
+// BatchDelete deletes the Source of each op, running up to concurrency at a time (4 if concurrency is 0 or less).  A
+// failed delete doesn't stop the others; the outcome of each is returned, in the same order as ops.
+func BatchDelete(ops []BatchOp, concurrency int) []BatchResult {
+	return runBatch(ops, concurrency, func(op BatchOp) error {
+		return op.Source.Delete()
+	})
+}
+
+// BatchErrors returns the results of the operations that failed.
+func BatchErrors(results []BatchResult) []BatchResult {
+	var failed []BatchResult
+	for _, result := range results {
+		if !result.OK() {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// runBatch runs fn for each op, up to concurrency at a time, returning their results in order.
+func runBatch(ops []BatchOp, concurrency int, fn func(BatchOp) error) []BatchResult {
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	results := make([]BatchResult, len(ops))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = runOp(ops[i], fn)
+			}
+		}()
+	}
+
+	for i := range ops {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// runOp runs fn for op, recording the size of its Source first.
+func runOp(op BatchOp, fn func(BatchOp) error) BatchResult {
+	result := BatchResult{Op: op}
+	if op.Source == nil {
+		result.Err = errors.New("batch operation requires a Source file")
+		return result
+	}
+	if size, err := op.Source.Size(); err == nil {
+		result.Bytes = size
+	}
+	result.Err = fn(op)
+	return result
+}
@@ -0,0 +1,115 @@
+package utils_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+type batchTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (ts *batchTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "batch_test")
+	ts.NoError(err)
+	ts.dir = dir
+}
+
+func (ts *batchTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *batchTestSuite) newFile(path, contents string) vfs.File {
+	file, err := _os.NewFileSystem().NewFile("", ts.dir+path)
+	ts.NoError(err)
+	if contents != "" {
+		_, err = file.Write([]byte(contents))
+		ts.NoError(err)
+		ts.NoError(file.Close())
+	}
+	return file
+}
+
+func (ts *batchTestSuite) contents(file vfs.File) string {
+	data, err := ioutil.ReadAll(file)
+	ts.NoError(err)
+	ts.NoError(file.Close())
+	return string(data)
+}
+
+func (ts *batchTestSuite) TestBatchCopy() {
+	ops := []utils.BatchOp{
+		{Source: ts.newFile("/a.txt", "aaa"), Target: ts.newFile("/copies/a.txt", "")},
+		{Source: ts.newFile("/missing.txt", ""), Target: ts.newFile("/copies/missing.txt", "")},
+		{Source: ts.newFile("/b.txt", "bb"), Target: ts.newFile("/copies/b.txt", "")},
+		{Source: ts.newFile("/c.txt", "c")},
+	}
+
+	results := utils.BatchCopy(ops, 2)
+	ts.Len(results, 4)
+	for i, result := range results {
+		ts.Equal(ops[i], result.Op, "results are in the same order as ops")
+	}
+	ts.True(results[0].OK())
+	ts.Equal(uint64(3), results[0].Bytes)
+	ts.Equal("aaa", ts.contents(ops[0].Target))
+	ts.False(results[1].OK(), "a failure doesn't stop the batch")
+	ts.Equal(uint64(0), results[1].Bytes)
+	ts.True(results[2].OK())
+	ts.Equal(uint64(2), results[2].Bytes)
+	ts.Equal("bb", ts.contents(ops[2].Target))
+	ts.Error(results[3].Err, "copies require a Target")
+
+	failed := utils.BatchErrors(results)
+	ts.Len(failed, 2)
+	ts.Equal(ops[1], failed[0].Op)
+	ts.Equal(ops[3], failed[1].Op)
+}
+
+func (ts *batchTestSuite) TestBatchMove() {
+	ops := []utils.BatchOp{
+		{Source: ts.newFile("/a.txt", "aaa"), Target: ts.newFile("/moved/a.txt", "")},
+		{Source: ts.newFile("/b.txt", "bb"), Target: ts.newFile("/moved/b.txt", "")},
+	}
+
+	results := utils.BatchMove(ops, 0)
+	ts.Empty(utils.BatchErrors(results))
+	ts.Equal(uint64(3), results[0].Bytes)
+	ts.Equal("bb", ts.contents(ops[1].Target))
+	exists, err := ops[0].Source.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+}
+
+func (ts *batchTestSuite) TestBatchDelete() {
+	var ops []utils.BatchOp
+	for _, name := range []string{"/1.txt", "/2.txt", "/3.txt", "/4.txt", "/5.txt"} {
+		ops = append(ops, utils.BatchOp{Source: ts.newFile(name, "data")})
+	}
+	ops = append(ops, utils.BatchOp{Source: ts.newFile("/missing.txt", "")}, utils.BatchOp{})
+
+	results := utils.BatchDelete(ops, 3)
+	failed := utils.BatchErrors(results)
+	ts.Len(failed, 2)
+	ts.True(os.IsNotExist(failed[0].Err))
+	ts.Error(failed[1].Err, "ops require a Source")
+	for _, op := range ops[:5] {
+		exists, err := op.Source.Exists()
+		ts.NoError(err)
+		ts.False(exists)
+	}
+
+	ts.Empty(utils.BatchDelete(nil, 2))
+}
+
+func TestBatch(t *testing.T) {
+	suite.Run(t, new(batchTestSuite))
+}
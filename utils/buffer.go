@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// copyBufferSize is the size of the buffers used by Copy.  It's larger than io.Copy's 32KB default to reduce the number
+// of reads and writes made when copying to and from network backed files.
+const copyBufferSize = 256 * 1024
+
+// maxPooledWriteBufferSize is the largest write buffer returned to the pool by PutWriteBuffer.  Larger buffers are left
+// to the garbage collector rather than held onto indefinitely.
+const maxPooledWriteBufferSize = 64 * 1024 * 1024
+
+var copyBuffers = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, copyBufferSize)
+		return &buf
+	},
+}
+
+var writeBuffers = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// Copy is io.Copy using a buffer from a shared pool, avoiding allocating a new buffer for every copy.  As with io.Copy,
+// no buffer is used if src implements io.WriterTo or dst implements io.ReaderFrom.
+func Copy(dst io.Writer, src io.Reader) (int64, error) {
+	buf := copyBuffers.Get().(*[]byte)
+	defer copyBuffers.Put(buf)
+	return io.CopyBuffer(dst, src, *buf)
+}
+
+// GetWriteBuffer returns an empty buffer from a shared pool, for backends that buffer writes in memory until Close.
+// Return it with PutWriteBuffer once it's no longer used.
+func GetWriteBuffer() *bytes.Buffer {
+	return writeBuffers.Get().(*bytes.Buffer)
+}
+
+// PutWriteBuffer resets buf and returns it to the shared pool so its memory can be reused by a later write, rather than
+// growing a new buffer from scratch.  buf must not be used afterwards.
+func PutWriteBuffer(buf *bytes.Buffer) {
+	if buf == nil || buf.Cap() > maxPooledWriteBufferSize {
+		return
+	}
+	buf.Reset()
+	writeBuffers.Put(buf)
+}
@@ -0,0 +1,55 @@
+package utils_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type bufferSuite struct {
+	suite.Suite
+}
+
+// readerOnly hides any io.WriterTo implementation so Copy uses its buffer
+type readerOnly struct {
+	r *strings.Reader
+}
+
+func (r readerOnly) Read(p []byte) (int, error) {
+	return r.r.Read(p)
+}
+
+func (b *bufferSuite) TestCopy() {
+	contents := strings.Repeat("a", 1024*1024)
+	dst := &strings.Builder{}
+	n, err := utils.Copy(dst, readerOnly{strings.NewReader(contents)})
+	b.NoError(err)
+	b.Equal(int64(len(contents)), n)
+	b.Equal(contents, dst.String())
+}
+
+func (b *bufferSuite) TestWriteBuffers() {
+	buf := utils.GetWriteBuffer()
+	b.Equal(0, buf.Len())
+	buf.WriteString("hello")
+	utils.PutWriteBuffer(buf)
+	b.Equal(0, buf.Len(), "returned buffers are reset")
+
+	b.Equal(0, utils.GetWriteBuffer().Len(), "pooled buffers are empty")
+
+	// nil and oversized buffers are ignored
+	utils.PutWriteBuffer(nil)
+	utils.PutWriteBuffer(bytes.NewBuffer(make([]byte, 0, 65*1024*1024)))
+}
+
+func TestBuffer(t *testing.T) {
+	suite.Run(t, new(bufferSuite))
+}
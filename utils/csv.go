@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/csv"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// CSVReader is a csv.Reader of a file, which it closes with Close.
+type CSVReader struct {
+	*csv.Reader
+	file vfs.File
+}
+
+// NewCSVReader returns a CSVReader of file.  The file is read in 64KB chunks, so network backed files are read with
+// fewer, larger reads.  Configure the embedded csv.Reader, ie: its Comma, before reading.
+func NewCSVReader(file vfs.File) *CSVReader {
+	return &CSVReader{
+		Reader: csv.NewReader(bufio.NewReaderSize(file, lineReadSize)),
+		file:   file,
+	}
+}
+
+// Close closes the file.
+func (r *CSVReader) Close() error {
+	return r.file.Close()
+}
+
+// CSVWriter is a csv.Writer to a file.  Close it, rather than the file, once all records are written: it flushes the
+// records the csv.Writer has buffered before closing the file, which, for backends like s3, is when the file is
+// actually written.
+type CSVWriter struct {
+	*csv.Writer
+	file vfs.File
+}
+
+// NewCSVWriter returns a CSVWriter to file.  Configure the embedded csv.Writer, ie: its Comma, before writing.
+func NewCSVWriter(file vfs.File) *CSVWriter {
+	return &CSVWriter{
+		Writer: csv.NewWriter(file),
+		file:   file,
+	}
+}
+
+// Close flushes any buffered records to the file then closes it, creating it even if no records were written.  It
+// returns the first error from writing records, flushing or closing the file.
+func (w *CSVWriter) Close() error {
+	w.Flush()
+	return closeWritten(w.file, w.Error())
+}
+
+// closeWritten closes file after it's been written to, unless writing failed with err, creating it if nothing was
+// written.  The first error is returned.
+func closeWritten(file vfs.File, err error) error {
+	if err == nil {
+		_, err = file.Write([]byte{})
+	}
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,97 @@
+package utils_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_mem "github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type csvSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (c *csvSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "csv_test")
+	c.NoError(err)
+	c.dir = dir
+}
+
+func (c *csvSuite) TearDownTest() {
+	c.NoError(os.RemoveAll(c.dir))
+}
+
+func (c *csvSuite) TestWriteRead() {
+	records := [][]string{{"name", "note"}, {"a", "has, comma"}, {"b", "has \"quotes\"\nand a newline"}}
+	for _, fs := range []vfs.FileSystem{_os.NewFileSystem(), _mem.NewFileSystem()} {
+		file, err := fs.NewFile("", c.dir+"/file.csv")
+		c.NoError(err)
+
+		writer := utils.NewCSVWriter(file)
+		c.NoError(writer.WriteAll(records), fs.Name())
+		for _, record := range records {
+			c.NoError(writer.Write(record), fs.Name())
+		}
+		c.NoError(writer.Close(), fs.Name())
+
+		reader := utils.NewCSVReader(file)
+		read, err := reader.ReadAll()
+		c.NoError(err, fs.Name())
+		c.NoError(reader.Close(), fs.Name())
+		c.Equal(append(records, records...), read, fs.Name())
+	}
+}
+
+func (c *csvSuite) TestEmpty() {
+	for _, fs := range []vfs.FileSystem{_os.NewFileSystem(), _mem.NewFileSystem()} {
+		file, err := fs.NewFile("", c.dir+"/empty.csv")
+		c.NoError(err)
+		c.NoError(utils.NewCSVWriter(file).Close(), fs.Name())
+
+		exists, err := file.Exists()
+		c.NoError(err, fs.Name())
+		c.True(exists, "the file is created even with no records, %s", fs.Name())
+
+		reader := utils.NewCSVReader(file)
+		_, err = reader.Read()
+		c.Equal(io.EOF, err, fs.Name())
+		c.NoError(reader.Close(), fs.Name())
+	}
+}
+
+func (c *csvSuite) TestComma() {
+	file, err := _os.NewFileSystem().NewFile("", c.dir+"/file.tsv")
+	c.NoError(err)
+
+	writer := utils.NewCSVWriter(file)
+	writer.Comma = '\t'
+	c.NoError(writer.Write([]string{"a,b", "c"}))
+	c.NoError(writer.Close())
+
+	contents, err := ioutil.ReadFile(c.dir + "/file.tsv")
+	c.NoError(err)
+	c.Equal("a,b\tc\n", string(contents))
+
+	reader := utils.NewCSVReader(file)
+	reader.Comma = '\t'
+	record, err := reader.Read()
+	c.NoError(err)
+	c.Equal([]string{"a,b", "c"}, record)
+	c.NoError(reader.Close())
+}
+
+func TestCSV(t *testing.T) {
+	suite.Run(t, new(csvSuite))
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// defaultDedupeConcurrency is the number of files checksummed in parallel when DedupeOptions.Concurrency isn't set.
+const defaultDedupeConcurrency = 4
+
+// DedupeOptions controls how Dedupe finds and handles duplicate files.
+type DedupeOptions struct {
+	// Concurrency is the number of files checksummed in parallel.  Defaults to 4.
+	Concurrency int
+	// Delete removes every file in each group of duplicates except the first (by name).  Otherwise duplicates are only
+	// reported.
+	Delete bool
+}
+
+// Dedupe finds files with identical contents at location (not including sub-locations), returning each group of
+// duplicates sorted by name.  Files are first grouped by size, so only files that share a size with another file are
+// read to compute their checksums.  If opts.Delete is set, all but the first file of each group are deleted.
+func Dedupe(location vfs.Location, opts DedupeOptions) ([][]vfs.File, error) {
+	files, err := sortedFiles(location)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]uint64, len(files))
+	bySize := map[uint64]int{}
+	for _, file := range files {
+		size, err := file.Size()
+		if err != nil {
+			return nil, err
+		}
+		sizes[file.Name()] = size
+		bySize[size]++
+	}
+
+	var candidates []vfs.File
+	for _, file := range files {
+		if bySize[sizes[file.Name()]] > 1 {
+			candidates = append(candidates, file)
+		}
+	}
+	checksums, err := parallelChecksums(candidates, opts.Concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	// candidates are in name order, so groups (and the files within them) are too
+	var keys []string
+	byContents := map[string][]vfs.File{}
+	for i, file := range candidates {
+		key := fmt.Sprintf("%d:%s", sizes[file.Name()], checksums[i])
+		if _, ok := byContents[key]; !ok {
+			keys = append(keys, key)
+		}
+		byContents[key] = append(byContents[key], file)
+	}
+
+	duplicates := [][]vfs.File{}
+	for _, key := range keys {
+		if group := byContents[key]; len(group) > 1 {
+			duplicates = append(duplicates, group)
+		}
+	}
+
+	if opts.Delete {
+		for _, group := range duplicates {
+			for _, file := range group[1:] {
+				if err := file.Delete(); err != nil {
+					return duplicates, err
+				}
+			}
+		}
+	}
+	return duplicates, nil
+}
+
+// parallelChecksums returns the checksum of each file, in the same order, computing up to concurrency at a time.
+func parallelChecksums(files []vfs.File, concurrency int) ([]string, error) {
+	if concurrency <= 0 {
+		concurrency = defaultDedupeConcurrency
+	}
+
+	checksums := make([]string, len(files))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				checksum, err := fileChecksum(files[i])
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					continue
+				}
+				checksums[i] = checksum
+			}
+		}()
+	}
+
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return checksums, firstErr
+}
@@ -0,0 +1,86 @@
+package utils_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type dedupeSuite struct {
+	suite.Suite
+	dir      string
+	location vfs.Location
+}
+
+func (d *dedupeSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "dedupe_test")
+	d.NoError(err)
+	d.dir = dir
+	d.location, err = _os.NewFileSystem().NewLocation("", utils.EnsureTrailingSlash(dir))
+	d.NoError(err)
+
+	for name, contents := range map[string]string{
+		"a.txt":      "hello",
+		"a_copy.txt": "hello",
+		"b.txt":      "jello", // same size as a.txt, different contents
+		"c.txt":      "hello world",
+		"c_copy.txt": "hello world",
+		"c_copy2":    "hello world",
+		"d.txt":      "unique",
+	} {
+		file, err := d.location.NewFile(name)
+		d.NoError(err)
+		_, err = file.Write([]byte(contents))
+		d.NoError(err)
+		d.NoError(file.Close())
+	}
+}
+
+func (d *dedupeSuite) TearDownTest() {
+	d.NoError(os.RemoveAll(d.dir))
+}
+
+func names(group []vfs.File) []string {
+	var n []string
+	for _, file := range group {
+		n = append(n, file.Name())
+	}
+	return n
+}
+
+func (d *dedupeSuite) TestDedupe() {
+	duplicates, err := utils.Dedupe(d.location, utils.DedupeOptions{Concurrency: 2})
+	d.NoError(err)
+	d.Len(duplicates, 2)
+	d.Equal([]string{"a.txt", "a_copy.txt"}, names(duplicates[0]))
+	d.Equal([]string{"c.txt", "c_copy.txt", "c_copy2"}, names(duplicates[1]))
+
+	// nothing is deleted when only reporting
+	list, err := d.location.List()
+	d.NoError(err)
+	d.Len(list, 7)
+}
+
+func (d *dedupeSuite) TestDedupe_Delete() {
+	duplicates, err := utils.Dedupe(d.location, utils.DedupeOptions{Delete: true})
+	d.NoError(err)
+	d.Len(duplicates, 2)
+
+	list, err := d.location.List()
+	d.NoError(err)
+	d.ElementsMatch([]string{"a.txt", "b.txt", "c.txt", "d.txt"}, list)
+}
+
+func TestDedupe(t *testing.T) {
+	suite.Run(t, new(dedupeSuite))
+}
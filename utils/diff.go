@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"sort"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// DiffKind describes how a file differs between the two locations passed to Diff.
+type DiffKind int
+
+const (
+	// DiffOnlyInA means the file only exists in the first location.
+	DiffOnlyInA DiffKind = iota
+	// DiffOnlyInB means the file only exists in the second location.
+	DiffOnlyInB
+	// DiffChanged means the file exists in both locations but differs, per DiffOptions.
+	DiffChanged
+)
+
+// DiffOptions determines how files found in both locations are compared by Diff.  Sizes are always compared.
+type DiffOptions struct {
+	// ModTime also compares last modified times.  Copies don't generally preserve these, so this is only useful for
+	// locations that are kept in sync by a tool which does.
+	ModTime bool
+	// Checksum also compares the sha256 checksum of files of the same size, reading both in full.
+	Checksum bool
+}
+
+// DiffResult is a single difference found by Diff.
+type DiffResult struct {
+	// Name is the file's name relative to its location.
+	Name string
+	Kind DiffKind
+	// A and B are the file in each location, nil if it doesn't exist there.
+	A, B vfs.File
+}
+
+// fileLister is implemented by locations that can list files with their metadata already populated, ie: s3.Location,
+// which saves a request per file when comparing sizes and modification times.
+type fileLister interface {
+	ListFiles() ([]vfs.File, error)
+}
+
+// Diff compares the files at locations a and b (not including sub-locations), calling fn with each difference found, in
+// name order.  Results are passed to fn as they're found rather than collected, so comparing large locations only holds
+// their listings in memory.  If fn returns an error, Diff stops and returns it.
+func Diff(a, b vfs.Location, opts DiffOptions, fn func(DiffResult) error) error {
+	filesA, err := sortedFiles(a)
+	if err != nil {
+		return err
+	}
+	filesB, err := sortedFiles(b)
+	if err != nil {
+		return err
+	}
+
+	i, j := 0, 0
+	for i < len(filesA) || j < len(filesB) {
+		var result *DiffResult
+		switch {
+		case j == len(filesB) || (i < len(filesA) && filesA[i].Name() < filesB[j].Name()):
+			result = &DiffResult{Name: filesA[i].Name(), Kind: DiffOnlyInA, A: filesA[i]}
+			i++
+		case i == len(filesA) || filesB[j].Name() < filesA[i].Name():
+			result = &DiffResult{Name: filesB[j].Name(), Kind: DiffOnlyInB, B: filesB[j]}
+			j++
+		default:
+			changed, err := filesDiffer(filesA[i], filesB[j], opts)
+			if err != nil {
+				return err
+			}
+			if changed {
+				result = &DiffResult{Name: filesA[i].Name(), Kind: DiffChanged, A: filesA[i], B: filesB[j]}
+			}
+			i++
+			j++
+		}
+
+		if result != nil {
+			if err := fn(*result); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sortedFiles returns the files at location sorted by name.
+func sortedFiles(location vfs.Location) ([]vfs.File, error) {
+	files, err := listFiles(location)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+	return files, nil
+}
+
+func filesDiffer(a, b vfs.File, opts DiffOptions) (bool, error) {
+	sizeA, err := a.Size()
+	if err != nil {
+		return false, err
+	}
+	sizeB, err := b.Size()
+	if err != nil {
+		return false, err
+	}
+	if sizeA != sizeB {
+		return true, nil
+	}
+
+	if opts.ModTime {
+		modA, err := a.LastModified()
+		if err != nil {
+			return false, err
+		}
+		modB, err := b.LastModified()
+		if err != nil {
+			return false, err
+		}
+		if !modA.Equal(*modB) {
+			return true, nil
+		}
+	}
+
+	if opts.Checksum {
+		checksumA, err := fileChecksum(a)
+		if err != nil {
+			return false, err
+		}
+		checksumB, err := fileChecksum(b)
+		if err != nil {
+			return false, err
+		}
+		return checksumA != checksumB, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,109 @@
+package utils_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type diffSuite struct {
+	suite.Suite
+	dir  string
+	a, b vfs.Location
+}
+
+func (d *diffSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "diff_test")
+	d.NoError(err)
+	d.dir = utils.EnsureTrailingSlash(dir)
+	d.a, err = _os.NewFileSystem().NewLocation("", d.dir+"a/")
+	d.NoError(err)
+	d.b, err = _os.NewFileSystem().NewLocation("", d.dir+"b/")
+	d.NoError(err)
+}
+
+func (d *diffSuite) TearDownTest() {
+	d.NoError(os.RemoveAll(d.dir))
+}
+
+func (d *diffSuite) writeFile(location vfs.Location, name, contents string) vfs.File {
+	file, err := location.NewFile(name)
+	d.NoError(err)
+	_, err = file.Write([]byte(contents))
+	d.NoError(err)
+	d.NoError(file.Close())
+	return file
+}
+
+func (d *diffSuite) diff(opts utils.DiffOptions) map[string]utils.DiffKind {
+	results := map[string]utils.DiffKind{}
+	err := utils.Diff(d.a, d.b, opts, func(result utils.DiffResult) error {
+		results[result.Name] = result.Kind
+		return nil
+	})
+	d.NoError(err)
+	return results
+}
+
+func (d *diffSuite) TestDiff() {
+	d.writeFile(d.a, "same.txt", "hello")
+	d.writeFile(d.b, "same.txt", "hello")
+	d.writeFile(d.a, "only_a.txt", "hello")
+	d.writeFile(d.b, "only_b.txt", "hello")
+	d.writeFile(d.a, "resized.txt", "hello")
+	d.writeFile(d.b, "resized.txt", "hello world")
+	d.writeFile(d.a, "changed.txt", "hello")
+	d.writeFile(d.b, "changed.txt", "jello")
+
+	d.Equal(map[string]utils.DiffKind{
+		"only_a.txt":  utils.DiffOnlyInA,
+		"only_b.txt":  utils.DiffOnlyInB,
+		"resized.txt": utils.DiffChanged,
+	}, d.diff(utils.DiffOptions{}), "only sizes compared by default")
+
+	d.Equal(map[string]utils.DiffKind{
+		"only_a.txt":  utils.DiffOnlyInA,
+		"only_b.txt":  utils.DiffOnlyInB,
+		"resized.txt": utils.DiffChanged,
+		"changed.txt": utils.DiffChanged,
+	}, d.diff(utils.DiffOptions{Checksum: true}))
+}
+
+func (d *diffSuite) TestDiff_ModTime() {
+	d.writeFile(d.a, "file.txt", "hello")
+	file := d.writeFile(d.b, "file.txt", "hello")
+	mtime := time.Now().Add(-time.Hour)
+	d.NoError(os.Chtimes(file.Path(), mtime, mtime))
+
+	d.Empty(d.diff(utils.DiffOptions{}))
+	d.Equal(map[string]utils.DiffKind{"file.txt": utils.DiffChanged}, d.diff(utils.DiffOptions{ModTime: true}))
+}
+
+func (d *diffSuite) TestDiff_CallbackError() {
+	d.writeFile(d.a, "a.txt", "hello")
+	d.writeFile(d.a, "b.txt", "hello")
+
+	calls := 0
+	err := utils.Diff(d.a, d.b, utils.DiffOptions{}, func(result utils.DiffResult) error {
+		calls++
+		return errors.New("stop")
+	})
+	d.EqualError(err, "stop")
+	d.Equal(1, calls, "diff should stop at the first callback error")
+}
+
+func TestDiff(t *testing.T) {
+	suite.Run(t, new(diffSuite))
+}
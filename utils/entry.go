@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"errors"
+	"path"
+	"sort"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// SkipLocation is returned by a Walk function to skip the entries beneath the location entry it was called with or,
+// for a file entry, the rest of the entries at the file's location.
+var SkipLocation = errors.New("skip this location")
+
+// Entry is a file or sub-location found at a location by ListEntries or Walk, so code walking a tree can treat both
+// alike without knowing how a backend represents directories, ie: as s3 common prefixes or os directories.  Exactly one
+// of File and Location is set.
+type Entry struct {
+	File     vfs.File
+	Location vfs.Location
+}
+
+// IsDir returns true if the entry is a sub-location.
+func (e Entry) IsDir() bool {
+	return e.Location != nil
+}
+
+// IsRegular returns true if the entry is a file.
+func (e Entry) IsRegular() bool {
+	return e.File != nil
+}
+
+// Name returns the file's name, or the last element of the location's path without its trailing slash.
+func (e Entry) Name() string {
+	if e.IsDir() {
+		return path.Base(RemoveTrailingSlash(e.Location.Path()))
+	}
+	return e.File.Name()
+}
+
+// Path returns the path of the file or location.
+func (e Entry) Path() string {
+	if e.IsDir() {
+		return e.Location.Path()
+	}
+	return e.File.Path()
+}
+
+// URI returns the URI of the file or location.
+func (e Entry) URI() string {
+	if e.IsDir() {
+		return e.Location.URI()
+	}
+	return e.File.URI()
+}
+
+// ListEntries returns the files and sub-locations found directly at location, in name order, as ListDir does.
+// Locations that can't list their sub-locations (mem.Location) return only their files.
+func ListEntries(location vfs.Location) ([]Entry, error) {
+	files, locations, err := ListDir(location)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(files)+len(locations))
+	for _, file := range files {
+		entries = append(entries, Entry{File: file})
+	}
+	for _, sub := range locations {
+		entries = append(entries, Entry{Location: sub})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// Walk calls fn for each file and sub-location beneath location, depth first and in name order at each location.  A
+// sub-location's entries follow it, unless fn returns SkipLocation for it.  Any other error from fn stops the walk and
+// is returned, as are errors listing a location.
+func Walk(location vfs.Location, fn func(Entry) error) error {
+	entries, err := ListEntries(location)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		err := fn(entry)
+		if err == SkipLocation {
+			if entry.IsDir() {
+				continue
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if err := Walk(entry.Location, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package utils_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_mem "github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+type entrySuite struct {
+	suite.Suite
+	dir      string
+	location vfs.Location
+}
+
+func (s *entrySuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "entry_test")
+	s.NoError(err)
+	s.dir = dir
+
+	// dir/
+	//   b.txt
+	//   a/
+	//     c.txt
+	//     d/
+	//       e.txt
+	//   f/
+	//     g.txt
+	for _, name := range []string{"/b.txt", "/a/c.txt", "/a/d/e.txt", "/f/g.txt"} {
+		s.NoError(os.MkdirAll(dir+name[:len(name)-len("x.txt")], 0755))
+		s.NoError(ioutil.WriteFile(dir+name, []byte("hello"), 0644))
+	}
+	s.location, err = _os.NewFileSystem().NewLocation("", dir+"/")
+	s.NoError(err)
+}
+
+func (s *entrySuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *entrySuite) TestListEntries() {
+	entries, err := utils.ListEntries(s.location)
+	s.NoError(err)
+	s.Len(entries, 3)
+
+	s.Equal("a", entries[0].Name(), "entries are in name order")
+	s.True(entries[0].IsDir())
+	s.False(entries[0].IsRegular())
+	s.Equal(s.dir+"/a/", entries[0].Path())
+	s.Equal(s.location.URI()+"a/", entries[0].URI())
+
+	s.Equal("b.txt", entries[1].Name())
+	s.True(entries[1].IsRegular())
+	s.False(entries[1].IsDir())
+	s.Equal(s.dir+"/b.txt", entries[1].Path())
+
+	s.Equal("f", entries[2].Name())
+}
+
+func (s *entrySuite) TestListEntries_WithoutSubLocations() {
+	file, err := _mem.NewFileSystem().NewFile("bucket", "/dir/sub/file.txt")
+	s.NoError(err)
+	s.NoError(file.Touch())
+	file, err = file.Location().NewFile("../file.txt")
+	s.NoError(err)
+	s.NoError(file.Touch())
+
+	entries, err := utils.ListEntries(file.Location())
+	s.NoError(err)
+	s.Len(entries, 1, "only files are listed where sub-locations can't be")
+	s.Equal("file.txt", entries[0].Name())
+}
+
+func (s *entrySuite) TestWalk() {
+	var walked []string
+	err := utils.Walk(s.location, func(entry utils.Entry) error {
+		walked = append(walked, entry.Path()[len(s.dir):])
+		return nil
+	})
+	s.NoError(err)
+	s.Equal([]string{"/a/", "/a/c.txt", "/a/d/", "/a/d/e.txt", "/b.txt", "/f/", "/f/g.txt"}, walked)
+}
+
+func (s *entrySuite) TestWalk_Skip() {
+	var walked []string
+	err := utils.Walk(s.location, func(entry utils.Entry) error {
+		walked = append(walked, entry.Path()[len(s.dir):])
+		switch entry.Name() {
+		case "d":
+			return utils.SkipLocation
+		case "c.txt":
+			return utils.SkipLocation
+		}
+		return nil
+	})
+	s.NoError(err)
+	s.Equal([]string{"/a/", "/a/c.txt", "/b.txt", "/f/", "/f/g.txt"}, walked,
+		"skipping a file skips the rest of its location")
+
+	walked = nil
+	err = utils.Walk(s.location, func(entry utils.Entry) error {
+		walked = append(walked, entry.Name())
+		if entry.Name() == "a" {
+			return utils.SkipLocation
+		}
+		return nil
+	})
+	s.NoError(err)
+	s.Equal([]string{"a", "b.txt", "f", "g.txt"}, walked)
+}
+
+func (s *entrySuite) TestWalk_Error() {
+	errStop := errors.New("stop")
+	count := 0
+	err := utils.Walk(s.location, func(entry utils.Entry) error {
+		count++
+		if entry.Name() == "c.txt" {
+			return errStop
+		}
+		return nil
+	})
+	s.Equal(errStop, err)
+	s.Equal(2, count)
+}
+
+func TestEntry(t *testing.T) {
+	suite.Run(t, new(entrySuite))
+}
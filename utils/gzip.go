@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// gzipMagic begins every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// IsGzipped returns true if file has a .gz extension or, for files that support Stat (ie: s3.File), a content encoding
+// of gzip.
+func IsGzipped(file vfs.File) (bool, error) {
+	if strings.EqualFold(path.Ext(file.Name()), ".gz") {
+		return true, nil
+	}
+	s, ok := file.(statter)
+	if !ok {
+		return false, nil
+	}
+	info, err := s.Stat()
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(info.ContentEncoding, "gzip"), nil
+}
+
+// NewDecompressingReader returns a reader of file's contents which, when IsGzipped is true and the contents begin with
+// a gzip header, decompresses them transparently.  Otherwise the contents are returned as they are, so a file that
+// was already decompressed in transit, ie: by a server honouring its Content-Encoding, is still read correctly.  Read
+// the file directly for its raw contents.  Closing the reader closes the file.
+func NewDecompressingReader(file vfs.File) (io.ReadCloser, error) {
+	gzipped, err := IsGzipped(file)
+	if err != nil {
+		return nil, err
+	}
+	if !gzipped {
+		return file, nil
+	}
+
+	buf := bufio.NewReaderSize(file, lineReadSize)
+	magic, err := buf.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		_ = file.Close()
+		return nil, err
+	}
+	if !bytes.Equal(magic, gzipMagic) {
+		return &gzipReader{Reader: buf, file: file}, nil
+	}
+
+	decompressor, err := gzip.NewReader(buf)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &gzipReader{Reader: decompressor, file: file}, nil
+}
+
+// gzipReader is the reader returned by NewDecompressingReader.  There's no need to close a gzip.Reader; its Close only
+// returns any error already returned by Read.
+type gzipReader struct {
+	io.Reader
+	file vfs.File
+}
+
+// Close closes the file.
+func (r *gzipReader) Close() error {
+	return r.file.Close()
+}
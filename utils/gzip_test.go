@@ -0,0 +1,112 @@
+package utils_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_mem "github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type gzipSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (g *gzipSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "gzip_test")
+	g.NoError(err)
+	g.dir = dir
+}
+
+func (g *gzipSuite) TearDownTest() {
+	g.NoError(os.RemoveAll(g.dir))
+}
+
+func (g *gzipSuite) newFile(fs vfs.FileSystem, name string, contents []byte) vfs.File {
+	file, err := fs.NewFile("", g.dir+"/"+name)
+	g.NoError(err)
+	_, err = file.Write(contents)
+	g.NoError(err)
+	g.NoError(file.Close())
+	return file
+}
+
+func (g *gzipSuite) read(file vfs.File) string {
+	reader, err := utils.NewDecompressingReader(file)
+	g.NoError(err)
+	contents, err := ioutil.ReadAll(reader)
+	g.NoError(err)
+	g.NoError(reader.Close())
+	return string(contents)
+}
+
+func gzipped(contents string) []byte {
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	_, _ = writer.Write([]byte(contents))
+	_ = writer.Close()
+	return buf.Bytes()
+}
+
+func (g *gzipSuite) TestIsGzipped() {
+	fs := _mem.NewFileSystem()
+	for name, expected := range map[string]bool{"feed.csv.gz": true, "FEED.GZ": true, "feed.csv": false, "gz": false} {
+		file, err := fs.NewFile("", "/"+name)
+		g.NoError(err)
+		gzipped, err := utils.IsGzipped(file)
+		g.NoError(err)
+		g.Equal(expected, gzipped, name)
+	}
+}
+
+func (g *gzipSuite) TestNewDecompressingReader() {
+	for _, fs := range []vfs.FileSystem{_os.NewFileSystem(), _mem.NewFileSystem()} {
+		file := g.newFile(fs, "feed.csv.gz", gzipped("a,b\nc,d\n"))
+		g.Equal("a,b\nc,d\n", g.read(file), fs.Name())
+
+		// raw contents are still available by reading the file directly
+		raw, err := ioutil.ReadAll(file)
+		g.NoError(err, fs.Name())
+		g.NoError(file.Close(), fs.Name())
+		g.Equal(gzipped("a,b\nc,d\n"), raw, fs.Name())
+
+		file = g.newFile(fs, "decompressed.csv.gz", []byte("a,b\n"))
+		g.Equal("a,b\n", g.read(file), "contents without a gzip header are read as they are, %s", fs.Name())
+
+		file = g.newFile(fs, "feed.bin", gzipped("a,b\n"))
+		g.Equal(string(gzipped("a,b\n")), g.read(file), "files that aren't gzipped are read as they are, %s", fs.Name())
+
+		file = g.newFile(fs, "empty.gz", []byte{})
+		g.Equal("", g.read(file), fs.Name())
+	}
+}
+
+func (g *gzipSuite) TestMultipleMembers() {
+	file := g.newFile(_os.NewFileSystem(), "feed.gz", append(gzipped("first\n"), gzipped("second\n")...))
+	g.Equal("first\nsecond\n", g.read(file), "concatenated gzip files are read in full")
+}
+
+func (g *gzipSuite) TestCorrupt() {
+	file := g.newFile(_os.NewFileSystem(), "corrupt.gz", gzipped("a,b\n")[:12])
+	reader, err := utils.NewDecompressingReader(file)
+	g.NoError(err)
+	_, err = ioutil.ReadAll(reader)
+	g.Error(err)
+	g.NoError(reader.Close())
+}
+
+func TestGzip(t *testing.T) {
+	suite.Run(t, new(gzipSuite))
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/json"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// JSONLReader decodes the values of a JSON Lines file, one per line.
+type JSONLReader struct {
+	decoder *json.Decoder
+	file    vfs.File
+}
+
+// NewJSONLReader returns a JSONLReader of file.  The file is read in 64KB chunks, so network backed files are read
+// with fewer, larger reads.
+func NewJSONLReader(file vfs.File) *JSONLReader {
+	return &JSONLReader{
+		decoder: json.NewDecoder(bufio.NewReaderSize(file, lineReadSize)),
+		file:    file,
+	}
+}
+
+// Decode decodes the next value into v, as with json.Decoder.Decode, returning io.EOF once there are no more.
+func (r *JSONLReader) Decode(v interface{}) error {
+	return r.decoder.Decode(v)
+}
+
+// Close closes the file.
+func (r *JSONLReader) Close() error {
+	return r.file.Close()
+}
+
+// JSONLWriter encodes values to a JSON Lines file, one per line.  Close it, rather than the file, once all values are
+// written: it flushes the values it has buffered before closing the file, which, for backends like s3, is when the
+// file is actually written.
+type JSONLWriter struct {
+	buf     *bufio.Writer
+	encoder *json.Encoder
+	file    vfs.File
+	err     error
+}
+
+// NewJSONLWriter returns a JSONLWriter to file.
+func NewJSONLWriter(file vfs.File) *JSONLWriter {
+	buf := bufio.NewWriter(file)
+	return &JSONLWriter{
+		buf:     buf,
+		encoder: json.NewEncoder(buf),
+		file:    file,
+	}
+}
+
+// Encode writes v as a single line of JSON, as with json.Encoder.Encode.  Once writing fails, every call returns the
+// same error.
+func (w *JSONLWriter) Encode(v interface{}) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.err = w.encoder.Encode(v)
+	return w.err
+}
+
+// Close flushes any buffered values to the file then closes it, creating it even if no values were written.  It
+// returns the first error from writing values, flushing or closing the file.
+func (w *JSONLWriter) Close() error {
+	if w.err == nil {
+		w.err = w.buf.Flush()
+	}
+	return closeWritten(w.file, w.err)
+}
@@ -0,0 +1,105 @@
+package utils_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_mem "github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type jsonlSuite struct {
+	suite.Suite
+	dir string
+}
+
+type jsonlRecord struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func (j *jsonlSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "jsonl_test")
+	j.NoError(err)
+	j.dir = dir
+}
+
+func (j *jsonlSuite) TearDownTest() {
+	j.NoError(os.RemoveAll(j.dir))
+}
+
+func (j *jsonlSuite) TestWriteRead() {
+	records := []jsonlRecord{{Name: "a", Count: 1}, {Name: "b\nc", Count: 2}}
+	for _, fs := range []vfs.FileSystem{_os.NewFileSystem(), _mem.NewFileSystem()} {
+		file, err := fs.NewFile("", j.dir+"/file.jsonl")
+		name := fs.Name()
+		j.NoError(err, name)
+
+		writer := utils.NewJSONLWriter(file)
+		for _, record := range records {
+			j.NoError(writer.Encode(record), name)
+		}
+		j.NoError(writer.Close(), name)
+
+		reader := utils.NewJSONLReader(file)
+		var read []jsonlRecord
+		for {
+			var record jsonlRecord
+			err := reader.Decode(&record)
+			if err == io.EOF {
+				break
+			}
+			j.NoError(err, name)
+			read = append(read, record)
+		}
+		j.NoError(reader.Close(), name)
+		j.Equal(records, read, name)
+	}
+
+	contents, err := ioutil.ReadFile(j.dir + "/file.jsonl")
+	j.NoError(err)
+	j.Equal("{\"name\":\"a\",\"count\":1}\n{\"name\":\"b\\nc\",\"count\":2}\n", string(contents), "one value per line")
+}
+
+func (j *jsonlSuite) TestEmpty() {
+	file, err := _os.NewFileSystem().NewFile("", j.dir+"/empty.jsonl")
+	j.NoError(err)
+	j.NoError(utils.NewJSONLWriter(file).Close())
+
+	exists, err := file.Exists()
+	j.NoError(err)
+	j.True(exists, "the file is created even with no values")
+
+	reader := utils.NewJSONLReader(file)
+	j.Equal(io.EOF, reader.Decode(&jsonlRecord{}))
+	j.NoError(reader.Close())
+}
+
+func (j *jsonlSuite) TestEncodeError() {
+	file, err := _os.NewFileSystem().NewFile("", j.dir+"/file.jsonl")
+	j.NoError(err)
+
+	writer := utils.NewJSONLWriter(file)
+	j.NoError(writer.Encode(jsonlRecord{Name: "a"}))
+	j.Error(writer.Encode(make(chan int)))
+	j.Error(writer.Encode(jsonlRecord{Name: "b"}), "later values fail with the same error")
+	j.Error(writer.Close())
+
+	exists, err := file.Exists()
+	j.NoError(err)
+	j.False(exists, "nothing is written once encoding fails")
+}
+
+func TestJSONL(t *testing.T) {
+	suite.Run(t, new(jsonlSuite))
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"bufio"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// DefaultMaxLineLength is the length of the longest line NewLineScanner and Lines accept when maxLineLength isn't set.
+const DefaultMaxLineLength = 1024 * 1024
+
+// lineReadSize is the initial size of a line scanner's buffer, and so the size of its reads of the file.  It's larger
+// than bufio.Scanner's default so network backed files are read with fewer, larger reads.
+const lineReadSize = 64 * 1024
+
+// NewLineScanner returns a bufio.Scanner of the lines of file, without their line endings (\n or \r\n), ie: for reading
+// CSV or JSONL files line by line without loading them into memory.  A line longer than maxLineLength bytes
+// (DefaultMaxLineLength if 0 or less) stops the scan, with Err returning bufio.ErrTooLong.  The scanner doesn't close
+// file, see Lines.
+func NewLineScanner(file vfs.File, maxLineLength int) *bufio.Scanner {
+	if maxLineLength <= 0 {
+		maxLineLength = DefaultMaxLineLength
+	}
+	// leave room for the line ending, which the scanner must find before returning the line
+	maxTokenSize := maxLineLength + 2
+	bufSize := lineReadSize
+	if bufSize > maxTokenSize {
+		bufSize = maxTokenSize
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, bufSize), maxTokenSize)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if len(token) > maxLineLength {
+			return 0, nil, bufio.ErrTooLong
+		}
+		return advance, token, err
+	})
+	return scanner
+}
+
+// Lines calls fn with each line of file, in order, then closes it.  If fn returns an error, Lines stops and returns it.
+// See NewLineScanner for maxLineLength.
+func Lines(file vfs.File, maxLineLength int, fn func(line string) error) error {
+	scanner := NewLineScanner(file, maxLineLength)
+	for scanner.Scan() {
+		if err := fn(scanner.Text()); err != nil {
+			_ = file.Close()
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
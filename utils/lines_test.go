@@ -0,0 +1,119 @@
+package utils_test
+
+import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_mem "github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type linesSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (l *linesSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "lines_test")
+	l.NoError(err)
+	l.dir = dir
+}
+
+func (l *linesSuite) TearDownTest() {
+	l.NoError(os.RemoveAll(l.dir))
+}
+
+func (l *linesSuite) newFile(fs vfs.FileSystem, contents string) vfs.File {
+	file, err := fs.NewFile("", l.dir+"/file.txt")
+	l.NoError(err)
+	_, err = file.Write([]byte(contents))
+	l.NoError(err)
+	l.NoError(file.Close())
+	return file
+}
+
+func (l *linesSuite) lines(file vfs.File, maxLineLength int) ([]string, error) {
+	lines := []string{}
+	err := utils.Lines(file, maxLineLength, func(line string) error {
+		lines = append(lines, line)
+		return nil
+	})
+	return lines, err
+}
+
+func (l *linesSuite) TestLines() {
+	for _, fs := range []vfs.FileSystem{_os.NewFileSystem(), _mem.NewFileSystem()} {
+		file := l.newFile(fs, "a,b\r\nc,d\n\nlast")
+		lines, err := l.lines(file, 0)
+		l.NoError(err, fs.Name())
+		l.Equal([]string{"a,b", "c,d", "", "last"}, lines, fs.Name())
+
+		// the file is closed, so can be read again
+		lines, err = l.lines(file, 0)
+		l.NoError(err, fs.Name())
+		l.Len(lines, 4, fs.Name())
+	}
+}
+
+func (l *linesSuite) TestLongLines() {
+	long := strings.Repeat("x", 100*1024)
+	file := l.newFile(_os.NewFileSystem(), "short\n"+long+"\r\nshort\n")
+
+	lines, err := l.lines(file, 0)
+	l.NoError(err)
+	l.Equal([]string{"short", long, "short"}, lines, "lines longer than the read size are allowed")
+
+	lines, err = l.lines(file, len(long))
+	l.NoError(err)
+	l.Len(lines, 3, "a line of exactly maxLineLength is allowed")
+
+	lines, err = l.lines(file, len(long)-1)
+	l.Equal(bufio.ErrTooLong, err)
+	l.Equal([]string{"short"}, lines)
+
+	lines, err = l.lines(l.newFile(_os.NewFileSystem(), "12345\n123456\n"), 5)
+	l.Equal(bufio.ErrTooLong, err)
+	l.Equal([]string{"12345"}, lines)
+}
+
+func (l *linesSuite) TestStop() {
+	file := l.newFile(_os.NewFileSystem(), "1\n2\n3\n")
+	var lines []string
+	err := utils.Lines(file, 0, func(line string) error {
+		lines = append(lines, line)
+		if line == "2" {
+			return errors.New("stop")
+		}
+		return nil
+	})
+	l.EqualError(err, "stop")
+	l.Equal([]string{"1", "2"}, lines)
+}
+
+func (l *linesSuite) TestScanner() {
+	file := l.newFile(_os.NewFileSystem(), "1\n2\n")
+	scanner := utils.NewLineScanner(file, 0)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	l.NoError(scanner.Err())
+	l.NoError(file.Close())
+	l.Equal([]string{"1", "2"}, lines)
+}
+
+func TestLines(t *testing.T) {
+	suite.Run(t, new(linesSuite))
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"github.com/c2fo/vfs/v5"
+)
+
+// prefixLister is implemented by locations which can list their sub-locations along with their files, ie: s3.Location
+// and os.Location.
+type prefixLister interface {
+	ListWithPrefixes() ([]vfs.File, []vfs.Location, error)
+}
+
+// ListDir returns the files found directly at location along with its sub-locations, ie: for presenting a location as
+// a directory.  Locations that can't list their sub-locations (mem.Location) return only their files.
+func ListDir(location vfs.Location) ([]vfs.File, []vfs.Location, error) {
+	if l, ok := location.(prefixLister); ok {
+		return l.ListWithPrefixes()
+	}
+
+	files, err := listFiles(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	return files, []vfs.Location{}, nil
+}
+
+// listFiles returns the files at location, using its ListFiles method where it has one.
+func listFiles(location vfs.Location) ([]vfs.File, error) {
+	if l, ok := location.(fileLister); ok {
+		return l.ListFiles()
+	}
+
+	names, err := location.List()
+	if err != nil {
+		return nil, err
+	}
+	files := make([]vfs.File, 0, len(names))
+	for _, name := range names {
+		file, err := location.NewFile(name)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
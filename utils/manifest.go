@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// ManifestEntry describes a single file in a Manifest.
+type ManifestEntry struct {
+	// Name is the file's path relative to the location, ie: "report.csv" or "logs/app.log".
+	Name string `json:"name"`
+	// Size is the size of the file in bytes.
+	Size uint64 `json:"size"`
+	// Checksum is the hex encoded sha256 checksum of the file's contents.
+	Checksum string `json:"checksum"`
+	// LastModified is the file's last modified time when the manifest was created.  It's informational only and isn't
+	// checked by VerifyManifest since copies and moves don't generally preserve it.
+	LastModified time.Time `json:"lastModified"`
+}
+
+// Manifest lists the files found beneath a location, see CreateManifest.
+type Manifest struct {
+	Files []ManifestEntry `json:"files"`
+}
+
+// ManifestReport is the result of verifying a location against a Manifest.  Each field lists file names relative to
+// the location.
+type ManifestReport struct {
+	// Missing files are in the manifest but not at the location.
+	Missing []string
+	// Extra files are at the location but not in the manifest.
+	Extra []string
+	// Corrupt files are at the location but their size or checksum doesn't match the manifest.
+	Corrupt []string
+}
+
+// OK returns true if there were no missing, extra or corrupt files.
+func (r ManifestReport) OK() bool {
+	return len(r.Missing) == 0 && len(r.Extra) == 0 && len(r.Corrupt) == 0
+}
+
+// CreateManifest returns a Manifest of the files beneath location, reading each one to compute its checksum.  Files in
+// sub-locations are included, named by their path relative to location (ie: "logs/app.log"), where its backend can
+// list them (see ListDir).  A manifest written beneath the location itself will show up as an extra file when
+// verifying, so write it elsewhere.
+func CreateManifest(location vfs.Location) (Manifest, error) {
+	names, files, err := manifestFiles(location)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	manifest := Manifest{Files: []ManifestEntry{}}
+	for _, name := range names {
+		entry, err := newManifestEntry(name, files[name])
+		if err != nil {
+			return Manifest{}, err
+		}
+		manifest.Files = append(manifest.Files, entry)
+	}
+	return manifest, nil
+}
+
+// manifestFiles returns the files beneath location keyed by their path relative to it, along with those paths in
+// sorted order.
+func manifestFiles(location vfs.Location) ([]string, map[string]vfs.File, error) {
+	files := map[string]vfs.File{}
+	err := Walk(location, func(entry Entry) error {
+		if !entry.IsDir() {
+			files[strings.TrimPrefix(entry.File.Path(), location.Path())] = entry.File
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, files, nil
+}
+
+// WriteManifest writes manifest to file as JSON.
+func WriteManifest(manifest Manifest, file vfs.File) error {
+	if err := json.NewEncoder(file).Encode(manifest); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ReadManifest reads a Manifest previously written with WriteManifest.
+func ReadManifest(file vfs.File) (Manifest, error) {
+	manifest := Manifest{}
+	if err := json.NewDecoder(file).Decode(&manifest); err != nil {
+		_ = file.Close()
+		return Manifest{}, fmt.Errorf("unable to read manifest %s: %s", file, err.Error())
+	}
+	return manifest, file.Close()
+}
+
+// VerifyManifest compares the files beneath location, found as CreateManifest finds them, to manifest, reporting
+// missing, extra and corrupt files.  A file's checksum is only computed when its size matches the manifest.  The returned error is for failures to list or read
+// files, not for verification failures, see ManifestReport.OK.
+func VerifyManifest(location vfs.Location, manifest Manifest) (ManifestReport, error) {
+	report := ManifestReport{}
+	names, files, err := manifestFiles(location)
+	if err != nil {
+		return report, err
+	}
+
+	expected := make(map[string]bool, len(manifest.Files))
+	for _, entry := range manifest.Files {
+		expected[entry.Name] = true
+		file, found := files[entry.Name]
+		if !found {
+			report.Missing = append(report.Missing, entry.Name)
+			continue
+		}
+
+		size, err := file.Size()
+		if err != nil {
+			return report, err
+		}
+		if size != entry.Size {
+			report.Corrupt = append(report.Corrupt, entry.Name)
+			continue
+		}
+		checksum, err := fileChecksum(file)
+		if err != nil {
+			return report, err
+		}
+		if checksum != entry.Checksum {
+			report.Corrupt = append(report.Corrupt, entry.Name)
+		}
+	}
+
+	for _, name := range names {
+		if !expected[name] {
+			report.Extra = append(report.Extra, name)
+		}
+	}
+	return report, nil
+}
+
+func newManifestEntry(name string, file vfs.File) (ManifestEntry, error) {
+	size, err := file.Size()
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	lastModified, err := file.LastModified()
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	checksum, err := fileChecksum(file)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	return ManifestEntry{
+		Name:         name,
+		Size:         size,
+		Checksum:     checksum,
+		LastModified: *lastModified,
+	}, nil
+}
+
+// fileChecksum returns the hex encoded sha256 checksum of the file's contents.
+func fileChecksum(file vfs.File) (string, error) {
+	return fileDigest(file, sha256.New())
+}
+
+// fileDigest returns the hex encoded sum of the file's contents computed by hash.  The file is read in full and closed.
+func fileDigest(file vfs.File, hash hash.Hash) (string, error) {
+	if _, err := Copy(hash, file); err != nil {
+		_ = file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,110 @@
+package utils_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type manifestSuite struct {
+	suite.Suite
+	dir      string
+	location vfs.Location
+}
+
+func (m *manifestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "manifest_test")
+	m.NoError(err)
+	m.dir = dir
+	m.location, err = _os.NewFileSystem().NewLocation("", utils.EnsureTrailingSlash(dir))
+	m.NoError(err)
+}
+
+func (m *manifestSuite) TearDownTest() {
+	m.NoError(os.RemoveAll(m.dir))
+}
+
+func (m *manifestSuite) writeFile(name, contents string) {
+	file, err := m.location.NewFile(name)
+	m.NoError(err)
+	_, err = file.Write([]byte(contents))
+	m.NoError(err)
+	m.NoError(file.Close())
+}
+
+func (m *manifestSuite) TestCreateManifest() {
+	m.writeFile("b.txt", "world")
+	m.writeFile("a.txt", "hello")
+	m.writeFile("sub/c.txt", "nested")
+	m.writeFile("sub/deeper/d.txt", "deeper")
+
+	manifest, err := utils.CreateManifest(m.location)
+	m.NoError(err)
+	m.Len(manifest.Files, 4)
+	m.Equal("a.txt", manifest.Files[0].Name)
+	m.Equal(uint64(5), manifest.Files[0].Size)
+	m.Equal("2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", manifest.Files[0].Checksum)
+	m.False(manifest.Files[0].LastModified.IsZero())
+	m.Equal("b.txt", manifest.Files[1].Name)
+	m.Equal("sub/c.txt", manifest.Files[2].Name, "files in sub-locations are included")
+	m.Equal("sub/deeper/d.txt", manifest.Files[3].Name)
+}
+
+func (m *manifestSuite) TestWriteAndReadManifest() {
+	m.writeFile("a.txt", "hello")
+	manifest, err := utils.CreateManifest(m.location)
+	m.NoError(err)
+
+	manifestFile, err := _os.NewFileSystem().NewFile("", m.dir+".manifest.json")
+	m.NoError(err)
+	defer func() { m.NoError(manifestFile.Delete()) }()
+	m.NoError(utils.WriteManifest(manifest, manifestFile))
+
+	read, err := utils.ReadManifest(manifestFile)
+	m.NoError(err)
+	m.Equal(manifest.Files[0].Name, read.Files[0].Name)
+	m.Equal(manifest.Files[0].Checksum, read.Files[0].Checksum)
+	m.True(manifest.Files[0].LastModified.Equal(read.Files[0].LastModified))
+}
+
+func (m *manifestSuite) TestVerifyManifest() {
+	m.writeFile("same.txt", "hello")
+	m.writeFile("resized.txt", "hello")
+	m.writeFile("changed.txt", "hello")
+	m.writeFile("deleted.txt", "hello")
+	m.writeFile("sub/nested.txt", "hello")
+	manifest, err := utils.CreateManifest(m.location)
+	m.NoError(err)
+
+	report, err := utils.VerifyManifest(m.location, manifest)
+	m.NoError(err)
+	m.True(report.OK(), "unchanged location should verify")
+
+	m.writeFile("resized.txt", "hello world")
+	m.writeFile("changed.txt", "jello")
+	m.NoError(m.location.DeleteFile("deleted.txt"))
+	m.writeFile("added.txt", "hello")
+	m.writeFile("sub/nested.txt", "jello")
+	m.writeFile("sub/added.txt", "hello")
+
+	report, err = utils.VerifyManifest(m.location, manifest)
+	m.NoError(err)
+	m.False(report.OK())
+	m.Equal([]string{"deleted.txt"}, report.Missing)
+	m.Equal([]string{"added.txt", "sub/added.txt"}, report.Extra)
+	m.Equal([]string{"changed.txt", "resized.txt", "sub/nested.txt"}, report.Corrupt)
+}
+
+func TestManifest(t *testing.T) {
+	suite.Run(t, new(manifestSuite))
+}
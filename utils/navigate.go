@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// ErrNoParent is returned by Parent for the root location of a volume.
+var ErrNoParent = errors.New("root location has no parent")
+
+// Parent returns the location containing location, ie: s3://bucket/path/ for s3://bucket/path/to/, or ErrNoParent for
+// the root location of a volume.
+func Parent(location vfs.Location) (vfs.Location, error) {
+	p := RemoveTrailingSlash(location.Path())
+	if p == "" {
+		return nil, ErrNoParent
+	}
+	parentPath := p[:strings.LastIndex(p, "/")+1]
+
+	parent, err := location.NewLocation("../")
+	if err == nil && parent.Path() == parentPath {
+		return parent, nil
+	}
+	// locations that don't resolve "..", ie: s3 with DisableKeyNormalization, are created from the file system
+	return location.FileSystem().NewLocation(location.Volume(), parentPath)
+}
+
+// Child returns the sub-location of location named name, ie: s3://bucket/path/to/ for Child(s3://bucket/path/, "to").
+// name may have a trailing slash but must be a single path segment, other than "." or "..".
+func Child(location vfs.Location, name string) (vfs.Location, error) {
+	name = RemoveTrailingSlash(name)
+	if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid child location name %q: must be a single path segment", name)
+	}
+	return location.NewLocation(name + "/")
+}
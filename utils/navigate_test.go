@@ -0,0 +1,67 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/backend/mem"
+	"github.com/c2fo/vfs/v5/backend/s3"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type navigateSuite struct {
+	suite.Suite
+}
+
+func (s *navigateSuite) location(fs vfs.FileSystem, path string) vfs.Location {
+	location, err := fs.NewLocation("volume", path)
+	s.Require().NoError(err)
+	return location
+}
+
+func (s *navigateSuite) TestParent() {
+	parent, err := utils.Parent(s.location(mem.NewFileSystem(), "/path/to/"))
+	s.NoError(err)
+	s.Equal("/path/", parent.Path())
+	s.Equal("volume", parent.Volume())
+
+	parent, err = utils.Parent(parent)
+	s.NoError(err)
+	s.Equal("/", parent.Path())
+
+	_, err = utils.Parent(parent)
+	s.Equal(utils.ErrNoParent, err)
+}
+
+func (s *navigateSuite) TestParent_Unresolved() {
+	fs := s3.NewFileSystem().WithOptions(s3.Options{DisableKeyNormalization: true})
+	parent, err := utils.Parent(s.location(fs, "/path//to/"))
+	s.NoError(err)
+	s.Equal("/path//", parent.Path(), "parent found without resolving ..")
+}
+
+func (s *navigateSuite) TestChild() {
+	location := s.location(mem.NewFileSystem(), "/path/")
+	child, err := utils.Child(location, "to")
+	s.NoError(err)
+	s.Equal("/path/to/", child.Path())
+
+	child, err = utils.Child(location, "to/")
+	s.NoError(err)
+	s.Equal("/path/to/", child.Path(), "trailing slash is optional")
+
+	for _, name := range []string{"", ".", "..", "to/sub", "/to"} {
+		_, err = utils.Child(location, name)
+		s.Error(err, name)
+	}
+}
+
+func TestNavigate(t *testing.T) {
+	suite.Run(t, new(navigateSuite))
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// PurgeReport is the outcome of Purge.
+type PurgeReport struct {
+	// DryRun is true if nothing was actually deleted.
+	DryRun bool
+	// Deleted are the files deleted or, for a dry run, that would have been.
+	Deleted []vfs.File
+	// Bytes is the total size of the Deleted files.
+	Bytes uint64
+	// Failed are the files that should have been deleted but couldn't be, with the error for each.
+	Failed []BatchResult
+}
+
+// Purge deletes the files at location last modified more than olderThan ago whose names match pattern, or all of
+// them if pattern is nil, ie: for retention jobs.  A dry run only reports the files that would be deleted.  As with
+// vfs.Location.List, files in sub-locations aren't included.  Files are deleted with BatchDelete, so a failed delete
+// doesn't stop the others, see PurgeReport.Failed.  The returned error is for failures to list the location or find
+// the age of its files, in which case nothing is deleted.
+func Purge(location vfs.Location, olderThan time.Duration, pattern *regexp.Regexp, dryRun bool) (PurgeReport, error) {
+	report := PurgeReport{DryRun: dryRun}
+	var names []string
+	var err error
+	if pattern != nil {
+		names, err = location.ListByRegex(pattern)
+	} else {
+		names, err = location.List()
+	}
+	if err != nil {
+		return report, err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	var ops []BatchOp
+	for _, name := range names {
+		file, err := location.NewFile(name)
+		if err != nil {
+			return report, err
+		}
+		lastModified, err := file.LastModified()
+		if err != nil {
+			return report, err
+		}
+		if !lastModified.Before(cutoff) {
+			continue
+		}
+		ops = append(ops, BatchOp{Source: file})
+	}
+
+	if dryRun {
+		for _, op := range ops {
+			size, err := op.Source.Size()
+			if err != nil {
+				return report, err
+			}
+			report.Deleted = append(report.Deleted, op.Source)
+			report.Bytes += size
+		}
+		return report, nil
+	}
+
+	for _, result := range BatchDelete(ops, 0) {
+		if !result.OK() {
+			report.Failed = append(report.Failed, result)
+			continue
+		}
+		report.Deleted = append(report.Deleted, result.Op.Source)
+		report.Bytes += result.Bytes
+	}
+	return report, nil
+}
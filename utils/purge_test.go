@@ -0,0 +1,105 @@
+package utils_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+type purgeTestSuite struct {
+	suite.Suite
+	dir      string
+	location vfs.Location
+}
+
+func (ts *purgeTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "purge_test")
+	ts.NoError(err)
+	ts.dir = dir
+	ts.location, err = _os.NewFileSystem().NewLocation("", dir+"/")
+	ts.NoError(err)
+
+	old := time.Now().Add(-48 * time.Hour)
+	for name, modTime := range map[string]time.Time{
+		"old.log":     old,
+		"old.csv":     old,
+		"new.log":     time.Now(),
+		"sub/old.log": old,
+	} {
+		name = filepath.Join(dir, name)
+		ts.NoError(os.MkdirAll(filepath.Dir(name), 0755))
+		ts.NoError(ioutil.WriteFile(name, []byte(filepath.Base(name)), 0644))
+		ts.NoError(os.Chtimes(name, modTime, modTime))
+	}
+}
+
+func (ts *purgeTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *purgeTestSuite) names(files []vfs.File) []string {
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (ts *purgeTestSuite) remaining() []string {
+	names, err := ts.location.List()
+	ts.NoError(err)
+	sort.Strings(names)
+	return names
+}
+
+func (ts *purgeTestSuite) TestPurge() {
+	report, err := utils.Purge(ts.location, 24*time.Hour, nil, false)
+	ts.NoError(err)
+	ts.False(report.DryRun)
+	ts.Equal([]string{"old.csv", "old.log"}, ts.names(report.Deleted))
+	ts.Equal(uint64(14), report.Bytes)
+	ts.Empty(report.Failed)
+	ts.Equal([]string{"new.log"}, ts.remaining())
+
+	_, err = os.Stat(ts.dir + "/sub/old.log")
+	ts.NoError(err, "files in sub-locations aren't purged")
+}
+
+func (ts *purgeTestSuite) TestPattern() {
+	report, err := utils.Purge(ts.location, 24*time.Hour, regexp.MustCompile(`\.log$`), false)
+	ts.NoError(err)
+	ts.Equal([]string{"old.log"}, ts.names(report.Deleted))
+	ts.Equal([]string{"new.log", "old.csv"}, ts.remaining())
+}
+
+func (ts *purgeTestSuite) TestDryRun() {
+	report, err := utils.Purge(ts.location, 24*time.Hour, nil, true)
+	ts.NoError(err)
+	ts.True(report.DryRun)
+	ts.Equal([]string{"old.csv", "old.log"}, ts.names(report.Deleted))
+	ts.Equal(uint64(14), report.Bytes)
+	ts.Equal([]string{"new.log", "old.csv", "old.log"}, ts.remaining(), "nothing is deleted in a dry run")
+}
+
+func (ts *purgeTestSuite) TestMissingLocation() {
+	location, err := _os.NewFileSystem().NewLocation("", ts.dir+"/missing/")
+	ts.NoError(err)
+	report, err := utils.Purge(location, 0, nil, false)
+	ts.NoError(err, "a location that doesn't exist has nothing to purge")
+	ts.Empty(report.Deleted)
+}
+
+func TestPurge(t *testing.T) {
+	suite.Run(t, new(purgeTestSuite))
+}
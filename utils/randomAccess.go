@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"io"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// RandomAccessReader reads a file at any offset and knows its size, as readers of formats that read a footer or
+// directory at the end of the file first need, ie: zip.NewReader(r, r.Size()) and parquet readers.
+type RandomAccessReader interface {
+	io.ReaderAt
+	io.Closer
+	// Size returns the size of the file in bytes when the reader was opened.
+	Size() int64
+}
+
+// randomAccessOpener is implemented by files which open their own RandomAccessReader, ie: s3.File.
+type randomAccessOpener interface {
+	OpenRandomAccess() (RandomAccessReader, error)
+}
+
+// OpenRandomAccess returns a RandomAccessReader of file, getting its size once, so that reading the end of the file
+// doesn't read the rest of it.  Files that open their own reader, ie: s3.File, do; otherwise files that can read a
+// range directly, ie: os.File, are read with ReadRange, and the reader is safe for concurrent use.  Any other file is
+// read by seeking, as for NewReaderAt, so it must not be read elsewhere while the reader is in use.
+func OpenRandomAccess(file vfs.File) (RandomAccessReader, error) {
+	if opener, ok := file.(randomAccessOpener); ok {
+		return opener.OpenRandomAccess()
+	}
+	size, err := file.Size()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := file.(rangeReader); ok {
+		return &rangeReaderAt{file: file, size: int64(size)}, nil
+	}
+	return &seekingReaderAt{ReaderAt: NewReaderAt(file), size: int64(size)}, nil
+}
+
+// rangeReaderAt is a RandomAccessReader reading each range with ReadRange.
+type rangeReaderAt struct {
+	file vfs.File
+	size int64
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *rangeReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	data, err := ReadRange(r.file, off, int64(len(p)))
+	n := copy(p, data)
+	if err == nil && n < len(p) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Size implements RandomAccessReader.
+func (r *rangeReaderAt) Size() int64 {
+	return r.size
+}
+
+// Close implements io.Closer.  Ranges are read independently of the file, so there's nothing to close.
+func (r *rangeReaderAt) Close() error {
+	return nil
+}
+
+// seekingReaderAt is a RandomAccessReader for files which can only be read by seeking.
+type seekingReaderAt struct {
+	*ReaderAt
+	size int64
+}
+
+// Size implements RandomAccessReader.
+func (r *seekingReaderAt) Size() int64 {
+	return r.size
+}
@@ -0,0 +1,95 @@
+package utils_test
+
+import (
+	"archive/zip"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_mem "github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+type randomAccessSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (s *randomAccessSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "randomAccess_test")
+	s.NoError(err)
+	s.dir = dir
+}
+
+func (s *randomAccessSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *randomAccessSuite) TestReadAt() {
+	for _, fs := range []vfs.FileSystem{_os.NewFileSystem(), _mem.NewFileSystem()} {
+		file, err := fs.NewFile("", s.dir+"/file.txt")
+		s.NoError(err)
+		_, err = file.Write([]byte("0123456789"))
+		s.NoError(err)
+		s.NoError(file.Close())
+
+		r, err := utils.OpenRandomAccess(file)
+		s.NoError(err)
+		s.Equal(int64(10), r.Size(), fs.Name())
+
+		p := make([]byte, 4)
+		n, err := r.ReadAt(p, 6)
+		s.NoError(err)
+		s.Equal("6789", string(p[:n]), fs.Name())
+		n, err = r.ReadAt(p, 8)
+		s.Equal(io.EOF, err)
+		s.Equal("89", string(p[:n]), fs.Name())
+		n, err = r.ReadAt(p, 10)
+		s.Equal(io.EOF, err)
+		s.Zero(n, fs.Name())
+		n, err = r.ReadAt(p, 0)
+		s.NoError(err)
+		s.Equal("0123", string(p[:n]), fs.Name())
+		s.NoError(r.Close())
+	}
+}
+
+func (s *randomAccessSuite) TestZip() {
+	file, err := _os.NewFileSystem().NewFile("", s.dir+"/archive.zip")
+	s.NoError(err)
+	zw := zip.NewWriter(file)
+	w, err := zw.Create("hello.txt")
+	s.NoError(err)
+	_, err = w.Write([]byte("hello"))
+	s.NoError(err)
+	s.NoError(zw.Close())
+	s.NoError(file.Close())
+
+	r, err := utils.OpenRandomAccess(file)
+	s.NoError(err)
+	zr, err := zip.NewReader(r, r.Size())
+	s.NoError(err)
+	s.Len(zr.File, 1)
+	rc, err := zr.File[0].Open()
+	s.NoError(err)
+	contents, err := ioutil.ReadAll(rc)
+	s.NoError(err)
+	s.Equal("hello", string(contents))
+	s.NoError(rc.Close())
+}
+
+func (s *randomAccessSuite) TestMissingFile() {
+	file, err := _os.NewFileSystem().NewFile("", s.dir+"/missing.txt")
+	s.NoError(err)
+	_, err = utils.OpenRandomAccess(file)
+	s.Error(err)
+}
+
+func TestRandomAccess(t *testing.T) {
+	suite.Run(t, new(randomAccessSuite))
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"errors"
+	"io"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// rangeReader is implemented by files that can read a range of bytes without reading the whole file, ie: s3.File (with
+// a ranged GET) and os.File.
+type rangeReader interface {
+	ReadRange(offset, length int64) ([]byte, error)
+}
+
+// ReadRange returns up to length bytes of file starting at offset.  Fewer than length bytes are returned if the range
+// extends past the end of the file, and io.EOF if offset is at or beyond it.  Files that don't support reading a range
+// directly are read using Seek, after which the file is closed to reset its cursor.
+func ReadRange(file vfs.File, offset, length int64) ([]byte, error) {
+	if offset < 0 || length < 1 {
+		return nil, errors.New("non-negative offset and positive length are required")
+	}
+	if r, ok := file.(rangeReader); ok {
+		return r.ReadRange(offset, length)
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	buf := make([]byte, length)
+	n, err := io.ReadFull(file, buf)
+	if err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// Head returns the first n bytes of file, or the whole file if it's smaller.
+func Head(file vfs.File, n int64) ([]byte, error) {
+	data, err := ReadRange(file, 0, n)
+	if err == io.EOF {
+		return []byte{}, nil
+	}
+	return data, err
+}
+
+// Tail returns the last n bytes of file, or the whole file if it's smaller.
+func Tail(file vfs.File, n int64) ([]byte, error) {
+	if n < 1 {
+		return nil, errors.New("positive n is required")
+	}
+	size, err := file.Size()
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		return []byte{}, nil
+	}
+	if uint64(n) > size {
+		n = int64(size)
+	}
+	return ReadRange(file, int64(size)-n, n)
+}
@@ -0,0 +1,87 @@
+package utils_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_mem "github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type rangeSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (r *rangeSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "range_test")
+	r.NoError(err)
+	r.dir = dir
+}
+
+func (r *rangeSuite) TearDownTest() {
+	r.NoError(os.RemoveAll(r.dir))
+}
+
+func (r *rangeSuite) newFile(fs vfs.FileSystem, contents string) vfs.File {
+	file, err := fs.NewFile("", r.dir+"/file.txt")
+	r.NoError(err)
+	if contents != "" {
+		_, err = file.Write([]byte(contents))
+		r.NoError(err)
+	}
+	r.NoError(file.Close())
+	return file
+}
+
+func (r *rangeSuite) TestHeadAndTail() {
+	// os.File reads ranges directly, mem.File falls back to Seek
+	for _, fs := range []vfs.FileSystem{_os.NewFileSystem(), _mem.NewFileSystem()} {
+		file := r.newFile(fs, "hello world")
+
+		data, err := utils.Head(file, 5)
+		r.NoError(err)
+		r.Equal("hello", string(data), fs.Name())
+
+		data, err = utils.Tail(file, 5)
+		r.NoError(err)
+		r.Equal("world", string(data), fs.Name())
+
+		data, err = utils.Tail(file, 100)
+		r.NoError(err)
+		r.Equal("hello world", string(data), fs.Name())
+
+		data, err = utils.ReadRange(file, 4, 3)
+		r.NoError(err)
+		r.Equal("o w", string(data), fs.Name())
+
+		// the fallback leaves the file readable from the start
+		contents, err := ioutil.ReadAll(file)
+		r.NoError(err)
+		r.Equal("hello world", string(contents), fs.Name())
+		r.NoError(file.Close())
+	}
+}
+
+func (r *rangeSuite) TestReadRange_PastEnd() {
+	file := r.newFile(_os.NewFileSystem(), "hello")
+	_, err := utils.ReadRange(file, 10, 5)
+	r.Equal(io.EOF, err)
+
+	_, err = utils.ReadRange(file, 0, 0)
+	r.EqualError(err, "non-negative offset and positive length are required")
+}
+
+func TestRange(t *testing.T) {
+	suite.Run(t, new(rangeSuite))
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"io"
+	"sync"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// ReaderAt implements io.ReaderAt for a vfs.File by seeking and reading it, ie: for servers that read a file in
+// chunks at offsets.  A read starting where the previous one ended doesn't seek, so reading the file in order streams
+// it with a single request where the backend reads over the network.  It's safe for concurrent use, though
+// concurrent reads are serialized.
+type ReaderAt struct {
+	mu     sync.Mutex
+	file   vfs.File
+	offset int64
+}
+
+// NewReaderAt returns a ReaderAt reading file, which must not be read or seeked elsewhere while it's in use.  Close
+// the ReaderAt to close file.
+func NewReaderAt(file vfs.File) *ReaderAt {
+	return &ReaderAt{file: file}
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *ReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if off != r.offset {
+		if _, err := r.file.Seek(off, io.SeekStart); err != nil {
+			return 0, err
+		}
+		r.offset = off
+	}
+	n, err := io.ReadFull(r.file, p)
+	r.offset += int64(n)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Close closes the file.
+func (r *ReaderAt) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
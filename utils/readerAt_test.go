@@ -0,0 +1,68 @@
+package utils_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_mem "github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type readerAtSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (s *readerAtSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "readerAt_test")
+	s.NoError(err)
+	s.dir = dir
+}
+
+func (s *readerAtSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *readerAtSuite) TestReadAt() {
+	for _, fs := range []vfs.FileSystem{_os.NewFileSystem(), _mem.NewFileSystem()} {
+		file, err := fs.NewFile("", s.dir+"/file.txt")
+		s.NoError(err)
+		_, err = file.Write([]byte("0123456789"))
+		s.NoError(err)
+		s.NoError(file.Close())
+
+		r := utils.NewReaderAt(file)
+		for _, read := range []struct {
+			offset   int64
+			size     int
+			expected string
+			err      error
+		}{
+			{0, 4, "0123", nil},
+			{4, 4, "4567", nil},
+			{2, 3, "234", nil},
+			{8, 4, "89", io.EOF},
+			{10, 4, "", io.EOF},
+		} {
+			buf := make([]byte, read.size)
+			n, err := r.ReadAt(buf, read.offset)
+			s.Equal(read.err, err, fs.Name())
+			s.Equal(read.expected, string(buf[:n]), fs.Name())
+		}
+		s.NoError(r.Close(), fs.Name())
+	}
+}
+
+func TestReaderAt(t *testing.T) {
+	suite.Run(t, new(readerAtSuite))
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// ErrNoMatchingFile is returned by FirstFile, NewestFile and OldestFile when no file at the location matches.
+var ErrNoMatchingFile = errors.New("no matching file found")
+
+// FirstFile returns the file at location (not including sub-locations) whose name sorts first of those matching
+// regex, or ErrNoMatchingFile if none do.  A nil regex matches every file.
+func FirstFile(location vfs.Location, regex *regexp.Regexp) (vfs.File, error) {
+	files, err := matchingFiles(location, regex)
+	if err != nil {
+		return nil, err
+	}
+	return files[0], nil
+}
+
+// NewestFile returns the most recently modified file at location (not including sub-locations) of those matching
+// regex, ie: the latest of a series of daily extracts, or ErrNoMatchingFile if none do.  A nil regex matches every
+// file.  Of files modified at the same time, the one whose name sorts first is returned.
+func NewestFile(location vfs.Location, regex *regexp.Regexp) (vfs.File, error) {
+	return selectByModTime(location, regex, true)
+}
+
+// OldestFile returns the least recently modified file at location (not including sub-locations) of those matching
+// regex, or ErrNoMatchingFile if none do.  A nil regex matches every file.  Of files modified at the same time, the
+// one whose name sorts first is returned.
+func OldestFile(location vfs.Location, regex *regexp.Regexp) (vfs.File, error) {
+	return selectByModTime(location, regex, false)
+}
+
+// selectByModTime returns the newest or, if newest is false, the oldest of the files at location matching regex.
+func selectByModTime(location vfs.Location, regex *regexp.Regexp, newest bool) (vfs.File, error) {
+	files, err := matchingFiles(location, regex)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := files[0]
+	selectedTime, err := selected.LastModified()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files[1:] {
+		modTime, err := file.LastModified()
+		if err != nil {
+			return nil, err
+		}
+		if (newest && modTime.After(*selectedTime)) || (!newest && modTime.Before(*selectedTime)) {
+			selected, selectedTime = file, modTime
+		}
+	}
+	return selected, nil
+}
+
+// matchingFiles returns the files at location whose names match regex sorted by name, or ErrNoMatchingFile if there
+// are none.
+func matchingFiles(location vfs.Location, regex *regexp.Regexp) ([]vfs.File, error) {
+	files, err := listFiles(location)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := files[:0]
+	for _, file := range files {
+		if regex == nil || regex.MatchString(file.Name()) {
+			matched = append(matched, file)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, ErrNoMatchingFile
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Name() < matched[j].Name() })
+	return matched, nil
+}
@@ -0,0 +1,96 @@
+package utils_test
+
+import (
+	"io/ioutil"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type selectSuite struct {
+	suite.Suite
+	dir      string
+	location vfs.Location
+}
+
+func (s *selectSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "select_test")
+	s.NoError(err)
+	s.dir = utils.EnsureTrailingSlash(dir)
+	s.location, err = _os.NewFileSystem().NewLocation("", s.dir)
+	s.NoError(err)
+}
+
+func (s *selectSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *selectSuite) writeFile(name string, age time.Duration) {
+	file, err := s.location.NewFile(name)
+	s.NoError(err)
+	_, err = file.Write([]byte(name))
+	s.NoError(err)
+	s.NoError(file.Close())
+	modTime := time.Now().Add(-age)
+	s.NoError(os.Chtimes(file.Path(), modTime, modTime))
+}
+
+func (s *selectSuite) TestSelect() {
+	s.writeFile("extract_2.csv", 2*time.Hour)
+	s.writeFile("extract_1.csv", time.Hour)
+	s.writeFile("extract_3.csv", 3*time.Hour)
+	s.writeFile("readme.txt", 0)
+	s.writeFile("archive.txt", 4*time.Hour)
+	extracts := regexp.MustCompile(`^extract_.*\.csv$`)
+
+	file, err := utils.FirstFile(s.location, extracts)
+	s.NoError(err)
+	s.Equal("extract_1.csv", file.Name())
+
+	file, err = utils.NewestFile(s.location, extracts)
+	s.NoError(err)
+	s.Equal("extract_1.csv", file.Name())
+
+	file, err = utils.OldestFile(s.location, extracts)
+	s.NoError(err)
+	s.Equal("extract_3.csv", file.Name())
+
+	file, err = utils.FirstFile(s.location, nil)
+	s.NoError(err)
+	s.Equal("archive.txt", file.Name(), "nil regex matches every file")
+
+	file, err = utils.NewestFile(s.location, nil)
+	s.NoError(err)
+	s.Equal("readme.txt", file.Name())
+
+	file, err = utils.OldestFile(s.location, nil)
+	s.NoError(err)
+	s.Equal("archive.txt", file.Name())
+}
+
+func (s *selectSuite) TestNoMatchingFile() {
+	s.writeFile("readme.txt", 0)
+	none := regexp.MustCompile(`\.csv$`)
+
+	_, err := utils.FirstFile(s.location, none)
+	s.Equal(utils.ErrNoMatchingFile, err)
+	_, err = utils.NewestFile(s.location, none)
+	s.Equal(utils.ErrNoMatchingFile, err)
+	_, err = utils.OldestFile(s.location, none)
+	s.Equal(utils.ErrNoMatchingFile, err)
+}
+
+func TestSelect(t *testing.T) {
+	suite.Run(t, new(selectSuite))
+}
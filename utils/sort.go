@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"sort"
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// SortKey is the file attribute ListSorted and SortFiles order files by.
+type SortKey int
+
+const (
+	// SortByName orders files by name.
+	SortByName SortKey = iota
+	// SortBySize orders files by size in bytes.
+	SortBySize
+	// SortByModTime orders files by last modified time.
+	SortByModTime
+)
+
+// SortOptions determines the order of the files returned by ListSorted and SortFiles.
+type SortOptions struct {
+	By SortKey
+	// Descending reverses the order, ie: largest or most recently modified first.  Files with the same size or
+	// modification time are always ordered by name, ascending.
+	Descending bool
+}
+
+// ListSorted returns the files at location (not including sub-locations) in the order determined by opts.  Locations
+// that list files with their metadata (s3.Location) are sorted by size or modification time from the listing itself,
+// without a request per file, and s3 listings are already in name order as they're paged.
+func ListSorted(location vfs.Location, opts SortOptions) ([]vfs.File, error) {
+	files, err := listFiles(location)
+	if err != nil {
+		return nil, err
+	}
+	if err := SortFiles(files, opts); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// SortFiles sorts files in place in the order determined by opts.  Each file's size or modification time is fetched
+// once, before sorting; the first error fetching one is returned and files is left unsorted.
+func SortFiles(files []vfs.File, opts SortOptions) error {
+	sorted := make([]sortedFile, len(files))
+	for i, file := range files {
+		sorted[i] = sortedFile{file: file, name: file.Name()}
+		switch opts.By {
+		case SortBySize:
+			size, err := file.Size()
+			if err != nil {
+				return err
+			}
+			sorted[i].size = size
+		case SortByModTime:
+			modTime, err := file.LastModified()
+			if err != nil {
+				return err
+			}
+			sorted[i].modTime = *modTime
+		}
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		switch {
+		case opts.By == SortBySize && a.size != b.size:
+			return (a.size < b.size) != opts.Descending
+		case opts.By == SortByModTime && !a.modTime.Equal(b.modTime):
+			return a.modTime.Before(b.modTime) != opts.Descending
+		case opts.By == SortByName:
+			return (a.name < b.name) != opts.Descending
+		}
+		return a.name < b.name
+	})
+	for i := range sorted {
+		files[i] = sorted[i].file
+	}
+	return nil
+}
+
+// sortedFile is a file with the attributes SortFiles orders it by.
+type sortedFile struct {
+	file    vfs.File
+	name    string
+	size    uint64
+	modTime time.Time
+}
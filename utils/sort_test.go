@@ -0,0 +1,88 @@
+package utils_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/mocks"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type sortSuite struct {
+	suite.Suite
+	dir      string
+	location vfs.Location
+}
+
+func (s *sortSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "sort_test")
+	s.NoError(err)
+	s.dir = utils.EnsureTrailingSlash(dir)
+	s.location, err = _os.NewFileSystem().NewLocation("", s.dir)
+	s.NoError(err)
+
+	s.writeFile("b.txt", 3, time.Hour)
+	s.writeFile("a.txt", 1, 2*time.Hour)
+	s.writeFile("d.txt", 2, 0)
+	s.writeFile("c.txt", 3, time.Hour)
+}
+
+func (s *sortSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *sortSuite) writeFile(name string, size int, age time.Duration) {
+	file, err := s.location.NewFile(name)
+	s.NoError(err)
+	_, err = file.Write([]byte(strings.Repeat("x", size)))
+	s.NoError(err)
+	s.NoError(file.Close())
+	modTime := time.Now().Add(-age).Truncate(time.Second)
+	s.NoError(os.Chtimes(file.Path(), modTime, modTime))
+}
+
+func (s *sortSuite) list(opts utils.SortOptions) []string {
+	files, err := utils.ListSorted(s.location, opts)
+	s.NoError(err)
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	return names
+}
+
+func (s *sortSuite) TestListSorted() {
+	s.Equal([]string{"a.txt", "b.txt", "c.txt", "d.txt"}, s.list(utils.SortOptions{}))
+	s.Equal([]string{"d.txt", "c.txt", "b.txt", "a.txt"}, s.list(utils.SortOptions{Descending: true}))
+	s.Equal([]string{"a.txt", "d.txt", "b.txt", "c.txt"}, s.list(utils.SortOptions{By: utils.SortBySize}))
+	s.Equal([]string{"b.txt", "c.txt", "d.txt", "a.txt"},
+		s.list(utils.SortOptions{By: utils.SortBySize, Descending: true}), "ties are ordered by name ascending")
+	s.Equal([]string{"a.txt", "b.txt", "c.txt", "d.txt"}, s.list(utils.SortOptions{By: utils.SortByModTime}))
+	s.Equal([]string{"d.txt", "b.txt", "c.txt", "a.txt"},
+		s.list(utils.SortOptions{By: utils.SortByModTime, Descending: true}))
+}
+
+func (s *sortSuite) TestSortFilesError() {
+	file := &mocks.File{}
+	file.On("Name").Return("broken.txt")
+	file.On("Size").Return(uint64(0), errors.New("no size"))
+	files := []vfs.File{file}
+
+	s.EqualError(utils.SortFiles(files, utils.SortOptions{By: utils.SortBySize}), "no size")
+}
+
+func TestSort(t *testing.T) {
+	suite.Run(t, new(sortSuite))
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// SpillFilePrefix begins the name of every temp file created by a SpillBuffer.
+const SpillFilePrefix = "vfs-spill-"
+
+// SpillBuffer is a write buffer that holds data in memory until it grows beyond a threshold, then moves ("spills") it
+// to a temp file.  This caps the memory used to buffer large writes while still avoiding temp files for small ones.
+type SpillBuffer struct {
+	threshold int64
+	dir       string
+	mem       *bytes.Buffer
+	file      *os.File
+	size      int64
+}
+
+// NewSpillBuffer returns an empty SpillBuffer that spills to a temp file in dir (os.TempDir() if empty) once more than
+// threshold bytes have been written.  A threshold of 0 or less never spills.  Close the buffer once it's no longer
+// needed to remove any temp file.
+func NewSpillBuffer(threshold int64, dir string) *SpillBuffer {
+	return &SpillBuffer{
+		threshold: threshold,
+		dir:       dir,
+		mem:       GetWriteBuffer(),
+	}
+}
+
+// Write implements io.Writer.
+func (b *SpillBuffer) Write(p []byte) (int, error) {
+	if b.file == nil && b.threshold > 0 && int64(b.mem.Len()+len(p)) > b.threshold {
+		if err := b.spill(); err != nil {
+			return 0, err
+		}
+	}
+
+	var n int
+	var err error
+	if b.file != nil {
+		// write at the end regardless of where a Reader left the file's offset
+		n, err = b.file.WriteAt(p, b.size)
+	} else {
+		n, err = b.mem.Write(p)
+	}
+	b.size += int64(n)
+	return n, err
+}
+
+// ReadFrom implements io.ReaderFrom, writing everything read from r to the buffer.
+func (b *SpillBuffer) ReadFrom(r io.Reader) (int64, error) {
+	// hide ReadFrom from Copy so it writes through Write rather than calling back into this method
+	return Copy(struct{ io.Writer }{b}, r)
+}
+
+// Len returns the number of bytes written to the buffer.
+func (b *SpillBuffer) Len() int64 {
+	return b.size
+}
+
+// Spilled returns true if the buffer's data has been moved to a temp file.
+func (b *SpillBuffer) Spilled() bool {
+	return b.file != nil
+}
+
+// Head returns up to the first n bytes written to the buffer, ie: for content type detection.
+func (b *SpillBuffer) Head(n int) ([]byte, error) {
+	if b.file == nil {
+		data := b.mem.Bytes()
+		if len(data) > n {
+			data = data[:n]
+		}
+		return data, nil
+	}
+
+	data := make([]byte, n)
+	read, err := b.file.ReadAt(data, 0)
+	if err == io.EOF {
+		err = nil
+	}
+	return data[:read], err
+}
+
+// Reader returns a reader of everything written to the buffer, from the start.  The reader also implements io.Seeker
+// and io.ReaderAt, so it can be read in parts concurrently, ie: by s3manager.Uploader.  The buffer must not be written
+// to while the reader is in use, but can be afterward, ie: to append to data that has already been uploaded.
+func (b *SpillBuffer) Reader() (io.ReadSeeker, error) {
+	if b.file == nil {
+		return bytes.NewReader(b.mem.Bytes()), nil
+	}
+	if _, err := b.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return b.file, nil
+}
+
+// Close releases the buffer's memory and removes its temp file, if any.  The buffer must not be used afterwards.
+func (b *SpillBuffer) Close() error {
+	PutWriteBuffer(b.mem)
+	b.mem = nil
+	if b.file == nil {
+		return nil
+	}
+
+	name := b.file.Name()
+	closeErr := b.file.Close()
+	b.file = nil
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return closeErr
+}
+
+// spill moves the data buffered in memory to a new temp file.
+func (b *SpillBuffer) spill() error {
+	file, err := ioutil.TempFile(b.dir, SpillFilePrefix)
+	if err != nil {
+		return err
+	}
+	if _, err := b.mem.WriteTo(file); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	b.file = file
+	PutWriteBuffer(b.mem)
+	b.mem = nil
+	return nil
+}
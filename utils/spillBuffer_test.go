@@ -0,0 +1,112 @@
+package utils_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type spillBufferSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (s *spillBufferSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "spill_test")
+	s.NoError(err)
+	s.dir = dir
+}
+
+func (s *spillBufferSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *spillBufferSuite) tempFiles() []os.FileInfo {
+	files, err := ioutil.ReadDir(s.dir)
+	s.NoError(err)
+	return files
+}
+
+func (s *spillBufferSuite) readAll(buf *utils.SpillBuffer) string {
+	reader, err := buf.Reader()
+	s.NoError(err)
+	data, err := ioutil.ReadAll(reader)
+	s.NoError(err)
+	return string(data)
+}
+
+func (s *spillBufferSuite) TestInMemory() {
+	buf := utils.NewSpillBuffer(10, s.dir)
+	_, err := buf.Write([]byte("hello"))
+	s.NoError(err)
+	s.False(buf.Spilled())
+	s.Equal(int64(5), buf.Len())
+	s.Empty(s.tempFiles())
+
+	head, err := buf.Head(3)
+	s.NoError(err)
+	s.Equal("hel", string(head))
+	s.Equal("hello", s.readAll(buf))
+	s.NoError(buf.Close())
+}
+
+func (s *spillBufferSuite) TestSpill() {
+	buf := utils.NewSpillBuffer(10, s.dir)
+	_, err := buf.Write([]byte("hello "))
+	s.NoError(err)
+	n, err := buf.ReadFrom(strings.NewReader("world, spilled"))
+	s.NoError(err)
+	s.Equal(int64(14), n)
+	s.True(buf.Spilled())
+	s.Equal(int64(20), buf.Len())
+	s.Len(s.tempFiles(), 1)
+
+	head, err := buf.Head(5)
+	s.NoError(err)
+	s.Equal("hello", string(head))
+	head, err = buf.Head(100)
+	s.NoError(err)
+	s.Equal("hello world, spilled", string(head))
+	s.Equal("hello world, spilled", s.readAll(buf))
+
+	s.NoError(buf.Close())
+	s.Empty(s.tempFiles(), "temp file should be removed on Close")
+}
+
+func (s *spillBufferSuite) TestWriteAfterRead() {
+	buf := utils.NewSpillBuffer(5, s.dir)
+	_, err := buf.Write([]byte("hello world"))
+	s.NoError(err)
+	s.True(buf.Spilled())
+	reader, err := buf.Reader()
+	s.NoError(err)
+	_, err = reader.Seek(2, io.SeekStart)
+	s.NoError(err)
+
+	_, err = buf.Write([]byte(", appended"))
+	s.NoError(err)
+	s.Equal("hello world, appended", s.readAll(buf), "writes append regardless of the reader's offset")
+	s.NoError(buf.Close())
+}
+
+func (s *spillBufferSuite) TestNoThreshold() {
+	buf := utils.NewSpillBuffer(0, s.dir)
+	_, err := buf.Write([]byte(strings.Repeat("a", 1024)))
+	s.NoError(err)
+	s.False(buf.Spilled())
+	s.NoError(buf.Close())
+}
+
+func TestSpillBuffer(t *testing.T) {
+	suite.Run(t, new(spillBufferSuite))
+}
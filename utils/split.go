@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// concatenator is implemented by files that can concatenate parts more efficiently than streaming them, ie: s3.File,
+// which copies s3 parts server-side.
+type concatenator interface {
+	Concat(parts ...vfs.File) error
+}
+
+// Split writes the contents of file to part files of at most partSize bytes in the same location, named
+// <name>.part0001, <name>.part0002, etc, returning them in order.  An empty file produces no parts.
+func Split(file vfs.File, partSize int64) ([]vfs.File, error) {
+	if partSize < 1 {
+		return nil, errors.New("positive partSize is required")
+	}
+
+	reader := bufio.NewReader(file)
+	var parts []vfs.File
+	for i := 1; ; i++ {
+		// only create another part if there's something left to write to it
+		if _, err := reader.Peek(1); err == io.EOF {
+			break
+		} else if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+
+		part, err := file.Location().NewFile(fmt.Sprintf("%s.part%04d", file.Name(), i))
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		if _, err := io.CopyN(part, reader, partSize); err != nil && err != io.EOF {
+			_ = part.Close()
+			_ = file.Close()
+			return nil, err
+		}
+		if err := part.Close(); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+
+	return parts, file.Close()
+}
+
+// Concat writes the contents of parts, in order, to target, ie: to reassemble the parts produced by Split.  Targets that
+// support it (s3.File) concatenate the parts without downloading them where possible; otherwise each part is streamed
+// to target in turn.
+func Concat(target vfs.File, parts ...vfs.File) error {
+	if len(parts) == 0 {
+		return errors.New("at least one part is required")
+	}
+	if c, ok := target.(concatenator); ok {
+		return c.Concat(parts...)
+	}
+
+	for _, part := range parts {
+		if _, err := Copy(target, part); err != nil {
+			_ = part.Close()
+			return err
+		}
+		if err := part.Close(); err != nil {
+			return err
+		}
+	}
+	return target.Close()
+}
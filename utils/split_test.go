@@ -0,0 +1,83 @@
+package utils_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type splitSuite struct {
+	suite.Suite
+	dir      string
+	location vfs.Location
+}
+
+func (s *splitSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "split_test")
+	s.NoError(err)
+	s.dir = dir
+	s.location, err = _os.NewFileSystem().NewLocation("", utils.EnsureTrailingSlash(dir))
+	s.NoError(err)
+}
+
+func (s *splitSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *splitSuite) newFile(name, contents string) vfs.File {
+	file, err := s.location.NewFile(name)
+	s.NoError(err)
+	_, err = file.Write([]byte(contents))
+	s.NoError(err)
+	s.NoError(file.Close())
+	return file
+}
+
+func (s *splitSuite) TestSplitAndConcat() {
+	file := s.newFile("data.txt", "hello world")
+
+	parts, err := utils.Split(file, 4)
+	s.NoError(err)
+	s.Len(parts, 3)
+	s.Equal("data.txt.part0001", parts[0].Name())
+	s.Equal("data.txt.part0003", parts[2].Name())
+	contents, err := ioutil.ReadFile(parts[2].Path())
+	s.NoError(err)
+	s.Equal("rld", string(contents))
+
+	joined, err := s.location.NewFile("joined.txt")
+	s.NoError(err)
+	s.NoError(utils.Concat(joined, parts...))
+	contents, err = ioutil.ReadFile(joined.Path())
+	s.NoError(err)
+	s.Equal("hello world", string(contents))
+}
+
+func (s *splitSuite) TestSplit_ExactMultiple() {
+	parts, err := utils.Split(s.newFile("data.txt", "12345678"), 4)
+	s.NoError(err)
+	s.Len(parts, 2, "no empty trailing part expected")
+}
+
+func (s *splitSuite) TestSplit_Errors() {
+	file, err := s.location.NewFile("data.txt")
+	s.NoError(err)
+	_, err = utils.Split(file, 0)
+	s.EqualError(err, "positive partSize is required")
+
+	s.EqualError(utils.Concat(file), "at least one part is required")
+}
+
+func TestSplit(t *testing.T) {
+	suite.Run(t, new(splitSuite))
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"github.com/c2fo/vfs/v5"
+)
+
+// statter is implemented by files which can return all of their metadata from a single call, ie: s3.File and os.File.
+type statter interface {
+	Stat() (*vfs.FileInfo, error)
+}
+
+// Stat returns a FileInfo for file, which implements os.FileInfo.  Files that support it (s3.File, os.File) gather it
+// from a single call to the underlying file system; otherwise it's built from the file's Size and LastModified, with a
+// mode of 0644.
+func Stat(file vfs.File) (*vfs.FileInfo, error) {
+	if s, ok := file.(statter); ok {
+		return s.Stat()
+	}
+
+	size, err := file.Size()
+	if err != nil {
+		return nil, err
+	}
+	lastModified, err := file.LastModified()
+	if err != nil {
+		return nil, err
+	}
+	return vfs.NewFileInfo(file.Name(), int64(size), *lastModified, 0644), nil
+}
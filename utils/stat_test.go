@@ -0,0 +1,63 @@
+package utils_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_mem "github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+/**********************************
+ ************TESTS*****************
+ **********************************/
+
+type statSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (s *statSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "stat_test")
+	s.NoError(err)
+	s.dir = dir
+}
+
+func (s *statSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *statSuite) TestStat() {
+	// os.File stats directly, mem.File falls back to Size and LastModified
+	for _, fs := range []vfs.FileSystem{_os.NewFileSystem(), _mem.NewFileSystem()} {
+		file, err := fs.NewFile("", s.dir+"/file.txt")
+		s.NoError(err)
+		_, err = file.Write([]byte("hello world"))
+		s.NoError(err)
+		s.NoError(file.Close())
+
+		info, err := utils.Stat(file)
+		s.NoError(err, fs.Name())
+		s.Equal("file.txt", info.Name(), fs.Name())
+		s.Equal(int64(11), info.Size(), fs.Name())
+		s.False(info.IsDir(), fs.Name())
+		s.False(info.ModTime().IsZero(), fs.Name())
+		lastModified, err := file.LastModified()
+		s.NoError(err)
+		s.True(lastModified.Equal(info.ModTime()), fs.Name())
+
+		missing, err := fs.NewFile("", s.dir+"/missing.txt")
+		s.NoError(err)
+		_, err = utils.Stat(missing)
+		s.Error(err, fs.Name())
+	}
+}
+
+func TestStat(t *testing.T) {
+	suite.Run(t, new(statSuite))
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// treeFile is a file found by TreeHash, with its path relative to the location being hashed.
+type treeFile struct {
+	name string
+	file vfs.File
+	hash string
+}
+
+// TreeHash returns a hex encoded sha256 fingerprint of the files at location and, where its backend can list
+// sub-locations (see Walk), beneath it, ie: for comparing datasets or deployments across backends. The fingerprint
+// covers each file's path relative to location and the sha256 of its contents, taken in path order, so it doesn't
+// depend on the backend or the order files are listed in.  Empty sub-locations don't affect it.  Up to concurrency
+// files (4 if concurrency is 0 or less) are read in parallel.  The first error listing or reading a file is returned.
+func TreeHash(location vfs.Location, concurrency int) (string, error) {
+	names, found, err := manifestFiles(location)
+	if err != nil {
+		return "", err
+	}
+	files := make([]*treeFile, 0, len(names))
+	for _, name := range names {
+		files = append(files, &treeFile{name: name, file: found[name]})
+	}
+
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	work := make(chan *treeFile)
+	errs := make(chan error, len(files))
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range work {
+				hash, err := fileChecksum(f.file)
+				if err != nil {
+					errs <- fmt.Errorf("unable to hash %s: %s", f.file, err.Error())
+					continue
+				}
+				f.hash = hash
+			}
+		}()
+	}
+	for _, f := range files {
+		work <- f
+	}
+	close(work)
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return "", err
+	}
+
+	tree := sha256.New()
+	for _, f := range files {
+		// a NUL can't appear in a path, so it unambiguously ends the name
+		_, _ = io.WriteString(tree, f.name+"\x00"+f.hash+"\n")
+	}
+	return hex.EncodeToString(tree.Sum(nil)), nil
+}
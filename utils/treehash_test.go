@@ -0,0 +1,84 @@
+package utils_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+type treeHashTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (ts *treeHashTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "treehash_test")
+	ts.NoError(err)
+	ts.dir = dir
+}
+
+func (ts *treeHashTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+// tree writes files, named by their relative path, beneath a new os location named name.
+func (ts *treeHashTestSuite) tree(name string, files map[string]string) vfs.Location {
+	for path, contents := range files {
+		path = filepath.Join(ts.dir, name, path)
+		ts.NoError(os.MkdirAll(filepath.Dir(path), 0700))
+		ts.NoError(ioutil.WriteFile(path, []byte(contents), 0600))
+	}
+	location, err := _os.NewFileSystem().NewLocation("", filepath.Join(ts.dir, name)+"/")
+	ts.Require().NoError(err)
+	return location
+}
+
+func (ts *treeHashTestSuite) hash(location vfs.Location) string {
+	hash, err := utils.TreeHash(location, 2)
+	ts.NoError(err)
+	return hash
+}
+
+func (ts *treeHashTestSuite) TestTreeHash() {
+	files := map[string]string{"a.txt": "a", "sub/b.txt": "b", "sub/deeper/c.txt": "c"}
+	hash := ts.hash(ts.tree("one", files))
+	ts.Len(hash, 64)
+	ts.Equal(hash, ts.hash(ts.tree("two", files)), "identical trees hash the same")
+
+	ts.NoError(os.MkdirAll(filepath.Join(ts.dir, "two", "empty"), 0700))
+	ts.Equal(hash, ts.hash(ts.tree("two", nil)), "empty sub-locations are ignored")
+
+	files["sub/deeper/c.txt"] = "changed"
+	ts.NotEqual(hash, ts.hash(ts.tree("changed", files)), "contents are hashed")
+
+	ts.NotEqual(hash, ts.hash(ts.tree("renamed", map[string]string{"a.txt": "a", "sub/b.txt": "b", "sub/c.txt": "c"})),
+		"paths are hashed")
+}
+
+func (ts *treeHashTestSuite) TestAcrossBackends() {
+	osLocation := ts.tree("os", map[string]string{"a.txt": "a", "b.txt": "b"})
+
+	memLocation, err := mem.NewFileSystem().NewLocation("volume", "/path/")
+	ts.NoError(err)
+	for name, contents := range map[string]string{"b.txt": "b", "a.txt": "a"} {
+		file, err := memLocation.NewFile(name)
+		ts.NoError(err)
+		_, err = file.Write([]byte(contents))
+		ts.NoError(err)
+		ts.NoError(file.Close())
+	}
+
+	ts.Equal(ts.hash(osLocation), ts.hash(memLocation))
+}
+
+func TestTreeHash(t *testing.T) {
+	suite.Run(t, new(treeHashTestSuite))
+}
@@ -1,9 +1,12 @@
 package utils
 
 import (
+	"context"
+	"crypto/md5"
 	"errors"
 	"fmt"
 	"io"
+	"path"
 	"regexp"
 	"strings"
 	"time"
@@ -70,14 +73,14 @@ func ValidateRelativeLocationPath(name string) error {
 	return nil
 }
 
-// GetFileURI returns a File URI
+// GetFileURI returns a File URI, percent-encoded by vfs.BuildURI
 func GetFileURI(f vfs.File) string {
-	return fmt.Sprintf("%s://%s%s", f.Location().FileSystem().Scheme(), f.Location().Volume(), f.Path())
+	return vfs.BuildURI(f.Location().FileSystem().Scheme(), f.Location().Volume(), f.Path())
 }
 
-// GetLocationURI returns a Location URI
+// GetLocationURI returns a Location URI, percent-encoded by vfs.BuildURI
 func GetLocationURI(l vfs.Location) string {
-	return fmt.Sprintf("%s://%s%s", l.FileSystem().Scheme(), l.Volume(), l.Path())
+	return vfs.BuildURI(l.FileSystem().Scheme(), l.Volume(), l.Path())
 }
 
 // EnsureTrailingSlash is like AddTrailingSlash but will only ever use / since it's use for web uri's, never an Windows OS path.
@@ -96,9 +99,26 @@ func EnsureLeadingSlash(dir string) string {
 	return "/" + dir
 }
 
+// RelPath returns name, a path from a client of a served location (ie: over sftp or grpc), relative to that location:
+// cleaned, with any ".." elements resolved so it can't escape the location, and without a leading slash.  "" is
+// returned for the location itself.
+func RelPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
 // TouchCopy is a wrapper around io.Copy which ensures that even empty source files (reader) will get written as an
 // empty file. It guarantees a Write() call on the target file.
 func TouchCopy(writer, reader vfs.File) error {
+	return TouchCopyWithOptions(writer, reader, vfs.CopyOptions{})
+}
+
+// TouchCopyWithOptions is TouchCopy honoring the Context, BufferSize and Progress of options, see CopyWithOptions.
+func TouchCopyWithOptions(writer, reader vfs.File, options vfs.CopyOptions) error {
+	if options.Context != nil {
+		if err := options.Context.Err(); err != nil {
+			return err
+		}
+	}
 	if size, err := reader.Size(); err != nil {
 		return err
 	} else if size == 0 {
@@ -107,13 +127,197 @@ func TouchCopy(writer, reader vfs.File) error {
 			return err
 		}
 	} else {
-		if _, err := io.Copy(writer, reader); err != nil {
+		if _, err := CopyWithOptions(writer, reader, options); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// CopyWithOptions is Copy through a buffer of options.BufferSize bytes, if set, calling options.Progress after each
+// write, waiting on options.BandwidthLimiter before each write and stopping with the error of options.Context once
+// it's done.  With none of those set, ie: the CopyOptions of NewCopyOptions(), it's simply Copy.
+func CopyWithOptions(dst io.Writer, src io.Reader, options vfs.CopyOptions) (int64, error) {
+	// a Context that can't be canceled, ie: context.Background(), has a nil Done channel
+	cancelable := options.Context != nil && options.Context.Done() != nil
+	if !cancelable && options.Progress == nil && options.BufferSize <= 0 && options.BandwidthLimiter == nil {
+		return Copy(dst, src)
+	}
+	writer := &copyWriter{dst: dst, options: options}
+	if options.BufferSize <= 0 {
+		return Copy(writer, src)
+	}
+	// hide WriterTo so the copy goes through the buffer
+	return io.CopyBuffer(writer, struct{ io.Reader }{src}, make([]byte, options.BufferSize))
+}
+
+// copyWriter is the writer CopyWithOptions copies to, checking the context, limiting bandwidth and reporting progress
+// on each write.
+type copyWriter struct {
+	dst     io.Writer
+	options vfs.CopyOptions
+	copied  int64
+}
+
+// Write implements io.Writer.
+func (w *copyWriter) Write(p []byte) (int, error) {
+	ctx := w.options.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if err := w.options.BandwidthLimiter.WaitN(ctx, len(p)); err != nil {
+		return 0, err
+	}
+	n, err := w.dst.Write(p)
+	w.copied += int64(n)
+	if w.options.Progress != nil && n > 0 {
+		w.options.Progress(w.copied)
+	}
+	return n, err
+}
+
+// CopyVerificationError is returned by VerifyCopy and VerifyChecksum when the target of a copy doesn't match its source.
+type CopyVerificationError struct {
+	// Source and Target are the URIs of the files compared.
+	Source string
+	Target string
+	// Field is what didn't match: "size" or "checksum".
+	Field string
+	// SourceValue and TargetValue are the values of Field for the source and target.
+	SourceValue string
+	TargetValue string
+}
+
+// Error implements error.
+func (e *CopyVerificationError) Error() string {
+	return fmt.Sprintf("copy of %s to %s failed verification: source %s is %s but target %s is %s",
+		e.Source, e.Target, e.Field, e.SourceValue, e.Field, e.TargetValue)
+}
+
+// contentMD5er is implemented by files whose backend stores the MD5 of their contents, ie: s3 objects whose ETag is
+// one, so it can be compared without reading them.  ok is false if the file has none.
+type contentMD5er interface {
+	ContentMD5() (md5 string, ok bool, err error)
+}
+
+// VerifyCopy returns an error unless the target file exists and has the same size and contents as the source file,
+// comparing their MD5 checksums.  Backends call it after copying, and before deleting the source, when moving a file by
+// copying it so that a move whose copy was only partially (or wrongly) written can't lose data.  The MD5 stored by
+// either file's backend is used where there is one; otherwise the file is read in full and closed.  A target that
+// doesn't match returns a *CopyVerificationError.
+func VerifyCopy(source, target vfs.File) error {
+	sourceSize, err := source.Size()
+	if err != nil {
+		return fmt.Errorf("unable to verify move of %s: %s", source, err.Error())
+	}
+	targetSize, err := target.Size()
+	if err != nil {
+		return fmt.Errorf("unable to verify move of %s to %s: %s", source, target, err.Error())
+	}
+	if sourceSize != targetSize {
+		return &CopyVerificationError{
+			Source:      source.String(),
+			Target:      target.String(),
+			Field:       "size",
+			SourceValue: fmt.Sprintf("%d bytes", sourceSize),
+			TargetValue: fmt.Sprintf("%d bytes", targetSize),
+		}
+	}
+	if sourceSize == 0 {
+		return nil
+	}
+
+	sourceMD5, err := fileMD5(source)
+	if err != nil {
+		return fmt.Errorf("unable to verify move of %s: %s", source, err.Error())
+	}
+	targetMD5, err := fileMD5(target)
+	if err != nil {
+		return fmt.Errorf("unable to verify move of %s to %s: %s", source, target, err.Error())
+	}
+	return checksumsMatch(source, target, sourceMD5, targetMD5)
+}
+
+// VerifyChecksum returns an error unless the source and target files have the same contents, comparing their sha256
+// checksums.  Backends call it after copying when vfs.CopyOptions.VerifyChecksum is set.  Both files are read in full
+// and closed.  A target that doesn't match returns a *CopyVerificationError.
+func VerifyChecksum(source, target vfs.File) error {
+	sourceChecksum, err := fileChecksum(source)
+	if err != nil {
+		return fmt.Errorf("unable to verify copy of %s: %s", source, err.Error())
+	}
+	targetChecksum, err := fileChecksum(target)
+	if err != nil {
+		return fmt.Errorf("unable to verify copy of %s to %s: %s", source, target, err.Error())
+	}
+	return checksumsMatch(source, target, sourceChecksum, targetChecksum)
+}
+
+// checksumsMatch returns a *CopyVerificationError unless the checksums of source and target are the same.
+func checksumsMatch(source, target vfs.File, sourceChecksum, targetChecksum string) error {
+	if sourceChecksum != targetChecksum {
+		return &CopyVerificationError{
+			Source:      source.String(),
+			Target:      target.String(),
+			Field:       "checksum",
+			SourceValue: sourceChecksum,
+			TargetValue: targetChecksum,
+		}
+	}
+	return nil
+}
+
+// fileMD5 returns the hex encoded MD5 checksum of the file's contents, stored by its backend if it has one.
+func fileMD5(file vfs.File) (string, error) {
+	if f, ok := file.(contentMD5er); ok {
+		sum, ok, err := f.ContentMD5()
+		if err != nil || ok {
+			return sum, err
+		}
+	}
+	return fileDigest(file, md5.New())
+}
+
+// ResolveConflict returns the file that should be copied or moved to in place of target, according to policy.  skip is
+// true if, under vfs.ConflictSkip, target already exists and shouldn't be copied to; target is returned in that case.
+// Under vfs.ConflictOverwrite, target is returned without checking whether it exists.
+func ResolveConflict(target vfs.File, policy vfs.ConflictPolicy) (resolved vfs.File, skip bool, err error) {
+	if policy == vfs.ConflictOverwrite {
+		return target, false, nil
+	}
+
+	exists, err := target.Exists()
+	if err != nil || !exists {
+		return target, false, err
+	}
+
+	switch policy {
+	case vfs.ConflictSkip:
+		return target, true, nil
+	case vfs.ConflictError:
+		return nil, false, vfs.ErrFileExists
+	case vfs.ConflictRenameWithSuffix:
+		ext := path.Ext(target.Name())
+		base := strings.TrimSuffix(target.Name(), ext)
+		location := target.Location()
+		for i := 1; exists; i++ {
+			resolved, err = location.NewFile(fmt.Sprintf("%s-%d%s", base, i, ext))
+			if err != nil {
+				return nil, false, err
+			}
+			if exists, err = resolved.Exists(); err != nil {
+				return nil, false, err
+			}
+		}
+		return resolved, false, nil
+	default:
+		return nil, false, fmt.Errorf("unknown conflict policy %d", policy)
+	}
+}
+
 // UpdateLastModifiedByMoving is used by some backends' Touch() method when a file already exists.
 func UpdateLastModifiedByMoving(file vfs.File) error {
 	// setup a tempfile
@@ -1,15 +1,20 @@
 package utils_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"io"
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/c2fo/vfs/v5"
 	_os "github.com/c2fo/vfs/v5/backend/os"
 	"github.com/c2fo/vfs/v5/mocks"
 	"github.com/c2fo/vfs/v5/utils"
@@ -160,6 +165,40 @@ func (s *utilsTest) TestRemoveLeadingSlash() {
 	}
 }
 
+func (s *utilsTest) TestRelPath() {
+	tests := []slashTest{
+		{
+			path:     "/some/path/file.txt",
+			expected: "some/path/file.txt",
+			message:  "absolute path - made relative",
+		},
+		{
+			path:     "some//path/./file.txt",
+			expected: "some/path/file.txt",
+			message:  "relative path - cleaned",
+		},
+		{
+			path:     "/../../etc/passwd",
+			expected: "etc/passwd",
+			message:  "parent elements - can't escape the location",
+		},
+		{
+			path:     "/",
+			expected: "",
+			message:  "just a slash - the location itself",
+		},
+		{
+			path:     "",
+			expected: "",
+			message:  "empty string - the location itself",
+		},
+	}
+
+	for _, slashtest := range tests {
+		s.Equal(slashtest.expected, utils.RelPath(slashtest.path), slashtest.message)
+	}
+}
+
 type pathValidationTest struct {
 	path         string
 	passExpected bool
@@ -502,6 +541,7 @@ func (s *utilsTest) TestTouchCopy() {
 	s.NoError(err, "unexpected error resetting vfs.File reader")
 	err = utils.TouchCopy(writer, reader)
 	s.NoError(err, "unexpected error running TouchCopy()")
+	s.NoError(writer.Close(), "unexpected error closing writer")
 	defer func() {
 		err := writer.Delete()
 		if err != nil {
@@ -524,6 +564,7 @@ func (s *utilsTest) TestTouchCopy() {
 
 	err = utils.TouchCopy(writer, reader)
 	s.NoError(err, "unexpected error running TouchCopy()")
+	s.NoError(writer.Close(), "unexpected error closing writer")
 	fi, err = os.Stat(writer.Path())
 	s.NoError(err, "file should exist, so no error")
 	s.NotEqual(fi, 0, "file should have a non-zero byte size")
@@ -537,6 +578,178 @@ func (s *utilsTest) TestTouchCopy() {
 
 }
 
+func (s *utilsTest) TestVerifyCopy() {
+	newFile := func(uri string, size uint64, err error) *mocks.File {
+		f := &mocks.File{}
+		f.On("Size").Return(size, err)
+		f.On("String").Return(uri)
+		return f
+	}
+
+	s.NoError(utils.VerifyCopy(newFile("file:///src.txt", 0, nil), newFile("s3://bucket/dst.txt", 0, nil)))
+
+	err := utils.VerifyCopy(newFile("file:///src.txt", 10, nil), newFile("s3://bucket/dst.txt", 5, nil))
+	s.EqualError(err, "copy of file:///src.txt to s3://bucket/dst.txt failed verification: source size is 10 bytes but "+
+		"target size is 5 bytes")
+	s.Equal(&utils.CopyVerificationError{
+		Source:      "file:///src.txt",
+		Target:      "s3://bucket/dst.txt",
+		Field:       "size",
+		SourceValue: "10 bytes",
+		TargetValue: "5 bytes",
+	}, err)
+
+	err = utils.VerifyCopy(newFile("file:///src.txt", 10, nil), newFile("s3://bucket/dst.txt", 0, errors.New("not found")))
+	s.EqualError(err, "unable to verify move of file:///src.txt to s3://bucket/dst.txt: not found")
+
+	err = utils.VerifyCopy(newFile("file:///src.txt", 0, errors.New("no size")), newFile("s3://bucket/dst.txt", 0, nil))
+	s.EqualError(err, "unable to verify move of file:///src.txt: no size")
+
+	// files of the same size are compared by checksum
+	dir, err := ioutil.TempDir("", "verify_copy")
+	s.NoError(err)
+	defer func() { s.NoError(os.RemoveAll(dir)) }()
+	newOSFile := func(name, contents string) vfs.File {
+		s.NoError(ioutil.WriteFile(path.Join(dir, name), []byte(contents), 0600))
+		file, err := _os.NewFileSystem().NewFile("", path.Join(dir, name))
+		s.NoError(err)
+		return file
+	}
+	source := newOSFile("src.txt", "contents")
+	s.NoError(utils.VerifyCopy(source, newOSFile("same.txt", "contents")))
+
+	different := newOSFile("different.txt", "CONTENTS")
+	err = utils.VerifyCopy(source, different)
+	s.IsType(&utils.CopyVerificationError{}, err)
+	s.Equal("checksum", err.(*utils.CopyVerificationError).Field)
+
+	// an MD5 stored by the target's backend is used in place of reading it
+	stored := md5File{File: newFile("s3://bucket/dst.txt", 8, nil), md5: "98bf7d8c15784f0a3d63204441e1e2aa"}
+	s.NoError(utils.VerifyCopy(source, stored))
+	stored.md5 = "00000000000000000000000000000000"
+	s.IsType(&utils.CopyVerificationError{}, utils.VerifyCopy(source, stored))
+}
+
+// md5File is a vfs.File whose backend stores the MD5 of its contents.
+type md5File struct {
+	vfs.File
+	md5 string
+}
+
+func (f md5File) ContentMD5() (string, bool, error) {
+	return f.md5, true, nil
+}
+
+func (s *utilsTest) TestCopyWithOptions() {
+	var progress []int64
+	dst := &bytes.Buffer{}
+	n, err := utils.CopyWithOptions(dst, strings.NewReader("0123456789"), vfs.NewCopyOptions(
+		vfs.WithBufferSize(4),
+		vfs.WithProgress(func(copied int64) { progress = append(progress, copied) }),
+	))
+	s.NoError(err)
+	s.EqualValues(10, n)
+	s.Equal("0123456789", dst.String())
+	s.Equal([]int64{4, 8, 10}, progress, "progress is reported for each buffer")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	dst.Reset()
+	_, err = utils.CopyWithOptions(dst, strings.NewReader("0123456789"), vfs.NewCopyOptions(vfs.WithContext(ctx)))
+	s.Equal(context.Canceled, err)
+	s.Empty(dst.String(), "nothing is written once canceled")
+
+	// without options, the copy is left to dst's ReadFrom
+	readerFrom := &readerFromBuffer{}
+	n, err = utils.CopyWithOptions(readerFrom, struct{ io.Reader }{strings.NewReader("0123456789")}, vfs.NewCopyOptions())
+	s.NoError(err)
+	s.EqualValues(10, n)
+	s.True(readerFrom.readFrom)
+	s.Equal("0123456789", readerFrom.String())
+}
+
+// readerFromBuffer is a bytes.Buffer that records whether its ReadFrom was called.
+type readerFromBuffer struct {
+	bytes.Buffer
+	readFrom bool
+}
+
+func (b *readerFromBuffer) ReadFrom(r io.Reader) (int64, error) {
+	b.readFrom = true
+	return b.Buffer.ReadFrom(r)
+}
+
+func (s *utilsTest) TestVerifyChecksum() {
+	dir, err := ioutil.TempDir("", "verify_checksum")
+	s.NoError(err)
+	defer func() { s.NoError(os.RemoveAll(dir)) }()
+
+	newFile := func(name, contents string) vfs.File {
+		s.NoError(ioutil.WriteFile(path.Join(dir, name), []byte(contents), 0600))
+		file, err := _os.NewFileSystem().NewFile("", path.Join(dir, name))
+		s.NoError(err)
+		return file
+	}
+	source := newFile("src.txt", "contents")
+
+	s.NoError(utils.VerifyChecksum(source, newFile("same.txt", "contents")))
+
+	different := newFile("different.txt", "CONTENTS")
+	err = utils.VerifyChecksum(source, different)
+	s.IsType(&utils.CopyVerificationError{}, err)
+	s.Equal("checksum", err.(*utils.CopyVerificationError).Field)
+
+	missing, err := _os.NewFileSystem().NewFile("", path.Join(dir, "missing.txt"))
+	s.NoError(err)
+	s.Error(utils.VerifyChecksum(source, missing))
+}
+
+func (s *utilsTest) TestResolveConflict() {
+	target := &mocks.File{}
+	target.On("Name").Return("report.csv")
+	target.On("Exists").Return(true, nil)
+
+	// overwrite never checks for an existing file
+	overwrite := &mocks.File{}
+	resolved, skip, err := utils.ResolveConflict(overwrite, vfs.ConflictOverwrite)
+	s.NoError(err)
+	s.False(skip)
+	s.Equal(overwrite, resolved)
+	overwrite.AssertNotCalled(s.T(), "Exists")
+
+	// no conflict when the target doesn't exist
+	missing := &mocks.File{}
+	missing.On("Exists").Return(false, nil)
+	resolved, skip, err = utils.ResolveConflict(missing, vfs.ConflictError)
+	s.NoError(err)
+	s.False(skip)
+	s.Equal(missing, resolved)
+
+	resolved, skip, err = utils.ResolveConflict(target, vfs.ConflictSkip)
+	s.NoError(err)
+	s.True(skip)
+	s.Equal(target, resolved)
+
+	_, _, err = utils.ResolveConflict(target, vfs.ConflictError)
+	s.Equal(vfs.ErrFileExists, err)
+
+	taken := &mocks.File{}
+	taken.On("Exists").Return(true, nil)
+	free := &mocks.File{}
+	free.On("Exists").Return(false, nil)
+	location := &mocks.Location{}
+	location.On("NewFile", "report-1.csv").Return(taken, nil)
+	location.On("NewFile", "report-2.csv").Return(free, nil)
+	target.On("Location").Return(location)
+	resolved, skip, err = utils.ResolveConflict(target, vfs.ConflictRenameWithSuffix)
+	s.NoError(err)
+	s.False(skip)
+	s.Equal(free, resolved)
+
+	_, _, err = utils.ResolveConflict(target, vfs.ConflictPolicy(99))
+	s.EqualError(err, "unknown conflict policy 99")
+}
+
 func TestUtils(t *testing.T) {
 	suite.Run(t, new(utilsTest))
 }
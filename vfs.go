@@ -1,8 +1,10 @@
 package vfs
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"regexp"
 	"time"
 )
@@ -133,7 +135,7 @@ type Location interface {
 
 	// URI returns the fully qualified absolute URI for the Location.  IE, s3://bucket/some/path/
 	//
-	// URI's for locations must always end with a slash.
+	// URI's for locations must always end with a slash.  The path is percent-encoded, see BuildURI.
 	URI() string
 }
 
@@ -159,7 +161,8 @@ type File interface {
 	//   * If the file already exists at the location, the contents will be overwritten with the current file's contents.
 	//   * CopyToLocation will Close both the source and target Files which therefore can't be appended to without first
 	//     calling Seek() to move the cursor to the end of the file.
-	CopyToLocation(location Location) (File, error)
+	//   * opts configure the copy, see CopyOptions.
+	CopyToLocation(location Location, opts ...CopyOption) (File, error)
 
 	// CopyToFile will copy the current file to the provided file instance.
 	//
@@ -168,7 +171,8 @@ type File interface {
 	//   * If the file already exists, the contents will be overwritten with the current file's contents.
 	//   * CopyToFile will Close both the source and target Files which therefore can't be appended to without first
 	//     calling Seek() to move the cursor to the end of the file.
-	CopyToFile(file File) error
+	//   * opts configure the copy, see CopyOptions.
+	CopyToFile(file File, opts ...CopyOption) error
 
 	// MoveToLocation will move the current file to the provided location.
 	//
@@ -180,7 +184,8 @@ type File interface {
 	//   * If the file already exists, the contents will be overwritten with the current file's contents.
 	//   * MoveToLocation will Close both the source and target Files which therefore can't be appended to without first
 	//     calling Seek() to move the cursor to the end of the file.
-	MoveToLocation(location Location) (File, error)
+	//   * opts configure the copy made by moves between schemes, see CopyOptions.
+	MoveToLocation(location Location, opts ...CopyOption) (File, error)
 
 	// MoveToFile will move the current file to the provided file instance.
 	//
@@ -188,7 +193,8 @@ type File interface {
 	//   * The current instance of the file will be removed.
 	//   * MoveToFile will Close both the source and target Files which therefore can't be appended to without first
 	//     calling Seek() to move the cursor to the end of the file.
-	MoveToFile(file File) error
+	//   * opts configure the copy made by moves between schemes, see CopyOptions.
+	MoveToFile(file File, opts ...CopyOption) error
 
 	// Delete unlinks the File on the file system.
 	Delete() error
@@ -215,9 +221,94 @@ type File interface {
 	Touch() error
 
 	// URI returns the fully qualified absolute URI for the File.  IE, s3://bucket/some/path/to/file.txt
+	//
+	// The path is percent-encoded, see BuildURI, so ParseURI returns the file's Path.
 	URI() string
 }
 
+// FileInfo holds the metadata of a File, as gathered by a single call to the underlying file system.  It implements
+// os.FileInfo (the same interface as io/fs.FileInfo), so it can be passed to code written against the standard
+// library's types.  Fields which the file system does not support are left at their zero value.
+type FileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	mode    os.FileMode
+
+	// ContentType is the MIME type of the file, ie: text/plain.
+	ContentType string
+	// ContentEncoding is the encoding the file's contents are stored with, ie: gzip.
+	ContentEncoding string
+	// ETag is an identifier of the file's contents, typically a hash.
+	ETag string
+	// StorageClass is the storage tier of the file, ie: STANDARD or GLACIER.
+	StorageClass string
+}
+
+// NewFileInfo returns a FileInfo for a file with the given base name, size, modification time and mode.  File systems
+// without permissions, such as s3, use 0644 for files.  A mode with os.ModeDir set describes a directory.
+func NewFileInfo(name string, size int64, modTime time.Time, mode os.FileMode) *FileInfo {
+	return &FileInfo{name: name, size: size, modTime: modTime, mode: mode}
+}
+
+// Name returns the base name of the file.
+func (fi *FileInfo) Name() string { return fi.name }
+
+// Size returns the size of the file in bytes.
+func (fi *FileInfo) Size() int64 { return fi.size }
+
+// Mode returns the file's mode and permission bits.
+func (fi *FileInfo) Mode() os.FileMode { return fi.mode }
+
+// ModTime returns the file's last modified time.
+func (fi *FileInfo) ModTime() time.Time { return fi.modTime }
+
+// IsDir reports whether the FileInfo describes a directory.
+func (fi *FileInfo) IsDir() bool { return fi.mode.IsDir() }
+
+// Sys returns nil; backend-specific metadata is held in FileInfo's exported fields instead.
+func (fi *FileInfo) Sys() interface{} { return nil }
+
+// ExtendedAttributer is implemented by files which expose backend-specific attributes, keyed by name, such as an s3
+// object's ETag and VersionId, ie: for recording a file's identity for auditing or conditional operations.
+type ExtendedAttributer interface {
+	ExtendedAttributes() (map[string]string, error)
+}
+
+// ReadSeekCloser groups the Read, Seek and Close methods, as returned by backends' OpenReader methods.  It's the same
+// as io.ReadSeekCloser, which requires go 1.16.
+type ReadSeekCloser interface {
+	io.Reader
+	io.Seeker
+	io.Closer
+}
+
+// ConflictPolicy determines what happens when CopyToLocation or MoveToLocation finds a file of the same name already at
+// the target location.  Backends that support it take a ConflictPolicy in their Options.
+type ConflictPolicy int
+
+const (
+	// ConflictOverwrite replaces the existing file (the default).
+	ConflictOverwrite ConflictPolicy = iota
+	// ConflictSkip leaves the existing file, and the source file, as they are.  The existing file is returned.
+	ConflictSkip
+	// ConflictError returns an ErrFileExists error.
+	ConflictError
+	// ConflictRenameWithSuffix copies to the first free name with a numeric suffix, ie: file-1.txt, file-2.txt, etc.
+	ConflictRenameWithSuffix
+)
+
+// ErrFileExists is returned by CopyToLocation and MoveToLocation under ConflictError when the target file exists.
+var ErrFileExists = errors.New("file already exists at the target location")
+
+// ErrQuotaExceeded is returned by file systems that enforce a quota, ie: vfsquota.FileSystem, for writes and copies
+// that would take the bytes stored beyond it.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// ErrLocked is returned by Lock when another holder's lease on the file hasn't expired, and by a Lease's methods once
+// it has been lost to another holder.
+var ErrLocked = errors.New("file is locked")
+
 // Options are structs that contain various options specific to the file system
 type Options interface{}
 
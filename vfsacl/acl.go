@@ -0,0 +1,137 @@
+package vfsacl
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/vfshooks"
+)
+
+// Access is a set of kinds of access to a URI.
+type Access int
+
+// The kinds of Access, combined with |, ie: Read | Write.
+const (
+	Read Access = 1 << iota
+	Write
+	Delete
+
+	All = Read | Write | Delete
+)
+
+// Effect is whether a Rule allows or denies access.
+type Effect int
+
+// The Effects of a Rule.
+const (
+	// Allow grants access, unless a Deny rule denies it.
+	Allow Effect = iota
+	// Deny denies access, regardless of any Allow rule.
+	Deny
+)
+
+// Rule allows or denies Access to the URIs it matches.
+type Rule struct {
+	Effect Effect
+	// Scheme and Volume match URIs with that scheme and volume, ie: "s3" and "mybucket".  Empty values match any.
+	Scheme string
+	Volume string
+	// Prefix matches URIs whose path is, or is beneath, it, ie: "/exports/" or "/exports" match "/exports/report.csv"
+	// but "/exp" and "/exports" don't match "/exports-old/report.csv".  An empty prefix matches any path.
+	Prefix string
+	// Access is the access allowed or denied.
+	Access Access
+}
+
+// matches returns true if the rule applies to uri for access.
+func (r Rule) matches(uri vfs.URI, access Access) bool {
+	return r.Access&access != 0 &&
+		(r.Scheme == "" || r.Scheme == uri.Scheme) &&
+		(r.Volume == "" || r.Volume == uri.Volume) &&
+		hasPathPrefix(uri.Path, r.Prefix)
+}
+
+// hasPathPrefix returns true if path begins with prefix on a path segment boundary: prefix is empty or ends in a slash,
+// or is followed in path by one or by nothing.
+func hasPathPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return prefix == "" || strings.HasSuffix(prefix, "/") || len(path) == len(prefix) || path[len(prefix)] == '/'
+}
+
+// Policy is a vfshooks.Hook which rejects the calls its rules don't allow.
+type Policy struct {
+	rules []Rule
+}
+
+// NewPolicy returns a Policy allowing the access granted by rules.
+func NewPolicy(rules ...Rule) *Policy {
+	return &Policy{rules: rules}
+}
+
+// NewFileSystem returns a vfshooks.FileSystem which rejects the calls to fs that rules don't allow.
+func NewFileSystem(fs vfs.FileSystem, rules ...Rule) *vfshooks.FileSystem {
+	return vfshooks.NewFileSystem(fs, NewPolicy(rules...))
+}
+
+// Allowed returns true if every kind of access in access is allowed to uri.  Invalid URIs aren't allowed any access.
+func (p *Policy) Allowed(uri string, access Access) bool {
+	parsed, err := vfs.ParseURI(uri)
+	if err != nil {
+		return false
+	}
+	for _, kind := range []Access{Read, Write, Delete} {
+		if access&kind != 0 && !p.allowed(parsed, kind) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowed returns true if a single kind of access is allowed to uri.
+func (p *Policy) allowed(uri vfs.URI, access Access) bool {
+	allowed := false
+	for _, rule := range p.rules {
+		if !rule.matches(uri, access) {
+			continue
+		}
+		if rule.Effect == Deny {
+			return false
+		}
+		allowed = true
+	}
+	return allowed
+}
+
+// OnBeforeOp implements vfshooks.Hook, returning an *os.PathError wrapping os.ErrPermission if op isn't allowed.
+func (p *Policy) OnBeforeOp(op vfshooks.Op) error {
+	var access Access
+	switch op.Kind {
+	case vfshooks.KindRead:
+		access = Read
+	case vfshooks.KindWrite:
+		access = Write
+	case vfshooks.KindDelete:
+		access = Delete
+		if op.Target != "" {
+			// moves read their source too
+			access |= Read
+		}
+	default:
+		return nil
+	}
+
+	if !p.Allowed(op.URI, access) {
+		return &os.PathError{Op: op.Name, Path: op.URI, Err: os.ErrPermission}
+	}
+	if op.Target != "" && !p.Allowed(op.Target, Write) {
+		return &os.PathError{Op: op.Name, Path: op.Target, Err: os.ErrPermission}
+	}
+	return nil
+}
+
+// OnAfterOp implements vfshooks.Hook.
+func (p *Policy) OnAfterOp(op vfshooks.Op, duration time.Duration, err error) {}
@@ -0,0 +1,116 @@
+package vfsacl_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/backend/mem"
+	"github.com/c2fo/vfs/v5/vfsacl"
+)
+
+type aclTestSuite struct {
+	suite.Suite
+	fs vfs.FileSystem
+}
+
+func (ts *aclTestSuite) SetupTest() {
+	ts.fs = vfsacl.NewFileSystem(mem.NewFileSystem(),
+		vfsacl.Rule{Scheme: "mem", Volume: "bucket", Prefix: "/exports/", Access: vfsacl.Read | vfsacl.Write},
+		vfsacl.Rule{Scheme: "mem", Volume: "bucket", Prefix: "/exports/tmp/", Access: vfsacl.Delete},
+		vfsacl.Rule{Effect: vfsacl.Deny, Prefix: "/exports/secret/", Access: vfsacl.All},
+		vfsacl.Rule{Scheme: "mem", Volume: "bucket", Prefix: "/public/", Access: vfsacl.Read},
+	)
+}
+
+func (ts *aclTestSuite) newFile(path string) vfs.File {
+	file, err := ts.fs.NewFile("bucket", path)
+	ts.NoError(err)
+	return file
+}
+
+func (ts *aclTestSuite) assertDenied(err error) {
+	ts.Error(err)
+	ts.True(os.IsPermission(err), "denied calls fail with a permission error")
+}
+
+func (ts *aclTestSuite) TestAllowed() {
+	policy := vfsacl.NewPolicy(
+		vfsacl.Rule{Scheme: "s3", Prefix: "/data/", Access: vfsacl.All},
+		vfsacl.Rule{Effect: vfsacl.Deny, Scheme: "s3", Volume: "prod", Access: vfsacl.Delete},
+	)
+	ts.True(policy.Allowed("s3://dev/data/file.txt", vfsacl.Read|vfsacl.Delete))
+	ts.True(policy.Allowed("s3://prod/data/file.txt", vfsacl.Read|vfsacl.Write))
+	ts.False(policy.Allowed("s3://prod/data/file.txt", vfsacl.Delete), "deny overrides allow")
+	ts.False(policy.Allowed("s3://dev/database/file.txt", vfsacl.Read))
+	ts.False(policy.Allowed("gs://dev/data/file.txt", vfsacl.Read), "schemes must match")
+	ts.False(policy.Allowed("/data/file.txt", vfsacl.Read), "invalid uris aren't allowed")
+}
+
+func (ts *aclTestSuite) TestAllowed_PrefixSegments() {
+	policy := vfsacl.NewPolicy(
+		vfsacl.Rule{Prefix: "/data", Access: vfsacl.Read},
+		vfsacl.Rule{Prefix: "/logs/app", Access: vfsacl.Read},
+	)
+	ts.True(policy.Allowed("s3://bucket/data/file.txt", vfsacl.Read))
+	ts.True(policy.Allowed("s3://bucket/data", vfsacl.Read))
+	ts.True(policy.Allowed("s3://bucket/logs/app/today.log", vfsacl.Read))
+	ts.False(policy.Allowed("s3://bucket/database/file.txt", vfsacl.Read), "prefixes match whole segments")
+	ts.False(policy.Allowed("s3://bucket/data-old/file.txt", vfsacl.Read))
+	ts.False(policy.Allowed("s3://bucket/logs/application.log", vfsacl.Read))
+}
+
+func (ts *aclTestSuite) TestReadWrite() {
+	file := ts.newFile("/exports/report.csv")
+	_, err := file.Write([]byte("hello"))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+	ts.assertDenied(file.Delete())
+
+	_, err = ts.newFile("/public/report.csv").Write([]byte("hello"))
+	ts.assertDenied(err)
+	_, err = ts.newFile("/other/report.csv").Exists()
+	ts.assertDenied(err)
+	_, err = ts.newFile("/exports/secret/report.csv").Exists()
+	ts.assertDenied(err)
+
+	location, err := ts.fs.NewLocation("bucket", "/exports/")
+	ts.NoError(err)
+	_, err = location.List()
+	ts.NoError(err)
+	location, err = ts.fs.NewLocation("bucket", "/")
+	ts.NoError(err)
+	_, err = location.List()
+	ts.assertDenied(err)
+}
+
+func (ts *aclTestSuite) TestDelete() {
+	file := ts.newFile("/exports/tmp/scratch.txt")
+	ts.NoError(file.Touch())
+	ts.NoError(file.Delete())
+
+	location, err := ts.fs.NewLocation("bucket", "/exports/")
+	ts.NoError(err)
+	ts.assertDenied(location.DeleteFile("report.csv"))
+}
+
+func (ts *aclTestSuite) TestCopyAndMove() {
+	source := ts.newFile("/exports/tmp/report.csv")
+	ts.NoError(source.Touch())
+
+	ts.NoError(source.CopyToFile(ts.newFile("/exports/copy.csv")))
+	ts.assertDenied(source.CopyToFile(ts.newFile("/public/copy.csv")))
+
+	// moves need delete of the source
+	ts.assertDenied(ts.newFile("/exports/copy.csv").MoveToFile(ts.newFile("/exports/tmp/moved.csv")))
+	ts.NoError(source.MoveToFile(ts.newFile("/exports/moved.csv")))
+}
+
+func TestACL(t *testing.T) {
+	suite.Run(t, new(aclTestSuite))
+}
@@ -0,0 +1,29 @@
+/*
+Package vfsacl restricts the files and locations a vfs.FileSystem can reach to those allowed by a list of rules, as
+defense-in-depth for services that should only ever touch specific buckets or prefixes.
+
+Usage
+
+Wrap a file system with its rules, and use it in its place:
+
+  fs := vfsacl.NewFileSystem(s3.NewFileSystem(),
+      vfsacl.Rule{Scheme: "s3", Volume: "mybucket", Prefix: "/exports/", Access: vfsacl.Read | vfsacl.Write},
+      vfsacl.Rule{Scheme: "s3", Volume: "mybucket", Prefix: "/exports/tmp/", Access: vfsacl.Delete},
+      vfsacl.Rule{Effect: vfsacl.Deny, Prefix: "/exports/secret/", Access: vfsacl.All},
+  )
+  file, err := fs.NewFile("mybucket", "/exports/report.csv")
+
+Rules
+
+A rule matches a URI by its scheme, volume and path prefix; an empty scheme or volume matches any.  Prefixes match
+whole path segments, so "/exports" matches "/exports/report.csv" but not "/exports-old/report.csv".  Access to a URI is allowed if an
+Allow rule grants it and no Deny rule matching the URI denies it; anything not allowed is denied.
+
+Calls are checked by the kind of access they make, see vfshooks.Op.  Reads (including listing and reading metadata)
+need Read, writes and Touch need Write and deletes need Delete.  Copies need Read of the source and moves need Read and
+Delete of it, and both need Write of the target.  Close is always allowed.  A denied call fails, without reaching the
+wrapped file system, with an *os.PathError for which os.IsPermission returns true.
+
+Policy is the vfshooks.Hook doing the checks, for combining with other hooks in a vfshooks.FileSystem.
+*/
+package vfsacl
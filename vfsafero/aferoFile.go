@@ -0,0 +1,237 @@
+package vfsafero
+
+import (
+	"io"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// truncater is implemented by files whose backend supports truncating them (os.File, s3.File).
+type truncater interface {
+	Truncate(size int64) error
+}
+
+// aferoFile implements afero.File for a vfs.File, or, for directories, a vfs.Location.
+type aferoFile struct {
+	name     string
+	file     vfs.File
+	location vfs.Location
+	writing  bool
+	closed   bool
+
+	// size is the size of a file opened for writing, which isn't written to the backend until it's closed
+	size int64
+
+	// entries are the directory's contents, listed on the first call to Readdir, and offset is how many of them have
+	// been returned so far
+	entries []os.FileInfo
+	offset  int
+}
+
+// Name returns the name the file was opened with.
+func (f *aferoFile) Name() string {
+	return f.name
+}
+
+// Read implements io.Reader.
+func (f *aferoFile) Read(p []byte) (int, error) {
+	if err := f.check("read", true); err != nil {
+		return 0, err
+	}
+	return f.file.Read(p)
+}
+
+// ReadAt implements io.ReaderAt using utils.ReadRange, so it doesn't move the file's cursor.
+func (f *aferoFile) ReadAt(p []byte, off int64) (int, error) {
+	if err := f.check("read", true); err != nil {
+		return 0, err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	data, err := utils.ReadRange(f.file, off, int64(len(p)))
+	n := copy(p, data)
+	if err == nil && n < len(p) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (f *aferoFile) Seek(offset int64, whence int) (int64, error) {
+	if err := f.check("seek", true); err != nil {
+		return 0, err
+	}
+	return f.file.Seek(offset, whence)
+}
+
+// Write implements io.Writer.
+func (f *aferoFile) Write(p []byte) (int, error) {
+	if err := f.check("write", true); err != nil {
+		return 0, err
+	}
+	if !f.writing {
+		return 0, &os.PathError{Op: "write", Path: f.name, Err: os.ErrPermission}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// WriteAt implements io.WriterAt where the backend's files do (os.File), writing to the file in place.
+func (f *aferoFile) WriteAt(p []byte, off int64) (int, error) {
+	if err := f.check("write", true); err != nil {
+		return 0, err
+	}
+	if w, ok := f.file.(io.WriterAt); ok && f.writing {
+		n, err := w.WriteAt(p, off)
+		if end := off + int64(n); end > f.size {
+			f.size = end
+		}
+		return n, err
+	}
+	return 0, &os.PathError{Op: "write", Path: f.name, Err: errNotSupported}
+}
+
+// WriteString writes s to the file.
+func (f *aferoFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+// Truncate changes the size of the file where the backend's files support it (os.File, s3.File).
+func (f *aferoFile) Truncate(size int64) error {
+	if err := f.check("truncate", true); err != nil {
+		return err
+	}
+	if t, ok := f.file.(truncater); ok && f.writing {
+		if err := t.Truncate(size); err != nil {
+			return err
+		}
+		f.size = size
+		return nil
+	}
+	return &os.PathError{Op: "truncate", Path: f.name, Err: errNotSupported}
+}
+
+// Sync does nothing; vfs files are written when closed.
+func (f *aferoFile) Sync() error {
+	return f.check("sync", false)
+}
+
+// Stat returns a FileInfo describing the file or directory.  For a file opened for writing, it describes what's been
+// written so far, as that isn't in the backend until the file is closed.
+func (f *aferoFile) Stat() (os.FileInfo, error) {
+	if err := f.check("stat", false); err != nil {
+		return nil, err
+	}
+	if f.location != nil {
+		return dirInfo(f.location), nil
+	}
+	if f.writing {
+		return vfs.NewFileInfo(path.Base(f.name), f.size, time.Now(), 0644), nil
+	}
+	info, err := utils.Stat(f.file)
+	if err != nil {
+		return nil, &os.PathError{Op: "stat", Path: f.name, Err: err}
+	}
+	return info, nil
+}
+
+// Readdir returns up to count FileInfos for the directory's contents, as os.File.Readdir does: with count > 0, io.EOF
+// is returned once there are none left; otherwise all remaining entries are returned.
+func (f *aferoFile) Readdir(count int) ([]os.FileInfo, error) {
+	if err := f.check("readdir", false); err != nil {
+		return nil, err
+	}
+	if f.location == nil {
+		return nil, &os.PathError{Op: "readdir", Path: f.name, Err: errNotDir}
+	}
+	if f.entries == nil {
+		entries, err := listInfo(f.location)
+		if err != nil {
+			return nil, &os.PathError{Op: "readdir", Path: f.name, Err: err}
+		}
+		f.entries = entries
+	}
+
+	remaining := f.entries[f.offset:]
+	if count <= 0 {
+		f.offset = len(f.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if count > len(remaining) {
+		count = len(remaining)
+	}
+	f.offset += count
+	return remaining[:count], nil
+}
+
+// Readdirnames returns the names of up to n of the directory's contents, as Readdir does.
+func (f *aferoFile) Readdirnames(n int) ([]string, error) {
+	infos, err := f.Readdir(n)
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = info.Name()
+	}
+	return names, err
+}
+
+// Close closes the file, writing it if it was opened for writing.
+func (f *aferoFile) Close() error {
+	if err := f.check("close", false); err != nil {
+		return err
+	}
+	f.closed = true
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Close()
+}
+
+// check returns an error for op if the file is closed or, when needsFile is set, is a directory.
+func (f *aferoFile) check(op string, needsFile bool) error {
+	if f.closed {
+		return afero.ErrFileClosed
+	}
+	if needsFile && f.file == nil {
+		return &os.PathError{Op: op, Path: f.name, Err: errIsDir}
+	}
+	return nil
+}
+
+// listInfo returns FileInfos for the files and sub-locations of location, sorted by name.
+func listInfo(location vfs.Location) ([]os.FileInfo, error) {
+	files, locations, err := utils.ListDir(location)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]os.FileInfo, 0, len(files)+len(locations))
+	for _, file := range files {
+		info, err := utils.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	for _, sub := range locations {
+		infos = append(infos, dirInfo(sub))
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	return infos, nil
+}
+
+// dirInfo returns a FileInfo for location as a directory.
+func dirInfo(location vfs.Location) os.FileInfo {
+	return vfs.NewFileInfo(path.Base(location.Path()), 0, time.Time{}, dirMode)
+}
@@ -0,0 +1,33 @@
+/*
+Package vfsafero adapts between vfs and github.com/spf13/afero, so projects standardized on afero can use any vfs
+backend (ie: s3) without a rewrite, and afero file systems can be used wherever a vfs.FileSystem is expected.
+
+Usage
+
+Use NewFs to present a vfs.Location as an afero.Fs:
+
+  loc, err := vfssimple.NewLocation("s3://mybucket/reports/")
+  if err != nil {
+      return err
+  }
+  appFs := vfsafero.NewFs(loc)
+
+  // "/2020/summary.csv" is s3://mybucket/reports/2020/summary.csv
+  err = afero.WriteFile(appFs, "/2020/summary.csv", data, 0644)
+
+Use NewFileSystem to present an afero.Fs as a vfs.FileSystem:
+
+  fs := vfsafero.NewFileSystem(afero.NewMemMapFs())
+  file, err := fs.NewFile("", "/path/to/file.txt")
+
+Directories and Writes
+
+vfs has no directories of its own, only locations, which exist implicitly while they contain files.  Through NewFs,
+Mkdir and MkdirAll therefore succeed without doing anything, a directory exists while it contains files, and Remove of
+a directory succeeds only once it's empty.  Renaming directories isn't supported.
+
+Files opened for writing replace the file's contents when closed, as vfs writes do, so they must be opened with
+os.O_TRUNC or os.O_APPEND (which copies the existing contents first); afero.WriteFile and Create do so.  Seek and WriteAt
+on files opened for writing, and Chmod and Chtimes, are supported only as far as the underlying backend supports them.
+*/
+package vfsafero
@@ -0,0 +1,227 @@
+package vfsafero
+
+import (
+	"os"
+	"path"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// File implements vfs.File for a file of an afero.Fs.  As with other vfs backends, writes replace the file's contents:
+// the first Write truncates the file, and the file is written when closed.
+type File struct {
+	fileSystem *FileSystem
+	name       string
+	reader     afero.File
+	writer     afero.File
+}
+
+// Read implements io.Reader, opening the file for reading on first use.
+func (f *File) Read(p []byte) (int, error) {
+	if f.reader == nil {
+		reader, err := f.fileSystem.fs.Open(f.name)
+		if err != nil {
+			return 0, err
+		}
+		f.reader = reader
+	}
+	return f.reader.Read(p)
+}
+
+// Seek implements io.Seeker, seeking in what's been written if the file has been written to, otherwise in the file
+// being read.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if f.writer != nil {
+		return f.writer.Seek(offset, whence)
+	}
+	if f.reader == nil {
+		reader, err := f.fileSystem.fs.Open(f.name)
+		if err != nil {
+			return 0, err
+		}
+		f.reader = reader
+	}
+	return f.reader.Seek(offset, whence)
+}
+
+// Write implements io.Writer, creating or truncating the file on first use.
+func (f *File) Write(p []byte) (int, error) {
+	if f.writer == nil {
+		if err := f.fileSystem.fs.MkdirAll(path.Dir(f.name), 0777); err != nil {
+			return 0, err
+		}
+		writer, err := f.fileSystem.fs.OpenFile(f.name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+		if err != nil {
+			return 0, err
+		}
+		f.writer = writer
+	}
+	return f.writer.Write(p)
+}
+
+// Close closes the file's reader and writer, if any.
+func (f *File) Close() error {
+	var err error
+	if f.reader != nil {
+		err = f.reader.Close()
+		f.reader = nil
+	}
+	if f.writer != nil {
+		if werr := f.writer.Close(); err == nil {
+			err = werr
+		}
+		f.writer = nil
+	}
+	return err
+}
+
+// Exists returns true if the file exists.
+func (f *File) Exists() (bool, error) {
+	info, err := f.fileSystem.fs.Stat(f.name)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !info.IsDir(), nil
+}
+
+// Location returns the Location of the file's directory.
+func (f *File) Location() vfs.Location {
+	return &Location{fileSystem: f.fileSystem, name: utils.EnsureTrailingSlash(path.Dir(f.name))}
+}
+
+// CopyToLocation copies the file to a file of the same name at location, returning the new file.  If that file already
+// exists, the ConflictPolicy in opts determines what happens (overwriting it by default).
+func (f *File) CopyToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	newFile, skip, err := f.resolveConflict(location, opts)
+	if err != nil || skip {
+		return newFile, err
+	}
+	return newFile, f.CopyToFile(newFile, opts...)
+}
+
+// CopyToFile copies the file's contents to file.
+func (f *File) CopyToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	options := vfs.NewCopyOptions(opts...)
+	if err := utils.TouchCopyWithOptions(file, f, options); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if options.VerifyChecksum {
+		return utils.VerifyChecksum(f, file)
+	}
+	return nil
+}
+
+// MoveToLocation moves the file to a file of the same name at location, returning the new file.  If that file already
+// exists, the ConflictPolicy in opts determines what happens (overwriting it by default).
+func (f *File) MoveToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	newFile, skip, err := f.resolveConflict(location, opts)
+	if err != nil || skip {
+		return newFile, err
+	}
+	return newFile, f.MoveToFile(newFile, opts...)
+}
+
+// resolveConflict returns the file to copy or move to at location, and whether to skip it, according to the
+// ConflictPolicy in opts.
+func (f *File) resolveConflict(location vfs.Location, opts []vfs.CopyOption) (vfs.File, bool, error) {
+	newFile, err := location.NewFile(f.Name())
+	if err != nil {
+		return nil, false, err
+	}
+	return utils.ResolveConflict(newFile, vfs.NewCopyOptions(opts...).ConflictPolicyOr(vfs.ConflictOverwrite))
+}
+
+// MoveToFile moves the file to file, renaming it if both are in the same afero.Fs, otherwise copying it and deleting
+// the original.
+func (f *File) MoveToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	if target, ok := file.(*File); ok && target.fileSystem.fs == f.fileSystem.fs {
+		if err := f.Close(); err != nil {
+			return err
+		}
+		if err := f.fileSystem.fs.MkdirAll(path.Dir(target.name), 0777); err != nil {
+			return err
+		}
+		return f.fileSystem.fs.Rename(f.name, target.name)
+	}
+
+	if err := f.CopyToFile(file, opts...); err != nil {
+		return err
+	}
+	return f.Delete()
+}
+
+// Delete closes and removes the file.
+func (f *File) Delete() error {
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return f.fileSystem.fs.Remove(f.name)
+}
+
+// LastModified returns the file's modification time.
+func (f *File) LastModified() (*time.Time, error) {
+	info, err := f.fileSystem.fs.Stat(f.name)
+	if err != nil {
+		return nil, err
+	}
+	modTime := info.ModTime()
+	return &modTime, nil
+}
+
+// Size returns the size of the file in bytes.
+func (f *File) Size() (uint64, error) {
+	info, err := f.fileSystem.fs.Stat(f.name)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(info.Size()), nil
+}
+
+// Path returns the file's absolute path.
+func (f *File) Path() string {
+	return f.name
+}
+
+// Name returns the file's base name.
+func (f *File) Name() string {
+	return path.Base(f.name)
+}
+
+// Touch creates the file if it doesn't exist, otherwise updates its modification time.
+func (f *File) Touch() error {
+	exists, err := f.Exists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if _, err := f.Write([]byte{}); err != nil {
+			return err
+		}
+		return f.Close()
+	}
+	now := time.Now()
+	return f.fileSystem.fs.Chtimes(f.name, now, now)
+}
+
+// URI returns the file's URI as a string, ie: afero:///path/to/file.txt
+func (f *File) URI() string {
+	return utils.GetFileURI(f)
+}
+
+// String implement fmt.Stringer, returning the file's URI as the default string.
+func (f *File) String() string {
+	return f.URI()
+}
@@ -0,0 +1,68 @@
+package vfsafero
+
+import (
+	"errors"
+	"path"
+
+	"github.com/spf13/afero"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// Scheme defines the file system type of FileSystem.
+const Scheme = "afero"
+const name = "afero"
+
+// FileSystem implements vfs.FileSystem on top of an afero.Fs.  It isn't registered as a backend, since it needs an
+// afero.Fs to work with; create one with NewFileSystem.
+type FileSystem struct {
+	fs afero.Fs
+}
+
+// NewFileSystem returns a vfs.FileSystem of the files in fs.
+func NewFileSystem(fs afero.Fs) *FileSystem {
+	return &FileSystem{fs: fs}
+}
+
+// Retry will return a no-op retrier.
+func (fs *FileSystem) Retry() vfs.Retry {
+	return vfs.DefaultRetryer()
+}
+
+// NewFile returns the file at the absolute path name.  volume is ignored.
+func (fs *FileSystem) NewFile(volume string, name string) (vfs.File, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil vfsafero.FileSystem pointer is required")
+	}
+	if err := utils.ValidateAbsoluteFilePath(name); err != nil {
+		return nil, err
+	}
+	return &File{fileSystem: fs, name: path.Clean(name)}, nil
+}
+
+// NewLocation returns the location at the absolute path name.  volume is ignored.
+func (fs *FileSystem) NewLocation(volume string, name string) (vfs.Location, error) {
+	if fs == nil {
+		return nil, errors.New("non-nil vfsafero.FileSystem pointer is required")
+	}
+	if err := utils.ValidateAbsoluteLocationPath(name); err != nil {
+		return nil, err
+	}
+	return &Location{fileSystem: fs, name: utils.EnsureTrailingSlash(path.Clean(name))}, nil
+}
+
+// Name returns "afero"
+func (fs *FileSystem) Name() string {
+	return name
+}
+
+// Scheme returns "afero" as the initial part of a file URI ie: afero://
+func (fs *FileSystem) Scheme() string {
+	return Scheme
+}
+
+// Fs returns the underlying afero.Fs.
+func (fs *FileSystem) Fs() afero.Fs {
+	return fs.fs
+}
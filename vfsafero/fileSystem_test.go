@@ -0,0 +1,121 @@
+package vfsafero
+
+import (
+	"io/ioutil"
+	"regexp"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+type fileSystemTestSuite struct {
+	suite.Suite
+	memFs afero.Fs
+	fs    *FileSystem
+}
+
+func (ts *fileSystemTestSuite) SetupTest() {
+	ts.memFs = afero.NewMemMapFs()
+	ts.fs = NewFileSystem(ts.memFs)
+}
+
+func (ts *fileSystemTestSuite) TestFile() {
+	var file vfs.File
+	file, err := ts.fs.NewFile("", "/some/path/file.txt")
+	ts.NoError(err)
+	ts.Equal("afero:///some/path/file.txt", file.URI())
+
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+
+	_, err = file.Write([]byte("hello world"))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+
+	data, err := afero.ReadFile(ts.memFs, "/some/path/file.txt")
+	ts.NoError(err)
+	ts.Equal("hello world", string(data))
+
+	size, err := file.Size()
+	ts.NoError(err)
+	ts.Equal(uint64(11), size)
+	data, err = ioutil.ReadAll(file)
+	ts.NoError(err)
+	ts.Equal("hello world", string(data))
+	ts.NoError(file.Close())
+
+	// the first write replaces the contents
+	_, err = file.Write([]byte("bye"))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+	data, err = afero.ReadFile(ts.memFs, "/some/path/file.txt")
+	ts.NoError(err)
+	ts.Equal("bye", string(data))
+
+	ts.NoError(file.Delete())
+	exists, err = file.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+}
+
+func (ts *fileSystemTestSuite) TestLocation() {
+	for _, name := range []string{"/dir/a.txt", "/dir/b.csv", "/dir/sub/c.txt"} {
+		ts.NoError(afero.WriteFile(ts.memFs, name, []byte(name), 0644))
+	}
+
+	location, err := ts.fs.NewLocation("", "/dir/")
+	ts.NoError(err)
+	names, err := location.List()
+	ts.NoError(err)
+	ts.Equal([]string{"a.txt", "b.csv"}, names)
+	names, err = location.ListByPrefix("sub/c")
+	ts.NoError(err)
+	ts.Equal([]string{"c.txt"}, names)
+	names, err = location.ListByRegex(regexp.MustCompile(`\.csv$`))
+	ts.NoError(err)
+	ts.Equal([]string{"b.csv"}, names)
+
+	files, locations, err := location.(*Location).ListWithPrefixes()
+	ts.NoError(err)
+	ts.Len(files, 2)
+	ts.Len(locations, 1)
+	ts.Equal("/dir/sub/", locations[0].Path())
+
+	missing, err := location.NewLocation("missing/")
+	ts.NoError(err)
+	exists, err := missing.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+	names, err = missing.List()
+	ts.NoError(err)
+	ts.Empty(names)
+}
+
+func (ts *fileSystemTestSuite) TestMoveAndCopy() {
+	ts.NoError(afero.WriteFile(ts.memFs, "/src/file.txt", []byte("data"), 0644))
+	file, err := ts.fs.NewFile("", "/src/file.txt")
+	ts.NoError(err)
+	target, err := ts.fs.NewLocation("", "/dst/")
+	ts.NoError(err)
+
+	copied, err := file.CopyToLocation(target)
+	ts.NoError(err)
+	ts.Equal("/dst/file.txt", copied.Path())
+
+	moved, err := file.MoveToLocation(target)
+	ts.NoError(err)
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+	data, err := afero.ReadFile(ts.memFs, moved.Path())
+	ts.NoError(err)
+	ts.Equal("data", string(data))
+}
+
+func TestFileSystem(t *testing.T) {
+	suite.Run(t, new(fileSystemTestSuite))
+}
@@ -0,0 +1,335 @@
+package vfsafero
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// Errors returned, wrapped in an os.PathError, by operations that can't be performed.
+var (
+	errNotSupported = errors.New("operation not supported by the vfs backend")
+	errIsDir        = errors.New("is a directory")
+	errNotDir       = errors.New("not a directory")
+)
+
+// chmoder and chtimeser are implemented by files whose backend supports changing their mode and times (os.File).
+type chmoder interface {
+	Chmod(mode os.FileMode) error
+}
+
+type chtimeser interface {
+	Chtimes(atime, mtime time.Time) error
+}
+
+// dirMode is the mode reported for directories, which vfs locations don't have permissions for.
+const dirMode = os.ModeDir | 0755
+
+// Fs implements afero.Fs on top of a vfs.Location.  Paths are relative to the location, so "/reports/a.csv" and
+// "reports/a.csv" both refer to the file reports/a.csv beneath it.
+type Fs struct {
+	location vfs.Location
+}
+
+// NewFs returns an afero.Fs of the files beneath location.
+func NewFs(location vfs.Location) *Fs {
+	return &Fs{location: location}
+}
+
+// Name returns the name of the file system, "VfsFs".
+func (fs *Fs) Name() string {
+	return "VfsFs"
+}
+
+// Create creates or truncates the named file, opening it for writing.
+func (fs *Fs) Create(name string) (afero.File, error) {
+	return fs.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+// Mkdir does nothing, since vfs locations exist implicitly while they contain files.
+func (fs *Fs) Mkdir(name string, perm os.FileMode) error {
+	return nil
+}
+
+// MkdirAll does nothing, since vfs locations exist implicitly while they contain files.
+func (fs *Fs) MkdirAll(path string, perm os.FileMode) error {
+	return nil
+}
+
+// Open opens the named file or directory for reading.
+func (fs *Fs) Open(name string) (afero.File, error) {
+	return fs.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// OpenFile opens the named file with the given flags, see the package documentation for the flags supported when
+// writing.  perm is ignored.
+func (fs *Fs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	writing := flag&(os.O_WRONLY|os.O_RDWR) != 0
+	rel := utils.RelPath(filepath.ToSlash(name))
+	if rel == "" {
+		if writing {
+			return nil, &os.PathError{Op: "open", Path: name, Err: errIsDir}
+		}
+		return &aferoFile{name: name, location: fs.location}, nil
+	}
+
+	file, err := fs.location.NewFile(rel)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: name, Err: err}
+	}
+	exists, err := fileExists(file)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	if !writing {
+		if exists {
+			return &aferoFile{name: name, file: file}, nil
+		}
+		location, err := fs.dir(rel)
+		if err != nil {
+			return nil, &os.PathError{Op: "open", Path: name, Err: err}
+		}
+		if location == nil {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		return &aferoFile{name: name, location: location}, nil
+	}
+
+	switch {
+	case exists && flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0:
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrExist}
+	case !exists && flag&os.O_CREATE == 0:
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	case exists && flag&os.O_APPEND != 0:
+		// vfs writes replace the file, so start with its existing contents
+		size, err := copyContents(file, fs.location, rel)
+		if err != nil {
+			return nil, &os.PathError{Op: "open", Path: name, Err: err}
+		}
+		return &aferoFile{name: name, file: file, writing: true, size: size}, nil
+	case exists && flag&os.O_TRUNC == 0:
+		return nil, &os.PathError{Op: "open", Path: name,
+			Err: errors.New("existing files must be opened for writing with O_TRUNC or O_APPEND")}
+	}
+	return &aferoFile{name: name, file: file, writing: true}, nil
+}
+
+// Remove removes the named file, or directory if it's empty.
+func (fs *Fs) Remove(name string) error {
+	rel := utils.RelPath(filepath.ToSlash(name))
+	if rel != "" {
+		file, err := fs.location.NewFile(rel)
+		if err != nil {
+			return &os.PathError{Op: "remove", Path: name, Err: err}
+		}
+		if exists, err := fileExists(file); err != nil {
+			return &os.PathError{Op: "remove", Path: name, Err: err}
+		} else if exists {
+			return file.Delete()
+		}
+	}
+
+	location, err := fs.dir(rel)
+	if err != nil {
+		return &os.PathError{Op: "remove", Path: name, Err: err}
+	}
+	if location == nil {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	files, locations, err := utils.ListDir(location)
+	if err != nil {
+		return &os.PathError{Op: "remove", Path: name, Err: err}
+	}
+	if len(files) > 0 || len(locations) > 0 {
+		return &os.PathError{Op: "remove", Path: name, Err: errors.New("directory not empty")}
+	}
+	return nil
+}
+
+// RemoveAll removes the named file, or directory and everything beneath it.  It returns nil if there's nothing there.
+func (fs *Fs) RemoveAll(name string) error {
+	rel := utils.RelPath(filepath.ToSlash(name))
+	if rel != "" {
+		file, err := fs.location.NewFile(rel)
+		if err != nil {
+			return &os.PathError{Op: "removeall", Path: name, Err: err}
+		}
+		if exists, err := fileExists(file); err != nil {
+			return &os.PathError{Op: "removeall", Path: name, Err: err}
+		} else if exists {
+			return file.Delete()
+		}
+	}
+
+	location := fs.location
+	if rel != "" {
+		var err error
+		if location, err = fs.location.NewLocation(utils.EnsureTrailingSlash(rel)); err != nil {
+			return &os.PathError{Op: "removeall", Path: name, Err: err}
+		}
+	}
+	return removeAll(location)
+}
+
+// Rename moves the named file to newname, replacing any file there.  Directories can't be renamed.
+func (fs *Fs) Rename(oldname, newname string) error {
+	oldRel, newRel := utils.RelPath(filepath.ToSlash(oldname)), utils.RelPath(filepath.ToSlash(newname))
+	if oldRel == "" || newRel == "" {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: errNotSupported}
+	}
+	file, err := fs.location.NewFile(oldRel)
+	if err != nil {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+	}
+	if exists, err := fileExists(file); err != nil {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+	} else if !exists {
+		if location, _ := fs.dir(oldRel); location != nil {
+			return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: errNotSupported}
+		}
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: os.ErrNotExist}
+	}
+
+	target, err := fs.location.NewFile(newRel)
+	if err != nil {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+	}
+	if err := file.MoveToFile(target); err != nil {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+	}
+	return nil
+}
+
+// Stat returns a FileInfo describing the named file or directory.
+func (fs *Fs) Stat(name string) (os.FileInfo, error) {
+	f, err := fs.Open(name)
+	if err != nil {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: underlyingError(err)}
+	}
+	return f.Stat()
+}
+
+// Chmod changes the mode of the named file, where the backend supports it (os.File).
+func (fs *Fs) Chmod(name string, mode os.FileMode) error {
+	file, err := fs.existingFile("chmod", name)
+	if err != nil {
+		return err
+	}
+	if c, ok := file.(chmoder); ok {
+		return c.Chmod(mode)
+	}
+	return &os.PathError{Op: "chmod", Path: name, Err: errNotSupported}
+}
+
+// Chtimes changes the access and modification times of the named file, where the backend supports it.
+func (fs *Fs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	file, err := fs.existingFile("chtimes", name)
+	if err != nil {
+		return err
+	}
+	if c, ok := file.(chtimeser); ok {
+		return c.Chtimes(atime, mtime)
+	}
+	return &os.PathError{Op: "chtimes", Path: name, Err: errNotSupported}
+}
+
+// existingFile returns the named file, or an os.PathError for op if it doesn't exist.
+func (fs *Fs) existingFile(op, name string) (vfs.File, error) {
+	rel := utils.RelPath(filepath.ToSlash(name))
+	if rel == "" {
+		return nil, &os.PathError{Op: op, Path: name, Err: errNotSupported}
+	}
+	file, err := fs.location.NewFile(rel)
+	if err != nil {
+		return nil, &os.PathError{Op: op, Path: name, Err: err}
+	}
+	if exists, err := fileExists(file); err != nil {
+		return nil, &os.PathError{Op: op, Path: name, Err: err}
+	} else if !exists {
+		return nil, &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+	}
+	return file, nil
+}
+
+// dir returns the location at rel if it exists as a directory, that is, if it's the root or contains anything,
+// otherwise nil.
+func (fs *Fs) dir(rel string) (vfs.Location, error) {
+	if rel == "" {
+		return fs.location, nil
+	}
+	location, err := fs.location.NewLocation(utils.EnsureTrailingSlash(rel))
+	if err != nil {
+		return nil, err
+	}
+	files, locations, err := utils.ListDir(location)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 && len(locations) == 0 {
+		return nil, nil
+	}
+	return location, nil
+}
+
+// fileExists returns true if file exists and isn't a directory.  Backends with real directories (os) report a
+// directory as an existing file.
+func fileExists(file vfs.File) (bool, error) {
+	exists, err := file.Exists()
+	if err != nil || !exists {
+		return false, err
+	}
+	info, err := utils.Stat(file)
+	if err != nil {
+		return false, err
+	}
+	return !info.IsDir(), nil
+}
+
+// underlyingError returns the error wrapped by an os.PathError, or err itself.
+func underlyingError(err error) error {
+	if pathErr, ok := err.(*os.PathError); ok {
+		return pathErr.Err
+	}
+	return err
+}
+
+// copyContents writes the contents of the file at rel beneath location to file, which must be the same file, read
+// through a separate handle, returning the number of bytes written.
+func copyContents(file vfs.File, location vfs.Location, rel string) (int64, error) {
+	existing, err := location.NewFile(rel)
+	if err != nil {
+		return 0, err
+	}
+	n, err := utils.Copy(file, existing)
+	if err != nil {
+		_ = existing.Close()
+		return 0, err
+	}
+	return n, existing.Close()
+}
+
+// removeAll deletes every file beneath location.
+func removeAll(location vfs.Location) error {
+	files, locations, err := utils.ListDir(location)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := file.Delete(); err != nil {
+			return err
+		}
+	}
+	for _, sub := range locations {
+		if err := removeAll(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
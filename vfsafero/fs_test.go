@@ -0,0 +1,147 @@
+package vfsafero
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+
+	_os "github.com/c2fo/vfs/v5/backend/os"
+)
+
+type fsTestSuite struct {
+	suite.Suite
+	dir string
+	fs  afero.Fs
+}
+
+func (ts *fsTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfsafero_test")
+	ts.NoError(err)
+	ts.dir = dir
+	location, err := _os.NewFileSystem().NewLocation("", dir+"/")
+	ts.NoError(err)
+	ts.fs = NewFs(location)
+}
+
+func (ts *fsTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *fsTestSuite) TestWriteAndReadFile() {
+	ts.NoError(afero.WriteFile(ts.fs, "/reports/2020/summary.csv", []byte("a,b,c\n"), 0644))
+
+	data, err := ioutil.ReadFile(filepath.Join(ts.dir, "reports", "2020", "summary.csv"))
+	ts.NoError(err)
+	ts.Equal("a,b,c\n", string(data))
+
+	data, err = afero.ReadFile(ts.fs, "reports/2020/summary.csv")
+	ts.NoError(err)
+	ts.Equal("a,b,c\n", string(data))
+
+	f, err := ts.fs.Open("/reports/2020/summary.csv")
+	ts.NoError(err)
+	buf := make([]byte, 3)
+	n, err := f.ReadAt(buf, 2)
+	ts.NoError(err)
+	ts.Equal("b,c", string(buf[:n]))
+	n, err = f.ReadAt(buf, 4)
+	ts.Equal(io.EOF, err)
+	ts.Equal("c\n", string(buf[:n]))
+	_, err = f.Write([]byte("x"))
+	ts.Error(err, "file opened for reading can't be written")
+	ts.NoError(f.Close())
+	ts.Equal(afero.ErrFileClosed, f.Close())
+}
+
+func (ts *fsTestSuite) TestOpenFileFlags() {
+	ts.NoError(afero.WriteFile(ts.fs, "/log.txt", []byte("one\n"), 0644))
+
+	f, err := ts.fs.OpenFile("/log.txt", os.O_WRONLY|os.O_APPEND, 0644)
+	ts.NoError(err)
+	_, err = f.WriteString("two\n")
+	ts.NoError(err)
+	info, err := f.Stat()
+	ts.NoError(err)
+	ts.Equal(int64(8), info.Size(), "size includes what's been written but not yet closed")
+	ts.NoError(f.Close())
+	data, err := afero.ReadFile(ts.fs, "/log.txt")
+	ts.NoError(err)
+	ts.Equal("one\ntwo\n", string(data))
+
+	_, err = ts.fs.OpenFile("/log.txt", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	ts.True(os.IsExist(err))
+	_, err = ts.fs.OpenFile("/log.txt", os.O_WRONLY, 0644)
+	ts.Error(err, "overwriting in place isn't supported")
+	_, err = ts.fs.OpenFile("/missing.txt", os.O_WRONLY, 0644)
+	ts.True(os.IsNotExist(err))
+	_, err = ts.fs.Open("/missing.txt")
+	ts.True(os.IsNotExist(err))
+}
+
+func (ts *fsTestSuite) TestDirectories() {
+	ts.NoError(ts.fs.MkdirAll("/a/b", 0755))
+	ts.NoError(afero.WriteFile(ts.fs, "/a/b/file2.txt", []byte("22"), 0644))
+	ts.NoError(afero.WriteFile(ts.fs, "/a/file1.txt", []byte("1"), 0644))
+
+	infos, err := afero.ReadDir(ts.fs, "/a")
+	ts.NoError(err)
+	ts.Len(infos, 2)
+	ts.Equal("b", infos[0].Name())
+	ts.True(infos[0].IsDir())
+	ts.Equal("file1.txt", infos[1].Name())
+	ts.Equal(int64(1), infos[1].Size())
+
+	info, err := ts.fs.Stat("/a/b")
+	ts.NoError(err)
+	ts.True(info.IsDir())
+	info, err = ts.fs.Stat("/a/b/file2.txt")
+	ts.NoError(err)
+	ts.Equal(int64(2), info.Size())
+	_, err = ts.fs.Stat("/nothing")
+	ts.True(os.IsNotExist(err))
+
+	var walked []string
+	ts.NoError(afero.Walk(ts.fs, "/", func(path string, info os.FileInfo, err error) error {
+		walked = append(walked, path)
+		return err
+	}))
+	ts.Equal([]string{"/", "/a", "/a/b", "/a/b/file2.txt", "/a/file1.txt"}, walked)
+
+	ts.Error(ts.fs.Remove("/a/b"), "directory isn't empty")
+	ts.NoError(ts.fs.RemoveAll("/a"))
+	exists, err := afero.Exists(ts.fs, "/a/file1.txt")
+	ts.NoError(err)
+	ts.False(exists)
+}
+
+func (ts *fsTestSuite) TestRename() {
+	ts.NoError(afero.WriteFile(ts.fs, "/old.txt", []byte("data"), 0644))
+	ts.NoError(ts.fs.Rename("/old.txt", "/sub/new.txt"))
+
+	exists, err := afero.Exists(ts.fs, "/old.txt")
+	ts.NoError(err)
+	ts.False(exists)
+	data, err := afero.ReadFile(ts.fs, "/sub/new.txt")
+	ts.NoError(err)
+	ts.Equal("data", string(data))
+
+	ts.Error(ts.fs.Rename("/sub", "/other"), "directories can't be renamed")
+	ts.Error(ts.fs.Rename("/missing.txt", "/other.txt"))
+}
+
+func (ts *fsTestSuite) TestChmod() {
+	ts.NoError(afero.WriteFile(ts.fs, "/file.txt", []byte("data"), 0644))
+	ts.NoError(ts.fs.Chmod("/file.txt", 0600))
+	info, err := os.Stat(filepath.Join(ts.dir, "file.txt"))
+	ts.NoError(err)
+	ts.Equal(os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestFs(t *testing.T) {
+	suite.Run(t, new(fsTestSuite))
+}
@@ -0,0 +1,167 @@
+package vfsafero
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// Location implements vfs.Location for a directory of an afero.Fs.
+type Location struct {
+	fileSystem *FileSystem
+	name       string
+}
+
+// List returns the names of the files in the location's directory.
+func (l *Location) List() ([]string, error) {
+	return l.fileList(func(name string) bool { return true })
+}
+
+// ListByPrefix returns the names of the files in the location's directory starting with prefix.  A prefix with a
+// directory component lists the files of that sub-directory.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	loc := l
+	if d := path.Dir(prefix); d != "." && d != "/" {
+		sub, err := l.NewLocation(utils.EnsureTrailingSlash(d))
+		if err != nil {
+			return []string{}, err
+		}
+		loc = sub.(*Location)
+		prefix = path.Base(prefix)
+	}
+	return loc.fileList(func(name string) bool { return strings.HasPrefix(name, prefix) })
+}
+
+// ListByRegex returns the names of the files in the location's directory matching regex.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	return l.fileList(regex.MatchString)
+}
+
+// ListWithPrefixes returns the files in the location's directory along with a Location for each sub-directory.
+func (l *Location) ListWithPrefixes() ([]vfs.File, []vfs.Location, error) {
+	files := []vfs.File{}
+	locations := []vfs.Location{}
+	exists, err := l.Exists()
+	if err != nil || !exists {
+		return files, locations, err
+	}
+
+	infos, err := afero.ReadDir(l.fileSystem.fs, l.Path())
+	if err != nil {
+		return files, locations, err
+	}
+	for _, info := range infos {
+		if info.IsDir() {
+			locations = append(locations, &Location{fileSystem: l.fileSystem, name: path.Join(l.name, info.Name()) + "/"})
+		} else {
+			files = append(files, &File{fileSystem: l.fileSystem, name: path.Join(l.name, info.Name())})
+		}
+	}
+	return files, locations, nil
+}
+
+func (l *Location) fileList(include func(name string) bool) ([]string, error) {
+	names := []string{}
+	exists, err := l.Exists()
+	if err != nil || !exists {
+		return names, err
+	}
+
+	infos, err := afero.ReadDir(l.fileSystem.fs, l.Path())
+	if err != nil {
+		return names, err
+	}
+	for _, info := range infos {
+		if !info.IsDir() && include(info.Name()) {
+			names = append(names, info.Name())
+		}
+	}
+	return names, nil
+}
+
+// Volume returns "", since afero file systems don't have volumes.
+func (l *Location) Volume() string {
+	return ""
+}
+
+// Path returns the location's absolute path, with leading and trailing slashes.
+func (l *Location) Path() string {
+	return utils.EnsureLeadingSlash(utils.EnsureTrailingSlash(l.name))
+}
+
+// Exists returns true if the location's directory exists.
+func (l *Location) Exists() (bool, error) {
+	return afero.DirExists(l.fileSystem.fs, l.Path())
+}
+
+// NewLocation returns a copy of the location with its path changed by ChangeDir.
+func (l *Location) NewLocation(relativePath string) (vfs.Location, error) {
+	if l == nil {
+		return nil, errors.New("non-nil vfsafero.Location pointer is required")
+	}
+	newLocation := &Location{}
+	*newLocation = *l
+	if err := newLocation.ChangeDir(relativePath); err != nil {
+		return nil, err
+	}
+	return newLocation, nil
+}
+
+// ChangeDir changes the location's path by the relative path relativePath.
+func (l *Location) ChangeDir(relativePath string) error {
+	if l == nil {
+		return errors.New("non-nil vfsafero.Location pointer is required")
+	}
+	if relativePath == "" {
+		return errors.New("non-empty string relativePath is required")
+	}
+	if err := utils.ValidateRelativeLocationPath(relativePath); err != nil {
+		return err
+	}
+	l.name = utils.EnsureTrailingSlash(path.Clean(path.Join(l.name, relativePath)))
+	return nil
+}
+
+// FileSystem returns the location's FileSystem.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile returns the file at the path relFilePath, relative to the location.
+func (l *Location) NewFile(relFilePath string) (vfs.File, error) {
+	if l == nil {
+		return nil, errors.New("non-nil vfsafero.Location pointer is required")
+	}
+	if relFilePath == "" {
+		return nil, errors.New("non-empty string filePath is required")
+	}
+	if err := utils.ValidateRelativeFilePath(relFilePath); err != nil {
+		return nil, err
+	}
+	return l.fileSystem.NewFile(l.Volume(), utils.EnsureLeadingSlash(path.Clean(path.Join(l.name, relFilePath))))
+}
+
+// DeleteFile deletes the file at the path relFilePath, relative to the location.
+func (l *Location) DeleteFile(relFilePath string) error {
+	file, err := l.NewFile(relFilePath)
+	if err != nil {
+		return err
+	}
+	return file.Delete()
+}
+
+// URI returns the Location's URI as a string.
+func (l *Location) URI() string {
+	return utils.GetLocationURI(l)
+}
+
+// String implement fmt.Stringer, returning the location's URI as the default string.
+func (l *Location) String() string {
+	return l.URI()
+}
@@ -0,0 +1,318 @@
+package vfsbackup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+const (
+	// idFormat is the time layout of snapshot IDs, which sort in the order the snapshots were taken.
+	idFormat = "20060102T150405.000000000Z"
+	// snapshotExt is the extension of the file each snapshot is written to, named by its ID.
+	snapshotExt = ".json"
+)
+
+// ErrSnapshotNotFound is returned when a snapshot doesn't exist in the target.
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// Options configures Backup.
+type Options struct {
+	// Incremental only copies the files that are new or changed since the latest snapshot in the target.  A full
+	// snapshot is taken if there isn't one.
+	Incremental bool
+	// Retain, if greater than 0, prunes all but the newest Retain snapshots once the snapshot is taken, see Prune.
+	Retain int
+	// CopyOptions are passed to CopyToFile for each file copied, ie: vfs.WithBandwidthLimit.
+	CopyOptions []vfs.CopyOption
+}
+
+// Entry describes a file in a Snapshot.
+type Entry struct {
+	utils.ManifestEntry
+	// Snapshot is the ID of the snapshot holding the file's contents, an earlier one if the file was unchanged by an
+	// incremental snapshot.
+	Snapshot string `json:"snapshot"`
+}
+
+// Snapshot describes the files at a source location when a snapshot was taken.
+type Snapshot struct {
+	// ID names the snapshot in its target.
+	ID string `json:"id"`
+	// Created is when the snapshot was taken.
+	Created time.Time `json:"created"`
+	// Source is the URI of the location the snapshot was taken of.
+	Source string `json:"source"`
+	// Incremental is true if the snapshot only copied files changed since an earlier one.
+	Incremental bool `json:"incremental"`
+	// Files lists every file at the source, in name order, with its path relative to the source.
+	Files []Entry `json:"files"`
+}
+
+// Copied returns the names of the files whose contents the snapshot copied, rather than an earlier one.
+func (s *Snapshot) Copied() []string {
+	names := []string{}
+	for _, entry := range s.Files {
+		if entry.Snapshot == s.ID {
+			names = append(names, entry.Name)
+		}
+	}
+	return names
+}
+
+// Backup takes a snapshot of the files at source into target, returning it.  If the snapshot was taken but pruning
+// fails, the snapshot is returned along with the error.
+func Backup(source, target vfs.Location, opts Options) (*Snapshot, error) {
+	ids, err := Snapshots(target)
+	if err != nil {
+		return nil, err
+	}
+
+	created := time.Now().UTC()
+	previous := map[string]Entry{}
+	var incremental bool
+	if len(ids) > 0 {
+		latest, err := ReadSnapshot(target, ids[len(ids)-1])
+		if err != nil {
+			return nil, err
+		}
+		// keep IDs in order even if the clock has gone backwards
+		if !created.After(latest.Created) {
+			created = latest.Created.Add(time.Nanosecond)
+		}
+		if opts.Incremental {
+			incremental = true
+			for _, entry := range latest.Files {
+				previous[entry.Name] = entry
+			}
+		}
+	}
+
+	manifest, err := utils.CreateManifest(source)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{
+		ID:          created.Format(idFormat),
+		Created:     created,
+		Source:      utils.GetLocationURI(source),
+		Incremental: incremental,
+		Files:       make([]Entry, 0, len(manifest.Files)),
+	}
+	for _, entry := range manifest.Files {
+		prev, ok := previous[entry.Name]
+		if ok && prev.Size == entry.Size && prev.Checksum == entry.Checksum {
+			snapshot.Files = append(snapshot.Files, Entry{ManifestEntry: entry, Snapshot: prev.Snapshot})
+			continue
+		}
+		file, err := source.NewFile(entry.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := copyTo(file, target, snapshot.ID, entry.Name, opts.CopyOptions); err != nil {
+			return nil, err
+		}
+		snapshot.Files = append(snapshot.Files, Entry{ManifestEntry: entry, Snapshot: snapshot.ID})
+	}
+	if err := writeSnapshot(target, snapshot); err != nil {
+		return nil, err
+	}
+
+	if opts.Retain > 0 {
+		if _, err := Prune(target, opts.Retain); err != nil {
+			return snapshot, err
+		}
+	}
+	return snapshot, nil
+}
+
+// Snapshots returns the IDs of the snapshots in target, oldest first.
+func Snapshots(target vfs.Location) ([]string, error) {
+	names, err := target.List()
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, name := range names {
+		if !strings.HasSuffix(name, snapshotExt) {
+			continue
+		}
+		id := strings.TrimSuffix(name, snapshotExt)
+		if _, err := time.Parse(idFormat, id); err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// ReadSnapshot returns the snapshot in target named id, or ErrSnapshotNotFound if there isn't one.
+func ReadSnapshot(target vfs.Location, id string) (*Snapshot, error) {
+	file, err := target.NewFile(id + snapshotExt)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := file.Exists()
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrSnapshotNotFound
+	}
+
+	snapshot := &Snapshot{}
+	if err := json.NewDecoder(file).Decode(snapshot); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("unable to read snapshot %s: %s", file, err.Error())
+	}
+	return snapshot, file.Close()
+}
+
+// Restore copies the files of the snapshot in target named id to destination, at their paths relative to the source
+// the snapshot was taken of, overwriting any already there.  Files at destination that aren't in the snapshot are left
+// alone.
+func Restore(target vfs.Location, id string, destination vfs.Location, opts ...vfs.CopyOption) error {
+	snapshot, err := ReadSnapshot(target, id)
+	if err != nil {
+		return err
+	}
+	for _, entry := range snapshot.Files {
+		file, err := target.NewFile(dataPath(entry.Snapshot, entry.Name))
+		if err != nil {
+			return err
+		}
+		restored, err := destination.NewFile(entry.Name)
+		if err != nil {
+			return err
+		}
+		if err := file.CopyToFile(restored, opts...); err != nil {
+			return fmt.Errorf("unable to restore %s: %s", entry.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// Prune deletes all but the newest retain snapshots in target, returning the IDs of those deleted.  Contents the kept
+// snapshots still need are copied into the oldest kept snapshot that needs them before anything is deleted.  Nothing
+// is deleted if retain is 0 or less.
+func Prune(target vfs.Location, retain int) ([]string, error) {
+	ids, err := Snapshots(target)
+	if err != nil {
+		return nil, err
+	}
+	if retain <= 0 || len(ids) <= retain {
+		return []string{}, nil
+	}
+	expired := ids[:len(ids)-retain]
+	isExpired := make(map[string]bool, len(expired))
+	for _, id := range expired {
+		isExpired[id] = true
+	}
+
+	// relocated maps the data path of contents needed from an expired snapshot to the kept snapshot now holding them
+	relocated := map[string]string{}
+	for _, id := range ids[len(ids)-retain:] {
+		snapshot, err := ReadSnapshot(target, id)
+		if err != nil {
+			return nil, err
+		}
+		changed := false
+		for i, entry := range snapshot.Files {
+			if !isExpired[entry.Snapshot] {
+				continue
+			}
+			from := dataPath(entry.Snapshot, entry.Name)
+			if _, ok := relocated[from]; !ok {
+				file, err := target.NewFile(from)
+				if err != nil {
+					return nil, err
+				}
+				if err := copyTo(file, target, snapshot.ID, entry.Name, nil); err != nil {
+					return nil, err
+				}
+				relocated[from] = snapshot.ID
+			}
+			snapshot.Files[i].Snapshot = relocated[from]
+			changed = true
+		}
+		if changed {
+			if err := writeSnapshot(target, snapshot); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, id := range expired {
+		if err := deleteSnapshot(target, id); err != nil {
+			return nil, err
+		}
+	}
+	return expired, nil
+}
+
+// copyTo copies file into target as name in the snapshot id.
+func copyTo(file vfs.File, target vfs.Location, id, name string, opts []vfs.CopyOption) error {
+	copied, err := target.NewFile(dataPath(id, name))
+	if err != nil {
+		return err
+	}
+	if err := file.CopyToFile(copied, opts...); err != nil {
+		return fmt.Errorf("unable to copy %s: %s", file, err.Error())
+	}
+	return nil
+}
+
+// writeSnapshot writes snapshot to target as JSON.
+func writeSnapshot(target vfs.Location, snapshot *Snapshot) error {
+	file, err := target.NewFile(snapshot.ID + snapshotExt)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(file).Encode(snapshot); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// deleteSnapshot deletes the snapshot in target named id and the contents it holds.  The snapshot itself is deleted
+// first so a partly deleted snapshot isn't listed.
+func deleteSnapshot(target vfs.Location, id string) error {
+	snapshot, err := ReadSnapshot(target, id)
+	if err != nil {
+		return err
+	}
+	if err := target.DeleteFile(id + snapshotExt); err != nil {
+		return err
+	}
+	for _, name := range snapshot.Copied() {
+		file, err := target.NewFile(dataPath(id, name))
+		if err != nil {
+			return err
+		}
+		exists, err := file.Exists()
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if err := file.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dataPath returns the path, relative to the target, of the contents of the file name held by the snapshot id.
+func dataPath(id, name string) string {
+	return id + "/" + name
+}
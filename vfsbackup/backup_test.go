@@ -0,0 +1,229 @@
+package vfsbackup_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+	"github.com/c2fo/vfs/v5/vfsbackup"
+)
+
+type backupTestSuite struct {
+	suite.Suite
+	dir    string
+	source vfs.Location
+	target vfs.Location
+}
+
+func (ts *backupTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfsbackup_test")
+	ts.NoError(err)
+	ts.dir = dir
+	ts.source = ts.newLocation("source")
+	ts.target = ts.newLocation("target")
+}
+
+func (ts *backupTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *backupTestSuite) newLocation(name string) vfs.Location {
+	dir := filepath.Join(ts.dir, name)
+	ts.NoError(os.MkdirAll(dir, 0755))
+	location, err := _os.NewFileSystem().NewLocation("", utils.EnsureTrailingSlash(dir))
+	ts.NoError(err)
+	return location
+}
+
+func (ts *backupTestSuite) writeFile(location vfs.Location, name, contents string) {
+	file, err := location.NewFile(name)
+	ts.NoError(err)
+	_, err = file.Write([]byte(contents))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+}
+
+func (ts *backupTestSuite) readFile(location vfs.Location, name string) string {
+	file, err := location.NewFile(name)
+	ts.NoError(err)
+	data, err := ioutil.ReadAll(file)
+	ts.NoError(err)
+	ts.NoError(file.Close())
+	return string(data)
+}
+
+func (ts *backupTestSuite) TestFullBackup() {
+	ts.writeFile(ts.source, "a.txt", "hello")
+	ts.writeFile(ts.source, "sub/b.txt", "world")
+
+	snapshot, err := vfsbackup.Backup(ts.source, ts.target, vfsbackup.Options{})
+	ts.NoError(err)
+	ts.False(snapshot.Incremental)
+	ts.Equal(utils.GetLocationURI(ts.source), snapshot.Source)
+	ts.Len(snapshot.Files, 2)
+	ts.Equal("a.txt", snapshot.Files[0].Name)
+	ts.Equal(uint64(5), snapshot.Files[0].Size)
+	ts.Equal("sub/b.txt", snapshot.Files[1].Name)
+	ts.Equal([]string{"a.txt", "sub/b.txt"}, snapshot.Copied())
+	ts.Equal("world", ts.readFile(ts.target, snapshot.ID+"/sub/b.txt"))
+
+	ids, err := vfsbackup.Snapshots(ts.target)
+	ts.NoError(err)
+	ts.Equal([]string{snapshot.ID}, ids)
+
+	read, err := vfsbackup.ReadSnapshot(ts.target, snapshot.ID)
+	ts.NoError(err)
+	ts.Len(read.Files, 2)
+	ts.Equal(snapshot.Files[1].Name, read.Files[1].Name)
+	ts.Equal(snapshot.Files[1].Checksum, read.Files[1].Checksum)
+	ts.Equal(snapshot.ID, read.Files[1].Snapshot)
+	ts.True(snapshot.Created.Equal(read.Created))
+
+	// a full backup copies everything again
+	second, err := vfsbackup.Backup(ts.source, ts.target, vfsbackup.Options{})
+	ts.NoError(err)
+	ts.True(second.ID > snapshot.ID)
+	ts.Equal([]string{"a.txt", "sub/b.txt"}, second.Copied())
+}
+
+func (ts *backupTestSuite) TestIncrementalBackup() {
+	ts.writeFile(ts.source, "a.txt", "hello")
+	ts.writeFile(ts.source, "b.txt", "world")
+
+	// with no prior snapshot, an incremental backup is full
+	first, err := vfsbackup.Backup(ts.source, ts.target, vfsbackup.Options{Incremental: true})
+	ts.NoError(err)
+	ts.False(first.Incremental)
+	ts.Equal([]string{"a.txt", "b.txt"}, first.Copied())
+
+	ts.writeFile(ts.source, "b.txt", "changed")
+	ts.writeFile(ts.source, "c.txt", "new")
+	second, err := vfsbackup.Backup(ts.source, ts.target, vfsbackup.Options{Incremental: true})
+	ts.NoError(err)
+	ts.True(second.Incremental)
+	ts.Equal([]string{"b.txt", "c.txt"}, second.Copied())
+	ts.Equal(first.ID, second.Files[0].Snapshot)
+
+	file, err := ts.target.NewFile(second.ID + "/a.txt")
+	ts.NoError(err)
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.False(exists, "unchanged files aren't copied")
+
+	// unchanged since the last snapshot, which held none of the contents itself
+	third, err := vfsbackup.Backup(ts.source, ts.target, vfsbackup.Options{Incremental: true})
+	ts.NoError(err)
+	ts.Empty(third.Copied())
+	ts.Equal(first.ID, third.Files[0].Snapshot)
+	ts.Equal(second.ID, third.Files[1].Snapshot)
+}
+
+func (ts *backupTestSuite) TestRestore() {
+	ts.writeFile(ts.source, "a.txt", "hello")
+	ts.writeFile(ts.source, "sub/b.txt", "world")
+	first, err := vfsbackup.Backup(ts.source, ts.target, vfsbackup.Options{Incremental: true})
+	ts.NoError(err)
+
+	ts.writeFile(ts.source, "sub/b.txt", "changed")
+	second, err := vfsbackup.Backup(ts.source, ts.target, vfsbackup.Options{Incremental: true})
+	ts.NoError(err)
+
+	restored := ts.newLocation("restored")
+	ts.NoError(vfsbackup.Restore(ts.target, second.ID, restored))
+	ts.Equal("hello", ts.readFile(restored, "a.txt"))
+	ts.Equal("changed", ts.readFile(restored, "sub/b.txt"))
+
+	ts.NoError(vfsbackup.Restore(ts.target, first.ID, restored))
+	ts.Equal("world", ts.readFile(restored, "sub/b.txt"))
+
+	ts.Equal(vfsbackup.ErrSnapshotNotFound, vfsbackup.Restore(ts.target, "20000101T000000.000000000Z", restored))
+}
+
+func (ts *backupTestSuite) TestRetain() {
+	ts.writeFile(ts.source, "a.txt", "hello")
+	ts.writeFile(ts.source, "b.txt", "world")
+	first, err := vfsbackup.Backup(ts.source, ts.target, vfsbackup.Options{Incremental: true, Retain: 2})
+	ts.NoError(err)
+
+	ts.writeFile(ts.source, "b.txt", "changed")
+	second, err := vfsbackup.Backup(ts.source, ts.target, vfsbackup.Options{Incremental: true, Retain: 2})
+	ts.NoError(err)
+
+	ts.writeFile(ts.source, "c.txt", "new")
+	third, err := vfsbackup.Backup(ts.source, ts.target, vfsbackup.Options{Incremental: true, Retain: 2})
+	ts.NoError(err)
+
+	ids, err := vfsbackup.Snapshots(ts.target)
+	ts.NoError(err)
+	ts.Equal([]string{second.ID, third.ID}, ids)
+
+	// a.txt was held by the pruned snapshot, so it's now held by the oldest kept one
+	kept, err := vfsbackup.ReadSnapshot(ts.target, second.ID)
+	ts.NoError(err)
+	ts.Equal(second.ID, kept.Files[0].Snapshot)
+	kept, err = vfsbackup.ReadSnapshot(ts.target, third.ID)
+	ts.NoError(err)
+	ts.Equal(second.ID, kept.Files[0].Snapshot)
+
+	file, err := ts.target.NewFile(first.ID + "/a.txt")
+	ts.NoError(err)
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+
+	restored := ts.newLocation("restored")
+	ts.NoError(vfsbackup.Restore(ts.target, third.ID, restored))
+	ts.Equal("hello", ts.readFile(restored, "a.txt"))
+	ts.Equal("changed", ts.readFile(restored, "b.txt"))
+	ts.Equal("new", ts.readFile(restored, "c.txt"))
+}
+
+func (ts *backupTestSuite) TestPrune() {
+	ts.writeFile(ts.source, "a.txt", "hello")
+	for i := 0; i < 3; i++ {
+		_, err := vfsbackup.Backup(ts.source, ts.target, vfsbackup.Options{})
+		ts.NoError(err)
+	}
+	ids, err := vfsbackup.Snapshots(ts.target)
+	ts.NoError(err)
+	ts.Len(ids, 3)
+
+	deleted, err := vfsbackup.Prune(ts.target, 0)
+	ts.NoError(err)
+	ts.Empty(deleted)
+
+	deleted, err = vfsbackup.Prune(ts.target, 1)
+	ts.NoError(err)
+	ts.Equal(ids[:2], deleted)
+
+	remaining, err := vfsbackup.Snapshots(ts.target)
+	ts.NoError(err)
+	ts.Equal(ids[2:], remaining)
+}
+
+func (ts *backupTestSuite) TestMemTarget() {
+	ts.writeFile(ts.source, "a.txt", "hello")
+	target, err := mem.NewFileSystem().NewLocation("backups", "/snapshots/")
+	ts.NoError(err)
+
+	snapshot, err := vfsbackup.Backup(ts.source, target, vfsbackup.Options{Incremental: true})
+	ts.NoError(err)
+	ids, err := vfsbackup.Snapshots(target)
+	ts.NoError(err)
+	ts.Equal([]string{snapshot.ID}, ids)
+
+	restored := ts.newLocation("restored")
+	ts.NoError(vfsbackup.Restore(target, snapshot.ID, restored))
+	ts.Equal("hello", ts.readFile(restored, "a.txt"))
+}
+
+func TestBackup(t *testing.T) {
+	suite.Run(t, new(backupTestSuite))
+}
@@ -0,0 +1,35 @@
+/*
+Package vfsbackup takes snapshots of the files at a vfs.Location, into another Location on any backend, and restores
+them.
+
+Usage
+
+Take a snapshot, keeping the newest 7:
+
+  snapshot, err := vfsbackup.Backup(source, target, vfsbackup.Options{Incremental: true, Retain: 7})
+
+List the snapshots in a target, oldest first, and restore one:
+
+  ids, err := vfsbackup.Snapshots(target)
+  err = vfsbackup.Restore(target, ids[len(ids)-1], restoreLocation)
+
+Layout
+
+Each snapshot has an ID made from the UTC time it was taken, ie: "20200102T150405.000000000Z", so IDs sort in the order
+the snapshots were taken.  The contents of the files a snapshot copied are stored beneath the target, under the
+location named by its ID, at their paths relative to the source.  The snapshot itself is written last, as JSON, to the
+file named by its ID with a ".json" extension, so a snapshot that didn't complete isn't listed.
+
+A full snapshot copies every file.  An incremental snapshot only copies files that are new or whose size or sha256
+checksum has changed since the latest snapshot; the others are recorded as held by the snapshot that last copied them.
+Either way, every file is read to compute its checksum.
+
+Files beneath the source are included where its backend can list sub-locations (s3, gs and os); otherwise, as with
+Location.List, only the files directly at the source are.
+
+Retention
+
+Prune deletes all but the newest snapshots.  Contents still needed by a snapshot that's kept are first copied into the
+oldest kept snapshot that needs them, so every kept snapshot can still be restored.
+*/
+package vfsbackup
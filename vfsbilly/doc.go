@@ -0,0 +1,25 @@
+/*
+Package vfsbilly adapts a vfs.Location to github.com/go-git/go-billy's billy.Filesystem, so go-git can clone and check
+out repositories directly onto any vfs backend (ie: s3 or sftp) without an intermediate disk.
+
+Usage
+
+Use NewFilesystem to present a vfs.Location as a billy.Filesystem:
+
+  loc, err := vfssimple.NewLocation("s3://mybucket/snapshots/myrepo/")
+  if err != nil {
+      return err
+  }
+  fs := vfsbilly.NewFilesystem(loc)
+
+  // check out the worktree onto s3, keeping git's own storage in memory
+  _, err = git.Clone(memory.NewStorage(), fs, &git.CloneOptions{URL: "https://github.com/c2fo/vfs.git"})
+
+Limitations
+
+Files are opened through vfsafero.Fs, so its rules apply: directories exist only while they contain files and files
+opened for writing must be opened with os.O_TRUNC or os.O_APPEND (see the vfsafero package documentation).  In billy
+terms, files can't be read and written through the same handle, Lock and Unlock do nothing, and symlinks aren't
+supported, so Filesystem reports only the read, write and seek capabilities.
+*/
+package vfsbilly
@@ -0,0 +1,136 @@
+package vfsbilly
+
+import (
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/spf13/afero"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+	"github.com/c2fo/vfs/v5/vfsafero"
+)
+
+// Filesystem implements billy.Filesystem on top of a vfs.Location.  Paths are relative to the location, so
+// "/src/main.go" and "src/main.go" both refer to the file src/main.go beneath it.
+type Filesystem struct {
+	location vfs.Location
+	fs       *vfsafero.Fs
+}
+
+// NewFilesystem returns a billy.Filesystem of the files beneath location.
+func NewFilesystem(location vfs.Location) *Filesystem {
+	return &Filesystem{location: location, fs: vfsafero.NewFs(location)}
+}
+
+// Create creates or truncates the named file, opening it for writing.
+func (fs *Filesystem) Create(filename string) (billy.File, error) {
+	return fs.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+// Open opens the named file for reading.
+func (fs *Filesystem) Open(filename string) (billy.File, error) {
+	return fs.OpenFile(filename, os.O_RDONLY, 0)
+}
+
+// OpenFile opens the named file with the given flags, as vfsafero.Fs.OpenFile does.  perm is ignored.
+func (fs *Filesystem) OpenFile(filename string, flag int, perm os.FileMode) (billy.File, error) {
+	f, err := fs.fs.OpenFile(filename, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &file{File: f}, nil
+}
+
+// Stat returns a FileInfo describing the named file or directory.
+func (fs *Filesystem) Stat(filename string) (os.FileInfo, error) {
+	return fs.fs.Stat(filename)
+}
+
+// Rename moves oldpath to newpath.  Only files can be renamed.
+func (fs *Filesystem) Rename(oldpath, newpath string) error {
+	return fs.fs.Rename(oldpath, newpath)
+}
+
+// Remove removes the named file, or directory if it's empty.
+func (fs *Filesystem) Remove(filename string) error {
+	return fs.fs.Remove(filename)
+}
+
+// Join joins path elements with slashes, as vfs paths always use them.
+func (fs *Filesystem) Join(elem ...string) string {
+	return path.Join(elem...)
+}
+
+// TempFile creates a new file in dir with a name beginning with prefix, opening it for writing.  As with billy's other
+// file systems, an empty dir means os.TempDir(), taken relative to the location.
+func (fs *Filesystem) TempFile(dir, prefix string) (billy.File, error) {
+	return util.TempFile(fs, dir, prefix)
+}
+
+// ReadDir returns a FileInfo for each file and directory in the named directory, sorted by name.
+func (fs *Filesystem) ReadDir(path string) ([]os.FileInfo, error) {
+	return afero.ReadDir(fs.fs, path)
+}
+
+// MkdirAll does nothing, since vfs locations exist implicitly while they contain files.
+func (fs *Filesystem) MkdirAll(filename string, perm os.FileMode) error {
+	return fs.fs.MkdirAll(filename, perm)
+}
+
+// Lstat is the same as Stat, since there are no symlinks.
+func (fs *Filesystem) Lstat(filename string) (os.FileInfo, error) {
+	return fs.Stat(filename)
+}
+
+// Symlink returns billy.ErrNotSupported, since vfs has no symlinks.
+func (fs *Filesystem) Symlink(target, link string) error {
+	return &os.LinkError{Op: "symlink", Old: target, New: link, Err: billy.ErrNotSupported}
+}
+
+// Readlink returns billy.ErrNotSupported, since vfs has no symlinks.
+func (fs *Filesystem) Readlink(link string) (string, error) {
+	return "", &os.PathError{Op: "readlink", Path: link, Err: billy.ErrNotSupported}
+}
+
+// Chroot returns a Filesystem of the files beneath path.
+func (fs *Filesystem) Chroot(path string) (billy.Filesystem, error) {
+	rel := strings.TrimPrefix(fs.Join("/", path), "/")
+	if rel == "" {
+		return NewFilesystem(fs.location), nil
+	}
+	location, err := fs.location.NewLocation(utils.EnsureTrailingSlash(rel))
+	if err != nil {
+		return nil, err
+	}
+	return NewFilesystem(location), nil
+}
+
+// Root returns the URI of the Filesystem's location.
+func (fs *Filesystem) Root() string {
+	return fs.location.URI()
+}
+
+// Capabilities returns the read, write and seek capabilities.  Files can't be read and written through the same
+// handle and aren't locked, and truncation depends on the backend.
+func (fs *Filesystem) Capabilities() billy.Capability {
+	return billy.ReadCapability | billy.WriteCapability | billy.SeekCapability
+}
+
+// file adds billy's Lock and Unlock to the afero.File returned by vfsafero.Fs.
+type file struct {
+	afero.File
+}
+
+// Lock does nothing, since vfs files can't be locked.
+func (f *file) Lock() error {
+	return nil
+}
+
+// Unlock does nothing, since vfs files can't be locked.
+func (f *file) Unlock() error {
+	return nil
+}
@@ -0,0 +1,127 @@
+package vfsbilly
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/stretchr/testify/suite"
+
+	_os "github.com/c2fo/vfs/v5/backend/os"
+)
+
+type filesystemTestSuite struct {
+	suite.Suite
+	dir string
+	fs  *Filesystem
+}
+
+func (ts *filesystemTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfsbilly_test")
+	ts.NoError(err)
+	ts.dir = dir
+	location, err := _os.NewFileSystem().NewLocation("", dir+"/")
+	ts.NoError(err)
+	ts.fs = NewFilesystem(location)
+}
+
+func (ts *filesystemTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *filesystemTestSuite) TestCreateAndOpen() {
+	f, err := ts.fs.Create("src/main.go")
+	ts.NoError(err)
+	ts.Equal("src/main.go", f.Name())
+	ts.NoError(f.Lock())
+	_, err = f.Write([]byte("package main\n"))
+	ts.NoError(err)
+	ts.NoError(f.Unlock())
+	ts.NoError(f.Close())
+
+	data, err := ioutil.ReadFile(filepath.Join(ts.dir, "src", "main.go"))
+	ts.NoError(err)
+	ts.Equal("package main\n", string(data))
+
+	data, err = readFile(ts.fs, "/src/main.go")
+	ts.NoError(err)
+	ts.Equal("package main\n", string(data))
+
+	info, err := ts.fs.Lstat("src/main.go")
+	ts.NoError(err)
+	ts.Equal(int64(13), info.Size())
+
+	_, err = ts.fs.Open("src/missing.go")
+	ts.True(os.IsNotExist(err))
+}
+
+func (ts *filesystemTestSuite) TestTempFileAndRename() {
+	ts.NoError(ts.fs.MkdirAll("objects/pack", 0755))
+	f, err := ts.fs.TempFile("objects/pack", "tmp_pack_")
+	ts.NoError(err)
+	ts.Contains(f.Name(), "objects/pack/tmp_pack_")
+	_, err = f.Write([]byte("PACK"))
+	ts.NoError(err)
+	ts.NoError(f.Close())
+
+	ts.NoError(ts.fs.Rename(f.Name(), ts.fs.Join("objects", "pack", "pack-1.pack")))
+	infos, err := ts.fs.ReadDir("objects")
+	ts.NoError(err)
+	ts.Len(infos, 1)
+	ts.Equal("pack", infos[0].Name())
+	ts.True(infos[0].IsDir())
+	infos, err = ts.fs.ReadDir("/objects/pack")
+	ts.NoError(err)
+	ts.Len(infos, 1)
+	ts.Equal("pack-1.pack", infos[0].Name())
+
+	ts.NoError(ts.fs.Remove("objects/pack/pack-1.pack"))
+	_, err = ts.fs.Stat("objects/pack/pack-1.pack")
+	ts.True(os.IsNotExist(err))
+}
+
+func (ts *filesystemTestSuite) TestChroot() {
+	ts.NoError(util.WriteFile(ts.fs, "repo/README.md", []byte("# repo"), 0644))
+
+	chroot, err := ts.fs.Chroot("/repo")
+	ts.NoError(err)
+	ts.Equal("file://"+ts.dir+"/repo/", chroot.Root())
+	data, err := readFile(chroot, "README.md")
+	ts.NoError(err)
+	ts.Equal("# repo", string(data))
+
+	// paths can't escape the root
+	data, err = readFile(chroot, "../repo/README.md")
+	ts.True(os.IsNotExist(err))
+	ts.Nil(data)
+
+	root, err := ts.fs.Chroot("/")
+	ts.NoError(err)
+	ts.Equal(ts.fs.Root(), root.Root())
+}
+
+func (ts *filesystemTestSuite) TestUnsupported() {
+	ts.Equal(billy.ReadCapability|billy.WriteCapability|billy.SeekCapability, ts.fs.Capabilities())
+	ts.False(billy.CapabilityCheck(ts.fs, billy.LockCapability))
+
+	err := ts.fs.Symlink("target", "link")
+	ts.Equal(billy.ErrNotSupported, err.(*os.LinkError).Err)
+	_, err = ts.fs.Readlink("link")
+	ts.Equal(billy.ErrNotSupported, err.(*os.PathError).Err)
+}
+
+func readFile(fs billy.Basic, name string) ([]byte, error) {
+	f, err := fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return ioutil.ReadAll(f)
+}
+
+func TestFilesystem(t *testing.T) {
+	suite.Run(t, new(filesystemTestSuite))
+}
@@ -0,0 +1,29 @@
+/*
+Package vfschaos wraps any vfs.FileSystem to inject faults, for testing that pipelines built on vfs are resilient to
+the failures real backends have, without needing to provoke them from the real thing.
+
+Usage
+
+Wrap a file system, and use it in its place:
+
+  fs := vfschaos.NewFileSystem(s3.NewFileSystem(), vfschaos.Options{
+      Latency:          50 * time.Millisecond,
+      Jitter:           100 * time.Millisecond,
+      ThrottleRate:     0.05,
+      PartialWriteRate: 0.01,
+      Seed:             42,
+  })
+  file, err := fs.NewFile("mybucket", "/path/to/file.txt")
+
+Faults
+
+Every call that can fail, to the file system or to a Location or File it returns, is first delayed by Latency plus up to
+Jitter.  It then fails, without reaching the wrapped file system, with probability ThrottleRate, returning
+ErrSlowDown: an awserr.RequestFailure with the code SlowDown and status 503, as s3 returns when requests are made too
+quickly.  A Write fails with probability PartialWriteRate after writing only part of its data, returning
+ErrPartialWrite along with the number of bytes written.
+
+Set Ops to limit faults to some calls, named by type and method, ie: "File.Write" or "Location.List".  Set Seed for a
+reproducible sequence of faults.  Faults returns the number of faults injected so far.
+*/
+package vfschaos
@@ -0,0 +1,126 @@
+package vfschaos
+
+import (
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/vfswrap"
+)
+
+// File is a vfs.File of a FileSystem, injecting faults into calls to the wrapped file.
+type File struct {
+	*vfswrap.File
+	fileSystem *FileSystem
+}
+
+// Close implements io.Closer.
+func (f *File) Close() error {
+	if err := f.fileSystem.fault("File.Close"); err != nil {
+		return err
+	}
+	return f.File.Close()
+}
+
+// Read implements io.Reader.
+func (f *File) Read(p []byte) (int, error) {
+	if err := f.fileSystem.fault("File.Read"); err != nil {
+		return 0, err
+	}
+	return f.File.Read(p)
+}
+
+// Seek implements io.Seeker.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if err := f.fileSystem.fault("File.Seek"); err != nil {
+		return 0, err
+	}
+	return f.File.Seek(offset, whence)
+}
+
+// Write implements io.Writer.  A partial write writes the first part of p to the wrapped file, then fails with
+// ErrPartialWrite.
+func (f *File) Write(p []byte) (int, error) {
+	if err := f.fileSystem.fault("File.Write"); err != nil {
+		return 0, err
+	}
+	if n := f.fileSystem.partialWrite(len(p)); n >= 0 {
+		written, err := f.File.Write(p[:n])
+		if err != nil {
+			return written, err
+		}
+		return written, ErrPartialWrite
+	}
+	return f.File.Write(p)
+}
+
+// Exists implements vfs.File.
+func (f *File) Exists() (bool, error) {
+	if err := f.fileSystem.fault("File.Exists"); err != nil {
+		return false, err
+	}
+	return f.File.Exists()
+}
+
+// CopyToLocation implements vfs.File.
+func (f *File) CopyToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	if err := f.fileSystem.fault("File.CopyToLocation"); err != nil {
+		return nil, err
+	}
+	return f.File.CopyToLocation(location, opts...)
+}
+
+// CopyToFile implements vfs.File.
+func (f *File) CopyToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	if err := f.fileSystem.fault("File.CopyToFile"); err != nil {
+		return err
+	}
+	return f.File.CopyToFile(file, opts...)
+}
+
+// MoveToLocation implements vfs.File.
+func (f *File) MoveToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	if err := f.fileSystem.fault("File.MoveToLocation"); err != nil {
+		return nil, err
+	}
+	return f.File.MoveToLocation(location, opts...)
+}
+
+// MoveToFile implements vfs.File.
+func (f *File) MoveToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	if err := f.fileSystem.fault("File.MoveToFile"); err != nil {
+		return err
+	}
+	return f.File.MoveToFile(file, opts...)
+}
+
+// Delete implements vfs.File.
+func (f *File) Delete() error {
+	if err := f.fileSystem.fault("File.Delete"); err != nil {
+		return err
+	}
+	return f.File.Delete()
+}
+
+// LastModified implements vfs.File.
+func (f *File) LastModified() (*time.Time, error) {
+	if err := f.fileSystem.fault("File.LastModified"); err != nil {
+		return nil, err
+	}
+	return f.File.LastModified()
+}
+
+// Size implements vfs.File.
+func (f *File) Size() (uint64, error) {
+	if err := f.fileSystem.fault("File.Size"); err != nil {
+		return 0, err
+	}
+	return f.File.Size()
+}
+
+// Touch implements vfs.File.
+func (f *File) Touch() error {
+	if err := f.fileSystem.fault("File.Touch"); err != nil {
+		return err
+	}
+	return f.File.Touch()
+}
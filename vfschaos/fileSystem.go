@@ -0,0 +1,160 @@
+package vfschaos
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/vfswrap"
+)
+
+// ErrSlowDown is returned by calls failed by throttling.  It's the error s3 returns when requests are made too quickly.
+var ErrSlowDown error = awserr.NewRequestFailure(
+	awserr.New("SlowDown", "Please reduce your request rate.", nil), http.StatusServiceUnavailable, "vfschaos")
+
+// ErrPartialWrite is returned by writes which fail after writing only part of their data.
+var ErrPartialWrite = errors.New("vfschaos: write failed after writing part of the data")
+
+// Options configures the faults a FileSystem injects.  Rates are probabilities, from 0 (never) to 1 (always).
+type Options struct {
+	// Latency delays every call, by up to Jitter more at random.
+	Latency time.Duration
+	Jitter  time.Duration
+	// ThrottleRate is the rate at which calls fail with ErrSlowDown.
+	ThrottleRate float64
+	// PartialWriteRate is the rate at which writes fail with ErrPartialWrite after writing part of their data.
+	PartialWriteRate float64
+	// Ops limits faults to the named calls, ie: "File.Write" or "Location.List".  All calls are faulted if empty.
+	Ops []string
+	// Seed seeds the random faults, for a reproducible sequence of them.  The current time is used if 0.
+	Seed int64
+}
+
+// FileSystem is a vfs.FileSystem which injects faults into the calls made to a wrapped file system.
+type FileSystem struct {
+	*vfswrap.FileSystem
+	options Options
+	ops     map[string]bool
+	mu      sync.Mutex
+	random  *rand.Rand
+	faults  int
+}
+
+// NewFileSystem returns a FileSystem which injects faults, configured by options, into calls to fs.
+func NewFileSystem(fs vfs.FileSystem, options Options) *FileSystem {
+	seed := options.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	var ops map[string]bool
+	if len(options.Ops) > 0 {
+		ops = make(map[string]bool, len(options.Ops))
+		for _, op := range options.Ops {
+			ops[op] = true
+		}
+	}
+	wrapper := &FileSystem{
+		options: options,
+		ops:     ops,
+		random:  rand.New(rand.NewSource(seed)),
+	}
+	wrapper.FileSystem = vfswrap.NewFileSystem(fs, wrapper)
+	return wrapper
+}
+
+// Faults returns the number of faults injected so far, not counting latency.
+func (fs *FileSystem) Faults() int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.faults
+}
+
+// NewFile implements vfs.FileSystem.
+func (fs *FileSystem) NewFile(volume string, absFilePath string) (vfs.File, error) {
+	if err := fs.fault("FileSystem.NewFile"); err != nil {
+		return nil, err
+	}
+	return fs.FileSystem.NewFile(volume, absFilePath)
+}
+
+// NewLocation implements vfs.FileSystem.
+func (fs *FileSystem) NewLocation(volume string, absLocPath string) (vfs.Location, error) {
+	if err := fs.fault("FileSystem.NewLocation"); err != nil {
+		return nil, err
+	}
+	return fs.FileSystem.NewLocation(volume, absLocPath)
+}
+
+// Name implements vfs.FileSystem.
+func (fs *FileSystem) Name() string {
+	return "Chaos " + fs.Wrapped().Name()
+}
+
+// WrapFile implements vfswrap.Wrapper.
+func (fs *FileSystem) WrapFile(file vfs.File) vfs.File {
+	return &File{File: vfswrap.NewFile(file, fs), fileSystem: fs}
+}
+
+// WrapLocation implements vfswrap.Wrapper.
+func (fs *FileSystem) WrapLocation(location vfs.Location) vfs.Location {
+	return &Location{Location: vfswrap.NewLocation(location, fs), fileSystem: fs}
+}
+
+// fault delays a call to op, then returns ErrSlowDown if it's to be throttled.
+func (fs *FileSystem) fault(op string) error {
+	if !fs.faulted(op) {
+		return nil
+	}
+	fs.delay()
+	if fs.chance(fs.options.ThrottleRate) {
+		return ErrSlowDown
+	}
+	return nil
+}
+
+// partialWrite returns the number of bytes of a write of n bytes to write before failing it, or -1 to write them all.
+func (fs *FileSystem) partialWrite(n int) int {
+	if !fs.faulted("File.Write") || n == 0 || !fs.chance(fs.options.PartialWriteRate) {
+		return -1
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.random.Intn(n)
+}
+
+// faulted returns true if faults are injected into calls to op.
+func (fs *FileSystem) faulted(op string) bool {
+	return fs.ops == nil || fs.ops[op]
+}
+
+// delay sleeps for Latency plus up to Jitter.
+func (fs *FileSystem) delay() {
+	latency := fs.options.Latency
+	if fs.options.Jitter > 0 {
+		fs.mu.Lock()
+		latency += time.Duration(fs.random.Int63n(int64(fs.options.Jitter)))
+		fs.mu.Unlock()
+	}
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+}
+
+// chance returns true with probability rate, counting it as a fault.
+func (fs *FileSystem) chance(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.random.Float64() >= rate {
+		return false
+	}
+	fs.faults++
+	return true
+}
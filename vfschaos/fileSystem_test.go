@@ -0,0 +1,149 @@
+package vfschaos_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/stretchr/testify/suite"
+
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/vfschaos"
+	"github.com/c2fo/vfs/v5/vfstest"
+)
+
+type fileSystemTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (ts *fileSystemTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfschaos_test")
+	ts.NoError(err)
+	ts.dir = dir
+}
+
+func (ts *fileSystemTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *fileSystemTestSuite) newFileSystem(options vfschaos.Options) *vfschaos.FileSystem {
+	return vfschaos.NewFileSystem(_os.NewFileSystem(), options)
+}
+
+func (ts *fileSystemTestSuite) contents(name string) string {
+	data, err := ioutil.ReadFile(ts.dir + "/" + name)
+	ts.NoError(err)
+	return string(data)
+}
+
+func (ts *fileSystemTestSuite) TestNoFaults() {
+	fs := ts.newFileSystem(vfschaos.Options{})
+	ts.Equal(_os.Scheme, fs.Scheme())
+	ts.Equal("Chaos os", fs.Name())
+
+	file, err := fs.NewFile("", ts.dir+"/file.txt")
+	ts.NoError(err)
+	_, err = file.Write([]byte("hello"))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+	ts.Equal("hello", ts.contents("file.txt"))
+
+	location, err := fs.NewLocation("", ts.dir+"/copies/")
+	ts.NoError(err)
+	copied, err := file.CopyToLocation(location)
+	ts.NoError(err)
+	ts.IsType(&vfschaos.File{}, copied, "returned files are wrapped")
+	ts.Equal(fs, copied.Location().FileSystem())
+	ts.Equal("hello", ts.contents("copies/file.txt"))
+	ts.Equal(0, fs.Faults())
+}
+
+func (ts *fileSystemTestSuite) TestThrottle() {
+	fs := ts.newFileSystem(vfschaos.Options{ThrottleRate: 1, Ops: []string{"Location.List", "File.Size"}})
+	location, err := fs.NewLocation("", ts.dir+"/")
+	ts.NoError(err, "calls not in Ops aren't faulted")
+
+	_, err = location.List()
+	ts.Equal(vfschaos.ErrSlowDown, err)
+	failure, ok := err.(awserr.RequestFailure)
+	ts.True(ok, "throttling errors are aws request failures")
+	ts.Equal("SlowDown", failure.Code())
+	ts.Equal(503, failure.StatusCode())
+
+	file, err := location.NewFile("file.txt")
+	ts.NoError(err)
+	ts.NoError(file.Touch())
+	_, err = file.Size()
+	ts.Equal(vfschaos.ErrSlowDown, err)
+	ts.Equal(2, fs.Faults())
+}
+
+func (ts *fileSystemTestSuite) TestPartialWrite() {
+	fs := ts.newFileSystem(vfschaos.Options{PartialWriteRate: 1})
+	file, err := fs.NewFile("", ts.dir+"/file.txt")
+	ts.NoError(err)
+
+	data := []byte("0123456789")
+	n, err := file.Write(data)
+	ts.Equal(vfschaos.ErrPartialWrite, err)
+	ts.True(n < len(data), "only part of the data is written")
+	ts.NoError(file.Close())
+	ts.Equal(string(data[:n]), ts.contents("file.txt"))
+	ts.Equal(1, fs.Faults())
+}
+
+func (ts *fileSystemTestSuite) TestLatency() {
+	fs := ts.newFileSystem(vfschaos.Options{Latency: 20 * time.Millisecond, Jitter: 10 * time.Millisecond,
+		Ops: []string{"File.Exists"}})
+	file, err := fs.NewFile("", ts.dir+"/file.txt")
+	ts.NoError(err)
+
+	start := time.Now()
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.False(exists)
+	ts.True(time.Since(start) >= 20*time.Millisecond, "calls are delayed")
+	ts.Equal(0, fs.Faults(), "latency isn't counted as a fault")
+}
+
+func (ts *fileSystemTestSuite) TestSeed() {
+	faults := func() []bool {
+		fs := ts.newFileSystem(vfschaos.Options{ThrottleRate: 0.5, Seed: 42})
+		location, err := fs.NewLocation("", ts.dir+"/")
+		for err != nil {
+			location, err = fs.NewLocation("", ts.dir+"/")
+		}
+		var results []bool
+		for i := 0; i < 20; i++ {
+			_, err := location.List()
+			results = append(results, err != nil)
+		}
+		return results
+	}
+	first := faults()
+	ts.Equal(first, faults(), "the same seed gives the same faults")
+	ts.Contains(first, true)
+	ts.Contains(first, false)
+}
+
+func TestFileSystem(t *testing.T) {
+	suite.Run(t, new(fileSystemTestSuite))
+}
+
+func TestConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vfschaos_conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// with no faults configured, the wrapper behaves just as the wrapped file system
+	location, err := vfschaos.NewFileSystem(_os.NewFileSystem(), vfschaos.Options{}).NewLocation("", dir+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vfstest.Run(t, location)
+}
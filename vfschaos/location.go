@@ -0,0 +1,78 @@
+package vfschaos
+
+import (
+	"regexp"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/vfswrap"
+)
+
+// Location is a vfs.Location of a FileSystem, injecting faults into calls to the wrapped location.
+type Location struct {
+	*vfswrap.Location
+	fileSystem *FileSystem
+}
+
+// List implements vfs.Location.
+func (l *Location) List() ([]string, error) {
+	if err := l.fileSystem.fault("Location.List"); err != nil {
+		return nil, err
+	}
+	return l.Location.List()
+}
+
+// ListByPrefix implements vfs.Location.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	if err := l.fileSystem.fault("Location.ListByPrefix"); err != nil {
+		return nil, err
+	}
+	return l.Location.ListByPrefix(prefix)
+}
+
+// ListByRegex implements vfs.Location.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	if err := l.fileSystem.fault("Location.ListByRegex"); err != nil {
+		return nil, err
+	}
+	return l.Location.ListByRegex(regex)
+}
+
+// Exists implements vfs.Location.
+func (l *Location) Exists() (bool, error) {
+	if err := l.fileSystem.fault("Location.Exists"); err != nil {
+		return false, err
+	}
+	return l.Location.Exists()
+}
+
+// NewLocation implements vfs.Location.
+func (l *Location) NewLocation(relLocPath string) (vfs.Location, error) {
+	if err := l.fileSystem.fault("Location.NewLocation"); err != nil {
+		return nil, err
+	}
+	return l.Location.NewLocation(relLocPath)
+}
+
+// ChangeDir implements vfs.Location.
+func (l *Location) ChangeDir(relLocPath string) error {
+	if err := l.fileSystem.fault("Location.ChangeDir"); err != nil {
+		return err
+	}
+	return l.Location.ChangeDir(relLocPath)
+}
+
+// NewFile implements vfs.Location.
+func (l *Location) NewFile(relFilePath string) (vfs.File, error) {
+	if err := l.fileSystem.fault("Location.NewFile"); err != nil {
+		return nil, err
+	}
+	return l.Location.NewFile(relFilePath)
+}
+
+// DeleteFile implements vfs.Location.
+func (l *Location) DeleteFile(relFilePath string) error {
+	if err := l.fileSystem.fault("Location.DeleteFile"); err != nil {
+		return err
+	}
+	return l.Location.DeleteFile(relFilePath)
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+func cp(args []string) error {
+	return transfer("cp", args, "copied", vfs.File.CopyToFile)
+}
+
+func mv(args []string) error {
+	return transfer("mv", args, "moved", vfs.File.MoveToFile)
+}
+
+// transfer copies or moves, with op, a file to a file or location, or the files at a location to another location.
+func transfer(name string, args []string, done string,
+	op func(source, target vfs.File, opts ...vfs.CopyOption) error) error {
+	flags := newFlagSet(name, name+" [-r] <src> <dst>")
+	recursive := flags.Bool("r", false, "copies or moves every file at and beneath a source location")
+	args, err := parseArgs(flags, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	src, dst := args[0], args[1]
+
+	if !isLocation(src) {
+		source, err := newFile(src)
+		if err != nil {
+			return err
+		}
+		target, err := transferTarget(source, dst)
+		if err != nil {
+			return err
+		}
+		if err := op(source, target); err != nil {
+			return err
+		}
+		fmt.Printf("%s %s to %s\n", done, source, target)
+		return nil
+	}
+
+	if !*recursive {
+		return notRecursiveError(src)
+	}
+	if !isLocation(dst) {
+		return fmt.Errorf("%s is a location, so %s must be too", src, dst)
+	}
+	source, err := newLocation(src)
+	if err != nil {
+		return err
+	}
+	target, err := newLocation(dst)
+	if err != nil {
+		return err
+	}
+	return walk(source, true, func(file vfs.File, relPath string) error {
+		targetFile, err := target.NewFile(relPath)
+		if err != nil {
+			return err
+		}
+		if err := op(file, targetFile); err != nil {
+			return err
+		}
+		fmt.Printf("%s %s to %s\n", done, file, targetFile)
+		return nil
+	})
+}
+
+// transferTarget returns the file source is copied or moved to: dst itself, or a file of the same name if dst is a
+// location.
+func transferTarget(source vfs.File, dst string) (vfs.File, error) {
+	if !isLocation(dst) {
+		return newFile(dst)
+	}
+	location, err := newLocation(dst)
+	if err != nil {
+		return nil, err
+	}
+	return location.NewFile(source.Name())
+}
+
+func ls(args []string) error {
+	flags := newFlagSet("ls", "ls [-l] <uri>")
+	long := flags.Bool("l", false, "also prints each file's size and last modified time")
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	if !isLocation(args[0]) {
+		file, err := newFile(args[0])
+		if err != nil {
+			return err
+		}
+		return printFile(file, *long)
+	}
+
+	location, err := newLocation(args[0])
+	if err != nil {
+		return err
+	}
+	files, locations, err := listDir(location)
+	if err != nil {
+		return err
+	}
+	for _, sub := range locations {
+		name := subName(location, sub)
+		if *long {
+			fmt.Printf("%12s  %-25s  %s\n", "-", "-", name)
+		} else {
+			fmt.Println(name)
+		}
+	}
+	for _, file := range files {
+		if err := printFile(file, *long); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printFile prints the name of file, after its size and last modified time if long is true.
+func printFile(file vfs.File, long bool) error {
+	if !long {
+		fmt.Println(file.Name())
+		return nil
+	}
+	info, err := utils.Stat(file)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%12d  %-25s  %s\n", info.Size(), info.ModTime().Format(time.RFC3339), file.Name())
+	return nil
+}
+
+func rm(args []string) error {
+	flags := newFlagSet("rm", "rm [-r] <uri>...")
+	recursive := flags.Bool("r", false, "removes every file at and beneath a location")
+	args, err := parseArgs(flags, args, 1, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, uri := range args {
+		if !isLocation(uri) {
+			file, err := newFile(uri)
+			if err != nil {
+				return err
+			}
+			if err := deleteFile(file); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !*recursive {
+			return notRecursiveError(uri)
+		}
+		location, err := newLocation(uri)
+		if err != nil {
+			return err
+		}
+		if err := walk(location, true, func(file vfs.File, _ string) error {
+			return deleteFile(file)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteFile(file vfs.File) error {
+	if err := file.Delete(); err != nil {
+		return err
+	}
+	fmt.Printf("removed %s\n", file)
+	return nil
+}
+
+func cat(args []string) error {
+	flags := newFlagSet("cat", "cat <uri>...")
+	args, err := parseArgs(flags, args, 1, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, uri := range args {
+		file, err := newFile(uri)
+		if err != nil {
+			return err
+		}
+		if _, err := utils.Copy(os.Stdout, file); err != nil {
+			_ = file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncOptions are the flags of the sync command.
+type syncOptions struct {
+	recursive bool
+	delete    bool
+	checksum  bool
+	dryRun    bool
+}
+
+func sync(args []string) error {
+	flags := newFlagSet("sync", "sync [-r] [-delete] [-checksum] [-dry-run] <src> <dst>")
+	opts := syncOptions{}
+	flags.BoolVar(&opts.recursive, "r", false, "also syncs sub-locations")
+	flags.BoolVar(&opts.delete, "delete", false, "removes files from the target that aren't in the source")
+	flags.BoolVar(&opts.checksum, "checksum", false,
+		"compares the checksums of files of the same size, rather than only their sizes")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "prints what would be done without doing it")
+	args, err := parseArgs(flags, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	if !isLocation(args[0]) || !isLocation(args[1]) {
+		return fmt.Errorf("sync requires two locations")
+	}
+
+	source, err := newLocation(args[0])
+	if err != nil {
+		return err
+	}
+	target, err := newLocation(args[1])
+	if err != nil {
+		return err
+	}
+	return syncLocations(source, target, opts)
+}
+
+// syncLocations copies the files at source that are missing from target, or differ in size (or checksum), to target,
+// and with opts.delete, removes files at target that aren't at source.  With opts.recursive, sub-locations are synced
+// the same way.
+func syncLocations(source, target vfs.Location, opts syncOptions) error {
+	err := utils.Diff(source, target, utils.DiffOptions{Checksum: opts.checksum}, func(result utils.DiffResult) error {
+		switch result.Kind {
+		case utils.DiffOnlyInA, utils.DiffChanged:
+			targetFile, err := target.NewFile(result.Name)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("copied %s to %s\n", result.A, targetFile)
+			if opts.dryRun {
+				return nil
+			}
+			return result.A.CopyToFile(targetFile)
+		case utils.DiffOnlyInB:
+			if !opts.delete {
+				return nil
+			}
+			fmt.Printf("removed %s\n", result.B)
+			if opts.dryRun {
+				return nil
+			}
+			return result.B.Delete()
+		}
+		return nil
+	})
+	if err != nil || !opts.recursive {
+		return err
+	}
+
+	_, sourceSubs, err := listDir(source)
+	if err != nil {
+		return err
+	}
+	synced := map[string]bool{}
+	for _, sub := range sourceSubs {
+		name := subName(source, sub)
+		synced[name] = true
+		targetSub, err := target.NewLocation(name)
+		if err != nil {
+			return err
+		}
+		if err := syncLocations(sub, targetSub, opts); err != nil {
+			return err
+		}
+	}
+	if !opts.delete {
+		return nil
+	}
+
+	// remove sub-locations of target that aren't in source
+	_, targetSubs, err := listDir(target)
+	if err != nil {
+		return err
+	}
+	for _, sub := range targetSubs {
+		if synced[subName(target, sub)] {
+			continue
+		}
+		if err := walk(sub, true, func(file vfs.File, _ string) error {
+			fmt.Printf("removed %s\n", file)
+			if opts.dryRun {
+				return nil
+			}
+			return file.Delete()
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
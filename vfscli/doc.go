@@ -0,0 +1,33 @@
+/*
+vfscli copies, moves, lists, removes and prints files on any supported system, and syncs locations between them, ie:
+for inspecting and fixing up remote files while debugging.  Complete URI (scheme:// authority/path) required except for
+local file system.  URIs of locations end with a slash.  See github.com/c2fo/vfs docs for authentication.
+
+
+Usage
+
+  vfscli <command> [flags] <uri>...
+
+  cp [-r] <src> <dst>       copies a file, or with -r, every file at and beneath a location
+  mv [-r] <src> <dst>       moves a file, or with -r, every file at and beneath a location
+  ls [-l] <uri>             lists the files and sub-locations at a location, with -l, with sizes and last modified times
+  rm [-r] <uri>...          removes files, or with -r, every file at and beneath a location
+  cat <uri>...              prints the contents of files
+  sync [-r] [-delete] [-checksum] [-dry-run] <src> <dst>
+                            copies files missing from, or of a different size at, one location to another
+  -help                     prints help message
+
+A file copied or moved to a location keeps its name.  sync compares files with utils.Diff; -checksum also compares
+the contents of files of the same size, -delete removes files from the target that aren't in the source and -dry-run
+prints what would be copied and removed without doing it.
+
+Examples
+
+Copy a local file to an S3 prefix
+  vfscli cp /some/local/file.txt s3://mybucket/path/to/
+List an SFTP directory with sizes
+  vfscli ls -l sftp://user@host.com:22/exports/
+Mirror a Google Cloud Storage prefix to S3, including sub-locations
+  vfscli sync -r -delete gs://googlebucket/photos/ s3://awsS3bucket/photos/
+*/
+package main
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+	"github.com/c2fo/vfs/v5/vfssimple"
+)
+
+const usageTemplate = `
+%[1]s copies, moves, lists, removes and prints files on any supported system, and syncs locations between them.
+Complete URI (scheme://authority/path) required except for local filesystem.  Location URIs end with a slash.
+See github.com/c2fo/vfs docs for authentication.
+
+Usage:  %[1]s <command> [flags] <uri>...
+
+    cp [-r] <src> <dst>
+        copies a file, or the files at a location, to a file or location
+    mv [-r] <src> <dst>
+        moves a file, or the files at a location, to a file or location
+    ls [-l] <uri>
+        lists the files and sub-locations at a location
+    rm [-r] <uri>...
+        removes files, or the files at a location
+    cat <uri>...
+        prints the contents of files
+    sync [-r] [-delete] [-checksum] [-dry-run] <src> <dst>
+        copies new and changed files from one location to another
+
+    ie,        %[1]s cp /some/local/file.txt s3://mybucket/path/to/
+    gcs to s3  %[1]s sync -r gs://googlebucket/photos/ s3://awsS3bucket/photos/
+
+Run '%[1]s <command> -help' for a command's flags.
+
+`
+
+// commands are run with the arguments that follow their name.
+var commands = map[string]func(args []string) error{
+	"cp":   cp,
+	"mv":   mv,
+	"ls":   ls,
+	"rm":   rm,
+	"cat":  cat,
+	"sync": sync,
+}
+
+func main() {
+	usage := func() {
+		_, _ = fmt.Fprintf(os.Stdout, usageTemplate, filepath.Base(os.Args[0]))
+	}
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+	if os.Args[1] == "-help" || os.Args[1] == "-h" || os.Args[1] == "help" {
+		usage()
+		os.Exit(0)
+	}
+
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(1)
+	}
+	if err := run(os.Args[2:]); err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(0)
+		}
+		failMessage(err)
+	}
+}
+
+// newFlagSet returns a FlagSet for the named command, which prints usage, ie: "cp [-r] <src> <dst>", along with its
+// flags.
+func newFlagSet(name, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stdout, "\nUsage:  %s %s\n\n", filepath.Base(os.Args[0]), usage)
+		flags.PrintDefaults()
+		fmt.Println()
+	}
+	return flags
+}
+
+// parseArgs parses args with flags, returning an error if fewer than min or more than max (if positive) arguments
+// remain.
+func parseArgs(flags *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() < min || (max > 0 && flags.NArg() > max) {
+		flags.Usage()
+		return nil, fmt.Errorf("wrong number of arguments for %s", flags.Name())
+	}
+	return flags.Args(), nil
+}
+
+// isLocation returns true if uri refers to a location rather than a file.
+func isLocation(uri string) bool {
+	return strings.HasSuffix(uri, "/")
+}
+
+// newFile returns the file at uri, which may be a local path.
+func newFile(uri string) (vfs.File, error) {
+	normalized, err := normalizeArgs(uri)
+	if err != nil {
+		return nil, err
+	}
+	return vfssimple.NewFile(normalized)
+}
+
+// newLocation returns the location at uri, which may be a local path.
+func newLocation(uri string) (vfs.Location, error) {
+	normalized, err := normalizeArgs(uri)
+	if err != nil {
+		return nil, err
+	}
+	return vfssimple.NewLocation(normalized)
+}
+
+func normalizeArgs(str string) (string, error) {
+	u, err := url.Parse(str)
+	if err != nil {
+		return "", err
+	}
+	if u.IsAbs() {
+		return str, nil
+	}
+	absPath, err := filepath.Abs(str)
+	if err != nil {
+		return "", err
+	}
+	// filepath.Abs cleans off the trailing slash of a location
+	if isLocation(str) && !isLocation(absPath) {
+		absPath += "/"
+	}
+	return "file://" + absPath, nil
+}
+
+// walk calls fn with each file at location, and with recursive, each file beneath it, with its path relative to
+// location.  Files are visited in name order, with those at a location before those in its sub-locations.
+func walk(location vfs.Location, recursive bool, fn func(file vfs.File, relPath string) error) error {
+	return walkPrefix(location, "", recursive, fn)
+}
+
+func walkPrefix(location vfs.Location, prefix string, recursive bool, fn func(vfs.File, string) error) error {
+	files, locations, err := listDir(location)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := fn(file, prefix+file.Name()); err != nil {
+			return err
+		}
+	}
+	if !recursive {
+		return nil
+	}
+	for _, sub := range locations {
+		if err := walkPrefix(sub, prefix+subName(location, sub), recursive, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subName returns the path of sub relative to location, ending in a slash, ie: "sub/".
+func subName(location, sub vfs.Location) string {
+	return strings.TrimPrefix(sub.Path(), location.Path())
+}
+
+// listDir returns the files and sub-locations at location, sorted.
+func listDir(location vfs.Location) ([]vfs.File, []vfs.Location, error) {
+	files, locations, err := utils.ListDir(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+	sort.Slice(locations, func(i, j int) bool { return locations[i].Path() < locations[j].Path() })
+	return files, locations, nil
+}
+
+// notRecursiveError is returned for a location given to a command without -r.
+func notRecursiveError(uri string) error {
+	return fmt.Errorf("%s is a location, use -r", uri)
+}
+
+func failMessage(err error) {
+	red := color.New(color.FgHiRed).Add(color.Bold)
+	_, _ = fmt.Fprintf(os.Stderr, red.Sprint("failed\n\n")+"\n%s\n\n", err.Error())
+	os.Exit(1)
+}
@@ -0,0 +1,21 @@
+/*
+Package vfsfake provides a stateful, in-memory vfs.FileSystem for unit tests that records the calls made to it and
+can be scripted to fail them, for testing retry and error handling without setting up mock expectations.
+
+Usage
+
+Use a fake in place of a real file system, scripting the failures to test:
+
+  fs := vfsfake.NewFileSystem()
+  fs.FailOn("File.Write", 2, errors.New("connection reset"))
+
+  err := uploadReport(fs)
+  ...
+  calls := fs.Calls()
+  contents, err := fs.Contents("/reports/daily.csv")
+
+Files are kept by a mem.FileSystem, so what's written can be read back, listed, copied and moved.  Calls are named by
+type and method, ie: "FileSystem.NewFile", "Location.List" or "File.Write".  FailOn fails the nth call to one, or
+every call from then on, without it reaching the underlying file system.
+*/
+package vfsfake
@@ -0,0 +1,119 @@
+package vfsfake
+
+import (
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// fakeFile is a File of a FileSystem.
+type fakeFile struct {
+	fs   *FileSystem
+	file vfs.File
+}
+
+func (f *fakeFile) Close() error {
+	return f.fs.call("File.Close", f.Path(), f.file.Close)
+}
+
+func (f *fakeFile) Read(p []byte) (int, error) {
+	var n int
+	err := f.fs.call("File.Read", f.Path(), func() (err error) {
+		n, err = f.file.Read(p)
+		return err
+	})
+	return n, err
+}
+
+func (f *fakeFile) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	err := f.fs.call("File.Seek", f.Path(), func() (err error) {
+		pos, err = f.file.Seek(offset, whence)
+		return err
+	})
+	return pos, err
+}
+
+func (f *fakeFile) Write(p []byte) (int, error) {
+	var n int
+	err := f.fs.call("File.Write", f.Path(), func() (err error) {
+		n, err = f.file.Write(p)
+		return err
+	})
+	return n, err
+}
+
+func (f *fakeFile) String() string { return f.file.String() }
+
+func (f *fakeFile) Exists() (bool, error) {
+	var exists bool
+	err := f.fs.call("File.Exists", f.Path(), func() (err error) {
+		exists, err = f.file.Exists()
+		return err
+	})
+	return exists, err
+}
+
+func (f *fakeFile) Location() vfs.Location { return f.fs.wrapLocation(f.file.Location()) }
+
+func (f *fakeFile) CopyToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	var file vfs.File
+	err := f.fs.call("File.CopyToLocation", f.Path(), func() (err error) {
+		file, err = f.file.CopyToLocation(unwrapLocation(location), opts...)
+		return err
+	})
+	return f.fs.wrapFile(file), err
+}
+
+func (f *fakeFile) CopyToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	return f.fs.call("File.CopyToFile", f.Path(), func() error {
+		return f.file.CopyToFile(unwrapFile(file), opts...)
+	})
+}
+
+func (f *fakeFile) MoveToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	var file vfs.File
+	err := f.fs.call("File.MoveToLocation", f.Path(), func() (err error) {
+		file, err = f.file.MoveToLocation(unwrapLocation(location), opts...)
+		return err
+	})
+	return f.fs.wrapFile(file), err
+}
+
+func (f *fakeFile) MoveToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	return f.fs.call("File.MoveToFile", f.Path(), func() error {
+		return f.file.MoveToFile(unwrapFile(file), opts...)
+	})
+}
+
+func (f *fakeFile) Delete() error {
+	return f.fs.call("File.Delete", f.Path(), f.file.Delete)
+}
+
+func (f *fakeFile) LastModified() (*time.Time, error) {
+	var lastModified *time.Time
+	err := f.fs.call("File.LastModified", f.Path(), func() (err error) {
+		lastModified, err = f.file.LastModified()
+		return err
+	})
+	return lastModified, err
+}
+
+func (f *fakeFile) Size() (uint64, error) {
+	var size uint64
+	err := f.fs.call("File.Size", f.Path(), func() (err error) {
+		size, err = f.file.Size()
+		return err
+	})
+	return size, err
+}
+
+func (f *fakeFile) Path() string { return f.file.Path() }
+
+func (f *fakeFile) Name() string { return f.file.Name() }
+
+func (f *fakeFile) Touch() error {
+	return f.fs.call("File.Touch", f.Path(), f.file.Touch)
+}
+
+func (f *fakeFile) URI() string { return f.file.URI() }
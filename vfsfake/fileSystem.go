@@ -0,0 +1,174 @@
+package vfsfake
+
+import (
+	"io/ioutil"
+	"sync"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/backend/mem"
+)
+
+// FileSystem is a stateful vfs.FileSystem for unit tests.  Unlike the mocks package it needs no expectations:
+// files are kept in memory by a mem.FileSystem, so what's written can be read back, listed, copied and so on.  Every
+// call that can fail, to the fake or to a Location or File it returns, is recorded, and calls can be scripted to fail
+// with FailOn, so retry and error handling can be tested deterministically.  It's safe for concurrent use.
+type FileSystem struct {
+	fs       *mem.FileSystem
+	mu       sync.Mutex
+	calls    []Call
+	counts   map[string]int
+	failures map[string][]failure
+}
+
+// Call is a call recorded by a FileSystem.
+type Call struct {
+	// Op is the type and method called, ie: "FileSystem.NewFile", "Location.List" or "File.Write".
+	Op string
+	// Path is the absolute path of the file or location called, or given to FileSystem.NewFile or NewLocation.
+	Path string
+	// Err is the error the call returned, if any.
+	Err error
+}
+
+type failure struct {
+	n   int
+	err error
+}
+
+// NewFileSystem returns an empty FileSystem.
+func NewFileSystem() *FileSystem {
+	return &FileSystem{
+		fs:       mem.NewFileSystem(),
+		counts:   map[string]int{},
+		failures: map[string][]failure{},
+	}
+}
+
+// FailOn makes the nth call to op (counting from 1 since the fake was created) fail with err, or every call from now
+// on if n is 0 or less.  A failed call doesn't reach the underlying file system, ie: a failed File.Write writes
+// nothing.  See Call for the names of ops.
+func (f *FileSystem) FailOn(op string, n int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[op] = append(f.failures[op], failure{n: n, err: err})
+}
+
+// Calls returns the calls made so far, in order.
+func (f *FileSystem) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// CallCount returns the number of calls made to op so far, including any that failed.
+func (f *FileSystem) CallCount(op string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.counts[op]
+}
+
+// Contents returns the contents of the file at absFilePath, without recording any calls or triggering failures.
+func (f *FileSystem) Contents(absFilePath string) ([]byte, error) {
+	file, err := f.fs.NewFile("", absFilePath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return data, file.Close()
+}
+
+// NewFile implements vfs.FileSystem.
+func (f *FileSystem) NewFile(volume string, absFilePath string) (vfs.File, error) {
+	var file vfs.File
+	err := f.call("FileSystem.NewFile", absFilePath, func() (err error) {
+		file, err = f.fs.NewFile(volume, absFilePath)
+		return err
+	})
+	return f.wrapFile(file), err
+}
+
+// NewLocation implements vfs.FileSystem.
+func (f *FileSystem) NewLocation(volume string, absLocPath string) (vfs.Location, error) {
+	var location vfs.Location
+	err := f.call("FileSystem.NewLocation", absLocPath, func() (err error) {
+		location, err = f.fs.NewLocation(volume, absLocPath)
+		return err
+	})
+	return f.wrapLocation(location), err
+}
+
+// Name implements vfs.FileSystem.
+func (f *FileSystem) Name() string {
+	return "Fake File System"
+}
+
+// Scheme implements vfs.FileSystem, returning the scheme of the underlying mem.FileSystem.
+func (f *FileSystem) Scheme() string {
+	return f.fs.Scheme()
+}
+
+// Retry implements vfs.FileSystem.
+func (f *FileSystem) Retry() vfs.Retry {
+	return vfs.DefaultRetryer()
+}
+
+// call records a call to op, running fn unless the call is scripted to fail.
+func (f *FileSystem) call(op, path string, fn func() error) error {
+	f.mu.Lock()
+	f.counts[op]++
+	err := f.scriptedFailure(op, f.counts[op])
+	f.mu.Unlock()
+
+	if err == nil {
+		err = fn()
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{Op: op, Path: path, Err: err})
+	f.mu.Unlock()
+	return err
+}
+
+// scriptedFailure returns the error the nth call to op is scripted to fail with, if any.
+func (f *FileSystem) scriptedFailure(op string, n int) error {
+	for _, failure := range f.failures[op] {
+		if failure.n <= 0 || failure.n == n {
+			return failure.err
+		}
+	}
+	return nil
+}
+
+func (f *FileSystem) wrapFile(file vfs.File) vfs.File {
+	if file == nil {
+		return nil
+	}
+	return &fakeFile{fs: f, file: file}
+}
+
+func (f *FileSystem) wrapLocation(location vfs.Location) vfs.Location {
+	if location == nil {
+		return nil
+	}
+	return &fakeLocation{fs: f, location: location}
+}
+
+// unwrapFile returns the underlying file of a fake file, so it's passed to the mem backend as one of its own.
+func unwrapFile(file vfs.File) vfs.File {
+	if fake, ok := file.(*fakeFile); ok {
+		return fake.file
+	}
+	return file
+}
+
+// unwrapLocation returns the underlying location of a fake location.
+func unwrapLocation(location vfs.Location) vfs.Location {
+	if fake, ok := location.(*fakeLocation); ok {
+		return fake.location
+	}
+	return location
+}
@@ -0,0 +1,86 @@
+package vfsfake_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/vfsfake"
+)
+
+type fileSystemTestSuite struct {
+	suite.Suite
+	fs *vfsfake.FileSystem
+}
+
+func (ts *fileSystemTestSuite) SetupTest() {
+	ts.fs = vfsfake.NewFileSystem()
+}
+
+func (ts *fileSystemTestSuite) TestStateful() {
+	file, err := ts.fs.NewFile("", "/some/path/file.txt")
+	ts.NoError(err)
+	_, err = file.Write([]byte("hello"))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+
+	contents, err := ts.fs.Contents("/some/path/file.txt")
+	ts.NoError(err)
+	ts.Equal("hello", string(contents))
+
+	location, err := ts.fs.NewLocation("", "/other/")
+	ts.NoError(err)
+	copied, err := file.CopyToLocation(location)
+	ts.NoError(err)
+	ts.Equal(ts.fs, copied.Location().FileSystem(), "returned files belong to the fake")
+	data, err := ioutil.ReadAll(copied)
+	ts.NoError(err)
+	ts.Equal("hello", string(data))
+	ts.NoError(copied.Close())
+
+	names, err := location.List()
+	ts.NoError(err)
+	ts.Equal([]string{"file.txt"}, names)
+
+	ts.Equal(1, ts.fs.CallCount("File.Write"))
+	ts.Equal(1, ts.fs.CallCount("File.CopyToLocation"), "calls made by the underlying file system aren't recorded")
+	calls := ts.fs.Calls()
+	ts.Equal(vfsfake.Call{Op: "FileSystem.NewFile", Path: "/some/path/file.txt"}, calls[0])
+	ts.Equal(vfsfake.Call{Op: "File.Write", Path: "/some/path/file.txt"}, calls[1])
+	ts.Equal(vfsfake.Call{Op: "Location.List", Path: "/other/"}, calls[len(calls)-1])
+}
+
+func (ts *fileSystemTestSuite) TestFailOn() {
+	writeErr := errors.New("connection reset")
+	ts.fs.FailOn("File.Write", 2, writeErr)
+
+	file, err := ts.fs.NewFile("", "/file.txt")
+	ts.NoError(err)
+	_, err = file.Write([]byte("a"))
+	ts.NoError(err)
+	_, err = file.Write([]byte("b"))
+	ts.Equal(writeErr, err, "the 2nd write fails")
+	_, err = file.Write([]byte("c"))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+
+	contents, err := ts.fs.Contents("/file.txt")
+	ts.NoError(err)
+	ts.Equal("ac", string(contents), "a failed write writes nothing")
+	ts.Equal(3, ts.fs.CallCount("File.Write"))
+	ts.Equal(writeErr, ts.fs.Calls()[2].Err)
+
+	deleteErr := errors.New("permission denied")
+	ts.fs.FailOn("File.Delete", 0, deleteErr)
+	ts.Equal(deleteErr, file.Delete())
+	ts.Equal(deleteErr, file.Delete(), "every delete fails")
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+}
+
+func TestFileSystem(t *testing.T) {
+	suite.Run(t, new(fileSystemTestSuite))
+}
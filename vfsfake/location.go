@@ -0,0 +1,89 @@
+package vfsfake
+
+import (
+	"regexp"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// fakeLocation is a Location of a FileSystem.
+type fakeLocation struct {
+	fs       *FileSystem
+	location vfs.Location
+}
+
+func (l *fakeLocation) String() string { return l.location.String() }
+
+func (l *fakeLocation) List() ([]string, error) {
+	var names []string
+	err := l.fs.call("Location.List", l.Path(), func() (err error) {
+		names, err = l.location.List()
+		return err
+	})
+	return names, err
+}
+
+func (l *fakeLocation) ListByPrefix(prefix string) ([]string, error) {
+	var names []string
+	err := l.fs.call("Location.ListByPrefix", l.Path(), func() (err error) {
+		names, err = l.location.ListByPrefix(prefix)
+		return err
+	})
+	return names, err
+}
+
+func (l *fakeLocation) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	var names []string
+	err := l.fs.call("Location.ListByRegex", l.Path(), func() (err error) {
+		names, err = l.location.ListByRegex(regex)
+		return err
+	})
+	return names, err
+}
+
+func (l *fakeLocation) Volume() string { return l.location.Volume() }
+
+func (l *fakeLocation) Path() string { return l.location.Path() }
+
+func (l *fakeLocation) Exists() (bool, error) {
+	var exists bool
+	err := l.fs.call("Location.Exists", l.Path(), func() (err error) {
+		exists, err = l.location.Exists()
+		return err
+	})
+	return exists, err
+}
+
+func (l *fakeLocation) NewLocation(relLocPath string) (vfs.Location, error) {
+	var location vfs.Location
+	err := l.fs.call("Location.NewLocation", l.Path(), func() (err error) {
+		location, err = l.location.NewLocation(relLocPath)
+		return err
+	})
+	return l.fs.wrapLocation(location), err
+}
+
+func (l *fakeLocation) ChangeDir(relLocPath string) error {
+	return l.fs.call("Location.ChangeDir", l.Path(), func() error {
+		return l.location.ChangeDir(relLocPath)
+	})
+}
+
+func (l *fakeLocation) FileSystem() vfs.FileSystem { return l.fs }
+
+func (l *fakeLocation) NewFile(relFilePath string) (vfs.File, error) {
+	var file vfs.File
+	err := l.fs.call("Location.NewFile", l.Path(), func() (err error) {
+		file, err = l.location.NewFile(relFilePath)
+		return err
+	})
+	return l.fs.wrapFile(file), err
+}
+
+func (l *fakeLocation) DeleteFile(relFilePath string) error {
+	return l.fs.call("Location.DeleteFile", l.Path(), func() error {
+		return l.location.DeleteFile(relFilePath)
+	})
+}
+
+func (l *fakeLocation) URI() string { return l.location.URI() }
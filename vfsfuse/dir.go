@@ -0,0 +1,189 @@
+// +build linux darwin freebsd
+
+package vfsfuse
+
+import (
+	"context"
+	"sort"
+	"syscall"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// dir is the node of a directory, backed by a vfs.Location.
+type dir struct {
+	fs       *FS
+	location vfs.Location
+}
+
+// Attr implements fs.Node.
+func (d *dir) Attr(ctx context.Context, a *fuse.Attr) error {
+	a.Mode = d.fs.mode(true)
+	return nil
+}
+
+// Lookup implements fs.NodeStringLookuper, returning the file or directory called name.
+func (d *dir) Lookup(ctx context.Context, name string) (fs.Node, error) {
+	vfsFile, err := d.location.NewFile(name)
+	if err != nil {
+		return nil, err
+	}
+	// a new file doesn't exist in the backend until it's first closed
+	if node := d.fs.openFileNode(vfsFile.URI()); node != nil {
+		return node, nil
+	}
+	if exists, err := fileExists(vfsFile); err != nil {
+		return nil, err
+	} else if exists {
+		return d.fs.fileNode(vfsFile), nil
+	}
+
+	location, err := d.location.NewLocation(utils.EnsureTrailingSlash(name))
+	if err != nil {
+		return nil, err
+	}
+	if exists, err := d.fs.dirExists(location); err != nil {
+		return nil, err
+	} else if exists {
+		return &dir{fs: d.fs, location: location}, nil
+	}
+	return nil, fuse.ENOENT
+}
+
+// ReadDirAll implements fs.HandleReadDirAller, returning the files and directories in the directory sorted by name.
+func (d *dir) ReadDirAll(ctx context.Context) ([]fuse.Dirent, error) {
+	files, locations, err := utils.ListDir(d.location)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := map[string]fuse.DirentType{}
+	for _, vfsFile := range files {
+		entries[vfsFile.Name()] = fuse.DT_File
+	}
+	for _, node := range d.fs.openFileNodes(d.location) {
+		entries[node.file.Name()] = fuse.DT_File
+	}
+	for _, location := range locations {
+		_, name := splitDirURI(location.URI())
+		entries[name] = fuse.DT_Dir
+	}
+	for _, name := range d.fs.madeDirs(d.location) {
+		entries[name] = fuse.DT_Dir
+	}
+
+	dirents := make([]fuse.Dirent, 0, len(entries))
+	for name, direntType := range entries {
+		dirents = append(dirents, fuse.Dirent{Name: name, Type: direntType})
+	}
+	sort.Slice(dirents, func(i, j int) bool { return dirents[i].Name < dirents[j].Name })
+	return dirents, nil
+}
+
+// Mkdir implements fs.NodeMkdirer.  The directory exists for the life of the mount, or until it's removed, but isn't
+// written to the backend until files are written in it.
+func (d *dir) Mkdir(ctx context.Context, req *fuse.MkdirRequest) (fs.Node, error) {
+	if d.fs.options.ReadOnly {
+		return nil, fuse.EPERM
+	}
+	location, err := d.location.NewLocation(utils.EnsureTrailingSlash(req.Name))
+	if err != nil {
+		return nil, err
+	}
+	d.fs.makeDir(location)
+	return &dir{fs: d.fs, location: location}, nil
+}
+
+// Create implements fs.NodeCreater, creating the file called req.Name and opening it for writing.
+func (d *dir) Create(ctx context.Context, req *fuse.CreateRequest, resp *fuse.CreateResponse) (fs.Node, fs.Handle,
+	error) {
+	if d.fs.options.ReadOnly {
+		return nil, nil, fuse.EPERM
+	}
+	vfsFile, err := d.location.NewFile(req.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	node := d.fs.fileNode(vfsFile)
+	if err := node.open(true); err != nil {
+		return nil, nil, errno(err)
+	}
+	return node, &handle{node: node, writable: true}, nil
+}
+
+// Remove implements fs.NodeRemover, deleting a file or forgetting an empty directory.
+func (d *dir) Remove(ctx context.Context, req *fuse.RemoveRequest) error {
+	if d.fs.options.ReadOnly {
+		return fuse.EPERM
+	}
+	if req.Dir {
+		location, err := d.location.NewLocation(utils.EnsureTrailingSlash(req.Name))
+		if err != nil {
+			return err
+		}
+		files, locations, err := utils.ListDir(location)
+		if err != nil {
+			return err
+		}
+		if len(files) > 0 || len(locations) > 0 {
+			return fuse.Errno(syscall.ENOTEMPTY)
+		}
+		d.fs.removeDir(location)
+		return nil
+	}
+
+	vfsFile, err := d.location.NewFile(req.Name)
+	if err != nil {
+		return err
+	}
+	if err := vfsFile.Delete(); err != nil {
+		return errno(err)
+	}
+	d.fs.forget(vfsFile.URI())
+	return nil
+}
+
+// Rename implements fs.NodeRenamer, moving a file to newDir.  Directories can't be renamed, which fails with EXDEV so
+// mv falls back to copying their contents.
+func (d *dir) Rename(ctx context.Context, req *fuse.RenameRequest, newDir fs.Node) error {
+	if d.fs.options.ReadOnly {
+		return fuse.EPERM
+	}
+	target, ok := newDir.(*dir)
+	if !ok {
+		return fuse.EIO
+	}
+	vfsFile, err := d.location.NewFile(req.OldName)
+	if err != nil {
+		return err
+	}
+	if exists, err := fileExists(vfsFile); err != nil {
+		return err
+	} else if !exists {
+		location, err := d.location.NewLocation(utils.EnsureTrailingSlash(req.OldName))
+		if err != nil {
+			return err
+		}
+		if exists, err := d.fs.dirExists(location); err != nil {
+			return err
+		} else if exists {
+			return fuse.Errno(syscall.EXDEV)
+		}
+		return fuse.ENOENT
+	}
+
+	targetFile, err := target.location.NewFile(req.NewName)
+	if err != nil {
+		return err
+	}
+	if err := vfsFile.MoveToFile(targetFile); err != nil {
+		return errno(err)
+	}
+	d.fs.forget(vfsFile.URI())
+	d.fs.forget(targetFile.URI())
+	return nil
+}
@@ -0,0 +1,36 @@
+/*
+Package vfsfuse mounts a vfs.Location as a local file system with FUSE (bazil.org/fuse), so programs that only
+understand paths can read and write any backend (ie: s3).  It's supported on Linux, macOS (with OSXFUSE) and FreeBSD.
+See the vfsmount command for mounting a location from the command line.
+
+Usage
+
+Use Mount to mount a location, and Unmount to unmount it again:
+
+  loc, err := vfssimple.NewLocation("s3://mybucket/data/")
+  if err != nil {
+      return err
+  }
+  mounted, err := vfsfuse.Mount(loc, "/mnt/data", vfsfuse.Options{})
+  if err != nil {
+      return err
+  }
+  defer mounted.Unmount()
+
+  // legacy tools can now use /mnt/data/report.csv
+
+Use NewFS instead for a bazil.org/fuse/fs.FS to serve on a FUSE connection of your own.
+
+Reads and Writes
+
+Reads are streamed from the backend.  Files opened for writing are copied to a local temp file (see Options.TempDir),
+which is read and written in its place and written back to the backend whenever it's closed, so a file is uploaded in
+full when a program closes it, and any error doing so is returned by close(2).
+
+Directories
+
+vfs has no directories of its own, only locations, which exist implicitly while they contain files.  A directory made
+with mkdir exists for the life of the mount, or until it's removed, but is only written to the backend once files are
+written in it.  Renaming directories fails with EXDEV, so mv falls back to copying their contents.
+*/
+package vfsfuse
@@ -0,0 +1,231 @@
+// +build linux darwin freebsd
+
+package vfsfuse
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// file is the node of a file, backed by a vfs.File.  While the file is open for writing, its contents are held in a
+// local temp file, buffer, which is written back to the backend when a handle writing to it is flushed.
+type file struct {
+	fs   *FS
+	file vfs.File
+
+	mu      sync.Mutex
+	buffer  *os.File
+	writers int
+	dirty   bool
+}
+
+// Attr implements fs.Node.
+func (f *file) Attr(ctx context.Context, a *fuse.Attr) error {
+	a.Mode = f.fs.mode(false)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.buffer != nil {
+		info, err := f.buffer.Stat()
+		if err != nil {
+			return err
+		}
+		a.Size = uint64(info.Size())
+		a.Mtime = info.ModTime()
+		return nil
+	}
+
+	if exists, err := f.file.Exists(); err != nil {
+		return err
+	} else if !exists {
+		return fuse.ENOENT
+	}
+	info, err := utils.Stat(f.file)
+	if err != nil {
+		return errno(err)
+	}
+	a.Size = uint64(info.Size())
+	a.Mtime = info.ModTime()
+	return nil
+}
+
+// Open implements fs.NodeOpener.
+func (f *file) Open(ctx context.Context, req *fuse.OpenRequest, resp *fuse.OpenResponse) (fs.Handle, error) {
+	if req.Flags.IsReadOnly() {
+		return &handle{node: f}, nil
+	}
+	if f.fs.options.ReadOnly {
+		return nil, fuse.EPERM
+	}
+	if err := f.open(req.Flags&fuse.OpenTruncate != 0); err != nil {
+		return nil, errno(err)
+	}
+	return &handle{node: f, writable: true}, nil
+}
+
+// Setattr implements fs.NodeSetattrer.  Only size changes are supported, as vfs files have no other attributes to set.
+func (f *file) Setattr(ctx context.Context, req *fuse.SetattrRequest, resp *fuse.SetattrResponse) error {
+	if req.Valid.Size() {
+		if f.fs.options.ReadOnly {
+			return fuse.EPERM
+		}
+		if err := f.truncate(int64(req.Size)); err != nil {
+			return errno(err)
+		}
+	}
+	return f.Attr(ctx, &resp.Attr)
+}
+
+// Fsync implements fs.NodeFsyncer, writing the file back to the backend.
+func (f *file) Fsync(ctx context.Context, req *fuse.FsyncRequest) error {
+	return errno(f.flush())
+}
+
+// Forget implements fs.NodeForgetter.
+func (f *file) Forget() {
+	f.fs.forget(f.file.URI())
+}
+
+// isOpen returns true if the file is open for writing.
+func (f *file) isOpen() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.buffer != nil
+}
+
+// open opens the file for writing, copying its contents to a local temp file unless it's already open or truncate is
+// true.  Each call must be followed by a call to release.
+func (f *file) open(truncate bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.buffer == nil {
+		buffer, err := ioutil.TempFile(f.fs.options.TempDir, tempFilePrefix)
+		if err != nil {
+			return err
+		}
+		if !truncate {
+			if err := copyExisting(buffer, f.file); err != nil {
+				_ = buffer.Close()
+				_ = os.Remove(buffer.Name())
+				return err
+			}
+		}
+		f.buffer = buffer
+	}
+	if truncate {
+		if err := f.buffer.Truncate(0); err != nil {
+			return err
+		}
+		f.dirty = true
+	}
+	f.writers++
+	return nil
+}
+
+// readAt reads from the local copy of a file open for writing.  ok is false if the file isn't open for writing.
+func (f *file) readAt(p []byte, off int64) (n int, ok bool, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.buffer == nil {
+		return 0, false, nil
+	}
+	n, err = f.buffer.ReadAt(p, off)
+	return n, true, err
+}
+
+// writeAt writes to the local copy of a file open for writing.
+func (f *file) writeAt(p []byte, off int64) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.dirty = true
+	return f.buffer.WriteAt(p, off)
+}
+
+// truncate changes the size of the file, writing it back to the backend unless it's open for writing.
+func (f *file) truncate(size int64) error {
+	if err := f.open(size == 0); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	err := f.buffer.Truncate(size)
+	f.dirty = true
+	f.mu.Unlock()
+	if releaseErr := f.release(); err == nil {
+		err = releaseErr
+	}
+	return err
+}
+
+// flush writes the local copy of a file open for writing back to the backend, if it's changed.
+func (f *file) flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.writeBack()
+}
+
+// release closes the file for writing, writing it back to the backend and removing the local copy once it's no longer
+// open for writing.
+func (f *file) release() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.writers--
+	if f.writers > 0 {
+		return nil
+	}
+
+	err := f.writeBack()
+	name := f.buffer.Name()
+	if closeErr := f.buffer.Close(); err == nil {
+		err = closeErr
+	}
+	if removeErr := os.Remove(name); err == nil {
+		err = removeErr
+	}
+	f.buffer = nil
+	return err
+}
+
+// writeBack writes the local copy back to the backend if it's changed.  f.mu must be held.
+func (f *file) writeBack() error {
+	if f.buffer == nil || !f.dirty {
+		return nil
+	}
+	if _, err := f.buffer.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	// start the write so an empty file is still created
+	if _, err := f.file.Write([]byte{}); err != nil {
+		_ = f.file.Close()
+		return err
+	}
+	if _, err := utils.Copy(f.file, f.buffer); err != nil {
+		_ = f.file.Close()
+		return err
+	}
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.dirty = false
+	return nil
+}
+
+// copyExisting copies the contents of vfsFile, if it exists, to buffer.
+func copyExisting(buffer *os.File, vfsFile vfs.File) error {
+	if exists, err := vfsFile.Exists(); err != nil || !exists {
+		return err
+	}
+	if _, err := utils.Copy(buffer, vfsFile); err != nil {
+		_ = vfsFile.Close()
+		return err
+	}
+	return vfsFile.Close()
+}
@@ -0,0 +1,195 @@
+// +build linux darwin freebsd
+
+package vfsfuse
+
+import (
+	"os"
+	"sync"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// tempFilePrefix begins the name of every local temp file holding a file opened for writing.
+const tempFilePrefix = "vfs-fuse-"
+
+// Options configure a mounted location.
+type Options struct {
+	// ReadOnly mounts the location read-only.
+	ReadOnly bool
+	// AllowOther allows users other than the one mounting the location to access it.  It requires user_allow_other in
+	// /etc/fuse.conf when not mounting as root.
+	AllowOther bool
+	// TempDir is the directory for the local copies of files opened for writing.  It defaults to os.TempDir().
+	TempDir string
+}
+
+// FS implements bazil.org/fuse/fs.FS for a vfs.Location.
+type FS struct {
+	location vfs.Location
+	options  Options
+
+	mu sync.Mutex
+	// files are the nodes of the files looked up or created, by URI, so each file keeps the same node while it's in use
+	files map[string]*file
+	// dirs are the URIs of the directories made with mkdir, which don't exist in the backend until they contain files
+	dirs map[string]bool
+}
+
+// NewFS returns a FS of the files beneath location.
+func NewFS(location vfs.Location, options Options) *FS {
+	return &FS{
+		location: location,
+		options:  options,
+		files:    map[string]*file{},
+		dirs:     map[string]bool{},
+	}
+}
+
+// Root implements fs.FS, returning the node of the location.
+func (f *FS) Root() (fs.Node, error) {
+	return &dir{fs: f, location: f.location}, nil
+}
+
+// fileNode returns the node of vfsFile, creating it if there isn't one already.
+func (f *FS) fileNode(vfsFile vfs.File) *file {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if node, ok := f.files[vfsFile.URI()]; ok {
+		return node
+	}
+	node := &file{fs: f, file: vfsFile}
+	f.files[vfsFile.URI()] = node
+	return node
+}
+
+// openFileNode returns the node of the file at uri if it's open for writing, otherwise nil.
+func (f *FS) openFileNode(uri string) *file {
+	f.mu.Lock()
+	node := f.files[uri]
+	f.mu.Unlock()
+	if node == nil || !node.isOpen() {
+		return nil
+	}
+	return node
+}
+
+// openFileNodes returns the nodes of files in location that are open for writing.
+func (f *FS) openFileNodes(location vfs.Location) []*file {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var nodes []*file
+	for _, node := range f.files {
+		if node.file.Location().URI() == location.URI() && node.isOpen() {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// forget drops the node of the file at uri unless it's open for writing.
+func (f *FS) forget(uri string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if node, ok := f.files[uri]; ok && !node.isOpen() {
+		delete(f.files, uri)
+	}
+}
+
+// makeDir records that a directory was made at location.
+func (f *FS) makeDir(location vfs.Location) {
+	f.mu.Lock()
+	f.dirs[location.URI()] = true
+	f.mu.Unlock()
+}
+
+// removeDir forgets a directory made at location.
+func (f *FS) removeDir(location vfs.Location) {
+	f.mu.Lock()
+	delete(f.dirs, location.URI())
+	f.mu.Unlock()
+}
+
+// madeDirs returns the names of the directories made directly beneath location.
+func (f *FS) madeDirs(location vfs.Location) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var names []string
+	for uri := range f.dirs {
+		if parent, name := splitDirURI(uri); parent == location.URI() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// dirExists returns true if location was made with mkdir or contains anything.
+func (f *FS) dirExists(location vfs.Location) (bool, error) {
+	f.mu.Lock()
+	made := f.dirs[location.URI()]
+	f.mu.Unlock()
+	if made {
+		return true, nil
+	}
+	files, locations, err := utils.ListDir(location)
+	if err != nil {
+		return false, err
+	}
+	return len(files) > 0 || len(locations) > 0, nil
+}
+
+// mode returns the mode of files, or directories if isDir is true.
+func (f *FS) mode(isDir bool) os.FileMode {
+	switch {
+	case isDir && f.options.ReadOnly:
+		return os.ModeDir | 0555
+	case isDir:
+		return os.ModeDir | 0755
+	case f.options.ReadOnly:
+		return 0444
+	default:
+		return 0644
+	}
+}
+
+// fileExists returns true if file exists and isn't a directory.  Backends with real directories (os) report a
+// directory as an existing file.
+func fileExists(file vfs.File) (bool, error) {
+	exists, err := file.Exists()
+	if err != nil || !exists {
+		return false, err
+	}
+	info, err := utils.Stat(file)
+	if err != nil {
+		return false, err
+	}
+	return !info.IsDir(), nil
+}
+
+// splitDirURI splits the URI of a location into the URI of its parent and its name.
+func splitDirURI(uri string) (string, string) {
+	trimmed := uri[:len(uri)-1]
+	for i := len(trimmed) - 1; i >= 0; i-- {
+		if trimmed[i] == '/' {
+			return trimmed[:i+1], trimmed[i+1:]
+		}
+	}
+	return "", trimmed
+}
+
+// errno converts err to the error number FUSE should return for it.  Errors without one are returned as EIO.
+func errno(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case os.IsNotExist(err):
+		return fuse.ENOENT
+	case os.IsPermission(err):
+		return fuse.EPERM
+	default:
+		return err
+	}
+}
@@ -0,0 +1,231 @@
+// +build linux darwin freebsd
+
+package vfsfuse
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"bazil.org/fuse"
+	"github.com/stretchr/testify/suite"
+
+	_os "github.com/c2fo/vfs/v5/backend/os"
+)
+
+type fsTestSuite struct {
+	suite.Suite
+	dir     string
+	tempDir string
+	ctx     context.Context
+}
+
+func (ts *fsTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfsfuse_test")
+	ts.NoError(err)
+	ts.dir = dir
+	ts.tempDir = filepath.Join(dir, ".temp")
+	ts.NoError(os.Mkdir(ts.tempDir, 0755))
+	ts.ctx = context.Background()
+}
+
+func (ts *fsTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *fsTestSuite) root(options Options) *dir {
+	location, err := _os.NewFileSystem().NewLocation("", ts.dir+"/data/")
+	ts.NoError(err)
+	options.TempDir = ts.tempDir
+	root, err := NewFS(location, options).Root()
+	ts.NoError(err)
+	return root.(*dir)
+}
+
+func (ts *fsTestSuite) writeFile(name, contents string) {
+	path := filepath.Join(ts.dir, "data", name)
+	ts.NoError(os.MkdirAll(filepath.Dir(path), 0755))
+	ts.NoError(ioutil.WriteFile(path, []byte(contents), 0644))
+}
+
+func (ts *fsTestSuite) readFile(name string) string {
+	data, err := ioutil.ReadFile(filepath.Join(ts.dir, "data", name))
+	ts.NoError(err)
+	return string(data)
+}
+
+func (ts *fsTestSuite) tempFiles() []os.FileInfo {
+	infos, err := ioutil.ReadDir(ts.tempDir)
+	ts.NoError(err)
+	return infos
+}
+
+func (ts *fsTestSuite) TestCreate() {
+	root := ts.root(Options{})
+	node, h, err := root.Create(ts.ctx, &fuse.CreateRequest{Name: "new.txt"}, &fuse.CreateResponse{})
+	ts.NoError(err)
+	handle := h.(*handle)
+	resp := &fuse.WriteResponse{}
+	ts.NoError(handle.Write(ts.ctx, &fuse.WriteRequest{Data: []byte("hello fuse"), Offset: 0}, resp))
+	ts.Equal(10, resp.Size)
+
+	// the new file is visible before it's written back
+	looked, err := root.Lookup(ts.ctx, "new.txt")
+	ts.NoError(err)
+	ts.Equal(node, looked)
+	dirents, err := root.ReadDirAll(ts.ctx)
+	ts.NoError(err)
+	ts.Equal([]fuse.Dirent{{Name: "new.txt", Type: fuse.DT_File}}, dirents)
+	attr := fuse.Attr{}
+	ts.NoError(node.Attr(ts.ctx, &attr))
+	ts.Equal(uint64(10), attr.Size)
+	_, err = os.Stat(filepath.Join(ts.dir, "data", "new.txt"))
+	ts.True(os.IsNotExist(err))
+
+	ts.NoError(handle.Flush(ts.ctx, &fuse.FlushRequest{}))
+	ts.Equal("hello fuse", ts.readFile("new.txt"))
+	ts.Len(ts.tempFiles(), 1)
+	ts.NoError(handle.Release(ts.ctx, &fuse.ReleaseRequest{}))
+	ts.Empty(ts.tempFiles(), "the local copy is removed once released")
+}
+
+func (ts *fsTestSuite) TestCreateEmpty() {
+	root := ts.root(Options{})
+	_, h, err := root.Create(ts.ctx, &fuse.CreateRequest{Name: "empty.txt"}, &fuse.CreateResponse{})
+	ts.NoError(err)
+	ts.NoError(h.(*handle).Flush(ts.ctx, &fuse.FlushRequest{}))
+	ts.NoError(h.(*handle).Release(ts.ctx, &fuse.ReleaseRequest{}))
+	ts.Equal("", ts.readFile("empty.txt"))
+}
+
+func (ts *fsTestSuite) TestRead() {
+	ts.writeFile("file.txt", "0123456789")
+	root := ts.root(Options{})
+	node, err := root.Lookup(ts.ctx, "file.txt")
+	ts.NoError(err)
+	attr := fuse.Attr{}
+	ts.NoError(node.Attr(ts.ctx, &attr))
+	ts.Equal(uint64(10), attr.Size)
+	ts.Equal(os.FileMode(0644), attr.Mode)
+
+	h, err := node.(*file).Open(ts.ctx, &fuse.OpenRequest{Flags: fuse.OpenReadOnly}, &fuse.OpenResponse{})
+	ts.NoError(err)
+	handle := h.(*handle)
+	for _, read := range []struct {
+		offset   int64
+		size     int
+		expected string
+	}{
+		{0, 4, "0123"},
+		{4, 4, "4567"},
+		{2, 3, "234"},
+		{8, 4, "89"},
+		{10, 4, ""},
+	} {
+		resp := &fuse.ReadResponse{}
+		ts.NoError(handle.Read(ts.ctx, &fuse.ReadRequest{Offset: read.offset, Size: read.size}, resp))
+		ts.Equal(read.expected, string(resp.Data))
+	}
+	ts.NoError(handle.Release(ts.ctx, &fuse.ReleaseRequest{}))
+
+	_, err = root.Lookup(ts.ctx, "missing.txt")
+	ts.Equal(fuse.ENOENT, err)
+}
+
+func (ts *fsTestSuite) TestWriteExisting() {
+	ts.writeFile("file.txt", "0123456789")
+	root := ts.root(Options{})
+	node, err := root.Lookup(ts.ctx, "file.txt")
+	ts.NoError(err)
+
+	h, err := node.(*file).Open(ts.ctx, &fuse.OpenRequest{Flags: fuse.OpenReadWrite}, &fuse.OpenResponse{})
+	ts.NoError(err)
+	handle := h.(*handle)
+	ts.NoError(handle.Write(ts.ctx, &fuse.WriteRequest{Data: []byte("abc"), Offset: 4}, &fuse.WriteResponse{}))
+	resp := &fuse.ReadResponse{}
+	ts.NoError(handle.Read(ts.ctx, &fuse.ReadRequest{Offset: 0, Size: 20}, resp))
+	ts.Equal("0123abc789", string(resp.Data))
+	ts.NoError(handle.Flush(ts.ctx, &fuse.FlushRequest{}))
+	ts.NoError(handle.Release(ts.ctx, &fuse.ReleaseRequest{}))
+	ts.Equal("0123abc789", ts.readFile("file.txt"))
+
+	// truncating a file that isn't open writes it back straight away
+	setattr := &fuse.SetattrRequest{Valid: fuse.SetattrSize, Size: 4}
+	setattrResp := &fuse.SetattrResponse{}
+	ts.NoError(node.(*file).Setattr(ts.ctx, setattr, setattrResp))
+	ts.Equal(uint64(4), setattrResp.Attr.Size)
+	ts.Equal("0123", ts.readFile("file.txt"))
+	ts.Empty(ts.tempFiles())
+}
+
+func (ts *fsTestSuite) TestDirectories() {
+	ts.writeFile("sub/file.txt", "data")
+	root := ts.root(Options{})
+
+	made, err := root.Mkdir(ts.ctx, &fuse.MkdirRequest{Name: "made"})
+	ts.NoError(err)
+	looked, err := root.Lookup(ts.ctx, "made")
+	ts.NoError(err)
+	ts.Equal(made.(*dir).location.URI(), looked.(*dir).location.URI())
+	dirents, err := root.ReadDirAll(ts.ctx)
+	ts.NoError(err)
+	ts.Equal([]fuse.Dirent{{Name: "made", Type: fuse.DT_Dir}, {Name: "sub", Type: fuse.DT_Dir}}, dirents)
+
+	sub, err := root.Lookup(ts.ctx, "sub")
+	ts.NoError(err)
+	attr := fuse.Attr{}
+	ts.NoError(sub.Attr(ts.ctx, &attr))
+	ts.True(attr.Mode.IsDir())
+
+	ts.Equal(fuse.Errno(syscall.ENOTEMPTY), root.Remove(ts.ctx, &fuse.RemoveRequest{Name: "sub", Dir: true}))
+	ts.NoError(root.Remove(ts.ctx, &fuse.RemoveRequest{Name: "made", Dir: true}))
+	_, err = root.Lookup(ts.ctx, "made")
+	ts.Equal(fuse.ENOENT, err)
+
+	ts.NoError(sub.(*dir).Remove(ts.ctx, &fuse.RemoveRequest{Name: "file.txt"}))
+	_, err = os.Stat(filepath.Join(ts.dir, "data", "sub", "file.txt"))
+	ts.True(os.IsNotExist(err))
+}
+
+func (ts *fsTestSuite) TestRename() {
+	ts.writeFile("file.txt", "data")
+	ts.writeFile("sub/other.txt", "other")
+	root := ts.root(Options{})
+	sub, err := root.Lookup(ts.ctx, "sub")
+	ts.NoError(err)
+
+	ts.NoError(root.Rename(ts.ctx, &fuse.RenameRequest{OldName: "file.txt", NewName: "moved.txt"}, sub))
+	ts.Equal("data", ts.readFile("sub/moved.txt"))
+	_, err = root.Lookup(ts.ctx, "file.txt")
+	ts.Equal(fuse.ENOENT, err)
+
+	ts.Equal(fuse.Errno(syscall.EXDEV), root.Rename(ts.ctx, &fuse.RenameRequest{OldName: "sub", NewName: "dir"}, root))
+	ts.Equal(fuse.ENOENT, root.Rename(ts.ctx, &fuse.RenameRequest{OldName: "missing", NewName: "dir"}, root))
+}
+
+func (ts *fsTestSuite) TestReadOnly() {
+	ts.writeFile("file.txt", "data")
+	root := ts.root(Options{ReadOnly: true})
+
+	_, _, err := root.Create(ts.ctx, &fuse.CreateRequest{Name: "new.txt"}, &fuse.CreateResponse{})
+	ts.Equal(fuse.EPERM, err)
+	_, err = root.Mkdir(ts.ctx, &fuse.MkdirRequest{Name: "dir"})
+	ts.Equal(fuse.EPERM, err)
+	ts.Equal(fuse.EPERM, root.Remove(ts.ctx, &fuse.RemoveRequest{Name: "file.txt"}))
+
+	node, err := root.Lookup(ts.ctx, "file.txt")
+	ts.NoError(err)
+	attr := fuse.Attr{}
+	ts.NoError(node.Attr(ts.ctx, &attr))
+	ts.Equal(os.FileMode(0444), attr.Mode)
+	_, err = node.(*file).Open(ts.ctx, &fuse.OpenRequest{Flags: fuse.OpenWriteOnly}, &fuse.OpenResponse{})
+	ts.Equal(fuse.EPERM, err)
+	ts.Equal("data", ts.readFile("file.txt"))
+}
+
+func TestFS(t *testing.T) {
+	suite.Run(t, new(fsTestSuite))
+}
@@ -0,0 +1,85 @@
+// +build linux darwin freebsd
+
+package vfsfuse
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"bazil.org/fuse"
+
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// handle is an open file.  Handles reading a file that isn't open for writing stream it from the backend through their
+// own vfs.File, so each has its own cursor.
+type handle struct {
+	node     *file
+	writable bool
+
+	// mu guards reader, which is opened by the first read
+	mu     sync.Mutex
+	reader *utils.ReaderAt
+}
+
+// Read implements fs.HandleReader.
+func (h *handle) Read(ctx context.Context, req *fuse.ReadRequest, resp *fuse.ReadResponse) error {
+	buf := make([]byte, req.Size)
+	n, ok, err := h.node.readAt(buf, req.Offset)
+	if !ok {
+		n, err = h.readBackend(buf, req.Offset)
+	}
+	if err != nil && err != io.EOF {
+		return errno(err)
+	}
+	resp.Data = buf[:n]
+	return nil
+}
+
+// Write implements fs.HandleWriter.
+func (h *handle) Write(ctx context.Context, req *fuse.WriteRequest, resp *fuse.WriteResponse) error {
+	if !h.writable {
+		return fuse.EPERM
+	}
+	n, err := h.node.writeAt(req.Data, req.Offset)
+	resp.Size = n
+	return errno(err)
+}
+
+// Flush implements fs.HandleFlusher, writing the file back to the backend when the handle is closed.
+func (h *handle) Flush(ctx context.Context, req *fuse.FlushRequest) error {
+	if !h.writable {
+		return nil
+	}
+	return errno(h.node.flush())
+}
+
+// Release implements fs.HandleReleaser.
+func (h *handle) Release(ctx context.Context, req *fuse.ReleaseRequest) error {
+	if h.writable {
+		return errno(h.node.release())
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.reader != nil {
+		return errno(h.reader.Close())
+	}
+	return nil
+}
+
+// readBackend reads from the file in the backend at offset.
+func (h *handle) readBackend(p []byte, offset int64) (int, error) {
+	h.mu.Lock()
+	if h.reader == nil {
+		reader, err := h.node.file.Location().NewFile(h.node.file.Name())
+		if err != nil {
+			h.mu.Unlock()
+			return 0, err
+		}
+		h.reader = utils.NewReaderAt(reader)
+	}
+	reader := h.reader
+	h.mu.Unlock()
+	return reader.ReadAt(p, offset)
+}
@@ -0,0 +1,69 @@
+// +build linux darwin freebsd
+
+package vfsfuse
+
+import (
+	"sync"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// Mounted is a vfs.Location mounted with Mount.
+type Mounted struct {
+	conn       *fuse.Conn
+	mountpoint string
+	served     chan error
+
+	once sync.Once
+	err  error
+}
+
+// Mount mounts location at mountpoint, an existing directory, serving it until it's unmounted.
+func Mount(location vfs.Location, mountpoint string, options Options) (*Mounted, error) {
+	mountOptions := []fuse.MountOption{fuse.FSName(location.URI()), fuse.Subtype("vfs")}
+	if options.ReadOnly {
+		mountOptions = append(mountOptions, fuse.ReadOnly())
+	}
+	if options.AllowOther {
+		mountOptions = append(mountOptions, fuse.AllowOther())
+	}
+	conn, err := fuse.Mount(mountpoint, mountOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Mounted{conn: conn, mountpoint: mountpoint, served: make(chan error, 1)}
+	go func() {
+		m.served <- fs.Serve(conn, NewFS(location, options))
+	}()
+	<-conn.Ready
+	if err := conn.MountError; err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// Unmount unmounts the location and waits for it to stop being served.  Files still open for writing aren't written
+// back to the backend.
+func (m *Mounted) Unmount() error {
+	if err := fuse.Unmount(m.mountpoint); err != nil {
+		return err
+	}
+	return m.Wait()
+}
+
+// Wait waits until the location is unmounted, by Unmount or otherwise (ie: with umount or fusermount -u), returning
+// any error serving it.
+func (m *Mounted) Wait() error {
+	m.once.Do(func() {
+		m.err = <-m.served
+		if err := m.conn.Close(); m.err == nil {
+			m.err = err
+		}
+	})
+	return m.err
+}
@@ -0,0 +1,38 @@
+/*
+Package vfsgrpc serves a vfs.Location over gRPC, for a storage gateway architecture: a single service holds the cloud
+credentials for the backend and clients access its files with the grpcvfs backend (see backend/grpcvfs), which needs
+no credentials for the backend itself.
+
+The service is defined in vfs.proto: Stat, Exists, List, Delete and Touch requests, streamed Read and Write requests
+for file contents and Copy and Move requests, which the server performs with its backend's native copy and move.
+
+Usage
+
+Register a Server with a grpc.Server, using TLS and an interceptor to authenticate clients as for any gRPC service:
+
+  loc, err := vfssimple.NewLocation("s3://mybucket/shared/")
+  if err != nil {
+      return err
+  }
+  creds, err := credentials.NewServerTLSFromFile("server.crt", "server.key")
+  if err != nil {
+      return err
+  }
+  server := grpc.NewServer(grpc.Creds(creds), grpc.UnaryInterceptor(auth), grpc.StreamInterceptor(streamAuth))
+  vfsgrpc.RegisterFileServiceServer(server, vfsgrpc.NewServer(loc, vfsgrpc.Options{}))
+
+  listener, err := net.Listen("tcp", ":8443")
+  if err != nil {
+      return err
+  }
+  return server.Serve(listener)
+
+Paths in requests are absolute paths relative to the served location, so a client can't reach files outside it.
+Errors for files that don't exist are returned with codes.NotFound and those for denied requests, ie: writes to a
+ReadOnly server, with codes.PermissionDenied.
+
+The Go code is generated from vfs.proto with protoc-gen-go v1.3.1:
+
+  protoc --go_out=plugins=grpc,paths=source_relative:. vfs.proto
+*/
+package vfsgrpc
@@ -0,0 +1,288 @@
+package vfsgrpc
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. vfs.proto
+
+// ChunkSize is the largest amount of file data sent in a single ReadResponse or WriteRequest, well below gRPC's
+// default 4MB message size limit.
+const ChunkSize = 64 * 1024
+
+// Options configures a Server.
+type Options struct {
+	// ReadOnly rejects Write, Copy, Move, Delete and Touch requests with codes.PermissionDenied.
+	ReadOnly bool
+}
+
+// Server implements FileServiceServer, serving the files of a vfs.Location.
+type Server struct {
+	location vfs.Location
+	options  Options
+}
+
+// NewServer returns a Server for the files at location.  Serve it by registering it with a grpc.Server with
+// RegisterFileServiceServer.
+func NewServer(location vfs.Location, options Options) *Server {
+	return &Server{location: location, options: options}
+}
+
+// Stat implements FileServiceServer.
+func (s *Server) Stat(ctx context.Context, request *FileRequest) (*FileInfo, error) {
+	file, err := s.existingFile(request.Path)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	info, err := utils.Stat(file)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &FileInfo{
+		Name:        info.Name(),
+		Size:        info.Size(),
+		ModTime:     info.ModTime().UnixNano(),
+		Mode:        uint32(info.Mode()),
+		ContentType: info.ContentType,
+		Etag:        info.ETag,
+	}, nil
+}
+
+// Exists implements FileServiceServer.  A path ending in a slash is a location.
+func (s *Server) Exists(ctx context.Context, request *FileRequest) (*ExistsResponse, error) {
+	var exists bool
+	var err error
+	if strings.HasSuffix(request.Path, "/") {
+		var location vfs.Location
+		if location, err = s.dir(request.Path); err == nil {
+			exists, err = location.Exists()
+		}
+	} else {
+		var file vfs.File
+		if file, err = s.file(request.Path); err == nil {
+			exists, err = file.Exists()
+		}
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &ExistsResponse{Exists: exists}, nil
+}
+
+// List implements FileServiceServer.
+func (s *Server) List(ctx context.Context, request *ListRequest) (*ListResponse, error) {
+	location, err := s.dir(request.Path)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	var names []string
+	if request.Prefix != "" {
+		names, err = location.ListByPrefix(request.Prefix)
+	} else {
+		names, err = location.List()
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &ListResponse{Names: names}, nil
+}
+
+// Read implements FileServiceServer, streaming the file in chunks of up to ChunkSize bytes.
+func (s *Server) Read(request *ReadRequest, stream FileService_ReadServer) error {
+	file, err := s.existingFile(request.Path)
+	if err != nil {
+		return statusError(err)
+	}
+	if request.Offset > 0 {
+		if _, err := file.Seek(request.Offset, io.SeekStart); err != nil {
+			_ = file.Close()
+			return statusError(err)
+		}
+	}
+
+	buf := make([]byte, ChunkSize)
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			if sendErr := stream.Send(&ReadResponse{Data: buf[:n]}); sendErr != nil {
+				_ = file.Close()
+				return sendErr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = file.Close()
+			return statusError(err)
+		}
+	}
+	return statusError(file.Close())
+}
+
+// Write implements FileServiceServer.  The first request must be a WriteHeader; the data in the requests that follow
+// is written to the file, which is closed, and so written to the backend, once the stream ends.
+func (s *Server) Write(stream FileService_WriteServer) error {
+	if s.options.ReadOnly {
+		return statusError(os.ErrPermission)
+	}
+	request, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	header := request.GetHeader()
+	if header == nil {
+		return status.Error(codes.InvalidArgument, "first write request must be a header")
+	}
+	file, err := s.file(header.Path)
+	if err != nil {
+		return statusError(err)
+	}
+	if header.Offset > 0 {
+		if _, err := file.Seek(header.Offset, io.SeekStart); err != nil {
+			_ = file.Close()
+			return statusError(err)
+		}
+	}
+
+	// ensure the file is created even if no data follows
+	if _, err := file.Write([]byte{}); err != nil {
+		_ = file.Close()
+		return statusError(err)
+	}
+	for {
+		request, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+		if _, err := file.Write(request.GetData()); err != nil {
+			_ = file.Close()
+			return statusError(err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		return statusError(err)
+	}
+	return stream.SendAndClose(&Empty{})
+}
+
+// Copy implements FileServiceServer.
+func (s *Server) Copy(ctx context.Context, request *CopyRequest) (*Empty, error) {
+	return s.transfer(request, vfs.File.CopyToFile)
+}
+
+// Move implements FileServiceServer.
+func (s *Server) Move(ctx context.Context, request *CopyRequest) (*Empty, error) {
+	return s.transfer(request, vfs.File.MoveToFile)
+}
+
+// Delete implements FileServiceServer.
+func (s *Server) Delete(ctx context.Context, request *FileRequest) (*Empty, error) {
+	if s.options.ReadOnly {
+		return nil, statusError(os.ErrPermission)
+	}
+	file, err := s.existingFile(request.Path)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &Empty{}, statusError(file.Delete())
+}
+
+// Touch implements FileServiceServer.
+func (s *Server) Touch(ctx context.Context, request *FileRequest) (*Empty, error) {
+	if s.options.ReadOnly {
+		return nil, statusError(os.ErrPermission)
+	}
+	file, err := s.file(request.Path)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &Empty{}, statusError(file.Touch())
+}
+
+// transfer copies or moves the request's source file to its target with op.
+func (s *Server) transfer(request *CopyRequest,
+	op func(source, target vfs.File, opts ...vfs.CopyOption) error) (*Empty, error) {
+	if s.options.ReadOnly {
+		return nil, statusError(os.ErrPermission)
+	}
+	source, err := s.existingFile(request.Source)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	target, err := s.file(request.Target)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &Empty{}, statusError(op(source, target))
+}
+
+// file returns the file at name, an absolute path relative to the served location.
+func (s *Server) file(name string) (vfs.File, error) {
+	rel := utils.RelPath(name)
+	if rel == "" {
+		return nil, status.Error(codes.InvalidArgument, "file path is required")
+	}
+	return s.location.NewFile(rel)
+}
+
+// existingFile returns the file at name, or os.ErrNotExist if it doesn't exist.
+func (s *Server) existingFile(name string) (vfs.File, error) {
+	file, err := s.file(name)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := file.Exists()
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, os.ErrNotExist
+	}
+	return file, nil
+}
+
+// dir returns the location at name, an absolute path relative to the served location.
+func (s *Server) dir(name string) (vfs.Location, error) {
+	rel := utils.RelPath(name)
+	if rel == "" {
+		return s.location, nil
+	}
+	return s.location.NewLocation(utils.EnsureTrailingSlash(rel))
+}
+
+// statusError converts err to a gRPC status error, with codes.NotFound and codes.PermissionDenied for the
+// corresponding os errors and codes.ResourceExhausted for vfs.ErrQuotaExceeded.
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case os.IsNotExist(err):
+		return status.Error(codes.NotFound, err.Error())
+	case os.IsPermission(err):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case err == vfs.ErrFileExists:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case err == vfs.ErrQuotaExceeded:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Unknown, err.Error())
+	}
+}
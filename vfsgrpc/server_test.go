@@ -0,0 +1,179 @@
+package vfsgrpc
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	_os "github.com/c2fo/vfs/v5/backend/os"
+)
+
+type serverTestSuite struct {
+	suite.Suite
+	dir    string
+	server *grpc.Server
+	conn   *grpc.ClientConn
+	client FileServiceClient
+}
+
+func (ts *serverTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfsgrpc_test")
+	ts.NoError(err)
+	ts.dir = dir
+	ts.NoError(os.MkdirAll(filepath.Join(dir, "served"), 0755))
+	ts.NoError(ioutil.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0644))
+	ts.NoError(ioutil.WriteFile(filepath.Join(dir, "served", "file.txt"), []byte("0123456789"), 0644))
+}
+
+func (ts *serverTestSuite) TearDownTest() {
+	ts.NoError(ts.conn.Close())
+	ts.server.Stop()
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *serverTestSuite) serve(options Options) {
+	location, err := _os.NewFileSystem().NewLocation("", ts.dir+"/served/")
+	ts.NoError(err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	ts.NoError(err)
+	server := grpc.NewServer()
+	RegisterFileServiceServer(server, NewServer(location, options))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	ts.server = server
+	ts.conn, err = grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	ts.NoError(err)
+	ts.client = NewFileServiceClient(ts.conn)
+}
+
+func (ts *serverTestSuite) read(path string, offset int64) (string, error) {
+	stream, err := ts.client.Read(context.Background(), &ReadRequest{Path: path, Offset: offset})
+	ts.NoError(err)
+	var data []byte
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return string(data), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		data = append(data, response.Data...)
+	}
+}
+
+func (ts *serverTestSuite) TestRead() {
+	ts.serve(Options{})
+
+	data, err := ts.read("/file.txt", 0)
+	ts.NoError(err)
+	ts.Equal("0123456789", data)
+	data, err = ts.read("/file.txt", 6)
+	ts.NoError(err)
+	ts.Equal("6789", data)
+
+	_, err = ts.read("/missing.txt", 0)
+	ts.Equal(codes.NotFound, status.Code(err))
+	_, err = ts.read("/../secret.txt", 0)
+	ts.Equal(codes.NotFound, status.Code(err), "paths can't escape the served location")
+	_, err = ts.read("/", 0)
+	ts.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (ts *serverTestSuite) TestWrite() {
+	ts.serve(Options{})
+
+	stream, err := ts.client.Write(context.Background())
+	ts.NoError(err)
+	ts.NoError(stream.Send(&WriteRequest{Request: &WriteRequest_Header{Header: &WriteHeader{Path: "/dir/new.txt"}}}))
+	ts.NoError(stream.Send(&WriteRequest{Request: &WriteRequest_Data{Data: []byte("hello ")}}))
+	ts.NoError(stream.Send(&WriteRequest{Request: &WriteRequest_Data{Data: []byte("world")}}))
+	_, err = stream.CloseAndRecv()
+	ts.NoError(err)
+	data, err := ioutil.ReadFile(filepath.Join(ts.dir, "served", "dir", "new.txt"))
+	ts.NoError(err)
+	ts.Equal("hello world", string(data))
+
+	stream, err = ts.client.Write(context.Background())
+	ts.NoError(err)
+	ts.NoError(stream.Send(&WriteRequest{Request: &WriteRequest_Data{Data: []byte("no header")}}))
+	_, err = stream.CloseAndRecv()
+	ts.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (ts *serverTestSuite) TestFileRequests() {
+	ts.serve(Options{})
+	ctx := context.Background()
+
+	info, err := ts.client.Stat(ctx, &FileRequest{Path: "/file.txt"})
+	ts.NoError(err)
+	ts.Equal("file.txt", info.Name)
+	ts.Equal(int64(10), info.Size)
+	_, err = ts.client.Stat(ctx, &FileRequest{Path: "/missing.txt"})
+	ts.Equal(codes.NotFound, status.Code(err))
+
+	exists, err := ts.client.Exists(ctx, &FileRequest{Path: "/file.txt"})
+	ts.NoError(err)
+	ts.True(exists.Exists)
+	exists, err = ts.client.Exists(ctx, &FileRequest{Path: "/"})
+	ts.NoError(err)
+	ts.True(exists.Exists)
+	exists, err = ts.client.Exists(ctx, &FileRequest{Path: "/missing/"})
+	ts.NoError(err)
+	ts.False(exists.Exists)
+
+	_, err = ts.client.Copy(ctx, &CopyRequest{Source: "/file.txt", Target: "/copy.txt"})
+	ts.NoError(err)
+	_, err = ts.client.Move(ctx, &CopyRequest{Source: "/copy.txt", Target: "/dir/moved.txt"})
+	ts.NoError(err)
+	_, err = ts.client.Touch(ctx, &FileRequest{Path: "/touched.txt"})
+	ts.NoError(err)
+
+	list, err := ts.client.List(ctx, &ListRequest{Path: "/"})
+	ts.NoError(err)
+	ts.ElementsMatch([]string{"file.txt", "touched.txt"}, list.Names)
+	list, err = ts.client.List(ctx, &ListRequest{Path: "/", Prefix: "dir/m"})
+	ts.NoError(err)
+	ts.Equal([]string{"moved.txt"}, list.Names)
+
+	_, err = ts.client.Delete(ctx, &FileRequest{Path: "/touched.txt"})
+	ts.NoError(err)
+	_, err = ts.client.Delete(ctx, &FileRequest{Path: "/touched.txt"})
+	ts.Equal(codes.NotFound, status.Code(err))
+}
+
+func (ts *serverTestSuite) TestReadOnly() {
+	ts.serve(Options{ReadOnly: true})
+	ctx := context.Background()
+
+	data, err := ts.read("/file.txt", 0)
+	ts.NoError(err)
+	ts.Equal("0123456789", data)
+
+	stream, err := ts.client.Write(ctx)
+	ts.NoError(err)
+	_, err = stream.CloseAndRecv()
+	ts.Equal(codes.PermissionDenied, status.Code(err))
+	_, err = ts.client.Copy(ctx, &CopyRequest{Source: "/file.txt", Target: "/copy.txt"})
+	ts.Equal(codes.PermissionDenied, status.Code(err))
+	_, err = ts.client.Move(ctx, &CopyRequest{Source: "/file.txt", Target: "/moved.txt"})
+	ts.Equal(codes.PermissionDenied, status.Code(err))
+	_, err = ts.client.Delete(ctx, &FileRequest{Path: "/file.txt"})
+	ts.Equal(codes.PermissionDenied, status.Code(err))
+	_, err = ts.client.Touch(ctx, &FileRequest{Path: "/file.txt"})
+	ts.Equal(codes.PermissionDenied, status.Code(err))
+}
+
+func TestServer(t *testing.T) {
+	suite.Run(t, new(serverTestSuite))
+}
@@ -0,0 +1,1009 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: vfs.proto
+
+package vfsgrpc
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Empty struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Empty) Reset()         { *m = Empty{} }
+func (m *Empty) String() string { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()    {}
+func (*Empty) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3498b34ebe55a2be, []int{0}
+}
+
+func (m *Empty) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Empty.Unmarshal(m, b)
+}
+func (m *Empty) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Empty.Marshal(b, m, deterministic)
+}
+func (m *Empty) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Empty.Merge(m, src)
+}
+func (m *Empty) XXX_Size() int {
+	return xxx_messageInfo_Empty.Size(m)
+}
+func (m *Empty) XXX_DiscardUnknown() {
+	xxx_messageInfo_Empty.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Empty proto.InternalMessageInfo
+
+type FileRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FileRequest) Reset()         { *m = FileRequest{} }
+func (m *FileRequest) String() string { return proto.CompactTextString(m) }
+func (*FileRequest) ProtoMessage()    {}
+func (*FileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3498b34ebe55a2be, []int{1}
+}
+
+func (m *FileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FileRequest.Unmarshal(m, b)
+}
+func (m *FileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FileRequest.Marshal(b, m, deterministic)
+}
+func (m *FileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileRequest.Merge(m, src)
+}
+func (m *FileRequest) XXX_Size() int {
+	return xxx_messageInfo_FileRequest.Size(m)
+}
+func (m *FileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FileRequest proto.InternalMessageInfo
+
+func (m *FileRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type FileInfo struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Size                 int64    `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	ModTime              int64    `protobuf:"varint,3,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	Mode                 uint32   `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	ContentType          string   `protobuf:"bytes,5,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Etag                 string   `protobuf:"bytes,6,opt,name=etag,proto3" json:"etag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FileInfo) Reset()         { *m = FileInfo{} }
+func (m *FileInfo) String() string { return proto.CompactTextString(m) }
+func (*FileInfo) ProtoMessage()    {}
+func (*FileInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3498b34ebe55a2be, []int{2}
+}
+
+func (m *FileInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FileInfo.Unmarshal(m, b)
+}
+func (m *FileInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FileInfo.Marshal(b, m, deterministic)
+}
+func (m *FileInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileInfo.Merge(m, src)
+}
+func (m *FileInfo) XXX_Size() int {
+	return xxx_messageInfo_FileInfo.Size(m)
+}
+func (m *FileInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FileInfo proto.InternalMessageInfo
+
+func (m *FileInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FileInfo) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *FileInfo) GetModTime() int64 {
+	if m != nil {
+		return m.ModTime
+	}
+	return 0
+}
+
+func (m *FileInfo) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
+}
+
+func (m *FileInfo) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
+	return ""
+}
+
+func (m *FileInfo) GetEtag() string {
+	if m != nil {
+		return m.Etag
+	}
+	return ""
+}
+
+type ExistsResponse struct {
+	Exists               bool     `protobuf:"varint,1,opt,name=exists,proto3" json:"exists,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExistsResponse) Reset()         { *m = ExistsResponse{} }
+func (m *ExistsResponse) String() string { return proto.CompactTextString(m) }
+func (*ExistsResponse) ProtoMessage()    {}
+func (*ExistsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3498b34ebe55a2be, []int{3}
+}
+
+func (m *ExistsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExistsResponse.Unmarshal(m, b)
+}
+func (m *ExistsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExistsResponse.Marshal(b, m, deterministic)
+}
+func (m *ExistsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExistsResponse.Merge(m, src)
+}
+func (m *ExistsResponse) XXX_Size() int {
+	return xxx_messageInfo_ExistsResponse.Size(m)
+}
+func (m *ExistsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExistsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExistsResponse proto.InternalMessageInfo
+
+func (m *ExistsResponse) GetExists() bool {
+	if m != nil {
+		return m.Exists
+	}
+	return false
+}
+
+type ListRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Prefix               string   `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRequest) Reset()         { *m = ListRequest{} }
+func (m *ListRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRequest) ProtoMessage()    {}
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3498b34ebe55a2be, []int{4}
+}
+
+func (m *ListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRequest.Unmarshal(m, b)
+}
+func (m *ListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRequest.Marshal(b, m, deterministic)
+}
+func (m *ListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRequest.Merge(m, src)
+}
+func (m *ListRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRequest.Size(m)
+}
+func (m *ListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRequest proto.InternalMessageInfo
+
+func (m *ListRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ListRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+type ListResponse struct {
+	Names                []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListResponse) Reset()         { *m = ListResponse{} }
+func (m *ListResponse) String() string { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()    {}
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3498b34ebe55a2be, []int{5}
+}
+
+func (m *ListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListResponse.Unmarshal(m, b)
+}
+func (m *ListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListResponse.Marshal(b, m, deterministic)
+}
+func (m *ListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListResponse.Merge(m, src)
+}
+func (m *ListResponse) XXX_Size() int {
+	return xxx_messageInfo_ListResponse.Size(m)
+}
+func (m *ListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListResponse proto.InternalMessageInfo
+
+func (m *ListResponse) GetNames() []string {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+type ReadRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Offset               int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadRequest) Reset()         { *m = ReadRequest{} }
+func (m *ReadRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadRequest) ProtoMessage()    {}
+func (*ReadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3498b34ebe55a2be, []int{6}
+}
+
+func (m *ReadRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadRequest.Unmarshal(m, b)
+}
+func (m *ReadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadRequest.Merge(m, src)
+}
+func (m *ReadRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadRequest.Size(m)
+}
+func (m *ReadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadRequest proto.InternalMessageInfo
+
+func (m *ReadRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ReadRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type ReadResponse struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadResponse) Reset()         { *m = ReadResponse{} }
+func (m *ReadResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadResponse) ProtoMessage()    {}
+func (*ReadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3498b34ebe55a2be, []int{7}
+}
+
+func (m *ReadResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadResponse.Unmarshal(m, b)
+}
+func (m *ReadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadResponse.Merge(m, src)
+}
+func (m *ReadResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadResponse.Size(m)
+}
+func (m *ReadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadResponse proto.InternalMessageInfo
+
+func (m *ReadResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type WriteRequest struct {
+	// Types that are valid to be assigned to Request:
+	//	*WriteRequest_Header
+	//	*WriteRequest_Data
+	Request              isWriteRequest_Request `protobuf_oneof:"request"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *WriteRequest) Reset()         { *m = WriteRequest{} }
+func (m *WriteRequest) String() string { return proto.CompactTextString(m) }
+func (*WriteRequest) ProtoMessage()    {}
+func (*WriteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3498b34ebe55a2be, []int{8}
+}
+
+func (m *WriteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteRequest.Unmarshal(m, b)
+}
+func (m *WriteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteRequest.Marshal(b, m, deterministic)
+}
+func (m *WriteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteRequest.Merge(m, src)
+}
+func (m *WriteRequest) XXX_Size() int {
+	return xxx_messageInfo_WriteRequest.Size(m)
+}
+func (m *WriteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriteRequest proto.InternalMessageInfo
+
+type isWriteRequest_Request interface {
+	isWriteRequest_Request()
+}
+
+type WriteRequest_Header struct {
+	Header *WriteHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type WriteRequest_Data struct {
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"`
+}
+
+func (*WriteRequest_Header) isWriteRequest_Request() {}
+
+func (*WriteRequest_Data) isWriteRequest_Request() {}
+
+func (m *WriteRequest) GetRequest() isWriteRequest_Request {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (m *WriteRequest) GetHeader() *WriteHeader {
+	if x, ok := m.GetRequest().(*WriteRequest_Header); ok {
+		return x.Header
+	}
+	return nil
+}
+
+func (m *WriteRequest) GetData() []byte {
+	if x, ok := m.GetRequest().(*WriteRequest_Data); ok {
+		return x.Data
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*WriteRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*WriteRequest_Header)(nil),
+		(*WriteRequest_Data)(nil),
+	}
+}
+
+type WriteHeader struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Offset               int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WriteHeader) Reset()         { *m = WriteHeader{} }
+func (m *WriteHeader) String() string { return proto.CompactTextString(m) }
+func (*WriteHeader) ProtoMessage()    {}
+func (*WriteHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3498b34ebe55a2be, []int{9}
+}
+
+func (m *WriteHeader) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteHeader.Unmarshal(m, b)
+}
+func (m *WriteHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteHeader.Marshal(b, m, deterministic)
+}
+func (m *WriteHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteHeader.Merge(m, src)
+}
+func (m *WriteHeader) XXX_Size() int {
+	return xxx_messageInfo_WriteHeader.Size(m)
+}
+func (m *WriteHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriteHeader proto.InternalMessageInfo
+
+func (m *WriteHeader) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *WriteHeader) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type CopyRequest struct {
+	Source               string   `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Target               string   `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CopyRequest) Reset()         { *m = CopyRequest{} }
+func (m *CopyRequest) String() string { return proto.CompactTextString(m) }
+func (*CopyRequest) ProtoMessage()    {}
+func (*CopyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3498b34ebe55a2be, []int{10}
+}
+
+func (m *CopyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CopyRequest.Unmarshal(m, b)
+}
+func (m *CopyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CopyRequest.Marshal(b, m, deterministic)
+}
+func (m *CopyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CopyRequest.Merge(m, src)
+}
+func (m *CopyRequest) XXX_Size() int {
+	return xxx_messageInfo_CopyRequest.Size(m)
+}
+func (m *CopyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CopyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CopyRequest proto.InternalMessageInfo
+
+func (m *CopyRequest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *CopyRequest) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*Empty)(nil), "vfsgrpc.Empty")
+	proto.RegisterType((*FileRequest)(nil), "vfsgrpc.FileRequest")
+	proto.RegisterType((*FileInfo)(nil), "vfsgrpc.FileInfo")
+	proto.RegisterType((*ExistsResponse)(nil), "vfsgrpc.ExistsResponse")
+	proto.RegisterType((*ListRequest)(nil), "vfsgrpc.ListRequest")
+	proto.RegisterType((*ListResponse)(nil), "vfsgrpc.ListResponse")
+	proto.RegisterType((*ReadRequest)(nil), "vfsgrpc.ReadRequest")
+	proto.RegisterType((*ReadResponse)(nil), "vfsgrpc.ReadResponse")
+	proto.RegisterType((*WriteRequest)(nil), "vfsgrpc.WriteRequest")
+	proto.RegisterType((*WriteHeader)(nil), "vfsgrpc.WriteHeader")
+	proto.RegisterType((*CopyRequest)(nil), "vfsgrpc.CopyRequest")
+}
+
+func init() { proto.RegisterFile("vfs.proto", fileDescriptor_3498b34ebe55a2be) }
+
+var fileDescriptor_3498b34ebe55a2be = []byte{
+	// 534 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0xad, 0x89, 0xe3, 0xc4, 0x13, 0x53, 0x89, 0x55, 0x52, 0x4c, 0x0e, 0x28, 0xb5, 0x38, 0xf8,
+	0x00, 0x4e, 0x95, 0xaa, 0x42, 0x1c, 0xb8, 0x14, 0x8a, 0x82, 0x04, 0x97, 0x6d, 0x24, 0x24, 0x2e,
+	0x91, 0x6b, 0x8f, 0x13, 0x4b, 0x75, 0xd6, 0x78, 0x37, 0x56, 0xc3, 0x2f, 0xe1, 0x67, 0xf1, 0x93,
+	0xd0, 0x7e, 0xa4, 0x75, 0x2d, 0x15, 0xca, 0x6d, 0xe6, 0xe5, 0xbd, 0x7d, 0xf3, 0x15, 0x83, 0x5b,
+	0x67, 0x3c, 0x2a, 0x2b, 0x26, 0x18, 0xe9, 0xd5, 0x19, 0x5f, 0x55, 0x65, 0x12, 0xf4, 0xa0, 0x7b,
+	0x51, 0x94, 0x62, 0x17, 0x1c, 0xc3, 0xe0, 0x53, 0x7e, 0x8d, 0x14, 0x7f, 0x6c, 0x91, 0x0b, 0x42,
+	0xc0, 0x2e, 0x63, 0xb1, 0xf6, 0xad, 0x89, 0x15, 0xba, 0x54, 0xc5, 0xc1, 0x2f, 0x0b, 0xfa, 0x92,
+	0xf3, 0x79, 0x93, 0x31, 0x49, 0xd8, 0xc4, 0x05, 0xee, 0x09, 0x32, 0x96, 0x18, 0xcf, 0x7f, 0xa2,
+	0xff, 0x64, 0x62, 0x85, 0x1d, 0xaa, 0x62, 0xf2, 0x02, 0xfa, 0x05, 0x4b, 0x97, 0x22, 0x2f, 0xd0,
+	0xef, 0x28, 0xbc, 0x57, 0xb0, 0x74, 0x91, 0x6b, 0x7a, 0xc1, 0x52, 0xf4, 0xed, 0x89, 0x15, 0x3e,
+	0xa5, 0x2a, 0x26, 0xc7, 0xe0, 0x25, 0x6c, 0x23, 0x70, 0x23, 0x96, 0x62, 0x57, 0xa2, 0xdf, 0x55,
+	0xcf, 0x0f, 0x0c, 0xb6, 0xd8, 0x95, 0x4a, 0x86, 0x22, 0x5e, 0xf9, 0x8e, 0x76, 0x96, 0x71, 0x10,
+	0xc2, 0xe1, 0xc5, 0x4d, 0xce, 0x05, 0xa7, 0xc8, 0x4b, 0xb6, 0xe1, 0x48, 0x8e, 0xc0, 0x41, 0x85,
+	0xa8, 0x0a, 0xfb, 0xd4, 0x64, 0xc1, 0x3b, 0x18, 0x7c, 0xc9, 0xb9, 0xf8, 0x4b, 0x9f, 0x52, 0x5a,
+	0x56, 0x98, 0xe5, 0x37, 0xaa, 0x11, 0x97, 0x9a, 0x2c, 0x78, 0x05, 0x9e, 0x96, 0x1a, 0x8b, 0x21,
+	0x74, 0x65, 0xdb, 0xd2, 0xa1, 0x13, 0xba, 0x54, 0x27, 0xd2, 0x80, 0x62, 0x9c, 0xfe, 0xc3, 0x80,
+	0x65, 0x19, 0x47, 0x61, 0x26, 0x65, 0xb2, 0x20, 0x00, 0x4f, 0x4b, 0x8d, 0x01, 0x01, 0x3b, 0x8d,
+	0x45, 0xac, 0xb4, 0x1e, 0x55, 0x71, 0xb0, 0x04, 0xef, 0x5b, 0x95, 0x8b, 0xdb, 0x45, 0x45, 0xe0,
+	0xac, 0x31, 0x4e, 0xb1, 0x52, 0xac, 0xc1, 0x6c, 0x18, 0x99, 0xd5, 0x46, 0x8a, 0x36, 0x57, 0xbf,
+	0xcd, 0x0f, 0xa8, 0x61, 0x91, 0xa1, 0x79, 0x53, 0x3a, 0x7b, 0xf3, 0x03, 0xfd, 0xea, 0xb9, 0x0b,
+	0xbd, 0x4a, 0x3f, 0x28, 0xeb, 0x6f, 0x28, 0xff, 0xab, 0xfe, 0xf7, 0x30, 0xf8, 0xc0, 0xca, 0xdd,
+	0xbe, 0xb4, 0x23, 0x70, 0x38, 0xdb, 0x56, 0xc9, 0xfe, 0x48, 0x4c, 0x26, 0x71, 0x11, 0x57, 0x2b,
+	0x23, 0x77, 0xa9, 0xc9, 0x66, 0xbf, 0x3b, 0xfa, 0x06, 0x2f, 0xb1, 0xaa, 0xf3, 0x04, 0xc9, 0x14,
+	0xec, 0x4b, 0x11, 0x0b, 0x72, 0xd7, 0x52, 0xe3, 0x42, 0xc7, 0xcf, 0xee, 0xa1, 0xea, 0x26, 0xdf,
+	0x82, 0xa3, 0xaf, 0xe0, 0x01, 0xc9, 0xf3, 0x5b, 0xb4, 0x75, 0x2c, 0xa7, 0x60, 0xcb, 0xcd, 0x36,
+	0x64, 0x8d, 0x1b, 0x19, 0x8f, 0x5a, 0xa8, 0x11, 0x9d, 0x81, 0x2d, 0xb7, 0xd5, 0x10, 0x35, 0xf6,
+	0x3e, 0x1e, 0xb5, 0x50, 0x2d, 0x3a, 0xb1, 0xc8, 0x09, 0x74, 0xd5, 0x7c, 0xc9, 0xe8, 0xfe, 0xa6,
+	0xf6, 0xc2, 0xc3, 0xbb, 0x22, 0xe5, 0x1f, 0x33, 0xb4, 0xc8, 0x6b, 0xb0, 0xe5, 0x58, 0x1b, 0x46,
+	0x8d, 0x29, 0xb7, 0xf9, 0x92, 0xfd, 0x95, 0xd5, 0xf8, 0x48, 0x76, 0x04, 0xce, 0x47, 0xbc, 0x46,
+	0x81, 0x0f, 0x8c, 0xac, 0xcd, 0x7f, 0x03, 0xdd, 0x05, 0xdb, 0x26, 0xeb, 0xc7, 0xd1, 0xcf, 0x27,
+	0xdf, 0x5f, 0xae, 0x72, 0xb1, 0xde, 0x5e, 0x45, 0x09, 0x2b, 0xa6, 0xc9, 0x2c, 0x63, 0xd3, 0x3a,
+	0xe3, 0xd3, 0xfa, 0x6c, 0x6a, 0x78, 0x57, 0x8e, 0xfa, 0x20, 0x9d, 0xfe, 0x19, 0x00, 0xc3, 0xf6,
+	0xb0, 0xf7, 0x9d, 0x04, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// FileServiceClient is the client API for FileService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type FileServiceClient interface {
+	Stat(ctx context.Context, in *FileRequest, opts ...grpc.CallOption) (*FileInfo, error)
+	Exists(ctx context.Context, in *FileRequest, opts ...grpc.CallOption) (*ExistsResponse, error)
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (FileService_ReadClient, error)
+	Write(ctx context.Context, opts ...grpc.CallOption) (FileService_WriteClient, error)
+	Copy(ctx context.Context, in *CopyRequest, opts ...grpc.CallOption) (*Empty, error)
+	Move(ctx context.Context, in *CopyRequest, opts ...grpc.CallOption) (*Empty, error)
+	Delete(ctx context.Context, in *FileRequest, opts ...grpc.CallOption) (*Empty, error)
+	Touch(ctx context.Context, in *FileRequest, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type fileServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewFileServiceClient(cc *grpc.ClientConn) FileServiceClient {
+	return &fileServiceClient{cc}
+}
+
+func (c *fileServiceClient) Stat(ctx context.Context, in *FileRequest, opts ...grpc.CallOption) (*FileInfo, error) {
+	out := new(FileInfo)
+	err := c.cc.Invoke(ctx, "/vfsgrpc.FileService/Stat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) Exists(ctx context.Context, in *FileRequest, opts ...grpc.CallOption) (*ExistsResponse, error) {
+	out := new(ExistsResponse)
+	err := c.cc.Invoke(ctx, "/vfsgrpc.FileService/Exists", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, "/vfsgrpc.FileService/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (FileService_ReadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FileService_serviceDesc.Streams[0], "/vfsgrpc.FileService/Read", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileServiceReadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FileService_ReadClient interface {
+	Recv() (*ReadResponse, error)
+	grpc.ClientStream
+}
+
+type fileServiceReadClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileServiceReadClient) Recv() (*ReadResponse, error) {
+	m := new(ReadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileServiceClient) Write(ctx context.Context, opts ...grpc.CallOption) (FileService_WriteClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FileService_serviceDesc.Streams[1], "/vfsgrpc.FileService/Write", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileServiceWriteClient{stream}
+	return x, nil
+}
+
+type FileService_WriteClient interface {
+	Send(*WriteRequest) error
+	CloseAndRecv() (*Empty, error)
+	grpc.ClientStream
+}
+
+type fileServiceWriteClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileServiceWriteClient) Send(m *WriteRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fileServiceWriteClient) CloseAndRecv() (*Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileServiceClient) Copy(ctx context.Context, in *CopyRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/vfsgrpc.FileService/Copy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) Move(ctx context.Context, in *CopyRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/vfsgrpc.FileService/Move", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) Delete(ctx context.Context, in *FileRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/vfsgrpc.FileService/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) Touch(ctx context.Context, in *FileRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/vfsgrpc.FileService/Touch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FileServiceServer is the server API for FileService service.
+type FileServiceServer interface {
+	Stat(context.Context, *FileRequest) (*FileInfo, error)
+	Exists(context.Context, *FileRequest) (*ExistsResponse, error)
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	Read(*ReadRequest, FileService_ReadServer) error
+	Write(FileService_WriteServer) error
+	Copy(context.Context, *CopyRequest) (*Empty, error)
+	Move(context.Context, *CopyRequest) (*Empty, error)
+	Delete(context.Context, *FileRequest) (*Empty, error)
+	Touch(context.Context, *FileRequest) (*Empty, error)
+}
+
+func RegisterFileServiceServer(s *grpc.Server, srv FileServiceServer) {
+	s.RegisterService(&_FileService_serviceDesc, srv)
+}
+
+func _FileService_Stat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).Stat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vfsgrpc.FileService/Stat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).Stat(ctx, req.(*FileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_Exists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).Exists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vfsgrpc.FileService/Exists",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).Exists(ctx, req.(*FileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vfsgrpc.FileService/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_Read_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileServiceServer).Read(m, &fileServiceReadServer{stream})
+}
+
+type FileService_ReadServer interface {
+	Send(*ReadResponse) error
+	grpc.ServerStream
+}
+
+type fileServiceReadServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileServiceReadServer) Send(m *ReadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FileService_Write_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileServiceServer).Write(&fileServiceWriteServer{stream})
+}
+
+type FileService_WriteServer interface {
+	SendAndClose(*Empty) error
+	Recv() (*WriteRequest, error)
+	grpc.ServerStream
+}
+
+type fileServiceWriteServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileServiceWriteServer) SendAndClose(m *Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fileServiceWriteServer) Recv() (*WriteRequest, error) {
+	m := new(WriteRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _FileService_Copy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CopyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).Copy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vfsgrpc.FileService/Copy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).Copy(ctx, req.(*CopyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_Move_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CopyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).Move(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vfsgrpc.FileService/Move",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).Move(ctx, req.(*CopyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vfsgrpc.FileService/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).Delete(ctx, req.(*FileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_Touch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).Touch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vfsgrpc.FileService/Touch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).Touch(ctx, req.(*FileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _FileService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "vfsgrpc.FileService",
+	HandlerType: (*FileServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Stat",
+			Handler:    _FileService_Stat_Handler,
+		},
+		{
+			MethodName: "Exists",
+			Handler:    _FileService_Exists_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _FileService_List_Handler,
+		},
+		{
+			MethodName: "Copy",
+			Handler:    _FileService_Copy_Handler,
+		},
+		{
+			MethodName: "Move",
+			Handler:    _FileService_Move_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _FileService_Delete_Handler,
+		},
+		{
+			MethodName: "Touch",
+			Handler:    _FileService_Touch_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Read",
+			Handler:       _FileService_Read_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Write",
+			Handler:       _FileService_Write_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "vfs.proto",
+}
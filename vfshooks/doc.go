@@ -0,0 +1,32 @@
+/*
+Package vfshooks wraps any vfs.FileSystem to call hooks before and after each call made through it, so policy code,
+ie: auditing, allow-lists or metrics, can be attached once for every backend rather than wrapping each separately.
+
+Usage
+
+Wrap a file system with hooks, and use it in its place:
+
+  fs := vfshooks.NewFileSystem(s3.NewFileSystem(), vfshooks.HookFuncs{
+      Before: func(op vfshooks.Op) error {
+          if op.Kind == vfshooks.KindDelete {
+              return errors.New("deletes aren't allowed")
+          }
+          return nil
+      },
+      After: func(op vfshooks.Op, duration time.Duration, err error) {
+          log.Printf("%s %s took %s: %v", op.Name, op.URI, duration, err)
+      },
+  })
+  file, err := fs.NewFile("mybucket", "/path/to/file.txt")
+
+Ops
+
+Each call that reaches the wrapped file system is an Op, named by type and method, ie: "File.Write" or
+"Location.List", with the URI it's made on, the URI copied or moved to, if any, and its Kind: whether it reads,
+writes or deletes.  Calls which don't, ie: NewFile or Path, aren't hooked.
+
+Hooks are called in the order given before each call, and in reverse order after it.  A hook returning an error from
+OnBeforeOp fails the call with that error, without reaching the wrapped file system or the hooks that follow; OnAfterOp
+is still called on every hook, with the error.
+*/
+package vfshooks
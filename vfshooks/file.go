@@ -0,0 +1,172 @@
+package vfshooks
+
+import (
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// File is a vfs.File of a FileSystem, calling its hooks before and after calls to the wrapped file.
+type File struct {
+	fileSystem *FileSystem
+	file       vfs.File
+}
+
+// Close implements io.Closer.
+func (f *File) Close() error {
+	return f.run("File.Close", KindClose, f.file.Close)
+}
+
+// Read implements io.Reader.
+func (f *File) Read(p []byte) (int, error) {
+	var n int
+	err := f.run("File.Read", KindRead, func() error {
+		var err error
+		n, err = f.file.Read(p)
+		return err
+	})
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	err := f.run("File.Seek", KindRead, func() error {
+		var err error
+		pos, err = f.file.Seek(offset, whence)
+		return err
+	})
+	return pos, err
+}
+
+// Write implements io.Writer.
+func (f *File) Write(p []byte) (int, error) {
+	var n int
+	err := f.run("File.Write", KindWrite, func() error {
+		var err error
+		n, err = f.file.Write(p)
+		return err
+	})
+	return n, err
+}
+
+// String implements fmt.Stringer.
+func (f *File) String() string {
+	return f.file.String()
+}
+
+// Exists implements vfs.File.
+func (f *File) Exists() (bool, error) {
+	var exists bool
+	err := f.run("File.Exists", KindRead, func() error {
+		var err error
+		exists, err = f.file.Exists()
+		return err
+	})
+	return exists, err
+}
+
+// Location implements vfs.File.
+func (f *File) Location() vfs.Location {
+	return f.fileSystem.wrapLocation(f.file.Location())
+}
+
+// CopyToLocation implements vfs.File.
+func (f *File) CopyToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	var file vfs.File
+	op := Op{Name: "File.CopyToLocation", Kind: KindRead, URI: f.file.URI(), Target: location.URI()}
+	err := f.fileSystem.run(op, func() error {
+		var err error
+		file, err = f.file.CopyToLocation(unwrapLocation(location), opts...)
+		return err
+	})
+	return f.fileSystem.wrapFile(file), err
+}
+
+// CopyToFile implements vfs.File.
+func (f *File) CopyToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	op := Op{Name: "File.CopyToFile", Kind: KindRead, URI: f.file.URI(), Target: file.URI()}
+	return f.fileSystem.run(op, func() error {
+		return f.file.CopyToFile(unwrapFile(file), opts...)
+	})
+}
+
+// MoveToLocation implements vfs.File.
+func (f *File) MoveToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	var file vfs.File
+	op := Op{Name: "File.MoveToLocation", Kind: KindDelete, URI: f.file.URI(), Target: location.URI()}
+	err := f.fileSystem.run(op, func() error {
+		var err error
+		file, err = f.file.MoveToLocation(unwrapLocation(location), opts...)
+		return err
+	})
+	return f.fileSystem.wrapFile(file), err
+}
+
+// MoveToFile implements vfs.File.
+func (f *File) MoveToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	op := Op{Name: "File.MoveToFile", Kind: KindDelete, URI: f.file.URI(), Target: file.URI()}
+	return f.fileSystem.run(op, func() error {
+		return f.file.MoveToFile(unwrapFile(file), opts...)
+	})
+}
+
+// Delete implements vfs.File.
+func (f *File) Delete() error {
+	return f.run("File.Delete", KindDelete, f.file.Delete)
+}
+
+// LastModified implements vfs.File.
+func (f *File) LastModified() (*time.Time, error) {
+	var lastModified *time.Time
+	err := f.run("File.LastModified", KindRead, func() error {
+		var err error
+		lastModified, err = f.file.LastModified()
+		return err
+	})
+	return lastModified, err
+}
+
+// Size implements vfs.File.
+func (f *File) Size() (uint64, error) {
+	var size uint64
+	err := f.run("File.Size", KindRead, func() error {
+		var err error
+		size, err = f.file.Size()
+		return err
+	})
+	return size, err
+}
+
+// Path implements vfs.File.
+func (f *File) Path() string {
+	return f.file.Path()
+}
+
+// Name implements vfs.File.
+func (f *File) Name() string {
+	return f.file.Name()
+}
+
+// Touch implements vfs.File.
+func (f *File) Touch() error {
+	return f.run("File.Touch", KindWrite, f.file.Touch)
+}
+
+// URI implements vfs.File.
+func (f *File) URI() string {
+	return f.file.URI()
+}
+
+// run runs fn, the call name of the given kind on the file, between the hooks.
+func (f *File) run(name string, kind Kind, fn func() error) error {
+	return f.fileSystem.run(Op{Name: name, Kind: kind, URI: f.file.URI()}, fn)
+}
+
+// unwrapFile returns the wrapped file of a File, so it's passed to the wrapped file system as one of its own.
+func unwrapFile(file vfs.File) vfs.File {
+	if f, ok := file.(*File); ok {
+		return f.file
+	}
+	return file
+}
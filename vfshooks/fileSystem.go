@@ -0,0 +1,80 @@
+package vfshooks
+
+import (
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// FileSystem is a vfs.FileSystem which calls hooks before and after the calls made to a wrapped file system.
+type FileSystem struct {
+	fs    vfs.FileSystem
+	hooks []Hook
+}
+
+// NewFileSystem returns a FileSystem which calls hooks, in order, before and after calls to fs.
+func NewFileSystem(fs vfs.FileSystem, hooks ...Hook) *FileSystem {
+	return &FileSystem{fs: fs, hooks: hooks}
+}
+
+// NewFile implements vfs.FileSystem.
+func (fs *FileSystem) NewFile(volume string, absFilePath string) (vfs.File, error) {
+	file, err := fs.fs.NewFile(volume, absFilePath)
+	return fs.wrapFile(file), err
+}
+
+// NewLocation implements vfs.FileSystem.
+func (fs *FileSystem) NewLocation(volume string, absLocPath string) (vfs.Location, error) {
+	location, err := fs.fs.NewLocation(volume, absLocPath)
+	return fs.wrapLocation(location), err
+}
+
+// Name implements vfs.FileSystem.
+func (fs *FileSystem) Name() string {
+	return "Hooked " + fs.fs.Name()
+}
+
+// Scheme implements vfs.FileSystem, returning the wrapped file system's scheme.
+func (fs *FileSystem) Scheme() string {
+	return fs.fs.Scheme()
+}
+
+// Retry implements vfs.FileSystem, returning the wrapped file system's retryer.
+func (fs *FileSystem) Retry() vfs.Retry {
+	return fs.fs.Retry()
+}
+
+// run calls fn, which makes op, between the hooks' OnBeforeOp and OnAfterOp.  fn isn't called if a hook rejects op.
+func (fs *FileSystem) run(op Op, fn func() error) error {
+	for _, hook := range fs.hooks {
+		if err := hook.OnBeforeOp(op); err != nil {
+			fs.after(op, 0, err)
+			return err
+		}
+	}
+	start := time.Now()
+	err := fn()
+	fs.after(op, time.Since(start), err)
+	return err
+}
+
+// after calls the hooks' OnAfterOp, in reverse order.
+func (fs *FileSystem) after(op Op, duration time.Duration, err error) {
+	for i := len(fs.hooks) - 1; i >= 0; i-- {
+		fs.hooks[i].OnAfterOp(op, duration, err)
+	}
+}
+
+func (fs *FileSystem) wrapFile(file vfs.File) vfs.File {
+	if file == nil {
+		return nil
+	}
+	return &File{fileSystem: fs, file: file}
+}
+
+func (fs *FileSystem) wrapLocation(location vfs.Location) vfs.Location {
+	if location == nil {
+		return nil
+	}
+	return &Location{fileSystem: fs, location: location}
+}
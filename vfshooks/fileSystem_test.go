@@ -0,0 +1,164 @@
+package vfshooks_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/vfshooks"
+	"github.com/c2fo/vfs/v5/vfstest"
+)
+
+// recorder is a Hook which records the calls made to it, optionally rejecting ops with reject.
+type recorder struct {
+	name   string
+	calls  *[]string
+	ops    []vfshooks.Op
+	errs   []error
+	reject func(op vfshooks.Op) error
+}
+
+func (r *recorder) OnBeforeOp(op vfshooks.Op) error {
+	*r.calls = append(*r.calls, r.name+" before "+op.Name)
+	if r.reject != nil {
+		return r.reject(op)
+	}
+	return nil
+}
+
+func (r *recorder) OnAfterOp(op vfshooks.Op, duration time.Duration, err error) {
+	*r.calls = append(*r.calls, r.name+" after "+op.Name)
+	r.ops = append(r.ops, op)
+	r.errs = append(r.errs, err)
+}
+
+type fileSystemTestSuite struct {
+	suite.Suite
+	dir   string
+	calls []string
+}
+
+func (ts *fileSystemTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfshooks_test")
+	ts.NoError(err)
+	ts.dir = dir
+	ts.calls = nil
+}
+
+func (ts *fileSystemTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *fileSystemTestSuite) TestOps() {
+	hook := &recorder{name: "a", calls: &ts.calls}
+	fs := vfshooks.NewFileSystem(_os.NewFileSystem(), hook)
+	ts.Equal("Hooked os", fs.Name())
+
+	file, err := fs.NewFile("", ts.dir+"/file.txt")
+	ts.NoError(err)
+	ts.Empty(hook.ops, "creating a file isn't hooked")
+	_, err = file.Write([]byte("hello"))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+
+	location, err := fs.NewLocation("", ts.dir+"/copies/")
+	ts.NoError(err)
+	copied, err := file.CopyToLocation(location)
+	ts.NoError(err)
+	ts.IsType(&vfshooks.File{}, copied, "returned files are wrapped")
+	_, err = location.List()
+	ts.NoError(err)
+	ts.NoError(location.DeleteFile("file.txt"))
+
+	ts.Equal([]vfshooks.Op{
+		{Name: "File.Write", Kind: vfshooks.KindWrite, URI: file.URI()},
+		{Name: "File.Close", Kind: vfshooks.KindClose, URI: file.URI()},
+		{Name: "File.CopyToLocation", Kind: vfshooks.KindRead, URI: file.URI(), Target: location.URI()},
+		{Name: "Location.List", Kind: vfshooks.KindRead, URI: location.URI()},
+		{Name: "Location.DeleteFile", Kind: vfshooks.KindDelete, URI: copied.URI()},
+	}, hook.ops)
+	ts.Equal([]error{nil, nil, nil, nil, nil}, hook.errs)
+}
+
+func (ts *fileSystemTestSuite) TestErrorsPassedToAfter() {
+	hook := &recorder{name: "a", calls: &ts.calls}
+	fs := vfshooks.NewFileSystem(_os.NewFileSystem(), hook)
+	file, err := fs.NewFile("", ts.dir+"/missing.txt")
+	ts.NoError(err)
+
+	_, err = file.Size()
+	ts.Error(err)
+	ts.Equal([]error{err}, hook.errs)
+}
+
+func (ts *fileSystemTestSuite) TestReject() {
+	errDenied := errors.New("denied")
+	first := &recorder{name: "a", calls: &ts.calls}
+	second := &recorder{name: "b", calls: &ts.calls, reject: func(op vfshooks.Op) error {
+		if op.Kind == vfshooks.KindDelete {
+			return errDenied
+		}
+		return nil
+	}}
+	third := &recorder{name: "c", calls: &ts.calls}
+	fs := vfshooks.NewFileSystem(_os.NewFileSystem(), first, second, third)
+
+	file, err := fs.NewFile("", ts.dir+"/file.txt")
+	ts.NoError(err)
+	ts.NoError(file.Touch())
+	ts.Equal(errDenied, file.Delete())
+
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.True(exists, "rejected calls don't reach the wrapped file system")
+
+	ts.Equal([]string{
+		"a before File.Touch", "b before File.Touch", "c before File.Touch",
+		"c after File.Touch", "b after File.Touch", "a after File.Touch",
+		"a before File.Delete", "b before File.Delete",
+		"c after File.Delete", "b after File.Delete", "a after File.Delete",
+		"a before File.Exists", "b before File.Exists", "c before File.Exists",
+		"c after File.Exists", "b after File.Exists", "a after File.Exists",
+	}, ts.calls)
+	ts.Equal(errDenied, third.errs[1], "every hook is told of the rejection")
+}
+
+func (ts *fileSystemTestSuite) TestHookFuncs() {
+	var duration time.Duration
+	fs := vfshooks.NewFileSystem(_os.NewFileSystem(), vfshooks.HookFuncs{
+		After: func(op vfshooks.Op, d time.Duration, err error) {
+			duration = d
+		},
+	})
+	location, err := fs.NewLocation("", ts.dir+"/")
+	ts.NoError(err)
+	exists, err := location.Exists()
+	ts.NoError(err)
+	ts.True(exists)
+	ts.True(duration > 0)
+
+	ts.Equal("delete", vfshooks.KindDelete.String())
+}
+
+func TestFileSystem(t *testing.T) {
+	suite.Run(t, new(fileSystemTestSuite))
+}
+
+func TestConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vfshooks_conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	location, err := vfshooks.NewFileSystem(_os.NewFileSystem(), vfshooks.HookFuncs{}).NewLocation("", dir+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vfstest.Run(t, location)
+}
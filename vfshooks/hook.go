@@ -0,0 +1,73 @@
+package vfshooks
+
+import (
+	"time"
+)
+
+// Kind is the kind of access an Op makes to its URI.
+type Kind int
+
+// The kinds of Op.
+const (
+	// KindRead ops read a file's contents or metadata, or list a location.  Copies read their source.
+	KindRead Kind = iota
+	// KindWrite ops write a file's contents or, for Touch, its modification time.
+	KindWrite
+	// KindDelete ops delete a file.  Moves delete their source.
+	KindDelete
+	// KindClose ops close a file, completing the reads or writes already made to it.
+	KindClose
+)
+
+var kindNames = map[Kind]string{
+	KindRead:   "read",
+	KindWrite:  "write",
+	KindDelete: "delete",
+	KindClose:  "close",
+}
+
+// String returns the name of the kind, ie: "read".
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Op is a call made through a FileSystem.
+type Op struct {
+	// Name is the call, named by type and method, ie: "File.Write" or "Location.List".
+	Name string
+	// Kind is the kind of access the call makes to URI.
+	Kind Kind
+	// URI is the URI of the file or location the call is made on.  For Location.DeleteFile, it's the file's URI.
+	URI string
+	// Target is the URI of the file or location copied or moved to, which is written, or "" for other calls.
+	Target string
+}
+
+// Hook is called before and after each call made through a FileSystem.
+type Hook interface {
+	// OnBeforeOp is called before op is made.  Returning an error fails the call with it.
+	OnBeforeOp(op Op) error
+	// OnAfterOp is called once op returns, with how long it took and the error it returned, if any.
+	OnAfterOp(op Op, duration time.Duration, err error)
+}
+
+// HookFuncs is a Hook calling its functions, either of which may be nil.
+type HookFuncs struct {
+	Before func(op Op) error
+	After  func(op Op, duration time.Duration, err error)
+}
+
+// OnBeforeOp implements Hook.
+func (h HookFuncs) OnBeforeOp(op Op) error {
+	if h.Before == nil {
+		return nil
+	}
+	return h.Before(op)
+}
+
+// OnAfterOp implements Hook.
+func (h HookFuncs) OnAfterOp(op Op, duration time.Duration, err error) {
+	if h.After != nil {
+		h.After(op, duration, err)
+	}
+}
@@ -0,0 +1,125 @@
+package vfshooks
+
+import (
+	"regexp"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// Location is a vfs.Location of a FileSystem, calling its hooks before and after calls to the wrapped location.
+type Location struct {
+	fileSystem *FileSystem
+	location   vfs.Location
+}
+
+// String implements fmt.Stringer.
+func (l *Location) String() string {
+	return l.location.String()
+}
+
+// List implements vfs.Location.
+func (l *Location) List() ([]string, error) {
+	var names []string
+	err := l.run("Location.List", func() error {
+		var err error
+		names, err = l.location.List()
+		return err
+	})
+	return names, err
+}
+
+// ListByPrefix implements vfs.Location.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	var names []string
+	err := l.run("Location.ListByPrefix", func() error {
+		var err error
+		names, err = l.location.ListByPrefix(prefix)
+		return err
+	})
+	return names, err
+}
+
+// ListByRegex implements vfs.Location.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	var names []string
+	err := l.run("Location.ListByRegex", func() error {
+		var err error
+		names, err = l.location.ListByRegex(regex)
+		return err
+	})
+	return names, err
+}
+
+// Volume implements vfs.Location.
+func (l *Location) Volume() string {
+	return l.location.Volume()
+}
+
+// Path implements vfs.Location.
+func (l *Location) Path() string {
+	return l.location.Path()
+}
+
+// Exists implements vfs.Location.
+func (l *Location) Exists() (bool, error) {
+	var exists bool
+	err := l.run("Location.Exists", func() error {
+		var err error
+		exists, err = l.location.Exists()
+		return err
+	})
+	return exists, err
+}
+
+// NewLocation implements vfs.Location.
+func (l *Location) NewLocation(relLocPath string) (vfs.Location, error) {
+	location, err := l.location.NewLocation(relLocPath)
+	return l.fileSystem.wrapLocation(location), err
+}
+
+// ChangeDir implements vfs.Location.
+func (l *Location) ChangeDir(relLocPath string) error {
+	return l.location.ChangeDir(relLocPath)
+}
+
+// FileSystem implements vfs.Location.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.fileSystem
+}
+
+// NewFile implements vfs.Location.
+func (l *Location) NewFile(relFilePath string) (vfs.File, error) {
+	file, err := l.location.NewFile(relFilePath)
+	return l.fileSystem.wrapFile(file), err
+}
+
+// DeleteFile implements vfs.Location.  The Op's URI is that of the file deleted.
+func (l *Location) DeleteFile(relFilePath string) error {
+	file, err := l.location.NewFile(relFilePath)
+	if err != nil {
+		return err
+	}
+	op := Op{Name: "Location.DeleteFile", Kind: KindDelete, URI: file.URI()}
+	return l.fileSystem.run(op, func() error {
+		return l.location.DeleteFile(relFilePath)
+	})
+}
+
+// URI implements vfs.Location.
+func (l *Location) URI() string {
+	return l.location.URI()
+}
+
+// run runs fn, a read of the location named name, between the hooks.
+func (l *Location) run(name string, fn func() error) error {
+	return l.fileSystem.run(Op{Name: name, Kind: KindRead, URI: l.location.URI()}, fn)
+}
+
+// unwrapLocation returns the wrapped location of a Location, so it's passed to the wrapped file system as one of its
+// own.
+func unwrapLocation(location vfs.Location) vfs.Location {
+	if l, ok := location.(*Location); ok {
+		return l.location
+	}
+	return location
+}
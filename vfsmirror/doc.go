@@ -0,0 +1,37 @@
+/*
+Package vfsmirror keeps the files at a destination vfs.Location continuously in sync with those at a source, on any
+pair of backends, ie: replicating an s3 bucket to gcs.
+
+Usage
+
+Create a Mirror and run it in its own goroutine, stopping it on shutdown:
+
+  mirror := vfsmirror.NewMirror(source, destination, vfsmirror.Options{
+      Interval: 5 * time.Minute,
+      Delete:   true,
+      Filter:   func(name string) bool { return !strings.HasSuffix(name, ".tmp") },
+      OnError:  func(err error) { log.Printf("mirror failed: %s", err) },
+  })
+  go func() { _ = mirror.Run() }()
+  ...
+  mirror.Stop()
+
+Or sync once, ie: from a scheduled job:
+
+  result, err := vfsmirror.NewMirror(source, destination, vfsmirror.Options{}).Sync()
+
+Syncing
+
+vfs has no way to be notified of changes to a location, so a Mirror polls: each sync compares the source with the
+destination using utils.Diff, copying the files that are missing from the destination or differ in size, or in
+modification time or checksum where Options.DiffOptions asks for it.  With Options.Delete, files at the destination
+that are no longer at the source are deleted, so the destination matches it exactly.  Empty locations left behind
+aren't removed, since most backends don't have them.
+
+Files beneath the source are included where its backend can list sub-locations (s3, gs and os); otherwise, as with
+Location.List, only the files directly at the source are.
+
+A sync that fails stops at the first error.  Run reports it to Options.OnError and tries again at the next interval, so
+a transient failure is made good by the following sync.
+*/
+package vfsmirror
@@ -0,0 +1,220 @@
+package vfsmirror
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// DefaultInterval is how often Run syncs when Options.Interval isn't set.
+const DefaultInterval = time.Minute
+
+// Options configures a Mirror.
+type Options struct {
+	// Interval is how long Run waits between syncs, DefaultInterval if not set.
+	Interval time.Duration
+	// Filter, if set, is called with the path relative to the source of each file, and of each sub-location with a
+	// trailing slash, ie: "logs/".  Those it returns false for are neither copied nor deleted, nor is anything beneath a
+	// sub-location.
+	Filter func(name string) bool
+	// Delete propagates deletes, deleting files at the destination that no longer exist at the source.
+	Delete bool
+	// DiffOptions determines how files at both the source and destination are compared to decide whether to copy them.
+	DiffOptions utils.DiffOptions
+	// CopyOptions are passed to CopyToFile for each file copied, ie: vfs.WithBandwidthLimit.
+	CopyOptions []vfs.CopyOption
+	// OnSync, if set, is called by Run with the result of each sync that succeeds.
+	OnSync func(*Result)
+	// OnError, if set, is called by Run with the error of each sync that fails.  Run carries on, retrying at the next
+	// interval.
+	OnError func(error)
+}
+
+// Result describes the changes a sync made to the destination.
+type Result struct {
+	// Copied lists the files copied, by their path relative to the source.
+	Copied []string
+	// Deleted lists the files deleted, by their path relative to the destination.
+	Deleted []string
+}
+
+// Mirror keeps a destination location in sync with a source.
+type Mirror struct {
+	source      vfs.Location
+	destination vfs.Location
+	opts        Options
+	// syncMu serializes syncs, so one started by Sync doesn't overlap one started by Run.
+	syncMu sync.Mutex
+	mu     sync.Mutex
+	ran    bool
+	done   chan struct{}
+	stop   chan struct{}
+	once   sync.Once
+}
+
+// NewMirror returns a Mirror of source into destination.
+func NewMirror(source, destination vfs.Location, opts Options) *Mirror {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	return &Mirror{source: source, destination: destination, opts: opts, stop: make(chan struct{})}
+}
+
+// Sync makes a single pass over the source, copying the files that are new or changed to the destination and, with
+// Options.Delete, deleting those no longer at the source.  If a copy or delete fails, Sync stops and returns the error
+// along with the changes made so far.
+func (m *Mirror) Sync() (*Result, error) {
+	m.syncMu.Lock()
+	defer m.syncMu.Unlock()
+
+	result := &Result{Copied: []string{}, Deleted: []string{}}
+	return result, m.sync(m.source, m.destination, "", result)
+}
+
+// Run syncs immediately then every Options.Interval until Stop is called, when it returns nil.  It's intended to be run
+// in its own goroutine.  A Mirror can only be run once; Run returns an error if it's called again.
+func (m *Mirror) Run() error {
+	m.mu.Lock()
+	if m.ran {
+		m.mu.Unlock()
+		return errors.New("mirror has already been run")
+	}
+	m.ran = true
+	done := make(chan struct{})
+	m.done = done
+	m.mu.Unlock()
+	defer close(done)
+
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return nil
+		default:
+		}
+
+		result, err := m.Sync()
+		switch {
+		case err != nil && m.opts.OnError != nil:
+			m.opts.OnError(err)
+		case err == nil && m.opts.OnSync != nil:
+			m.opts.OnSync(result)
+		}
+
+		select {
+		case <-m.stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop stops Run, waiting for a sync in progress to finish.  It's safe to call more than once, and before Run, which
+// then returns without syncing.
+func (m *Mirror) Stop() {
+	m.once.Do(func() { close(m.stop) })
+
+	m.mu.Lock()
+	done := m.done
+	m.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// sync syncs the files at source into destination, then their sub-locations, whose paths relative to the mirror's
+// source begin with prefix.
+func (m *Mirror) sync(source, destination vfs.Location, prefix string, result *Result) error {
+	err := utils.Diff(source, destination, m.opts.DiffOptions, func(diff utils.DiffResult) error {
+		name := prefix + diff.Name
+		if !m.included(name) {
+			return nil
+		}
+		switch diff.Kind {
+		case utils.DiffOnlyInA, utils.DiffChanged:
+			target, err := destination.NewFile(diff.Name)
+			if err != nil {
+				return err
+			}
+			if err := diff.A.CopyToFile(target, m.opts.CopyOptions...); err != nil {
+				return fmt.Errorf("unable to copy %s: %s", diff.A, err.Error())
+			}
+			result.Copied = append(result.Copied, name)
+		case utils.DiffOnlyInB:
+			if !m.opts.Delete {
+				return nil
+			}
+			if err := diff.B.Delete(); err != nil {
+				return fmt.Errorf("unable to delete %s: %s", diff.B, err.Error())
+			}
+			result.Deleted = append(result.Deleted, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	names, err := subLocations(source, nil)
+	if err != nil {
+		return err
+	}
+	if m.opts.Delete {
+		// sub-locations only at the destination are synced from their empty counterparts at the source, deleting
+		// their files
+		if names, err = subLocations(destination, names); err != nil {
+			return err
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		if !m.included(prefix + name) {
+			continue
+		}
+		sourceSub, err := source.NewLocation(name)
+		if err != nil {
+			return err
+		}
+		destinationSub, err := destination.NewLocation(name)
+		if err != nil {
+			return err
+		}
+		if err := m.sync(sourceSub, destinationSub, prefix+name, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// included returns true if name passes the filter.
+func (m *Mirror) included(name string) bool {
+	return m.opts.Filter == nil || m.opts.Filter(name)
+}
+
+// subLocations adds the paths of the sub-locations found directly at location, relative to it and with a trailing
+// slash, to names, returning it.  Locations that can't list their sub-locations (mem.Location) add none.
+func subLocations(location vfs.Location, names map[string]bool) (map[string]bool, error) {
+	if names == nil {
+		names = map[string]bool{}
+	}
+	_, locations, err := utils.ListDir(location)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range locations {
+		names[strings.TrimPrefix(sub.Path(), location.Path())] = true
+	}
+	return names, nil
+}
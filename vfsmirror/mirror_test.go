@@ -0,0 +1,190 @@
+package vfsmirror_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/utils"
+	"github.com/c2fo/vfs/v5/vfsmirror"
+)
+
+type mirrorTestSuite struct {
+	suite.Suite
+	dir         string
+	source      vfs.Location
+	destination vfs.Location
+}
+
+func (ts *mirrorTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfsmirror_test")
+	ts.NoError(err)
+	ts.dir = dir
+	ts.source = ts.newLocation("source")
+	ts.destination = ts.newLocation("destination")
+}
+
+func (ts *mirrorTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *mirrorTestSuite) newLocation(name string) vfs.Location {
+	dir := filepath.Join(ts.dir, name)
+	ts.NoError(os.MkdirAll(dir, 0755))
+	location, err := _os.NewFileSystem().NewLocation("", utils.EnsureTrailingSlash(dir))
+	ts.NoError(err)
+	return location
+}
+
+func (ts *mirrorTestSuite) writeFile(location vfs.Location, name, contents string) {
+	file, err := location.NewFile(name)
+	ts.NoError(err)
+	_, err = file.Write([]byte(contents))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+}
+
+func (ts *mirrorTestSuite) readFile(location vfs.Location, name string) string {
+	file, err := location.NewFile(name)
+	ts.NoError(err)
+	data, err := ioutil.ReadAll(file)
+	ts.NoError(err)
+	ts.NoError(file.Close())
+	return string(data)
+}
+
+func (ts *mirrorTestSuite) exists(location vfs.Location, name string) bool {
+	file, err := location.NewFile(name)
+	ts.NoError(err)
+	exists, err := file.Exists()
+	ts.NoError(err)
+	return exists
+}
+
+func (ts *mirrorTestSuite) TestSync() {
+	ts.writeFile(ts.source, "a.txt", "hello")
+	ts.writeFile(ts.source, "sub/b.txt", "world")
+	ts.writeFile(ts.destination, "extra.txt", "extra")
+
+	mirror := vfsmirror.NewMirror(ts.source, ts.destination, vfsmirror.Options{})
+	result, err := mirror.Sync()
+	ts.NoError(err)
+	ts.Equal([]string{"a.txt", "sub/b.txt"}, result.Copied)
+	ts.Empty(result.Deleted)
+	ts.Equal("hello", ts.readFile(ts.destination, "a.txt"))
+	ts.Equal("world", ts.readFile(ts.destination, "sub/b.txt"))
+	ts.True(ts.exists(ts.destination, "extra.txt"), "deletes aren't propagated by default")
+
+	// only changed files are copied again
+	ts.writeFile(ts.source, "sub/b.txt", "changed")
+	result, err = mirror.Sync()
+	ts.NoError(err)
+	ts.Equal([]string{"sub/b.txt"}, result.Copied)
+	ts.Equal("changed", ts.readFile(ts.destination, "sub/b.txt"))
+
+	result, err = mirror.Sync()
+	ts.NoError(err)
+	ts.Empty(result.Copied)
+}
+
+func (ts *mirrorTestSuite) TestSync_Delete() {
+	ts.writeFile(ts.source, "a.txt", "hello")
+	ts.writeFile(ts.destination, "a.txt", "hello")
+	ts.writeFile(ts.destination, "extra.txt", "extra")
+	ts.writeFile(ts.destination, "sub/b.txt", "world")
+	ts.writeFile(ts.destination, "sub/deeper/c.txt", "deeper")
+
+	result, err := vfsmirror.NewMirror(ts.source, ts.destination, vfsmirror.Options{Delete: true}).Sync()
+	ts.NoError(err)
+	ts.Empty(result.Copied)
+	ts.Equal([]string{"extra.txt", "sub/b.txt", "sub/deeper/c.txt"}, result.Deleted)
+	ts.True(ts.exists(ts.destination, "a.txt"))
+	ts.False(ts.exists(ts.destination, "extra.txt"))
+	ts.False(ts.exists(ts.destination, "sub/deeper/c.txt"))
+}
+
+func (ts *mirrorTestSuite) TestSync_Filter() {
+	ts.writeFile(ts.source, "a.txt", "hello")
+	ts.writeFile(ts.source, "b.tmp", "temporary")
+	ts.writeFile(ts.source, "skip/c.txt", "skipped")
+	ts.writeFile(ts.destination, "d.tmp", "kept")
+
+	result, err := vfsmirror.NewMirror(ts.source, ts.destination, vfsmirror.Options{
+		Delete: true,
+		Filter: func(name string) bool { return !strings.HasSuffix(name, ".tmp") && name != "skip/" },
+	}).Sync()
+	ts.NoError(err)
+	ts.Equal([]string{"a.txt"}, result.Copied)
+	ts.Empty(result.Deleted, "files excluded by the filter aren't deleted")
+	ts.False(ts.exists(ts.destination, "b.tmp"))
+	ts.False(ts.exists(ts.destination, "skip/c.txt"))
+	ts.True(ts.exists(ts.destination, "d.tmp"))
+}
+
+func (ts *mirrorTestSuite) TestRun() {
+	synced := make(chan *vfsmirror.Result, 10)
+	mirror := vfsmirror.NewMirror(ts.source, ts.destination, vfsmirror.Options{
+		Interval: 10 * time.Millisecond,
+		OnSync:   func(result *vfsmirror.Result) { synced <- result },
+	})
+	ran := make(chan error)
+	go func() { ran <- mirror.Run() }()
+
+	<-synced
+	ts.writeFile(ts.source, "a.txt", "hello")
+	ts.Eventually(func() bool { return ts.exists(ts.destination, "a.txt") }, time.Second, 10*time.Millisecond)
+
+	mirror.Stop()
+	ts.NoError(<-ran)
+	mirror.Stop()
+
+	// a stopped mirror doesn't sync again
+	ts.writeFile(ts.source, "b.txt", "world")
+	time.Sleep(30 * time.Millisecond)
+	ts.False(ts.exists(ts.destination, "b.txt"))
+	ts.EqualError(mirror.Run(), "mirror has already been run")
+}
+
+func (ts *mirrorTestSuite) TestRun_StoppedFirst() {
+	ts.writeFile(ts.source, "a.txt", "hello")
+	mirror := vfsmirror.NewMirror(ts.source, ts.destination, vfsmirror.Options{})
+	mirror.Stop()
+	ts.NoError(mirror.Run())
+	ts.False(ts.exists(ts.destination, "a.txt"))
+}
+
+func (ts *mirrorTestSuite) TestRun_OnError() {
+	failed := make(chan error, 10)
+	mirror := vfsmirror.NewMirror(ts.source, failingLocation{ts.destination}, vfsmirror.Options{
+		Interval: 10 * time.Millisecond,
+		OnError:  func(err error) { failed <- err },
+	})
+	ts.writeFile(ts.source, "a.txt", "hello")
+	go func() { _ = mirror.Run() }()
+
+	// failed syncs are retried
+	ts.EqualError(<-failed, "list failed")
+	ts.EqualError(<-failed, "list failed")
+	mirror.Stop()
+}
+
+// failingLocation is a vfs.Location that can't be listed.
+type failingLocation struct {
+	vfs.Location
+}
+
+func (l failingLocation) List() ([]string, error) {
+	return nil, errors.New("list failed")
+}
+
+func TestMirror(t *testing.T) {
+	suite.Run(t, new(mirrorTestSuite))
+}
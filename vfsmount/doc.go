@@ -0,0 +1,23 @@
+/*
+vfsmount mounts a location as a local directory with FUSE, so programs that only understand paths can read and write
+any supported remote system.  It runs until interrupted, or until the directory is unmounted (ie: with umount or
+fusermount -u).  Complete URI (scheme:// authority/path) required except for local file system.
+See github.com/c2fo/vfs docs for authentication, and the vfsfuse package for how files and directories behave.
+
+
+Usage
+
+  vfsmount [-ro] [-allow-other] [-temp-dir <dir>] <uri> <mountpoint>
+  -ro            mounts the location read-only
+  -allow-other   allows other users to access the mount
+  -temp-dir      directory for local copies of files opened for writing
+  -help          prints help message
+
+Examples
+
+Mount an S3 prefix at /mnt/data
+  vfsmount s3://mybucket/data/ /mnt/data
+Mount an SFTP directory read-only
+  vfsmount -ro sftp://user@host.com:22/exports/ /mnt/exports
+*/
+package main
@@ -0,0 +1,122 @@
+// +build linux darwin freebsd
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/fatih/color"
+
+	"github.com/c2fo/vfs/v5/utils"
+	"github.com/c2fo/vfs/v5/vfsfuse"
+	"github.com/c2fo/vfs/v5/vfssimple"
+)
+
+const usageTemplate = `
+%[1]s mounts a location as a local directory with FUSE, until interrupted or unmounted.
+Complete URI (scheme://authority/path) required except for local filesystem.
+See github.com/c2fo/vfs docs for authentication.
+
+Usage:  %[1]s [-ro] [-allow-other] [-temp-dir <dir>] <uri> <mountpoint>
+
+    ie,        %[1]s s3://mybucket/data/ /mnt/data
+    read-only  %[1]s -ro sftp://user@host.com:22/exports/ /mnt/exports
+
+    -ro
+        mounts the location read-only
+    -allow-other
+        allows other users to access the mount
+    -temp-dir
+        directory for local copies of files opened for writing
+    -help
+        prints this message
+
+`
+
+func main() {
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stdout, usageTemplate, os.Args[0])
+	}
+	var help bool
+	options := vfsfuse.Options{}
+	flag.BoolVar(&help, "help", false, "prints this message")
+	flag.BoolVar(&options.ReadOnly, "ro", false, "mounts the location read-only")
+	flag.BoolVar(&options.AllowOther, "allow-other", false, "allows other users to access the mount")
+	flag.StringVar(&options.TempDir, "temp-dir", "", "directory for local copies of files opened for writing")
+	flag.Parse()
+
+	if help {
+		flag.Usage()
+		os.Exit(0)
+	}
+
+	if len(flag.Args()) != 2 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	locationURI, err := normalizeArgs(flag.Arg(0))
+	if err != nil {
+		failMessage(err)
+	}
+	mount(utils.EnsureTrailingSlash(locationURI), flag.Arg(1), options)
+}
+
+func mount(locationURI, mountpoint string, options vfsfuse.Options) {
+	green := color.New(color.FgHiGreen).Add(color.Bold)
+	white := color.New(color.FgHiWhite).Add(color.Bold)
+	blue := color.New(color.FgHiBlue).Add(color.Bold)
+
+	location, err := vfssimple.NewLocation(locationURI)
+	if err != nil {
+		failMessage(err)
+	}
+	mounted, err := vfsfuse.Mount(location, mountpoint, options)
+	if err != nil {
+		failMessage(err)
+	}
+	fmt.Print(white.Sprint("Mounted ") + blue.Sprint(locationURI) + white.Sprint(" at ") + blue.Sprint(mountpoint) +
+		white.Sprint(", interrupt to unmount\n"))
+
+	// unmount on interrupt, or wait for it to be unmounted some other way
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		if err := mounted.Unmount(); err != nil {
+			failMessage(err)
+		}
+	}()
+	if err := mounted.Wait(); err != nil {
+		failMessage(err)
+	}
+
+	fmt.Print(green.Sprint("unmounted\n\n"))
+}
+
+func normalizeArgs(str string) (string, error) {
+	u, err := url.Parse(str)
+	if err != nil {
+		return "", err
+	}
+	if u.IsAbs() {
+		return str, nil
+	}
+	absPath, err := filepath.Abs(str)
+	if err != nil {
+		return "", err
+	}
+	return "file://" + absPath, nil
+}
+
+func failMessage(err error) {
+	red := color.New(color.FgHiRed).Add(color.Bold)
+	fmt.Printf(red.Sprint("failed\n\n")+"\n%s\n\n", err.Error())
+	os.Exit(1)
+}
@@ -0,0 +1,13 @@
+// +build !linux,!darwin,!freebsd
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Println("vfsmount requires FUSE, which isn't supported on this platform")
+	os.Exit(1)
+}
@@ -0,0 +1,20 @@
+/*
+Package vfspath joins, splits and validates the paths of vfs files and locations, and the object keys they map to on
+s3 and gs, without the surprises of path.Join.
+
+path.Join cleans its result: it drops the trailing slash that marks a location path, resolves ".." (so a joined key can
+escape the prefix it was meant to be under) and collapses doubled slashes.  The functions here keep location paths
+distinguishable from file paths and reject ".." and "." rather than resolving them:
+
+  key, err := vfspath.Join("/exports/", customerID, "2020-01-01.csv")   // "/exports/1234/2020-01-01.csv"
+  dir, err := vfspath.Join("/exports/", customerID+"/")                 // "/exports/1234/"
+  _, err = vfspath.Join("/exports/", "../secrets.txt")                  // ErrDotSegment
+
+Rel and IsWithin compare paths a segment at a time, so "/data/foobar.txt" isn't within the location "/data/foo".
+
+Validation
+
+Validate checks a path against the rules of a backend, identified by its scheme, ie: that an s3 or gs object key is at
+most 1024 bytes of valid UTF-8.
+*/
+package vfspath
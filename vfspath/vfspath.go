@@ -0,0 +1,118 @@
+package vfspath
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrDotSegment is returned for a path containing a "." or ".." segment, which backends with flat keys (s3 and gs)
+	// don't resolve.
+	ErrDotSegment = errors.New(`path may not contain "." or ".." segments`)
+	// ErrNotWithin is returned by Rel for a path that isn't within the base location.
+	ErrNotWithin = errors.New("path is not within the location")
+)
+
+// maxKeyLength is the maximum length in bytes of an s3 or gs object key.
+const maxKeyLength = 1024
+
+// Join joins elems into a single path, separating them with a slash.  Unlike path.Join, empty segments (doubled
+// slashes) are dropped but the result is otherwise left as given: it has a leading slash if the first element does and
+// a trailing slash, marking it as a location path, if the last element does.  ErrDotSegment is returned if any element
+// contains a "." or ".." segment.
+func Join(elems ...string) (string, error) {
+	var segments []string
+	for _, elem := range elems {
+		for _, segment := range strings.Split(elem, "/") {
+			if segment == "" {
+				continue
+			}
+			if segment == "." || segment == ".." {
+				return "", ErrDotSegment
+			}
+			segments = append(segments, segment)
+		}
+	}
+
+	joined := strings.Join(segments, "/")
+	if len(elems) > 0 && strings.HasPrefix(elems[0], "/") {
+		joined = "/" + joined
+	}
+	if len(elems) > 0 && strings.HasSuffix(elems[len(elems)-1], "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	return joined, nil
+}
+
+// Split splits p into its location path, with a trailing slash, and file name.  A location path (with a trailing
+// slash) is returned as is, with an empty name.  A path without a slash has an empty location path.
+func Split(p string) (dir, name string) {
+	i := strings.LastIndex(p, "/")
+	return p[:i+1], p[i+1:]
+}
+
+// Rel returns p relative to the location path base, ie: Rel("/data/", "/data/2020/report.csv") is "2020/report.csv",
+// and "" for base itself.  base is treated as a location path whether or not it has a trailing slash, so ErrNotWithin
+// is returned for Rel("/data/foo", "/data/foobar.txt"), as it is for any p not within base.
+func Rel(base, p string) (string, error) {
+	if !IsWithin(base, p) {
+		return "", ErrNotWithin
+	}
+	base = ensureTrailingSlash(base)
+	if ensureTrailingSlash(p) == base {
+		return "", nil
+	}
+	return strings.TrimPrefix(p, base), nil
+}
+
+// IsWithin returns true if p is within, or is, the location path base.  base is treated as a location path whether or
+// not it has a trailing slash, so "/data/foobar.txt" isn't within "/data/foo".
+func IsWithin(base, p string) bool {
+	base = ensureTrailingSlash(base)
+	return strings.HasPrefix(ensureTrailingSlash(p), base)
+}
+
+// Validate checks p against the rules for paths of the backend with scheme, returning an error describing the first
+// rule it breaks:
+//
+//   s3, gs: at most 1024 bytes of valid UTF-8 once the leading slash is removed; gs also disallows carriage returns
+//           and line feeds
+//   file:   no NUL bytes
+//
+// Paths for every scheme may not contain "." or ".." segments.
+func Validate(scheme, p string) error {
+	for _, segment := range strings.Split(p, "/") {
+		if segment == "." || segment == ".." {
+			return ErrDotSegment
+		}
+	}
+
+	switch scheme {
+	case "s3", "gs":
+		key := strings.TrimPrefix(p, "/")
+		if len(key) > maxKeyLength {
+			return fmt.Errorf("%s key may not exceed %d bytes, got %d", scheme, maxKeyLength, len(key))
+		}
+		if !utf8.ValidString(key) {
+			return fmt.Errorf("%s key must be valid UTF-8", scheme)
+		}
+		if scheme == "gs" && strings.ContainsAny(key, "\r\n") {
+			return errors.New("gs key may not contain carriage return or line feed characters")
+		}
+	case "file":
+		if strings.ContainsRune(p, 0) {
+			return errors.New("file path may not contain NUL bytes")
+		}
+	}
+	return nil
+}
+
+// ensureTrailingSlash returns p with a trailing slash.
+func ensureTrailingSlash(p string) string {
+	if strings.HasSuffix(p, "/") {
+		return p
+	}
+	return p + "/"
+}
@@ -0,0 +1,98 @@
+package vfspath_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5/vfspath"
+)
+
+type vfspathTest struct {
+	suite.Suite
+}
+
+func (s *vfspathTest) TestJoin() {
+	tests := []struct {
+		elems    []string
+		expected string
+	}{
+		{[]string{"/exports/", "1234", "report.csv"}, "/exports/1234/report.csv"},
+		{[]string{"/exports", "1234/"}, "/exports/1234/"},
+		{[]string{"exports/", "/1234//", "report.csv"}, "exports/1234/report.csv"},
+		{[]string{"exports", ""}, "exports"},
+		{[]string{"/"}, "/"},
+		{[]string{"/", "/"}, "/"},
+		{[]string{}, ""},
+	}
+	for _, test := range tests {
+		joined, err := vfspath.Join(test.elems...)
+		s.NoError(err, test.elems)
+		s.Equal(test.expected, joined, test.elems)
+	}
+
+	_, err := vfspath.Join("/exports/", "../secrets.txt")
+	s.Equal(vfspath.ErrDotSegment, err)
+	_, err = vfspath.Join("/exports/", "./report.csv")
+	s.Equal(vfspath.ErrDotSegment, err)
+}
+
+func (s *vfspathTest) TestSplit() {
+	dir, name := vfspath.Split("/exports/1234/report.csv")
+	s.Equal("/exports/1234/", dir)
+	s.Equal("report.csv", name)
+
+	dir, name = vfspath.Split("/exports/1234/")
+	s.Equal("/exports/1234/", dir)
+	s.Equal("", name)
+
+	dir, name = vfspath.Split("report.csv")
+	s.Equal("", dir)
+	s.Equal("report.csv", name)
+}
+
+func (s *vfspathTest) TestRel() {
+	rel, err := vfspath.Rel("/data/", "/data/2020/report.csv")
+	s.NoError(err)
+	s.Equal("2020/report.csv", rel)
+
+	rel, err = vfspath.Rel("/data", "/data/2020/")
+	s.NoError(err)
+	s.Equal("2020/", rel, "base without a trailing slash is a location")
+
+	rel, err = vfspath.Rel("/data/", "/data")
+	s.NoError(err)
+	s.Equal("", rel)
+
+	_, err = vfspath.Rel("/data/foo", "/data/foobar.txt")
+	s.Equal(vfspath.ErrNotWithin, err, "prefixes are compared a segment at a time")
+
+	_, err = vfspath.Rel("/data/", "/other/report.csv")
+	s.Equal(vfspath.ErrNotWithin, err)
+}
+
+func (s *vfspathTest) TestIsWithin() {
+	s.True(vfspath.IsWithin("/data/", "/data/report.csv"))
+	s.True(vfspath.IsWithin("/data/", "/data/"))
+	s.True(vfspath.IsWithin("/", "/data/report.csv"))
+	s.False(vfspath.IsWithin("/data/foo", "/data/foobar.txt"))
+	s.False(vfspath.IsWithin("/data/2020/", "/data/"))
+}
+
+func (s *vfspathTest) TestValidate() {
+	s.NoError(vfspath.Validate("s3", "/path/to/report.csv"))
+	s.NoError(vfspath.Validate("s3", "/"+strings.Repeat("a", 1024)), "leading slash isn't part of the key")
+	s.EqualError(vfspath.Validate("s3", "/"+strings.Repeat("a", 1025)), "s3 key may not exceed 1024 bytes, got 1025")
+	s.EqualError(vfspath.Validate("gs", "/bad\xff.csv"), "gs key must be valid UTF-8")
+	s.EqualError(vfspath.Validate("gs", "/line\nbreak.csv"),
+		"gs key may not contain carriage return or line feed characters")
+	s.NoError(vfspath.Validate("s3", "/line\nbreak.csv"))
+	s.EqualError(vfspath.Validate("file", "/nul\x00.csv"), "file path may not contain NUL bytes")
+	s.NoError(vfspath.Validate("mem", "/"+strings.Repeat("a", 2000)))
+	s.Equal(vfspath.ErrDotSegment, vfspath.Validate("mem", "/data/../report.csv"))
+}
+
+func TestVfspath(t *testing.T) {
+	suite.Run(t, new(vfspathTest))
+}
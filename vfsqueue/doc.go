@@ -0,0 +1,45 @@
+/*
+Package vfsqueue treats a vfs.Location as a work queue, so workers on any number of hosts can share the files dropped
+there without electing a leader: each file is claimed by exactly one worker, by moving it into an in-progress location,
+and moved on to a done or failed location once it's processed.
+
+Usage
+
+Create a queue over the location producers write to, with how long a worker has to process each file it claims:
+
+  location, err := s3.NewFileSystem().NewLocation("mybucket", "/inbox/")
+  queue, err := vfsqueue.NewQueue(location, 10*time.Minute)
+
+  for {
+      item, err := queue.Claim()
+      if err == vfsqueue.ErrEmpty {
+          break
+      }
+      if err := process(item.File); err != nil {
+          _, err = item.Fail()
+          continue
+      }
+      _, err = item.Complete()
+  }
+
+Layout
+
+Claimed files are moved beneath the queue location, to "in-progress/", named by the UTC time their claim expires and
+their name in the queue, ie:
+
+  in-progress/20200102T150405.000000000Z~report.csv
+
+Completed files are moved to "done/" and failed files to "failed/", under their name in the queue.  Files are claimed
+in name order, so producers wanting first in, first out should name files by the time they're written.
+
+Expiry
+
+A claim that's neither completed, failed, released nor extended before it expires, ie: because its worker died, is
+requeued by the next Claim, or by Requeue, moving the file back to the queue for another worker.  A worker that then
+tries to complete it gets ErrClaimLost.
+
+Where the backend supports vfs.Lock (s3 and os), each move of a file is made holding a lock named for it in "locks/",
+so of the workers racing to claim or requeue the same file, only one moves it.  Other backends rely on the move itself
+failing for all but one of them, which is only the case where moves are atomic, ie: a single process using mem.
+*/
+package vfsqueue
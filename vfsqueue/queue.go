@@ -0,0 +1,271 @@
+package vfsqueue
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+const (
+	// timeFormat is the time layout of the time a claim expires, which begins the name of an in-progress file.
+	timeFormat = "20060102T150405.000000000Z"
+	// separator separates the expiry time of an in-progress file from its name in the queue.
+	separator = "~"
+	// lockTTL is how long the lock on a file is taken for while it's moved.
+	lockTTL = time.Minute
+)
+
+// The sub-locations of the queue location that files are moved to.
+const (
+	inProgressPath = "in-progress/"
+	donePath       = "done/"
+	failedPath     = "failed/"
+	locksPath      = "locks/"
+)
+
+var (
+	// ErrEmpty is returned by Claim when there are no files in the queue left to claim.
+	ErrEmpty = errors.New("queue is empty")
+	// ErrClaimLost is returned by an Item's methods when its claim expired and the file was requeued.
+	ErrClaimLost = errors.New("claim expired and the file was requeued")
+	// errBusy is returned by move when another worker holds the lock on the file.
+	errBusy = errors.New("file is being moved by another worker")
+	// errFinished is returned by an Item's methods once it has been completed, failed or released.
+	errFinished = errors.New("item was already completed, failed or released")
+)
+
+// Queue is a work queue of the files at a location.
+type Queue struct {
+	location   vfs.Location
+	inProgress vfs.Location
+	done       vfs.Location
+	failed     vfs.Location
+	locks      vfs.Location
+	ttl        time.Duration
+}
+
+// NewQueue returns a Queue of the files at location, whose claims expire after ttl.
+func NewQueue(location vfs.Location, ttl time.Duration) (*Queue, error) {
+	if ttl <= 0 {
+		return nil, errors.New("positive ttl is required")
+	}
+	q := &Queue{location: location, ttl: ttl}
+	for path, sub := range map[string]*vfs.Location{
+		inProgressPath: &q.inProgress,
+		donePath:       &q.done,
+		failedPath:     &q.failed,
+		locksPath:      &q.locks,
+	} {
+		var err error
+		if *sub, err = location.NewLocation(path); err != nil {
+			return nil, err
+		}
+	}
+	return q, nil
+}
+
+// InProgress returns the location claimed files are moved to.
+func (q *Queue) InProgress() vfs.Location {
+	return q.inProgress
+}
+
+// Done returns the location completed files are moved to.
+func (q *Queue) Done() vfs.Location {
+	return q.done
+}
+
+// Failed returns the location failed files are moved to.
+func (q *Queue) Failed() vfs.Location {
+	return q.failed
+}
+
+// Claim requeues any expired claims, then claims the first file in the queue, by name, that no other worker claims
+// first, moving it to the in-progress location.  ErrEmpty is returned if there's no file to claim.
+func (q *Queue) Claim() (*Item, error) {
+	if _, err := q.Requeue(); err != nil {
+		return nil, err
+	}
+
+	names, err := q.location.List()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		file, err := q.location.NewFile(name)
+		if err != nil {
+			return nil, err
+		}
+		expires := time.Now().Add(q.ttl).UTC()
+		claimed, err := q.move(name, file, q.inProgress, inProgressName(name, expires))
+		switch err {
+		case nil:
+			return &Item{Name: name, File: claimed, Expires: expires, queue: q}, nil
+		case errBusy, ErrClaimLost:
+			// claimed by another worker
+			continue
+		}
+		return nil, err
+	}
+	return nil, ErrEmpty
+}
+
+// Requeue moves the files whose claims have expired back to the queue, returning how many were.
+func (q *Queue) Requeue() (int, error) {
+	names, err := q.inProgress.List()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	now := time.Now()
+	for _, inProgress := range names {
+		name, expires, ok := parseInProgressName(inProgress)
+		if !ok || now.Before(expires) {
+			continue
+		}
+		file, err := q.inProgress.NewFile(inProgress)
+		if err != nil {
+			return count, err
+		}
+		switch _, err := q.move(name, file, q.location, name); err {
+		case nil:
+			count++
+		case errBusy, ErrClaimLost:
+			// requeued, or completed just in time, by another worker
+		default:
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// move moves file, named name in the queue, to location as target, holding its lock where the backend supports
+// locking.  ErrClaimLost is returned if the file no longer exists, and errBusy if another worker holds its lock.
+func (q *Queue) move(name string, file vfs.File, location vfs.Location, target string) (vfs.File, error) {
+	lockFile, err := q.locks.NewFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := lockFile.(vfs.Locker); ok {
+		lease, err := vfs.Lock(lockFile, lockTTL)
+		if err == vfs.ErrLocked {
+			return nil, errBusy
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = lease.Release() }()
+	}
+
+	exists, err := file.Exists()
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrClaimLost
+	}
+	moved, err := location.NewFile(target)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.MoveToFile(moved); err != nil {
+		if exists, existsErr := file.Exists(); existsErr == nil && !exists {
+			// moved by another worker on a backend without locking
+			return nil, ErrClaimLost
+		}
+		return nil, err
+	}
+	return moved, nil
+}
+
+// Item is a file claimed from a Queue.
+type Item struct {
+	// Name is the file's name in the queue.
+	Name string
+	// File is the claimed file, in the in-progress location.
+	File vfs.File
+	// Expires is when the claim expires, after which the file is requeued unless it's completed, failed, released or
+	// extended first.
+	Expires  time.Time
+	queue    *Queue
+	finished bool
+}
+
+// Complete moves the file to the done location, returning it there.
+func (i *Item) Complete() (vfs.File, error) {
+	return i.finish(i.queue.done)
+}
+
+// Fail moves the file to the failed location, returning it there, ie: after it couldn't be processed.
+func (i *Item) Fail() (vfs.File, error) {
+	return i.finish(i.queue.failed)
+}
+
+// Release gives up the claim, moving the file back to the queue for another worker to claim.
+func (i *Item) Release() error {
+	_, err := i.finish(i.queue.location)
+	return err
+}
+
+// Extend extends the claim to expire ttl from now, ie: for files that take longer than the queue's ttl to process.
+func (i *Item) Extend(ttl time.Duration) error {
+	if ttl <= 0 {
+		return errors.New("positive ttl is required")
+	}
+	if i.finished {
+		return errFinished
+	}
+	expires := time.Now().Add(ttl).UTC()
+	file, err := i.queue.move(i.Name, i.File, i.queue.inProgress, inProgressName(i.Name, expires))
+	if err != nil {
+		return claimErr(err)
+	}
+	i.File = file
+	i.Expires = expires
+	return nil
+}
+
+// finish moves the file out of the in-progress location to location.
+func (i *Item) finish(location vfs.Location) (vfs.File, error) {
+	if i.finished {
+		return nil, errFinished
+	}
+	file, err := i.queue.move(i.Name, i.File, location, i.Name)
+	if err != nil {
+		return nil, claimErr(err)
+	}
+	i.File = file
+	i.finished = true
+	return file, nil
+}
+
+// claimErr returns ErrClaimLost for errBusy, since for a claimed file it means another worker is requeuing it.
+func claimErr(err error) error {
+	if err == errBusy {
+		return ErrClaimLost
+	}
+	return err
+}
+
+// inProgressName returns the name of a file named name in the queue when it's claimed until expires.
+func inProgressName(name string, expires time.Time) string {
+	return expires.UTC().Format(timeFormat) + separator + name
+}
+
+// parseInProgressName returns the name in the queue and the claim expiry of an in-progress file, or false if name
+// isn't one.
+func parseInProgressName(inProgress string) (string, time.Time, bool) {
+	parts := strings.SplitN(inProgress, separator, 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", time.Time{}, false
+	}
+	expires, err := time.Parse(timeFormat, parts[0])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return parts[1], expires, true
+}
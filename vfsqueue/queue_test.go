@@ -0,0 +1,178 @@
+package vfsqueue_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/vfsqueue"
+)
+
+type queueTestSuite struct {
+	suite.Suite
+	dir      string
+	location vfs.Location
+}
+
+func (ts *queueTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfsqueue_test")
+	ts.NoError(err)
+	ts.dir = dir
+	ts.location, err = _os.NewFileSystem().NewLocation("", dir+"/")
+	ts.NoError(err)
+}
+
+func (ts *queueTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+// enqueue writes files of the given names to location.
+func (ts *queueTestSuite) enqueue(location vfs.Location, names ...string) {
+	for _, name := range names {
+		file, err := location.NewFile(name)
+		ts.NoError(err)
+		_, err = file.Write([]byte(name))
+		ts.NoError(err)
+		ts.NoError(file.Close())
+	}
+}
+
+// list returns the names of the files at location.
+func (ts *queueTestSuite) list(location vfs.Location) []string {
+	names, err := location.List()
+	ts.NoError(err)
+	return names
+}
+
+func (ts *queueTestSuite) TestClaimAndComplete() {
+	queue, err := vfsqueue.NewQueue(ts.location, time.Minute)
+	ts.NoError(err)
+	ts.enqueue(ts.location, "2.txt", "1.txt")
+
+	item, err := queue.Claim()
+	ts.NoError(err)
+	ts.Equal("1.txt", item.Name, "files are claimed in name order")
+	ts.Equal(queue.InProgress().Path(), item.File.Location().Path())
+	ts.True(strings.HasSuffix(item.File.Name(), "~1.txt"))
+	ts.Equal([]string{"2.txt"}, ts.list(ts.location))
+
+	done, err := item.Complete()
+	ts.NoError(err)
+	ts.Equal(queue.Done().Path()+"1.txt", done.Path())
+	ts.Empty(ts.list(queue.InProgress()))
+	_, err = item.Complete()
+	ts.Error(err, "items can only be finished once")
+
+	item, err = queue.Claim()
+	ts.NoError(err)
+	ts.Equal("2.txt", item.Name)
+	failed, err := item.Fail()
+	ts.NoError(err)
+	ts.Equal(queue.Failed().Path()+"2.txt", failed.Path())
+
+	_, err = queue.Claim()
+	ts.Equal(vfsqueue.ErrEmpty, err)
+	ts.Empty(ts.list(queue.InProgress()))
+}
+
+func (ts *queueTestSuite) TestRelease() {
+	queue, err := vfsqueue.NewQueue(ts.location, time.Minute)
+	ts.NoError(err)
+	ts.enqueue(ts.location, "1.txt")
+
+	item, err := queue.Claim()
+	ts.NoError(err)
+	ts.NoError(item.Release())
+	ts.Equal([]string{"1.txt"}, ts.list(ts.location))
+
+	item, err = queue.Claim()
+	ts.NoError(err)
+	ts.Equal("1.txt", item.Name)
+}
+
+func (ts *queueTestSuite) TestExpiry() {
+	queue, err := vfsqueue.NewQueue(ts.location, time.Millisecond)
+	ts.NoError(err)
+	ts.enqueue(ts.location, "1.txt")
+
+	item, err := queue.Claim()
+	ts.NoError(err)
+	time.Sleep(5 * time.Millisecond)
+
+	count, err := queue.Requeue()
+	ts.NoError(err)
+	ts.Equal(1, count, "expired claims are requeued")
+	ts.Equal([]string{"1.txt"}, ts.list(ts.location))
+
+	again, err := queue.Claim()
+	ts.NoError(err)
+	ts.NoError(again.Extend(time.Minute))
+	_, err = item.Complete()
+	ts.Equal(vfsqueue.ErrClaimLost, err, "the first worker's claim was lost")
+
+	time.Sleep(5 * time.Millisecond)
+	count, err = queue.Requeue()
+	ts.NoError(err)
+	ts.Zero(count, "extended claims aren't requeued")
+	_, err = again.Complete()
+	ts.NoError(err)
+}
+
+func (ts *queueTestSuite) TestConcurrentClaims() {
+	queue, err := vfsqueue.NewQueue(ts.location, time.Minute)
+	ts.NoError(err)
+	ts.enqueue(ts.location, "1.txt", "2.txt", "3.txt", "4.txt", "5.txt")
+
+	claimed := make(chan string, 10)
+	errs := make(chan error, 10)
+	for worker := 0; worker < 4; worker++ {
+		go func() {
+			for {
+				item, err := queue.Claim()
+				if err != nil {
+					errs <- err
+					return
+				}
+				claimed <- item.Name
+			}
+		}()
+	}
+
+	for worker := 0; worker < 4; worker++ {
+		ts.Equal(vfsqueue.ErrEmpty, <-errs)
+	}
+	close(claimed)
+	var names []string
+	for name := range claimed {
+		names = append(names, name)
+	}
+	ts.ElementsMatch([]string{"1.txt", "2.txt", "3.txt", "4.txt", "5.txt"}, names, "each file is claimed once")
+}
+
+func (ts *queueTestSuite) TestWithoutLocking() {
+	location, err := mem.NewFileSystem().NewLocation("bucket", "/inbox/")
+	ts.NoError(err)
+	queue, err := vfsqueue.NewQueue(location, time.Minute)
+	ts.NoError(err)
+	ts.enqueue(location, "1.txt")
+
+	item, err := queue.Claim()
+	ts.NoError(err)
+	_, err = item.Complete()
+	ts.NoError(err)
+	ts.Equal([]string{"1.txt"}, ts.list(queue.Done()))
+
+	_, err = vfsqueue.NewQueue(location, 0)
+	ts.EqualError(err, "positive ttl is required")
+}
+
+func TestQueue(t *testing.T) {
+	suite.Run(t, new(queueTestSuite))
+}
@@ -0,0 +1,25 @@
+/*
+Package vfsquota wraps any vfs.FileSystem to cap the bytes stored beneath a location, ie: a tenant's prefix behind an
+upload endpoint.
+
+Usage
+
+Wrap a file system with the location to enforce the quota on and the quota in bytes, seed it with the bytes already
+stored there if they're to count, and use it in its place:
+
+  tenant, err := s3.NewFileSystem().NewLocation("uploads", "/tenants/1234/")
+  fs := vfsquota.NewFileSystem(s3.NewFileSystem(), tenant, 10<<30)
+  fs.SetUsed(storedBytes)
+  file, err := fs.NewFile("uploads", "/tenants/1234/file.txt")
+  _, err = file.Write(data)   // vfs.ErrQuotaExceeded once 10GB is stored
+
+Accounting
+
+Bytes written to files beneath the location, and the size of files copied or moved there from elsewhere, count
+against the quota.  A write or copy that would exceed it fails with vfs.ErrQuotaExceeded, writing nothing, so a file
+being uploaded when the quota runs out is left with the data written before it did.  Deleting a file beneath the
+location, or moving it elsewhere, frees its size.  Overwriting a file, by writing to it or copying or moving another
+onto it with CopyToFile or MoveToFile, frees the size of the file it replaced; CopyToLocation and MoveToLocation onto
+an existing file don't.  Changes made other than through the FileSystem aren't tracked.
+*/
+package vfsquota
@@ -0,0 +1,147 @@
+package vfsquota
+
+import (
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/vfswrap"
+)
+
+// File is a vfs.File of a FileSystem, counting the bytes written to it against the quota if it's beneath the location
+// the quota is enforced on.
+type File struct {
+	*vfswrap.File
+	fileSystem *FileSystem
+	metered    bool
+	// writing is set by the first write since the file was last closed, which replaces it.
+	writing bool
+}
+
+// Close implements io.Closer.
+func (f *File) Close() error {
+	f.writing = false
+	return f.File.Close()
+}
+
+// Write implements io.Writer.  If writing p would exceed the quota nothing is written and vfs.ErrQuotaExceeded is
+// returned.  The first write since the file was closed frees the size of the file it replaces, if any.
+func (f *File) Write(p []byte) (int, error) {
+	if !f.metered {
+		return f.File.Write(p)
+	}
+	var replaced int64
+	if !f.writing {
+		var err error
+		if replaced, err = existingSize(f.File); err != nil {
+			return 0, err
+		}
+	}
+	if err := f.fileSystem.reserve(int64(len(p)) - replaced); err != nil {
+		return 0, err
+	}
+	f.writing = true
+	n, err := f.File.Write(p)
+	if n < len(p) {
+		f.fileSystem.release(int64(len(p) - n))
+	}
+	return n, err
+}
+
+// CopyToLocation implements vfs.File.
+func (f *File) CopyToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	var file vfs.File
+	err := f.transfer(location, nil, false, func() error {
+		var err error
+		file, err = f.File.CopyToLocation(location, opts...)
+		return err
+	})
+	return file, err
+}
+
+// CopyToFile implements vfs.File.
+func (f *File) CopyToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	return f.transfer(file.Location(), file, false, func() error {
+		return f.File.CopyToFile(file, opts...)
+	})
+}
+
+// MoveToLocation implements vfs.File.
+func (f *File) MoveToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	var file vfs.File
+	err := f.transfer(location, nil, true, func() error {
+		var err error
+		file, err = f.File.MoveToLocation(location, opts...)
+		return err
+	})
+	return file, err
+}
+
+// MoveToFile implements vfs.File.
+func (f *File) MoveToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	return f.transfer(file.Location(), file, true, func() error {
+		return f.File.MoveToFile(file, opts...)
+	})
+}
+
+// Delete implements vfs.File, freeing the file's size if it's beneath the location the quota is enforced on.
+func (f *File) Delete() error {
+	if !f.metered {
+		return f.File.Delete()
+	}
+	size, sizeErr := f.File.Size()
+	if err := f.File.Delete(); err != nil {
+		return err
+	}
+	if sizeErr == nil {
+		f.fileSystem.release(int64(size))
+	}
+	return nil
+}
+
+// transfer runs op, a copy or move of the file to target, counting the file's size against the quota if it's copied or
+// moved there from elsewhere, or freeing it if it's moved from there to elsewhere.  If the file is copied or moved onto
+// targetFile, an existing file there, the size of the file it replaces is freed.
+func (f *File) transfer(target vfs.Location, targetFile vfs.File, move bool, op func() error) error {
+	into := f.fileSystem.contains(target)
+	var replaced int64
+	if into && targetFile != nil {
+		var err error
+		if replaced, err = existingSize(targetFile); err != nil {
+			return err
+		}
+	}
+	if into == (move && f.metered) {
+		if err := op(); err != nil {
+			return err
+		}
+		f.fileSystem.release(replaced)
+		return nil
+	}
+	size, err := f.File.Size()
+	if err != nil {
+		return err
+	}
+	if !into {
+		if err := op(); err != nil {
+			return err
+		}
+		f.fileSystem.release(int64(size))
+		return nil
+	}
+	if err := f.fileSystem.reserve(int64(size) - replaced); err != nil {
+		return err
+	}
+	if err := op(); err != nil {
+		f.fileSystem.release(int64(size) - replaced)
+		return err
+	}
+	return nil
+}
+
+// existingSize returns the size of file, or 0 if it doesn't exist.
+func existingSize(file vfs.File) (int64, error) {
+	exists, err := file.Exists()
+	if err != nil || !exists {
+		return 0, err
+	}
+	size, err := file.Size()
+	return int64(size), err
+}
@@ -0,0 +1,99 @@
+package vfsquota
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/vfswrap"
+)
+
+// FileSystem is a vfs.FileSystem which caps the bytes stored beneath a location of a wrapped file system.
+type FileSystem struct {
+	*vfswrap.FileSystem
+	location vfs.Location
+	quota    int64
+	mu       sync.Mutex
+	used     int64
+}
+
+// NewFileSystem returns a FileSystem which caps the bytes stored beneath location, a location of fs, at quota.
+func NewFileSystem(fs vfs.FileSystem, location vfs.Location, quota int64) *FileSystem {
+	wrapper := &FileSystem{location: location, quota: quota}
+	wrapper.FileSystem = vfswrap.NewFileSystem(fs, wrapper)
+	return wrapper
+}
+
+// Quota returns the most bytes that can be stored beneath the location.
+func (fs *FileSystem) Quota() int64 {
+	return fs.quota
+}
+
+// Used returns the bytes stored beneath the location.
+func (fs *FileSystem) Used() int64 {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.used
+}
+
+// Remaining returns the bytes that can still be stored beneath the location.
+func (fs *FileSystem) Remaining() int64 {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.used >= fs.quota {
+		return 0
+	}
+	return fs.quota - fs.used
+}
+
+// SetUsed sets the bytes stored beneath the location, ie: to count those stored before the FileSystem was created.
+func (fs *FileSystem) SetUsed(used int64) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.used = used
+}
+
+// Name implements vfs.FileSystem.
+func (fs *FileSystem) Name() string {
+	return "Quota " + fs.Wrapped().Name()
+}
+
+// WrapFile implements vfswrap.Wrapper.
+func (fs *FileSystem) WrapFile(file vfs.File) vfs.File {
+	return &File{File: vfswrap.NewFile(file, fs), fileSystem: fs, metered: fs.contains(file.Location())}
+}
+
+// WrapLocation implements vfswrap.Wrapper.
+func (fs *FileSystem) WrapLocation(location vfs.Location) vfs.Location {
+	return &Location{Location: vfswrap.NewLocation(location, fs)}
+}
+
+// contains returns true if location is, or is beneath, the location the quota is enforced on.
+func (fs *FileSystem) contains(location vfs.Location) bool {
+	return strings.HasPrefix(location.URI(), fs.location.URI())
+}
+
+// reserve counts n more bytes as stored, or returns vfs.ErrQuotaExceeded if that would exceed the quota.  n is negative
+// when more is freed than stored, ie: by overwriting a file with a smaller one.
+func (fs *FileSystem) reserve(n int64) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.used+n > fs.quota {
+		return vfs.ErrQuotaExceeded
+	}
+	fs.used += n
+	if fs.used < 0 {
+		fs.used = 0
+	}
+	return nil
+}
+
+// release counts n fewer bytes as stored.
+func (fs *FileSystem) release(n int64) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.used -= n
+	if fs.used < 0 {
+		fs.used = 0
+	}
+}
@@ -0,0 +1,177 @@
+package vfsquota_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/vfsquota"
+	"github.com/c2fo/vfs/v5/vfstest"
+)
+
+type fileSystemTestSuite struct {
+	suite.Suite
+	dir string
+	fs  *vfsquota.FileSystem
+}
+
+func (ts *fileSystemTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfsquota_test")
+	ts.NoError(err)
+	ts.dir = dir
+	tenant, err := _os.NewFileSystem().NewLocation("", dir+"/tenant/")
+	ts.NoError(err)
+	ts.fs = vfsquota.NewFileSystem(_os.NewFileSystem(), tenant, 10)
+}
+
+func (ts *fileSystemTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *fileSystemTestSuite) writeFile(name, contents string) (vfs.File, error) {
+	file, err := ts.fs.NewFile("", ts.dir+"/"+name)
+	ts.NoError(err)
+	_, err = file.Write([]byte(contents))
+	ts.NoError(file.Close())
+	return file, err
+}
+
+func (ts *fileSystemTestSuite) TestWrite() {
+	ts.Equal("Quota os", ts.fs.Name())
+	ts.Equal(int64(10), ts.fs.Quota())
+
+	_, err := ts.writeFile("tenant/a.txt", "hello")
+	ts.NoError(err)
+	ts.Equal(int64(5), ts.fs.Used())
+	ts.Equal(int64(5), ts.fs.Remaining())
+
+	file, err := ts.writeFile("tenant/sub/b.txt", "world!")
+	ts.Equal(vfs.ErrQuotaExceeded, err)
+	ts.Equal(int64(5), ts.fs.Used(), "a rejected write isn't counted")
+	exists, err := file.Exists()
+	ts.NoError(err)
+	ts.False(exists, "nothing is written by a rejected write")
+
+	_, err = ts.writeFile("tenant/sub/b.txt", "world")
+	ts.NoError(err)
+	ts.Equal(int64(10), ts.fs.Used())
+	ts.Equal(int64(0), ts.fs.Remaining())
+
+	_, err = ts.writeFile("elsewhere.txt", "not counted")
+	ts.NoError(err, "files outside the location aren't limited")
+	ts.Equal(int64(10), ts.fs.Used())
+}
+
+func (ts *fileSystemTestSuite) TestDelete() {
+	file, err := ts.writeFile("tenant/a.txt", "hello")
+	ts.NoError(err)
+	_, err = ts.writeFile("tenant/b.txt", "world")
+	ts.NoError(err)
+	ts.Equal(int64(10), ts.fs.Used())
+
+	ts.NoError(file.Delete())
+	ts.Equal(int64(5), ts.fs.Used())
+
+	location, err := ts.fs.NewLocation("", ts.dir+"/tenant/")
+	ts.NoError(err)
+	ts.NoError(location.DeleteFile("b.txt"))
+	ts.Equal(int64(0), ts.fs.Used())
+}
+
+func (ts *fileSystemTestSuite) TestCopyAndMove() {
+	source, err := ts.writeFile("big.txt", "0123456789ab")
+	ts.NoError(err)
+	small, err := ts.writeFile("small.txt", "0123")
+	ts.NoError(err)
+	tenant, err := ts.fs.NewLocation("", ts.dir+"/tenant/")
+	ts.NoError(err)
+
+	_, err = source.CopyToLocation(tenant)
+	ts.Equal(vfs.ErrQuotaExceeded, err)
+	ts.Equal(int64(0), ts.fs.Used())
+
+	copied, err := small.CopyToLocation(tenant)
+	ts.NoError(err)
+	ts.Equal(int64(4), ts.fs.Used())
+
+	// moves within the location don't change what's stored
+	moved, err := tenant.NewFile("moved.txt")
+	ts.NoError(err)
+	ts.NoError(copied.MoveToFile(moved))
+	ts.Equal(int64(4), ts.fs.Used())
+
+	// moving out frees the file's size
+	outside, err := ts.fs.NewFile("", ts.dir+"/outside.txt")
+	ts.NoError(err)
+	ts.NoError(moved.MoveToFile(outside))
+	ts.Equal(int64(0), ts.fs.Used())
+
+	moved, err = tenant.NewFile("moved.txt")
+	ts.NoError(err)
+	ts.NoError(outside.MoveToFile(moved))
+	ts.Equal(int64(4), ts.fs.Used())
+}
+
+func (ts *fileSystemTestSuite) TestOverwrite() {
+	file, err := ts.writeFile("tenant/a.txt", "hello")
+	ts.NoError(err)
+	ts.Equal(int64(5), ts.fs.Used())
+
+	// rewriting a file frees the size of what it replaced
+	_, err = file.Write([]byte("0123"))
+	ts.NoError(err)
+	_, err = file.Write([]byte("456789"))
+	ts.NoError(err, "a file can be rewritten up to the whole quota")
+	ts.NoError(file.Close())
+	ts.Equal(int64(10), ts.fs.Used())
+
+	_, err = ts.writeFile("tenant/a.txt", "abc")
+	ts.NoError(err)
+	ts.Equal(int64(3), ts.fs.Used())
+
+	// as does copying or moving onto it
+	source, err := ts.writeFile("source.txt", "0123456")
+	ts.NoError(err)
+	ts.NoError(source.CopyToFile(file))
+	ts.Equal(int64(7), ts.fs.Used())
+
+	other, err := ts.writeFile("tenant/b.txt", "xy")
+	ts.NoError(err)
+	ts.Equal(int64(9), ts.fs.Used())
+	ts.NoError(other.MoveToFile(file))
+	ts.Equal(int64(2), ts.fs.Used())
+}
+
+func (ts *fileSystemTestSuite) TestSetUsed() {
+	ts.fs.SetUsed(8)
+	_, err := ts.writeFile("tenant/a.txt", "hello")
+	ts.Equal(vfs.ErrQuotaExceeded, err)
+	ts.Equal(int64(2), ts.fs.Remaining())
+}
+
+func TestFileSystem(t *testing.T) {
+	suite.Run(t, new(fileSystemTestSuite))
+}
+
+func TestConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vfsquota_conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// with a quota the conformance tests won't reach, the wrapper behaves just as the wrapped file system
+	location, err := _os.NewFileSystem().NewLocation("", dir+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	location, err = vfsquota.NewFileSystem(_os.NewFileSystem(), location, 1<<30).NewLocation("", dir+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vfstest.Run(t, location)
+}
@@ -0,0 +1,20 @@
+package vfsquota
+
+import (
+	"github.com/c2fo/vfs/v5/vfswrap"
+)
+
+// Location is a vfs.Location of a FileSystem, whose files count against the quota if they're beneath the location
+// it's enforced on.
+type Location struct {
+	*vfswrap.Location
+}
+
+// DeleteFile implements vfs.Location, freeing the file's size if it's beneath the location the quota is enforced on.
+func (l *Location) DeleteFile(relFilePath string) error {
+	file, err := l.NewFile(relFilePath)
+	if err != nil {
+		return err
+	}
+	return file.Delete()
+}
@@ -0,0 +1,40 @@
+/*
+Package vfssftp serves a vfs.Location over SFTP, so partners can pull and push files with any SFTP client while they're
+stored in any backend (ie: s3).  For an SFTP client backend, see backend/sftp.
+
+Usage
+
+Authentication is configured with an ssh.ServerConfig, so any of its callbacks can be plugged in (ie: checking
+passwords or public keys against a database).  It must have at least one host key:
+
+  config := &ssh.ServerConfig{
+      PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+          if conn.User() == "partner" && string(password) == os.Getenv("PARTNER_PASSWORD") {
+              return nil, nil
+          }
+          return nil, errors.New("access denied")
+      },
+  }
+  config.AddHostKey(hostKey)
+
+  loc, err := vfssimple.NewLocation("s3://mybucket/partners/")
+  if err != nil {
+      return err
+  }
+  server := vfssftp.NewServer(loc, config, vfssftp.Options{})
+  log.Fatal(server.ListenAndServe(":2022"))
+
+Set Options.UserLocation to serve each user a location of their own, ie: a folder beneath loc named for the user.
+
+Files and Directories
+
+Files are read by streaming them from the backend.  Uploads are written to a local temp file (see Options.TempDir),
+as SFTP clients may send an upload's chunks out of order, and written to the backend when the client closes the file.
+Changing a file's permissions or times is supported where the backend supports it and otherwise ignored, as are
+changes of ownership, so clients that set them after an upload don't fail.
+
+vfs has no directories of its own, only locations, which exist implicitly while they contain files.  A directory made
+with mkdir exists until the server is stopped, or it's removed, but is only written to the backend once files are
+uploaded to it.  Renaming directories and symlinks aren't supported.
+*/
+package vfssftp
@@ -0,0 +1,341 @@
+package vfssftp
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/spf13/afero"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+	"github.com/c2fo/vfs/v5/vfsafero"
+)
+
+// dirMode is the mode reported for directories, which vfs locations don't have permissions for.
+const dirMode = os.ModeDir | 0755
+
+var errIsDir = errors.New("is a directory")
+
+// chmoder, chtimeser and truncater are implemented by files whose backend supports changing their mode, times and
+// size.
+type chmoder interface {
+	Chmod(mode os.FileMode) error
+}
+
+type chtimeser interface {
+	Chtimes(atime, mtime time.Time) error
+}
+
+type truncater interface {
+	Truncate(size int64) error
+}
+
+// handlers implements the pkg/sftp request server's handlers for a session, serving location.  Paths are relative to
+// the location.
+type handlers struct {
+	server   *Server
+	location vfs.Location
+	fs       *vfsafero.Fs
+}
+
+func newHandlers(server *Server, location vfs.Location) *handlers {
+	return &handlers{server: server, location: location, fs: vfsafero.NewFs(location)}
+}
+
+// Fileread implements sftp.FileReader, opening the file to stream it from the backend.
+func (h *handlers) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	file, err := h.existingFile(r.Filepath)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return utils.NewReaderAt(file), nil
+}
+
+// Filewrite implements sftp.FileWriter, opening the file for writing to a local copy that's written to the backend
+// when closed.
+func (h *handlers) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	if h.server.options.ReadOnly {
+		return nil, sftp.ErrSshFxPermissionDenied
+	}
+	rel := utils.RelPath(r.Filepath)
+	if rel == "" {
+		return nil, errIsDir
+	}
+	file, err := h.location.NewFile(rel)
+	if err != nil {
+		return nil, err
+	}
+
+	exists := true
+	info, err := h.fs.Stat(r.Filepath)
+	switch {
+	case os.IsNotExist(err):
+		exists = false
+	case err != nil:
+		return nil, err
+	case info.IsDir():
+		return nil, errIsDir
+	}
+
+	flags := r.Pflags()
+	switch {
+	case exists && flags.Creat && flags.Excl:
+		return nil, os.ErrExist
+	case !exists && !flags.Creat:
+		return nil, sftp.ErrSshFxNoSuchFile
+	}
+	return newWriter(file, exists && !flags.Trunc, h.server.options.TempDir)
+}
+
+// Filecmd implements sftp.FileCmder.
+func (h *handlers) Filecmd(r *sftp.Request) error {
+	if h.server.options.ReadOnly {
+		return sftp.ErrSshFxPermissionDenied
+	}
+	switch r.Method {
+	case "Setstat":
+		return statusError(h.setstat(r))
+	case "Rename":
+		return statusError(h.fs.Rename(r.Filepath, r.Target))
+	case "Remove":
+		if _, err := h.existingFile(r.Filepath); err != nil {
+			return statusError(err)
+		}
+		return statusError(h.fs.Remove(r.Filepath))
+	case "Mkdir":
+		location, err := h.dir(r.Filepath)
+		if err != nil {
+			return err
+		}
+		h.server.makeDir(location)
+		return nil
+	case "Rmdir":
+		return statusError(h.rmdir(r.Filepath))
+	default:
+		return sftp.ErrSshFxOpUnsupported
+	}
+}
+
+// Filelist implements sftp.FileLister, listing a directory or stating a file or directory.
+func (h *handlers) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	switch r.Method {
+	case "List":
+		infos, err := h.readDir(r.Filepath)
+		return listerAt(infos), statusError(err)
+	case "Stat":
+		info, err := h.stat(r.Filepath)
+		return listerAt{info}, statusError(err)
+	default:
+		return nil, sftp.ErrSshFxOpUnsupported
+	}
+}
+
+// existingFile returns the file at name, or an error if it doesn't exist or is a directory.
+func (h *handlers) existingFile(name string) (vfs.File, error) {
+	info, err := h.fs.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, errIsDir
+	}
+	return h.location.NewFile(utils.RelPath(name))
+}
+
+// dir returns the location of the directory at name.
+func (h *handlers) dir(name string) (vfs.Location, error) {
+	rel := utils.RelPath(name)
+	if rel == "" {
+		return h.location, nil
+	}
+	return h.location.NewLocation(utils.EnsureTrailingSlash(rel))
+}
+
+// stat returns a FileInfo for the file or directory at name, including directories made with mkdir.
+func (h *handlers) stat(name string) (os.FileInfo, error) {
+	info, err := h.fs.Stat(name)
+	if os.IsNotExist(err) {
+		location, dirErr := h.dir(name)
+		if dirErr == nil && h.server.isMadeDir(location) {
+			return vfs.NewFileInfo(path.Base(name), 0, time.Now(), dirMode), nil
+		}
+	}
+	return info, err
+}
+
+// readDir returns FileInfos for the contents of the directory at name, including directories made with mkdir.
+func (h *handlers) readDir(name string) ([]os.FileInfo, error) {
+	location, err := h.dir(name)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := afero.ReadDir(h.fs, name)
+	if os.IsNotExist(err) && h.server.isMadeDir(location) {
+		infos, err = []os.FileInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	listed := map[string]bool{}
+	for _, info := range infos {
+		listed[info.Name()] = true
+	}
+	for _, dirName := range h.server.madeDirs(location) {
+		if !listed[dirName] {
+			infos = append(infos, vfs.NewFileInfo(dirName, 0, time.Now(), dirMode))
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	return infos, nil
+}
+
+// rmdir removes the empty directory at name.
+func (h *handlers) rmdir(name string) error {
+	location, err := h.dir(name)
+	if err != nil {
+		return err
+	}
+	infos, err := h.readDir(name)
+	if err != nil {
+		return err
+	}
+	if len(infos) > 0 {
+		return errors.New("directory not empty")
+	}
+	if !h.server.removeDir(location) {
+		// other directories only exist while they contain files, so can't be empty
+		return os.ErrNotExist
+	}
+	return nil
+}
+
+// setstat changes the size, permissions and times of a file where the backend supports it.  Changes to permissions,
+// times and ownership are otherwise ignored, so clients that set them after an upload don't fail.
+func (h *handlers) setstat(r *sftp.Request) error {
+	file, err := h.existingFile(r.Filepath)
+	if err != nil {
+		if info, statErr := h.stat(r.Filepath); statErr == nil && info.IsDir() {
+			return nil
+		}
+		return err
+	}
+	flags := r.AttrFlags()
+	attrs := r.Attributes()
+	if flags.Size {
+		t, ok := file.(truncater)
+		if !ok {
+			return sftp.ErrSshFxOpUnsupported
+		}
+		if err := t.Truncate(int64(attrs.Size)); err != nil {
+			return err
+		}
+	}
+	if c, ok := file.(chmoder); ok && flags.Permissions {
+		if err := c.Chmod(attrs.FileMode().Perm()); err != nil {
+			return err
+		}
+	}
+	if c, ok := file.(chtimeser); ok && flags.Acmodtime {
+		if err := c.Chtimes(time.Unix(int64(attrs.Atime), 0), time.Unix(int64(attrs.Mtime), 0)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listerAt implements sftp.ListerAt for a slice of FileInfos.
+type listerAt []os.FileInfo
+
+// ListAt implements sftp.ListerAt.
+func (l listerAt) ListAt(infos []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+	n := copy(infos, l[offset:])
+	if n < len(infos) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// writer implements io.WriterAt for an upload, writing to a local temp file that's written to the backend when closed.
+type writer struct {
+	mu   sync.Mutex
+	file vfs.File
+	temp *os.File
+}
+
+// newWriter returns a writer for file, starting with a copy of its contents if copyExisting is true.
+func newWriter(file vfs.File, copyExisting bool, tempDir string) (*writer, error) {
+	temp, err := ioutil.TempFile(tempDir, tempFilePrefix)
+	if err != nil {
+		return nil, err
+	}
+	if copyExisting {
+		if _, err := utils.Copy(temp, file); err != nil {
+			_ = file.Close()
+			_ = temp.Close()
+			_ = os.Remove(temp.Name())
+			return nil, err
+		}
+		if err := file.Close(); err != nil {
+			_ = temp.Close()
+			_ = os.Remove(temp.Name())
+			return nil, err
+		}
+	}
+	return &writer{file: file, temp: temp}, nil
+}
+
+// WriteAt implements io.WriterAt.
+func (w *writer) WriteAt(p []byte, off int64) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.temp.WriteAt(p, off)
+}
+
+// Close writes the upload to the backend and removes the local copy.
+func (w *writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	defer func() {
+		_ = w.temp.Close()
+		_ = os.Remove(w.temp.Name())
+	}()
+
+	if _, err := w.temp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	// start the write so an empty upload still creates the file
+	if _, err := w.file.Write([]byte{}); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+	if _, err := utils.Copy(w.file, w.temp); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// statusError converts errors to the SFTP status codes clients expect, where there is one.
+func statusError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case os.IsNotExist(err):
+		return sftp.ErrSshFxNoSuchFile
+	case os.IsPermission(err):
+		return sftp.ErrSshFxPermissionDenied
+	default:
+		return err
+	}
+}
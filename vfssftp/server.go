@@ -0,0 +1,218 @@
+package vfssftp
+
+import (
+	"encoding/binary"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// tempFilePrefix begins the name of every local temp file holding an upload.
+const tempFilePrefix = "vfs-sftp-"
+
+// Options configure a Server.
+type Options struct {
+	// ReadOnly rejects every request that would modify the location with a permission denied error.
+	ReadOnly bool
+	// TempDir is the directory for local copies of uploads.  It defaults to os.TempDir().
+	TempDir string
+	// UserLocation, if set, returns the location to serve an authenticated user, given the Permissions returned by
+	// the ssh.ServerConfig callback that authenticated them.  An error closes the connection.
+	UserLocation func(location vfs.Location, user string, permissions *ssh.Permissions) (vfs.Location, error)
+}
+
+// Server serves a vfs.Location over SFTP.
+type Server struct {
+	location vfs.Location
+	config   *ssh.ServerConfig
+	options  Options
+
+	mu        sync.Mutex
+	listeners map[net.Listener]bool
+	conns     map[net.Conn]bool
+	// dirs are the URIs of the directories made with mkdir, which don't exist in the backend until they contain files
+	dirs map[string]bool
+}
+
+// NewServer returns a Server serving location to the clients config authenticates.
+func NewServer(location vfs.Location, config *ssh.ServerConfig, options Options) *Server {
+	return &Server{
+		location:  location,
+		config:    config,
+		options:   options,
+		listeners: map[net.Listener]bool{},
+		conns:     map[net.Conn]bool{},
+		dirs:      map[string]bool{},
+	}
+}
+
+// ListenAndServe listens on the TCP address addr and serves the connections accepted until the Server is closed.
+func (s *Server) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve serves the connections accepted on listener, each in its own goroutine, until the listener or Server is
+// closed.
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	s.listeners[listener] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.listeners, listener)
+		s.mu.Unlock()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			_ = s.ServeConn(conn)
+		}()
+	}
+}
+
+// ServeConn authenticates the client on conn and serves its SFTP sessions until it disconnects.
+func (s *Server) ServeConn(conn net.Conn) error {
+	s.mu.Lock()
+	s.conns[conn] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	sshConn, channels, requests, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		return err
+	}
+	go ssh.DiscardRequests(requests)
+
+	location := s.location
+	if s.options.UserLocation != nil {
+		if location, err = s.options.UserLocation(s.location, sshConn.User(), sshConn.Permissions); err != nil {
+			_ = sshConn.Close()
+			return err
+		}
+	}
+
+	var wg sync.WaitGroup
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "only session channels are supported")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveSession(location, channel, channelRequests)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// Close closes the Server's listeners and connections.  Uploads in progress are abandoned.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var err error
+	for listener := range s.listeners {
+		if closeErr := listener.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	for conn := range s.conns {
+		if closeErr := conn.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// serveSession serves SFTP on channel once the client requests the sftp subsystem, rejecting any other requests.
+func (s *Server) serveSession(location vfs.Location, channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer func() { _ = channel.Close() }()
+	for req := range requests {
+		if req.Type != "subsystem" || !isSFTPSubsystem(req.Payload) {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		_ = req.Reply(true, nil)
+		go ssh.DiscardRequests(requests)
+
+		h := newHandlers(s, location)
+		server := sftp.NewRequestServer(channel, sftp.Handlers{FileGet: h, FilePut: h, FileCmd: h, FileList: h})
+		_ = server.Serve()
+		_ = server.Close()
+		return
+	}
+}
+
+// isSFTPSubsystem returns true if payload, a subsystem request's, asks for the sftp subsystem.
+func isSFTPSubsystem(payload []byte) bool {
+	if len(payload) < 4 {
+		return false
+	}
+	length := binary.BigEndian.Uint32(payload)
+	return uint64(length) == uint64(len(payload)-4) && string(payload[4:]) == "sftp"
+}
+
+// makeDir records that a directory was made at location.
+func (s *Server) makeDir(location vfs.Location) {
+	s.mu.Lock()
+	s.dirs[location.URI()] = true
+	s.mu.Unlock()
+}
+
+// removeDir forgets a directory made at location, returning true if there was one.
+func (s *Server) removeDir(location vfs.Location) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	made := s.dirs[location.URI()]
+	delete(s.dirs, location.URI())
+	return made
+}
+
+// isMadeDir returns true if a directory was made at location.
+func (s *Server) isMadeDir(location vfs.Location) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dirs[location.URI()]
+}
+
+// madeDirs returns the names of the directories made directly beneath location.
+func (s *Server) madeDirs(location vfs.Location) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for uri := range s.dirs {
+		name := strings.TrimSuffix(strings.TrimPrefix(uri, location.URI()), "/")
+		if strings.HasPrefix(uri, location.URI()) && name != "" && !strings.Contains(name, "/") {
+			// URIs are percent-encoded, see vfs.BuildURI
+			if decoded, err := url.PathUnescape(name); err == nil {
+				name = decoded
+			}
+			names = append(names, name)
+		}
+	}
+	return names
+}
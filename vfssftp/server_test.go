@@ -0,0 +1,283 @@
+package vfssftp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+)
+
+type serverTestSuite struct {
+	suite.Suite
+	dir      string
+	tempDir  string
+	location vfs.Location
+	config   *ssh.ServerConfig
+}
+
+func (ts *serverTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfssftp_test")
+	ts.NoError(err)
+	ts.dir = dir
+	ts.tempDir = filepath.Join(dir, ".temp")
+	ts.NoError(os.MkdirAll(ts.tempDir, 0755))
+	ts.NoError(os.MkdirAll(filepath.Join(dir, "data"), 0755))
+	ts.location, err = _os.NewFileSystem().NewLocation("", dir+"/data/")
+	ts.NoError(err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ts.NoError(err)
+	signer, err := ssh.NewSignerFromKey(key)
+	ts.NoError(err)
+	ts.config = &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if string(password) == "secret" {
+				return &ssh.Permissions{Extensions: map[string]string{"folder": conn.User()}}, nil
+			}
+			return nil, errors.New("access denied")
+		},
+	}
+	ts.config.AddHostKey(signer)
+}
+
+func (ts *serverTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+// serve starts a server, returning a client connected to it as user with password and a func to stop both.
+func (ts *serverTestSuite) serve(options Options, user, password string) (*sftp.Client, func(), error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	ts.NoError(err)
+	options.TempDir = ts.tempDir
+	server := NewServer(ts.location, ts.config, options)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	sshClient, err := ssh.Dial("tcp", listener.Addr().String(), &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.Password(password)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		_ = server.Close()
+		return nil, nil, err
+	}
+	client, err := sftp.NewClient(sshClient)
+	ts.NoError(err)
+	return client, func() {
+		ts.NoError(client.Close())
+		ts.NoError(server.Close())
+	}, nil
+}
+
+func (ts *serverTestSuite) writeFile(name, contents string) {
+	path := filepath.Join(ts.dir, "data", name)
+	ts.NoError(os.MkdirAll(filepath.Dir(path), 0755))
+	ts.NoError(ioutil.WriteFile(path, []byte(contents), 0644))
+}
+
+func (ts *serverTestSuite) readFile(name string) string {
+	data, err := ioutil.ReadFile(filepath.Join(ts.dir, "data", name))
+	ts.NoError(err)
+	return string(data)
+}
+
+func (ts *serverTestSuite) TestUploadAndDownload() {
+	client, stop, err := ts.serve(Options{}, "partner", "secret")
+	ts.NoError(err)
+	defer stop()
+
+	// large enough to be sent in many concurrent chunks
+	data := make([]byte, 1024*1024)
+	_, err = rand.Read(data)
+	ts.NoError(err)
+	f, err := client.Create("/upload/data.bin")
+	ts.NoError(err)
+	_, err = f.Write(data)
+	ts.NoError(err)
+	ts.NoError(f.Close())
+	ts.Equal(string(data), ts.readFile("upload/data.bin"))
+
+	f, err = client.Open("/upload/data.bin")
+	ts.NoError(err)
+	downloaded, err := ioutil.ReadAll(f)
+	ts.NoError(err)
+	ts.NoError(f.Close())
+	ts.Equal(data, downloaded)
+
+	info, err := client.Stat("/upload/data.bin")
+	ts.NoError(err)
+	ts.Equal(int64(len(data)), info.Size())
+
+	f, err = client.Create("empty.txt")
+	ts.NoError(err)
+	ts.NoError(f.Close())
+	ts.Equal("", ts.readFile("empty.txt"))
+
+	_, err = client.Open("/missing.txt")
+	ts.True(os.IsNotExist(err))
+	ts.Empty(ts.tempDirContents(), "local copies of uploads are removed")
+}
+
+func (ts *serverTestSuite) TestOverwriteAndAppend() {
+	ts.writeFile("file.txt", "0123456789")
+	client, stop, err := ts.serve(Options{}, "partner", "secret")
+	ts.NoError(err)
+	defer stop()
+
+	f, err := client.OpenFile("/file.txt", os.O_WRONLY)
+	ts.NoError(err)
+	_, err = f.Seek(4, io.SeekStart)
+	ts.NoError(err)
+	_, err = f.Write([]byte("abc"))
+	ts.NoError(err)
+	ts.NoError(f.Close())
+	ts.Equal("0123abc789", ts.readFile("file.txt"))
+
+	f, err = client.OpenFile("/file.txt", os.O_WRONLY|os.O_APPEND)
+	ts.NoError(err)
+	_, err = f.Seek(10, io.SeekStart)
+	ts.NoError(err)
+	_, err = f.Write([]byte("!"))
+	ts.NoError(err)
+	ts.NoError(f.Close())
+	ts.Equal("0123abc789!", ts.readFile("file.txt"))
+
+	_, err = client.OpenFile("/file.txt", os.O_WRONLY|os.O_CREATE|os.O_EXCL)
+	ts.Error(err)
+	_, err = client.OpenFile("/missing.txt", os.O_WRONLY)
+	ts.True(os.IsNotExist(err))
+
+	ts.NoError(client.Truncate("/file.txt", 4))
+	ts.Equal("0123", ts.readFile("file.txt"))
+	ts.NoError(client.Chmod("/file.txt", 0600))
+	info, err := os.Stat(filepath.Join(ts.dir, "data", "file.txt"))
+	ts.NoError(err)
+	ts.Equal(os.FileMode(0600), info.Mode().Perm())
+	ts.NoError(client.Chown("/file.txt", 1000, 1000), "ownership changes are ignored")
+}
+
+func (ts *serverTestSuite) TestDirectories() {
+	ts.writeFile("sub/file.txt", "data")
+	ts.writeFile("top.txt", "top")
+	client, stop, err := ts.serve(Options{}, "partner", "secret")
+	ts.NoError(err)
+	defer stop()
+
+	ts.NoError(client.Mkdir("/made"))
+	info, err := client.Stat("/made")
+	ts.NoError(err)
+	ts.True(info.IsDir())
+
+	infos, err := client.ReadDir("/")
+	ts.NoError(err)
+	ts.Equal([]string{"made", "sub", "top.txt"}, names(infos))
+	ts.True(infos[0].IsDir())
+	ts.True(infos[1].IsDir())
+	ts.Equal(int64(3), infos[2].Size())
+
+	f, err := client.Create("/made/new.txt")
+	ts.NoError(err)
+	ts.NoError(f.Close())
+	infos, err = client.ReadDir("/made")
+	ts.NoError(err)
+	ts.Equal([]string{"new.txt"}, names(infos))
+
+	ts.Error(client.RemoveDirectory("/sub"), "directory isn't empty")
+	ts.NoError(client.Remove("/sub/file.txt"))
+	_, err = client.Stat("/sub")
+	ts.True(os.IsNotExist(err), "directories not made with mkdir are gone with their last file")
+	ts.Error(client.Remove("/made"), "Remove doesn't remove directories")
+
+	ts.NoError(client.Rename("/top.txt", "/made/moved.txt"))
+	ts.Equal("top", ts.readFile("made/moved.txt"))
+	ts.NoError(client.Mkdir("/made/empty"))
+	ts.NoError(client.RemoveDirectory("/made/empty"))
+	_, err = client.Stat("/made/empty")
+	ts.True(os.IsNotExist(err))
+	ts.Error(client.Symlink("/made/moved.txt", "/link"))
+}
+
+func (ts *serverTestSuite) TestReadOnly() {
+	ts.writeFile("file.txt", "data")
+	client, stop, err := ts.serve(Options{ReadOnly: true}, "partner", "secret")
+	ts.NoError(err)
+	defer stop()
+
+	f, err := client.Open("/file.txt")
+	ts.NoError(err)
+	data, err := ioutil.ReadAll(f)
+	ts.NoError(err)
+	ts.NoError(f.Close())
+	ts.Equal("data", string(data))
+
+	_, err = client.Create("/new.txt")
+	ts.True(isPermissionDenied(err))
+	ts.True(isPermissionDenied(client.Remove("/file.txt")))
+	ts.True(isPermissionDenied(client.Mkdir("/dir")))
+	ts.Equal("data", ts.readFile("file.txt"))
+}
+
+func (ts *serverTestSuite) TestAuthentication() {
+	_, _, err := ts.serve(Options{}, "partner", "wrong")
+	ts.Error(err)
+
+	ts.writeFile("alice/file.txt", "alice's")
+	ts.writeFile("bob/file.txt", "bob's")
+	options := Options{
+		UserLocation: func(location vfs.Location, user string, permissions *ssh.Permissions) (vfs.Location, error) {
+			return location.NewLocation(permissions.Extensions["folder"] + "/")
+		},
+	}
+	client, stop, err := ts.serve(options, "alice", "secret")
+	ts.NoError(err)
+	defer stop()
+	f, err := client.Open("/file.txt")
+	ts.NoError(err)
+	data, err := ioutil.ReadAll(f)
+	ts.NoError(err)
+	ts.NoError(f.Close())
+	ts.Equal("alice's", string(data))
+	_, err = client.Open("/../bob/file.txt")
+	ts.True(os.IsNotExist(err), "paths can't escape the user's location")
+}
+
+func (ts *serverTestSuite) tempDirContents() []os.FileInfo {
+	infos, err := ioutil.ReadDir(ts.tempDir)
+	ts.NoError(err)
+	return infos
+}
+
+// isPermissionDenied returns true if err is an SSH_FX_PERMISSION_DENIED status from the server.
+func isPermissionDenied(err error) bool {
+	statusErr, ok := err.(*sftp.StatusError)
+	return ok && statusErr.Code == uint32(sftp.ErrSshFxPermissionDenied)
+}
+
+func names(infos []os.FileInfo) []string {
+	result := make([]string, 0, len(infos))
+	for _, info := range infos {
+		result = append(result, info.Name())
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestServer(t *testing.T) {
+	suite.Run(t, new(serverTestSuite))
+}
@@ -2,7 +2,6 @@ package vfssimple
 
 import (
 	"fmt"
-	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -37,18 +36,11 @@ func NewFile(uri string) (vfs.File, error) {
 }
 
 func parseSupportedURI(uri string) (vfs.FileSystem, string, string, error) {
-	var err error
-	var u *url.URL
-	u, err = url.Parse(uri)
+	parsed, err := vfs.ParseURI(uri)
 	if err != nil {
 		return nil, "", "", err
 	}
-	host := u.Host
-	path := u.Path
-	user := u.User
-	if user.String() != "" {
-		host = fmt.Sprintf("%s@%s", user, host)
-	}
+	host := parsed.Volume[strings.LastIndex(parsed.Volume, "@")+1:]
 
 	var fs vfs.FileSystem
 	for _, backendScheme := range backend.RegisteredBackends() {
@@ -58,29 +50,29 @@ func parseSupportedURI(uri string) (vfs.FileSystem, string, string, error) {
 			break
 		}
 		// Bucket-level backend
-		volume := fmt.Sprintf("%s://%s/", u.Scheme, u.Host)
+		volume := fmt.Sprintf("%s://%s/", parsed.Scheme, host)
 		if volume == backendScheme {
 			fs = backend.Backend(backendScheme)
 			break
 		}
 
 		// Location-level backend
-		if isInPath(u, volume, backendScheme) {
+		if isInPath(parsed.Path, volume, backendScheme) {
 			fs = backend.Backend(backendScheme)
 			break
 		}
 
 		// Scheme-level backend
-		if u.Scheme == backendScheme {
+		if parsed.Scheme == backendScheme {
 			fs = backend.Backend(backendScheme)
 		}
 	}
 
 	if fs == nil {
-		err = fmt.Errorf("%s is an unsupported uri scheme", u.Scheme)
+		err = fmt.Errorf("%s is an unsupported uri scheme", parsed.Scheme)
 	}
 
-	return fs, host, path, err
+	return fs, parsed.Volume, parsed.Path, err
 }
 
 // isInPath will crawl down the provided url's path to see if it matches the registered root value
@@ -89,8 +81,7 @@ func parseSupportedURI(uri string) (vfs.FileSystem, string, string, error) {
 //   root: s3://bucket/root/   <== Registered URI for file system.
 //
 //   This would return true because the target url is within the root.
-func isInPath(url *url.URL, volume, root string) bool {
-	path := url.Path
+func isInPath(path, volume, root string) bool {
 	paths := strings.Split(path, string(os.PathSeparator))
 	for i := range paths {
 		path := fmt.Sprintf("%s%s/", volume, filepath.Join(paths[0:i]...))
@@ -0,0 +1,32 @@
+/*
+Package vfstest provides a conformance suite for vfs backends, in the spirit of testing/fstest.  It exercises the
+vfs.FileSystem, vfs.Location and vfs.File contract (path handling, listing, reading, writing, seeking, copying,
+moving and deleting, including edge cases) so new backends, and forks of existing ones, can prove they behave as the
+rest of vfs expects.
+
+Usage
+
+Call Run from a test with an empty, writable location:
+
+  func TestConformance(t *testing.T) {
+      dir, err := ioutil.TempDir("", "conformance")
+      if err != nil {
+          t.Fatal(err)
+      }
+      defer os.RemoveAll(dir)
+
+      location, err := myfs.NewFileSystem().NewLocation("", dir+"/")
+      if err != nil {
+          t.Fatal(err)
+      }
+      vfstest.Run(t, location)
+  }
+
+Each test works in a sub-location of its own and deletes the files it creates, so a shared location such as a test
+bucket prefix can be used.  Backends with real directories, such as os and sftp, are left with empty directories,
+which is why the example removes its temp dir.
+
+Move and copy tests run within the backend.  To test moving and copying between backends, see the integration suite
+in backend/testsuite.
+*/
+package vfstest
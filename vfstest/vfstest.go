@@ -0,0 +1,570 @@
+package vfstest
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/utils"
+)
+
+// Run runs the conformance suite against location, which must be writable.  Each test runs as a subtest of t.
+func Run(t *testing.T, location vfs.Location) {
+	suite.Run(t, &conformanceSuite{base: location})
+}
+
+type conformanceSuite struct {
+	suite.Suite
+	base vfs.Location
+	// dir is the sub-location of base the current test works in.
+	dir vfs.Location
+	// created are the locations the current test has created files in, to be cleaned up after it.
+	created []vfs.Location
+}
+
+func (s *conformanceSuite) SetupTest() {
+	dir, err := s.base.NewLocation(fmt.Sprintf("vfstest-%d/", time.Now().UnixNano()))
+	s.Require().NoError(err)
+	s.dir = dir
+	s.created = []vfs.Location{dir}
+}
+
+func (s *conformanceSuite) TearDownTest() {
+	for _, location := range s.created {
+		names, err := location.List()
+		s.NoError(err, "unable to list %s to clean up", location)
+		for _, name := range names {
+			s.NoError(location.DeleteFile(name), "unable to clean up %s%s", location, name)
+		}
+	}
+}
+
+// location returns the sub-location of the test's dir at relLocPath, cleaned up after the test.
+func (s *conformanceSuite) location(relLocPath string) vfs.Location {
+	location, err := s.dir.NewLocation(relLocPath)
+	s.Require().NoError(err)
+	s.created = append(s.created, location)
+	return location
+}
+
+// file returns the file at relFilePath in location, without creating it.
+func (s *conformanceSuite) file(location vfs.Location, relFilePath string) vfs.File {
+	file, err := location.NewFile(relFilePath)
+	s.Require().NoError(err)
+	return file
+}
+
+// writeFile writes contents to the file at relFilePath in location, returning the file.
+func (s *conformanceSuite) writeFile(location vfs.Location, relFilePath, contents string) vfs.File {
+	file := s.file(location, relFilePath)
+	n, err := file.Write([]byte(contents))
+	s.Require().NoError(err, "write %s", file)
+	s.Equal(len(contents), n, "write %s", file)
+	s.Require().NoError(file.Close(), "close %s", file)
+	return file
+}
+
+// contents reads the whole of file, then closes it.
+func (s *conformanceSuite) contents(file vfs.File) string {
+	data, err := ioutil.ReadAll(file)
+	s.NoError(err, "read %s", file)
+	s.NoError(file.Close(), "close %s", file)
+	return string(data)
+}
+
+// exists returns whether file exists.
+func (s *conformanceSuite) exists(file vfs.File) bool {
+	exists, err := file.Exists()
+	s.NoError(err, "exists %s", file)
+	return exists
+}
+
+// sorted returns names sorted, as List's order isn't specified.
+func sorted(names []string) []string {
+	sort.Strings(names)
+	return names
+}
+
+/**********************************
+ ***********FileSystem*************
+ **********************************/
+
+func (s *conformanceSuite) TestFileSystemNewFile() {
+	fs := s.base.FileSystem()
+	paths := map[string]bool{
+		"/path/to/file.txt":    true,
+		"/path/./to/file.txt":  true,
+		"/path/../to/file.txt": true,
+		"path/to/file.txt":     false,
+		"./path/to/file.txt":   false,
+		"../path/to/":          false,
+		"/path/to/":            false,
+		"":                     false,
+	}
+	for name, valid := range paths {
+		file, err := fs.NewFile(s.base.Volume(), name)
+		if !valid {
+			s.Error(err, "NewFile(%q) should fail validation", name)
+			continue
+		}
+		if s.NoError(err, "NewFile(%q)", name) {
+			s.Equal(fmt.Sprintf("%s://%s%s", fs.Scheme(), s.base.Volume(), path.Clean(name)), file.URI())
+			s.Equal(path.Clean(name), file.Path())
+			s.Equal("file.txt", file.Name())
+		}
+	}
+}
+
+func (s *conformanceSuite) TestFileSystemNewLocation() {
+	fs := s.base.FileSystem()
+	paths := map[string]bool{
+		"/path/to/":         true,
+		"/path/./to/":       true,
+		"/path/../to/":      true,
+		"/":                 true,
+		"path/to/":          false,
+		"./path/to/":        false,
+		"../path/to/":       false,
+		"/path/to/file.txt": false,
+		"":                  false,
+	}
+	for name, valid := range paths {
+		location, err := fs.NewLocation(s.base.Volume(), name)
+		if !valid {
+			s.Error(err, "NewLocation(%q) should fail validation", name)
+			continue
+		}
+		if s.NoError(err, "NewLocation(%q)", name) {
+			expected := utils.EnsureTrailingSlash(path.Clean(name))
+			s.Equal(fmt.Sprintf("%s://%s%s", fs.Scheme(), s.base.Volume(), expected), location.URI())
+			s.Equal(expected, location.Path())
+			s.Equal(fs.Scheme(), location.FileSystem().Scheme())
+		}
+	}
+}
+
+/**********************************
+ ************Location**************
+ **********************************/
+
+func (s *conformanceSuite) TestLocationPaths() {
+	s.True(strings.HasPrefix(s.dir.Path(), "/"), "location paths are absolute")
+	s.True(strings.HasSuffix(s.dir.Path(), "/"), "location paths end with a slash")
+	s.True(strings.HasSuffix(s.dir.URI(), "/"), "location URIs end with a slash")
+	s.Equal(s.dir.URI(), s.dir.String())
+	s.Equal(s.base.Volume(), s.dir.Volume())
+
+	paths := map[string]bool{
+		"path/to/":          true,
+		"./path/to/":        true,
+		"path/./to/../to/":  true,
+		"../path/to/":       true,
+		"/path/to/":         false,
+		"/path/to/file.txt": false,
+		"":                  false,
+	}
+	for name, valid := range paths {
+		location, err := s.dir.NewLocation(name)
+		if !valid {
+			s.Error(err, "NewLocation(%q) should fail validation", name)
+			continue
+		}
+		if s.NoError(err, "NewLocation(%q)", name) {
+			expected := utils.EnsureTrailingSlash(path.Clean(path.Join(s.dir.Path(), name)))
+			s.Equal(expected, location.Path(), "NewLocation(%q)", name)
+			s.Equal(fmt.Sprintf("%s://%s%s", s.dir.FileSystem().Scheme(), s.dir.Volume(), expected), location.URI())
+		}
+	}
+	s.Equal(s.dir.Path(), s.location("sub/").Path()[:len(s.dir.Path())], "NewLocation doesn't change the location")
+}
+
+func (s *conformanceSuite) TestLocationNewFile() {
+	paths := map[string]bool{
+		"path/to/file.txt":    true,
+		"./path/to/file.txt":  true,
+		"../path/to/file.txt": true,
+		"file.txt":            true,
+		"/path/to/file.txt":   false,
+		"../path/to/":         false,
+		"/path/to/":           false,
+		"":                    false,
+	}
+	for name, valid := range paths {
+		file, err := s.dir.NewFile(name)
+		if !valid {
+			s.Error(err, "NewFile(%q) should fail validation", name)
+			continue
+		}
+		if s.NoError(err, "NewFile(%q)", name) {
+			expected := path.Clean(path.Join(s.dir.Path(), name))
+			s.Equal(expected, file.Path(), "NewFile(%q)", name)
+			s.Equal(fmt.Sprintf("%s://%s%s", s.dir.FileSystem().Scheme(), s.dir.Volume(), expected), file.URI())
+			s.Equal(utils.EnsureTrailingSlash(path.Dir(expected)), file.Location().Path())
+		}
+	}
+}
+
+func (s *conformanceSuite) TestLocationChangeDir() {
+	location, err := s.dir.NewLocation("chdir/")
+	s.Require().NoError(err)
+
+	s.Error(location.ChangeDir(""), "an empty path is an error")
+	s.Error(location.ChangeDir("/home/"), "an absolute path is an error")
+	s.Error(location.ChangeDir("file.txt"), "a file path is an error")
+	s.Equal(s.dir.Path()+"chdir/", location.Path(), "a failed ChangeDir doesn't change the location")
+
+	s.NoError(location.ChangeDir("l1/./l2a/../l2b/"))
+	s.Equal(s.dir.Path()+"chdir/l1/l2b/", location.Path())
+	s.Equal(s.dir.URI()+"chdir/l1/l2b/", location.URI())
+
+	s.NoError(location.ChangeDir("../../../"))
+	s.Equal(s.dir.Path(), location.Path())
+}
+
+func (s *conformanceSuite) TestLocationList() {
+	s.writeFile(s.dir, "file1.txt", "1")
+	s.writeFile(s.dir, "file2.txt", "2")
+	s.writeFile(s.dir, "self.csv", "self")
+	sub := s.location("sub/")
+	s.writeFile(sub, "that.txt", "that")
+
+	names, err := s.dir.List()
+	s.NoError(err)
+	s.Equal([]string{"file1.txt", "file2.txt", "self.csv"}, sorted(names), "files in sub-locations aren't listed")
+
+	names, err = sub.List()
+	s.NoError(err)
+	s.Equal([]string{"that.txt"}, names)
+
+	names, err = s.location("missing/").List()
+	s.NoError(err, "listing a location that doesn't exist isn't an error")
+	s.Empty(names)
+}
+
+func (s *conformanceSuite) TestLocationListByPrefix() {
+	s.writeFile(s.dir, "file1.txt", "1")
+	s.writeFile(s.dir, "file2.txt", "2")
+	s.writeFile(s.dir, "self.csv", "self")
+	s.writeFile(s.location("sub/"), "that.txt", "that")
+
+	names, err := s.dir.ListByPrefix("file")
+	s.NoError(err)
+	s.Equal([]string{"file1.txt", "file2.txt"}, sorted(names))
+
+	names, err = s.dir.ListByPrefix("s")
+	s.NoError(err)
+	s.Equal([]string{"self.csv"}, names, "sub-locations matching the prefix aren't listed")
+
+	names, err = s.dir.ListByPrefix("sub/t")
+	s.NoError(err)
+	s.Equal([]string{"that.txt"}, names, "relative prefixes list the sub-location")
+
+	names, err = s.dir.ListByPrefix("z")
+	s.NoError(err)
+	s.Empty(names)
+
+	names, err = s.location("missing/").ListByPrefix("file")
+	s.NoError(err, "listing a location that doesn't exist isn't an error")
+	s.Empty(names)
+}
+
+func (s *conformanceSuite) TestLocationListByRegex() {
+	s.writeFile(s.dir, "file1.txt", "1")
+	s.writeFile(s.dir, "file2.txt", "2")
+	s.writeFile(s.dir, "self.csv", "self")
+
+	names, err := s.dir.ListByRegex(regexp.MustCompile(`^f`))
+	s.NoError(err)
+	s.Equal([]string{"file1.txt", "file2.txt"}, sorted(names))
+
+	names, err = s.dir.ListByRegex(regexp.MustCompile(`\.csv$`))
+	s.NoError(err)
+	s.Equal([]string{"self.csv"}, names)
+
+	names, err = s.dir.ListByRegex(regexp.MustCompile(`Z`))
+	s.NoError(err)
+	s.Empty(names)
+
+	names, err = s.location("missing/").ListByRegex(regexp.MustCompile(`.*`))
+	s.NoError(err, "listing a location that doesn't exist isn't an error")
+	s.Empty(names)
+}
+
+func (s *conformanceSuite) TestLocationExists() {
+	exists, err := s.base.Exists()
+	s.NoError(err)
+	s.True(exists, "the base location exists")
+
+	s.writeFile(s.dir, "file.txt", "contents")
+	exists, err = s.dir.Exists()
+	s.NoError(err)
+	s.True(exists, "a location with files exists")
+}
+
+func (s *conformanceSuite) TestLocationDeleteFile() {
+	file := s.writeFile(s.dir, "file.txt", "contents")
+	s.writeFile(s.location("sub/"), "that.txt", "that")
+
+	s.NoError(s.dir.DeleteFile("file.txt"))
+	s.False(s.exists(file))
+	s.NoError(s.dir.DeleteFile("sub/that.txt"), "relative paths are deleted")
+	s.False(s.exists(s.file(s.dir, "sub/that.txt")))
+
+	s.Error(s.dir.DeleteFile("file.txt"), "deleting a file that doesn't exist is an error")
+}
+
+/**********************************
+ **************File****************
+ **********************************/
+
+func (s *conformanceSuite) TestFilePaths() {
+	file := s.file(s.dir, "file.txt")
+	s.Equal("file.txt", file.Name())
+	s.Equal(s.dir.Path()+"file.txt", file.Path())
+	s.Equal(s.dir.URI()+"file.txt", file.URI())
+	s.Equal(file.URI(), file.String())
+	s.Equal(file.URI(), fmt.Sprintf("%s", file))
+	s.Equal(s.dir.URI(), file.Location().URI())
+	s.Equal(s.dir.FileSystem().Scheme(), file.Location().FileSystem().Scheme())
+}
+
+func (s *conformanceSuite) TestFileWriteRead() {
+	file := s.file(s.dir, "file.txt")
+	s.False(s.exists(file), "a new file doesn't exist")
+
+	n, err := file.Write([]byte("this is a test\n"))
+	s.NoError(err)
+	s.Equal(15, n)
+	n, err = file.Write([]byte("and more text"))
+	s.NoError(err)
+	s.Equal(13, n)
+	s.NoError(file.Close())
+
+	s.True(s.exists(file), "a written file exists")
+	size, err := file.Size()
+	s.NoError(err)
+	s.Equal(uint64(28), size)
+	lastModified, err := file.LastModified()
+	s.NoError(err)
+	s.NotNil(lastModified)
+
+	s.Equal("this is a test\nand more text", s.contents(file))
+	s.Equal("this is a test\nand more text", s.contents(file), "a closed file is read from the start again")
+	s.Equal("this is a test\nand more text", s.contents(s.file(s.dir, "file.txt")), "the file is read by new instances")
+}
+
+func (s *conformanceSuite) TestFileOverwrite() {
+	s.writeFile(s.dir, "file.txt", "the original, longer, contents")
+	file := s.writeFile(s.dir, "file.txt", "new contents")
+	s.Equal("new contents", s.contents(file), "writing replaces a file's contents")
+
+	size, err := file.Size()
+	s.NoError(err)
+	s.Equal(uint64(12), size)
+}
+
+func (s *conformanceSuite) TestFileEmpty() {
+	file := s.writeFile(s.dir, "empty.txt", "")
+	s.True(s.exists(file), "a file written with no data exists")
+	size, err := file.Size()
+	s.NoError(err)
+	s.Equal(uint64(0), size)
+	s.Equal("", s.contents(file))
+}
+
+func (s *conformanceSuite) TestFileSeek() {
+	file := s.writeFile(s.dir, "file.txt", "0123456789")
+
+	offset, err := file.Seek(3, io.SeekStart)
+	s.NoError(err)
+	s.Equal(int64(3), offset)
+	buf := make([]byte, 2)
+	_, err = io.ReadFull(file, buf)
+	s.NoError(err)
+	s.Equal("34", string(buf))
+
+	offset, err = file.Seek(2, io.SeekCurrent)
+	s.NoError(err)
+	s.Equal(int64(7), offset)
+	_, err = io.ReadFull(file, buf)
+	s.NoError(err)
+	s.Equal("78", string(buf))
+
+	offset, err = file.Seek(-4, io.SeekEnd)
+	s.NoError(err)
+	s.Equal(int64(6), offset)
+	s.Equal("6789", s.contents(file))
+}
+
+func (s *conformanceSuite) TestFileReadAfterSeekStart() {
+	file := s.writeFile(s.dir, "file.txt", "this is a test")
+	s.Equal("this is a test", s.contents(file))
+
+	_, err := file.Seek(0, io.SeekStart)
+	s.NoError(err)
+	data, err := ioutil.ReadAll(file)
+	s.NoError(err)
+	s.Equal("this is a test", string(data), "a file can be read again after seeking to the start")
+	s.NoError(file.Close())
+}
+
+func (s *conformanceSuite) TestFileIOCopy() {
+	source := s.writeFile(s.dir, "source.txt", "some contents")
+	target := s.file(s.dir, "target.txt")
+
+	n, err := io.Copy(target, source)
+	s.NoError(err)
+	s.Equal(int64(13), n)
+	s.NoError(source.Close())
+	s.NoError(target.Close())
+	s.Equal("some contents", s.contents(target))
+}
+
+func (s *conformanceSuite) TestFileCopyToFile() {
+	source := s.writeFile(s.dir, "source.txt", "some contents")
+	target := s.file(s.location("sub/"), "target.txt")
+
+	s.NoError(source.CopyToFile(target))
+	s.True(s.exists(source), "the source still exists after a copy")
+	s.Equal("some contents", s.contents(target))
+	s.Equal("some contents", s.contents(source))
+
+	overwrite := s.writeFile(s.dir, "source.txt", "new contents")
+	s.NoError(overwrite.CopyToFile(target))
+	s.Equal("new contents", s.contents(target), "copying overwrites an existing file")
+}
+
+func (s *conformanceSuite) TestFileCopyToLocation() {
+	source := s.writeFile(s.dir, "source.txt", "some contents")
+	copied, err := source.CopyToLocation(s.location("copied/"))
+	s.Require().NoError(err)
+
+	s.Equal(s.dir.Path()+"copied/source.txt", copied.Path())
+	s.True(s.exists(source), "the source still exists after a copy")
+	s.Equal("some contents", s.contents(copied))
+
+	s.writeFile(s.dir, "source.txt", "new contents")
+	copied, err = source.CopyToLocation(s.location("copied/"))
+	s.Require().NoError(err)
+	s.Equal("new contents", s.contents(copied), "copying overwrites an existing file")
+}
+
+func (s *conformanceSuite) TestFileCopyAfterRead() {
+	source := s.writeFile(s.dir, "source.txt", "some contents")
+	buf := make([]byte, 4)
+	_, err := io.ReadFull(source, buf)
+	s.NoError(err)
+	_, err = source.Seek(0, io.SeekStart)
+	s.NoError(err)
+
+	target := s.file(s.dir, "target.txt")
+	s.NoError(source.CopyToFile(target))
+	s.Equal("some contents", s.contents(target), "the whole file is copied after seeking back to the start")
+}
+
+func (s *conformanceSuite) TestFileMoveToFile() {
+	source := s.writeFile(s.dir, "source.txt", "some contents")
+	target := s.file(s.location("moved/"), "target.txt")
+
+	s.NoError(source.MoveToFile(target))
+	s.False(s.exists(source), "the source doesn't exist after a move")
+	s.True(s.exists(target))
+	s.Equal("some contents", s.contents(target))
+}
+
+func (s *conformanceSuite) TestFileMoveToLocation() {
+	source := s.writeFile(s.dir, "source.txt", "some contents")
+	moved, err := source.MoveToLocation(s.location("moved/doesnotexist/"))
+	s.Require().NoError(err, "moving to a location that doesn't exist yet creates it")
+
+	s.Equal(s.dir.Path()+"moved/doesnotexist/source.txt", moved.Path())
+	s.False(s.exists(source), "the source doesn't exist after a move")
+	s.Equal("some contents", s.contents(moved))
+
+	s.writeFile(s.dir, "source.txt", "new contents")
+	moved, err = source.MoveToLocation(s.location("moved/doesnotexist/"))
+	s.Require().NoError(err)
+	s.Equal("new contents", s.contents(moved), "moving overwrites an existing file")
+}
+
+func (s *conformanceSuite) TestFileMoveWithSpaces() {
+	tests := []struct {
+		path, name string
+	}{
+		{path: "file/", name: "has space.txt"},
+		{path: "file/", name: "has%20encodedSpace.txt"},
+		{path: "path has/", name: "space.txt"},
+		{path: "path%20has/", name: "encodedSpace.txt"},
+	}
+	for _, test := range tests {
+		s.location(test.path)
+		source := s.writeFile(s.dir, test.path+test.name, "something")
+		moved, err := source.MoveToLocation(s.location("moved/" + test.path))
+		if !s.NoError(err, "move %s", source) {
+			continue
+		}
+		s.Equal(s.dir.Path()+"moved/"+test.path+test.name, moved.Path())
+		s.True(s.exists(moved), "%s exists after the move", moved)
+		s.False(s.exists(source), "%s doesn't exist after the move", source)
+		s.Equal("something", s.contents(moved))
+	}
+}
+
+func (s *conformanceSuite) TestFileDelete() {
+	file := s.writeFile(s.dir, "file.txt", "contents")
+	s.NoError(file.Delete())
+	s.False(s.exists(file))
+
+	names, err := s.dir.List()
+	s.NoError(err)
+	s.Empty(names, "a deleted file isn't listed")
+}
+
+func (s *conformanceSuite) TestFileTouch() {
+	file := s.file(s.dir, "touch.txt")
+	s.NoError(file.Touch())
+	s.True(s.exists(file), "touch creates a file")
+	size, err := file.Size()
+	s.NoError(err)
+	s.Equal(uint64(0), size, "touch creates an empty file")
+
+	modified, err := file.LastModified()
+	s.Require().NoError(err)
+	// some backends only record the last modified time to the second
+	time.Sleep(time.Second)
+	s.NoError(file.Touch())
+	touched, err := file.LastModified()
+	s.Require().NoError(err)
+	s.True(touched.After(*modified), "touch updates the last modified time")
+
+	file = s.writeFile(s.dir, "existing.txt", "contents")
+	s.NoError(file.Touch())
+	s.Equal("contents", s.contents(file), "touching an existing file doesn't change its contents")
+}
+
+func (s *conformanceSuite) TestFileNotExist() {
+	file := s.file(s.dir, "doesNotExist.txt")
+	s.False(s.exists(file))
+
+	size, err := file.Size()
+	s.Error(err, "size of a file that doesn't exist")
+	s.Equal(uint64(0), size)
+	_, err = file.LastModified()
+	s.Error(err, "last modified time of a file that doesn't exist")
+	_, err = file.Seek(-1, io.SeekEnd)
+	s.Error(err, "seek of a file that doesn't exist")
+	_, err = file.Read(make([]byte, 1))
+	s.Error(err, "read of a file that doesn't exist")
+	s.False(s.exists(file), "reading a file that doesn't exist doesn't create it")
+	s.Error(file.CopyToFile(s.file(s.dir, "target.txt")), "copy of a file that doesn't exist")
+	s.False(s.exists(s.file(s.dir, "target.txt")), "a failed copy doesn't create the target")
+}
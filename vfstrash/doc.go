@@ -0,0 +1,27 @@
+/*
+Package vfstrash wraps any vfs.FileSystem so that deleting a file moves it to a trash location instead, from where it
+can be restored, protecting against accidental deletes.
+
+Usage
+
+Wrap a file system, with the location deleted files are moved to, and use it in its place:
+
+  trash, err := s3.NewFileSystem().NewLocation("mybucket", "/.trash/")
+  fs := vfstrash.NewFileSystem(s3.NewFileSystem(), trash)
+  file, err := fs.NewFile("mybucket", "/path/to/file.txt")
+  err = file.Delete()   // moved to the trash
+
+File.Delete and Location.DeleteFile move the file to the trash, which may be on any backend.  Files already in the
+trash are deleted.  Moves, and writes that overwrite a file, aren't affected.
+
+Restore and Empty
+
+Each trashed file is named by the UTC time it was deleted and its original volume and path, so the trash only needs
+to be listed, not walked, and files deleted from the same path more than once are all kept:
+
+  20200102T150405.000000000Z~mybucket~%2Fpath%2Fto%2Ffile.txt
+
+Trashed lists the files in the trash, oldest first.  Restore moves one back to where it was deleted from, unless a file
+has since been written there.  Empty deletes the files trashed more than a given time ago, or all of them.
+*/
+package vfstrash
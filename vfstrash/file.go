@@ -0,0 +1,16 @@
+package vfstrash
+
+import (
+	"github.com/c2fo/vfs/v5/vfswrap"
+)
+
+// File is a vfs.File of a FileSystem, moving the wrapped file to the trash when it's deleted.
+type File struct {
+	*vfswrap.File
+	fileSystem *FileSystem
+}
+
+// Delete implements vfs.File, moving the file to the trash, or deleting it if it's already there.
+func (f *File) Delete() error {
+	return f.fileSystem.delete(f.Wrapped())
+}
@@ -0,0 +1,167 @@
+package vfstrash
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/vfswrap"
+)
+
+const (
+	// timeFormat is the time layout of the time a trashed file was deleted, which begins its name.
+	timeFormat = "20060102T150405.000000000Z"
+	// separator separates the time a trashed file was deleted from its original volume and path.
+	separator = "~"
+)
+
+// Item is a file in the trash.
+type Item struct {
+	// File is the trashed file, at the trash location.
+	File vfs.File
+	// Volume and Path are where the file was deleted from.
+	Volume string
+	Path   string
+	// Deleted is when the file was deleted.
+	Deleted time.Time
+}
+
+// FileSystem is a vfs.FileSystem which moves files deleted from a wrapped file system to a trash location.
+type FileSystem struct {
+	*vfswrap.FileSystem
+	trash vfs.Location
+}
+
+// NewFileSystem returns a FileSystem which moves files deleted from fs to trash.
+func NewFileSystem(fs vfs.FileSystem, trash vfs.Location) *FileSystem {
+	wrapper := &FileSystem{trash: trash}
+	wrapper.FileSystem = vfswrap.NewFileSystem(fs, wrapper)
+	return wrapper
+}
+
+// Trash returns the location deleted files are moved to.
+func (fs *FileSystem) Trash() vfs.Location {
+	return fs.trash
+}
+
+// Name implements vfs.FileSystem.
+func (fs *FileSystem) Name() string {
+	return "Trash " + fs.Wrapped().Name()
+}
+
+// WrapFile implements vfswrap.Wrapper.
+func (fs *FileSystem) WrapFile(file vfs.File) vfs.File {
+	return &File{File: vfswrap.NewFile(file, fs), fileSystem: fs}
+}
+
+// WrapLocation implements vfswrap.Wrapper.
+func (fs *FileSystem) WrapLocation(location vfs.Location) vfs.Location {
+	return &Location{Location: vfswrap.NewLocation(location, fs), fileSystem: fs}
+}
+
+// Trashed returns the files in the trash, oldest first.  Files in the trash location that weren't put there by a
+// FileSystem are ignored.
+func (fs *FileSystem) Trashed() ([]Item, error) {
+	names, err := fs.trash.List()
+	if err != nil {
+		return nil, err
+	}
+	items := []Item{}
+	for _, name := range names {
+		item, ok := parseName(name)
+		if !ok {
+			continue
+		}
+		if item.File, err = fs.trash.NewFile(name); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Deleted.Before(items[j].Deleted) })
+	return items, nil
+}
+
+// Restore moves a file in the trash back to where it was deleted from, returning it.  vfs.ErrFileExists is returned if
+// a file has since been written there.
+func (fs *FileSystem) Restore(item Item) (vfs.File, error) {
+	restored, err := fs.Wrapped().NewFile(item.Volume, item.Path)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := restored.Exists()
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, vfs.ErrFileExists
+	}
+	if err := item.File.MoveToFile(restored); err != nil {
+		return nil, fmt.Errorf("unable to restore %s: %s", item.Path, err.Error())
+	}
+	return fs.WrapFile(restored), nil
+}
+
+// Empty deletes the files trashed more than olderThan ago, or all of them if olderThan is 0, returning the number
+// deleted.
+func (fs *FileSystem) Empty(olderThan time.Duration) (int, error) {
+	items, err := fs.Trashed()
+	if err != nil {
+		return 0, err
+	}
+	cutoff := time.Now().Add(-olderThan)
+	deleted := 0
+	for _, item := range items {
+		if olderThan > 0 && !item.Deleted.Before(cutoff) {
+			continue
+		}
+		if err := item.File.Delete(); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// delete moves file, a file of the wrapped file system, to the trash, or deletes it if it's already there.
+func (fs *FileSystem) delete(file vfs.File) error {
+	if strings.HasPrefix(file.Location().URI(), fs.trash.URI()) {
+		return file.Delete()
+	}
+	trashed, err := fs.trash.NewFile(trashName(time.Now(), file.Location().Volume(), file.Path()))
+	if err != nil {
+		return err
+	}
+	return file.MoveToFile(trashed)
+}
+
+// trashName returns the name of a file deleted at deleted from volume and path, in the trash.  The volume can't contain
+// the separator once escaped; the path may, since it's last.
+func trashName(deleted time.Time, volume, path string) string {
+	escapedVolume := strings.Replace(url.PathEscape(volume), separator, "%7E", -1)
+	return deleted.UTC().Format(timeFormat) + separator + escapedVolume + separator + url.PathEscape(path)
+}
+
+// parseName returns the Item for the name of a file in the trash, without its File, or false if it isn't a name
+// returned by trashName.
+func parseName(name string) (Item, bool) {
+	parts := strings.SplitN(name, separator, 3)
+	if len(parts) != 3 {
+		return Item{}, false
+	}
+	deleted, err := time.Parse(timeFormat, parts[0])
+	if err != nil {
+		return Item{}, false
+	}
+	volume, err := url.PathUnescape(parts[1])
+	if err != nil {
+		return Item{}, false
+	}
+	path, err := url.PathUnescape(parts[2])
+	if err != nil {
+		return Item{}, false
+	}
+	return Item{Volume: volume, Path: path, Deleted: deleted}, true
+}
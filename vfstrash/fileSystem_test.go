@@ -0,0 +1,193 @@
+package vfstrash_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/backend/mem"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/vfstest"
+	"github.com/c2fo/vfs/v5/vfstrash"
+)
+
+type fileSystemTestSuite struct {
+	suite.Suite
+	dir string
+	fs  *vfstrash.FileSystem
+}
+
+func (ts *fileSystemTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfstrash_test")
+	ts.NoError(err)
+	ts.dir = dir
+	trash, err := _os.NewFileSystem().NewLocation("", dir+"/.trash/")
+	ts.NoError(err)
+	ts.fs = vfstrash.NewFileSystem(_os.NewFileSystem(), trash)
+}
+
+func (ts *fileSystemTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *fileSystemTestSuite) writeFile(name, contents string) vfs.File {
+	file, err := ts.fs.NewFile("", ts.dir+"/"+name)
+	ts.NoError(err)
+	_, err = file.Write([]byte(contents))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+	return file
+}
+
+func (ts *fileSystemTestSuite) exists(name string) bool {
+	_, err := os.Stat(ts.dir + "/" + name)
+	return err == nil
+}
+
+func (ts *fileSystemTestSuite) TestDelete() {
+	ts.Equal("Trash os", ts.fs.Name())
+	ts.Equal(_os.Scheme, ts.fs.Scheme())
+
+	file := ts.writeFile("data/file.txt", "hello")
+	ts.NoError(file.Delete())
+	ts.False(ts.exists("data/file.txt"))
+
+	location, err := ts.fs.NewLocation("", ts.dir+"/data/")
+	ts.NoError(err)
+	ts.writeFile("data/other.txt", "world")
+	ts.NoError(location.DeleteFile("other.txt"))
+	ts.False(ts.exists("data/other.txt"))
+
+	items, err := ts.fs.Trashed()
+	ts.NoError(err)
+	ts.Len(items, 2)
+	ts.Equal(ts.dir+"/data/file.txt", items[0].Path)
+	ts.Equal("", items[0].Volume)
+	ts.False(items[0].Deleted.IsZero())
+	ts.False(items[1].Deleted.Before(items[0].Deleted), "oldest first")
+	ts.Equal(ts.dir+"/data/other.txt", items[1].Path)
+
+	data, err := ioutil.ReadAll(items[0].File)
+	ts.NoError(err)
+	ts.NoError(items[0].File.Close())
+	ts.Equal("hello", string(data))
+}
+
+func (ts *fileSystemTestSuite) TestDeleteSamePathTwice() {
+	ts.NoError(ts.writeFile("file.txt", "first").Delete())
+	ts.NoError(ts.writeFile("file.txt", "second").Delete())
+
+	items, err := ts.fs.Trashed()
+	ts.NoError(err)
+	ts.Len(items, 2, "each delete is kept")
+}
+
+func (ts *fileSystemTestSuite) TestDeleteFromTrash() {
+	ts.NoError(ts.writeFile("file.txt", "hello").Delete())
+	items, err := ts.fs.Trashed()
+	ts.NoError(err)
+	ts.Len(items, 1)
+
+	trashed, err := ts.fs.NewFile("", items[0].File.Path())
+	ts.NoError(err)
+	ts.NoError(trashed.Delete(), "files in the trash are deleted")
+	items, err = ts.fs.Trashed()
+	ts.NoError(err)
+	ts.Empty(items)
+}
+
+func (ts *fileSystemTestSuite) TestRestore() {
+	ts.NoError(ts.writeFile("data/file.txt", "hello").Delete())
+	items, err := ts.fs.Trashed()
+	ts.NoError(err)
+	ts.Len(items, 1)
+
+	restored, err := ts.fs.Restore(items[0])
+	ts.NoError(err)
+	ts.IsType(&vfstrash.File{}, restored)
+	ts.Equal(ts.dir+"/data/file.txt", restored.Path())
+	data, err := ioutil.ReadFile(ts.dir + "/data/file.txt")
+	ts.NoError(err)
+	ts.Equal("hello", string(data))
+
+	items, err = ts.fs.Trashed()
+	ts.NoError(err)
+	ts.Empty(items)
+}
+
+func (ts *fileSystemTestSuite) TestRestoreExisting() {
+	ts.NoError(ts.writeFile("file.txt", "hello").Delete())
+	ts.writeFile("file.txt", "written since")
+	items, err := ts.fs.Trashed()
+	ts.NoError(err)
+
+	_, err = ts.fs.Restore(items[0])
+	ts.Equal(vfs.ErrFileExists, err)
+	data, err := ioutil.ReadFile(ts.dir + "/file.txt")
+	ts.NoError(err)
+	ts.Equal("written since", string(data))
+}
+
+func (ts *fileSystemTestSuite) TestEmpty() {
+	ts.NoError(ts.writeFile("a.txt", "a").Delete())
+	ts.NoError(ts.writeFile("b.txt", "b").Delete())
+
+	deleted, err := ts.fs.Empty(time.Hour)
+	ts.NoError(err)
+	ts.Equal(0, deleted, "nothing was trashed more than an hour ago")
+
+	deleted, err = ts.fs.Empty(0)
+	ts.NoError(err)
+	ts.Equal(2, deleted)
+	items, err := ts.fs.Trashed()
+	ts.NoError(err)
+	ts.Empty(items)
+}
+
+func (ts *fileSystemTestSuite) TestTrashOnAnotherBackend() {
+	trash, err := mem.NewFileSystem().NewLocation("trash", "/.trash/")
+	ts.NoError(err)
+	fs := vfstrash.NewFileSystem(_os.NewFileSystem(), trash)
+
+	file, err := fs.NewFile("", ts.dir+"/file.txt")
+	ts.NoError(err)
+	_, err = file.Write([]byte("hello"))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+	ts.NoError(file.Delete())
+	ts.False(ts.exists("file.txt"))
+
+	items, err := fs.Trashed()
+	ts.NoError(err)
+	ts.Len(items, 1)
+	ts.Equal("trash", items[0].File.Location().Volume())
+	_, err = fs.Restore(items[0])
+	ts.NoError(err)
+	ts.True(ts.exists("file.txt"))
+}
+
+func TestFileSystem(t *testing.T) {
+	suite.Run(t, new(fileSystemTestSuite))
+}
+
+func TestConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vfstrash_conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	trash, err := _os.NewFileSystem().NewLocation("", dir+"/.trash/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	location, err := vfstrash.NewFileSystem(_os.NewFileSystem(), trash).NewLocation("", dir+"/files/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vfstest.Run(t, location)
+}
@@ -0,0 +1,20 @@
+package vfstrash
+
+import (
+	"github.com/c2fo/vfs/v5/vfswrap"
+)
+
+// Location is a vfs.Location of a FileSystem, moving files deleted from the wrapped location to the trash.
+type Location struct {
+	*vfswrap.Location
+	fileSystem *FileSystem
+}
+
+// DeleteFile implements vfs.Location, moving the file to the trash, or deleting it if it's already there.
+func (l *Location) DeleteFile(relFilePath string) error {
+	file, err := l.Wrapped().NewFile(relFilePath)
+	if err != nil {
+		return err
+	}
+	return l.fileSystem.delete(file)
+}
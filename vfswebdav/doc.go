@@ -0,0 +1,29 @@
+/*
+Package vfswebdav serves a vfs.Location over WebDAV with golang.org/x/net/webdav, so any backend (ie: s3) can be
+mounted by desktop clients such as Finder, Windows Explorer or cadaver and used with drag-and-drop.
+
+Usage
+
+Use NewHandler to serve a location:
+
+  loc, err := vfssimple.NewLocation("s3://mybucket/shared/")
+  if err != nil {
+      return err
+  }
+  handler := vfswebdav.NewHandler(loc, vfswebdav.Options{
+      ReadOnly: true,
+      Authenticate: vfswebdav.BasicAuth(func(username, password string) bool {
+          return username == "partner" && password == os.Getenv("PARTNER_PASSWORD")
+      }),
+  })
+  log.Fatal(http.ListenAndServe(":8080", handler))
+
+Use NewFileSystem instead for a webdav.FileSystem to configure a webdav.Handler directly.
+
+Limitations
+
+Files are accessed through vfsafero.Fs, so its rules apply: directories exist only while they contain files, so a
+directory created with MKCOL doesn't show up until a file is put in it, and directories can be copied but not moved.
+Uploads are written to the backend once they've been received in full.
+*/
+package vfswebdav
@@ -0,0 +1,92 @@
+package vfswebdav
+
+import (
+	"context"
+	"os"
+
+	"golang.org/x/net/webdav"
+
+	"github.com/c2fo/vfs/v5"
+	"github.com/c2fo/vfs/v5/vfsafero"
+)
+
+// writeFlags are the os.OpenFile flags that open a file for modification.
+const writeFlags = os.O_WRONLY | os.O_RDWR | os.O_APPEND | os.O_CREATE | os.O_TRUNC
+
+// FileSystem implements webdav.FileSystem on top of a vfs.Location.
+type FileSystem struct {
+	fs       *vfsafero.Fs
+	readOnly bool
+}
+
+// NewFileSystem returns a webdav.FileSystem of the files beneath location.  If readOnly is true, every operation that
+// would modify the location fails with os.ErrPermission.
+func NewFileSystem(location vfs.Location, readOnly bool) *FileSystem {
+	return &FileSystem{fs: vfsafero.NewFs(location), readOnly: readOnly}
+}
+
+// Mkdir does nothing, since vfs locations exist implicitly while they contain files.
+func (fs *FileSystem) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	if fs.readOnly {
+		return &os.PathError{Op: "mkdir", Path: name, Err: os.ErrPermission}
+	}
+	return fs.fs.Mkdir(name, perm)
+}
+
+// OpenFile opens the named file or directory, as vfsafero.Fs.OpenFile does.
+func (fs *FileSystem) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	if fs.readOnly && flag&writeFlags != 0 {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrPermission}
+	}
+	return fs.fs.OpenFile(name, flag, perm)
+}
+
+// RemoveAll removes the named file, or directory and everything beneath it.
+func (fs *FileSystem) RemoveAll(ctx context.Context, name string) error {
+	if fs.readOnly {
+		return &os.PathError{Op: "removeall", Path: name, Err: os.ErrPermission}
+	}
+	return fs.fs.RemoveAll(name)
+}
+
+// Rename moves oldName to newName.  Only files can be renamed.
+func (fs *FileSystem) Rename(ctx context.Context, oldName, newName string) error {
+	if fs.readOnly {
+		return &os.LinkError{Op: "rename", Old: oldName, New: newName, Err: os.ErrPermission}
+	}
+	return fs.fs.Rename(oldName, newName)
+}
+
+// Stat returns a FileInfo describing the named file or directory.  Where the backend reports them (s3), the file's
+// ETag and content type are passed on to WebDAV clients.
+func (fs *FileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	info, err := fs.fs.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if vfsInfo, ok := info.(*vfs.FileInfo); ok {
+		return fileInfo{vfsInfo}, nil
+	}
+	return info, nil
+}
+
+// fileInfo implements webdav.ETager and webdav.ContentTyper for a vfs.FileInfo.
+type fileInfo struct {
+	*vfs.FileInfo
+}
+
+// ETag returns the file's quoted ETag, or webdav.ErrNotImplemented if the backend doesn't report one.
+func (i fileInfo) ETag(ctx context.Context) (string, error) {
+	if i.FileInfo.ETag == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return `"` + i.FileInfo.ETag + `"`, nil
+}
+
+// ContentType returns the file's content type, or webdav.ErrNotImplemented if the backend doesn't report one.
+func (i fileInfo) ContentType(ctx context.Context) (string, error) {
+	if i.FileInfo.ContentType == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return i.FileInfo.ContentType, nil
+}
@@ -0,0 +1,82 @@
+package vfswebdav
+
+import (
+	"net/http"
+
+	"golang.org/x/net/webdav"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// readMethods are the WebDAV methods that don't modify anything, allowed by read-only handlers.
+var readMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	"PROPFIND":         true,
+}
+
+// Authenticator checks a request's credentials, returning false to reject it.
+type Authenticator func(r *http.Request) bool
+
+// BasicAuth returns an Authenticator that checks the request's HTTP basic auth credentials with check.  Requests
+// without basic auth credentials are rejected.
+func BasicAuth(check func(username, password string) bool) Authenticator {
+	return func(r *http.Request) bool {
+		username, password, ok := r.BasicAuth()
+		return ok && check(username, password)
+	}
+}
+
+// Options configure a handler created with NewHandler.
+type Options struct {
+	// Prefix is the URL path prefix to strip from request paths, ie: "/dav" to serve the location at /dav/.
+	Prefix string
+	// ReadOnly rejects requests that would modify the location with 403 Forbidden.
+	ReadOnly bool
+	// Authenticate, if set, is called for every request.  Requests it rejects get 401 Unauthorized, asking for basic
+	// auth credentials.
+	Authenticate Authenticator
+	// Realm is the basic auth realm sent with 401 responses.  It defaults to "vfs".
+	Realm string
+	// Logger, if set, is called for every request with the error handling it, if any.
+	Logger func(r *http.Request, err error)
+}
+
+// handler wraps a webdav.Handler with authentication and the read-only check.
+type handler struct {
+	webdav  *webdav.Handler
+	options Options
+}
+
+// NewHandler returns an http.Handler serving location over WebDAV.  Locks are held in memory, so aren't shared between
+// handlers or server instances.
+func NewHandler(location vfs.Location, options Options) http.Handler {
+	return &handler{
+		webdav: &webdav.Handler{
+			Prefix:     options.Prefix,
+			FileSystem: NewFileSystem(location, options.ReadOnly),
+			LockSystem: webdav.NewMemLS(),
+			Logger:     options.Logger,
+		},
+		options: options,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.options.Authenticate != nil && !h.options.Authenticate(r) {
+		realm := h.options.Realm
+		if realm == "" {
+			realm = "vfs"
+		}
+		w.Header().Set("WWW-Authenticate", `Basic realm="`+realm+`"`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	if h.options.ReadOnly && !readMethods[r.Method] {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	h.webdav.ServeHTTP(w, r)
+}
@@ -0,0 +1,163 @@
+package vfswebdav
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/webdav"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+)
+
+type handlerTestSuite struct {
+	suite.Suite
+	dir      string
+	location vfs.Location
+}
+
+func (ts *handlerTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfswebdav_test")
+	ts.NoError(err)
+	ts.dir = dir
+	ts.location, err = _os.NewFileSystem().NewLocation("", dir+"/")
+	ts.NoError(err)
+}
+
+func (ts *handlerTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *handlerTestSuite) do(server *httptest.Server, method, path, body string, header http.Header) (*http.Response, string) {
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	ts.NoError(err)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := server.Client().Do(req)
+	ts.NoError(err)
+	data, err := ioutil.ReadAll(resp.Body)
+	ts.NoError(err)
+	ts.NoError(resp.Body.Close())
+	return resp, string(data)
+}
+
+func (ts *handlerTestSuite) TestReadWrite() {
+	server := httptest.NewServer(NewHandler(ts.location, Options{Prefix: "/dav"}))
+	defer server.Close()
+
+	resp, _ := ts.do(server, http.MethodPut, "/dav/docs/readme.txt", "hello webdav", nil)
+	ts.Equal(http.StatusCreated, resp.StatusCode)
+	ts.NotEmpty(resp.Header.Get("ETag"))
+	data, err := ioutil.ReadFile(filepath.Join(ts.dir, "docs", "readme.txt"))
+	ts.NoError(err)
+	ts.Equal("hello webdav", string(data))
+
+	resp, body := ts.do(server, http.MethodGet, "/dav/docs/readme.txt", "", nil)
+	ts.Equal(http.StatusOK, resp.StatusCode)
+	ts.Equal("hello webdav", body)
+
+	resp, body = ts.do(server, "PROPFIND", "/dav/docs/", "", http.Header{"Depth": {"1"}})
+	ts.Equal(http.StatusMultiStatus, resp.StatusCode)
+	ts.Contains(body, "/dav/docs/readme.txt")
+	ts.Contains(body, "<D:getcontentlength>12</D:getcontentlength>")
+
+	resp, _ = ts.do(server, "MOVE", "/dav/docs/readme.txt", "",
+		http.Header{"Destination": {server.URL + "/dav/archive/readme.txt"}})
+	ts.Equal(http.StatusCreated, resp.StatusCode)
+	resp, _ = ts.do(server, http.MethodGet, "/dav/docs/readme.txt", "", nil)
+	ts.Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = ts.do(server, http.MethodDelete, "/dav/archive/readme.txt", "", nil)
+	ts.Equal(http.StatusNoContent, resp.StatusCode)
+	_, err = os.Stat(filepath.Join(ts.dir, "archive", "readme.txt"))
+	ts.True(os.IsNotExist(err))
+}
+
+func (ts *handlerTestSuite) TestReadOnly() {
+	ts.NoError(ioutil.WriteFile(filepath.Join(ts.dir, "file.txt"), []byte("data"), 0644))
+	server := httptest.NewServer(NewHandler(ts.location, Options{ReadOnly: true}))
+	defer server.Close()
+
+	resp, body := ts.do(server, http.MethodGet, "/file.txt", "", nil)
+	ts.Equal(http.StatusOK, resp.StatusCode)
+	ts.Equal("data", body)
+	resp, _ = ts.do(server, "PROPFIND", "/", "", http.Header{"Depth": {"1"}})
+	ts.Equal(http.StatusMultiStatus, resp.StatusCode)
+
+	for _, method := range []string{http.MethodPut, http.MethodDelete, "MKCOL", "MOVE", "COPY", "PROPPATCH", "LOCK"} {
+		resp, _ = ts.do(server, method, "/file.txt", "", nil)
+		ts.Equal(http.StatusForbidden, resp.StatusCode, method)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(ts.dir, "file.txt"))
+	ts.NoError(err)
+	ts.Equal("data", string(data))
+
+	// the file system refuses writes itself, for use with other handlers
+	fs := NewFileSystem(ts.location, true)
+	_, err = fs.OpenFile(context.Background(), "/file.txt", os.O_RDWR|os.O_TRUNC, 0644)
+	ts.True(os.IsPermission(err))
+	ts.True(os.IsPermission(fs.RemoveAll(context.Background(), "/file.txt")))
+}
+
+func (ts *handlerTestSuite) TestAuthenticate() {
+	var logged []string
+	server := httptest.NewServer(NewHandler(ts.location, Options{
+		Authenticate: BasicAuth(func(username, password string) bool {
+			return username == "partner" && password == "secret"
+		}),
+		Realm: "files",
+		Logger: func(r *http.Request, err error) {
+			logged = append(logged, r.Method)
+		},
+	}))
+	defer server.Close()
+
+	resp, _ := ts.do(server, "PROPFIND", "/", "", nil)
+	ts.Equal(http.StatusUnauthorized, resp.StatusCode)
+	ts.Equal(`Basic realm="files"`, resp.Header.Get("WWW-Authenticate"))
+
+	req, err := http.NewRequest("PROPFIND", server.URL+"/", nil)
+	ts.NoError(err)
+	req.SetBasicAuth("partner", "wrong")
+	resp, err = server.Client().Do(req)
+	ts.NoError(err)
+	ts.NoError(resp.Body.Close())
+	ts.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	req.SetBasicAuth("partner", "secret")
+	resp, err = server.Client().Do(req)
+	ts.NoError(err)
+	ts.NoError(resp.Body.Close())
+	ts.Equal(http.StatusMultiStatus, resp.StatusCode)
+	ts.Equal([]string{"PROPFIND"}, logged, "only requests reaching the webdav handler are logged")
+}
+
+func (ts *handlerTestSuite) TestFileInfo() {
+	info := vfs.NewFileInfo("file.txt", 4, time.Now(), 0644)
+	_, err := fileInfo{info}.ETag(context.Background())
+	ts.Equal(webdav.ErrNotImplemented, err)
+	_, err = fileInfo{info}.ContentType(context.Background())
+	ts.Equal(webdav.ErrNotImplemented, err)
+
+	info.ETag = "abc123"
+	info.ContentType = "text/plain"
+	etag, err := fileInfo{info}.ETag(context.Background())
+	ts.NoError(err)
+	ts.Equal(`"abc123"`, etag)
+	contentType, err := fileInfo{info}.ContentType(context.Background())
+	ts.NoError(err)
+	ts.Equal("text/plain", contentType)
+}
+
+func TestHandler(t *testing.T) {
+	suite.Run(t, new(handlerTestSuite))
+}
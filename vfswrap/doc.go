@@ -0,0 +1,41 @@
+/*
+Package vfswrap provides the scaffolding for file systems which wrap another vfs.FileSystem to change some of its
+behavior, ie: vfstrash, vfsquota and vfschaos, so each only implements the calls it changes.
+
+Usage
+
+Embed FileSystem, File and Location in the wrapping types, implement Wrapper to wrap the files and locations of the
+wrapped file system, and override the calls to change:
+
+  type FileSystem struct {
+      *vfswrap.FileSystem
+  }
+
+  func NewFileSystem(fs vfs.FileSystem) *FileSystem {
+      wrapper := &FileSystem{}
+      wrapper.FileSystem = vfswrap.NewFileSystem(fs, wrapper)
+      return wrapper
+  }
+
+  func (fs *FileSystem) WrapFile(file vfs.File) vfs.File {
+      return &File{File: vfswrap.NewFile(file, fs)}
+  }
+
+  func (fs *FileSystem) WrapLocation(location vfs.Location) vfs.Location {
+      return vfswrap.NewLocation(location, fs)
+  }
+
+  type File struct {
+      *vfswrap.File
+  }
+
+  func (f *File) Delete() error {
+      log.Printf("deleting %s", f.URI())
+      return f.File.Delete()
+  }
+
+Files and locations returned by calls, ie: File.Location or Location.NewFile, are wrapped by the Wrapper, and those
+passed to calls, ie: the target of File.CopyToFile, are unwrapped before they reach the wrapped file system if they're
+the Wrapper's own.
+*/
+package vfswrap
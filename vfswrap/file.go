@@ -0,0 +1,129 @@
+package vfswrap
+
+import (
+	"time"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// File is a vfs.File delegating to a wrapped file, for embedding in the files of a Wrapper.
+type File struct {
+	file    vfs.File
+	wrapper Wrapper
+}
+
+// NewFile returns a File delegating to file, a file of the file system wrapped by wrapper.
+func NewFile(file vfs.File, wrapper Wrapper) *File {
+	return &File{file: file, wrapper: wrapper}
+}
+
+// Wrapped returns the wrapped file.
+func (f *File) Wrapped() vfs.File {
+	return f.file
+}
+
+// Close implements io.Closer.
+func (f *File) Close() error {
+	return f.file.Close()
+}
+
+// Read implements io.Reader.
+func (f *File) Read(p []byte) (int, error) {
+	return f.file.Read(p)
+}
+
+// Seek implements io.Seeker.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	return f.file.Seek(offset, whence)
+}
+
+// Write implements io.Writer.
+func (f *File) Write(p []byte) (int, error) {
+	return f.file.Write(p)
+}
+
+// String implements fmt.Stringer.
+func (f *File) String() string {
+	return f.file.String()
+}
+
+// Exists implements vfs.File.
+func (f *File) Exists() (bool, error) {
+	return f.file.Exists()
+}
+
+// Location implements vfs.File.
+func (f *File) Location() vfs.Location {
+	return wrapLocation(f.wrapper, f.file.Location())
+}
+
+// CopyToLocation implements vfs.File.
+func (f *File) CopyToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	file, err := f.file.CopyToLocation(unwrapLocation(f.wrapper, location), opts...)
+	return wrapFile(f.wrapper, file), err
+}
+
+// CopyToFile implements vfs.File.
+func (f *File) CopyToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	return f.file.CopyToFile(unwrapFile(f.wrapper, file), opts...)
+}
+
+// MoveToLocation implements vfs.File.
+func (f *File) MoveToLocation(location vfs.Location, opts ...vfs.CopyOption) (vfs.File, error) {
+	file, err := f.file.MoveToLocation(unwrapLocation(f.wrapper, location), opts...)
+	return wrapFile(f.wrapper, file), err
+}
+
+// MoveToFile implements vfs.File.
+func (f *File) MoveToFile(file vfs.File, opts ...vfs.CopyOption) error {
+	return f.file.MoveToFile(unwrapFile(f.wrapper, file), opts...)
+}
+
+// Delete implements vfs.File.
+func (f *File) Delete() error {
+	return f.file.Delete()
+}
+
+// LastModified implements vfs.File.
+func (f *File) LastModified() (*time.Time, error) {
+	return f.file.LastModified()
+}
+
+// Size implements vfs.File.
+func (f *File) Size() (uint64, error) {
+	return f.file.Size()
+}
+
+// Path implements vfs.File.
+func (f *File) Path() string {
+	return f.file.Path()
+}
+
+// Name implements vfs.File.
+func (f *File) Name() string {
+	return f.file.Name()
+}
+
+// Touch implements vfs.File.
+func (f *File) Touch() error {
+	return f.file.Touch()
+}
+
+// URI implements vfs.File.
+func (f *File) URI() string {
+	return f.file.URI()
+}
+
+// base returns the File, for the files embedding it.
+func (f *File) base() *File {
+	return f
+}
+
+// unwrapFile returns the wrapped file of a file of wrapper, so it's passed to the wrapped file system as one of its own,
+// or file itself if it isn't one.
+func unwrapFile(wrapper Wrapper, file vfs.File) vfs.File {
+	if f, ok := file.(interface{ base() *File }); ok && f.base().wrapper == wrapper {
+		return f.base().file
+	}
+	return file
+}
@@ -0,0 +1,70 @@
+package vfswrap
+
+import (
+	"github.com/c2fo/vfs/v5"
+)
+
+// Wrapper is the vfs.FileSystem wrapping another, which wraps the files and locations of the wrapped file system as its
+// own.  WrapFile and WrapLocation aren't called with nil.
+type Wrapper interface {
+	vfs.FileSystem
+	WrapFile(file vfs.File) vfs.File
+	WrapLocation(location vfs.Location) vfs.Location
+}
+
+// FileSystem is a vfs.FileSystem delegating to a wrapped file system, for embedding in a Wrapper.
+type FileSystem struct {
+	fs      vfs.FileSystem
+	wrapper Wrapper
+}
+
+// NewFileSystem returns a FileSystem delegating to fs, whose files and locations are wrapped by wrapper.
+func NewFileSystem(fs vfs.FileSystem, wrapper Wrapper) *FileSystem {
+	return &FileSystem{fs: fs, wrapper: wrapper}
+}
+
+// Wrapped returns the wrapped file system.
+func (fs *FileSystem) Wrapped() vfs.FileSystem {
+	return fs.fs
+}
+
+// NewFile implements vfs.FileSystem.
+func (fs *FileSystem) NewFile(volume string, absFilePath string) (vfs.File, error) {
+	file, err := fs.fs.NewFile(volume, absFilePath)
+	return wrapFile(fs.wrapper, file), err
+}
+
+// NewLocation implements vfs.FileSystem.
+func (fs *FileSystem) NewLocation(volume string, absLocPath string) (vfs.Location, error) {
+	location, err := fs.fs.NewLocation(volume, absLocPath)
+	return wrapLocation(fs.wrapper, location), err
+}
+
+// Name implements vfs.FileSystem, returning the wrapped file system's name.
+func (fs *FileSystem) Name() string {
+	return fs.fs.Name()
+}
+
+// Scheme implements vfs.FileSystem, returning the wrapped file system's scheme.
+func (fs *FileSystem) Scheme() string {
+	return fs.fs.Scheme()
+}
+
+// Retry implements vfs.FileSystem, returning the wrapped file system's retryer.
+func (fs *FileSystem) Retry() vfs.Retry {
+	return fs.fs.Retry()
+}
+
+func wrapFile(wrapper Wrapper, file vfs.File) vfs.File {
+	if file == nil {
+		return nil
+	}
+	return wrapper.WrapFile(file)
+}
+
+func wrapLocation(wrapper Wrapper, location vfs.Location) vfs.Location {
+	if location == nil {
+		return nil
+	}
+	return wrapper.WrapLocation(location)
+}
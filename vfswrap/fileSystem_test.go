@@ -0,0 +1,109 @@
+package vfswrap_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/c2fo/vfs/v5"
+	_os "github.com/c2fo/vfs/v5/backend/os"
+	"github.com/c2fo/vfs/v5/vfstest"
+	"github.com/c2fo/vfs/v5/vfswrap"
+)
+
+// deleteCounter is a Wrapper counting the files deleted through it.
+type deleteCounter struct {
+	*vfswrap.FileSystem
+	deleted int
+}
+
+func newDeleteCounter(fs vfs.FileSystem) *deleteCounter {
+	wrapper := &deleteCounter{}
+	wrapper.FileSystem = vfswrap.NewFileSystem(fs, wrapper)
+	return wrapper
+}
+
+func (fs *deleteCounter) WrapFile(file vfs.File) vfs.File {
+	return &countedFile{File: vfswrap.NewFile(file, fs), fileSystem: fs}
+}
+
+func (fs *deleteCounter) WrapLocation(location vfs.Location) vfs.Location {
+	return vfswrap.NewLocation(location, fs)
+}
+
+type countedFile struct {
+	*vfswrap.File
+	fileSystem *deleteCounter
+}
+
+func (f *countedFile) Delete() error {
+	f.fileSystem.deleted++
+	return f.File.Delete()
+}
+
+type fileSystemTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (ts *fileSystemTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "vfswrap_test")
+	ts.NoError(err)
+	ts.dir = dir
+}
+
+func (ts *fileSystemTestSuite) TearDownTest() {
+	ts.NoError(os.RemoveAll(ts.dir))
+}
+
+func (ts *fileSystemTestSuite) writeFile(fs vfs.FileSystem, name, contents string) vfs.File {
+	file, err := fs.NewFile("", ts.dir+"/"+name)
+	ts.NoError(err)
+	_, err = file.Write([]byte(contents))
+	ts.NoError(err)
+	ts.NoError(file.Close())
+	return file
+}
+
+func (ts *fileSystemTestSuite) TestWrap() {
+	fs := newDeleteCounter(_os.NewFileSystem())
+	ts.Equal("os", fs.Name())
+	ts.Equal(_os.Scheme, fs.Scheme())
+
+	file := ts.writeFile(fs, "file.txt", "hello")
+	ts.IsType(&countedFile{}, file)
+	ts.Equal(fs, file.Location().FileSystem(), "locations belong to the wrapper")
+
+	location, err := fs.NewLocation("", ts.dir+"/copies/")
+	ts.NoError(err)
+	copied, err := file.CopyToLocation(location)
+	ts.NoError(err)
+	ts.IsType(&countedFile{}, copied, "returned files are wrapped")
+
+	newFile, err := location.NewFile("new.txt")
+	ts.NoError(err)
+	ts.NoError(file.MoveToFile(newFile), "wrapped targets are unwrapped")
+	ts.NoError(copied.Delete())
+	ts.NoError(location.DeleteFile("new.txt"))
+	ts.Equal(1, fs.deleted, "only calls the wrapper overrides are changed")
+}
+
+func TestFileSystem(t *testing.T) {
+	suite.Run(t, new(fileSystemTestSuite))
+}
+
+func TestConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vfswrap_conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	location, err := newDeleteCounter(_os.NewFileSystem()).NewLocation("", dir+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vfstest.Run(t, location)
+}
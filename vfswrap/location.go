@@ -0,0 +1,104 @@
+package vfswrap
+
+import (
+	"regexp"
+
+	"github.com/c2fo/vfs/v5"
+)
+
+// Location is a vfs.Location delegating to a wrapped location, for embedding in the locations of a Wrapper.
+type Location struct {
+	location vfs.Location
+	wrapper  Wrapper
+}
+
+// NewLocation returns a Location delegating to location, a location of the file system wrapped by wrapper.
+func NewLocation(location vfs.Location, wrapper Wrapper) *Location {
+	return &Location{location: location, wrapper: wrapper}
+}
+
+// Wrapped returns the wrapped location.
+func (l *Location) Wrapped() vfs.Location {
+	return l.location
+}
+
+// String implements fmt.Stringer.
+func (l *Location) String() string {
+	return l.location.String()
+}
+
+// List implements vfs.Location.
+func (l *Location) List() ([]string, error) {
+	return l.location.List()
+}
+
+// ListByPrefix implements vfs.Location.
+func (l *Location) ListByPrefix(prefix string) ([]string, error) {
+	return l.location.ListByPrefix(prefix)
+}
+
+// ListByRegex implements vfs.Location.
+func (l *Location) ListByRegex(regex *regexp.Regexp) ([]string, error) {
+	return l.location.ListByRegex(regex)
+}
+
+// Volume implements vfs.Location.
+func (l *Location) Volume() string {
+	return l.location.Volume()
+}
+
+// Path implements vfs.Location.
+func (l *Location) Path() string {
+	return l.location.Path()
+}
+
+// Exists implements vfs.Location.
+func (l *Location) Exists() (bool, error) {
+	return l.location.Exists()
+}
+
+// NewLocation implements vfs.Location.
+func (l *Location) NewLocation(relLocPath string) (vfs.Location, error) {
+	location, err := l.location.NewLocation(relLocPath)
+	return wrapLocation(l.wrapper, location), err
+}
+
+// ChangeDir implements vfs.Location.
+func (l *Location) ChangeDir(relLocPath string) error {
+	return l.location.ChangeDir(relLocPath)
+}
+
+// FileSystem implements vfs.Location, returning the Wrapper.
+func (l *Location) FileSystem() vfs.FileSystem {
+	return l.wrapper
+}
+
+// NewFile implements vfs.Location.
+func (l *Location) NewFile(relFilePath string) (vfs.File, error) {
+	file, err := l.location.NewFile(relFilePath)
+	return wrapFile(l.wrapper, file), err
+}
+
+// DeleteFile implements vfs.Location.
+func (l *Location) DeleteFile(relFilePath string) error {
+	return l.location.DeleteFile(relFilePath)
+}
+
+// URI implements vfs.Location.
+func (l *Location) URI() string {
+	return l.location.URI()
+}
+
+// base returns the Location, for the locations embedding it.
+func (l *Location) base() *Location {
+	return l
+}
+
+// unwrapLocation returns the wrapped location of a location of wrapper, so it's passed to the wrapped file system as
+// one of its own, or location itself if it isn't one.
+func unwrapLocation(wrapper Wrapper, location vfs.Location) vfs.Location {
+	if l, ok := location.(interface{ base() *Location }); ok && l.base().wrapper == wrapper {
+		return l.base().location
+	}
+	return location
+}